@@ -1,6 +1,7 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -8,7 +9,11 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/accelbench/accelbench/internal/analyzer"
 	"github.com/accelbench/accelbench/internal/database"
 )
 
@@ -18,16 +23,37 @@ type Client struct {
 	httpClient *http.Client
 }
 
-// New creates a Client targeting the given base URL (e.g. "http://localhost:8080").
-func New(baseURL string) *Client {
+// New creates a Client targeting the given base URL (e.g.
+// "http://localhost:8080"). readTimeout bounds the underlying HTTP client's
+// round trip, including reading the response body, so a controller that
+// accepts a connection but then stops responding can't hang the CLI
+// forever; zero disables it.
+func New(baseURL string, readTimeout time.Duration) *Client {
 	return &Client{
 		baseURL:    baseURL,
-		httpClient: http.DefaultClient,
+		httpClient: &http.Client{Timeout: readTimeout},
 	}
 }
 
+// Page wraps a single page of a limit/offset-paginated list endpoint's
+// response: the items themselves, the total row count across every page
+// (from the server's X-Total-Count header), and NextOffset, the offset to
+// request for the following page (from the server's Link: rel="next"
+// header), or -1 if the server didn't advertise one (the caller is on the
+// last page).
+type Page[T any] struct {
+	Items      []T
+	Total      int
+	NextOffset int
+}
+
+// HasMore reports whether a further page is available.
+func (p Page[T]) HasMore() bool {
+	return p.NextOffset >= 0
+}
+
 // ListCatalog queries GET /api/v1/catalog with optional filters.
-func (c *Client) ListCatalog(ctx context.Context, f database.CatalogFilter) ([]database.CatalogEntry, error) {
+func (c *Client) ListCatalog(ctx context.Context, f database.CatalogFilter) (Page[database.CatalogEntry], error) {
 	params := url.Values{}
 	if f.ModelHfID != "" {
 		params.Set("model", f.ModelHfID)
@@ -53,6 +79,10 @@ func (c *Client) ListCatalog(ctx context.Context, f database.CatalogFilter) ([]d
 	if f.Offset > 0 {
 		params.Set("offset", fmt.Sprintf("%d", f.Offset))
 	}
+	if f.AfterRunID != "" {
+		params.Set("after_run_id", f.AfterRunID)
+		params.Set("after_sort_value", FormatCursorValue(f.AfterSortValue))
+	}
 
 	u := c.baseURL + "/api/v1/catalog"
 	if len(params) > 0 {
@@ -60,10 +90,178 @@ func (c *Client) ListCatalog(ctx context.Context, f database.CatalogFilter) ([]d
 	}
 
 	var entries []database.CatalogEntry
-	if err := c.doGet(ctx, u, &entries); err != nil {
+	total, nextOffset, err := c.doGetPage(ctx, u, &entries)
+	if err != nil {
+		return Page[database.CatalogEntry]{}, err
+	}
+	return Page[database.CatalogEntry]{Items: entries, Total: total, NextOffset: nextOffset}, nil
+}
+
+// FormatCursorValue renders a database.CatalogFilter.AfterSortValue (as
+// produced by database.CatalogEntrySortValue) into the string the server's
+// catalogFilterFromQuery passes straight through to Postgres. time.Time
+// uses RFC3339Nano so sub-second completed_at ordering survives the round
+// trip; everything else uses its default string form. Exported so a
+// caller paginating ListCatalog itself (see the query command's
+// --cursor/--all flags) can build the next page's cursor the same way
+// ListCatalog's own request-building does.
+func FormatCursorValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case *time.Time:
+		if t == nil {
+			return ""
+		}
+		return t.Format(time.RFC3339Nano)
+	case *float64:
+		if t == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*t, 'g', -1, 64)
+	case *string:
+		if t == nil {
+			return ""
+		}
+		return *t
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+// CatalogHealth queries GET /api/v1/catalog/health with optional filters,
+// or POST /api/v1/catalog/health/repair when repair is true.
+func (c *Client) CatalogHealth(ctx context.Context, f database.CatalogFilter, repair bool) ([]database.RunHealthStats, error) {
+	params := url.Values{}
+	if f.ModelHfID != "" {
+		params.Set("model", f.ModelHfID)
+	}
+	if f.ModelFamily != "" {
+		params.Set("model_family", f.ModelFamily)
+	}
+	if f.InstanceFamily != "" {
+		params.Set("instance_family", f.InstanceFamily)
+	}
+	if f.AcceleratorType != "" {
+		params.Set("accelerator_type", f.AcceleratorType)
+	}
+
+	if !repair {
+		u := c.baseURL + "/api/v1/catalog/health"
+		if len(params) > 0 {
+			u += "?" + params.Encode()
+		}
+		var stats []database.RunHealthStats
+		if err := c.doGet(ctx, u, &stats); err != nil {
+			return nil, err
+		}
+		return stats, nil
+	}
+
+	u := c.baseURL + "/api/v1/catalog/health/repair"
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, u, nil)
+	if err != nil {
 		return nil, err
 	}
-	return entries, nil
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.readError(resp)
+	}
+	var result struct {
+		RepairedRunIDs []string `json:"repaired_run_ids"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	stats := make([]database.RunHealthStats, len(result.RepairedRunIDs))
+	for i, id := range result.RepairedRunIDs {
+		stats[i] = database.RunHealthStats{RunID: id, Healthy: false}
+	}
+	return stats, nil
+}
+
+// promQueryResponse mirrors the status/data envelope the server's
+// /api/v1/query and /api/v1/query_range return, matching Prometheus's own
+// HTTP API response shape.
+type promQueryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric database.LabelSet `json:"metric"`
+			Value  [2]any            `json:"value"`
+			Values [][2]any          `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+func parsePromValue(pair [2]any) (float64, error) {
+	s, ok := pair[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected value encoding %v", pair[1])
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// QueryCatalog evaluates a PromQL-style expr against GET /api/v1/query as
+// of at (the zero value means "now").
+func (c *Client) QueryCatalog(ctx context.Context, expr string, at time.Time) (database.Vector, error) {
+	params := url.Values{"query": {expr}}
+	if !at.IsZero() {
+		params.Set("time", strconv.FormatInt(at.Unix(), 10))
+	}
+	var resp promQueryResponse
+	if err := c.doGet(ctx, c.baseURL+"/api/v1/query?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	vec := make(database.Vector, len(resp.Data.Result))
+	for i, r := range resp.Data.Result {
+		v, err := parsePromValue(r.Value)
+		if err != nil {
+			return nil, fmt.Errorf("decode result: %w", err)
+		}
+		vec[i] = database.VectorSample{Labels: r.Metric, Value: v}
+	}
+	return vec, nil
+}
+
+// QueryCatalogRange evaluates expr against GET /api/v1/query_range over
+// [start, end] at the given step.
+func (c *Client) QueryCatalogRange(ctx context.Context, expr string, start, end time.Time, step time.Duration) (database.Matrix, error) {
+	params := url.Values{
+		"query": {expr},
+		"start": {strconv.FormatInt(start.Unix(), 10)},
+		"end":   {strconv.FormatInt(end.Unix(), 10)},
+		"step":  {step.String()},
+	}
+	var resp promQueryResponse
+	if err := c.doGet(ctx, c.baseURL+"/api/v1/query_range?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	matrix := make(database.Matrix, len(resp.Data.Result))
+	for i, r := range resp.Data.Result {
+		points := make([]database.MatrixPoint, len(r.Values))
+		for j, pair := range r.Values {
+			v, err := parsePromValue(pair)
+			if err != nil {
+				return nil, fmt.Errorf("decode point: %w", err)
+			}
+			ts, ok := pair[0].(float64)
+			if !ok {
+				return nil, fmt.Errorf("unexpected timestamp encoding %v", pair[0])
+			}
+			points[j] = database.MatrixPoint{Timestamp: time.Unix(int64(ts), 0), Value: v}
+		}
+		matrix[i] = database.Series{Labels: r.Metric, Points: points}
+	}
+	return matrix, nil
 }
 
 // CreateRun submits POST /api/v1/runs and returns the run ID and status.
@@ -95,6 +293,101 @@ func (c *Client) CreateRun(ctx context.Context, req database.RunRequest) (string
 	return result.ID, result.Status, nil
 }
 
+// CreateExperiment submits POST /api/v1/experiments and returns the
+// experiment ID and its child run IDs.
+func (c *Client) CreateExperiment(ctx context.Context, spec database.ExperimentSpec) (string, []string, error) {
+	body, _ := json.Marshal(spec)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/experiments", bytes.NewReader(body))
+	if err != nil {
+		return "", nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return "", nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return "", nil, c.readError(resp)
+	}
+
+	var result struct {
+		ExperimentID string   `json:"experiment_id"`
+		RunIDs       []string `json:"run_ids"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", nil, fmt.Errorf("decode response: %w", err)
+	}
+	return result.ExperimentID, result.RunIDs, nil
+}
+
+// ListRuns queries GET /api/v1/runs with optional filters, for an
+// operator triaging the run queue (as opposed to ListCatalog, which only
+// ever returns completed catalog-type runs).
+func (c *Client) ListRuns(ctx context.Context, f database.RunFilter) (Page[database.RunListItem], error) {
+	params := url.Values{}
+	if f.Status != "" {
+		params.Set("status", f.Status)
+	}
+	if len(f.Statuses) > 0 {
+		params.Set("statuses", strings.Join(f.Statuses, ","))
+	}
+	if f.ModelID != "" {
+		params.Set("model", f.ModelID)
+	}
+	if f.PolicyID != "" {
+		params.Set("policy_id", f.PolicyID)
+	}
+	if f.Trigger != "" {
+		params.Set("trigger", f.Trigger)
+	}
+	if f.Framework != "" {
+		params.Set("framework", f.Framework)
+	}
+	if f.InstanceFamily != "" {
+		params.Set("instance_family", f.InstanceFamily)
+	}
+	if f.AcceleratorType != "" {
+		params.Set("accelerator_type", f.AcceleratorType)
+	}
+	if !f.CreatedAfter.IsZero() {
+		params.Set("created_after", f.CreatedAfter.Format(time.RFC3339Nano))
+	}
+	if !f.CreatedBefore.IsZero() {
+		params.Set("created_before", f.CreatedBefore.Format(time.RFC3339Nano))
+	}
+	if !f.CompletedAfter.IsZero() {
+		params.Set("completed_after", f.CompletedAfter.Format(time.RFC3339Nano))
+	}
+	if !f.CompletedBefore.IsZero() {
+		params.Set("completed_before", f.CompletedBefore.Format(time.RFC3339Nano))
+	}
+	if f.Limit > 0 {
+		params.Set("limit", fmt.Sprintf("%d", f.Limit))
+	}
+	if f.Offset > 0 {
+		params.Set("offset", fmt.Sprintf("%d", f.Offset))
+	}
+	if f.AfterID != "" {
+		params.Set("after_id", f.AfterID)
+		params.Set("after_created_at", f.AfterCreatedAt.Format(time.RFC3339Nano))
+	}
+
+	u := c.baseURL + "/api/v1/runs"
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+
+	var items []database.RunListItem
+	total, nextOffset, err := c.doGetPage(ctx, u, &items)
+	if err != nil {
+		return Page[database.RunListItem]{}, err
+	}
+	return Page[database.RunListItem]{Items: items, Total: total, NextOffset: nextOffset}, nil
+}
+
 // GetRun fetches GET /api/v1/runs/{id}.
 func (c *Client) GetRun(ctx context.Context, id string) (*database.BenchmarkRun, error) {
 	var run database.BenchmarkRun
@@ -113,6 +406,199 @@ func (c *Client) GetMetrics(ctx context.Context, id string) (*database.Benchmark
 	return &m, nil
 }
 
+// ExtendRunDeadline submits PATCH /api/v1/runs/{id}/deadline, pushing id's
+// DeadlineAt out by extendSeconds, and returns the new deadline. Use this
+// to prolong a long-running run mid-flight before the reaper fails it out;
+// the server rejects the request once the run is no longer queued,
+// pending, or running.
+func (c *Client) ExtendRunDeadline(ctx context.Context, id string, extendSeconds int) (time.Time, error) {
+	body, _ := json.Marshal(struct {
+		ExtendSeconds int `json:"extend_seconds"`
+	}{extendSeconds})
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPatch, c.baseURL+"/api/v1/runs/"+id+"/deadline", bytes.NewReader(body))
+	if err != nil {
+		return time.Time{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, c.readError(resp)
+	}
+
+	var result struct {
+		DeadlineAt time.Time `json:"deadline_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return time.Time{}, fmt.Errorf("decode response: %w", err)
+	}
+	return result.DeadlineAt, nil
+}
+
+// WaitForRun blocks until id reaches a terminal status ("completed" or
+// "failed"), then returns its final BenchmarkRun, instead of the caller
+// polling GetRun in a loop. It opens a Server-Sent Events stream against
+// GET /api/v1/runs/{id}/events (backed by internal/database's Broker) and
+// watches for a run-update event whose status is terminal; if the stream
+// closes first (e.g. the server already saw the run finish and closed the
+// connection per handleRunEvents) it falls back to one GetRun call to read
+// the current state directly.
+func (c *Client) WaitForRun(ctx context.Context, id string) (*database.BenchmarkRun, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/runs/"+id+"/events", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.readError(resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	var dataLine string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data: "):
+			dataLine = strings.TrimPrefix(line, "data: ")
+		case line == "":
+			if dataLine == "" {
+				continue
+			}
+			var ev struct {
+				Status string `json:"status"`
+			}
+			data := dataLine
+			dataLine = ""
+			if err := json.Unmarshal([]byte(data), &ev); err != nil {
+				continue
+			}
+			if ev.Status == "completed" || ev.Status == "failed" {
+				return c.GetRun(ctx, id)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read event stream: %w", err)
+	}
+
+	// The stream closed without ever reporting a terminal status (e.g. the
+	// run was already terminal when we connected); fetch its current state
+	// directly rather than assume anything about why it closed.
+	return c.GetRun(ctx, id)
+}
+
+// CompareRuns fetches GET /api/v1/runs/compare, an A/B statistical
+// comparison of controlID's and treatmentID's raw per-request samples.
+func (c *Client) CompareRuns(ctx context.Context, controlID, treatmentID string) (*analyzer.Report, error) {
+	params := url.Values{"control": {controlID}, "treatment": {treatmentID}}
+	var report analyzer.Report
+	if err := c.doGet(ctx, c.baseURL+"/api/v1/runs/compare?"+params.Encode(), &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// policyRequest is the wire shape for POST/PUT /api/v1/policies, mirroring
+// internal/api's policyRequest.
+type policyRequest struct {
+	Name     string                        `json:"name"`
+	Template database.BenchmarkRunTemplate `json:"template"`
+	Trigger  string                        `json:"trigger"`
+	CronExpr string                        `json:"cron_expr,omitempty"`
+	Enabled  bool                          `json:"enabled"`
+}
+
+// CreatePolicy submits POST /api/v1/policies and returns the new policy's
+// ID.
+func (c *Client) CreatePolicy(ctx context.Context, name string, template database.BenchmarkRunTemplate, trigger, cronExpr string, enabled bool) (string, error) {
+	req := policyRequest{Name: name, Template: template, Trigger: trigger, CronExpr: cronExpr, Enabled: enabled}
+	body, _ := json.Marshal(req)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/policies", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", c.readError(resp)
+	}
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	return result.ID, nil
+}
+
+// ListPolicies fetches GET /api/v1/policies.
+func (c *Client) ListPolicies(ctx context.Context) ([]database.BenchmarkPolicy, error) {
+	var policies []database.BenchmarkPolicy
+	if err := c.doGet(ctx, c.baseURL+"/api/v1/policies", &policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// UpdatePolicy submits PUT /api/v1/policies/{id}, replacing its mutable
+// fields.
+func (c *Client) UpdatePolicy(ctx context.Context, id, name string, template database.BenchmarkRunTemplate, trigger, cronExpr string, enabled bool) error {
+	req := policyRequest{Name: name, Template: template, Trigger: trigger, CronExpr: cronExpr, Enabled: enabled}
+	body, _ := json.Marshal(req)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/api/v1/policies/"+id, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.readError(resp)
+	}
+	return nil
+}
+
+// DeletePolicy submits DELETE /api/v1/policies/{id}.
+func (c *Client) DeletePolicy(ctx context.Context, id string) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/api/v1/policies/"+id, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return c.readError(resp)
+	}
+	return nil
+}
+
 func (c *Client) doGet(ctx context.Context, rawURL string, out any) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 	if err != nil {
@@ -130,13 +616,76 @@ func (c *Client) doGet(ctx context.Context, rawURL string, out any) error {
 	return json.NewDecoder(resp.Body).Decode(out)
 }
 
+// doGetPage is doGet for a paginated list endpoint: it additionally parses
+// the response's X-Total-Count and Link headers (see the server's
+// writePaginationHeaders) into a total row count and the offset of the
+// next page, returning nextOffset -1 when the response carries no
+// rel="next" link.
+func (c *Client) doGetPage(ctx context.Context, rawURL string, out any) (total int, nextOffset int, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, -1, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, -1, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, -1, c.readError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return 0, -1, err
+	}
+
+	total, _ = strconv.Atoi(resp.Header.Get("X-Total-Count"))
+	nextOffset = nextOffsetFromLink(resp.Header.Get("Link"))
+	return total, nextOffset, nil
+}
+
+// nextOffsetFromLink extracts the "offset" query parameter of the
+// rel="next" entry in an RFC 5988 Link header (as built by the server's
+// writePaginationHeaders), or -1 if there is no rel="next" entry or it
+// doesn't parse as a URL with a valid offset.
+func nextOffsetFromLink(header string) int {
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		urlPart, params, ok := strings.Cut(part, ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		rawURL := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(urlPart), "<"), ">")
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			continue
+		}
+		offset, err := strconv.Atoi(u.Query().Get("offset"))
+		if err != nil {
+			continue
+		}
+		return offset
+	}
+	return -1
+}
+
 func (c *Client) readError(resp *http.Response) error {
 	body, _ := io.ReadAll(resp.Body)
-	var apiErr struct {
+	var problem struct {
+		Code   string `json:"code"`
+		Detail string `json:"detail"`
+	}
+	if json.Unmarshal(body, &problem) == nil && problem.Code != "" {
+		return fmt.Errorf("API error %d [%s]: %s", resp.StatusCode, problem.Code, problem.Detail)
+	}
+	// Fall back to the legacy {"error": "..."} shape predating RFC7807
+	// problem details, so an older server (or a handler that hasn't been
+	// migrated yet) still reports a clean message instead of a raw JSON dump.
+	var legacy struct {
 		Error string `json:"error"`
 	}
-	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, apiErr.Error)
+	if json.Unmarshal(body, &legacy) == nil && legacy.Error != "" {
+		return fmt.Errorf("API error %d: %s", resp.StatusCode, legacy.Error)
 	}
 	return fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
 }
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -29,22 +30,54 @@ func TestListCatalog(t *testing.T) {
 			t.Errorf("expected model filter, got query: %s", r.URL.RawQuery)
 		}
 		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Count", "1")
 		json.NewEncoder(w).Encode(entries)
 	}))
 	defer srv.Close()
 
-	c := New(srv.URL)
+	c := New(srv.URL, 0)
 	result, err := c.ListCatalog(context.Background(), database.CatalogFilter{
 		ModelHfID: "meta-llama/Llama-3.1-70B",
 	})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if len(result) != 1 {
-		t.Fatalf("expected 1 entry, got %d", len(result))
+	if len(result.Items) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(result.Items))
 	}
-	if result[0].RunID != "run-1" {
-		t.Errorf("unexpected run ID: %s", result[0].RunID)
+	if result.Items[0].RunID != "run-1" {
+		t.Errorf("unexpected run ID: %s", result.Items[0].RunID)
+	}
+	if result.Total != 1 {
+		t.Errorf("expected total 1, got %d", result.Total)
+	}
+	if result.HasMore() {
+		t.Error("expected no more pages")
+	}
+}
+
+func TestListCatalog_Pagination(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Count", "120")
+		w.Header().Set("Link", `<http://example.com/api/v1/catalog?limit=50&offset=50>; rel="next", <http://example.com/api/v1/catalog?limit=50&offset=0>; rel="prev"`)
+		json.NewEncoder(w).Encode([]database.CatalogEntry{{RunID: "run-1"}})
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, 0)
+	result, err := c.ListCatalog(context.Background(), database.CatalogFilter{Limit: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 120 {
+		t.Errorf("expected total 120, got %d", result.Total)
+	}
+	if result.NextOffset != 50 {
+		t.Errorf("expected next offset 50, got %d", result.NextOffset)
+	}
+	if !result.HasMore() {
+		t.Error("expected more pages")
 	}
 }
 
@@ -71,7 +104,7 @@ func TestListCatalog_AllFilters(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	c := New(srv.URL)
+	c := New(srv.URL, 0)
 	_, err := c.ListCatalog(context.Background(), database.CatalogFilter{
 		ModelFamily:     "llama",
 		InstanceFamily:  "p5",
@@ -84,6 +117,51 @@ func TestListCatalog_AllFilters(t *testing.T) {
 	}
 }
 
+func TestListCatalog_Cursor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("after_run_id") != "run-1" {
+			t.Errorf("expected after_run_id=run-1, got %s", q.Get("after_run_id"))
+		}
+		if q.Get("after_sort_value") != "500" {
+			t.Errorf("expected after_sort_value=500, got %s", q.Get("after_sort_value"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]database.CatalogEntry{})
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, 0)
+	_, err := c.ListCatalog(context.Background(), database.CatalogFilter{
+		SortBy:         "throughput_aggregate",
+		AfterRunID:     "run-1",
+		AfterSortValue: 500.0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFormatCursorValue(t *testing.T) {
+	ttft := 12.5
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	cases := []struct {
+		in   any
+		want string
+	}{
+		{nil, ""},
+		{&ts, "2026-01-02T03:04:05Z"},
+		{&ttft, "12.5"},
+		{(*float64)(nil), ""},
+		{"run-1", "run-1"},
+	}
+	for _, c := range cases {
+		if got := FormatCursorValue(c.in); got != c.want {
+			t.Errorf("FormatCursorValue(%v) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
 func TestCreateRun(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -102,11 +180,11 @@ func TestCreateRun(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	c := New(srv.URL)
+	c := New(srv.URL, 0)
 	id, status, err := c.CreateRun(context.Background(), database.RunRequest{
-		ModelHfID:    "test-model",
-		RunType:      "on_demand",
-		Concurrency:  1,
+		ModelHfID:   "test-model",
+		RunType:     "on_demand",
+		Concurrency: 1,
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -119,10 +197,72 @@ func TestCreateRun(t *testing.T) {
 	}
 }
 
+func TestListRuns(t *testing.T) {
+	runs := []database.RunListItem{
+		{ID: "run-1", ModelHfID: "meta-llama/Llama-3.1-70B", Status: "running"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/runs" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("statuses") != "running,pending" {
+			t.Errorf("expected statuses filter, got query: %s", r.URL.RawQuery)
+		}
+		if q.Get("framework") != "vllm" {
+			t.Errorf("expected framework filter, got query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Count", "1")
+		json.NewEncoder(w).Encode(runs)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, 0)
+	result, err := c.ListRuns(context.Background(), database.RunFilter{
+		Statuses:  []string{"running", "pending"},
+		Framework: "vllm",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 1 || result.Items[0].ID != "run-1" {
+		t.Fatalf("unexpected items: %+v", result.Items)
+	}
+	if result.Total != 1 {
+		t.Errorf("expected total 1, got %d", result.Total)
+	}
+}
+
+func TestListRuns_KeysetCursor(t *testing.T) {
+	after := time.Now().Add(-time.Hour)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("after_id") != "run-9" {
+			t.Errorf("expected after_id, got query: %s", r.URL.RawQuery)
+		}
+		if q.Get("after_created_at") == "" {
+			t.Errorf("expected after_created_at, got query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]database.RunListItem{})
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, 0)
+	_, err := c.ListRuns(context.Background(), database.RunFilter{
+		AfterID:        "run-9",
+		AfterCreatedAt: after,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestGetRun(t *testing.T) {
 	run := database.BenchmarkRun{
-		ID:     "run-abc",
-		Status: "completed",
+		ID:        "run-abc",
+		Status:    "completed",
 		CreatedAt: time.Now(),
 	}
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -133,7 +273,7 @@ func TestGetRun(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	c := New(srv.URL)
+	c := New(srv.URL, 0)
 	result, err := c.GetRun(context.Background(), "run-abc")
 	if err != nil {
 		t.Fatal(err)
@@ -150,9 +290,9 @@ func TestGetMetrics(t *testing.T) {
 	ttft := 15.5
 	tput := 1200.0
 	metrics := database.BenchmarkMetrics{
-		ID:    "m-1",
-		RunID: "run-xyz",
-		TTFTP50Ms: &ttft,
+		ID:                     "m-1",
+		RunID:                  "run-xyz",
+		TTFTP50Ms:              &ttft,
 		ThroughputAggregateTPS: &tput,
 	}
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -163,7 +303,7 @@ func TestGetMetrics(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	c := New(srv.URL)
+	c := New(srv.URL, 0)
 	result, err := c.GetMetrics(context.Background(), "run-xyz")
 	if err != nil {
 		t.Fatal(err)
@@ -176,6 +316,58 @@ func TestGetMetrics(t *testing.T) {
 	}
 }
 
+func TestWaitForRun_TerminalEvent(t *testing.T) {
+	run := database.BenchmarkRun{ID: "run-wait", Status: "completed"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/runs/run-wait/events":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "id: 1\nevent: run-update\ndata: {\"status\":\"running\"}\n\n")
+			w.(http.Flusher).Flush()
+			fmt.Fprintf(w, "id: 2\nevent: run-update\ndata: {\"status\":\"completed\"}\n\n")
+			w.(http.Flusher).Flush()
+		case "/api/v1/runs/run-wait":
+			json.NewEncoder(w).Encode(run)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, 0)
+	result, err := c.WaitForRun(context.Background(), "run-wait")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != "completed" {
+		t.Errorf("expected completed, got %s", result.Status)
+	}
+}
+
+func TestWaitForRun_StreamClosesWithoutTerminalEvent(t *testing.T) {
+	run := database.BenchmarkRun{ID: "run-done-already", Status: "failed"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/runs/run-done-already/events":
+			w.Header().Set("Content-Type", "text/event-stream")
+		case "/api/v1/runs/run-done-already":
+			json.NewEncoder(w).Encode(run)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, 0)
+	result, err := c.WaitForRun(context.Background(), "run-done-already")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != "failed" {
+		t.Errorf("expected failed, got %s", result.Status)
+	}
+}
+
 func TestAPIError(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -184,7 +376,7 @@ func TestAPIError(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	c := New(srv.URL)
+	c := New(srv.URL, 0)
 	_, err := c.GetRun(context.Background(), "nonexistent")
 	if err == nil {
 		t.Fatal("expected error")
@@ -202,7 +394,7 @@ func TestCreateRun_APIError(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	c := New(srv.URL)
+	c := New(srv.URL, 0)
 	_, _, err := c.CreateRun(context.Background(), database.RunRequest{ModelHfID: "missing"})
 	if err == nil {
 		t.Fatal("expected error")
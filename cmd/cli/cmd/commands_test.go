@@ -158,15 +158,15 @@ func TestStatusCommand_Completed(t *testing.T) {
 			})
 		default:
 			json.NewEncoder(w).Encode(database.BenchmarkRun{
-				ID:          "run-done",
-				Status:      "completed",
-				Framework:   "vllm",
-				FrameworkVersion: "0.4.0",
-				Concurrency: 8,
+				ID:                   "run-done",
+				Status:               "completed",
+				Framework:            "vllm",
+				FrameworkVersion:     "0.4.0",
+				Concurrency:          8,
 				TensorParallelDegree: 4,
-				StartedAt:   &now,
-				CompletedAt: &now,
-				CreatedAt:   now,
+				StartedAt:            &now,
+				CompletedAt:          &now,
+				CreatedAt:            now,
 			})
 		}
 	}))
@@ -203,8 +203,8 @@ func TestStatusCommand_JSON(t *testing.T) {
 			return
 		}
 		json.NewEncoder(w).Encode(database.BenchmarkRun{
-			ID:     "run-1",
-			Status: "pending",
+			ID:        "run-1",
+			Status:    "pending",
 			CreatedAt: time.Now(),
 		})
 	}))
@@ -232,6 +232,8 @@ func TestCompareCommand(t *testing.T) {
 	outputFormat = "table"
 	compareModel = "model/a"
 	compareInstances = "p5.48xlarge,g6e.48xlarge"
+	compareBaseline = ""
+	compareRegressionThreshold = ""
 
 	err := runCompare(nil, nil)
 	if err != nil {
@@ -251,6 +253,8 @@ func TestCompareCommand_NoFilter(t *testing.T) {
 	outputFormat = "table"
 	compareModel = "model/a"
 	compareInstances = ""
+	compareBaseline = ""
+	compareRegressionThreshold = ""
 
 	err := runCompare(nil, nil)
 	if err != nil {
@@ -258,6 +262,50 @@ func TestCompareCommand_NoFilter(t *testing.T) {
 	}
 }
 
+func TestCompareCommand_BaselineRegression(t *testing.T) {
+	ttft1, ttft2 := 10.0, 15.0
+	tput1, tput2 := 1000.0, 800.0
+	entries := []database.CatalogEntry{
+		{RunID: "r1", ModelHfID: "model/a", InstanceTypeName: "p5.48xlarge", TTFTP50Ms: &ttft1, ThroughputAggregateTPS: &tput1},
+		{RunID: "r2", ModelHfID: "model/a", InstanceTypeName: "g6e.48xlarge", TTFTP50Ms: &ttft2, ThroughputAggregateTPS: &tput2},
+	}
+	setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(entries)
+	}))
+
+	outputFormat = "json"
+	compareModel = "model/a"
+	compareInstances = ""
+	compareBaseline = "p5.48xlarge"
+	compareRegressionThreshold = ""
+
+	if err := runCompare(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompareCommand_RegressionThresholdBreach(t *testing.T) {
+	ttft1, ttft2 := 10.0, 15.0
+	entries := []database.CatalogEntry{
+		{RunID: "r1", ModelHfID: "model/a", InstanceTypeName: "p5.48xlarge", TTFTP50Ms: &ttft1},
+		{RunID: "r2", ModelHfID: "model/a", InstanceTypeName: "g6e.48xlarge", TTFTP50Ms: &ttft2},
+	}
+	setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(entries)
+	}))
+
+	outputFormat = "table"
+	compareModel = "model/a"
+	compareInstances = ""
+	compareBaseline = "p5.48xlarge"
+	compareRegressionThreshold = "ttft:+10%"
+
+	err := runCompare(nil, nil)
+	if err == nil {
+		t.Fatal("expected error for breached regression threshold, got nil")
+	}
+}
+
 func TestExportCommand_JSON(t *testing.T) {
 	entries := []database.CatalogEntry{
 		{RunID: "r1", ModelHfID: "test/m", InstanceTypeName: "p5.48xlarge"},
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -9,7 +8,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/accelbench/accelbench/cmd/cli/format"
+	"github.com/accelbench/accelbench/internal/analysis"
 	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/pricing"
 )
 
 var compareCmd = &cobra.Command{
@@ -17,35 +18,83 @@ var compareCmd = &cobra.Command{
 	Short: "Compare benchmark results across instance types",
 	Long: `Compare benchmark results for a model across multiple instance types.
 
+With --baseline, each other result is compared against the baseline row and
+flagged for regressions. --regression-threshold (e.g. "ttft:+10%,tput:-5%")
+gates the command's exit code on those comparisons, so it can run in CI.
+
 Examples:
   accelbench compare --model meta-llama/Llama-3.1-70B-Instruct --instances p5.48xlarge,g6e.48xlarge
-  accelbench compare --model meta-llama/Llama-3.1-70B-Instruct -o json`,
+  accelbench compare --model meta-llama/Llama-3.1-70B-Instruct -o json
+  accelbench compare --model meta-llama/Llama-3.1-70B-Instruct --baseline p5.48xlarge --regression-threshold ttft:+10%,tput:-5%`,
 	RunE: runCompare,
 }
 
 var (
-	compareModel     string
-	compareInstances string
+	compareModel               string
+	compareInstances           string
+	compareBaseline            string
+	compareRegressionThreshold string
+	compareSort                string
+	compareDesc                bool
+	compareCurrency            string
+	comparePUE                 float64
+	comparePricingFile         string
+	comparePricingProvider     string
+	comparePricingRegion       string
 )
 
 func init() {
 	compareCmd.Flags().StringVar(&compareModel, "model", "", "Model HuggingFace ID (required)")
 	compareCmd.Flags().StringVar(&compareInstances, "instances", "", "Comma-separated instance type names to compare")
+	compareCmd.Flags().StringVar(&compareBaseline, "baseline", "", "Run ID or instance type name to treat as the regression baseline")
+	compareCmd.Flags().StringVar(&compareRegressionThreshold, "regression-threshold", "", `Regression thresholds to gate on, e.g. "ttft:+10%,tput:-5%" (requires --baseline)`)
+	compareCmd.Flags().StringVar(&compareSort, "sort", "", "Sort by column, including cost_per_mtoken or tokens_per_joule")
+	compareCmd.Flags().BoolVar(&compareDesc, "desc", false, "Sort descending")
+	compareCmd.Flags().StringVar(&compareCurrency, "currency", "USD", "Currency for the cost columns (USD, EUR, GBP, JPY)")
+	compareCmd.Flags().Float64Var(&comparePUE, "pue", 1.0, "Data-center power-usage-effectiveness multiplier applied to device power draw")
+	compareCmd.Flags().StringVar(&comparePricingFile, "pricing-file", "", "YAML file of instance on-demand rates, overriding the built-in defaults")
+	compareCmd.Flags().StringVar(&comparePricingProvider, "pricing-provider", "static", `Pricing source: "static" (pricing-file or built-in defaults) or "aws" (live AWS Pricing API)`)
+	compareCmd.Flags().StringVar(&comparePricingRegion, "pricing-region", "us-east-2", "Region to price against when --pricing-provider=aws")
 	_ = compareCmd.MarkFlagRequired("model")
 	RootCmd.AddCommand(compareCmd)
 }
 
+// regressionMetricOrder fixes the metrics checked for every --baseline
+// comparison, and the order they're reported in.
+var regressionMetricOrder = []string{"ttft", "itl", "e2e", "tput"}
+
+// regressionMetricFields maps a --regression-threshold metric key to the
+// CatalogEntry field it compares.
+var regressionMetricFields = map[string]func(database.CatalogEntry) *float64{
+	"ttft": func(e database.CatalogEntry) *float64 { return e.TTFTP50Ms },
+	"itl":  func(e database.CatalogEntry) *float64 { return e.ITLP50Ms },
+	"e2e":  func(e database.CatalogEntry) *float64 { return e.E2ELatencyP50Ms },
+	"tput": func(e database.CatalogEntry) *float64 { return e.ThroughputAggregateTPS },
+}
+
 func runCompare(cmd *cobra.Command, args []string) error {
+	ctx, cancel := withTimeout()
+	defer cancel()
 	c := newClient()
 
+	// cost_per_mtoken/tokens_per_joule are computed client-side from the
+	// pricing overlay, so they can't be pushed down as a server sort column.
+	serverSort := compareSort
+	if pricing.IsClientSortKey(compareSort) {
+		serverSort = ""
+	}
+
 	// Fetch catalog entries for the model.
-	entries, err := c.ListCatalog(context.Background(), database.CatalogFilter{
+	result, err := c.ListCatalog(ctx, database.CatalogFilter{
 		ModelHfID: compareModel,
+		SortBy:    serverSort,
+		SortDesc:  compareDesc,
 		Limit:     500,
 	})
 	if err != nil {
 		return err
 	}
+	entries := result.Items
 
 	// Filter to requested instances if specified.
 	if compareInstances != "" {
@@ -67,35 +116,162 @@ func runCompare(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	provider, err := newPricingProvider(ctx, comparePricingProvider, comparePricingFile, comparePricingRegion)
+	if err != nil {
+		return err
+	}
+	derived := derivedColumns(ctx, entries, provider, comparePUE)
+	if pricing.IsClientSortKey(compareSort) {
+		sortByDerived(entries, derived, compareSort, compareDesc)
+	}
+
+	var baseline *database.CatalogEntry
+	var thresholds []analysis.Threshold
+	deltas := make(map[string][]analysis.Delta)
+	breached := false
+
+	if compareBaseline != "" {
+		for i := range entries {
+			if entries[i].RunID == compareBaseline || entries[i].InstanceTypeName == compareBaseline {
+				baseline = &entries[i]
+				break
+			}
+		}
+		if baseline == nil {
+			return fmt.Errorf("baseline %q not found among compared results", compareBaseline)
+		}
+
+		thresholds, err = analysis.ParseThresholds(compareRegressionThreshold)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range entries {
+			if e.RunID == baseline.RunID {
+				continue
+			}
+			ds := regressionDeltas(*baseline, e)
+			deltas[e.RunID] = ds
+			for _, d := range ds {
+				for _, th := range thresholds {
+					if th.Metric == d.Metric && th.Breaches(d.DeltaPct) {
+						breached = true
+					}
+				}
+			}
+		}
+	}
+
 	switch getFormat() {
 	case format.FormatJSON:
-		return format.JSON(entries)
+		if err := format.JSON(compareJSONRows(entries, derived, deltas)); err != nil {
+			return err
+		}
 	case format.FormatCSV:
-		return format.CSV(os.Stdout, compareHeaders(), compareRows(entries))
+		if err := format.CSV(os.Stdout, compareHeaders(baseline != nil, compareCurrency), compareRows(entries, derived, deltas, thresholds, baseline != nil, compareCurrency)); err != nil {
+			return err
+		}
 	default:
-		format.Table(compareHeaders(), compareRows(entries))
+		format.Table(compareHeaders(baseline != nil, compareCurrency), compareRows(entries, derived, deltas, thresholds, baseline != nil, compareCurrency))
 		fmt.Fprintf(os.Stderr, "\n%d configuration(s) compared\n", len(entries))
-		return nil
 	}
+
+	if breached {
+		return fmt.Errorf("one or more metrics breached --regression-threshold vs baseline %q", compareBaseline)
+	}
+	return nil
+}
+
+// regressionDeltas computes a Delta for each regression metric present on
+// both baseline and candidate, in regressionMetricOrder.
+func regressionDeltas(baseline, candidate database.CatalogEntry) []analysis.Delta {
+	var ds []analysis.Delta
+	for _, key := range regressionMetricOrder {
+		get := regressionMetricFields[key]
+		bv, cv := get(baseline), get(candidate)
+		if bv == nil || cv == nil {
+			continue
+		}
+		ds = append(ds, analysis.Compare(key, []float64{*bv}, []float64{*cv}))
+	}
+	return ds
+}
+
+// primaryDelta picks the Delta to surface in the table's single "Δ vs
+// baseline" column: the first metric breaching a threshold, or otherwise
+// the first metric in regressionMetricOrder.
+func primaryDelta(ds []analysis.Delta, thresholds []analysis.Threshold) *analysis.Delta {
+	for _, th := range thresholds {
+		for i := range ds {
+			if ds[i].Metric == th.Metric && th.Breaches(ds[i].DeltaPct) {
+				return &ds[i]
+			}
+		}
+	}
+	if len(ds) > 0 {
+		return &ds[0]
+	}
+	return nil
 }
 
-func compareHeaders() []string {
-	return []string{
+func formatDelta(d *analysis.Delta) string {
+	if d == nil {
+		return "-"
+	}
+	s := fmt.Sprintf("%+.1f%%", d.DeltaPct)
+	if d.Significant {
+		s += "*"
+	}
+	return s
+}
+
+// compareResult is the JSON shape for one compared entry: the catalog entry
+// plus its cost/energy overlay and, when --baseline is set, its per-metric
+// regression breakdown.
+type compareResult struct {
+	database.CatalogEntry
+	Cost       pricing.Derived           `json:"cost"`
+	Regression map[string]analysis.Delta `json:"regression,omitempty"`
+}
+
+func compareJSONRows(entries []database.CatalogEntry, derived []pricing.Derived, deltas map[string][]analysis.Delta) []compareResult {
+	rows := make([]compareResult, len(entries))
+	for i, e := range entries {
+		rows[i] = compareResult{CatalogEntry: e, Cost: derived[i]}
+		if ds, ok := deltas[e.RunID]; ok {
+			m := make(map[string]analysis.Delta, len(ds))
+			for _, d := range ds {
+				m[d.Metric] = d
+			}
+			rows[i].Regression = m
+		}
+	}
+	return rows
+}
+
+func compareHeaders(withDelta bool, currency string) []string {
+	headers := []string{
 		"Instance", "Accel", "TP", "Quant",
 		"TTFT p50", "E2E p50", "ITL p50",
 		"Tput(agg)", "Tput(req)", "RPS",
-		"GPU%", "Mem(GiB)",
+		"GPU%", "SM%", "Mem(GiB)",
+		"SrvTTFT p50", "SrvTTFT p99", "SrvE2E p50", "SrvE2E p99",
+	}
+	headers = append(headers, pricingHeaders(currency)...)
+	if withDelta {
+		headers = append(headers, "Δ vs baseline")
 	}
+	return headers
 }
 
-func compareRows(entries []database.CatalogEntry) [][]string {
+func compareRows(entries []database.CatalogEntry, derived []pricing.Derived, deltas map[string][]analysis.Delta, thresholds []analysis.Threshold, withDelta bool, currency string) [][]string {
 	rows := make([][]string, len(entries))
 	for i, e := range entries {
 		quant := "-"
 		if e.Quantization != nil {
 			quant = *e.Quantization
 		}
-		rows[i] = []string{
+		row := []string{
 			e.InstanceTypeName,
 			e.AcceleratorName,
 			fmt.Sprintf("%d", e.TensorParallelDegree),
@@ -107,8 +283,18 @@ func compareRows(entries []database.CatalogEntry) [][]string {
 			format.PtrF64(e.ThroughputPerRequestTPS, 1),
 			format.PtrF64(e.RequestsPerSecond, 2),
 			format.PtrF64(e.AcceleratorUtilizationPct, 0),
+			format.PtrF64(e.SMActivePeakPct, 0),
 			format.PtrF64(e.AcceleratorMemoryPeakGiB, 1),
+			format.PtrF64(e.ServerTTFTP50Ms, 1),
+			format.PtrF64(e.ServerTTFTP99Ms, 1),
+			format.PtrF64(e.ServerE2ELatencyP50Ms, 1),
+			format.PtrF64(e.ServerE2ELatencyP99Ms, 1),
+		}
+		row = append(row, pricingRow(derived[i], currency)...)
+		if withDelta {
+			row = append(row, formatDelta(primaryDelta(deltas[e.RunID], thresholds)))
 		}
+		rows[i] = row
 	}
 	return rows
 }
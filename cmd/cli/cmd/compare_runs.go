@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/accelbench/accelbench/cmd/cli/format"
+	"github.com/accelbench/accelbench/internal/analyzer"
+)
+
+var compareRunsCmd = &cobra.Command{
+	Use:   "runs <control-run-id> <treatment-run-id>",
+	Short: "Statistically compare two benchmark runs' raw samples",
+	Long: `Treat two benchmark runs as control/treatment and run a rigorous
+statistical A/B comparison over their raw per-request samples — mean,
+median, stddev, and IQR per metric, a bootstrap confidence interval on
+the difference of medians, and a Mann-Whitney U test p-value — instead
+of just diffing two p50 numbers. Metrics whose CI excludes zero are
+flagged significant.
+
+This is a finer-grained alternative to "accelbench compare --baseline",
+which compares pre-aggregated percentiles across instance types for one
+model; "compare runs" compares two specific runs' raw samples, the way
+you'd check whether a framework-version bump or quantization change
+actually helped.
+
+Examples:
+  accelbench compare runs run-abc123 run-def456
+  accelbench compare runs run-abc123 run-def456 -o json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCompareRuns,
+}
+
+func init() {
+	compareCmd.AddCommand(compareRunsCmd)
+}
+
+func runCompareRuns(cmd *cobra.Command, args []string) error {
+	c := newClient()
+	controlID, treatmentID := args[0], args[1]
+
+	ctx, cancel := withTimeout()
+	defer cancel()
+	report, err := c.CompareRuns(ctx, controlID, treatmentID)
+	if err != nil {
+		return err
+	}
+
+	if getFormat() == format.FormatJSON {
+		return format.JSON(report)
+	}
+
+	format.Table(
+		[]string{"Metric", "Control Median", "Treatment Median", "Δ Median", "95% CI", "p-value", "Significant"},
+		compareRunsRows(report),
+	)
+	return nil
+}
+
+func compareRunsRows(report *analyzer.Report) [][]string {
+	rows := make([][]string, len(report.Metrics))
+	for i, m := range report.Metrics {
+		sig := "no"
+		if m.Significant {
+			sig = "yes"
+		}
+		rows[i] = []string{
+			m.Metric,
+			fmt.Sprintf("%.2f", m.Control.Median),
+			fmt.Sprintf("%.2f", m.Treatment.Median),
+			fmt.Sprintf("%+.1f%%", m.MedianDeltaPct),
+			fmt.Sprintf("[%+.1f%%, %+.1f%%]", m.CILowPct, m.CIHighPct),
+			fmt.Sprintf("%.4f", m.PValue),
+			sig,
+		}
+	}
+	return rows
+}
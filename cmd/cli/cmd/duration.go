@@ -0,0 +1,30 @@
+package cmd
+
+import "time"
+
+// Duration wraps time.Duration so the same flag value can be parsed from
+// the command line (via pflag.Value, satisfied below) and decoded from a
+// YAML or JSON config file (via encoding.TextUnmarshaler), instead of
+// needing separate parsing logic for each source.
+type Duration time.Duration
+
+func (d *Duration) String() string {
+	return time.Duration(*d).String()
+}
+
+func (d *Duration) Set(s string) error {
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d *Duration) Type() string {
+	return "duration"
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	return d.Set(string(text))
+}
@@ -1,7 +1,7 @@
 package cmd
 
 import (
-	"context"
+	"bytes"
 	"fmt"
 	"os"
 
@@ -9,39 +9,51 @@ import (
 
 	"github.com/accelbench/accelbench/cmd/cli/format"
 	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/remotewrite"
 )
 
 var exportCmd = &cobra.Command{
 	Use:   "export",
-	Short: "Export benchmark results to JSON or CSV",
-	Long: `Export catalog benchmark results in JSON or CSV format.
+	Short: "Export benchmark results to JSON, CSV, or Prometheus",
+	Long: `Export catalog benchmark results in JSON, CSV, or OpenMetrics format.
 
-By default exports to stdout. Use --file to write to a file.
+By default exports to stdout. Use --file to write to a file, or
+--remote-write-url to push directly to a Prometheus remote_write endpoint.
 
 Examples:
   accelbench export -o json > results.json
   accelbench export -o csv --file results.csv
+  accelbench export -o prometheus --remote-write-url http://mimir:9009/api/v1/push
   accelbench export --model meta-llama/Llama-3.1-70B-Instruct -o csv`,
 	RunE: runExport,
 }
 
 var (
-	exportModel     string
-	exportInstFamily string
-	exportFile      string
+	exportModel             string
+	exportInstFamily        string
+	exportFile              string
+	exportRemoteWriteURL    string
+	exportRemoteWriteAuth   string
+	exportRemoteWriteTenant string
 )
 
 func init() {
 	exportCmd.Flags().StringVar(&exportModel, "model", "", "Filter by model HuggingFace ID")
 	exportCmd.Flags().StringVar(&exportInstFamily, "instance-family", "", "Filter by instance family")
 	exportCmd.Flags().StringVar(&exportFile, "file", "", "Output file path (default: stdout)")
+	exportCmd.Flags().StringVar(&exportRemoteWriteURL, "remote-write-url", "", "Prometheus remote_write endpoint to push results to")
+	exportCmd.Flags().StringVar(&exportRemoteWriteAuth, "remote-write-auth", "", "Bearer token for the remote_write endpoint")
+	exportCmd.Flags().StringVar(&exportRemoteWriteTenant, "remote-write-tenant", "", "X-Scope-OrgID tenant header for the remote_write endpoint")
 	RootCmd.AddCommand(exportCmd)
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
 	c := newClient()
 
-	entries, err := c.ListCatalog(context.Background(), database.CatalogFilter{
+	ctx, cancel := withTimeout()
+	defer cancel()
+
+	result, err := c.ListCatalog(ctx, database.CatalogFilter{
 		ModelHfID:      exportModel,
 		InstanceFamily: exportInstFamily,
 		Limit:          500,
@@ -49,12 +61,22 @@ func runExport(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	entries := result.Items
 
 	if len(entries) == 0 {
 		fmt.Fprintln(os.Stderr, "No results to export.")
 		return nil
 	}
 
+	if exportRemoteWriteURL != "" {
+		samples := remotewrite.CatalogSamples(entries, nil)
+		if err := remotewrite.Push(ctx, exportRemoteWriteURL, exportRemoteWriteAuth, exportRemoteWriteTenant, samples); err != nil {
+			return fmt.Errorf("push to remote_write: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Pushed %d sample(s) to %s\n", len(samples), exportRemoteWriteURL)
+		return nil
+	}
+
 	// Determine output destination.
 	out := os.Stdout
 	if exportFile != "" {
@@ -69,6 +91,13 @@ func runExport(cmd *cobra.Command, args []string) error {
 	switch getFormat() {
 	case format.FormatCSV:
 		return format.CSV(out, exportHeaders(), exportRows(entries))
+	case format.FormatPrometheus:
+		var buf bytes.Buffer
+		if err := remotewrite.RenderOpenMetrics(&buf, remotewrite.CatalogSamples(entries, nil)); err != nil {
+			return err
+		}
+		_, err := out.Write(buf.Bytes())
+		return err
 	default:
 		// Default to JSON for export.
 		return format.JSONTo(out, entries)
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/accelbench/accelbench/cmd/cli/format"
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Scan the catalog for silently-broken benchmark runs",
+	Long: `Run integrity checks (sample monotonicity, percentile sanity, required-field
+completeness, model/instance reference integrity) over catalog entries.
+
+Examples:
+  accelbench health
+  accelbench health --model meta-llama/Llama-3.1-70B-Instruct
+  accelbench health --repair`,
+	RunE: runHealth,
+}
+
+var (
+	healthModel          string
+	healthModelFamily    string
+	healthInstanceFamily string
+	healthAccelType      string
+	healthRepair         bool
+)
+
+func init() {
+	healthCmd.Flags().StringVar(&healthModel, "model", "", "Filter by model HuggingFace ID")
+	healthCmd.Flags().StringVar(&healthModelFamily, "model-family", "", "Filter by model family (e.g. llama, mistral)")
+	healthCmd.Flags().StringVar(&healthInstanceFamily, "instance-family", "", "Filter by instance family (e.g. p5, g6e, inf2)")
+	healthCmd.Flags().StringVar(&healthAccelType, "accelerator-type", "", "Filter by accelerator type (gpu or neuron)")
+	healthCmd.Flags().BoolVar(&healthRepair, "repair", false, "Supersede unhealthy runs instead of just reporting them")
+	RootCmd.AddCommand(healthCmd)
+}
+
+func runHealth(cmd *cobra.Command, args []string) error {
+	ctx, cancel := withTimeout()
+	defer cancel()
+	c := newClient()
+
+	filter := database.CatalogFilter{
+		ModelHfID:       healthModel,
+		ModelFamily:     healthModelFamily,
+		InstanceFamily:  healthInstanceFamily,
+		AcceleratorType: healthAccelType,
+	}
+
+	if healthRepair {
+		repaired, err := c.CatalogHealth(ctx, filter, true)
+		if err != nil {
+			return err
+		}
+		if getFormat() == format.FormatJSON {
+			return format.JSON(repaired)
+		}
+		if len(repaired) == 0 {
+			fmt.Println("No unhealthy runs found.")
+			return nil
+		}
+		fmt.Printf("Superseded %d unhealthy run(s):\n", len(repaired))
+		for _, s := range repaired {
+			fmt.Println(" ", s.RunID)
+		}
+		return nil
+	}
+
+	stats, err := c.CatalogHealth(ctx, filter, false)
+	if err != nil {
+		return err
+	}
+
+	if getFormat() == format.FormatJSON {
+		return format.JSON(stats)
+	}
+
+	if len(stats) == 0 {
+		fmt.Fprintln(os.Stderr, "No catalog entries found.")
+		return nil
+	}
+
+	rows := make([][]string, len(stats))
+	unhealthy := 0
+	for i, s := range stats {
+		healthy := "ok"
+		if !s.Healthy {
+			healthy = "UNHEALTHY"
+			unhealthy++
+		}
+		rows[i] = []string{
+			s.RunID,
+			healthy,
+			fmt.Sprintf("%d", s.MonotonicityViolations),
+			fmt.Sprintf("%t", s.PercentileSane),
+			strings.Join(s.FailureReasons, "; "),
+		}
+	}
+	format.Table([]string{"Run ID", "Status", "Monotonicity Violations", "Percentile Sane", "Failure Reasons"}, rows)
+	fmt.Fprintf(os.Stderr, "\n%d/%d run(s) unhealthy\n", unhealthy, len(stats))
+	return nil
+}
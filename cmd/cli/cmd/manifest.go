@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/accelbench/accelbench/internal/manifest"
+)
+
+var manifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "Render Kubernetes manifests for a benchmark rig without submitting a run",
+}
+
+var exportManifestCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Render a model Deployment + Service as plain YAML, a Helm chart, or a kustomize base+overlay",
+	Long: `Render the same model Deployment + Service manifest the orchestrator
+submits for a benchmark run, without actually submitting one — useful for
+reviewing or version-controlling a rig's Kubernetes manifests as a GitOps
+artifact.
+
+--format yaml (the default) prints the concatenated manifest to stdout, or
+writes it to -o/--out if given. --format helm and --format kustomize always
+require -o, since they render a directory tree (a Helm chart, or a
+kustomize base plus one overlay named after --framework and
+--accelerator-type) rather than a single file.
+
+Examples:
+  accelbench manifest export --model meta-llama/Llama-3.1-70B-Instruct --instance p5.48xlarge
+  accelbench manifest export --model meta-llama/Llama-3.1-70B-Instruct --instance p5.48xlarge --format helm -o ./chart
+  accelbench manifest export --model mistralai/Mixtral-8x7B-Instruct-v0.1 --instance g6e.12xlarge --tp 4 --format kustomize -o ./manifests`,
+	RunE: runExportManifest,
+}
+
+var (
+	manifestFormat            string
+	manifestOut               string
+	manifestName              string
+	manifestNamespace         string
+	manifestModel             string
+	manifestHfToken           string
+	manifestFramework         string
+	manifestFrameworkVer      string
+	manifestTP                int
+	manifestQuantization      string
+	manifestAcceleratorType   string
+	manifestAcceleratorCount  int
+	manifestAcceleratorMemGiB int
+	manifestInstanceTypeName  string
+	manifestInstanceFamily    string
+	manifestMaxModelLen       int
+	manifestCPURequest        string
+	manifestMemoryRequest     string
+)
+
+func init() {
+	exportManifestCmd.Flags().StringVar(&manifestFormat, "format", "yaml", "Output layout: yaml, helm, or kustomize")
+	exportManifestCmd.Flags().StringVarP(&manifestOut, "out", "o", "", "Output directory (helm/kustomize) or file (yaml); defaults to stdout for yaml")
+	exportManifestCmd.Flags().StringVar(&manifestName, "name", "", "Deployment/Service name (required)")
+	exportManifestCmd.Flags().StringVar(&manifestNamespace, "namespace", "accelbench", "Kubernetes namespace")
+	exportManifestCmd.Flags().StringVar(&manifestModel, "model", "", "Model HuggingFace ID (required)")
+	exportManifestCmd.Flags().StringVar(&manifestHfToken, "hf-token", "", "HuggingFace token, if the model is gated")
+	exportManifestCmd.Flags().StringVar(&manifestFramework, "framework", "vllm", "Serving framework (vllm or vllm-neuron)")
+	exportManifestCmd.Flags().StringVar(&manifestFrameworkVer, "framework-version", "latest", "Framework version")
+	exportManifestCmd.Flags().IntVar(&manifestTP, "tp", 1, "Tensor parallel degree")
+	exportManifestCmd.Flags().StringVar(&manifestQuantization, "quantization", "", "Quantization method (e.g. fp16, int8, int4)")
+	exportManifestCmd.Flags().StringVar(&manifestAcceleratorType, "accelerator-type", "gpu", "Accelerator type (gpu or neuron)")
+	exportManifestCmd.Flags().IntVar(&manifestAcceleratorCount, "accelerator-count", 1, "Accelerators per instance")
+	exportManifestCmd.Flags().IntVar(&manifestAcceleratorMemGiB, "accelerator-memory-gib", 0, "Accelerator memory, in GiB")
+	exportManifestCmd.Flags().StringVar(&manifestInstanceTypeName, "instance", "", "Instance type name (required)")
+	exportManifestCmd.Flags().StringVar(&manifestInstanceFamily, "instance-family", "", "Instance family (e.g. p5, g6e, inf2); defaults to --instance up to the first dot")
+	exportManifestCmd.Flags().IntVar(&manifestMaxModelLen, "max-model-len", 0, "Max model context length (0 = auto-detect)")
+	exportManifestCmd.Flags().StringVar(&manifestCPURequest, "cpu-request", "4", "CPU request for the model container")
+	exportManifestCmd.Flags().StringVar(&manifestMemoryRequest, "memory-request", "16Gi", "Memory request for the model container")
+	_ = exportManifestCmd.MarkFlagRequired("name")
+	_ = exportManifestCmd.MarkFlagRequired("model")
+	_ = exportManifestCmd.MarkFlagRequired("instance")
+
+	manifestCmd.AddCommand(exportManifestCmd)
+	RootCmd.AddCommand(manifestCmd)
+}
+
+func runExportManifest(cmd *cobra.Command, args []string) error {
+	target := manifest.RenderTarget(manifestFormat)
+	switch target {
+	case manifest.TargetYAML, manifest.TargetHelm, manifest.TargetKustomize:
+	default:
+		return fmt.Errorf("unknown --format %q (want yaml, helm, or kustomize)", manifestFormat)
+	}
+	if target != manifest.TargetYAML && manifestOut == "" {
+		return fmt.Errorf("--format %s renders a directory tree and requires -o/--out", manifestFormat)
+	}
+
+	instanceFamily := manifestInstanceFamily
+	if instanceFamily == "" {
+		instanceFamily, _, _ = cutInstanceFamily(manifestInstanceTypeName)
+	}
+
+	params := manifest.ModelDeploymentParams{
+		Name:                 manifestName,
+		Namespace:            manifestNamespace,
+		ModelHfID:            manifestModel,
+		HfToken:              manifestHfToken,
+		Framework:            manifestFramework,
+		FrameworkVersion:     manifestFrameworkVer,
+		TensorParallelDegree: manifestTP,
+		Quantization:         manifestQuantization,
+		AcceleratorType:      manifestAcceleratorType,
+		AcceleratorCount:     manifestAcceleratorCount,
+		AcceleratorMemoryGiB: manifestAcceleratorMemGiB,
+		InstanceTypeName:     manifestInstanceTypeName,
+		InstanceFamily:       instanceFamily,
+		MaxModelLen:          manifestMaxModelLen,
+		CPURequest:           manifestCPURequest,
+		MemoryRequest:        manifestMemoryRequest,
+	}
+
+	fsys, err := manifest.RenderModelDeploymentFS(params, target)
+	if err != nil {
+		return err
+	}
+
+	if target == manifest.TargetYAML {
+		data, err := fs.ReadFile(fsys, manifestName+".yaml")
+		if err != nil {
+			return err
+		}
+		if manifestOut == "" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(manifestOut, data, 0o644); err != nil {
+			return err
+		}
+	} else if err := writeFSToDir(fsys, manifestOut); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s manifest to %s\n", manifestFormat, manifestOut)
+	return nil
+}
+
+// cutInstanceFamily derives an instance family from its type name the same
+// way AWS names instance types: the characters before the first dot (e.g.
+// "p5.48xlarge" -> "p5").
+func cutInstanceFamily(instanceTypeName string) (family string, rest string, ok bool) {
+	for i, r := range instanceTypeName {
+		if r == '.' {
+			return instanceTypeName[:i], instanceTypeName[i+1:], true
+		}
+	}
+	return instanceTypeName, "", false
+}
+
+// writeFSToDir materializes every file in fsys under dir, creating parent
+// directories as needed, so Render's in-memory Helm/kustomize output can be
+// handed straight to `helm install <dir>` or `kubectl apply -k <dir>`.
+func writeFSToDir(fsys fs.FS, dir string) error {
+	return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return os.MkdirAll(dir, 0o755)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0o644)
+	})
+}
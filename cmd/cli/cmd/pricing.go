@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/accelbench/accelbench/cmd/cli/format"
+	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/pricing"
+)
+
+// newPricingProvider builds the pricing.Provider shared by --pricing-provider
+// on compare and query: "static" (the default) loads a YAML rate table —
+// the module's embedded defaults, or --pricing-file if set — and "aws"
+// queries the AWS Pricing API live, scoped to --pricing-region.
+func newPricingProvider(ctx context.Context, providerKind, pricingFile, pricingRegion string) (pricing.Provider, error) {
+	switch providerKind {
+	case "", "static":
+		return pricing.NewStaticProvider(pricingFile)
+	case "aws":
+		return pricing.NewAWSProvider(ctx, pricingRegion)
+	default:
+		return nil, fmt.Errorf("unknown --pricing-provider %q (want \"static\" or \"aws\")", providerKind)
+	}
+}
+
+// derivedColumns computes the pricing.Derived cost/energy overlay for each
+// entry, looking up each instance type's rate from provider at most once.
+// A lookup error or an unknown instance type both leave that entry's rate
+// nil, so Derive degrades gracefully (a dash in the rendered columns)
+// instead of failing the whole command.
+func derivedColumns(ctx context.Context, entries []database.CatalogEntry, provider pricing.Provider, pue float64) []pricing.Derived {
+	rates := make(map[string]*pricing.Rate)
+	out := make([]pricing.Derived, len(entries))
+	for i, e := range entries {
+		rate, looked := rates[e.InstanceTypeName]
+		if !looked {
+			r, err := provider.Rate(ctx, e.InstanceTypeName)
+			if err != nil {
+				r = nil
+			}
+			rate = r
+			rates[e.InstanceTypeName] = rate
+		}
+		out[i] = pricing.Derive(e, rate, pue)
+	}
+	return out
+}
+
+// sortByDerived reorders entries and their parallel derived slice in place
+// by one of pricing's client-side sort keys.
+func sortByDerived(entries []database.CatalogEntry, derived []pricing.Derived, key string, desc bool) {
+	get := func(i int) *float64 {
+		switch key {
+		case pricing.SortCostPerMToken:
+			return derived[i].CostPerMToken
+		case pricing.SortTokensPerJoule:
+			return derived[i].TokensPerJoule
+		default:
+			return nil
+		}
+	}
+
+	idx := make([]int, len(entries))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		va, vb := get(idx[a]), get(idx[b])
+		if va == nil {
+			return false
+		}
+		if vb == nil {
+			return true
+		}
+		if desc {
+			return *va > *vb
+		}
+		return *va < *vb
+	})
+
+	sortedEntries := make([]database.CatalogEntry, len(entries))
+	sortedDerived := make([]pricing.Derived, len(derived))
+	for i, j := range idx {
+		sortedEntries[i] = entries[j]
+		sortedDerived[i] = derived[j]
+	}
+	copy(entries, sortedEntries)
+	copy(derived, sortedDerived)
+}
+
+// pricingHeaders returns the derived-column headers shared by compare and
+// query, labeled with the currency the values were converted to.
+func pricingHeaders(currency string) []string {
+	_, code := pricing.ConvertUSD(0, currency)
+	return []string{
+		fmt.Sprintf("%s/1Mtok", code),
+		fmt.Sprintf("%s/req", code),
+		fmt.Sprintf("tok/%s", code),
+		"J/tok",
+		"tok/kWh",
+	}
+}
+
+// pricingRow formats one entry's derived columns in the requested currency.
+func pricingRow(d pricing.Derived, currency string) []string {
+	costPerMToken := "-"
+	if d.CostPerMToken != nil {
+		v, _ := pricing.ConvertUSD(*d.CostPerMToken, currency)
+		costPerMToken = format.Ptr(&v, "%.4f")
+	}
+	costPerRequest := "-"
+	if d.CostPerRequest != nil {
+		v, _ := pricing.ConvertUSD(*d.CostPerRequest, currency)
+		costPerRequest = format.Ptr(&v, "%.5f")
+	}
+	tokensPerCurrency := "-"
+	if d.TokensPerDollar != nil {
+		v, _ := pricing.ConvertPerUSD(*d.TokensPerDollar, currency)
+		tokensPerCurrency = format.Ptr(&v, "%.0f")
+	}
+	return []string{
+		costPerMToken,
+		costPerRequest,
+		tokensPerCurrency,
+		format.PtrF64(d.JoulesPerToken, 3),
+		format.PtrF64(d.TokensPerKWh, 0),
+	}
+}
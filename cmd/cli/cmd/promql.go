@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/accelbench/accelbench/cmd/cli/format"
+)
+
+var promqlCmd = &cobra.Command{
+	Use:   "promql <expr>",
+	Short: "Run a PromQL-style expression against the benchmark catalog",
+	Long: `Evaluate a read-only PromQL-style expression over the catalog, for
+queries query's --sort/--model flags can't express: arithmetic between
+metrics, aggregations, and top-k selection.
+
+Examples:
+  accelbench promql 'ttft_p99_ms{model_family="llama", instance_family="p5"}'
+  accelbench promql 'topk(5, throughput_aggregate_tps / accelerator_count)'
+  accelbench promql --range --start -1h --step 5m 'avg(accelerator_utilization_pct) by (instance_family)'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPromQL,
+}
+
+var (
+	promqlTime  string
+	promqlRange bool
+	promqlStart string
+	promqlEnd   string
+	promqlStep  string
+)
+
+func init() {
+	promqlCmd.Flags().StringVar(&promqlTime, "time", "", "Evaluation time for an instant query (RFC3339 or duration-before-now, e.g. -1h); defaults to now")
+	promqlCmd.Flags().BoolVar(&promqlRange, "range", false, "Run a range query instead of an instant query")
+	promqlCmd.Flags().StringVar(&promqlStart, "start", "-1h", "Range query start (RFC3339 or duration-before-now, e.g. -1h)")
+	promqlCmd.Flags().StringVar(&promqlEnd, "end", "", "Range query end (RFC3339 or duration-before-now); defaults to now")
+	promqlCmd.Flags().StringVar(&promqlStep, "step", "5m", "Range query step")
+	RootCmd.AddCommand(promqlCmd)
+}
+
+// parsePromQLFlagTime accepts either RFC3339 or a negative duration taken
+// relative to now (e.g. "-1h"), the same shorthand promtool's own query
+// flags accept.
+func parsePromQLFlagTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Now(), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func runPromQL(cmd *cobra.Command, args []string) error {
+	ctx, cancel := withTimeout()
+	defer cancel()
+	c := newClient()
+	expr := args[0]
+
+	if promqlRange {
+		start, err := parsePromQLFlagTime(promqlStart)
+		if err != nil {
+			return fmt.Errorf("invalid --start: %w", err)
+		}
+		end, err := parsePromQLFlagTime(promqlEnd)
+		if err != nil {
+			return fmt.Errorf("invalid --end: %w", err)
+		}
+		step, err := time.ParseDuration(promqlStep)
+		if err != nil {
+			return fmt.Errorf("invalid --step: %w", err)
+		}
+
+		matrix, err := c.QueryCatalogRange(ctx, expr, start, end, step)
+		if err != nil {
+			return err
+		}
+		if getFormat() == format.FormatJSON {
+			return format.JSON(matrix)
+		}
+		if len(matrix) == 0 {
+			fmt.Fprintln(os.Stderr, "No results found.")
+			return nil
+		}
+		var rows [][]string
+		for _, series := range matrix {
+			for _, p := range series.Points {
+				rows = append(rows, []string{fmt.Sprintf("%v", series.Labels), p.Timestamp.Format(time.RFC3339), fmt.Sprintf("%g", p.Value)})
+			}
+		}
+		format.Table([]string{"Labels", "Timestamp", "Value"}, rows)
+		return nil
+	}
+
+	at, err := parsePromQLFlagTime(promqlTime)
+	if err != nil {
+		return fmt.Errorf("invalid --time: %w", err)
+	}
+	vec, err := c.QueryCatalog(ctx, expr, at)
+	if err != nil {
+		return err
+	}
+	if getFormat() == format.FormatJSON {
+		return format.JSON(vec)
+	}
+	if len(vec) == 0 {
+		fmt.Fprintln(os.Stderr, "No results found.")
+		return nil
+	}
+	rows := make([][]string, len(vec))
+	for i, s := range vec {
+		rows[i] = []string{fmt.Sprintf("%v", s.Labels), fmt.Sprintf("%g", s.Value)}
+	}
+	format.Table([]string{"Labels", "Value"}, rows)
+	return nil
+}
@@ -1,14 +1,20 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 
+	"github.com/accelbench/accelbench/cmd/cli/client"
 	"github.com/accelbench/accelbench/cmd/cli/format"
 	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/pricing"
+	"github.com/accelbench/accelbench/internal/remotewrite"
 )
 
 var queryCmd = &cobra.Command{
@@ -24,13 +30,20 @@ Examples:
 }
 
 var (
-	queryModel          string
-	queryModelFamily    string
-	queryInstanceFamily string
-	queryAccelType      string
-	querySort           string
-	queryDesc           bool
-	queryLimit          int
+	queryModel           string
+	queryModelFamily     string
+	queryInstanceFamily  string
+	queryAccelType       string
+	querySort            string
+	queryDesc            bool
+	queryLimit           int
+	queryCurrency        string
+	queryPUE             float64
+	queryPricingFile     string
+	queryPricingProvider string
+	queryPricingRegion   string
+	queryCursor          string
+	queryAll             bool
 )
 
 func init() {
@@ -38,58 +51,268 @@ func init() {
 	queryCmd.Flags().StringVar(&queryModelFamily, "model-family", "", "Filter by model family (e.g. llama, mistral)")
 	queryCmd.Flags().StringVar(&queryInstanceFamily, "instance-family", "", "Filter by instance family (e.g. p5, g6e, inf2)")
 	queryCmd.Flags().StringVar(&queryAccelType, "accelerator-type", "", "Filter by accelerator type (gpu or neuron)")
-	queryCmd.Flags().StringVar(&querySort, "sort", "", "Sort by column (e.g. throughput_aggregate, ttft_p50, e2e_latency_p50)")
+	queryCmd.Flags().StringVar(&querySort, "sort", "", "Sort by column (e.g. throughput_aggregate, ttft_p50, e2e_latency_p50, cost_per_mtoken, tokens_per_joule)")
 	queryCmd.Flags().BoolVar(&queryDesc, "desc", false, "Sort descending")
 	queryCmd.Flags().IntVar(&queryLimit, "limit", 0, "Max results to return")
+	queryCmd.Flags().StringVar(&queryCurrency, "currency", "USD", "Currency for the cost columns (USD, EUR, GBP, JPY)")
+	queryCmd.Flags().Float64Var(&queryPUE, "pue", 1.0, "Data-center power-usage-effectiveness multiplier applied to device power draw")
+	queryCmd.Flags().StringVar(&queryPricingFile, "pricing-file", "", "YAML file of instance on-demand rates, overriding the built-in defaults")
+	queryCmd.Flags().StringVar(&queryPricingProvider, "pricing-provider", "static", `Pricing source: "static" (pricing-file or built-in defaults) or "aws" (live AWS Pricing API)`)
+	queryCmd.Flags().StringVar(&queryPricingRegion, "pricing-region", "us-east-2", "Region to price against when --pricing-provider=aws")
+	queryCmd.Flags().StringVar(&queryCursor, "cursor", "", "Opaque cursor (printed to stderr by a previous query) to resume a keyset-paginated query after")
+	queryCmd.Flags().BoolVar(&queryAll, "all", false, "Page through the entire result set using keyset pagination instead of a single bounded page; streams csv/json output incrementally")
 	RootCmd.AddCommand(queryCmd)
 }
 
+// queryPageSize is the page size used by --all and, when the result is
+// paginated with --cursor, the default --limit: the same cap Repository
+// enforces server-side, so a page never gets silently truncated further
+// than what --all already expects.
+const queryPageSize = 500
+
 func runQuery(cmd *cobra.Command, args []string) error {
+	ctx, cancel := withTimeout()
+	defer cancel()
 	c := newClient()
+
+	// cost_per_mtoken/tokens_per_joule are computed client-side from the
+	// pricing overlay, so they can't be pushed down as a server sort column
+	// — and without a stable server-side ordering, keyset pagination has
+	// nothing to page against.
+	serverSort := querySort
+	if pricing.IsClientSortKey(querySort) {
+		if queryAll {
+			return fmt.Errorf("--all can't be combined with --sort %s, which is computed client-side", querySort)
+		}
+		serverSort = ""
+	}
+
 	filter := database.CatalogFilter{
 		ModelHfID:       queryModel,
 		ModelFamily:     queryModelFamily,
 		InstanceFamily:  queryInstanceFamily,
 		AcceleratorType: queryAccelType,
-		SortBy:          querySort,
+		SortBy:          serverSort,
 		SortDesc:        queryDesc,
 		Limit:           queryLimit,
 	}
+	if queryCursor != "" {
+		runID, sortValue, err := decodeCatalogCursor(queryCursor)
+		if err != nil {
+			return fmt.Errorf("invalid --cursor: %w", err)
+		}
+		filter.AfterRunID = runID
+		filter.AfterSortValue = sortValue
+		if filter.Limit <= 0 {
+			filter.Limit = queryPageSize
+		}
+	}
 
-	entries, err := c.ListCatalog(context.Background(), filter)
+	provider, err := newPricingProvider(ctx, queryPricingProvider, queryPricingFile, queryPricingRegion)
 	if err != nil {
 		return err
 	}
 
+	if queryAll {
+		if filter.Limit <= 0 {
+			filter.Limit = queryPageSize
+		}
+		return runQueryAll(ctx, c, filter, provider)
+	}
+
+	result, err := c.ListCatalog(ctx, filter)
+	if err != nil {
+		return err
+	}
+	entries := result.Items
+
 	if len(entries) == 0 {
 		fmt.Fprintln(os.Stderr, "No results found.")
 		return nil
 	}
 
+	derived := derivedColumns(ctx, entries, provider, queryPUE)
+	if pricing.IsClientSortKey(querySort) {
+		sortByDerived(entries, derived, querySort, queryDesc)
+	}
+
 	switch getFormat() {
 	case format.FormatJSON:
-		return format.JSON(entries)
+		if err := format.JSON(queryJSONRows(entries, derived)); err != nil {
+			return err
+		}
 	case format.FormatCSV:
-		return format.CSV(os.Stdout, catalogHeaders(), catalogRows(entries))
+		if err := format.CSV(os.Stdout, catalogHeaders(queryCurrency), catalogRows(entries, derived, queryCurrency)); err != nil {
+			return err
+		}
+	case format.FormatPrometheus:
+		var buf bytes.Buffer
+		if err := remotewrite.RenderOpenMetrics(&buf, remotewrite.CatalogSamples(entries, nil)); err != nil {
+			return err
+		}
+		if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+			return err
+		}
 	default:
-		format.Table(catalogHeaders(), catalogRows(entries))
+		format.Table(catalogHeaders(queryCurrency), catalogRows(entries, derived, queryCurrency))
 		fmt.Fprintf(os.Stderr, "\n%d result(s)\n", len(entries))
 		return nil
 	}
+
+	if serverSort != "" && len(entries) == filter.Limit {
+		cursor := nextCatalogCursor(entries[len(entries)-1], serverSort)
+		fmt.Fprintf(os.Stderr, "\nMore results may remain; resume with --cursor %s\n", cursor)
+	}
+	return nil
+}
+
+// runQueryAll pages through the whole catalog matching filter using
+// keyset pagination (AfterRunID/AfterSortValue), one filter.Limit-sized
+// page per ListCatalog call. For csv/json it writes each page's rows as
+// soon as they arrive via format.CSVStream/JSONArrayStream instead of
+// buffering the whole catalog; table and prometheus output have no
+// incremental form, so those accumulate every page before rendering once,
+// same as a single non-paginated query.
+func runQueryAll(ctx context.Context, c *client.Client, filter database.CatalogFilter, provider pricing.Provider) error {
+	var (
+		total    int
+		all      []database.CatalogEntry
+		csvOut   *format.CSVStream
+		jsonOut  *format.JSONArrayStream
+		wantsCSV = getFormat() == format.FormatCSV
+		wantsJS  = getFormat() == format.FormatJSON
+	)
+	if wantsCSV {
+		csvOut = format.NewCSVStream(os.Stdout)
+		if err := csvOut.WriteHeader(catalogHeaders(queryCurrency)); err != nil {
+			return err
+		}
+	}
+	if wantsJS {
+		jsonOut = format.NewJSONArrayStream(os.Stdout)
+	}
+
+	for {
+		result, err := c.ListCatalog(ctx, filter)
+		if err != nil {
+			return err
+		}
+		entries := result.Items
+		if len(entries) == 0 {
+			break
+		}
+
+		derived := derivedColumns(ctx, entries, provider, queryPUE)
+		switch {
+		case wantsCSV:
+			for _, row := range catalogRows(entries, derived, queryCurrency) {
+				if err := csvOut.WriteRow(row); err != nil {
+					return err
+				}
+			}
+		case wantsJS:
+			for i, e := range entries {
+				if err := jsonOut.WriteElement(queryResult{CatalogEntry: e, Cost: derived[i]}); err != nil {
+					return err
+				}
+			}
+		default:
+			all = append(all, entries...)
+		}
+		total += len(entries)
+
+		if len(entries) < filter.Limit {
+			break
+		}
+		last := entries[len(entries)-1]
+		filter.AfterRunID = last.RunID
+		filter.AfterSortValue = database.CatalogEntrySortValue(last, filter.SortBy)
+	}
+
+	switch {
+	case wantsCSV:
+		return csvOut.Flush()
+	case wantsJS:
+		return jsonOut.Close()
+	case total == 0:
+		fmt.Fprintln(os.Stderr, "No results found.")
+		return nil
+	case getFormat() == format.FormatPrometheus:
+		var buf bytes.Buffer
+		if err := remotewrite.RenderOpenMetrics(&buf, remotewrite.CatalogSamples(all, nil)); err != nil {
+			return err
+		}
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	default:
+		derived := derivedColumns(ctx, all, provider, queryPUE)
+		format.Table(catalogHeaders(queryCurrency), catalogRows(all, derived, queryCurrency))
+		fmt.Fprintf(os.Stderr, "\n%d result(s)\n", total)
+		return nil
+	}
+}
+
+// catalogCursor is the opaque value --cursor encodes/decodes: the run ID
+// and sort-column value of the last entry a previous query page returned.
+// Combined with a matching --sort/--desc, it resumes ListCatalog's keyset
+// pagination exactly where that page left off.
+type catalogCursor struct {
+	RunID     string `json:"r"`
+	SortValue string `json:"v"`
+}
+
+func encodeCatalogCursor(runID, sortValue string) string {
+	b, _ := json.Marshal(catalogCursor{RunID: runID, SortValue: sortValue})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCatalogCursor(s string) (runID, sortValue string, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return "", "", err
+	}
+	var c catalogCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return "", "", err
+	}
+	return c.RunID, c.SortValue, nil
+}
+
+// nextCatalogCursor builds the cursor that resumes a query after last, sorted
+// by sortBy (ListCatalog's own default order if empty).
+func nextCatalogCursor(last database.CatalogEntry, sortBy string) string {
+	v := database.CatalogEntrySortValue(last, sortBy)
+	return encodeCatalogCursor(last.RunID, client.FormatCursorValue(v))
+}
+
+// queryResult is the JSON shape for one queried entry: the catalog entry
+// plus its cost/energy overlay.
+type queryResult struct {
+	database.CatalogEntry
+	Cost pricing.Derived `json:"cost"`
+}
+
+func queryJSONRows(entries []database.CatalogEntry, derived []pricing.Derived) []queryResult {
+	rows := make([]queryResult, len(entries))
+	for i, e := range entries {
+		rows[i] = queryResult{CatalogEntry: e, Cost: derived[i]}
+	}
+	return rows
 }
 
-func catalogHeaders() []string {
-	return []string{
+func catalogHeaders(currency string) []string {
+	headers := []string{
 		"Model", "Instance", "Accel", "TP",
 		"TTFT p50", "TTFT p99", "E2E p50", "E2E p99",
 		"ITL p50", "Tput(agg)", "RPS",
 	}
+	return append(headers, pricingHeaders(currency)...)
 }
 
-func catalogRows(entries []database.CatalogEntry) [][]string {
+func catalogRows(entries []database.CatalogEntry, derived []pricing.Derived, currency string) [][]string {
 	rows := make([][]string, len(entries))
 	for i, e := range entries {
-		rows[i] = []string{
+		row := []string{
 			e.ModelHfID,
 			e.InstanceTypeName,
 			e.AcceleratorName,
@@ -102,6 +325,7 @@ func catalogRows(entries []database.CatalogEntry) [][]string {
 			format.PtrF64(e.ThroughputAggregateTPS, 0),
 			format.PtrF64(e.RequestsPerSecond, 2),
 		}
+		rows[i] = append(row, pricingRow(derived[i], currency)...)
 	}
 	return rows
 }
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -12,6 +14,15 @@ import (
 var (
 	apiURL       string
 	outputFormat string
+
+	// requestTimeout bounds each individual client call via
+	// context.WithTimeout, so a command doesn't hang forever waiting on a
+	// stuck controller. readTimeout is propagated into the HTTP client
+	// itself as an additional, independent cap on reading a response;
+	// it defaults to 0 (disabled) so it never silently overrides
+	// --timeout — set it only to clamp reads tighter than --timeout.
+	requestTimeout = Duration(30 * time.Second)
+	readTimeout    = Duration(0)
 )
 
 // RootCmd is the top-level CLI command.
@@ -22,11 +33,19 @@ var RootCmd = &cobra.Command{
 
 func init() {
 	RootCmd.PersistentFlags().StringVar(&apiURL, "api-url", envOrDefault("ACCELBENCH_API_URL", "http://localhost:8080"), "AccelBench API base URL")
-	RootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format: table, json, csv")
+	RootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format: table, json, csv, prometheus")
+	RootCmd.PersistentFlags().Var(&requestTimeout, "timeout", "Per-request deadline for API calls (e.g. 30s, 2m)")
+	RootCmd.PersistentFlags().Var(&readTimeout, "read-timeout", "Additional HTTP client timeout for reading a response (0 disables, default); set below --timeout to fail faster on a stuck controller")
 }
 
 func newClient() *client.Client {
-	return client.New(apiURL)
+	return client.New(apiURL, time.Duration(readTimeout))
+}
+
+// withTimeout derives a context bounded by --timeout for a single command's
+// client calls. The caller must call the returned cancel func.
+func withTimeout() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), time.Duration(requestTimeout))
 }
 
 func getFormat() format.OutputFormat {
@@ -35,6 +54,8 @@ func getFormat() format.OutputFormat {
 		return format.FormatJSON
 	case "csv":
 		return format.FormatCSV
+	case "prometheus":
+		return format.FormatPrometheus
 	default:
 		return format.FormatTable
 	}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -72,7 +71,9 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 		req.Quantization = &runQuantization
 	}
 
-	id, status, err := c.CreateRun(context.Background(), req)
+	ctx, cancel := withTimeout()
+	defer cancel()
+	id, status, err := c.CreateRun(ctx, req)
 	if err != nil {
 		return err
 	}
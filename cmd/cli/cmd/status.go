@@ -1,13 +1,22 @@
 package cmd
 
 import (
-	"context"
+	"bytes"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/accelbench/accelbench/cmd/cli/client"
 	"github.com/accelbench/accelbench/cmd/cli/format"
+	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/remotewrite"
+)
+
+var (
+	statusWatch    bool
+	statusInterval = Duration(3 * time.Second)
 )
 
 var statusCmd = &cobra.Command{
@@ -15,35 +24,107 @@ var statusCmd = &cobra.Command{
 	Short: "Check the status of a benchmark run",
 	Long: `Fetch the current status and metrics (if available) for a benchmark run.
 
+With --watch, poll and re-render until the run reaches a terminal state
+("completed" or "failed") instead of exiting after one fetch.
+
 Examples:
   accelbench status abc12345-6789-0000-1111-222233334444
-  accelbench status abc12345 -o json`,
+  accelbench status abc12345 -o json
+  accelbench status abc12345 --watch --interval 5s`,
 	Args: cobra.ExactArgs(1),
 	RunE: runStatus,
 }
 
 func init() {
+	statusCmd.Flags().BoolVar(&statusWatch, "watch", false, "Poll until the run reaches a terminal state, re-rendering each time")
+	statusCmd.Flags().Var(&statusInterval, "interval", "Polling interval when --watch is set (e.g. 3s, 500ms)")
 	RootCmd.AddCommand(statusCmd)
 }
 
+// isTerminalRunStatus reports whether status is one UpdateRunStatus never
+// transitions out of, matching the "completed"/"failed" checks every
+// database backend's UpdateRunStatus uses to stop tracking a run as alive.
+func isTerminalRunStatus(status string) bool {
+	return status == "completed" || status == "failed"
+}
+
 func runStatus(cmd *cobra.Command, args []string) error {
 	c := newClient()
 	runID := args[0]
 
-	run, err := c.GetRun(context.Background(), runID)
+	for {
+		run, metrics, err := fetchStatus(c, runID)
+		if err != nil {
+			return err
+		}
+
+		if !statusWatch {
+			return renderStatus(run, metrics)
+		}
+
+		if getFormat() == format.FormatTable {
+			fmt.Print("\033[H\033[2J")
+		}
+		if err := renderStatus(run, metrics); err != nil {
+			return err
+		}
+
+		if isTerminalRunStatus(run.Status) {
+			return nil
+		}
+		time.Sleep(time.Duration(statusInterval))
+	}
+}
+
+// fetchStatus fetches a run and, for JSON output or a completed run, its
+// metrics too — matching runStatus's pre-existing single-fetch behavior so
+// --watch just repeats the same fetch instead of changing what gets shown.
+func fetchStatus(c *client.Client, runID string) (*database.BenchmarkRun, *database.BenchmarkMetrics, error) {
+	ctx, cancel := withTimeout()
+	defer cancel()
+	run, err := c.GetRun(ctx, runID)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	if getFormat() == format.FormatJSON {
-		// Include metrics in JSON output if available.
-		metrics, _ := c.GetMetrics(context.Background(), runID)
+		ctx, cancel := withTimeout()
+		defer cancel()
+		metrics, _ := c.GetMetrics(ctx, runID)
+		return run, metrics, nil
+	}
+
+	if run.Status != "completed" {
+		return run, nil, nil
+	}
+
+	ctx, cancel = withTimeout()
+	defer cancel()
+	metrics, err := c.GetMetrics(ctx, runID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: could not fetch metrics:", err)
+		return run, nil, nil
+	}
+	return run, metrics, nil
+}
+
+func renderStatus(run *database.BenchmarkRun, metrics *database.BenchmarkMetrics) error {
+	if getFormat() == format.FormatJSON {
 		return format.JSON(map[string]any{
 			"run":     run,
 			"metrics": metrics,
 		})
 	}
 
+	if getFormat() == format.FormatPrometheus {
+		var buf bytes.Buffer
+		if err := remotewrite.RenderOpenMetrics(&buf, remotewrite.RunSamples(run, metrics)); err != nil {
+			return err
+		}
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+
 	// Table output.
 	fmt.Printf("Run ID:     %s\n", run.ID)
 	fmt.Printf("Status:     %s\n", run.Status)
@@ -60,14 +141,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Completed:  %s\n", run.CompletedAt.Format("2006-01-02 15:04:05 UTC"))
 	}
 
-	if run.Status != "completed" {
-		return nil
-	}
-
-	// Show key metrics if run is complete.
-	metrics, err := c.GetMetrics(context.Background(), runID)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "Warning: could not fetch metrics:", err)
+	if metrics == nil {
 		return nil
 	}
 
@@ -85,10 +159,34 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			{"Requests/sec", format.PtrF64(metrics.RequestsPerSecond, 2) + " rps"},
 			{"GPU Utilization", format.PtrF64(metrics.AcceleratorUtilizationPct, 0) + " %"},
 			{"Peak Memory", format.PtrF64(metrics.AcceleratorMemoryPeakGiB, 1) + " GiB"},
+			{"Server TTFT p50", format.PtrF64(metrics.ServerTTFTP50Ms, 1) + " ms"},
+			{"Server TTFT p99", format.PtrF64(metrics.ServerTTFTP99Ms, 1) + " ms"},
+			{"Server E2E Latency p50", format.PtrF64(metrics.ServerE2ELatencyP50Ms, 1) + " ms"},
+			{"Server E2E Latency p99", format.PtrF64(metrics.ServerE2ELatencyP99Ms, 1) + " ms"},
 			{"Successful Requests", format.Ptr(metrics.SuccessfulRequests, "%d")},
 			{"Failed Requests", format.Ptr(metrics.FailedRequests, "%d")},
 			{"Duration", format.PtrF64(metrics.TotalDurationSeconds, 1) + " s"},
 		},
 	)
+
+	if len(metrics.PerRank) > 0 {
+		fmt.Println("\nPer-Rank Breakdown:")
+		rows := make([][]string, len(metrics.PerRank))
+		for i, rm := range metrics.PerRank {
+			smActive := "-"
+			if rm.SMActivePeakPct != nil {
+				smActive = fmt.Sprintf("%.1f", *rm.SMActivePeakPct)
+			}
+			rows[i] = []string{
+				fmt.Sprintf("%d", rm.Rank),
+				rm.PodName,
+				fmt.Sprintf("%.1f", rm.AcceleratorUtilizationPct),
+				smActive,
+				fmt.Sprintf("%.1f", rm.AcceleratorMemoryPeakGiB),
+			}
+		}
+		format.Table([]string{"Rank", "Pod", "GPU%", "SM%", "Mem(GiB)"}, rows)
+	}
+
 	return nil
 }
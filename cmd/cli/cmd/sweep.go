@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/accelbench/accelbench/cmd/cli/format"
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+var sweepCmd = &cobra.Command{
+	Use:   "sweep <spec-file>",
+	Short: "Submit a declarative experiment spec as a matrix of benchmark runs",
+	Long: `Read an ExperimentSpec from a YAML file and submit it as POST
+/api/v1/experiments, expanding one or more named "arms" into the
+Cartesian product of each arm's swept dimensions — models, instance
+types, tensor-parallel degrees, quantizations, concurrencies, and
+input/output sequence lengths. Runs are scheduled serially per instance
+family and retried on transient failure by the server; this command
+only submits the spec and returns the resulting experiment ID.
+
+Example spec file:
+  name: fp16-vs-int8
+  arms:
+    - name: control
+      model_hf_id: meta-llama/Llama-3.1-70B-Instruct
+      instance_type_name: [p5.48xlarge]
+      framework: [vllm]
+      framework_version: [latest]
+      tensor_parallel_degree: [8]
+      concurrency: [1, 8, 32]
+      input_sequence_length: [1024]
+      output_sequence_length: [512]
+      dataset_name: sharegpt
+      run_type: on_demand
+    - name: treatment
+      model_hf_id: meta-llama/Llama-3.1-70B-Instruct
+      instance_type_name: [p5.48xlarge]
+      framework: [vllm]
+      framework_version: [latest]
+      tensor_parallel_degree: [8]
+      quantization: int8
+      concurrency: [1, 8, 32]
+      input_sequence_length: [1024]
+      output_sequence_length: [512]
+      dataset_name: sharegpt
+      run_type: on_demand
+
+Examples:
+  accelbench sweep experiment.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSweep,
+}
+
+func init() {
+	RootCmd.AddCommand(sweepCmd)
+}
+
+func runSweep(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("read spec file: %w", err)
+	}
+
+	var spec database.ExperimentSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return fmt.Errorf("parse spec file: %w", err)
+	}
+
+	c := newClient()
+	ctx, cancel := withTimeout()
+	defer cancel()
+	experimentID, runIDs, err := c.CreateExperiment(ctx, spec)
+	if err != nil {
+		return err
+	}
+
+	switch getFormat() {
+	case format.FormatJSON:
+		return format.JSON(map[string]any{"experiment_id": experimentID, "run_ids": runIDs})
+	default:
+		fmt.Printf("Experiment submitted: %s (%d runs)\n", experimentID, len(runIDs))
+		fmt.Printf("Track progress: accelbench status %s\n", runIDs[0])
+		return nil
+	}
+}
@@ -14,9 +14,10 @@ import (
 type OutputFormat string
 
 const (
-	FormatTable OutputFormat = "table"
-	FormatJSON  OutputFormat = "json"
-	FormatCSV   OutputFormat = "csv"
+	FormatTable      OutputFormat = "table"
+	FormatJSON       OutputFormat = "json"
+	FormatCSV        OutputFormat = "csv"
+	FormatPrometheus OutputFormat = "prometheus"
 )
 
 // Table renders rows as a tab-aligned table to stdout.
@@ -69,6 +70,80 @@ func CSV(w io.Writer, headers []string, rows [][]string) error {
 	return cw.Error()
 }
 
+// CSVStream writes CSV rows incrementally to the given writer instead of
+// buffering every row first, for a caller (see the query command's --all
+// flag) paging through a result set too large to hold in memory at once.
+// WriteHeader must be called before the first WriteRow, and Flush once
+// after the last row to surface any buffered write error.
+type CSVStream struct {
+	cw *csv.Writer
+}
+
+// NewCSVStream creates a CSVStream writing to w.
+func NewCSVStream(w io.Writer) *CSVStream {
+	return &CSVStream{cw: csv.NewWriter(w)}
+}
+
+// WriteHeader writes the CSV header row.
+func (s *CSVStream) WriteHeader(headers []string) error {
+	return s.cw.Write(headers)
+}
+
+// WriteRow writes one CSV data row.
+func (s *CSVStream) WriteRow(row []string) error {
+	return s.cw.Write(row)
+}
+
+// Flush flushes any buffered rows and returns the first write error, if any.
+func (s *CSVStream) Flush() error {
+	s.cw.Flush()
+	return s.cw.Error()
+}
+
+// JSONArrayStream writes a JSON array incrementally, one element per
+// WriteElement call, so a caller paging through a result set too large to
+// buffer (see the query command's --all flag) doesn't have to collect the
+// whole slice just to call JSONTo once on it. Close must be called exactly
+// once after the last element, even if zero elements were ever written.
+type JSONArrayStream struct {
+	w       io.Writer
+	started bool
+}
+
+// NewJSONArrayStream creates a JSONArrayStream writing to w.
+func NewJSONArrayStream(w io.Writer) *JSONArrayStream {
+	return &JSONArrayStream{w: w}
+}
+
+// WriteElement appends v to the array, indenting it to match JSONTo's
+// top-level output.
+func (s *JSONArrayStream) WriteElement(v any) error {
+	b, err := json.MarshalIndent(v, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	prefix := "[\n  "
+	if s.started {
+		prefix = ",\n  "
+	}
+	s.started = true
+	if _, err := io.WriteString(s.w, prefix); err != nil {
+		return err
+	}
+	_, err = s.w.Write(b)
+	return err
+}
+
+// Close writes the array's closing bracket.
+func (s *JSONArrayStream) Close() error {
+	if !s.started {
+		_, err := io.WriteString(s.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(s.w, "\n]\n")
+	return err
+}
+
 // Ptr safely dereferences a pointer, returning a formatted string or "-" if nil.
 func Ptr[T any](p *T, fmtStr string) string {
 	if p == nil {
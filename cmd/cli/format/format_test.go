@@ -2,6 +2,7 @@ package format
 
 import (
 	"bytes"
+	"encoding/json"
 	"strings"
 	"testing"
 )
@@ -67,6 +68,63 @@ func TestCSV(t *testing.T) {
 	}
 }
 
+func TestCSVStream(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewCSVStream(&buf)
+	if err := s.WriteHeader([]string{"col1", "col2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteRow([]string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteRow([]string{"c", "d"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Errorf("expected 3 CSV lines, got %d", len(lines))
+	}
+	if lines[0] != "col1,col2" {
+		t.Errorf("unexpected header: %s", lines[0])
+	}
+}
+
+func TestJSONArrayStream(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewJSONArrayStream(&buf)
+	if err := s.WriteElement(map[string]string{"a": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteElement(map[string]string{"a": "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output isn't valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(got) != 2 || got[0]["a"] != "1" || got[1]["a"] != "2" {
+		t.Errorf("unexpected elements: %v", got)
+	}
+}
+
+func TestJSONArrayStream_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewJSONArrayStream(&buf)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("expected empty array, got %q", buf.String())
+	}
+}
+
 func TestPtrF64(t *testing.T) {
 	val := 123.456
 	if got := PtrF64(&val, 1); got != "123.5" {
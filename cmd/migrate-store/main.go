@@ -0,0 +1,100 @@
+// Command migrate-store replays every Model, InstanceType, BenchmarkRun,
+// BenchmarkMetrics, Sweep, Experiment, BenchmarkGroup, QueuedRun, and raw
+// time-series Sample from one database.Repo backend into another, for moving a
+// deployment between
+// --store=memory, bolt, and etcd without losing run history. Postgres
+// isn't a valid source or
+// destination here: its state is migrated with standard DB tooling
+// (pg_dump, logical replication) instead, so the Repository type
+// implements neither Snapshotter nor Importer.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+func main() {
+	var (
+		fromStore = flag.String("from", "", "source store: bolt=<path> or etcd=<endpoint1,endpoint2,...>")
+		toStore   = flag.String("to", "", "destination store: bolt=<path> or etcd=<endpoint1,endpoint2,...>")
+	)
+	flag.Parse()
+
+	if *fromStore == "" || *toStore == "" {
+		log.Fatal("both -from and -to are required, e.g. -from=bolt=./old.db -to=etcd=localhost:2379")
+	}
+
+	ctx := context.Background()
+
+	src, closeSrc, err := openSnapshotter(*fromStore)
+	if err != nil {
+		log.Fatalf("open source store %q: %v", *fromStore, err)
+	}
+	defer closeSrc()
+
+	dst, closeDst, err := openImporter(*toStore)
+	if err != nil {
+		log.Fatalf("open destination store %q: %v", *toStore, err)
+	}
+	defer closeDst()
+
+	snap, err := src.Snapshot(ctx)
+	if err != nil {
+		log.Fatalf("read snapshot: %v", err)
+	}
+	var sampleCount int
+	for _, rs := range snap.Samples {
+		sampleCount += len(rs.Samples)
+	}
+	log.Printf("Read %d models, %d instance types, %d runs, %d metrics, %d sweeps, %d experiments, %d samples, %d benchmark groups, %d queued runs from %s",
+		len(snap.Models), len(snap.InstanceTypes), len(snap.Runs), len(snap.Metrics), len(snap.Sweeps), len(snap.Experiments), sampleCount, len(snap.Groups), len(snap.Queue), *fromStore)
+
+	if err := dst.Import(ctx, snap); err != nil {
+		log.Fatalf("import snapshot: %v", err)
+	}
+	log.Printf("Migration complete: %s -> %s", *fromStore, *toStore)
+}
+
+func openSnapshotter(spec string) (database.Snapshotter, func(), error) {
+	switch driver, arg := splitSpec(spec); driver {
+	case "bolt":
+		repo, err := database.NewBoltRepo(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return repo, func() { repo.Close() }, nil
+	case "etcd":
+		repo, err := database.NewEtcdRepo(splitEndpoints(arg))
+		if err != nil {
+			return nil, nil, err
+		}
+		return repo, func() { repo.Close() }, nil
+	case "memory":
+		return database.NewMockRepo(), func() {}, nil
+	default:
+		return nil, nil, errUnknownDriver(driver)
+	}
+}
+
+func openImporter(spec string) (database.Importer, func(), error) {
+	switch driver, arg := splitSpec(spec); driver {
+	case "bolt":
+		repo, err := database.NewBoltRepo(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return repo, func() { repo.Close() }, nil
+	case "etcd":
+		repo, err := database.NewEtcdRepo(splitEndpoints(arg))
+		if err != nil {
+			return nil, nil, err
+		}
+		return repo, func() { repo.Close() }, nil
+	default:
+		return nil, nil, errUnknownDriver(driver)
+	}
+}
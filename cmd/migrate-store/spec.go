@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitSpec splits a "-from"/"-to" value of the form "driver=arg" (e.g.
+// "bolt=./old.db" or "etcd=localhost:2379") into its driver and argument.
+func splitSpec(spec string) (driver, arg string) {
+	parts := strings.SplitN(spec, "=", 2)
+	if len(parts) != 2 {
+		return spec, ""
+	}
+	return parts[0], parts[1]
+}
+
+func splitEndpoints(arg string) []string {
+	endpoints := strings.Split(arg, ",")
+	for i := range endpoints {
+		endpoints[i] = strings.TrimSpace(endpoints[i])
+	}
+	return endpoints
+}
+
+func errUnknownDriver(driver string) error {
+	return fmt.Errorf("unknown store driver %q: want bolt, etcd, or memory", driver)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	pricingtypes "github.com/aws/aws-sdk-go-v2/service/pricing/types"
+)
+
+// awsProvider fetches on-demand and reserved rates from the AWS Pricing
+// API. The Pricing API has no spot endpoint (spot prices come from
+// EC2's DescribeSpotPriceHistory, a different API with its own auth and
+// rate-limit shape); FetchPricing always returns a nil spot rate rather
+// than half-implement that separately.
+type awsProvider struct {
+	client *pricing.Client
+}
+
+// newAWSProvider builds an awsProvider using the default AWS credential
+// chain. The Pricing API is only available in us-east-1 and is queried
+// there regardless of which region's rates are being looked up.
+func newAWSProvider(ctx context.Context) (*awsProvider, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion("us-east-1"))
+	if err != nil {
+		return nil, fmt.Errorf("load AWS config: %w", err)
+	}
+	return &awsProvider{client: pricing.NewFromConfig(cfg)}, nil
+}
+
+// FetchPricing implements Provider.
+func (p *awsProvider) FetchPricing(ctx context.Context, instanceType, region string) (onDemand float64, res1yr, res3yr, spot *float64, err error) {
+	input := &pricing.GetProductsInput{
+		ServiceCode: strPtr("AmazonEC2"),
+		Filters: []pricingtypes.Filter{
+			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("instanceType"), Value: strPtr(instanceType)},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("operatingSystem"), Value: strPtr("Linux")},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("tenancy"), Value: strPtr("Shared")},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("preInstalledSw"), Value: strPtr("NA")},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("capacitystatus"), Value: strPtr("Used")},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("regionCode"), Value: strPtr(region)},
+		},
+		MaxResults: int32Ptr(10),
+	}
+
+	resp, err := p.client.GetProducts(ctx, input)
+	if err != nil {
+		return 0, nil, nil, nil, fmt.Errorf("GetProducts: %w", err)
+	}
+	if len(resp.PriceList) == 0 {
+		return 0, nil, nil, nil, fmt.Errorf("no pricing found for %s in %s", instanceType, region)
+	}
+
+	var product priceDoc
+	if err := json.Unmarshal([]byte(resp.PriceList[0]), &product); err != nil {
+		return 0, nil, nil, nil, fmt.Errorf("parse price list: %w", err)
+	}
+
+	onDemand, err = extractOnDemand(product.Terms.OnDemand)
+	if err != nil {
+		return 0, nil, nil, nil, fmt.Errorf("on-demand: %w", err)
+	}
+
+	res1yr = extractReserved(product.Terms.Reserved, "1yr")
+	res3yr = extractReserved(product.Terms.Reserved, "3yr")
+
+	return onDemand, res1yr, res3yr, nil, nil
+}
+
+// priceDoc represents the relevant structure of an AWS Pricing API response entry.
+type priceDoc struct {
+	Terms struct {
+		OnDemand map[string]termEntry `json:"OnDemand"`
+		Reserved map[string]termEntry `json:"Reserved"`
+	} `json:"terms"`
+}
+
+type termEntry struct {
+	PriceDimensions map[string]priceDimension `json:"priceDimensions"`
+	TermAttributes  map[string]string         `json:"termAttributes"`
+}
+
+type priceDimension struct {
+	Unit         string            `json:"unit"`
+	PricePerUnit map[string]string `json:"pricePerUnit"`
+}
+
+func extractOnDemand(terms map[string]termEntry) (float64, error) {
+	for _, term := range terms {
+		for _, pd := range term.PriceDimensions {
+			if pd.Unit == "Hrs" {
+				usd, ok := pd.PricePerUnit["USD"]
+				if !ok {
+					continue
+				}
+				return strconv.ParseFloat(usd, 64)
+			}
+		}
+	}
+	return 0, fmt.Errorf("no hourly on-demand price found")
+}
+
+// extractReserved finds the All Upfront, Standard reserved price for the given
+// lease length ("1yr" or "3yr") and returns the effective hourly rate.
+func extractReserved(terms map[string]termEntry, lease string) *float64 {
+	for _, term := range terms {
+		attrs := term.TermAttributes
+		if attrs["LeaseContractLength"] != lease ||
+			attrs["PurchaseOption"] != "All Upfront" ||
+			attrs["OfferingClass"] != "standard" {
+			continue
+		}
+
+		// Find the upfront fee (unit = "Quantity").
+		for _, pd := range term.PriceDimensions {
+			if pd.Unit == "Quantity" {
+				usd, ok := pd.PricePerUnit["USD"]
+				if !ok {
+					continue
+				}
+				upfront, err := strconv.ParseFloat(usd, 64)
+				if err != nil || upfront <= 0 {
+					continue
+				}
+				var hours float64
+				switch lease {
+				case "1yr":
+					hours = 8760
+				case "3yr":
+					hours = 26280
+				}
+				hourly := upfront / hours
+				return &hourly
+			}
+		}
+	}
+	return nil
+}
+
+func strPtr(s string) *string { return &s }
+func int32Ptr(i int32) *int32 { return &i }
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// azureRetailPricesURL is the Azure Retail Prices API, a public,
+// unauthenticated REST endpoint (https://learn.microsoft.com/en-us/rest/api/cost-management/retail-prices/azure-retail-prices).
+const azureRetailPricesURL = "https://prices.azure.com/api/retail/prices"
+
+// azureProvider fetches ND-series VM rates from the Azure Retail Prices
+// API. No credentials are needed; each lookup issues a fresh OData query
+// scoped to the instance type and region.
+type azureProvider struct {
+	httpClient *http.Client
+}
+
+func newAzureProvider() *azureProvider {
+	return &azureProvider{httpClient: http.DefaultClient}
+}
+
+// FetchPricing implements Provider. instanceType is an Azure VM size,
+// e.g. "Standard_ND96isr_H100_v5"; region is an armRegionName, e.g.
+// "eastus".
+func (p *azureProvider) FetchPricing(ctx context.Context, instanceType, region string) (onDemand float64, res1yr, res3yr, spot *float64, err error) {
+	items, err := p.queryItems(ctx, instanceType, region)
+	if err != nil {
+		return 0, nil, nil, nil, err
+	}
+
+	var onDemandFound bool
+	for _, item := range items {
+		if item.Type != "Consumption" {
+			continue
+		}
+		switch {
+		case strings.Contains(item.SkuName, "Spot"):
+			rate := item.RetailPrice
+			spot = &rate
+		case strings.Contains(item.SkuName, "Low Priority"):
+			// Low-priority VMs predate Spot; ignore them so they don't
+			// shadow a Spot SKU when both appear for older regions.
+		default:
+			onDemand = item.RetailPrice
+			onDemandFound = true
+		}
+	}
+	if !onDemandFound {
+		return 0, nil, nil, nil, fmt.Errorf("no on-demand price found for %s in %s", instanceType, region)
+	}
+
+	// Reservations are sold as 1yr/3yr "Reservation" priceType entries
+	// with a different unitOfMeasure (per-VM, not hourly); amortizing
+	// those to an hourly rate needs the reservation term's savings plan
+	// details the Retail Prices API doesn't expose per-SKU, so res1yr
+	// and res3yr are left nil here.
+	return onDemand, nil, nil, spot, nil
+}
+
+func (p *azureProvider) queryItems(ctx context.Context, instanceType, region string) ([]azureItem, error) {
+	filter := fmt.Sprintf("armRegionName eq '%s' and armSkuName eq '%s' and priceType eq 'Consumption'", region, instanceType)
+	q := url.Values{"$filter": {filter}}
+	reqURL := azureRetailPricesURL + "?" + q.Encode()
+
+	var all []azureItem
+	for reqURL != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := p.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var page azureItemPage
+		decErr := json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("query retail prices: status %d", resp.StatusCode)
+		}
+		if decErr != nil {
+			return nil, fmt.Errorf("decode retail prices: %w", decErr)
+		}
+		all = append(all, page.Items...)
+		reqURL = page.NextPageLink
+	}
+	return all, nil
+}
+
+type azureItemPage struct {
+	Items        []azureItem `json:"Items"`
+	NextPageLink string      `json:"NextPageLink"`
+}
+
+type azureItem struct {
+	RetailPrice float64 `json:"retailPrice"`
+	SkuName     string  `json:"skuName"`
+	Type        string  `json:"type"` // "Consumption" or "Reservation"
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// gcpComputeServiceID is Compute Engine's fixed service ID in the Cloud
+// Billing Catalog API (https://cloud.google.com/billing/v1/how-tos/catalog-api).
+const gcpComputeServiceID = "6F81-5844-456A"
+
+// gcpCatalogBaseURL is the Cloud Billing Catalog API root.
+const gcpCatalogBaseURL = "https://cloudbilling.googleapis.com/v1"
+
+// gcpProvider fetches A3/A2 GPU VM rates from the GCP Cloud Billing
+// Catalog API. The Catalog API has no server-side filter by machine type
+// or region, so each call pages through every Compute Engine SKU once
+// and caches the whole catalog for the life of the provider.
+type gcpProvider struct {
+	httpClient *http.Client
+	apiKey     string
+
+	skus []gcpSKU
+}
+
+// newGCPProvider builds a gcpProvider. apiKey is a Cloud Billing API key
+// (GOOGLE_CLOUD_BILLING_API_KEY); the Catalog API is one of the few GCP
+// APIs that accepts a bare API key instead of OAuth.
+func newGCPProvider(apiKey string) (*gcpProvider, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("gcp provider requires an API key")
+	}
+	return &gcpProvider{httpClient: http.DefaultClient, apiKey: apiKey}, nil
+}
+
+// FetchPricing implements Provider.
+func (p *gcpProvider) FetchPricing(ctx context.Context, instanceType, region string) (onDemand float64, res1yr, res3yr, spot *float64, err error) {
+	if p.skus == nil {
+		p.skus, err = p.loadSKUs(ctx)
+		if err != nil {
+			return 0, nil, nil, nil, fmt.Errorf("load GCP catalog: %w", err)
+		}
+	}
+
+	var onDemandFound bool
+	for _, sku := range p.skus {
+		if !strings.Contains(sku.Description, instanceType) || !sku.servesRegion(region) {
+			continue
+		}
+		rate, ok := sku.hourlyUSD()
+		if !ok {
+			continue
+		}
+		switch sku.Category.UsageType {
+		case "OnDemand":
+			onDemand = rate
+			onDemandFound = true
+		case "Preemptible":
+			spot = &rate
+		}
+	}
+	if !onDemandFound {
+		return 0, nil, nil, nil, fmt.Errorf("no on-demand SKU found for %s in %s", instanceType, region)
+	}
+	// GCP has no fixed-term reserved-instance pricing analogous to AWS RIs;
+	// committed-use discounts are negotiated separately and aren't exposed
+	// per-SKU, so res1yr/res3yr are always nil here.
+	return onDemand, nil, nil, spot, nil
+}
+
+// loadSKUs pages through every SKU under gcpComputeServiceID.
+func (p *gcpProvider) loadSKUs(ctx context.Context) ([]gcpSKU, error) {
+	var all []gcpSKU
+	pageToken := ""
+	for {
+		q := url.Values{"key": {p.apiKey}, "pageSize": {"5000"}}
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+		reqURL := fmt.Sprintf("%s/services/%s/skus?%s", gcpCatalogBaseURL, gcpComputeServiceID, q.Encode())
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := p.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var page gcpSKUPage
+		decErr := json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("list skus: status %d", resp.StatusCode)
+		}
+		if decErr != nil {
+			return nil, fmt.Errorf("decode skus: %w", decErr)
+		}
+
+		all = append(all, page.SKUs...)
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	return all, nil
+}
+
+type gcpSKUPage struct {
+	SKUs          []gcpSKU `json:"skus"`
+	NextPageToken string   `json:"nextPageToken"`
+}
+
+type gcpSKU struct {
+	Description    string   `json:"description"`
+	ServiceRegions []string `json:"serviceRegions"`
+	Category       struct {
+		UsageType string `json:"usageType"` // "OnDemand", "Preemptible", "Commit1Yr", "Commit3Yr"
+	} `json:"category"`
+	PricingInfo []struct {
+		PricingExpression struct {
+			UsageUnit   string `json:"usageUnit"` // "h" for hourly
+			TieredRates []struct {
+				UnitPrice struct {
+					Units        string `json:"units"`
+					Nanos        int64  `json:"nanos"`
+					CurrencyCode string `json:"currencyCode"`
+				} `json:"unitPrice"`
+			} `json:"tieredRates"`
+		} `json:"pricingExpression"`
+	} `json:"pricingInfo"`
+}
+
+func (s gcpSKU) servesRegion(region string) bool {
+	for _, r := range s.ServiceRegions {
+		if r == region || r == "global" {
+			return true
+		}
+	}
+	return false
+}
+
+// hourlyUSD returns the SKU's hourly USD rate from its last (highest-tier)
+// pricing entry, the one that applies once any free tier is exhausted.
+func (s gcpSKU) hourlyUSD() (float64, bool) {
+	if len(s.PricingInfo) == 0 {
+		return 0, false
+	}
+	expr := s.PricingInfo[len(s.PricingInfo)-1].PricingExpression
+	if expr.UsageUnit != "h" || len(expr.TieredRates) == 0 {
+		return 0, false
+	}
+	unit := expr.TieredRates[len(expr.TieredRates)-1].UnitPrice
+	if unit.CurrencyCode != "USD" {
+		return 0, false
+	}
+	units, err := strconv.ParseFloat(unit.Units, 64)
+	if err != nil {
+		return 0, false
+	}
+	return units + float64(unit.Nanos)/1e9, true
+}
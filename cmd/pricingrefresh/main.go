@@ -2,22 +2,24 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"flag"
 	"log"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
-	awsconfig "github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/pricing"
-	pricingtypes "github.com/aws/aws-sdk-go-v2/service/pricing/types"
-
 	"github.com/accelbench/accelbench/internal/database"
 )
 
+// priceRegressionThresholdPct is how far an on-demand rate must move
+// between refreshes, in either direction, before --detect-regressions
+// logs it.
+const priceRegressionThresholdPct = 5.0
+
 func main() {
+	detectRegressions := flag.Bool("detect-regressions", false, "log a PRICE_REGRESSION line whenever an on-demand rate moves more than 5% since the last refresh")
+	flag.Parse()
+
 	ctx := context.Background()
 
 	dbURL := os.Getenv("DATABASE_URL")
@@ -25,10 +27,7 @@ func main() {
 		log.Fatal("DATABASE_URL is required")
 	}
 
-	regions := strings.Split(getEnv("PRICING_REGIONS", "us-east-2"), ",")
-	for i := range regions {
-		regions[i] = strings.TrimSpace(regions[i])
-	}
+	regions := splitCSV(getEnv("PRICING_REGIONS", "us-east-2"))
 
 	repo, err := database.NewRepository(ctx, dbURL)
 	if err != nil {
@@ -36,12 +35,10 @@ func main() {
 	}
 	defer repo.Close()
 
-	// AWS Pricing API is only available in us-east-1.
-	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion("us-east-1"))
+	providers, err := buildProviders(ctx)
 	if err != nil {
-		log.Fatalf("load AWS config: %v", err)
+		log.Fatalf("build pricing providers: %v", err)
 	}
-	client := pricing.NewFromConfig(cfg)
 
 	instanceTypes, err := repo.ListInstanceTypes(ctx)
 	if err != nil {
@@ -52,25 +49,46 @@ func main() {
 	today := time.Now().Format("2006-01-02")
 
 	for _, region := range regions {
+		var previous map[string]float64
+		if *detectRegressions {
+			previous = previousOnDemandRates(ctx, repo, region)
+		}
+
 		var updated int
 		for _, it := range instanceTypes {
-			onDemand, res1yr, res3yr, err := fetchPricing(ctx, client, it.Name, region)
+			cloud := cloudProviderFor(it)
+			provider, ok := providers[cloud]
+			if !ok {
+				continue
+			}
+
+			onDemand, res1yr, res3yr, spot, err := provider.FetchPricing(ctx, it.Name, region)
 			if err != nil {
-				log.Printf("WARN: %s in %s: %v", it.Name, region, err)
+				log.Printf("WARN: %s (%s) in %s: %v", it.Name, cloud, region, err)
 				time.Sleep(200 * time.Millisecond)
 				continue
 			}
 
+			if prev, ok := previous[it.Name+"|"+cloud]; ok && prev > 0 {
+				deltaPct := (onDemand - prev) / prev * 100
+				if deltaPct > priceRegressionThresholdPct || deltaPct < -priceRegressionThresholdPct {
+					log.Printf("PRICE_REGRESSION instance_type=%s cloud=%s region=%s previous_usd=%.4f current_usd=%.4f delta_pct=%.2f",
+						it.Name, cloud, region, prev, onDemand, deltaPct)
+				}
+			}
+
 			p := &database.Pricing{
 				InstanceTypeID:       it.ID,
+				CloudProvider:        cloud,
 				Region:               region,
 				OnDemandHourlyUSD:    onDemand,
 				Reserved1YrHourlyUSD: res1yr,
 				Reserved3YrHourlyUSD: res3yr,
+				SpotHourlyUSD:        spot,
 				EffectiveDate:        today,
 			}
 			if err := repo.UpsertPricing(ctx, p); err != nil {
-				log.Printf("WARN: upsert %s in %s: %v", it.Name, region, err)
+				log.Printf("WARN: upsert %s (%s) in %s: %v", it.Name, cloud, region, err)
 			} else {
 				updated++
 			}
@@ -82,125 +100,92 @@ func main() {
 	log.Printf("Pricing refresh complete for regions: %s", strings.Join(regions, ", "))
 }
 
-func getEnv(key, fallback string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
-	}
-	return fallback
-}
+// buildProviders constructs every Provider this refresher knows about,
+// keyed by the cloudXxx constant cloudProviderFor resolves instance
+// types to. A provider that fails to initialize (e.g. a missing API key)
+// is logged and omitted rather than aborting the whole run, so the
+// remaining clouds still get refreshed.
+func buildProviders(ctx context.Context) (map[string]Provider, error) {
+	providers := make(map[string]Provider)
 
-// fetchPricing calls the AWS Pricing API for a single instance type and region,
-// returning on-demand hourly, 1yr RI (All Upfront), and 3yr RI (All Upfront) rates.
-func fetchPricing(ctx context.Context, client *pricing.Client, instanceType, region string) (onDemand float64, res1yr, res3yr *float64, err error) {
-	input := &pricing.GetProductsInput{
-		ServiceCode: strPtr("AmazonEC2"),
-		Filters: []pricingtypes.Filter{
-			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("instanceType"), Value: strPtr(instanceType)},
-			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("operatingSystem"), Value: strPtr("Linux")},
-			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("tenancy"), Value: strPtr("Shared")},
-			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("preInstalledSw"), Value: strPtr("NA")},
-			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("capacitystatus"), Value: strPtr("Used")},
-			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("regionCode"), Value: strPtr(region)},
-		},
-		MaxResults: int32Ptr(10),
-	}
-
-	resp, err := client.GetProducts(ctx, input)
+	aws, err := newAWSProvider(ctx)
 	if err != nil {
-		return 0, nil, nil, fmt.Errorf("GetProducts: %w", err)
-	}
-	if len(resp.PriceList) == 0 {
-		return 0, nil, nil, fmt.Errorf("no pricing found for %s in %s", instanceType, region)
+		log.Printf("WARN: AWS provider unavailable: %v", err)
+	} else {
+		providers[cloudAWS] = aws
 	}
 
-	// Parse the first price list entry.
-	var product priceDoc
-	if err := json.Unmarshal([]byte(resp.PriceList[0]), &product); err != nil {
-		return 0, nil, nil, fmt.Errorf("parse price list: %w", err)
-	}
-
-	// Extract on-demand price.
-	onDemand, err = extractOnDemand(product.Terms.OnDemand)
-	if err != nil {
-		return 0, nil, nil, fmt.Errorf("on-demand: %w", err)
+	if apiKey := os.Getenv("GOOGLE_CLOUD_BILLING_API_KEY"); apiKey != "" {
+		gcp, err := newGCPProvider(apiKey)
+		if err != nil {
+			log.Printf("WARN: GCP provider unavailable: %v", err)
+		} else {
+			providers[cloudGCP] = gcp
+		}
 	}
 
-	// Extract reserved prices.
-	res1yr = extractReserved(product.Terms.Reserved, "1yr")
-	res3yr = extractReserved(product.Terms.Reserved, "3yr")
+	providers[cloudAzure] = newAzureProvider()
 
-	return onDemand, res1yr, res3yr, nil
-}
+	if path := os.Getenv("ON_PREM_RATES_FILE"); path != "" {
+		onPrem, err := newStaticProvider(path)
+		if err != nil {
+			log.Printf("WARN: on-prem provider unavailable: %v", err)
+		} else {
+			providers[cloudOnPrem] = onPrem
+		}
+	}
 
-// priceDoc represents the relevant structure of an AWS Pricing API response entry.
-type priceDoc struct {
-	Terms struct {
-		OnDemand map[string]termEntry `json:"OnDemand"`
-		Reserved map[string]termEntry `json:"Reserved"`
-	} `json:"terms"`
+	return providers, nil
 }
 
-type termEntry struct {
-	PriceDimensions map[string]priceDimension `json:"priceDimensions"`
-	TermAttributes  map[string]string         `json:"termAttributes"`
+// previousOnDemandRates loads the on-demand rate recorded for each
+// (instance type, cloud provider) pair in region before this refresh
+// overwrites it, keyed the same way cloudProviderFor's result is joined
+// against FetchPricing's result below. A failed lookup just disables
+// regression detection for this region's run rather than aborting it.
+func previousOnDemandRates(ctx context.Context, repo *database.Repository, region string) map[string]float64 {
+	rows, err := repo.ListPricing(ctx, region)
+	if err != nil {
+		log.Printf("WARN: could not load prior pricing for regression detection in %s: %v", region, err)
+		return nil
+	}
+	rates := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		rates[row.InstanceTypeName+"|"+row.CloudProvider] = row.OnDemandHourlyUSD
+	}
+	return rates
 }
 
-type priceDimension struct {
-	Unit         string            `json:"unit"`
-	PricePerUnit map[string]string `json:"pricePerUnit"`
+// cloudProviderFor classifies an instance type by its family prefix:
+// "a3"/"a2" are GCP Accelerator-Optimized VMs, "nd" is Azure's
+// GPU-accelerated ND-series, "on-prem" is colocated hardware entered by
+// hand, and everything else is assumed to be an AWS EC2 instance type
+// (this refresher's original and still most common case).
+func cloudProviderFor(it database.InstanceType) string {
+	family := strings.ToLower(it.Family)
+	switch {
+	case strings.HasPrefix(family, "a3"), strings.HasPrefix(family, "a2"):
+		return cloudGCP
+	case strings.HasPrefix(family, "nd"):
+		return cloudAzure
+	case family == "on-prem":
+		return cloudOnPrem
+	default:
+		return cloudAWS
+	}
 }
 
-func extractOnDemand(terms map[string]termEntry) (float64, error) {
-	for _, term := range terms {
-		for _, pd := range term.PriceDimensions {
-			if pd.Unit == "Hrs" {
-				usd, ok := pd.PricePerUnit["USD"]
-				if !ok {
-					continue
-				}
-				return strconv.ParseFloat(usd, 64)
-			}
-		}
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
 	}
-	return 0, fmt.Errorf("no hourly on-demand price found")
+	return fallback
 }
 
-// extractReserved finds the All Upfront, Standard reserved price for the given
-// lease length ("1yr" or "3yr") and returns the effective hourly rate.
-func extractReserved(terms map[string]termEntry, lease string) *float64 {
-	for _, term := range terms {
-		attrs := term.TermAttributes
-		if attrs["LeaseContractLength"] != lease ||
-			attrs["PurchaseOption"] != "All Upfront" ||
-			attrs["OfferingClass"] != "standard" {
-			continue
-		}
-
-		// Find the upfront fee (unit = "Quantity").
-		for _, pd := range term.PriceDimensions {
-			if pd.Unit == "Quantity" {
-				usd, ok := pd.PricePerUnit["USD"]
-				if !ok {
-					continue
-				}
-				upfront, err := strconv.ParseFloat(usd, 64)
-				if err != nil || upfront <= 0 {
-					continue
-				}
-				var hours float64
-				switch lease {
-				case "1yr":
-					hours = 8760
-				case "3yr":
-					hours = 26280
-				}
-				hourly := upfront / hours
-				return &hourly
-			}
-		}
+func splitCSV(s string) []string {
+	parts := strings.Split(s, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
 	}
-	return nil
+	return parts
 }
-
-func strPtr(s string) *string   { return &s }
-func int32Ptr(i int32) *int32   { return &i }
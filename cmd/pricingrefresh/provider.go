@@ -0,0 +1,21 @@
+package main
+
+import "context"
+
+// Provider resolves hourly pricing for one instance type in one region
+// from a single source: a specific cloud's pricing API, or a static table
+// for on-prem/colocated hardware. res1yr, res3yr, and spot are nil
+// wherever that purchase option doesn't exist or isn't priced for the
+// instance type, so a provider that only knows on-demand rates (e.g.
+// static) can still be upserted with the fields it has.
+type Provider interface {
+	FetchPricing(ctx context.Context, instanceType, region string) (onDemand float64, res1yr, res3yr, spot *float64, err error)
+}
+
+// Cloud provider identifiers, persisted verbatim as Pricing.CloudProvider.
+const (
+	cloudAWS    = "aws"
+	cloudGCP    = "gcp"
+	cloudAzure  = "azure"
+	cloudOnPrem = "on-prem"
+)
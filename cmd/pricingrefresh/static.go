@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// onPremRatesDoc is the YAML shape of the ON_PREM_RATES_FILE file: an
+// amortized hourly cost per instance type, standing in for a cloud
+// on-demand rate so on-prem hardware can be compared on the same
+// cost-per-token basis.
+type onPremRatesDoc struct {
+	Rates []struct {
+		InstanceType      string  `yaml:"instance_type"`
+		OnDemandHourlyUSD float64 `yaml:"on_demand_hourly_usd"`
+	} `yaml:"rates"`
+}
+
+// staticProvider resolves on-prem/colocated hardware rates from a
+// user-supplied YAML table. There's no cloud API to query for hardware
+// you own, so the operator amortizes purchase price, power, and hosting
+// into an hourly figure and lists it here.
+type staticProvider struct {
+	rates map[string]float64
+}
+
+// newStaticProvider loads on-prem rates from path.
+func newStaticProvider(path string) (*staticProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read on-prem rates file %q: %w", path, err)
+	}
+
+	var doc onPremRatesDoc
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse on-prem rates file: %w", err)
+	}
+
+	rates := make(map[string]float64, len(doc.Rates))
+	for _, r := range doc.Rates {
+		rates[r.InstanceType] = r.OnDemandHourlyUSD
+	}
+	return &staticProvider{rates: rates}, nil
+}
+
+// FetchPricing implements Provider. On-prem hardware has no reserved or
+// spot pricing tiers, so res1yr, res3yr, and spot are always nil. region
+// is ignored: a given piece of on-prem hardware lives in exactly one
+// place, so PRICING_REGIONS should list a single placeholder (e.g. "on-prem")
+// for it.
+func (p *staticProvider) FetchPricing(_ context.Context, instanceType, _ string) (onDemand float64, res1yr, res3yr, spot *float64, err error) {
+	usd, ok := p.rates[instanceType]
+	if !ok {
+		return 0, nil, nil, nil, fmt.Errorf("no on-prem rate configured for %s", instanceType)
+	}
+	return usd, nil, nil, nil, nil
+}
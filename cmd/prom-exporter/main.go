@@ -0,0 +1,126 @@
+// Command prom-exporter backfills a Prometheus remote_write endpoint from
+// the full history of completed benchmark runs in a database.Repo, so a
+// freshly stood-up Grafana dashboard doesn't have to wait for new runs to
+// trickle in through the server's own internal/remotewrite.Pusher. It
+// pages through ListCatalog (bounded by --page-size) rather than loading
+// every run at once, so a large catalog doesn't blow up memory.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/remotewrite"
+)
+
+// defaultPricingRegion is the region priced for the cost_per_million_tokens_usd
+// sample, matching the server's own default.
+const defaultPricingRegion = "us-east-2"
+
+func main() {
+	var (
+		store             = flag.String("store", "", "store to read from: bolt=<path> or etcd=<endpoint1,endpoint2,...>")
+		remoteWriteURL    = flag.String("remote-write-url", "", "Prometheus remote_write endpoint to push results to")
+		remoteWriteAuth   = flag.String("remote-write-auth", "", "bearer token for the remote_write endpoint")
+		remoteWriteTenant = flag.String("remote-write-tenant", "", "X-Scope-OrgID tenant header for the remote_write endpoint")
+		pricingRegion     = flag.String("pricing-region", defaultPricingRegion, "region to price cost_per_million_tokens_usd from")
+		pageSize          = flag.Int("page-size", 500, "runs fetched per ListCatalog page")
+	)
+	flag.Parse()
+
+	if *store == "" || *remoteWriteURL == "" {
+		log.Fatal("both -store and -remote-write-url are required, e.g. -store=bolt=./accelbench.db -remote-write-url=http://mimir:9009/api/v1/push")
+	}
+
+	ctx := context.Background()
+
+	repo, closeRepo, err := openRepo(*store)
+	if err != nil {
+		log.Fatalf("open store %q: %v", *store, err)
+	}
+	defer closeRepo()
+
+	pricing, err := repo.ListPricing(ctx, *pricingRegion)
+	if err != nil {
+		log.Fatalf("list pricing: %v", err)
+	}
+	hourlyRate := make(map[string]float64, len(pricing))
+	for _, p := range pricing {
+		hourlyRate[p.InstanceTypeName] = p.OnDemandHourlyUSD
+	}
+
+	var total int
+	for offset := 0; ; offset += *pageSize {
+		entries, _, err := repo.ListCatalog(ctx, database.CatalogFilter{
+			SortBy: "completed_at",
+			Limit:  *pageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			log.Fatalf("list catalog (offset %d): %v", offset, err)
+		}
+		if len(entries) == 0 {
+			break
+		}
+
+		samples := remotewrite.CatalogSamples(entries, hourlyRate)
+		if err := remotewrite.Push(ctx, *remoteWriteURL, *remoteWriteAuth, *remoteWriteTenant, samples); err != nil {
+			log.Fatalf("push page at offset %d: %v", offset, err)
+		}
+		total += len(entries)
+		log.Printf("Pushed %d run(s) (%d sample(s)) at offset %d", len(entries), len(samples), offset)
+
+		if len(entries) < *pageSize {
+			break
+		}
+	}
+	log.Printf("Backfill complete: %d run(s) pushed to %s", total, *remoteWriteURL)
+}
+
+func openRepo(spec string) (database.Repo, func(), error) {
+	switch driver, arg := splitSpec(spec); driver {
+	case "bolt":
+		repo, err := database.NewBoltRepo(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return repo, func() { repo.Close() }, nil
+	case "etcd":
+		repo, err := database.NewEtcdRepo(splitEndpoints(arg))
+		if err != nil {
+			return nil, nil, err
+		}
+		return repo, func() { repo.Close() }, nil
+	case "memory":
+		return database.NewMockRepo(), func() {}, nil
+	default:
+		return nil, nil, errUnknownDriver(driver)
+	}
+}
+
+// splitSpec splits a "-store" value of the form "driver=arg" (e.g.
+// "bolt=./accelbench.db" or "etcd=localhost:2379") into its driver and
+// argument.
+func splitSpec(spec string) (driver, arg string) {
+	parts := strings.SplitN(spec, "=", 2)
+	if len(parts) != 2 {
+		return spec, ""
+	}
+	return parts[0], parts[1]
+}
+
+func splitEndpoints(arg string) []string {
+	endpoints := strings.Split(arg, ",")
+	for i := range endpoints {
+		endpoints[i] = strings.TrimSpace(endpoints[i])
+	}
+	return endpoints
+}
+
+func errUnknownDriver(driver string) error {
+	return fmt.Errorf("unknown store driver %q: want bolt, etcd, or memory", driver)
+}
@@ -6,9 +6,12 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/accelbench/accelbench/internal/api"
-	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/pricing"
+	"github.com/accelbench/accelbench/internal/remotewrite"
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -20,18 +23,13 @@ func main() {
 		port = "8080"
 	}
 
-	dbURL := os.Getenv("DATABASE_URL")
-	if dbURL == "" {
-		log.Fatal("DATABASE_URL is required")
-	}
-
 	ctx := context.Background()
 
-	repo, err := database.NewRepository(ctx, dbURL)
+	repo, closeRepo, err := openStore(ctx)
 	if err != nil {
-		log.Fatalf("connect to database: %v", err)
+		log.Fatalf("open store: %v", err)
 	}
-	defer repo.Close()
+	defer closeRepo()
 
 	k8sCfg, err := rest.InClusterConfig()
 	if err != nil {
@@ -42,7 +40,29 @@ func main() {
 		log.Fatalf("create kubernetes client: %v", err)
 	}
 
-	srv := api.NewServer(repo, k8sClient)
+	var serverOpts []api.ServerOption
+	if cnList := os.Getenv("ADMIN_ALLOWED_CNS"); cnList != "" {
+		serverOpts = append(serverOpts, api.WithAuthzPolicy(api.NewCNAuthzPolicy(strings.Split(cnList, ",")...)))
+	}
+	if maxTimeout := os.Getenv("MAX_RUN_TIMEOUT_SECONDS"); maxTimeout != "" {
+		n, err := strconv.Atoi(maxTimeout)
+		if err != nil {
+			log.Fatalf("parse MAX_RUN_TIMEOUT_SECONDS: %v", err)
+		}
+		serverOpts = append(serverOpts, api.WithMaxRunTimeoutSeconds(n))
+	}
+	if provider, source, err := newPricingProvider(ctx); err != nil {
+		log.Fatalf("configure pricing provider: %v", err)
+	} else if provider != nil {
+		serverOpts = append(serverOpts, api.WithPricingProvider(source, provider))
+	}
+	srv := api.NewServer(repo, k8sClient, serverOpts...)
+
+	if remoteWriteURL := os.Getenv("REMOTE_WRITE_URL"); remoteWriteURL != "" {
+		pusher := remotewrite.NewPusher(repo, remoteWriteURL, os.Getenv("REMOTE_WRITE_AUTH"), os.Getenv("REMOTE_WRITE_TENANT"))
+		go pusher.Run(ctx)
+		log.Printf("Streaming completed runs to remote_write endpoint %s", remoteWriteURL)
+	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
@@ -50,9 +70,67 @@ func main() {
 		fmt.Fprintln(w, "ok")
 	})
 	srv.RegisterRoutes(mux)
+	handler := api.WithRequestID(mux)
+
+	if socketPath := os.Getenv("ADMIN_UNIX_SOCKET"); socketPath != "" {
+		mode := os.FileMode(0600)
+		if m := os.Getenv("ADMIN_UNIX_SOCKET_MODE"); m != "" {
+			if parsed, err := strconv.ParseUint(m, 8, 32); err == nil {
+				mode = os.FileMode(parsed)
+			}
+		}
+		ln, err := api.ListenUnix(socketPath, mode)
+		if err != nil {
+			log.Fatalf("listen on unix socket %s: %v", socketPath, err)
+		}
+		go func() {
+			log.Printf("AccelBench API server additionally listening on unix socket %s", socketPath)
+			if err := http.Serve(ln, handler); err != nil {
+				log.Printf("unix socket listener stopped: %v", err)
+			}
+		}()
+	}
+
+	if mtlsAddr := os.Getenv("ADMIN_MTLS_ADDR"); mtlsAddr != "" {
+		ln, err := api.ListenMTLS(mtlsAddr,
+			os.Getenv("ADMIN_MTLS_CERT"), os.Getenv("ADMIN_MTLS_KEY"), os.Getenv("ADMIN_MTLS_CLIENT_CA"))
+		if err != nil {
+			log.Fatalf("listen on mTLS address %s: %v", mtlsAddr, err)
+		}
+		go func() {
+			log.Printf("AccelBench API server additionally listening with mTLS on %s", mtlsAddr)
+			if err := http.Serve(ln, handler); err != nil {
+				log.Printf("mTLS listener stopped: %v", err)
+			}
+		}()
+	}
 
 	log.Printf("AccelBench API server starting on :%s", port)
-	if err := http.ListenAndServe(":"+port, mux); err != nil {
+	if err := http.ListenAndServe(":"+port, handler); err != nil {
 		log.Fatalf("server failed: %v", err)
 	}
 }
+
+// newPricingProvider builds the pricing.Provider the orchestrator uses to
+// stamp a cost efficiency snapshot onto each run's BenchmarkMetrics, mirroring
+// the CLI's --pricing-provider flag: PRICING_PROVIDER selects "static" (the
+// default, an embedded or PRICING_FILE-supplied YAML rate table) or "aws"
+// (the live AWS Pricing API, scoped to PRICING_REGION). Returns a nil
+// provider when PRICING_PROVIDER is unset, so a server with no pricing
+// configuration leaves the cost columns unpopulated rather than failing to
+// start.
+func newPricingProvider(ctx context.Context) (pricing.Provider, string, error) {
+	providerKind := os.Getenv("PRICING_PROVIDER")
+	switch providerKind {
+	case "":
+		return nil, "", nil
+	case "static":
+		p, err := pricing.NewStaticProvider(os.Getenv("PRICING_FILE"))
+		return p, providerKind, err
+	case "aws":
+		p, err := pricing.NewAWSProvider(ctx, os.Getenv("PRICING_REGION"))
+		return p, providerKind, err
+	default:
+		return nil, "", fmt.Errorf("unknown PRICING_PROVIDER %q (want \"static\" or \"aws\")", providerKind)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// openStore builds the database.Repo selected by STORE_DRIVER
+// (bolt|etcd|memory|postgres, default postgres) and returns a close func
+// to release it on shutdown. postgres keeps reading DATABASE_URL exactly
+// as before; the new drivers are meant for deployments that don't want to
+// stand up Postgres just to hold benchmark state.
+func openStore(ctx context.Context) (database.Repo, func(), error) {
+	driver := strings.ToLower(os.Getenv("STORE_DRIVER"))
+	if driver == "" {
+		driver = "postgres"
+	}
+
+	switch driver {
+	case "postgres":
+		dbURL := os.Getenv("DATABASE_URL")
+		if dbURL == "" {
+			return nil, nil, fmt.Errorf("DATABASE_URL is required when STORE_DRIVER=postgres")
+		}
+		repo, err := database.NewRepository(ctx, dbURL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("connect to database: %w", err)
+		}
+		return repo, func() { repo.Close() }, nil
+
+	case "bolt":
+		path := os.Getenv("STORE_BOLT_PATH")
+		if path == "" {
+			path = "accelbench.db"
+		}
+		repo, err := database.NewBoltRepo(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open bolt store at %s: %w", path, err)
+		}
+		return repo, func() { repo.Close() }, nil
+
+	case "etcd":
+		endpointList := os.Getenv("STORE_ETCD_ENDPOINTS")
+		if endpointList == "" {
+			return nil, nil, fmt.Errorf("STORE_ETCD_ENDPOINTS is required when STORE_DRIVER=etcd")
+		}
+		endpoints := strings.Split(endpointList, ",")
+		for i := range endpoints {
+			endpoints[i] = strings.TrimSpace(endpoints[i])
+		}
+		repo, err := database.NewEtcdRepo(endpoints)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open etcd store at %s: %w", endpointList, err)
+		}
+		return repo, func() { repo.Close() }, nil
+
+	case "memory":
+		return database.NewMockRepo(), func() {}, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown STORE_DRIVER %q: want postgres, bolt, etcd, or memory", driver)
+	}
+}
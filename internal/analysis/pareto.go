@@ -0,0 +1,49 @@
+package analysis
+
+// ParetoPoint is one candidate in a cost/performance trade-off space: a
+// benchmark run identified by Label (typically its run ID), scored on
+// throughput (maximize), hourly cost (minimize), and p50 latency
+// (minimize).
+type ParetoPoint struct {
+	Label         string  `json:"label"`
+	ThroughputTPS float64 `json:"throughput_tps"`
+	CostHourlyUSD float64 `json:"cost_hourly_usd"`
+	LatencyP50Ms  float64 `json:"latency_p50_ms"`
+}
+
+// dominates reports whether a is at least as good as b on every axis and
+// strictly better on at least one, i.e. b is never the preferable choice
+// once a is available.
+func (a ParetoPoint) dominates(b ParetoPoint) bool {
+	betterOrEqual := a.ThroughputTPS >= b.ThroughputTPS &&
+		a.CostHourlyUSD <= b.CostHourlyUSD &&
+		a.LatencyP50Ms <= b.LatencyP50Ms
+	strictlyBetter := a.ThroughputTPS > b.ThroughputTPS ||
+		a.CostHourlyUSD < b.CostHourlyUSD ||
+		a.LatencyP50Ms < b.LatencyP50Ms
+	return betterOrEqual && strictlyBetter
+}
+
+// ParetoFrontier returns the subset of points not dominated by any other
+// point, preserving input order. Used to surface the sweep results worth
+// looking at instead of making the caller eyeball a full matrix of
+// throughput/cost/latency trade-offs.
+func ParetoFrontier(points []ParetoPoint) []ParetoPoint {
+	var frontier []ParetoPoint
+	for i, p := range points {
+		dominated := false
+		for j, q := range points {
+			if i == j {
+				continue
+			}
+			if q.dominates(p) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			frontier = append(frontier, p)
+		}
+	}
+	return frontier
+}
@@ -0,0 +1,51 @@
+package analysis
+
+import "testing"
+
+func labels(points []ParetoPoint) map[string]bool {
+	set := make(map[string]bool, len(points))
+	for _, p := range points {
+		set[p.Label] = true
+	}
+	return set
+}
+
+func TestParetoFrontier_DropsDominatedPoint(t *testing.T) {
+	points := []ParetoPoint{
+		{Label: "a", ThroughputTPS: 100, CostHourlyUSD: 10, LatencyP50Ms: 50},
+		{Label: "b", ThroughputTPS: 80, CostHourlyUSD: 12, LatencyP50Ms: 60}, // worse on every axis than a
+		{Label: "c", ThroughputTPS: 150, CostHourlyUSD: 20, LatencyP50Ms: 40},
+	}
+
+	got := labels(ParetoFrontier(points))
+	if got["b"] {
+		t.Error("b is dominated by a on every axis, should not be on the frontier")
+	}
+	if !got["a"] || !got["c"] {
+		t.Errorf("a and c trade off against each other, both should be on the frontier, got %v", got)
+	}
+}
+
+func TestParetoFrontier_NoDominationKeepsAll(t *testing.T) {
+	points := []ParetoPoint{
+		{Label: "cheap", ThroughputTPS: 50, CostHourlyUSD: 5, LatencyP50Ms: 80},
+		{Label: "fast", ThroughputTPS: 200, CostHourlyUSD: 30, LatencyP50Ms: 20},
+	}
+
+	got := ParetoFrontier(points)
+	if len(got) != 2 {
+		t.Errorf("len = %d, want 2 (neither point dominates the other)", len(got))
+	}
+}
+
+func TestParetoFrontier_EqualPointsBothSurvive(t *testing.T) {
+	points := []ParetoPoint{
+		{Label: "x", ThroughputTPS: 100, CostHourlyUSD: 10, LatencyP50Ms: 50},
+		{Label: "y", ThroughputTPS: 100, CostHourlyUSD: 10, LatencyP50Ms: 50},
+	}
+
+	got := ParetoFrontier(points)
+	if len(got) != 2 {
+		t.Errorf("len = %d, want 2 (identical points don't strictly dominate one another)", len(got))
+	}
+}
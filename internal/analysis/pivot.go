@@ -0,0 +1,70 @@
+package analysis
+
+import "sort"
+
+// PivotCell is one (row, column) intersection of a sweep report: the
+// throughput/latency/cost figures for whichever run landed in that cell,
+// or a zero value if no run covers that combination yet.
+type PivotCell struct {
+	RunID         string   `json:"run_id,omitempty"`
+	ThroughputTPS *float64 `json:"throughput_tps,omitempty"`
+	TTFTP50Ms     *float64 `json:"ttft_p50_ms,omitempty"`
+	CostHourlyUSD *float64 `json:"cost_hourly_usd,omitempty"`
+}
+
+// PivotPoint is a single run's contribution to a PivotTable: its row key
+// (e.g. model x quantization), column key (e.g. instance type x tensor
+// parallelism), and the metrics to place in that cell.
+type PivotPoint struct {
+	Row  string
+	Col  string
+	Cell PivotCell
+}
+
+// PivotTable is a sweep report rendered as rows x columns of PivotCells,
+// with Rows/Cols giving the stable, sorted axis labels so a renderer
+// doesn't need to re-derive them from the sparse Cells map.
+type PivotTable struct {
+	Rows  []string             `json:"rows"`
+	Cols  []string             `json:"cols"`
+	Cells map[string]PivotCell `json:"cells"`
+}
+
+// cellKey joins a row and column label into PivotTable.Cells' map key.
+func cellKey(row, col string) string { return row + "\x00" + col }
+
+// Pivot arranges points into a PivotTable, so a sweep with N models x
+// quantizations and M instance types x TP degrees reads as an N x M grid
+// of TPS/TTFT/cost instead of a flat list of runs the caller must
+// cross-reference by hand. A (row, col) pair seen more than once keeps
+// its last point, since a well-formed sweep has at most one run per cell.
+func Pivot(points []PivotPoint) PivotTable {
+	rowSet := make(map[string]struct{})
+	colSet := make(map[string]struct{})
+	cells := make(map[string]PivotCell, len(points))
+	for _, p := range points {
+		rowSet[p.Row] = struct{}{}
+		colSet[p.Col] = struct{}{}
+		cells[cellKey(p.Row, p.Col)] = p.Cell
+	}
+
+	rows := make([]string, 0, len(rowSet))
+	for row := range rowSet {
+		rows = append(rows, row)
+	}
+	sort.Strings(rows)
+
+	cols := make([]string, 0, len(colSet))
+	for col := range colSet {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	return PivotTable{Rows: rows, Cols: cols, Cells: cells}
+}
+
+// Cell returns the cell at (row, col), or the zero PivotCell if nothing
+// landed there.
+func (t PivotTable) Cell(row, col string) PivotCell {
+	return t.Cells[cellKey(row, col)]
+}
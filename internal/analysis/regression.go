@@ -0,0 +1,274 @@
+// Package analysis compares a candidate benchmark run against a baseline
+// run, so the `compare` CLI command can flag statistically meaningful
+// regressions instead of just printing two numbers side by side.
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// significanceAlpha is the threshold below which a p-value is considered
+// statistically significant.
+const significanceAlpha = 0.05
+
+// Delta is the statistical comparison of one metric between a baseline and
+// candidate sample set.
+type Delta struct {
+	Metric        string  `json:"metric"`
+	BaselineMean  float64 `json:"baseline_mean"`
+	CandidateMean float64 `json:"candidate_mean"`
+	DeltaPct      float64 `json:"delta_pct"`
+	PValue        float64 `json:"p_value"`
+	CILow         float64 `json:"ci_low"`
+	CIHigh        float64 `json:"ci_high"`
+	Significant   bool    `json:"significant"`
+}
+
+// Compare reports the percent change of candidate vs baseline for one
+// metric, along with a Welch's t-test and a Mann-Whitney U test between the
+// two sample sets. PValue is the larger of the two tests' p-values, so the
+// metric is flagged significant only if both tests reject the null
+// hypothesis at α=0.05 — Welch's test is sensitive to differences in
+// means, Mann-Whitney to differences in distribution shape.
+//
+// When only a single observation is available per side (the common case
+// today, since the metrics endpoint reports pre-aggregated percentiles
+// rather than raw per-request samples), there isn't enough data to estimate
+// variance, so PValue is reported as 1 and Significant as false regardless
+// of threshold — the point-estimate DeltaPct is still meaningful and is what
+// --regression-threshold gates on.
+func Compare(metric string, baseline, candidate []float64) Delta {
+	d := Delta{
+		Metric:        metric,
+		BaselineMean:  mean(baseline),
+		CandidateMean: mean(candidate),
+	}
+	if d.BaselineMean != 0 {
+		d.DeltaPct = (d.CandidateMean - d.BaselineMean) / math.Abs(d.BaselineMean) * 100
+	}
+
+	if len(baseline) < 2 || len(candidate) < 2 {
+		d.PValue = 1
+		d.CILow, d.CIHigh = d.DeltaPct, d.DeltaPct
+		return d
+	}
+
+	tP := welchTTest(baseline, candidate)
+	_, uP := mannWhitneyU(baseline, candidate)
+
+	// Report the more conservative (larger) p-value of the two tests.
+	d.PValue = math.Max(tP, uP)
+	d.Significant = d.PValue < significanceAlpha
+
+	lo, hi := welchCI(baseline, candidate)
+	d.CILow, d.CIHigh = lo, hi
+
+	return d
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+func variance(xs []float64, m float64) float64 {
+	if len(xs) < 2 {
+		return 0
+	}
+	var sumSq float64
+	for _, x := range xs {
+		d := x - m
+		sumSq += d * d
+	}
+	return sumSq / float64(len(xs)-1)
+}
+
+// welchTTest returns the two-tailed p-value for Welch's t-test between two
+// independent samples with possibly unequal variances.
+func welchTTest(a, b []float64) float64 {
+	ma, mb := mean(a), mean(b)
+	va, vb := variance(a, ma), variance(b, mb)
+	na, nb := float64(len(a)), float64(len(b))
+
+	se := math.Sqrt(va/na + vb/nb)
+	if se == 0 {
+		if ma == mb {
+			return 1
+		}
+		return 0
+	}
+	t := (ma - mb) / se
+
+	// Large-sample normal approximation to the t-distribution; adequate for
+	// the request-count sample sizes seen in benchmark runs and avoids
+	// pulling in an incomplete-beta implementation for an exact Student's t.
+	return twoTailedNormalP(t)
+}
+
+// welchCI returns the 95% confidence interval for the difference in means
+// (candidate - baseline), expressed as a percent of the baseline mean to
+// match DeltaPct.
+func welchCI(baseline, candidate []float64) (low, high float64) {
+	ma, mb := mean(baseline), mean(candidate)
+	va, vb := variance(baseline, ma), variance(candidate, mb)
+	na, nb := float64(len(baseline)), float64(len(candidate))
+
+	se := math.Sqrt(va/na + vb/nb)
+	diff := mb - ma
+	const z95 = 1.96
+	lowAbs, highAbs := diff-z95*se, diff+z95*se
+
+	if ma == 0 {
+		return lowAbs, highAbs
+	}
+	return lowAbs / math.Abs(ma) * 100, highAbs / math.Abs(ma) * 100
+}
+
+// mannWhitneyU returns the U statistic and the two-tailed p-value (normal
+// approximation with tie correction) for the Mann-Whitney U test between
+// two independent samples.
+func mannWhitneyU(a, b []float64) (u, pValue float64) {
+	na, nb := len(a), len(b)
+	combined := make([]struct {
+		value float64
+		group int
+	}, 0, na+nb)
+	for _, v := range a {
+		combined = append(combined, struct {
+			value float64
+			group int
+		}{v, 0})
+	}
+	for _, v := range b {
+		combined = append(combined, struct {
+			value float64
+			group int
+		}{v, 1})
+	}
+	sort.Slice(combined, func(i, j int) bool { return combined[i].value < combined[j].value })
+
+	ranks := make([]float64, len(combined))
+	var tieCorrection float64
+	i := 0
+	for i < len(combined) {
+		j := i
+		for j < len(combined) && combined[j].value == combined[i].value {
+			j++
+		}
+		avgRank := float64(i+j+1) / 2 // 1-indexed average rank over the tie run
+		tieCount := float64(j - i)
+		for k := i; k < j; k++ {
+			ranks[k] = avgRank
+		}
+		tieCorrection += tieCount*tieCount*tieCount - tieCount
+		i = j
+	}
+
+	var rankSumA float64
+	for i, c := range combined {
+		if c.group == 0 {
+			rankSumA += ranks[i]
+		}
+	}
+
+	nA, nB := float64(na), float64(nb)
+	uA := rankSumA - nA*(nA+1)/2
+	uB := nA*nB - uA
+	u = math.Min(uA, uB)
+
+	n := nA + nB
+	meanU := nA * nB / 2
+	varU := nA * nB * (n + 1) / 12
+	if n > 1 {
+		varU -= nA * nB * tieCorrection / (12 * n * (n - 1))
+	}
+	if varU <= 0 {
+		return u, 1
+	}
+
+	z := (u - meanU) / math.Sqrt(varU)
+	return u, twoTailedNormalP(z)
+}
+
+// twoTailedNormalP returns the two-tailed p-value for a standard normal test
+// statistic z.
+func twoTailedNormalP(z float64) float64 {
+	return 2 * (1 - normalCDF(math.Abs(z)))
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
+
+// Threshold is a parsed --regression-threshold entry, e.g. "ttft:+10%"
+// means "fail if TTFT rises by more than 10%", and "tput:-5%" means "fail
+// if throughput drops by more than 5%".
+type Threshold struct {
+	Metric     string
+	LimitPct   float64
+	AllowRaise bool // true if the sign is "+": a rise past LimitPct breaches
+}
+
+// Breaches reports whether deltaPct breaches this threshold.
+func (t Threshold) Breaches(deltaPct float64) bool {
+	if t.AllowRaise {
+		return deltaPct > t.LimitPct
+	}
+	return deltaPct < -t.LimitPct
+}
+
+// ParseThresholds parses a comma-separated list of "<metric>:<+|-><pct>%"
+// entries, e.g. "ttft:+10%,tput:-5%".
+func ParseThresholds(spec string) ([]Threshold, error) {
+	var thresholds []Threshold
+	if spec == "" {
+		return thresholds, nil
+	}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid threshold %q: expected <metric>:<+|-><pct>%%", entry)
+		}
+		metric := strings.TrimSpace(parts[0])
+		limitSpec := strings.TrimSpace(parts[1])
+
+		allowRaise := true
+		switch {
+		case strings.HasPrefix(limitSpec, "+"):
+			allowRaise = true
+			limitSpec = limitSpec[1:]
+		case strings.HasPrefix(limitSpec, "-"):
+			allowRaise = false
+			limitSpec = limitSpec[1:]
+		default:
+			return nil, fmt.Errorf("invalid threshold %q: missing +/- sign", entry)
+		}
+		limitSpec = strings.TrimSuffix(limitSpec, "%")
+
+		limitPct, err := strconv.ParseFloat(limitSpec, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold %q: %w", entry, err)
+		}
+
+		thresholds = append(thresholds, Threshold{
+			Metric:     metric,
+			LimitPct:   limitPct,
+			AllowRaise: allowRaise,
+		})
+	}
+	return thresholds, nil
+}
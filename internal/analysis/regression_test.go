@@ -0,0 +1,111 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompare_SingleObservationFallsBackToPointEstimate(t *testing.T) {
+	d := Compare("ttft", []float64{10}, []float64{12})
+
+	if math.Abs(d.DeltaPct-20) > 1e-9 {
+		t.Errorf("DeltaPct = %v, want 20", d.DeltaPct)
+	}
+	if d.PValue != 1 {
+		t.Errorf("PValue = %v, want 1 (insufficient data)", d.PValue)
+	}
+	if d.Significant {
+		t.Error("Significant = true with only one observation per side, want false")
+	}
+}
+
+func TestCompare_ClearShiftIsSignificant(t *testing.T) {
+	baseline := []float64{10, 11, 9, 10, 10, 9, 11, 10, 9, 10}
+	candidate := []float64{20, 21, 19, 20, 20, 19, 21, 20, 19, 20}
+
+	d := Compare("ttft", baseline, candidate)
+
+	if !d.Significant {
+		t.Errorf("expected a 2x shift across 10 samples to be significant, p_value=%v", d.PValue)
+	}
+	if d.DeltaPct < 50 {
+		t.Errorf("DeltaPct = %v, expected close to 100", d.DeltaPct)
+	}
+}
+
+func TestCompare_IdenticalSamplesAreNotSignificant(t *testing.T) {
+	baseline := []float64{10, 11, 9, 10, 12, 8, 10, 11, 9, 10}
+	candidate := []float64{10, 11, 9, 10, 12, 8, 10, 11, 9, 10}
+
+	d := Compare("ttft", baseline, candidate)
+
+	if d.Significant {
+		t.Errorf("identical samples should not be significant, p_value=%v", d.PValue)
+	}
+	if d.DeltaPct != 0 {
+		t.Errorf("DeltaPct = %v, want 0", d.DeltaPct)
+	}
+}
+
+func TestThreshold_Breaches(t *testing.T) {
+	cases := []struct {
+		name     string
+		t        Threshold
+		deltaPct float64
+		want     bool
+	}{
+		{"rise within limit", Threshold{Metric: "ttft", LimitPct: 10, AllowRaise: true}, 5, false},
+		{"rise past limit", Threshold{Metric: "ttft", LimitPct: 10, AllowRaise: true}, 15, true},
+		{"drop within limit", Threshold{Metric: "tput", LimitPct: 5, AllowRaise: false}, -3, false},
+		{"drop past limit", Threshold{Metric: "tput", LimitPct: 5, AllowRaise: false}, -8, true},
+		{"drop threshold ignores a rise", Threshold{Metric: "tput", LimitPct: 5, AllowRaise: false}, 20, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.t.Breaches(c.deltaPct); got != c.want {
+				t.Errorf("Breaches(%v) = %v, want %v", c.deltaPct, got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseThresholds(t *testing.T) {
+	got, err := ParseThresholds("ttft:+10%,tput:-5%")
+	if err != nil {
+		t.Fatalf("ParseThresholds: %v", err)
+	}
+	want := []Threshold{
+		{Metric: "ttft", LimitPct: 10, AllowRaise: true},
+		{Metric: "tput", LimitPct: 5, AllowRaise: false},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d thresholds, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("threshold[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseThresholds_Empty(t *testing.T) {
+	got, err := ParseThresholds("")
+	if err != nil {
+		t.Fatalf("ParseThresholds: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %d thresholds, want 0", len(got))
+	}
+}
+
+func TestParseThresholds_InvalidSign(t *testing.T) {
+	if _, err := ParseThresholds("ttft:10%"); err == nil {
+		t.Error("expected error for missing +/- sign, got nil")
+	}
+}
+
+func TestParseThresholds_InvalidNumber(t *testing.T) {
+	if _, err := ParseThresholds("ttft:+abc%"); err == nil {
+		t.Error("expected error for non-numeric limit, got nil")
+	}
+}
@@ -0,0 +1,257 @@
+// Package analyzer runs a rigorous statistical A/B comparison between two
+// sample sets, treating one as a control and the other as a treatment.
+// It differs from internal/analysis (which compares pre-aggregated
+// percentiles with a t-test) by working from raw per-request
+// observations: it reports median rather than mean deltas, and estimates
+// the confidence interval on that delta with bootstrap resampling instead
+// of a parametric formula, since raw latency samples are rarely
+// normally distributed.
+package analyzer
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// bootstrapResamples is how many times Compare resamples control and
+// treatment (with replacement) to estimate the confidence interval on
+// their median delta. 1000 is the standard default for a percentile
+// bootstrap: enough to resolve the 2.5th/97.5th percentile bounds
+// without making every comparison noticeably slow.
+const bootstrapResamples = 1000
+
+// bootstrapSeed fixes Compare's resampling so the same two sample sets
+// always produce the same CI bounds and Significant verdict — a report
+// that jitters a metric across the significance line between identical
+// runs would undermine the whole point of running the test.
+const bootstrapSeed = 42
+
+// MetricStats summarizes one side (control or treatment) of a metric
+// comparison.
+type MetricStats struct {
+	N      int     `json:"n"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"stddev"`
+	IQR    float64 `json:"iqr"`
+}
+
+// MetricReport is the full statistical comparison of one metric between
+// a control and a treatment sample set.
+type MetricReport struct {
+	Metric         string      `json:"metric"`
+	Control        MetricStats `json:"control"`
+	Treatment      MetricStats `json:"treatment"`
+	MedianDeltaPct float64     `json:"median_delta_pct"`
+	CILowPct       float64     `json:"ci_low_pct"`
+	CIHighPct      float64     `json:"ci_high_pct"`
+	PValue         float64     `json:"p_value"`
+
+	// Significant is true when the bootstrap CI on the median delta
+	// excludes zero, i.e. the direction of the change is unlikely to be
+	// noise regardless of what the Mann-Whitney p-value says.
+	Significant bool `json:"significant"`
+}
+
+// Compare computes summary statistics for control and treatment, a
+// bootstrap confidence interval on the percent change between their
+// medians, and a Mann-Whitney U test p-value for whether the two
+// distributions differ. When either side has fewer than two
+// observations there isn't enough data to resample or rank, so the CI
+// collapses to the point estimate and PValue is reported as 1.
+func Compare(metric string, control, treatment []float64) MetricReport {
+	r := MetricReport{
+		Metric:    metric,
+		Control:   summarize(control),
+		Treatment: summarize(treatment),
+	}
+	if len(control) == 0 || len(treatment) == 0 {
+		r.PValue = 1
+		return r
+	}
+	r.MedianDeltaPct = percentDelta(r.Control.Median, r.Treatment.Median)
+
+	if len(control) < 2 || len(treatment) < 2 {
+		r.CILowPct, r.CIHighPct = r.MedianDeltaPct, r.MedianDeltaPct
+		r.PValue = 1
+		return r
+	}
+
+	r.CILowPct, r.CIHighPct = bootstrapMedianDeltaCI(control, treatment)
+	r.Significant = r.CILowPct > 0 || r.CIHighPct < 0
+
+	_, r.PValue = mannWhitneyU(control, treatment)
+	return r
+}
+
+func percentDelta(baseline, candidate float64) float64 {
+	if baseline == 0 {
+		return 0
+	}
+	return (candidate - baseline) / math.Abs(baseline) * 100
+}
+
+func summarize(xs []float64) MetricStats {
+	if len(xs) == 0 {
+		return MetricStats{}
+	}
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	m := mean(xs)
+	return MetricStats{
+		N:      len(xs),
+		Mean:   m,
+		Median: percentile(sorted, 50),
+		StdDev: math.Sqrt(variance(xs, m)),
+		IQR:    percentile(sorted, 75) - percentile(sorted, 25),
+	}
+}
+
+// bootstrapMedianDeltaCI resamples control and treatment with
+// replacement bootstrapResamples times, recomputing the percent change
+// in medians each time, and returns the 2.5th/97.5th percentile bounds
+// of that resampled distribution as a nonparametric 95% confidence
+// interval.
+func bootstrapMedianDeltaCI(control, treatment []float64) (low, high float64) {
+	rng := rand.New(rand.NewSource(bootstrapSeed))
+	deltas := make([]float64, bootstrapResamples)
+	for i := 0; i < bootstrapResamples; i++ {
+		c := resample(rng, control)
+		t := resample(rng, treatment)
+		deltas[i] = percentDelta(medianOf(c), medianOf(t))
+	}
+	sort.Float64s(deltas)
+	return percentile(deltas, 2.5), percentile(deltas, 97.5)
+}
+
+func resample(rng *rand.Rand, xs []float64) []float64 {
+	out := make([]float64, len(xs))
+	for i := range out {
+		out[i] = xs[rng.Intn(len(xs))]
+	}
+	return out
+}
+
+func medianOf(xs []float64) float64 {
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	return percentile(sorted, 50)
+}
+
+// percentile returns the p-th percentile (0-100) of sorted via linear
+// interpolation between the closest ranks. sorted must already be
+// ascending.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	frac := rank - float64(lo)
+	return sorted[lo] + (sorted[hi]-sorted[lo])*frac
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+func variance(xs []float64, m float64) float64 {
+	if len(xs) < 2 {
+		return 0
+	}
+	var sumSq float64
+	for _, x := range xs {
+		d := x - m
+		sumSq += d * d
+	}
+	return sumSq / float64(len(xs)-1)
+}
+
+// mannWhitneyU returns the U statistic and the two-tailed p-value
+// (normal approximation with tie correction) for the Mann-Whitney U test
+// between two independent samples.
+func mannWhitneyU(a, b []float64) (u, pValue float64) {
+	na, nb := len(a), len(b)
+	combined := make([]struct {
+		value float64
+		group int
+	}, 0, na+nb)
+	for _, v := range a {
+		combined = append(combined, struct {
+			value float64
+			group int
+		}{v, 0})
+	}
+	for _, v := range b {
+		combined = append(combined, struct {
+			value float64
+			group int
+		}{v, 1})
+	}
+	sort.Slice(combined, func(i, j int) bool { return combined[i].value < combined[j].value })
+
+	ranks := make([]float64, len(combined))
+	var tieCorrection float64
+	i := 0
+	for i < len(combined) {
+		j := i
+		for j < len(combined) && combined[j].value == combined[i].value {
+			j++
+		}
+		avgRank := float64(i+j+1) / 2 // 1-indexed average rank over the tie run
+		tieCount := float64(j - i)
+		for k := i; k < j; k++ {
+			ranks[k] = avgRank
+		}
+		tieCorrection += tieCount*tieCount*tieCount - tieCount
+		i = j
+	}
+
+	var rankSumA float64
+	for i, c := range combined {
+		if c.group == 0 {
+			rankSumA += ranks[i]
+		}
+	}
+
+	nA, nB := float64(na), float64(nb)
+	uA := rankSumA - nA*(nA+1)/2
+	uB := nA*nB - uA
+	u = math.Min(uA, uB)
+
+	n := nA + nB
+	meanU := nA * nB / 2
+	varU := nA * nB * (n + 1) / 12
+	if n > 1 {
+		varU -= nA * nB * tieCorrection / (12 * n * (n - 1))
+	}
+	if varU <= 0 {
+		return u, 1
+	}
+
+	z := (u - meanU) / math.Sqrt(varU)
+	return u, twoTailedNormalP(z)
+}
+
+func twoTailedNormalP(z float64) float64 {
+	return 2 * (1 - normalCDF(math.Abs(z)))
+}
+
+func normalCDF(x float64) float64 {
+	return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
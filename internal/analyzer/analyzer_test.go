@@ -0,0 +1,66 @@
+package analyzer
+
+import "testing"
+
+func TestCompare_SingleObservationFallsBackToPointEstimate(t *testing.T) {
+	r := Compare("ttft_ms", []float64{10}, []float64{12})
+
+	if r.MedianDeltaPct != 20 {
+		t.Errorf("MedianDeltaPct = %v, want 20", r.MedianDeltaPct)
+	}
+	if r.PValue != 1 {
+		t.Errorf("PValue = %v, want 1 (insufficient data)", r.PValue)
+	}
+	if r.Significant {
+		t.Error("Significant = true with only one observation per side, want false")
+	}
+}
+
+func TestCompare_ClearShiftIsSignificant(t *testing.T) {
+	control := []float64{10, 11, 9, 10, 10, 9, 11, 10, 9, 10}
+	treatment := []float64{20, 21, 19, 20, 20, 19, 21, 20, 19, 20}
+
+	r := Compare("ttft_ms", control, treatment)
+
+	if !r.Significant {
+		t.Errorf("expected a 2x shift across 10 samples to be significant, ci=[%v,%v]", r.CILowPct, r.CIHighPct)
+	}
+	if r.MedianDeltaPct < 50 {
+		t.Errorf("MedianDeltaPct = %v, expected close to 100", r.MedianDeltaPct)
+	}
+	if r.PValue >= 0.05 {
+		t.Errorf("PValue = %v, expected < 0.05 for a clear shift", r.PValue)
+	}
+}
+
+func TestCompare_IdenticalSamplesAreNotSignificant(t *testing.T) {
+	xs := []float64{10, 11, 9, 10, 12, 8, 10, 11, 9, 10}
+
+	r := Compare("ttft_ms", xs, append([]float64(nil), xs...))
+
+	if r.Significant {
+		t.Errorf("identical samples should not be significant, ci=[%v,%v]", r.CILowPct, r.CIHighPct)
+	}
+	if r.MedianDeltaPct != 0 {
+		t.Errorf("MedianDeltaPct = %v, want 0", r.MedianDeltaPct)
+	}
+}
+
+func TestCompare_DeterministicAcrossRuns(t *testing.T) {
+	control := []float64{10, 14, 9, 11, 13, 8, 12, 10, 9, 15}
+	treatment := []float64{15, 19, 14, 16, 18, 13, 17, 15, 14, 20}
+
+	a := Compare("e2e_latency_ms", control, treatment)
+	b := Compare("e2e_latency_ms", control, treatment)
+
+	if a.CILowPct != b.CILowPct || a.CIHighPct != b.CIHighPct {
+		t.Errorf("bootstrap CI is not deterministic: %v,%v vs %v,%v", a.CILowPct, a.CIHighPct, b.CILowPct, b.CIHighPct)
+	}
+}
+
+func TestSummarize_EmptyIsZeroValue(t *testing.T) {
+	s := summarize(nil)
+	if s != (MetricStats{}) {
+		t.Errorf("summarize(nil) = %+v, want zero value", s)
+	}
+}
@@ -0,0 +1,82 @@
+package analyzer
+
+import "github.com/accelbench/accelbench/internal/database"
+
+// metricOrder fixes the metrics CompareSamples computes and the order
+// they appear in a Report: request-level latency first, then the
+// derived throughput figure users ultimately care about.
+var metricOrder = []string{"ttft_ms", "e2e_latency_ms", "itl_ms", "throughput_tps"}
+
+// Report is the full A/B comparison between a control run and a
+// treatment run, one MetricReport per metric in metricOrder.
+type Report struct {
+	ControlRunID   string         `json:"control_run_id"`
+	TreatmentRunID string         `json:"treatment_run_id"`
+	Metrics        []MetricReport `json:"metrics"`
+}
+
+// CompareSamples runs Compare for every metric in metricOrder between
+// control's and treatment's raw per-request samples, so a caller can
+// tell whether a framework-version bump or quantization change actually
+// moved the numbers rather than eyeballing two p50s.
+func CompareSamples(controlRunID, treatmentRunID string, control, treatment []database.Sample) Report {
+	report := Report{ControlRunID: controlRunID, TreatmentRunID: treatmentRunID}
+	for _, metric := range metricOrder {
+		report.Metrics = append(report.Metrics, Compare(metric, metricValues(control, metric), metricValues(treatment, metric)))
+	}
+	return report
+}
+
+// metricValues extracts every non-nil observation of metric from
+// samples, in order, for feeding to Compare.
+func metricValues(samples []database.Sample, metric string) []float64 {
+	values := make([]float64, 0, len(samples))
+	for _, s := range samples {
+		if v, ok := sampleMetricValue(s, metric); ok {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// sampleMetricValue extracts metric from one raw sample. e2e_latency_ms
+// and throughput_tps aren't recorded directly — Sample only carries
+// TTFT, ITL, and TokensOut per request — so both are derived from the
+// other three.
+func sampleMetricValue(s database.Sample, metric string) (float64, bool) {
+	switch metric {
+	case "ttft_ms":
+		if s.TTFTMs == nil {
+			return 0, false
+		}
+		return *s.TTFTMs, true
+	case "itl_ms":
+		if s.ITLMs == nil {
+			return 0, false
+		}
+		return *s.ITLMs, true
+	case "e2e_latency_ms":
+		return derivedE2ELatencyMs(s)
+	case "throughput_tps":
+		e2eMs, ok := derivedE2ELatencyMs(s)
+		if !ok || e2eMs <= 0 || s.TokensOut <= 0 {
+			return 0, false
+		}
+		return float64(s.TokensOut) / (e2eMs / 1000), true
+	default:
+		return 0, false
+	}
+}
+
+// derivedE2ELatencyMs approximates one request's end-to-end latency as
+// its time-to-first-token plus its inter-token latency repeated over the
+// remaining output tokens — the same TTFT + ITL*(tokens-1) model
+// gatherCatalogHealth uses to sanity-check aggregated percentiles,
+// applied per-request here since Sample has no E2E latency field of its
+// own.
+func derivedE2ELatencyMs(s database.Sample) (float64, bool) {
+	if s.TTFTMs == nil || s.ITLMs == nil || s.TokensOut < 1 {
+		return 0, false
+	}
+	return *s.TTFTMs + *s.ITLMs*float64(s.TokensOut-1), true
+}
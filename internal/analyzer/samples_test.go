@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestCompareSamples_DerivesE2ELatencyAndThroughput(t *testing.T) {
+	control := []database.Sample{
+		{TTFTMs: floatPtr(100), ITLMs: floatPtr(20), TokensOut: 11}, // e2e = 100 + 20*10 = 300ms
+	}
+	treatment := []database.Sample{
+		{TTFTMs: floatPtr(50), ITLMs: floatPtr(10), TokensOut: 11}, // e2e = 50 + 10*10 = 150ms
+	}
+
+	report := CompareSamples("control-run", "treatment-run", control, treatment)
+
+	var e2e, tput *MetricReport
+	for i := range report.Metrics {
+		switch report.Metrics[i].Metric {
+		case "e2e_latency_ms":
+			e2e = &report.Metrics[i]
+		case "throughput_tps":
+			tput = &report.Metrics[i]
+		}
+	}
+	if e2e == nil || tput == nil {
+		t.Fatalf("expected e2e_latency_ms and throughput_tps metrics, got %+v", report.Metrics)
+	}
+	if e2e.Control.Median != 300 || e2e.Treatment.Median != 150 {
+		t.Errorf("e2e medians = %v/%v, want 300/150", e2e.Control.Median, e2e.Treatment.Median)
+	}
+	// control: 11 tokens / 0.3s ≈ 36.67 tok/s; treatment: 11 / 0.15s ≈ 73.3 tok/s
+	if tput.Control.Median < 36 || tput.Control.Median > 37 {
+		t.Errorf("control throughput median = %v, want ~36.67", tput.Control.Median)
+	}
+	if tput.Treatment.Median < 73 || tput.Treatment.Median > 74 {
+		t.Errorf("treatment throughput median = %v, want ~73.33", tput.Treatment.Median)
+	}
+}
+
+func TestSampleMetricValue_MissingFieldsAreExcluded(t *testing.T) {
+	s := database.Sample{TokensOut: 5} // no TTFT/ITL recorded
+
+	if _, ok := sampleMetricValue(s, "ttft_ms"); ok {
+		t.Error("ttft_ms should be excluded when TTFTMs is nil")
+	}
+	if _, ok := sampleMetricValue(s, "e2e_latency_ms"); ok {
+		t.Error("e2e_latency_ms should be excluded when TTFT/ITL are nil")
+	}
+}
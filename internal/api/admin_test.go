@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/accelbench/accelbench/internal/database"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestListenUnix_ServesRequests(t *testing.T) {
+	_, mux := setupServer()
+
+	socketPath := filepath.Join(t.TempDir(), "accelbench.sock")
+	ln, err := ListenUnix(socketPath, 0600)
+	if err != nil {
+		t.Fatalf("ListenUnix: %v", err)
+	}
+	defer ln.Close()
+	go http.Serve(ln, mux)
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+
+	resp, err := client.Get("http://unix/api/v1/catalog")
+	if err != nil {
+		t.Fatalf("GET over unix socket: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestRequireAuthz_RejectsUnauthenticatedMutation(t *testing.T) {
+	repo := seedRepo()
+	client := fake.NewSimpleClientset()
+	srv := NewServer(repo, client, WithAuthzPolicy(NewCNAuthzPolicy("operator-1")))
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	body := database.RunRequest{
+		ModelHfID:        "meta-llama/Llama-3.1-8B",
+		ModelHfRevision:  "abc123",
+		InstanceTypeName: "g5.xlarge",
+		Framework:        "vllm",
+		FrameworkVersion: "v0.6.0",
+		Concurrency:      1,
+		RunType:          "on_demand",
+	}
+	b, _ := json.Marshal(body)
+
+	req := httptest.NewRequest("POST", "/api/v1/runs", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	var problem Problem
+	json.NewDecoder(w.Body).Decode(&problem)
+	if problem.Code != codeUnauthorized {
+		t.Errorf("code = %q, want %s", problem.Code, codeUnauthorized)
+	}
+}
+
+func TestRequireAuthz_AllowsReadOnlyEndpointsWithoutCert(t *testing.T) {
+	repo := seedRepo()
+	client := fake.NewSimpleClientset()
+	srv := NewServer(repo, client, WithAuthzPolicy(NewCNAuthzPolicy("operator-1")))
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	req := httptest.NewRequest("GET", "/api/v1/catalog", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
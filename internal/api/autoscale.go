@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// handleCreateAutoscale validates an AutoscaleRequest's model and instance
+// type, creates its RunType="autoscale" run, and launches the ramp in the
+// background via orchestrator.ExecuteAutoscale — the same detached-goroutine
+// shape handleCreateSLOSearch uses for ExecuteSLOSearch, since a ramp across
+// every ConcurrencyStages entry can take as long as a whole sweep.
+func (s *Server) handleCreateAutoscale(w http.ResponseWriter, r *http.Request) {
+	var req database.AutoscaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidAutoscaleRequest, "invalid request body"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidAutoscaleRequest, err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+
+	model, err := s.repo.EnsureModel(ctx, req.ModelHfID, req.ModelHfRevision)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "ensure model failed"})
+		return
+	}
+
+	instType, err := s.repo.GetInstanceTypeByName(ctx, req.InstanceTypeName)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "lookup instance type failed"})
+		return
+	}
+	if instType == nil {
+		writeError(w, &apiError{http.StatusNotFound, codeInstanceTypeNotFound, fmt.Sprintf("instance type %s not found", req.InstanceTypeName)})
+		return
+	}
+
+	run := &database.BenchmarkRun{
+		ModelID:              model.ID,
+		InstanceTypeID:       instType.ID,
+		Framework:            req.Framework,
+		FrameworkVersion:     req.FrameworkVersion,
+		TensorParallelDegree: req.TensorParallelDegree,
+		Quantization:         req.Quantization,
+		InputSequenceLength:  req.InputSequenceLength,
+		OutputSequenceLength: req.OutputSequenceLength,
+		DatasetName:          req.DatasetName,
+		DatasetSpec:          req.DatasetSpec,
+		RunType:              "autoscale",
+		Status:               "pending",
+	}
+	runID, err := s.repo.CreateBenchmarkRun(ctx, run)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "create run failed"})
+		return
+	}
+
+	go func() {
+		if err := s.orch.ExecuteAutoscale(context.Background(), runID, model, instType, req); err != nil {
+			log.Printf("autoscale %s: %v", runID, err)
+		}
+	}()
+
+	writeJSON(w, http.StatusAccepted, map[string]string{
+		"id":     runID,
+		"status": "pending",
+	})
+}
+
+// handleGetAutoscale reports a ramp's run status plus — once any stage has
+// persisted — the per-stage trajectory and replica bounds recorded on its
+// own BenchmarkMetrics.
+func (s *Server) handleGetAutoscale(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+	ctx := r.Context()
+
+	run, err := s.repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
+		return
+	}
+	if run == nil || run.RunType != "autoscale" {
+		writeError(w, &apiError{http.StatusNotFound, codeAutoscaleNotFound, "autoscale run not found"})
+		return
+	}
+
+	resp := map[string]any{
+		"id":     run.ID,
+		"status": run.Status,
+	}
+
+	metrics, err := s.repo.GetMetricsByRunID(ctx, runID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query metrics failed"})
+		return
+	}
+	if metrics != nil {
+		resp["stages"] = metrics.AutoscaleStages
+		if metrics.ReplicasMin != nil {
+			resp["replicas_min"] = *metrics.ReplicasMin
+		}
+		if metrics.ReplicasMax != nil {
+			resp["replicas_max"] = *metrics.ReplicasMax
+		}
+		if metrics.ScaleUpLatencySeconds != nil {
+			resp["scale_up_latency_seconds"] = *metrics.ScaleUpLatencySeconds
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
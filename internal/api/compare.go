@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/accelbench/accelbench/internal/analyzer"
+)
+
+// handleCompareRuns evaluates a statistical A/B comparison between the
+// run IDs in the control and treatment query parameters, over each run's
+// raw per-request samples, so a caller can tell whether a change
+// actually moved a metric rather than eyeballing two p50s.
+func (s *Server) handleCompareRuns(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	controlID := q.Get("control")
+	treatmentID := q.Get("treatment")
+	if controlID == "" || treatmentID == "" {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidQuery, "control and treatment query parameters are required"})
+		return
+	}
+
+	control, treatment, err := s.repo.CompareRuns(r.Context(), controlID, treatmentID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
+		return
+	}
+	if len(control) == 0 {
+		writeError(w, &apiError{http.StatusNotFound, codeRunNotFound, "no samples found for control run " + controlID})
+		return
+	}
+	if len(treatment) == 0 {
+		writeError(w, &apiError{http.StatusNotFound, codeRunNotFound, "no samples found for treatment run " + treatmentID})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, analyzer.CompareSamples(controlID, treatmentID, control, treatment))
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// defaultRunTimeoutSeconds is the deadline applied to a run that doesn't
+// set RunRequest.TimeoutSeconds, long enough to cover a normal deploy +
+// readiness wait + loadgen cycle on a cold cluster (see
+// orchestrator's readinessTimeout/jobTimeout) with headroom for queue wait.
+const defaultRunTimeoutSeconds = 4 * 60 * 60
+
+// defaultMaxRunTimeoutSeconds is the operator-set ceiling resolveRunTimeout
+// clamps every run's effective timeout to, so a caller can request a long
+// timeout but not an unbounded one. Override with WithMaxRunTimeoutSeconds.
+const defaultMaxRunTimeoutSeconds = 24 * 60 * 60
+
+// WithMaxRunTimeoutSeconds overrides defaultMaxRunTimeoutSeconds, the cap
+// resolveRunTimeout applies to every run's TimeoutSeconds.
+func WithMaxRunTimeoutSeconds(seconds int) ServerOption {
+	return func(s *Server) { s.maxRunTimeoutSeconds = seconds }
+}
+
+// resolveRunTimeout returns the effective TimeoutSeconds for a newly
+// created run: requested if positive and within s.maxRunTimeoutSeconds,
+// the max if requested exceeds it, or defaultRunTimeoutSeconds if the
+// caller left TimeoutSeconds unset (zero).
+func (s *Server) resolveRunTimeout(requested int) int {
+	if requested <= 0 {
+		requested = defaultRunTimeoutSeconds
+	}
+	if s.maxRunTimeoutSeconds > 0 && requested > s.maxRunTimeoutSeconds {
+		return s.maxRunTimeoutSeconds
+	}
+	return requested
+}
+
+// deadlineRequest is the body of PATCH /runs/{id}/deadline.
+type deadlineRequest struct {
+	// ExtendSeconds is added to the run's current DeadlineAt (or to now,
+	// if the run has none) to compute its new deadline, the same
+	// lease-renewal shape as orchestrator's job-poll leases: the caller
+	// asks for more time rather than supplying an absolute timestamp.
+	ExtendSeconds int `json:"extend_seconds"`
+}
+
+// handleExtendRunDeadline prolongs a still-queued, pending, or running
+// run's deadline mid-flight, for a caller whose workload is legitimately
+// taking longer than its original TimeoutSeconds allowed — e.g. a slow
+// model download it can see progressing. The repo's ExtendDeadline CAS is
+// what actually races safely against the reaper; this handler just
+// resolves ExtendSeconds against the run's current DeadlineAt first,
+// clamped to s.maxRunTimeoutSeconds from now the same way resolveRunTimeout
+// clamps a run's timeout at creation, so repeated extensions can't be used
+// to evade the operator-set ceiling.
+func (s *Server) handleExtendRunDeadline(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+	ctx := r.Context()
+
+	var req deadlineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidDeadlineRequest, "invalid request body"})
+		return
+	}
+	if req.ExtendSeconds <= 0 {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidDeadlineRequest, "extend_seconds must be positive"})
+		return
+	}
+
+	run, err := s.repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
+		return
+	}
+	if run == nil {
+		writeError(w, &apiError{http.StatusNotFound, codeRunNotFound, "run not found"})
+		return
+	}
+
+	now := time.Now()
+	base := now
+	if run.DeadlineAt != nil && run.DeadlineAt.After(base) {
+		base = *run.DeadlineAt
+	}
+	newDeadline := base.Add(time.Duration(req.ExtendSeconds) * time.Second)
+	if s.maxRunTimeoutSeconds > 0 {
+		if maxDeadline := now.Add(time.Duration(s.maxRunTimeoutSeconds) * time.Second); newDeadline.After(maxDeadline) {
+			newDeadline = maxDeadline
+		}
+	}
+
+	err = s.repo.ExtendDeadline(ctx, runID, newDeadline)
+	switch {
+	case err == nil:
+	case errors.Is(err, database.ErrRunNotFound):
+		writeError(w, &apiError{http.StatusNotFound, codeRunNotFound, "run not found"})
+		return
+	case errors.Is(err, database.ErrRunNotExtendable):
+		writeError(w, &apiError{http.StatusConflict, codeRunNotExtendable, fmt.Sprintf("cannot extend deadline for run with status %q", run.Status)})
+		return
+	default:
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "extend deadline failed"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"id": runID, "deadline_at": newDeadline})
+}
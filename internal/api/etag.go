@@ -0,0 +1,32 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// runETag returns run's current version as a quoted strong ETag, using
+// run.Status the same way the etcd-backed Repo uses ModRevision: a cheap,
+// monotonic-enough stand-in for a real version counter that changes on
+// every state transition a client could race against.
+func runETag(run *database.BenchmarkRun) string {
+	return fmt.Sprintf("%q", run.Status)
+}
+
+// checkIfMatch enforces an optional If-Match precondition against run,
+// returning a 412 apiError if the header is present and doesn't match
+// run's current ETag. A missing header always passes, so callers that
+// never read a run's ETag keep working unconditionally.
+func checkIfMatch(r *http.Request, run *database.BenchmarkRun) *apiError {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		return nil
+	}
+	if ifMatch != runETag(run) {
+		return &apiError{http.StatusPreconditionFailed, codePreconditionFailed,
+			fmt.Sprintf("run has since transitioned to status %q; refetch and retry", run.Status)}
+	}
+	return nil
+}
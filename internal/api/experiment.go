@@ -0,0 +1,166 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// handleCreateExperiment expands an ExperimentSpec into every arm's
+// Cartesian product of RunRequests, validates every distinct (model,
+// instance type) pair up front, and — only if all of them resolve —
+// creates the experiment and its child runs atomically before admitting
+// each into the scheduler's persistent queue. Unlike handleCreateSweep's
+// bespoke worker pool, experiment runs are dispatched through the same
+// Scheduler.Enqueue path as a single POST /runs, so the existing
+// per-instance-family concurrency cap applies across arms too.
+func (s *Server) handleCreateExperiment(w http.ResponseWriter, r *http.Request) {
+	var spec database.ExperimentSpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidExperimentSpec, "invalid request body"})
+		return
+	}
+
+	requests, arms, err := spec.Expand()
+	if err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidExperimentSpec, err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+
+	// Prefer the caller's verified mTLS client certificate CN over
+	// whatever UserID each expanded RunRequest self-reports, the same
+	// override handleCreateRun applies to a single run.
+	if cn := verifiedUserID(r); cn != "" {
+		for i := range requests {
+			requests[i].UserID = cn
+		}
+	}
+
+	// Resolve every distinct model and instance type up front so a single
+	// bad reference fails the whole experiment before anything is created.
+	type modelKey struct{ hfID, hfRevision string }
+	models := make(map[modelKey]*database.Model)
+	instTypes := make(map[string]*database.InstanceType)
+	for _, req := range requests {
+		mk := modelKey{req.ModelHfID, req.ModelHfRevision}
+		if _, ok := models[mk]; !ok {
+			model, err := s.repo.EnsureModel(ctx, req.ModelHfID, req.ModelHfRevision)
+			if err != nil {
+				writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "ensure model failed"})
+				return
+			}
+			models[mk] = model
+		}
+		if _, ok := instTypes[req.InstanceTypeName]; ok {
+			continue
+		}
+		it, err := s.repo.GetInstanceTypeByName(ctx, req.InstanceTypeName)
+		if err != nil {
+			writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "lookup instance type failed"})
+			return
+		}
+		if it == nil {
+			writeError(w, &apiError{http.StatusNotFound, codeInstanceTypeNotFound, fmt.Sprintf("instance type %s not found", req.InstanceTypeName)})
+			return
+		}
+		instTypes[req.InstanceTypeName] = it
+	}
+
+	runs := make([]*database.BenchmarkRun, len(requests))
+	for i, req := range requests {
+		runs[i] = &database.BenchmarkRun{
+			ModelID:              models[modelKey{req.ModelHfID, req.ModelHfRevision}].ID,
+			InstanceTypeID:       instTypes[req.InstanceTypeName].ID,
+			Framework:            req.Framework,
+			FrameworkVersion:     req.FrameworkVersion,
+			TensorParallelDegree: req.TensorParallelDegree,
+			Quantization:         req.Quantization,
+			Concurrency:          req.Concurrency,
+			InputSequenceLength:  req.InputSequenceLength,
+			OutputSequenceLength: req.OutputSequenceLength,
+			DatasetName:          req.DatasetName,
+			DatasetSpec:          req.DatasetSpec,
+			RunType:              req.RunType,
+			Status:               "pending",
+			Arm:                  arms[i],
+			OriginalRequest:      &requests[i],
+		}
+	}
+
+	experimentID, runIDs, err := s.repo.CreateExperiment(ctx, runs)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "create experiment failed"})
+		return
+	}
+
+	// Enqueue every run even if one fails partway through: leaving the
+	// rest at status "pending" with no queue entry would strand them
+	// there forever instead of surfacing as failed alongside the run that
+	// actually hit the error.
+	var enqueueErr error
+	for i, runID := range runIDs {
+		req := requests[i]
+		if enqueueErr != nil {
+			_ = s.repo.UpdateRunStatus(ctx, runID, "failed")
+			continue
+		}
+		if err := s.sched.Enqueue(ctx, runID, instTypes[req.InstanceTypeName].Family, req.UserID, req.Priority); err != nil {
+			enqueueErr = err
+			_ = s.repo.UpdateRunStatus(ctx, runID, "failed")
+		}
+	}
+	if enqueueErr != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "enqueue run failed"})
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"experiment_id": experimentID,
+		"run_ids":       runIDs,
+	})
+}
+
+// handleGetExperiment returns an experiment's metadata alongside every
+// child run tagged with its arm, so a caller (or internal/analyzer, by
+// picking out a control/treatment pair of run IDs) can inspect the whole
+// experiment without tracking each arm's runs separately.
+func (s *Server) handleGetExperiment(w http.ResponseWriter, r *http.Request) {
+	experimentID := r.PathValue("id")
+	ctx := r.Context()
+
+	exp, err := s.repo.GetExperiment(ctx, experimentID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
+		return
+	}
+	if exp == nil {
+		writeError(w, &apiError{http.StatusNotFound, codeExperimentNotFound, "experiment not found"})
+		return
+	}
+
+	runs, err := s.repo.ListRunsByExperiment(ctx, experimentID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "list experiment runs failed"})
+		return
+	}
+
+	statusCounts := make(map[string]int)
+	byArm := make(map[string][]database.ExperimentRunDetail)
+	for _, run := range runs {
+		statusCounts[run.Status]++
+		byArm[run.Arm] = append(byArm[run.Arm], run)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"experiment_id": exp.ID,
+		"created_at":    exp.CreatedAt,
+		"total_runs":    len(runs),
+		"status_counts": statusCounts,
+		"runs":          runs,
+		"arms":          byArm,
+	})
+}
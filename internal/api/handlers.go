@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
+	"net/url"
 	"errors"
 	"os"
 	"sort"
@@ -13,8 +13,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/accelbench/accelbench/internal/callbacks"
 	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/metrics"
 	"github.com/accelbench/accelbench/internal/orchestrator"
+	"github.com/accelbench/accelbench/internal/policy"
+	"github.com/accelbench/accelbench/internal/pricing"
+	"github.com/accelbench/accelbench/internal/reaper"
 	"github.com/accelbench/accelbench/internal/recommend"
 
 	batchv1 "k8s.io/api/batch/v1"
@@ -26,47 +31,284 @@ import (
 
 // Server holds dependencies for API handlers.
 type Server struct {
-	repo     database.Repo
-	orch     *orchestrator.Orchestrator
-	client   kubernetes.Interface
-	hfClient *recommend.HFClient
+	repo        database.Repo
+	orch        *orchestrator.Orchestrator
+	sched       *orchestrator.Scheduler
+	policyDisp  *policy.Dispatcher
+	callbacksWk *callbacks.Worker
+	reaper      *reaper.Reaper
+	client      kubernetes.Interface
+	hfClient    *recommend.HFClient
+	authz       AuthzPolicy
+	idempotency *database.IdempotencyStore
+	metrics     *apiMetrics
+	metricsReg  metrics.Registry
+
+	pricingProvider pricing.Provider
+	pricingSource   string
+
+	maxRunTimeoutSeconds int
+}
+
+// defaultSchedulerMaxRetries bounds how many times the scheduler
+// automatically retries a run that fails during execution, with
+// exponential backoff between attempts (see orchestrator.WithMaxRetries).
+const defaultSchedulerMaxRetries = 3
+
+// NewServer creates a new API server. Mutating endpoints are open to any
+// caller by default (allowAllPolicy); pass WithAuthzPolicy to gate them,
+// e.g. with a CNAuthzPolicy on a server also reachable over mTLS. The
+// run scheduler is started against a detached context so it keeps
+// dispatching queued runs independent of any single request's lifetime.
+// The orchestrator, scheduler, and API handlers all emit instruments
+// against the same metrics.Registry so GET /metrics exposes one coherent
+// SRE view of the whole control plane.
+func NewServer(repo database.Repo, client kubernetes.Interface, opts ...ServerOption) *Server {
+	reg := metrics.NewPrometheusRegistry()
+	apiM := newAPIMetrics(reg)
+	s := &Server{
+		repo:                 repo,
+		client:               client,
+		hfClient:             recommend.NewHFClient(),
+		authz:                allowAllPolicy{},
+		idempotency:          database.NewIdempotencyStore(defaultIdempotencyTTL),
+		metrics:              apiM,
+		metricsReg:           reg,
+		maxRunTimeoutSeconds: defaultMaxRunTimeoutSeconds,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	orchOpts := []orchestrator.Option{
+		orchestrator.WithMetricsRegistry(reg),
+		orchestrator.WithRunObserver(runObserver{apiM}),
+	}
+	if s.pricingProvider != nil {
+		orchOpts = append(orchOpts, orchestrator.WithPricingProvider(s.pricingSource, s.pricingProvider))
+	}
+	s.orch = orchestrator.New(client, repo, orchOpts...)
+	s.sched = orchestrator.NewScheduler(s.orch, repo,
+		orchestrator.WithSchedulerMetricsRegistry(reg),
+		orchestrator.WithMaxRetries(defaultSchedulerMaxRetries),
+	)
+	s.sched.Start(context.Background())
+	s.policyDisp = policy.NewDispatcher(repo, s.sched)
+	s.policyDisp.Start(context.Background())
+	s.callbacksWk = callbacks.NewWorker(repo)
+	s.callbacksWk.Start(context.Background())
+	s.reaper = reaper.New(repo, s.orch)
+	s.reaper.Start(context.Background())
+	return s
+}
+
+// ServerOption configures optional Server behavior at construction time.
+type ServerOption func(*Server)
+
+// WithAuthzPolicy overrides the default allow-all policy gating every
+// mutating endpoint wrapped in requireAuthz (run, sweep, experiment,
+// SLO-search and autoscale creation; run cancel, delete, priority,
+// requeue and deadline changes; catalog health repair; policy writes).
+func WithAuthzPolicy(policy AuthzPolicy) ServerOption {
+	return func(s *Server) { s.authz = policy }
 }
 
-// NewServer creates a new API server.
-func NewServer(repo database.Repo, client kubernetes.Interface) *Server {
-	return &Server{
-		repo:     repo,
-		orch:     orchestrator.New(client, repo),
-		client:   client,
-		hfClient: recommend.NewHFClient(),
+// WithPricingProvider configures the rate source the orchestrator queries
+// at run-completion time to stamp a cost efficiency snapshot onto each
+// run's BenchmarkMetrics (see orchestrator.WithPricingProvider and
+// metrics.PricingContext). source is recorded alongside the snapshot
+// (e.g. "aws", "static") so it can later be told apart from a live
+// lookup. Left unset by default, which leaves the cost columns nil
+// rather than failing runs.
+func WithPricingProvider(source string, provider pricing.Provider) ServerOption {
+	return func(s *Server) {
+		s.pricingSource = source
+		s.pricingProvider = provider
 	}
 }
 
 // RegisterRoutes registers all API routes on the given mux.
 func (s *Server) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /api/v1/catalog", s.handleListCatalog)
-	mux.HandleFunc("POST /api/v1/runs", s.handleCreateRun)
+	mux.HandleFunc("POST /api/v1/runs", s.requireAuthz(s.handleCreateRun))
+	mux.HandleFunc("POST /api/v1/runs:batch", s.requireAuthz(s.handleCreateSweep))
+	mux.HandleFunc("GET /api/v1/sweeps/{id}", s.handleGetSweep)
+	mux.HandleFunc("GET /api/v1/sweeps/{id}/report", s.handleGetSweepReport)
+	mux.HandleFunc("POST /api/v1/experiments", s.requireAuthz(s.handleCreateExperiment))
+	mux.HandleFunc("GET /api/v1/experiments/{id}", s.handleGetExperiment)
+	mux.HandleFunc("POST /api/v1/runs:slo-search", s.requireAuthz(s.handleCreateSLOSearch))
+	mux.HandleFunc("GET /api/v1/runs/{id}/slo-search", s.handleGetSLOSearch)
+	mux.HandleFunc("POST /api/v1/runs:autoscale", s.requireAuthz(s.handleCreateAutoscale))
+	mux.HandleFunc("GET /api/v1/runs/{id}/autoscale", s.handleGetAutoscale)
+	mux.HandleFunc("GET /api/v1/runs/compare", s.handleCompareRuns)
 	mux.HandleFunc("GET /api/v1/runs/{id}", s.handleGetRun)
 	mux.HandleFunc("GET /api/v1/runs/{id}/metrics", s.handleGetMetrics)
+	mux.HandleFunc("GET /api/v1/runs/{id}/events", s.handleRunEvents)
 	mux.HandleFunc("GET /api/v1/jobs", s.handleListRuns)
-	mux.HandleFunc("POST /api/v1/runs/{id}/cancel", s.handleCancelRun)
-	mux.HandleFunc("DELETE /api/v1/runs/{id}", s.handleDeleteRun)
+	mux.HandleFunc("POST /api/v1/runs/{id}/cancel", s.requireAuthz(s.handleCancelRun))
+	mux.HandleFunc("DELETE /api/v1/runs/{id}", s.requireAuthz(s.handleDeleteRun))
+	mux.HandleFunc("GET /api/v1/queue", s.handleListQueue)
+	mux.HandleFunc("POST /api/v1/runs/{id}/priority", s.requireAuthz(s.handleSetRunPriority))
+	mux.HandleFunc("POST /api/v1/runs/{id}/requeue", s.requireAuthz(s.handleRequeueRun))
+	mux.HandleFunc("PATCH /api/v1/runs/{id}/deadline", s.requireAuthz(s.handleExtendRunDeadline))
 	mux.HandleFunc("GET /api/v1/instance-types", s.handleListInstanceTypes)
 	mux.HandleFunc("GET /api/v1/pricing", s.handleListPricing)
+	mux.HandleFunc("GET /api/v1/pricing/history", s.handlePricingHistory)
 	mux.HandleFunc("GET /api/v1/recommend", s.handleRecommend)
 	mux.HandleFunc("POST /api/v1/catalog/seed", s.handleCatalogSeed)
 	mux.HandleFunc("GET /api/v1/catalog/seed", s.handleCatalogSeedStatus)
+	mux.HandleFunc("GET /api/v1/catalog/health", s.handleCatalogHealth)
+	mux.HandleFunc("POST /api/v1/catalog/health/repair", s.requireAuthz(s.handleCatalogHealthRepair))
+	mux.HandleFunc("GET /api/v1/query", s.handleQuery)
+	mux.HandleFunc("GET /api/v1/query_range", s.handleQueryRange)
+	mux.HandleFunc("POST /api/v1/policies", s.requireAuthz(s.handleCreatePolicy))
+	mux.HandleFunc("GET /api/v1/policies", s.handleListPolicies)
+	mux.HandleFunc("PUT /api/v1/policies/{id}", s.requireAuthz(s.handleUpdatePolicy))
+	mux.HandleFunc("DELETE /api/v1/policies/{id}", s.requireAuthz(s.handleDeletePolicy))
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
 }
 
 func (s *Server) handleListCatalog(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
+	f := catalogFilterFromQuery(q)
+	f.SortBy = q.Get("sort")
+	f.SortDesc = q.Get("order") == "desc"
+
+	entries, total, err := s.repo.ListCatalog(r.Context(), f)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "catalog query failed"})
+		return
+	}
+	if entries == nil {
+		entries = []database.CatalogEntry{}
+	}
+
+	region := q.Get("region")
+	if region == "" {
+		region = "us-east-2"
+	}
+	if err := applyCostDeltas(r.Context(), s.repo, region, entries); err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "cost delta computation failed"})
+		return
+	}
+
+	limit := 100
+	if f.Limit > 0 && f.Limit <= 500 {
+		limit = f.Limit
+	}
+	writePaginationHeaders(w, r, limit, f.Offset, total)
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// applyCostDeltas sets CostDeltaPct on each entry that has both a
+// CompletedAt and a currently-priced instance type: the percentage
+// change between the on-demand rate in effect when the run completed and
+// today's rate in region. Historical lookups are cached per completion
+// day so entries that finished on the same day share one ListPricingAt
+// call instead of issuing one per entry.
+func applyCostDeltas(ctx context.Context, repo database.Repo, region string, entries []database.CatalogEntry) error {
+	current, err := repo.ListPricing(ctx, region)
+	if err != nil {
+		return err
+	}
+	currentByName := make(map[string]float64, len(current))
+	for _, row := range current {
+		currentByName[row.InstanceTypeName] = row.OnDemandHourlyUSD
+	}
+
+	historicalByDay := make(map[string]map[string]float64)
+	for i := range entries {
+		e := &entries[i]
+		if e.CompletedAt == nil {
+			continue
+		}
+		curRate, ok := currentByName[e.InstanceTypeName]
+		if !ok {
+			continue
+		}
+
+		day := e.CompletedAt.Format("2006-01-02")
+		byName, ok := historicalByDay[day]
+		if !ok {
+			rows, err := repo.ListPricingAt(ctx, region, *e.CompletedAt)
+			if err != nil {
+				return err
+			}
+			byName = make(map[string]float64, len(rows))
+			for _, row := range rows {
+				byName[row.InstanceTypeName] = row.OnDemandHourlyUSD
+			}
+			historicalByDay[day] = byName
+		}
+
+		histRate, ok := byName[e.InstanceTypeName]
+		if !ok || histRate == 0 {
+			continue
+		}
+		delta := (curRate - histRate) / histRate * 100
+		e.CostDeltaPct = &delta
+	}
+	return nil
+}
+
+// handlePricingHistory returns the full pricing time series recorded for
+// one instance type in one region, the detail view behind the single
+// percentage ListCatalog's cost_delta_pct summarizes.
+func (s *Server) handlePricingHistory(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	instanceType := q.Get("instance_type")
+	if instanceType == "" {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidQuery, "instance_type query parameter is required"})
+		return
+	}
+	region := q.Get("region")
+	if region == "" {
+		region = "us-east-2"
+	}
+	var since time.Time
+	if v := q.Get("since"); v != "" {
+		t, err := parsePromTime(v)
+		if err != nil {
+			writeError(w, &apiError{http.StatusBadRequest, codeInvalidQuery, "invalid since: " + err.Error()})
+			return
+		}
+		since = t
+	}
+
+	it, err := s.repo.GetInstanceTypeByName(r.Context(), instanceType)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "instance type lookup failed"})
+		return
+	}
+	if it == nil {
+		writeError(w, &apiError{http.StatusNotFound, codeInstanceTypeNotFound, fmt.Sprintf("instance type %s not found", instanceType)})
+		return
+	}
+
+	rows, err := s.repo.PricingHistory(r.Context(), it.ID, region, since)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "pricing history query failed"})
+		return
+	}
+	if rows == nil {
+		rows = []database.PricingRow{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"instance_type": instanceType,
+		"region":        region,
+		"history":       rows,
+	})
+}
+
+// catalogFilterFromQuery builds a CatalogFilter from the same query
+// parameters handleListCatalog accepts, shared with handleCatalogHealth
+// and handleCatalogHealthRepair so a health scan can be scoped the same
+// way a catalog query is.
+func catalogFilterFromQuery(q url.Values) database.CatalogFilter {
 	f := database.CatalogFilter{
 		ModelHfID:       q.Get("model"),
 		ModelFamily:     q.Get("model_family"),
 		InstanceFamily:  q.Get("instance_family"),
 		AcceleratorType: q.Get("accelerator_type"),
-		SortBy:          q.Get("sort"),
-		SortDesc:        q.Get("order") == "desc",
 	}
 	if v := q.Get("limit"); v != "" {
 		fmt.Sscanf(v, "%d", &f.Limit)
@@ -74,42 +316,260 @@ func (s *Server) handleListCatalog(w http.ResponseWriter, r *http.Request) {
 	if v := q.Get("offset"); v != "" {
 		fmt.Sscanf(v, "%d", &f.Offset)
 	}
+	// Keyset cursor for the query command's --all mode: after_sort_value
+	// arrives as whatever string form the client formatted the previous
+	// page's last entry into (see client.formatCursorValue), and is passed
+	// through to Repository.ListCatalog/ListCatalogStream as-is — Postgres
+	// resolves it against whatever column "sort" (or the default
+	// completed_at) names.
+	if v := q.Get("after_run_id"); v != "" {
+		f.AfterRunID = v
+		f.AfterSortValue = q.Get("after_sort_value")
+	}
+	return f
+}
 
-	entries, err := s.repo.ListCatalog(r.Context(), f)
+// handleCatalogHealth runs VerifyRun's integrity checks over every run
+// matching the query's catalog filters, for an operator to scan the
+// catalog for silently-broken entries before they contaminate comparisons.
+func (s *Server) handleCatalogHealth(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.repo.GatherCatalogHealth(r.Context(), catalogFilterFromQuery(r.URL.Query()))
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "catalog query failed")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "catalog health scan failed"})
 		return
 	}
-	if entries == nil {
-		entries = []database.CatalogEntry{}
+	if stats == nil {
+		stats = []database.RunHealthStats{}
 	}
-	writeJSON(w, http.StatusOK, entries)
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// handleCatalogHealthRepair is handleCatalogHealth's mutating counterpart:
+// it supersedes every unhealthy run it finds instead of just reporting on
+// them, recording why via HealthFailureReason rather than deleting the run.
+func (s *Server) handleCatalogHealthRepair(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	stats, err := s.repo.GatherCatalogHealth(ctx, catalogFilterFromQuery(r.URL.Query()))
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "catalog health scan failed"})
+		return
+	}
+
+	var repaired []string
+	for _, stat := range stats {
+		if stat.Healthy {
+			continue
+		}
+		reason := strings.Join(stat.FailureReasons, "; ")
+		if err := s.repo.MarkRunHealthFailure(ctx, stat.RunID, reason); err != nil {
+			writeError(w, &apiError{http.StatusInternalServerError, codeInternal, fmt.Sprintf("repair run %s failed", stat.RunID)})
+			return
+		}
+		repaired = append(repaired, stat.RunID)
+	}
+	if repaired == nil {
+		repaired = []string{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"repaired_run_ids": repaired})
+}
+
+// promResult and promVectorSample/promSeries mirror the shape of
+// Prometheus's own /api/v1/query and /api/v1/query_range responses
+// (status/data/resultType/result, with values encoded as
+// [unix_seconds, "stringified float"]), so existing PromQL tooling that
+// only speaks that wire format can point at this API unmodified.
+type promResult struct {
+	Status string   `json:"status"`
+	Data   promData `json:"data"`
+}
+
+type promData struct {
+	ResultType string `json:"resultType"`
+	Result     any    `json:"result"`
+}
+
+type promVectorSample struct {
+	Metric database.LabelSet `json:"metric"`
+	Value  [2]any            `json:"value"`
+}
+
+type promSeries struct {
+	Metric database.LabelSet `json:"metric"`
+	Values [][2]any          `json:"values"`
+}
+
+func promValue(ts time.Time, v float64) [2]any {
+	return [2]any{float64(ts.Unix()), strconv.FormatFloat(v, 'f', -1, 64)}
+}
+
+// handleQuery implements Prometheus's instant /api/v1/query: evaluate
+// query as of time (defaulting to now) and return a vector result.
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	expr := q.Get("query")
+	if expr == "" {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidQuery, "query parameter is required"})
+		return
+	}
+	at, err := parsePromTime(q.Get("time"))
+	if err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidQuery, err.Error()})
+		return
+	}
+
+	vec, err := s.repo.QueryCatalog(r.Context(), expr, at)
+	if err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidQuery, err.Error()})
+		return
+	}
+
+	result := make([]promVectorSample, len(vec))
+	for i, sample := range vec {
+		result[i] = promVectorSample{Metric: sample.Labels, Value: promValue(at, sample.Value)}
+	}
+	writeJSON(w, http.StatusOK, promResult{Status: "success", Data: promData{ResultType: "vector", Result: result}})
+}
+
+// handleQueryRange implements Prometheus's /api/v1/query_range: evaluate
+// query at each step between start and end and return a matrix result.
+func (s *Server) handleQueryRange(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	expr := q.Get("query")
+	if expr == "" {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidQuery, "query parameter is required"})
+		return
+	}
+	start, err := parsePromTime(q.Get("start"))
+	if err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidQuery, "invalid start: " + err.Error()})
+		return
+	}
+	end, err := parsePromTime(q.Get("end"))
+	if err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidQuery, "invalid end: " + err.Error()})
+		return
+	}
+	step, err := parsePromDuration(q.Get("step"))
+	if err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidQuery, "invalid step: " + err.Error()})
+		return
+	}
+
+	matrix, err := s.repo.QueryCatalogRange(r.Context(), expr, start, end, step)
+	if err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidQuery, err.Error()})
+		return
+	}
+
+	result := make([]promSeries, len(matrix))
+	for i, series := range matrix {
+		values := make([][2]any, len(series.Points))
+		for j, p := range series.Points {
+			values[j] = promValue(p.Timestamp, p.Value)
+		}
+		result[i] = promSeries{Metric: series.Labels, Values: values}
+	}
+	writeJSON(w, http.StatusOK, promResult{Status: "success", Data: promData{ResultType: "matrix", Result: result}})
+}
+
+// parsePromTime parses a time parameter the way Prometheus's HTTP API
+// does: either RFC 3339 or a unix timestamp in (possibly fractional)
+// seconds. An empty string means "now".
+func parsePromTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Now(), nil
+	}
+	if ts, err := strconv.ParseFloat(s, 64); err == nil {
+		sec := int64(ts)
+		nsec := int64((ts - float64(sec)) * float64(time.Second))
+		return time.Unix(sec, nsec), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("time must be RFC3339 or a unix timestamp: %w", err)
+	}
+	return t, nil
+}
+
+// parsePromDuration parses a step parameter as either a Go duration
+// string ("30s") or plain decimal seconds ("30"), matching Prometheus's
+// own query_range step parameter.
+func parsePromDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("step is required")
+	}
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("step must be a duration or decimal seconds: %w", err)
+	}
+	return d, nil
 }
 
 func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 	var req database.RunRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidRunRequest, "invalid request body"})
 		return
 	}
 
 	ctx := r.Context()
 
+	// Prefer the caller's verified mTLS client certificate CN over
+	// whatever UserID the request body self-reports, so a malicious or
+	// misconfigured client can't borrow another user's Quota.MaxConcurrentPerUser
+	// allowance by claiming their ID.
+	if cn := verifiedUserID(r); cn != "" {
+		req.UserID = cn
+	}
+
+	// Replay guard: a client retrying a POST it's unsure reached the
+	// server shouldn't launch a second benchmark run. The same key with a
+	// different body is a client bug (or a key collision) and is rejected
+	// rather than silently returning the wrong run.
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	var requestHash string
+	if idempotencyKey != "" {
+		requestHash = hashRunRequest(&req)
+		if rec := s.idempotency.Lookup(clientID(r), idempotencyKey); rec != nil {
+			if rec.RequestHash != requestHash {
+				writeError(w, &apiError{http.StatusConflict, codeIdempotencyConflict, "Idempotency-Key was already used with a different request body"})
+				return
+			}
+			run, err := s.repo.GetBenchmarkRun(ctx, rec.RunID)
+			if err != nil {
+				writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
+				return
+			}
+			if run == nil {
+				writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "idempotency record refers to a missing run"})
+				return
+			}
+			writeJSON(w, http.StatusAccepted, map[string]string{
+				"id":     run.ID,
+				"status": run.Status,
+			})
+			return
+		}
+	}
+
 	// Look up or auto-register model.
 	model, err := s.repo.EnsureModel(ctx, req.ModelHfID, req.ModelHfRevision)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "ensure model failed")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "ensure model failed"})
 		return
 	}
 
 	// Look up instance type.
 	instType, err := s.repo.GetInstanceTypeByName(ctx, req.InstanceTypeName)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "lookup instance type failed")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "lookup instance type failed"})
 		return
 	}
 	if instType == nil {
-		writeError(w, http.StatusNotFound, fmt.Sprintf("instance type %s not found", req.InstanceTypeName))
+		writeError(w, &apiError{http.StatusNotFound, codeInstanceTypeNotFound, fmt.Sprintf("instance type %s not found", req.InstanceTypeName)})
 		return
 	}
 
@@ -127,58 +587,229 @@ func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 		DatasetName:          req.DatasetName,
 		RunType:              req.RunType,
 		MinDurationSeconds:   req.MinDurationSeconds,
-		Status:               "pending",
+		DatasetSpec:          req.DatasetSpec,
+		Status:               "queued",
+		OriginalRequest:      &req,
+		CallbackURL:          req.CallbackURL,
+		CallbackToken:        req.CallbackToken,
+		Trigger:              "manual",
+		TimeoutSeconds:       s.resolveRunTimeout(req.TimeoutSeconds),
 	}
 
 	runID, err := s.repo.CreateBenchmarkRun(ctx, run)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "create run failed")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "create run failed"})
 		return
 	}
 
-	// Launch orchestration in the background with a detached context
-	// so it isn't canceled when the HTTP response is sent.
-	go func() {
-		cfg := orchestrator.RunConfig{
-			RunID:        runID,
-			Model:        model,
-			InstanceType: instType,
-			Request:      &req,
-		}
-		if err := s.orch.Execute(context.Background(), cfg); err != nil {
-			log.Printf("benchmark run %s failed: %v", runID, err)
-		}
-	}()
+	if idempotencyKey != "" {
+		s.idempotency.Store(clientID(r), idempotencyKey, requestHash, runID)
+	}
+
+	// Admit the run into the scheduler's persistent queue instead of
+	// launching it directly, so a burst of submissions against a scarce
+	// instance family is capped rather than dispatched all at once.
+	if err := s.sched.Enqueue(ctx, runID, instType.Family, req.UserID, req.Priority); err != nil {
+		// The run row already exists but never made it into run_queue;
+		// mark it failed rather than leaving it stuck at "queued" with
+		// nothing that will ever dispatch it, so a client retrying the
+		// same Idempotency-Key doesn't just get the stuck run echoed back.
+		_ = s.repo.UpdateRunStatus(ctx, runID, "failed")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "enqueue run failed"})
+		return
+	}
+	s.metrics.runsTotal.WithLabelValues("created").Inc()
 
 	writeJSON(w, http.StatusAccepted, map[string]string{
 		"id":     runID,
-		"status": "pending",
+		"status": "queued",
 	})
 }
 
 func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
 	runID := r.PathValue("id")
+
+	// ?watch=true&since=<rev> turns this into a long-poll: block until
+	// the run changes instead of returning its current snapshot.
+	if r.URL.Query().Get("watch") == "true" {
+		s.handleGetRunWatch(w, r, runID)
+		return
+	}
+
 	run, err := s.repo.GetBenchmarkRun(r.Context(), runID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "query failed")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
 		return
 	}
 	if run == nil {
-		writeError(w, http.StatusNotFound, "run not found")
+		writeError(w, &apiError{http.StatusNotFound, codeRunNotFound, "run not found"})
 		return
 	}
-	writeJSON(w, http.StatusOK, run)
+	w.Header().Set("ETag", runETag(run))
+	writeJSON(w, http.StatusOK, redactRun(run))
+}
+
+// redactRun returns a shallow copy of run with its OriginalRequest's
+// HfToken cleared, so a run fetched over the API never echoes back a
+// caller's Hugging Face credential. The unredacted token stays in the
+// repo for the scheduler to use when rebuilding this run's RunConfig.
+func redactRun(run *database.BenchmarkRun) *database.BenchmarkRun {
+	if run.OriginalRequest == nil || run.OriginalRequest.HfToken == "" {
+		return run
+	}
+	redacted := *run
+	req := *run.OriginalRequest
+	req.HfToken = ""
+	redacted.OriginalRequest = &req
+	return &redacted
+}
+
+// longPollTimeout bounds how long handleGetRunWatch blocks waiting for a
+// change before returning the run's current state, so a client that never
+// sees an update still gets a timely response instead of hanging forever.
+const longPollTimeout = 30 * time.Second
+
+// handleGetRunWatch blocks until runID's status or metrics change after
+// the since revision (or longPollTimeout elapses), then returns the
+// run's current state — a long-poll analogue of handleRunEvents for
+// clients that can't or don't want to consume an SSE stream.
+func (s *Server) handleGetRunWatch(w http.ResponseWriter, r *http.Request, runID string) {
+	ctx, cancel := context.WithTimeout(r.Context(), longPollTimeout)
+	defer cancel()
+
+	sinceRev := parseRev(r.URL.Query().Get("since"))
+	events, err := s.repo.Watch(ctx, runID, sinceRev)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "watch failed"})
+		return
+	}
+
+	select {
+	case ev, ok := <-events:
+		if ok {
+			w.Header().Set("Last-Event-ID", strconv.FormatUint(ev.Rev, 10))
+		}
+	case <-ctx.Done():
+	}
+
+	run, err := s.repo.GetBenchmarkRun(r.Context(), runID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
+		return
+	}
+	if run == nil {
+		writeError(w, &apiError{http.StatusNotFound, codeRunNotFound, "run not found"})
+		return
+	}
+	w.Header().Set("ETag", runETag(run))
+	writeJSON(w, http.StatusOK, redactRun(run))
+}
+
+// handleRunEvents streams lifecycle and metrics updates for a benchmark
+// run as Server-Sent Events. A client that reconnects after a drop can
+// resume without missing events by sending back the last "id:" field it
+// saw, either as a Last-Event-ID header (per the SSE spec) or a since
+// query parameter.
+func (s *Server) handleRunEvents(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+	run, err := s.repo.GetBenchmarkRun(r.Context(), runID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
+		return
+	}
+	if run == nil {
+		writeError(w, &apiError{http.StatusNotFound, codeRunNotFound, "run not found"})
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, &apiError{http.StatusInternalServerError, codeStreamingUnsupported, "streaming unsupported"})
+		return
+	}
+
+	sinceRev := parseRev(r.Header.Get("Last-Event-ID"))
+	if sinceRev == 0 {
+		sinceRev = parseRev(r.URL.Query().Get("since"))
+	}
+
+	events, err := s.repo.Watch(r.Context(), runID, sinceRev)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "watch failed"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	idle := newDeadlineTimer(sseIdleTimeout)
+	defer idle.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-idle.C():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			idle.Reset(sseIdleTimeout)
+			writeSSEEvent(w, ev)
+			flusher.Flush()
+			if ev.Status == "completed" || ev.Status == "failed" {
+				return
+			}
+		}
+	}
+}
+
+// sseIdleTimeout bounds how long handleRunEvents keeps a connection open
+// with no events flowing, so a slow or abandoned consumer doesn't pin a
+// goroutine and a Watch subscription for the lifetime of the process.
+const sseIdleTimeout = 2 * time.Minute
+
+func writeSSEEvent(w http.ResponseWriter, ev database.RunEvent) {
+	fields := map[string]any{
+		"run_id": ev.RunID,
+	}
+	if ev.Status != "" {
+		fields["status"] = ev.Status
+	}
+	if ev.Metrics != nil {
+		fields["metrics"] = ev.Metrics
+	}
+	if ev.LogLine != "" {
+		fields["log_line"] = ev.LogLine
+	}
+	payload, _ := json.Marshal(fields)
+	fmt.Fprintf(w, "id: %d\nevent: run-update\ndata: %s\n\n", ev.Rev, payload)
+}
+
+func parseRev(s string) uint64 {
+	if s == "" {
+		return 0
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
 }
 
 func (s *Server) handleGetMetrics(w http.ResponseWriter, r *http.Request) {
 	runID := r.PathValue("id")
 	m, err := s.repo.GetMetricsByRunID(r.Context(), runID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "query failed")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
 		return
 	}
 	if m == nil {
-		writeError(w, http.StatusNotFound, "metrics not found")
+		writeError(w, &apiError{http.StatusNotFound, codeMetricsNotFound, "metrics not found"})
 		return
 	}
 	writeJSON(w, http.StatusOK, m)
@@ -186,9 +817,48 @@ func (s *Server) handleGetMetrics(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleListRuns(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
+	f, err := runFilterFromQuery(q)
+	if err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidQuery, err.Error()})
+		return
+	}
+
+	items, total, err := s.repo.ListRuns(r.Context(), f)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "list runs failed"})
+		return
+	}
+	if items == nil {
+		items = []database.RunListItem{}
+	}
+
+	limit := 50
+	if f.Limit > 0 && f.Limit <= 200 {
+		limit = f.Limit
+	}
+	writePaginationHeaders(w, r, limit, f.Offset, total)
+	writeJSON(w, http.StatusOK, items)
+}
+
+// runFilterFromQuery builds a database.RunFilter from handleListRuns'
+// query parameters. statuses is a comma-separated list, an OR'd
+// alternative to the single-value status; created_after/created_before
+// and completed_after/completed_before are RFC3339 timestamps; a
+// keyset cursor arrives as after_created_at/after_id, the RFC3339Nano
+// created_at and ID of the last run the caller saw on the previous page
+// (see client.Client.ListRuns).
+func runFilterFromQuery(q url.Values) (database.RunFilter, error) {
 	f := database.RunFilter{
-		Status:  q.Get("status"),
-		ModelID: q.Get("model"),
+		Status:          q.Get("status"),
+		ModelID:         q.Get("model"),
+		PolicyID:        q.Get("policy_id"),
+		Trigger:         q.Get("trigger"),
+		Framework:       q.Get("framework"),
+		InstanceFamily:  q.Get("instance_family"),
+		AcceleratorType: q.Get("accelerator_type"),
+	}
+	if v := q.Get("statuses"); v != "" {
+		f.Statuses = strings.Split(v, ",")
 	}
 	if v := q.Get("limit"); v != "" {
 		if n, err := strconv.Atoi(v); err == nil {
@@ -201,44 +871,161 @@ func (s *Server) handleListRuns(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	items, err := s.repo.ListRuns(r.Context(), f)
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, "list runs failed")
-		return
+	var err error
+	if f.CreatedAfter, err = parseRunFilterTime(q.Get("created_after")); err != nil {
+		return f, fmt.Errorf("invalid created_after: %w", err)
 	}
-	if items == nil {
-		items = []database.RunListItem{}
+	if f.CreatedBefore, err = parseRunFilterTime(q.Get("created_before")); err != nil {
+		return f, fmt.Errorf("invalid created_before: %w", err)
 	}
-	writeJSON(w, http.StatusOK, items)
+	if f.CompletedAfter, err = parseRunFilterTime(q.Get("completed_after")); err != nil {
+		return f, fmt.Errorf("invalid completed_after: %w", err)
+	}
+	if f.CompletedBefore, err = parseRunFilterTime(q.Get("completed_before")); err != nil {
+		return f, fmt.Errorf("invalid completed_before: %w", err)
+	}
+
+	if v := q.Get("after_id"); v != "" {
+		after, err := parseRunFilterTime(q.Get("after_created_at"))
+		if err != nil {
+			return f, fmt.Errorf("invalid after_created_at: %w", err)
+		}
+		f.AfterID = v
+		f.AfterCreatedAt = after
+	}
+
+	return f, nil
+}
+
+// parseRunFilterTime parses an RFC3339 timestamp, returning the zero
+// time.Time (meaning "no bound") for an empty string.
+func parseRunFilterTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
 }
 
 func (s *Server) handleCancelRun(w http.ResponseWriter, r *http.Request) {
 	runID := r.PathValue("id")
 	ctx := r.Context()
 
-	run, err := s.repo.GetBenchmarkRun(ctx, runID)
+	// An If-Match precondition is a best-effort check against the run as
+	// last seen by the caller; the queued/pending/running -> failed transition
+	// below is still a compare-and-swap enforced by the repo itself, so a
+	// cancel racing a completion notification from the orchestrator can
+	// never clobber a terminal status even without If-Match.
+	if r.Header.Get("If-Match") != "" {
+		run, err := s.repo.GetBenchmarkRun(ctx, runID)
+		if err != nil {
+			writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
+			return
+		}
+		if run == nil {
+			writeError(w, &apiError{http.StatusNotFound, codeRunNotFound, "run not found"})
+			return
+		}
+		if apiErr := checkIfMatch(r, run); apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+	}
+
+	err := s.repo.CancelRun(ctx, runID)
+	switch {
+	case err == nil:
+	case errors.Is(err, database.ErrRunNotFound):
+		writeError(w, &apiError{http.StatusNotFound, codeRunNotFound, "run not found"})
+		return
+	case errors.Is(err, database.ErrRunNotCancellable):
+		run, _ := s.repo.GetBenchmarkRun(ctx, runID)
+		status := ""
+		if run != nil {
+			status = run.Status
+		}
+		writeError(w, &apiError{http.StatusConflict, codeRunNotCancellable, fmt.Sprintf("cannot cancel run with status %q", status)})
+		return
+	default:
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "cancel run failed"})
+		return
+	}
+
+	// Cancel the orchestrator goroutine if it's running.
+	s.orch.CancelRun(runID)
+	s.metrics.runsTotal.WithLabelValues("canceled").Inc()
+
+	writeJSON(w, http.StatusOK, map[string]string{"id": runID, "status": "failed"})
+}
+
+// handleListQueue returns every run currently waiting in the scheduler's
+// admission queue, in dispatch order: priority descending, then enqueue
+// time ascending within a priority tier.
+func (s *Server) handleListQueue(w http.ResponseWriter, r *http.Request) {
+	queued, err := s.repo.ListQueuedRuns(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "query failed")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "list queued runs failed"})
 		return
 	}
-	if run == nil {
-		writeError(w, http.StatusNotFound, "run not found")
+	if queued == nil {
+		queued = []database.QueuedRun{}
+	}
+	writeJSON(w, http.StatusOK, queued)
+}
+
+// priorityRequest is the body of POST /runs/{id}/priority.
+type priorityRequest struct {
+	Priority int `json:"priority"`
+}
+
+// handleSetRunPriority updates a still-queued run's priority, for
+// promoting a run ahead of others waiting on the same instance family.
+func (s *Server) handleSetRunPriority(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+
+	var req priorityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidPriorityRequest, "invalid request body"})
 		return
 	}
-	if run.Status != "pending" && run.Status != "running" {
-		writeError(w, http.StatusConflict, fmt.Sprintf("cannot cancel run with status %q", run.Status))
+
+	err := s.sched.SetPriority(r.Context(), runID, req.Priority)
+	switch {
+	case err == nil:
+	case errors.Is(err, database.ErrRunNotQueued):
+		writeError(w, &apiError{http.StatusConflict, codeRunNotQueued, "run is not currently queued"})
+		return
+	default:
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "set run priority failed"})
 		return
 	}
 
-	// Cancel the orchestrator goroutine if it's running.
-	s.orch.CancelRun(runID)
+	writeJSON(w, http.StatusOK, map[string]any{"id": runID, "priority": req.Priority})
+}
+
+// handleRequeueRun re-admits a completed, failed, or canceled run into the
+// scheduler's queue using its persisted original request, so a run can be
+// resubmitted without the client having to reconstruct the full body.
+func (s *Server) handleRequeueRun(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+
+	var req priorityRequest
+	_ = json.NewDecoder(r.Body).Decode(&req) // priority is optional; zero value is fine
 
-	if err := s.repo.UpdateRunStatus(ctx, runID, "failed"); err != nil {
-		writeError(w, http.StatusInternalServerError, "update status failed")
+	err := s.sched.Requeue(r.Context(), runID, req.Priority)
+	switch {
+	case err == nil:
+	case errors.Is(err, database.ErrRunNotFound):
+		writeError(w, &apiError{http.StatusNotFound, codeRunNotFound, "run not found"})
+		return
+	case errors.Is(err, database.ErrRunNotRequeueable):
+		writeError(w, &apiError{http.StatusConflict, codeRunNotRequeueable, "run is still pending, running, or queued"})
+		return
+	default:
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "requeue run failed"})
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]string{"id": runID, "status": "failed"})
+	writeJSON(w, http.StatusAccepted, map[string]string{"id": runID, "status": "queued"})
 }
 
 func (s *Server) handleDeleteRun(w http.ResponseWriter, r *http.Request) {
@@ -247,111 +1034,178 @@ func (s *Server) handleDeleteRun(w http.ResponseWriter, r *http.Request) {
 
 	run, err := s.repo.GetBenchmarkRun(ctx, runID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "query failed")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
 		return
 	}
 	if run == nil {
-		writeError(w, http.StatusNotFound, "run not found")
+		writeError(w, &apiError{http.StatusNotFound, codeRunNotFound, "run not found"})
+		return
+	}
+	if apiErr := checkIfMatch(r, run); apiErr != nil {
+		writeError(w, apiErr)
 		return
 	}
 
 	// Cancel if still active — the deferred teardown in Execute will
-	// clean up K8s resources automatically.
-	if run.Status == "pending" || run.Status == "running" {
+	// clean up K8s resources automatically. A "queued" run hasn't been
+	// dispatched yet, so there's no orchestrator goroutine to cancel;
+	// dequeuing it is enough to stop the scheduler from picking it up.
+	switch run.Status {
+	case "pending", "running":
 		s.orch.CancelRun(runID)
 		_ = s.repo.UpdateRunStatus(ctx, runID, "failed")
+	case "queued":
+		_ = s.repo.DequeueRun(ctx, runID)
+		_ = s.repo.UpdateRunStatus(ctx, runID, "failed")
 	}
 
 	if err := s.repo.DeleteRun(ctx, runID); err != nil {
-		writeError(w, http.StatusInternalServerError, "delete failed")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "delete failed"})
 		return
 	}
+	s.metrics.runsTotal.WithLabelValues("deleted").Inc()
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleRecommend compares one or more candidate instance types for a
+// model and ranks them by feasibility, projected throughput, and blended
+// $/1M-output-token cost. Pass instance_type one or more times to compare
+// a specific set; omit it entirely to compare every GPU/Neuron SKU in the
+// catalog. max_ttft_ms and min_tps_per_req filter out candidates that
+// can't meet those SLOs; region selects which pricing row to cost against
+// (defaulting the same way handleListPricing does).
 func (s *Server) handleRecommend(w http.ResponseWriter, r *http.Request) {
 	modelID := r.URL.Query().Get("model")
-	instanceName := r.URL.Query().Get("instance_type")
-	if modelID == "" || instanceName == "" {
-		writeError(w, http.StatusBadRequest, "model and instance_type query parameters are required")
+	if modelID == "" {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidRunRequest, "model query parameter is required"})
 		return
 	}
 
-	hfToken := r.Header.Get("X-HF-Token")
-
-	// Look up instance type from DB.
-	instType, err := s.repo.GetInstanceTypeByName(r.Context(), instanceName)
+	constraints, err := parseRecommendConstraints(r)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "instance type lookup failed")
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidRunRequest, err.Error()})
 		return
 	}
-	if instType == nil {
-		writeError(w, http.StatusNotFound, fmt.Sprintf("instance type %s not found", instanceName))
+
+	region := r.URL.Query().Get("region")
+	if region == "" {
+		region = "us-east-2"
+	}
+
+	allInstTypes, err := s.repo.ListInstanceTypes(r.Context())
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "list instance types failed"})
 		return
 	}
+	specsByName := make(map[string]recommend.InstanceSpec, len(allInstTypes))
+	var allSpecs []recommend.InstanceSpec
+	for _, it := range allInstTypes {
+		spec := recommend.InstanceSpec{
+			Name:                 it.Name,
+			AcceleratorType:      it.AcceleratorType,
+			AcceleratorName:      it.AcceleratorName,
+			AcceleratorCount:     it.AcceleratorCount,
+			AcceleratorMemoryGiB: it.AcceleratorMemoryGiB,
+		}
+		specsByName[it.Name] = spec
+		allSpecs = append(allSpecs, spec)
+	}
 
-	// Check if Neuron instance.
-	if !strings.EqualFold(instType.AcceleratorType, "gpu") {
-		writeJSON(w, http.StatusOK, map[string]any{
-			"explanation": map[string]any{
-				"feasible": false,
-				"reason":   "Configuration suggestions are not yet available for Neuron instances.",
-			},
-		})
+	var candidates []recommend.InstanceSpec
+	if names := r.URL.Query()["instance_type"]; len(names) > 0 {
+		for _, name := range names {
+			spec, ok := specsByName[name]
+			if !ok {
+				writeError(w, &apiError{http.StatusNotFound, codeInstanceTypeNotFound, fmt.Sprintf("instance type %s not found", name)})
+				return
+			}
+			candidates = append(candidates, spec)
+		}
+	} else {
+		for _, spec := range allSpecs {
+			if strings.EqualFold(spec.AcceleratorType, "gpu") || strings.EqualFold(spec.AcceleratorType, "neuron") {
+				candidates = append(candidates, spec)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		writeError(w, &apiError{http.StatusNotFound, codeInstanceTypeNotFound, "no candidate GPU/Neuron instance types found"})
 		return
 	}
 
-	// Fetch model config from HuggingFace.
-	modelCfg, err := s.hfClient.FetchModelConfig(modelID, hfToken)
+	hfToken := r.Header.Get("X-HF-Token")
+	modelCfg, err := s.hfClient.FetchModelConfigContext(r.Context(), modelID, hfToken)
 	if err != nil {
 		var hfErr *recommend.HFError
 		if errors.As(err, &hfErr) {
-			writeError(w, hfErr.StatusCode, hfErr.Message)
+			s.metrics.hfRequestsTotal.WithLabelValues(strconv.Itoa(hfErr.StatusCode)).Inc()
+			writeError(w, &apiError{hfErr.StatusCode, codeUpstreamUnavailable, hfErr.Message})
 			return
 		}
-		writeError(w, http.StatusBadGateway, "failed to fetch model metadata from HuggingFace")
+		s.metrics.hfRequestsTotal.WithLabelValues("error").Inc()
+		writeError(w, &apiError{http.StatusBadGateway, codeUpstreamUnavailable, "failed to fetch model metadata from HuggingFace"})
 		return
 	}
+	s.metrics.hfRequestsTotal.WithLabelValues(strconv.Itoa(http.StatusOK)).Inc()
 
-	// Get all GPU instances for suggesting alternatives.
-	allInstTypes, err := s.repo.ListInstanceTypes(r.Context())
+	pricingRows, err := s.repo.ListPricing(r.Context(), region)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "list instance types failed")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "pricing query failed"})
 		return
 	}
-	var allSpecs []recommend.InstanceSpec
-	for _, it := range allInstTypes {
-		allSpecs = append(allSpecs, recommend.InstanceSpec{
-			Name:                 it.Name,
-			AcceleratorType:      it.AcceleratorType,
-			AcceleratorName:      it.AcceleratorName,
-			AcceleratorCount:     it.AcceleratorCount,
-			AcceleratorMemoryGiB: it.AcceleratorMemoryGiB,
-		})
+	pricing := make(map[string]recommend.Pricing, len(pricingRows))
+	for _, row := range pricingRows {
+		pricing[row.InstanceTypeName] = recommend.Pricing{OnDemandHourlyUSD: row.OnDemandHourlyUSD}
 	}
 
-	inst := recommend.InstanceSpec{
-		Name:                 instType.Name,
-		AcceleratorType:      instType.AcceleratorType,
-		AcceleratorName:      instType.AcceleratorName,
-		AcceleratorCount:     instType.AcceleratorCount,
-		AcceleratorMemoryGiB: instType.AcceleratorMemoryGiB,
-	}
+	comparison := recommend.Compare(*modelCfg, candidates, allSpecs, pricing, constraints)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"model":      modelID,
+		"region":     region,
+		"comparison": comparison,
+	})
+}
 
-	rec := recommend.Recommend(*modelCfg, inst, allSpecs)
-	writeJSON(w, http.StatusOK, rec)
+// parseRecommendConstraints reads max_ttft_ms and min_tps_per_req from the
+// query string into a recommend.Constraints, leaving a field zero (no
+// constraint) when its query parameter is absent.
+func parseRecommendConstraints(r *http.Request) (recommend.Constraints, error) {
+	var c recommend.Constraints
+	if v := r.URL.Query().Get("max_ttft_ms"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return c, fmt.Errorf("max_ttft_ms must be a number")
+		}
+		c.MaxTTFTMs = f
+	}
+	if v := r.URL.Query().Get("min_tps_per_req"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return c, fmt.Errorf("min_tps_per_req must be a number")
+		}
+		c.MinTPSPerReq = f
+	}
+	return c, nil
 }
 
+// handleListInstanceTypes and handleListPricing return every matching row
+// in one response rather than a limit/offset page — there's no Repo
+// method that scopes either to a page, and both are small, fixed-size
+// reference tables rather than something that grows with usage the way
+// catalog entries or runs do. They still set X-Total-Count for a caller
+// rendering "N instance types" without counting the body itself, but
+// Link headers don't apply since there's no next/prev page to point to.
 func (s *Server) handleListInstanceTypes(w http.ResponseWriter, r *http.Request) {
 	types, err := s.repo.ListInstanceTypes(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "list instance types failed")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "list instance types failed"})
 		return
 	}
 	if types == nil {
 		types = []database.InstanceType{}
 	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(types)))
 	writeJSON(w, http.StatusOK, types)
 }
 
@@ -363,12 +1217,13 @@ func (s *Server) handleListPricing(w http.ResponseWriter, r *http.Request) {
 
 	rows, err := s.repo.ListPricing(r.Context(), region)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "pricing query failed")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "pricing query failed"})
 		return
 	}
 	if rows == nil {
 		rows = []database.PricingRow{}
 	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(rows)))
 	writeJSON(w, http.StatusOK, rows)
 }
 
@@ -383,12 +1238,12 @@ func (s *Server) handleCatalogSeed(w http.ResponseWriter, r *http.Request) {
 
 	toolsImage := os.Getenv("TOOLS_IMAGE")
 	if toolsImage == "" {
-		writeError(w, http.StatusInternalServerError, "TOOLS_IMAGE not configured")
+		writeError(w, &apiError{http.StatusInternalServerError, codeMisconfigured, "TOOLS_IMAGE not configured"})
 		return
 	}
 	configMap := os.Getenv("CATALOG_CONFIGMAP")
 	if configMap == "" {
-		writeError(w, http.StatusInternalServerError, "CATALOG_CONFIGMAP not configured")
+		writeError(w, &apiError{http.StatusInternalServerError, codeMisconfigured, "CATALOG_CONFIGMAP not configured"})
 		return
 	}
 
@@ -397,12 +1252,12 @@ func (s *Server) handleCatalogSeed(w http.ResponseWriter, r *http.Request) {
 		LabelSelector: seedLabelKey + "=" + seedLabelVal,
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to list seed jobs")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "failed to list seed jobs"})
 		return
 	}
 	for _, j := range jobs.Items {
 		if j.Status.Active > 0 {
-			writeError(w, http.StatusConflict, fmt.Sprintf("A catalog seed job is already running: %s", j.Name))
+			writeError(w, &apiError{http.StatusConflict, codeSeedJobActive, fmt.Sprintf("A catalog seed job is already running: %s", j.Name)})
 			return
 		}
 	}
@@ -482,9 +1337,10 @@ func (s *Server) handleCatalogSeed(w http.ResponseWriter, r *http.Request) {
 
 	created, err := s.client.BatchV1().Jobs(seedNamespace).Create(ctx, job, metav1.CreateOptions{})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create seed job: %v", err))
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to create seed job: %v", err)})
 		return
 	}
+	s.metrics.seedJobsTotal.Inc()
 
 	writeJSON(w, http.StatusAccepted, map[string]string{
 		"job_name": created.Name,
@@ -497,7 +1353,7 @@ func (s *Server) handleCatalogSeedStatus(w http.ResponseWriter, r *http.Request)
 		LabelSelector: seedLabelKey + "=" + seedLabelVal,
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to list seed jobs")
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "failed to list seed jobs"})
 		return
 	}
 
@@ -551,6 +1407,44 @@ func writeJSON(w http.ResponseWriter, code int, v any) {
 	json.NewEncoder(w).Encode(v)
 }
 
-func writeError(w http.ResponseWriter, code int, msg string) {
-	writeJSON(w, code, map[string]string{"error": msg})
+// writePaginationHeaders sets X-Total-Count and, when limit is positive,
+// RFC 5988 Link headers (rel="next"/"prev"/"last") describing a
+// limit/offset-paginated list response — built from r's own query
+// parameters with only limit/offset replaced, so a caller just follows
+// the header instead of reconstructing the pagination math itself. Must
+// be called before WriteHeader/writeJSON, since Go's ResponseWriter
+// ignores header writes after the status line is sent.
+func writePaginationHeaders(w http.ResponseWriter, r *http.Request, limit, offset, total int) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	if limit <= 0 {
+		return
+	}
+
+	linkURL := func(off int) string {
+		q := r.URL.Query()
+		q.Set("limit", strconv.Itoa(limit))
+		q.Set("offset", strconv.Itoa(off))
+		u := *r.URL
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	var links []string
+	if offset+limit < total {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, linkURL(offset+limit)))
+	}
+	if offset > 0 {
+		prevOffset := offset - limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, linkURL(prevOffset)))
+	}
+	if total > 0 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="last"`, linkURL(((total-1)/limit)*limit)))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
 }
+
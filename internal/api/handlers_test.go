@@ -78,8 +78,93 @@ func TestHandleCreateRun_Success(t *testing.T) {
 	if resp["id"] == "" {
 		t.Error("response missing run id")
 	}
-	if resp["status"] != "pending" {
-		t.Errorf("status = %s, want pending", resp["status"])
+	if resp["status"] != "queued" {
+		t.Errorf("status = %s, want queued", resp["status"])
+	}
+}
+
+func TestHandleCreateRun_IdempotencyKeyReplaysOriginalRun(t *testing.T) {
+	_, mux := setupServer()
+
+	body := database.RunRequest{
+		ModelHfID:            "meta-llama/Llama-3.1-8B",
+		ModelHfRevision:      "abc123",
+		InstanceTypeName:     "g5.xlarge",
+		Framework:            "vllm",
+		FrameworkVersion:     "v0.6.0",
+		TensorParallelDegree: 1,
+		Concurrency:          16,
+		InputSequenceLength:  512,
+		OutputSequenceLength: 256,
+		DatasetName:          "sharegpt",
+		RunType:              "on_demand",
+	}
+	b, _ := json.Marshal(body)
+
+	req1 := httptest.NewRequest("POST", "/api/v1/runs", bytes.NewReader(b))
+	req1.Header.Set("Idempotency-Key", "retry-key-1")
+	w1 := httptest.NewRecorder()
+	mux.ServeHTTP(w1, req1)
+
+	if w1.Code != http.StatusAccepted {
+		t.Fatalf("first request status = %d, want %d; body: %s", w1.Code, http.StatusAccepted, w1.Body.String())
+	}
+	var first map[string]string
+	json.NewDecoder(w1.Body).Decode(&first)
+
+	req2 := httptest.NewRequest("POST", "/api/v1/runs", bytes.NewReader(b))
+	req2.Header.Set("Idempotency-Key", "retry-key-1")
+	w2 := httptest.NewRecorder()
+	mux.ServeHTTP(w2, req2)
+
+	if w2.Code != http.StatusAccepted {
+		t.Fatalf("replayed request status = %d, want %d; body: %s", w2.Code, http.StatusAccepted, w2.Body.String())
+	}
+	var second map[string]string
+	json.NewDecoder(w2.Body).Decode(&second)
+
+	if second["id"] != first["id"] {
+		t.Errorf("replayed request created run %s, want original run %s", second["id"], first["id"])
+	}
+}
+
+func TestHandleCreateRun_IdempotencyKeyConflictingBodyRejected(t *testing.T) {
+	_, mux := setupServer()
+
+	base := database.RunRequest{
+		ModelHfID:            "meta-llama/Llama-3.1-8B",
+		ModelHfRevision:      "abc123",
+		InstanceTypeName:     "g5.xlarge",
+		Framework:            "vllm",
+		FrameworkVersion:     "v0.6.0",
+		TensorParallelDegree: 1,
+		Concurrency:          16,
+		InputSequenceLength:  512,
+		OutputSequenceLength: 256,
+		DatasetName:          "sharegpt",
+		RunType:              "on_demand",
+	}
+	b1, _ := json.Marshal(base)
+
+	req1 := httptest.NewRequest("POST", "/api/v1/runs", bytes.NewReader(b1))
+	req1.Header.Set("Idempotency-Key", "retry-key-2")
+	w1 := httptest.NewRecorder()
+	mux.ServeHTTP(w1, req1)
+	if w1.Code != http.StatusAccepted {
+		t.Fatalf("first request status = %d, want %d; body: %s", w1.Code, http.StatusAccepted, w1.Body.String())
+	}
+
+	conflicting := base
+	conflicting.Concurrency = 32
+	b2, _ := json.Marshal(conflicting)
+
+	req2 := httptest.NewRequest("POST", "/api/v1/runs", bytes.NewReader(b2))
+	req2.Header.Set("Idempotency-Key", "retry-key-2")
+	w2 := httptest.NewRecorder()
+	mux.ServeHTTP(w2, req2)
+
+	if w2.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d; body: %s", w2.Code, http.StatusConflict, w2.Body.String())
 	}
 }
 
@@ -149,6 +234,11 @@ func TestHandleCreateRun_InstanceNotFound(t *testing.T) {
 	if w.Code != http.StatusNotFound {
 		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
 	}
+	var problem Problem
+	json.NewDecoder(w.Body).Decode(&problem)
+	if problem.Code != codeInstanceTypeNotFound {
+		t.Errorf("code = %q, want %s", problem.Code, codeInstanceTypeNotFound)
+	}
 }
 
 func TestHandleGetRun_Found(t *testing.T) {
@@ -197,6 +287,97 @@ func TestHandleGetRun_NotFound(t *testing.T) {
 	}
 }
 
+func TestHandleGetRunWatch_ReturnsOnStatusChange(t *testing.T) {
+	repo := seedRepo()
+	client := fake.NewSimpleClientset()
+	srv := NewServer(repo, client)
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	run := &database.BenchmarkRun{
+		ModelID: "model-001", InstanceTypeID: "inst-001",
+		Framework: "vllm", FrameworkVersion: "v0.6.0",
+		Concurrency: 16, InputSequenceLength: 512,
+		OutputSequenceLength: 256, DatasetName: "sharegpt",
+		RunType: "on_demand", Status: "pending",
+	}
+	runID, _ := repo.CreateBenchmarkRun(context.Background(), run)
+
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		req := httptest.NewRequest("GET", "/api/v1/runs/"+runID+"?watch=true", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		done <- w
+	}()
+
+	// Give the watch handler time to subscribe before publishing.
+	time.Sleep(10 * time.Millisecond)
+	if err := repo.UpdateRunStatus(context.Background(), runID, "running"); err != nil {
+		t.Fatalf("UpdateRunStatus: %v", err)
+	}
+
+	select {
+	case w := <-done:
+		if w.Code != http.StatusOK {
+			t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+		var resp database.BenchmarkRun
+		json.NewDecoder(w.Body).Decode(&resp)
+		if resp.Status != "running" {
+			t.Errorf("status = %s, want running", resp.Status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for long-poll response")
+	}
+}
+
+func TestHandleRunEvents_StreamsUntilTerminal(t *testing.T) {
+	repo := seedRepo()
+	client := fake.NewSimpleClientset()
+	srv := NewServer(repo, client)
+	mux := http.NewServeMux()
+	srv.RegisterRoutes(mux)
+
+	run := &database.BenchmarkRun{
+		ModelID: "model-001", InstanceTypeID: "inst-001",
+		Framework: "vllm", FrameworkVersion: "v0.6.0",
+		Concurrency: 16, InputSequenceLength: 512,
+		OutputSequenceLength: 256, DatasetName: "sharegpt",
+		RunType: "on_demand", Status: "pending",
+	}
+	runID, _ := repo.CreateBenchmarkRun(context.Background(), run)
+
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		req := httptest.NewRequest("GET", "/api/v1/runs/"+runID+"/events", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		done <- w
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	if err := repo.UpdateRunStatus(context.Background(), runID, "running"); err != nil {
+		t.Fatalf("UpdateRunStatus: %v", err)
+	}
+	if err := repo.UpdateRunStatus(context.Background(), runID, "failed"); err != nil {
+		t.Fatalf("UpdateRunStatus: %v", err)
+	}
+
+	select {
+	case w := <-done:
+		body := w.Body.String()
+		if !bytes.Contains([]byte(body), []byte("event: run-update")) {
+			t.Errorf("expected SSE events in body, got %q", body)
+		}
+		if !bytes.Contains([]byte(body), []byte(`"status":"failed"`)) {
+			t.Errorf("expected a failed status event, got %q", body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for SSE stream to close")
+	}
+}
+
 func TestHandleGetMetrics_NotFound(t *testing.T) {
 	_, mux := setupServer()
 
@@ -230,7 +411,7 @@ func TestHandleGetMetrics_Found(t *testing.T) {
 	ttft := 42.0
 	repo.PersistMetrics(context.Background(), runID, &database.BenchmarkMetrics{
 		TTFTP50Ms: &ttft,
-	})
+	}, nil)
 
 	req := httptest.NewRequest("GET", "/api/v1/runs/"+runID+"/metrics", nil)
 	w := httptest.NewRecorder()
@@ -293,7 +474,7 @@ func seedCatalogServer() (*database.MockRepo, *http.ServeMux) {
 			DatasetName: "sharegpt", RunType: "catalog", Status: "pending",
 		}
 		id, _ := repo.CreateBenchmarkRun(ctx, run)
-		repo.PersistMetrics(ctx, id, &database.BenchmarkMetrics{TTFTP50Ms: &ttft})
+		repo.PersistMetrics(ctx, id, &database.BenchmarkMetrics{TTFTP50Ms: &ttft}, nil)
 	}
 
 	srv := NewServer(repo, client)
@@ -544,7 +725,7 @@ func TestHandleCancelRun_NotFound(t *testing.T) {
 func TestHandleCancelRun_AlreadyCompleted(t *testing.T) {
 	repo, mux := seedJobsServer()
 
-	items, _ := repo.ListRuns(nil, database.RunFilter{Status: "completed"})
+	items, _, _ := repo.ListRuns(nil, database.RunFilter{Status: "completed"})
 	if len(items) == 0 {
 		t.Fatal("no completed runs")
 	}
@@ -556,12 +737,17 @@ func TestHandleCancelRun_AlreadyCompleted(t *testing.T) {
 	if w.Code != http.StatusConflict {
 		t.Fatalf("status = %d, want 409", w.Code)
 	}
+	var problem Problem
+	json.NewDecoder(w.Body).Decode(&problem)
+	if problem.Code != codeRunNotCancellable {
+		t.Errorf("code = %q, want %s", problem.Code, codeRunNotCancellable)
+	}
 }
 
 func TestHandleCancelRun_Success(t *testing.T) {
 	repo, mux := seedJobsServer()
 
-	items, _ := repo.ListRuns(nil, database.RunFilter{Status: "running"})
+	items, _, _ := repo.ListRuns(nil, database.RunFilter{Status: "running"})
 	if len(items) == 0 {
 		t.Fatal("no running runs")
 	}
@@ -594,7 +780,7 @@ func TestHandleDeleteRun_NotFound(t *testing.T) {
 func TestHandleDeleteRun_Success(t *testing.T) {
 	repo, mux := seedJobsServer()
 
-	items, _ := repo.ListRuns(nil, database.RunFilter{Status: "completed"})
+	items, _, _ := repo.ListRuns(nil, database.RunFilter{Status: "completed"})
 	if len(items) == 0 {
 		t.Fatal("no completed runs")
 	}
@@ -614,10 +800,53 @@ func TestHandleDeleteRun_Success(t *testing.T) {
 	}
 }
 
+func TestHandleDeleteRun_IfMatchMismatch(t *testing.T) {
+	repo, mux := seedJobsServer()
+
+	items, _, _ := repo.ListRuns(nil, database.RunFilter{Status: "completed"})
+	if len(items) == 0 {
+		t.Fatal("no completed runs")
+	}
+	runID := items[0].ID
+
+	req := httptest.NewRequest("DELETE", "/api/v1/runs/"+runID, nil)
+	req.Header.Set("If-Match", `"running"`)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("status = %d, want 412", w.Code)
+	}
+
+	run, _ := repo.GetBenchmarkRun(nil, runID)
+	if run == nil {
+		t.Error("expected run to survive a mismatched If-Match")
+	}
+}
+
+func TestHandleDeleteRun_IfMatchMatches(t *testing.T) {
+	repo, mux := seedJobsServer()
+
+	items, _, _ := repo.ListRuns(nil, database.RunFilter{Status: "completed"})
+	if len(items) == 0 {
+		t.Fatal("no completed runs")
+	}
+	runID := items[0].ID
+
+	req := httptest.NewRequest("DELETE", "/api/v1/runs/"+runID, nil)
+	req.Header.Set("If-Match", `"completed"`)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+}
+
 func TestHandleDeleteRun_CancelsActiveRun(t *testing.T) {
 	repo, mux := seedJobsServer()
 
-	items, _ := repo.ListRuns(nil, database.RunFilter{Status: "running"})
+	items, _, _ := repo.ListRuns(nil, database.RunFilter{Status: "running"})
 	if len(items) == 0 {
 		t.Fatal("no running runs")
 	}
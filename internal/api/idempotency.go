@@ -0,0 +1,46 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// defaultIdempotencyTTL is how long a replayed Idempotency-Key still
+// returns the original run instead of being treated as an unrelated
+// submission, long enough to cover a client's own retry window after a
+// dropped connection or gateway timeout.
+const defaultIdempotencyTTL = 24 * time.Hour
+
+// WithIdempotencyTTL overrides defaultIdempotencyTTL.
+func WithIdempotencyTTL(ttl time.Duration) ServerOption {
+	return func(s *Server) { s.idempotency = database.NewIdempotencyStore(ttl) }
+}
+
+// clientID scopes Idempotency-Key replay guards to the caller that
+// presented them, using the mTLS client certificate's CN when present.
+// Deployments without per-client certificates (the plain TCP and Unix
+// socket listeners) share a single anonymous scope, consistent with
+// allowAllPolicy's assumption that they sit behind a trusted network.
+func clientID(r *http.Request) string {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return r.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	return ""
+}
+
+// hashRunRequest returns a stable hash of req's canonical JSON encoding,
+// used to detect whether a replayed Idempotency-Key was submitted with
+// the same request body. encoding/json marshals map keys (e.g.
+// FrameworkPlugin.EnvAppend) in sorted order, so two requests with
+// identical fields always hash equal regardless of how the client
+// serialized them.
+func hashRunRequest(req *database.RunRequest) string {
+	b, _ := json.Marshal(req)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
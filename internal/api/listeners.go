@@ -0,0 +1,54 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+)
+
+// ListenUnix creates a Unix domain socket listener at path with the
+// given file mode, mirroring Consul's unix_sockets{mode=...} config. A
+// stale socket file left behind by a previous, uncleanly-stopped process
+// would otherwise make the bind fail with "address already in use", so
+// it's removed first.
+func ListenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("remove stale socket: %w", err)
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listen on unix socket: %w", err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("chmod unix socket: %w", err)
+	}
+	return ln, nil
+}
+
+// ListenMTLS creates a TCP listener on addr that requires and verifies a
+// client certificate signed by the CA in caFile, so a CNAuthzPolicy can
+// authorize callers by their certificate's CN.
+func ListenMTLS(addr, certFile, keyFile, caFile string) (net.Listener, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load server certificate: %w", err)
+	}
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("read client CA bundle: %w", err)
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("parse client CA bundle %s: no certificates found", caFile)
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    caPool,
+	}
+	return tls.Listen("tcp", addr, cfg)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// runDurationBuckets are the histogram buckets (seconds) for
+// accelbench_run_duration_seconds: runs span from a couple minutes (tiny
+// smoke tests) to a couple hours (long sweeps), so the default client_golang
+// buckets don't fit.
+var runDurationBuckets = []float64{30, 60, 120, 300, 600, 1200, 1800, 3600, 7200}
+
+// apiMetrics holds every instrument the API server emits, created against
+// an injected metrics.Registry for the same reason orchestratorMetrics is:
+// multiple Servers in one process shouldn't collide on metric names and
+// tests can substitute a metrics.NoopRegistry.
+type apiMetrics struct {
+	runsTotal       metrics.CounterVec   // status
+	runDuration     metrics.HistogramVec // outcome
+	activeRuns      metrics.Gauge
+	seedJobsTotal   metrics.Counter
+	hfRequestsTotal metrics.CounterVec // code
+}
+
+func newAPIMetrics(reg metrics.Registry) *apiMetrics {
+	return &apiMetrics{
+		runsTotal: reg.Counter("accelbench_runs_total",
+			"Count of benchmark runs by lifecycle transition or terminal outcome.", "status"),
+		runDuration: reg.Histogram("accelbench_run_duration_seconds",
+			"Wall-clock time from Orchestrator.Execute starting to it returning.", runDurationBuckets, "outcome"),
+		activeRuns: reg.Gauge("accelbench_active_runs",
+			"Number of benchmark runs currently executing in the orchestrator.").WithLabelValues(),
+		seedJobsTotal: reg.Counter("accelbench_seed_jobs_total",
+			"Count of catalog-seed Jobs created via POST /catalog/seed.").WithLabelValues(),
+		hfRequestsTotal: reg.Counter("accelbench_hf_requests_total",
+			"Count of HuggingFace metadata lookups made by the recommend endpoint, by response code.", "code"),
+	}
+}
+
+// runObserver adapts apiMetrics to orchestrator.RunObserver, so the
+// orchestrator package can report run lifecycle events without knowing
+// about accelbench_active_runs or accelbench_run_duration_seconds.
+type runObserver struct {
+	m *apiMetrics
+}
+
+func (o runObserver) RunStarted() {
+	o.m.activeRuns.Add(1)
+}
+
+func (o runObserver) RunFinished(outcome string, d time.Duration) {
+	o.m.activeRuns.Add(-1)
+	o.m.runsTotal.WithLabelValues(outcome).Inc()
+	o.m.runDuration.WithLabelValues(outcome).Observe(d.Seconds())
+}
+
+// prometheusExposable is implemented by metrics.Registry backends that can
+// expose their collected instruments over HTTP, so handleMetrics doesn't
+// need to import the concrete *metrics.PrometheusRegistry type.
+type prometheusExposable interface {
+	Prometheus() *prometheus.Registry
+}
+
+// handleMetrics serves the control plane's own Prometheus-format metrics
+// (accelbench_runs_total, accelbench_run_duration_seconds, accelbench_active_runs,
+// accelbench_seed_jobs_total, accelbench_hf_requests_total, plus everything the
+// orchestrator and scheduler register against the same shared registry) —
+// the SRE view of accelbench itself, alongside the vLLM/DCGM metrics it scrapes
+// from benchmarked pods.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	exposable, ok := s.metricsReg.(prometheusExposable)
+	if !ok {
+		writeError(w, &apiError{http.StatusNotImplemented, codeInternal, "metrics registry does not support exposition"})
+		return
+	}
+	promhttp.HandlerFor(exposable.Prometheus(), promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
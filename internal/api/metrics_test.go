@@ -0,0 +1,50 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+func TestHandleMetrics_ExposesRunsTotalAfterCreate(t *testing.T) {
+	_, mux := setupServer()
+
+	body := database.RunRequest{
+		ModelHfID:            "meta-llama/Llama-3.1-8B",
+		ModelHfRevision:      "abc123",
+		InstanceTypeName:     "g5.xlarge",
+		Framework:            "vllm",
+		FrameworkVersion:     "v0.6.0",
+		TensorParallelDegree: 1,
+		Concurrency:          16,
+		InputSequenceLength:  512,
+		OutputSequenceLength: 256,
+		DatasetName:          "sharegpt",
+		RunType:              "on_demand",
+	}
+	b, _ := json.Marshal(body)
+
+	req := httptest.NewRequest("POST", "/api/v1/runs", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("create run status = %d, want %d; body: %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/metrics", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /metrics status = %d, want 200", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `accelbench_runs_total{status="created"} 1`) {
+		t.Errorf("expected accelbench_runs_total{status=\"created\"} 1 in /metrics output, got:\n%s", w.Body.String())
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/accelbench/accelbench/internal/cronexpr"
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// errInvalidTrigger is returned by policyRequest.validate when Trigger is
+// not one of the three values a BenchmarkPolicy accepts.
+var errInvalidTrigger = errors.New(`trigger must be "manual", "scheduled", or "event"`)
+
+// policyRequest is the wire shape for POST/PUT /api/v1/policies, mirroring
+// database.BenchmarkPolicy's persisted fields minus the ones the server
+// computes (ID, NextRunAt, LastRunAt, CreatedAt).
+type policyRequest struct {
+	Name     string                        `json:"name"`
+	Template database.BenchmarkRunTemplate `json:"template"`
+	Trigger  string                        `json:"trigger"`
+	CronExpr string                        `json:"cron_expr,omitempty"`
+	Enabled  bool                          `json:"enabled"`
+}
+
+func (req *policyRequest) toPolicy() *database.BenchmarkPolicy {
+	return &database.BenchmarkPolicy{
+		Name:     req.Name,
+		Template: req.Template,
+		Trigger:  req.Trigger,
+		CronExpr: req.CronExpr,
+		Enabled:  req.Enabled,
+	}
+}
+
+func (req *policyRequest) validate() error {
+	switch req.Trigger {
+	case "manual", "event":
+	case "scheduled":
+		if _, err := cronexpr.Parse(req.CronExpr); err != nil {
+			return err
+		}
+	default:
+		return errInvalidTrigger
+	}
+	return nil
+}
+
+// handleCreatePolicy persists a new BenchmarkPolicy. A Trigger="scheduled"
+// policy has its first NextRunAt computed from CronExpr as of now, so it
+// fires at the next matching tick rather than immediately.
+func (s *Server) handleCreatePolicy(w http.ResponseWriter, r *http.Request) {
+	var req policyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidPolicyRequest, "invalid request body"})
+		return
+	}
+	if err := req.validate(); err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidPolicyRequest, err.Error()})
+		return
+	}
+
+	id, err := s.repo.CreatePolicy(r.Context(), req.toPolicy())
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "create policy failed"})
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"id": id})
+}
+
+// handleListPolicies returns every persisted BenchmarkPolicy.
+func (s *Server) handleListPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := s.repo.ListPolicies(r.Context())
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "list policies failed"})
+		return
+	}
+	if policies == nil {
+		policies = []database.BenchmarkPolicy{}
+	}
+	writeJSON(w, http.StatusOK, policies)
+}
+
+// handleUpdatePolicy replaces an existing BenchmarkPolicy's mutable
+// fields.
+func (s *Server) handleUpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	policyID := r.PathValue("id")
+
+	var req policyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidPolicyRequest, "invalid request body"})
+		return
+	}
+	if err := req.validate(); err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidPolicyRequest, err.Error()})
+		return
+	}
+
+	if err := s.repo.UpdatePolicy(r.Context(), policyID, req.toPolicy()); err != nil {
+		if err == database.ErrPolicyNotFound {
+			writeError(w, &apiError{http.StatusNotFound, codePolicyNotFound, "policy not found"})
+			return
+		}
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "update policy failed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"id": policyID})
+}
+
+// handleDeletePolicy removes a BenchmarkPolicy. Runs it already produced
+// are left untouched.
+func (s *Server) handleDeletePolicy(w http.ResponseWriter, r *http.Request) {
+	policyID := r.PathValue("id")
+	if err := s.repo.DeletePolicy(r.Context(), policyID); err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "delete policy failed"})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+)
+
+// Problem is an RFC 7807 application/problem+json error body, extended
+// with a stable machine-readable Code (e.g. "instance_type_not_found")
+// so clients can branch on error type without parsing Detail's prose,
+// and TraceID so a report can be matched back to server logs.
+type Problem struct {
+	Title   string `json:"title"`
+	Status  int    `json:"status"`
+	Detail  string `json:"detail,omitempty"`
+	Code    string `json:"code"`
+	TraceID string `json:"trace_id,omitempty"`
+}
+
+// apiError pairs an HTTP status and machine-readable code with a
+// human-readable detail, so call sites can build one value instead of
+// repeating all three writeProblem arguments inline.
+type apiError struct {
+	status int
+	code   string
+	detail string
+}
+
+func (e *apiError) Error() string { return e.detail }
+
+// Well-known error codes returned in Problem.Code. Handlers should use
+// one of these rather than inventing ad-hoc strings, so clients have a
+// stable, documented set to branch on.
+const (
+	codeInvalidRunRequest       = "invalid_run_request"
+	codeInstanceTypeNotFound    = "instance_type_not_found"
+	codeRunNotFound             = "run_not_found"
+	codeRunNotCancellable       = "run_not_cancellable"
+	codeMetricsNotFound         = "metrics_not_found"
+	codeSeedJobActive           = "catalog_seed_active"
+	codeInvalidSweepRequest     = "invalid_sweep_request"
+	codeSweepNotFound           = "sweep_not_found"
+	codeIdempotencyConflict     = "idempotency_key_conflict"
+	codeMisconfigured           = "server_misconfigured"
+	codeUpstreamUnavailable     = "upstream_unavailable"
+	codeStreamingUnsupported    = "streaming_unsupported"
+	codeUnauthorized            = "unauthorized"
+	codeInternal                = "internal_error"
+	codeInvalidQuery            = "invalid_query"
+	codeRunNotQueued            = "run_not_queued"
+	codeInvalidPriorityRequest  = "invalid_priority_request"
+	codeRunNotRequeueable       = "run_not_requeueable"
+	codePreconditionFailed      = "precondition_failed"
+	codeInvalidExperimentSpec   = "invalid_experiment_spec"
+	codeExperimentNotFound      = "experiment_not_found"
+	codeInvalidSLOSearchRequest = "invalid_slo_search_request"
+	codeSLOSearchNotFound       = "slo_search_not_found"
+	codeInvalidAutoscaleRequest = "invalid_autoscale_request"
+	codeAutoscaleNotFound       = "autoscale_not_found"
+	codeInvalidPolicyRequest    = "invalid_policy_request"
+	codePolicyNotFound          = "policy_not_found"
+	codeInvalidDeadlineRequest  = "invalid_deadline_request"
+	codeRunNotExtendable        = "run_not_extendable"
+)
+
+// writeProblem writes an RFC 7807 problem+json body with the given
+// status, machine-readable code, and human-readable detail, stamping on
+// the trace ID the WithRequestID middleware attached to the response.
+func writeProblem(w http.ResponseWriter, status int, code, detail string) {
+	p := Problem{
+		Title:   http.StatusText(status),
+		Status:  status,
+		Detail:  detail,
+		Code:    code,
+		TraceID: w.Header().Get("X-Request-ID"),
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// writeError writes err as a Problem. Handlers build an *apiError instead
+// of calling writeProblem directly so the status/code/detail travel
+// together as one value.
+func writeError(w http.ResponseWriter, err *apiError) {
+	writeProblem(w, err.status, err.code, err.detail)
+}
+
+// WithRequestID assigns every request an ID (propagating an inbound
+// X-Request-ID rather than minting a new one, so an ID survives a hop
+// across services) and echoes it back as a response header, so
+// writeProblem can stamp it onto every Problem as trace_id.
+func WithRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if id == "" {
+			id = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// handleCreateSLOSearch validates an SLOSearchRequest's model and instance
+// type, creates its RunType="slo_search" parent run, and launches the
+// bisection in the background via orchestrator.ExecuteSLOSearch — the same
+// detached-goroutine shape handleCreateSweep uses for runSweep, since a
+// search's probes can take as long as a whole sweep to converge.
+func (s *Server) handleCreateSLOSearch(w http.ResponseWriter, r *http.Request) {
+	var req database.SLOSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidSLOSearchRequest, "invalid request body"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidSLOSearchRequest, err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+
+	model, err := s.repo.EnsureModel(ctx, req.ModelHfID, req.ModelHfRevision)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "ensure model failed"})
+		return
+	}
+
+	instType, err := s.repo.GetInstanceTypeByName(ctx, req.InstanceTypeName)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "lookup instance type failed"})
+		return
+	}
+	if instType == nil {
+		writeError(w, &apiError{http.StatusNotFound, codeInstanceTypeNotFound, fmt.Sprintf("instance type %s not found", req.InstanceTypeName)})
+		return
+	}
+
+	run := &database.BenchmarkRun{
+		ModelID:              model.ID,
+		InstanceTypeID:       instType.ID,
+		Framework:            req.Framework,
+		FrameworkVersion:     req.FrameworkVersion,
+		TensorParallelDegree: req.TensorParallelDegree,
+		Quantization:         req.Quantization,
+		InputSequenceLength:  req.InputSequenceLength,
+		OutputSequenceLength: req.OutputSequenceLength,
+		DatasetName:          req.DatasetName,
+		DatasetSpec:          req.DatasetSpec,
+		RunType:              "slo_search",
+		Status:               "pending",
+	}
+	runID, err := s.repo.CreateBenchmarkRun(ctx, run)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "create run failed"})
+		return
+	}
+
+	go func() {
+		if err := s.orch.ExecuteSLOSearch(context.Background(), runID, model, instType, req); err != nil {
+			log.Printf("slo search %s: %v", runID, err)
+		}
+	}()
+
+	writeJSON(w, http.StatusAccepted, map[string]string{
+		"id":     runID,
+		"status": "pending",
+	})
+}
+
+// handleGetSLOSearch reports a search's parent run, the concurrency probes
+// it has run so far, and — once converged — the MaxConcurrencyAtSLO
+// recorded on the parent run's own BenchmarkMetrics.
+func (s *Server) handleGetSLOSearch(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+	ctx := r.Context()
+
+	run, err := s.repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
+		return
+	}
+	if run == nil || run.RunType != "slo_search" {
+		writeError(w, &apiError{http.StatusNotFound, codeSLOSearchNotFound, "slo search not found"})
+		return
+	}
+
+	probes, err := s.repo.ListRunsByParent(ctx, runID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "list probes failed"})
+		return
+	}
+
+	resp := map[string]any{
+		"id":     run.ID,
+		"status": run.Status,
+		"probes": probes,
+	}
+
+	metrics, err := s.repo.GetMetricsByRunID(ctx, runID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query metrics failed"})
+		return
+	}
+	if metrics != nil && metrics.MaxConcurrencyAtSLO != nil {
+		resp["max_concurrency_at_slo"] = *metrics.MaxConcurrencyAtSLO
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,34 @@
+package api
+
+import "time"
+
+// deadlineTimer is a resettable idle timeout. Each Reset pushes the
+// deadline out by the same duration instead of scheduling a fresh timer,
+// so a long-lived handler like handleRunEvents can bound idle time
+// across many resets without leaking a timer goroutine per event.
+type deadlineTimer struct {
+	timer *time.Timer
+}
+
+func newDeadlineTimer(d time.Duration) *deadlineTimer {
+	return &deadlineTimer{timer: time.NewTimer(d)}
+}
+
+// C returns the channel that fires when the deadline is reached.
+func (d *deadlineTimer) C() <-chan time.Time { return d.timer.C }
+
+// Reset pushes the deadline out by d, draining a stale fire if needed.
+func (d *deadlineTimer) Reset(d2 time.Duration) {
+	if !d.timer.Stop() {
+		select {
+		case <-d.timer.C:
+		default:
+		}
+	}
+	d.timer.Reset(d2)
+}
+
+// Stop releases the underlying timer's resources.
+func (d *deadlineTimer) Stop() {
+	d.timer.Stop()
+}
@@ -0,0 +1,268 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/accelbench/accelbench/internal/analysis"
+	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/orchestrator"
+)
+
+// maxConcurrentSweepRuns caps how many child runs of a single sweep the
+// orchestrator drives at once, so a wide sweep (e.g. every concurrency
+// level times every tensor-parallel degree) can't throw dozens of
+// simultaneous deployments at the cluster.
+const maxConcurrentSweepRuns = 4
+
+// defaultSweepPricingRegion is the region priced when computing a sweep's
+// Pareto frontier, matching handleListPricing's default.
+const defaultSweepPricingRegion = "us-east-2"
+
+// handleCreateSweep expands a SweepRequest into the Cartesian product of
+// RunRequests, validates the model and every distinct instance type up
+// front, and — only if all of them resolve — creates the sweep and its
+// child runs atomically before launching them with bounded concurrency.
+func (s *Server) handleCreateSweep(w http.ResponseWriter, r *http.Request) {
+	var spec database.SweepRequest
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidSweepRequest, "invalid request body"})
+		return
+	}
+
+	requests, err := spec.Expand()
+	if err != nil {
+		writeError(w, &apiError{http.StatusBadRequest, codeInvalidSweepRequest, err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+
+	model, err := s.repo.EnsureModel(ctx, spec.ModelHfID, spec.ModelHfRevision)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "ensure model failed"})
+		return
+	}
+
+	// Resolve every distinct instance type up front so a single bad name
+	// fails the whole sweep before anything is created.
+	instTypes := make(map[string]*database.InstanceType)
+	for _, req := range requests {
+		if _, ok := instTypes[req.InstanceTypeName]; ok {
+			continue
+		}
+		it, err := s.repo.GetInstanceTypeByName(ctx, req.InstanceTypeName)
+		if err != nil {
+			writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "lookup instance type failed"})
+			return
+		}
+		if it == nil {
+			writeError(w, &apiError{http.StatusNotFound, codeInstanceTypeNotFound, fmt.Sprintf("instance type %s not found", req.InstanceTypeName)})
+			return
+		}
+		instTypes[req.InstanceTypeName] = it
+	}
+
+	runs := make([]*database.BenchmarkRun, len(requests))
+	for i, req := range requests {
+		runs[i] = &database.BenchmarkRun{
+			ModelID:              model.ID,
+			InstanceTypeID:       instTypes[req.InstanceTypeName].ID,
+			Framework:            req.Framework,
+			FrameworkVersion:     req.FrameworkVersion,
+			TensorParallelDegree: req.TensorParallelDegree,
+			Quantization:         req.Quantization,
+			Concurrency:          req.Concurrency,
+			InputSequenceLength:  req.InputSequenceLength,
+			OutputSequenceLength: req.OutputSequenceLength,
+			DatasetName:          req.DatasetName,
+			DatasetSpec:          req.DatasetSpec,
+			RunType:              req.RunType,
+			Status:               "pending",
+		}
+	}
+
+	sweepID, runIDs, err := s.repo.CreateSweep(ctx, runs)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "create sweep failed"})
+		return
+	}
+
+	go s.runSweep(sweepID, model, instTypes, requests, runIDs)
+
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"sweep_id": sweepID,
+		"run_ids":  runIDs,
+	})
+}
+
+// runSweep drives every child run of a sweep through the orchestrator,
+// bounded to maxConcurrentSweepRuns at a time so the cluster only ever
+// sees a handful of simultaneous deployments regardless of how wide the
+// sweep's Cartesian product is. It runs detached from the request that
+// created the sweep and calls the orchestrator directly, outside the
+// scheduler queue that handleCreateRun admits a single run into.
+func (s *Server) runSweep(sweepID string, model *database.Model, instTypes map[string]*database.InstanceType, requests []database.RunRequest, runIDs []string) {
+	indexes := make(chan int, len(runIDs))
+	for i := range runIDs {
+		indexes <- i
+	}
+	close(indexes)
+
+	workers := maxConcurrentSweepRuns
+	if workers > len(runIDs) {
+		workers = len(runIDs)
+	}
+
+	done := make(chan struct{})
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range indexes {
+				req := requests[i]
+				cfg := orchestrator.RunConfig{
+					RunID:        runIDs[i],
+					Model:        model,
+					InstanceType: instTypes[req.InstanceTypeName],
+					Request:      &req,
+				}
+				if err := s.orch.Execute(context.Background(), cfg); err != nil {
+					log.Printf("sweep %s: run %s failed: %v", sweepID, runIDs[i], err)
+				}
+			}
+			done <- struct{}{}
+		}()
+	}
+	for w := 0; w < workers; w++ {
+		<-done
+	}
+}
+
+// handleGetSweep aggregates a sweep's child run statuses and derives a
+// Pareto frontier over (throughput, cost, latency p50) from the
+// persisted metrics of its completed runs.
+func (s *Server) handleGetSweep(w http.ResponseWriter, r *http.Request) {
+	sweepID := r.PathValue("id")
+	ctx := r.Context()
+
+	sweep, err := s.repo.GetSweep(ctx, sweepID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
+		return
+	}
+	if sweep == nil {
+		writeError(w, &apiError{http.StatusNotFound, codeSweepNotFound, "sweep not found"})
+		return
+	}
+
+	runs, err := s.repo.ListSweepRuns(ctx, sweepID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "list sweep runs failed"})
+		return
+	}
+
+	pricing, err := s.repo.ListPricing(ctx, defaultSweepPricingRegion)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "pricing query failed"})
+		return
+	}
+	hourlyRate := make(map[string]float64, len(pricing))
+	for _, p := range pricing {
+		hourlyRate[p.InstanceTypeName] = p.OnDemandHourlyUSD
+	}
+
+	statusCounts := make(map[string]int)
+	var points []analysis.ParetoPoint
+	for _, run := range runs {
+		statusCounts[run.Status]++
+
+		if run.Metrics == nil || run.Metrics.ThroughputAggregateTPS == nil || run.Metrics.E2ELatencyP50Ms == nil {
+			continue
+		}
+		rate, ok := hourlyRate[run.InstanceTypeName]
+		if !ok {
+			continue
+		}
+		points = append(points, analysis.ParetoPoint{
+			Label:         run.ID,
+			ThroughputTPS: *run.Metrics.ThroughputAggregateTPS,
+			CostHourlyUSD: rate,
+			LatencyP50Ms:  *run.Metrics.E2ELatencyP50Ms,
+		})
+	}
+	frontier := analysis.ParetoFrontier(points)
+	if frontier == nil {
+		frontier = []analysis.ParetoPoint{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sweep_id":        sweep.ID,
+		"created_at":      sweep.CreatedAt,
+		"total_runs":      len(runs),
+		"status_counts":   statusCounts,
+		"runs":            runs,
+		"pareto_frontier": frontier,
+	})
+}
+
+// handleGetSweepReport pivots a sweep's child runs into a model x
+// quantization (rows) by instance-type x tensor-parallel-degree (cols)
+// grid of TPS/TTFT/cost, so a caller can eyeball the whole matrix instead
+// of cross-referencing handleGetSweep's flat run list by hand.
+func (s *Server) handleGetSweepReport(w http.ResponseWriter, r *http.Request) {
+	sweepID := r.PathValue("id")
+	ctx := r.Context()
+
+	sweep, err := s.repo.GetSweep(ctx, sweepID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "query failed"})
+		return
+	}
+	if sweep == nil {
+		writeError(w, &apiError{http.StatusNotFound, codeSweepNotFound, "sweep not found"})
+		return
+	}
+
+	runs, err := s.repo.ListSweepRuns(ctx, sweepID)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "list sweep runs failed"})
+		return
+	}
+
+	pricing, err := s.repo.ListPricing(ctx, defaultSweepPricingRegion)
+	if err != nil {
+		writeError(w, &apiError{http.StatusInternalServerError, codeInternal, "pricing query failed"})
+		return
+	}
+	hourlyRate := make(map[string]float64, len(pricing))
+	for _, p := range pricing {
+		hourlyRate[p.InstanceTypeName] = p.OnDemandHourlyUSD
+	}
+
+	points := make([]analysis.PivotPoint, 0, len(runs))
+	for _, run := range runs {
+		quant := "none"
+		if run.Quantization != nil {
+			quant = *run.Quantization
+		}
+		row := fmt.Sprintf("%s (%s)", run.ModelHfID, quant)
+		col := fmt.Sprintf("%s (tp%d)", run.InstanceTypeName, run.TensorParallelDegree)
+
+		cell := analysis.PivotCell{RunID: run.ID}
+		if run.Metrics != nil {
+			cell.ThroughputTPS = run.Metrics.ThroughputAggregateTPS
+			cell.TTFTP50Ms = run.Metrics.TTFTP50Ms
+		}
+		if rate, ok := hourlyRate[run.InstanceTypeName]; ok {
+			cell.CostHourlyUSD = &rate
+		}
+		points = append(points, analysis.PivotPoint{Row: row, Col: col, Cell: cell})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sweep_id": sweep.ID,
+		"report":   analysis.Pivot(points),
+	})
+}
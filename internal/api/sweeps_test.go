@@ -0,0 +1,242 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/accelbench/accelbench/internal/analysis"
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+func TestHandleCreateSweep_ExpandsCartesianProduct(t *testing.T) {
+	_, mux := setupServer()
+
+	spec := database.SweepRequest{
+		ModelHfID:            "meta-llama/Llama-3.1-8B",
+		ModelHfRevision:      "abc123",
+		Framework:            []string{"vllm"},
+		FrameworkVersion:     []string{"v0.6.0"},
+		TensorParallelDegree: []int{1},
+		Concurrency:          []int{1, 2, 4},
+		InputSequenceLength:  []int{512},
+		OutputSequenceLength: []int{256},
+		InstanceTypeName:     []string{"g5.xlarge"},
+		DatasetName:          "sharegpt",
+		RunType:              "sweep",
+	}
+	b, _ := json.Marshal(spec)
+
+	req := httptest.NewRequest("POST", "/api/v1/runs:batch", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+
+	var resp struct {
+		SweepID string   `json:"sweep_id"`
+		RunIDs  []string `json:"run_ids"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.SweepID == "" {
+		t.Error("response missing sweep_id")
+	}
+	if len(resp.RunIDs) != 3 {
+		t.Errorf("len(run_ids) = %d, want 3 (one per concurrency value)", len(resp.RunIDs))
+	}
+}
+
+func TestHandleCreateSweep_UnknownInstanceTypeCreatesNoRuns(t *testing.T) {
+	srv, mux := setupServer()
+
+	spec := database.SweepRequest{
+		ModelHfID:            "meta-llama/Llama-3.1-8B",
+		ModelHfRevision:      "abc123",
+		Framework:            []string{"vllm"},
+		FrameworkVersion:     []string{"v0.6.0"},
+		TensorParallelDegree: []int{1},
+		Concurrency:          []int{1, 2},
+		InputSequenceLength:  []int{512},
+		OutputSequenceLength: []int{256},
+		InstanceTypeName:     []string{"g5.xlarge", "nonexistent.xlarge"},
+		DatasetName:          "sharegpt",
+		RunType:              "sweep",
+	}
+	b, _ := json.Marshal(spec)
+
+	req := httptest.NewRequest("POST", "/api/v1/runs:batch", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusNotFound, w.Body.String())
+	}
+
+	items, _, err := srv.repo.ListRuns(req.Context(), database.RunFilter{})
+	if err != nil {
+		t.Fatalf("ListRuns: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("len(items) = %d, want 0 (sweep must be atomic: an invalid instance type creates no runs)", len(items))
+	}
+}
+
+func TestHandleCreateSweep_MissingDimensionRejected(t *testing.T) {
+	_, mux := setupServer()
+
+	spec := database.SweepRequest{
+		ModelHfID:        "meta-llama/Llama-3.1-8B",
+		Framework:        []string{"vllm"},
+		FrameworkVersion: []string{"v0.6.0"},
+		// TensorParallelDegree intentionally omitted.
+		Concurrency:          []int{1},
+		InputSequenceLength:  []int{512},
+		OutputSequenceLength: []int{256},
+		InstanceTypeName:     []string{"g5.xlarge"},
+	}
+	b, _ := json.Marshal(spec)
+
+	req := httptest.NewRequest("POST", "/api/v1/runs:batch", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleGetSweep_NotFound(t *testing.T) {
+	_, mux := setupServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/sweeps/nonexistent", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleGetSweep_AggregatesChildStatuses(t *testing.T) {
+	_, mux := setupServer()
+
+	spec := database.SweepRequest{
+		ModelHfID:            "meta-llama/Llama-3.1-8B",
+		ModelHfRevision:      "abc123",
+		Framework:            []string{"vllm"},
+		FrameworkVersion:     []string{"v0.6.0"},
+		TensorParallelDegree: []int{1},
+		Concurrency:          []int{1, 2},
+		InputSequenceLength:  []int{512},
+		OutputSequenceLength: []int{256},
+		InstanceTypeName:     []string{"g5.xlarge"},
+		DatasetName:          "sharegpt",
+		RunType:              "sweep",
+	}
+	b, _ := json.Marshal(spec)
+
+	createReq := httptest.NewRequest("POST", "/api/v1/runs:batch", bytes.NewReader(b))
+	createW := httptest.NewRecorder()
+	mux.ServeHTTP(createW, createReq)
+
+	var createResp struct {
+		SweepID string `json:"sweep_id"`
+	}
+	if err := json.NewDecoder(createW.Body).Decode(&createResp); err != nil {
+		t.Fatalf("decode create response: %v", err)
+	}
+
+	getReq := httptest.NewRequest("GET", "/api/v1/sweeps/"+createResp.SweepID, nil)
+	getW := httptest.NewRecorder()
+	mux.ServeHTTP(getW, getReq)
+
+	if getW.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", getW.Code, http.StatusOK, getW.Body.String())
+	}
+
+	var detail struct {
+		SweepID      string                    `json:"sweep_id"`
+		TotalRuns    int                       `json:"total_runs"`
+		StatusCounts map[string]int            `json:"status_counts"`
+		Runs         []database.SweepRunDetail `json:"runs"`
+	}
+	if err := json.NewDecoder(getW.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode sweep detail: %v", err)
+	}
+	if detail.TotalRuns != 2 {
+		t.Errorf("TotalRuns = %d, want 2", detail.TotalRuns)
+	}
+	if len(detail.Runs) != 2 {
+		t.Errorf("len(Runs) = %d, want 2", len(detail.Runs))
+	}
+}
+
+func TestHandleGetSweepReport_NotFound(t *testing.T) {
+	_, mux := setupServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/sweeps/nonexistent/report", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleGetSweepReport_PivotsByModelAndInstanceType(t *testing.T) {
+	_, mux := setupServer()
+
+	spec := database.SweepRequest{
+		ModelHfID:            "meta-llama/Llama-3.1-8B",
+		ModelHfRevision:      "abc123",
+		Framework:            []string{"vllm"},
+		FrameworkVersion:     []string{"v0.6.0"},
+		TensorParallelDegree: []int{1, 2},
+		Concurrency:          []int{1},
+		InputSequenceLength:  []int{512},
+		OutputSequenceLength: []int{256},
+		InstanceTypeName:     []string{"g5.xlarge"},
+		DatasetName:          "sharegpt",
+		RunType:              "sweep",
+	}
+	b, _ := json.Marshal(spec)
+
+	createReq := httptest.NewRequest("POST", "/api/v1/runs:batch", bytes.NewReader(b))
+	createW := httptest.NewRecorder()
+	mux.ServeHTTP(createW, createReq)
+
+	var createResp struct {
+		SweepID string `json:"sweep_id"`
+	}
+	if err := json.NewDecoder(createW.Body).Decode(&createResp); err != nil {
+		t.Fatalf("decode create response: %v", err)
+	}
+
+	getReq := httptest.NewRequest("GET", "/api/v1/sweeps/"+createResp.SweepID+"/report", nil)
+	getW := httptest.NewRecorder()
+	mux.ServeHTTP(getW, getReq)
+
+	if getW.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", getW.Code, http.StatusOK, getW.Body.String())
+	}
+
+	var resp struct {
+		SweepID string              `json:"sweep_id"`
+		Report  analysis.PivotTable `json:"report"`
+	}
+	if err := json.NewDecoder(getW.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if len(resp.Report.Rows) != 1 {
+		t.Errorf("len(Report.Rows) = %d, want 1 (single model/quantization)", len(resp.Report.Rows))
+	}
+	if len(resp.Report.Cols) != 2 {
+		t.Errorf("len(Report.Cols) = %d, want 2 (one per tensor-parallel degree)", len(resp.Report.Cols))
+	}
+}
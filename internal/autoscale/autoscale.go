@@ -0,0 +1,86 @@
+// Package autoscale implements the HPA-style replica scaling decision used
+// by orchestrator.ExecuteAutoscale. It is deliberately pure: no Kubernetes
+// client, no database access, just the same math the Kubernetes
+// HorizontalPodAutoscaler controller itself uses to turn a metric reading
+// into a replica count, so it can be unit tested without a cluster and
+// reused if a future caller wants to simulate a scaling trajectory offline.
+package autoscale
+
+import "math"
+
+// defaultTolerance is the fractional usageRatio band around 1.0 within
+// which Decide leaves the replica count unchanged, matching the
+// kube-controller-manager default for
+// --horizontal-pod-autoscaler-tolerance. Without a tolerance band, a metric
+// oscillating within normal noise would thrash the replica count up and
+// down every tick.
+const defaultTolerance = 0.1
+
+// Decision is the outcome of evaluating one scaling tick.
+type Decision struct {
+	// DesiredReplicas is the replica count this tick recommends, already
+	// clamped to [minReplicas, maxReplicas].
+	DesiredReplicas int
+
+	// UsageRatio is currentMetric/targetMetric, the raw signal the
+	// decision was derived from. Zero when targetMetric or
+	// currentReplicas was non-positive, since no meaningful ratio exists.
+	UsageRatio float64
+
+	// WithinTolerance is true when UsageRatio fell inside
+	// [1-tolerance, 1+tolerance], in which case DesiredReplicas equals
+	// currentReplicas rather than a freshly computed value.
+	WithinTolerance bool
+}
+
+// Decide computes the next replica count for a deployment currently running
+// currentReplicas, given the metric value it's reporting (currentMetric)
+// against the per-replica target (targetMetric). tolerance is the
+// fractional band around a usageRatio of 1.0 treated as close enough to
+// leave alone; a non-positive tolerance falls back to defaultTolerance. The
+// result is clamped to [minReplicas, maxReplicas].
+//
+// The formula mirrors the Kubernetes HPA controller's own algorithm:
+//
+//	desiredReplicas = ceil(currentReplicas * (currentMetric / targetMetric))
+//
+// A non-positive targetMetric or currentReplicas has no meaningful ratio to
+// scale by, so Decide leaves the replica count unchanged rather than
+// dividing by zero or scaling from nothing.
+func Decide(currentReplicas int, currentMetric, targetMetric, tolerance float64, minReplicas, maxReplicas int) Decision {
+	if tolerance <= 0 {
+		tolerance = defaultTolerance
+	}
+	if targetMetric <= 0 || currentReplicas <= 0 {
+		return Decision{
+			DesiredReplicas: clamp(currentReplicas, minReplicas, maxReplicas),
+			WithinTolerance: true,
+		}
+	}
+
+	ratio := currentMetric / targetMetric
+	if math.Abs(ratio-1) <= tolerance {
+		return Decision{
+			DesiredReplicas: clamp(currentReplicas, minReplicas, maxReplicas),
+			UsageRatio:      ratio,
+			WithinTolerance: true,
+		}
+	}
+
+	desired := int(math.Ceil(float64(currentReplicas) * ratio))
+	return Decision{
+		DesiredReplicas: clamp(desired, minReplicas, maxReplicas),
+		UsageRatio:      ratio,
+	}
+}
+
+// clamp bounds n to [lo, hi].
+func clamp(n, lo, hi int) int {
+	if n < lo {
+		return lo
+	}
+	if n > hi {
+		return hi
+	}
+	return n
+}
@@ -0,0 +1,95 @@
+package autoscale
+
+import "testing"
+
+func TestDecide_ScalesUpPastTolerance(t *testing.T) {
+	// currentMetric is double targetMetric: usageRatio=2, well outside the
+	// default 10% tolerance band, so desired = ceil(2 * 2.0) = 4.
+	d := Decide(2, 20, 10, 0, 1, 8)
+	if d.WithinTolerance {
+		t.Error("expected usageRatio=2.0 to fall outside tolerance")
+	}
+	if d.DesiredReplicas != 4 {
+		t.Errorf("DesiredReplicas = %d, want 4", d.DesiredReplicas)
+	}
+	if d.UsageRatio != 2.0 {
+		t.Errorf("UsageRatio = %v, want 2.0", d.UsageRatio)
+	}
+}
+
+func TestDecide_ScalesDownPastTolerance(t *testing.T) {
+	// usageRatio=0.25, desired = ceil(4 * 0.25) = 1.
+	d := Decide(4, 5, 20, 0, 1, 8)
+	if d.WithinTolerance {
+		t.Error("expected usageRatio=0.25 to fall outside tolerance")
+	}
+	if d.DesiredReplicas != 1 {
+		t.Errorf("DesiredReplicas = %d, want 1", d.DesiredReplicas)
+	}
+}
+
+func TestDecide_WithinToleranceLeavesReplicasUnchanged(t *testing.T) {
+	// usageRatio=1.05, within the default 10% tolerance band.
+	d := Decide(4, 105, 100, 0, 1, 8)
+	if !d.WithinTolerance {
+		t.Error("expected usageRatio=1.05 to fall within the default tolerance")
+	}
+	if d.DesiredReplicas != 4 {
+		t.Errorf("DesiredReplicas = %d, want 4 (unchanged)", d.DesiredReplicas)
+	}
+}
+
+func TestDecide_CustomToleranceNarrowsTheBand(t *testing.T) {
+	// usageRatio=1.05 falls outside a tight 1% tolerance, even though it's
+	// within the package default.
+	d := Decide(4, 105, 100, 0.01, 1, 8)
+	if d.WithinTolerance {
+		t.Error("expected usageRatio=1.05 to fall outside a 1% tolerance")
+	}
+	if d.DesiredReplicas != 5 {
+		t.Errorf("DesiredReplicas = %d, want 5", d.DesiredReplicas)
+	}
+}
+
+func TestDecide_ClampsToMaxReplicas(t *testing.T) {
+	// usageRatio=10 would want 10 replicas, but maxReplicas caps it at 8.
+	d := Decide(1, 100, 10, 0, 1, 8)
+	if d.DesiredReplicas != 8 {
+		t.Errorf("DesiredReplicas = %d, want 8 (clamped to max)", d.DesiredReplicas)
+	}
+}
+
+func TestDecide_ClampsToMinReplicas(t *testing.T) {
+	// usageRatio=0.1 would want 1 replica from currentReplicas=10, but
+	// minReplicas keeps it at 2.
+	d := Decide(10, 1, 10, 0, 2, 16)
+	if d.DesiredReplicas != 2 {
+		t.Errorf("DesiredReplicas = %d, want 2 (clamped to min)", d.DesiredReplicas)
+	}
+}
+
+func TestDecide_NonPositiveTargetLeavesReplicasUnchanged(t *testing.T) {
+	d := Decide(3, 50, 0, 0, 1, 8)
+	if !d.WithinTolerance {
+		t.Error("expected a non-positive target to report WithinTolerance")
+	}
+	if d.DesiredReplicas != 3 {
+		t.Errorf("DesiredReplicas = %d, want 3 (unchanged)", d.DesiredReplicas)
+	}
+}
+
+func TestDecide_NonPositiveCurrentReplicasLeavesReplicasUnchanged(t *testing.T) {
+	d := Decide(0, 50, 10, 0, 1, 8)
+	if d.DesiredReplicas != 1 {
+		t.Errorf("DesiredReplicas = %d, want 1 (clamped to min)", d.DesiredReplicas)
+	}
+}
+
+func TestDecide_ZeroToleranceFallsBackToDefault(t *testing.T) {
+	// tolerance=0 means "use the package default", not "zero tolerance" —
+	// usageRatio=1.05 should still read as within-tolerance.
+	d := Decide(4, 105, 100, 0, 1, 8)
+	if !d.WithinTolerance {
+		t.Error("expected tolerance=0 to fall back to the default 10% band")
+	}
+}
@@ -0,0 +1,194 @@
+// Package callbacks delivers HTTP notifications for a BenchmarkRun's
+// terminal status transitions, so an external caller (a CI job, a pipeline
+// step, a Slack notifier) can be woken up instead of polling GetRun. It is
+// deliberately its own package rather than a method on orchestrator or api,
+// mirroring internal/policy: the outbox rows it drains (database.RunCallback)
+// are written by several different Repo backends' UpdateRunStatus/
+// PersistMetrics, and draining them is a concern of its own.
+package callbacks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// tickInterval is how often the Worker polls for due callbacks when its
+// repo doesn't implement database.RunCallbackClaimer.
+const tickInterval = 5 * time.Second
+
+// batchSize bounds how many callbacks a single tick claims/lists, so one
+// slow receiver's retry storm can't starve every other run's notification.
+const batchSize = 50
+
+// SignatureHeader carries an HMAC-SHA256 signature (hex-encoded) of the
+// request body, keyed by the originating run's CallbackToken, so a
+// receiver can verify a callback actually came from this server. Absent
+// entirely when the run didn't set a CallbackToken.
+const SignatureHeader = "X-AccelBench-Signature"
+
+// deliverTimeout bounds how long a single callback POST may take, so a
+// receiver that never responds can't wedge the Worker's tick loop.
+const deliverTimeout = 10 * time.Second
+
+// Worker drains the database.RunCallback outbox and POSTs each one to its
+// run's CallbackURL. If repo implements database.RunCallbackClaimer, Worker
+// claims due callbacks through it so more than one Worker process can
+// safely poll the same backend; otherwise it falls back to plain
+// ListDueRunCallbacks, which is safe only when exactly one Worker process
+// is polling that backend (mirroring orchestrator.Scheduler and
+// policy.Dispatcher's own leased/non-leased split).
+type Worker struct {
+	repo   database.Repo
+	client *http.Client
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewWorker creates a Worker. Call Start to begin ticking.
+func NewWorker(repo database.Repo) *Worker {
+	return &Worker{
+		repo:   repo,
+		client: &http.Client{Timeout: deliverTimeout},
+		done:   make(chan struct{}),
+	}
+}
+
+// Start begins the background tick loop. Safe to call only once.
+func (w *Worker) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	go w.loop(ctx)
+}
+
+// Stop cancels the tick loop and waits for it to exit. A delivery already
+// in flight is allowed to finish.
+func (w *Worker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	<-w.done
+}
+
+func (w *Worker) loop(ctx context.Context) {
+	defer close(w.done)
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	w.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.tick(ctx)
+		}
+	}
+}
+
+// tick claims every due callback and attempts to deliver each one.
+func (w *Worker) tick(ctx context.Context) {
+	now := time.Now().UTC()
+
+	if claimer, ok := w.repo.(database.RunCallbackClaimer); ok {
+		due, err := claimer.ClaimDueRunCallbacks(ctx, now, batchSize)
+		if err != nil {
+			log.Printf("callbacks: claim due run callbacks: %v", err)
+			return
+		}
+		for _, cb := range due {
+			w.deliver(ctx, cb)
+		}
+		return
+	}
+
+	due, err := w.repo.ListDueRunCallbacks(ctx, now, batchSize)
+	if err != nil {
+		log.Printf("callbacks: list due run callbacks: %v", err)
+		return
+	}
+	for _, cb := range due {
+		w.deliver(ctx, cb)
+	}
+}
+
+// runCallbackPayload is the JSON body POSTed to a run's CallbackURL.
+type runCallbackPayload struct {
+	RunID          string                      `json:"run_id"`
+	Status         string                      `json:"status"`
+	MetricsSummary database.RunCallbackSummary `json:"metrics_summary"`
+}
+
+// deliver POSTs cb's payload to its CallbackURL and records the outcome.
+// A non-2xx response or a transport error counts as a failed attempt and
+// is retried at an exponential backoff via database.RecordRunCallbackResult;
+// once Attempts reaches database.MaxCallbackAttempts the row is no longer
+// returned by ListDueRunCallbacks/ClaimDueRunCallbacks, and an operator has
+// to notice it via LastError.
+func (w *Worker) deliver(ctx context.Context, cb database.RunCallback) {
+	body, err := json.Marshal(runCallbackPayload{
+		RunID:          cb.RunID,
+		Status:         cb.Status,
+		MetricsSummary: cb.Summary,
+	})
+	if err != nil {
+		log.Printf("callbacks: marshal payload for run %s: %v", cb.RunID, err)
+		w.recordResult(ctx, cb, fmt.Errorf("marshal payload: %w", err))
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cb.URL, bytes.NewReader(body))
+	if err != nil {
+		w.recordResult(ctx, cb, fmt.Errorf("build request: %w", err))
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cb.Token != "" {
+		req.Header.Set(SignatureHeader, Sign(cb.Token, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		w.recordResult(ctx, cb, fmt.Errorf("deliver: %w", err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		w.recordResult(ctx, cb, fmt.Errorf("receiver returned %d", resp.StatusCode))
+		return
+	}
+	w.recordResult(ctx, cb, nil)
+}
+
+// recordResult calls database.RecordRunCallbackResult, computing the next
+// retry time from database's backoff schedule when deliverErr is non-nil.
+func (w *Worker) recordResult(ctx context.Context, cb database.RunCallback, deliverErr error) {
+	var nextAttemptAt *time.Time
+	if deliverErr != nil {
+		t := time.Now().Add(database.NextCallbackBackoff(cb.Attempts + 1))
+		nextAttemptAt = &t
+		log.Printf("callbacks: deliver run %s callback to %s: %v", cb.RunID, cb.URL, deliverErr)
+	}
+	if err := w.repo.RecordRunCallbackResult(ctx, cb.ID, deliverErr, nextAttemptAt); err != nil {
+		log.Printf("callbacks: record result for run %s callback %s: %v", cb.RunID, cb.ID, err)
+	}
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of body keyed by token, sent in
+// SignatureHeader so a receiver can verify a callback actually came from
+// this server and wasn't forged or tampered with in transit.
+func Sign(token string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,115 @@
+// Package collector turns a run's raw per-device accelerator samples
+// (scraped from the DCGM exporter or neuron-monitor sidecar by
+// internal/orchestrator's GPUScraper) into the extended percentile,
+// energy, and thermal-headroom fields PersistMetrics folds onto
+// BenchmarkMetrics. It depends only on internal/database, the same way
+// internal/recommend does, so internal/orchestrator can call it without
+// a cycle.
+package collector
+
+import (
+	"sort"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// RollupResult is the extended per-device accelerator summary Rollup
+// computes from a run's raw AcceleratorSample series.
+type RollupResult struct {
+	SMUtilP50Pct         *float64
+	SMUtilP95Pct         *float64
+	SMUtilMaxPct         *float64
+	EnergyConsumedJoules *float64
+	ThermalHeadroomC     *float64
+}
+
+// Rollup reduces samples into RollupResult. thermalMaxC is the
+// accelerator's safe operating temperature ceiling (see ThermalMaxC); pass
+// 0 to skip the thermal headroom calculation. Returns a zero RollupResult
+// if samples is empty.
+func Rollup(samples []database.AcceleratorSample, thermalMaxC float64) RollupResult {
+	var res RollupResult
+	if len(samples) == 0 {
+		return res
+	}
+
+	var smVals []float64
+	var maxTemp float64
+	haveTemp := false
+	for _, s := range samples {
+		if s.SMUtilPct != nil {
+			smVals = append(smVals, *s.SMUtilPct)
+		}
+		if s.TempC != nil && (!haveTemp || *s.TempC > maxTemp) {
+			maxTemp = *s.TempC
+			haveTemp = true
+		}
+	}
+
+	if len(smVals) > 0 {
+		sorted := append([]float64(nil), smVals...)
+		sort.Float64s(sorted)
+		p50 := nearestRank(sorted, 50)
+		p95 := nearestRank(sorted, 95)
+		max := sorted[len(sorted)-1]
+		res.SMUtilP50Pct = &p50
+		res.SMUtilP95Pct = &p95
+		res.SMUtilMaxPct = &max
+	}
+
+	res.EnergyConsumedJoules = energyJoules(samples)
+
+	if haveTemp && thermalMaxC > 0 {
+		headroom := thermalMaxC - maxTemp
+		res.ThermalHeadroomC = &headroom
+	}
+
+	return res
+}
+
+// energyJoules integrates power (W) over time per device index via the
+// trapezoidal rule between consecutive samples, then sums across devices.
+// Returns nil if no sample carries a power reading.
+func energyJoules(samples []database.AcceleratorSample) *float64 {
+	byDevice := make(map[int][]database.AcceleratorSample)
+	var anyPower bool
+	for _, s := range samples {
+		if s.PowerW == nil {
+			continue
+		}
+		anyPower = true
+		byDevice[s.DeviceIndex] = append(byDevice[s.DeviceIndex], s)
+	}
+	if !anyPower {
+		return nil
+	}
+
+	var total float64
+	for _, ds := range byDevice {
+		sort.Slice(ds, func(i, j int) bool { return ds[i].Timestamp.Before(ds[j].Timestamp) })
+		for i := 1; i < len(ds); i++ {
+			dt := ds[i].Timestamp.Sub(ds[i-1].Timestamp).Seconds()
+			if dt <= 0 {
+				continue
+			}
+			avgPower := (*ds[i-1].PowerW + *ds[i].PowerW) / 2
+			total += avgPower * dt
+		}
+	}
+	return &total
+}
+
+// nearestRank mirrors internal/orchestrator's gpuscraper.nearestRank —
+// same nearest-rank percentile, duplicated rather than imported to avoid a
+// cycle (orchestrator calls into this package, not the other way around).
+func nearestRank(sorted []float64, p float64) float64 {
+	rank := (p / 100.0) * float64(len(sorted))
+	idx := int(rank)
+	if idx > 0 {
+		idx--
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
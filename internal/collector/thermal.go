@@ -0,0 +1,35 @@
+package collector
+
+// acceleratorThermalMaxC is each accelerator's vendor-published maximum
+// safe junction/operating temperature in Celsius.
+//
+// This mirrors internal/recommend/throughput.go's acceleratorSpecs table:
+// internal/orchestrator already owns the scrape path this data describes,
+// but this package must not import orchestrator (orchestrator calls into
+// collector, not the other way around), so duplicating the (small,
+// rarely-changing) table is the lesser evil.
+var acceleratorThermalMaxC = map[string]float64{
+	"A10G":        83,
+	"A100":        85,
+	"H100":        90,
+	"H200":        90,
+	"L40S":        90,
+	"L4":          85,
+	"Trainium2":   95,
+	"Inferentia2": 95,
+}
+
+// defaultThermalMaxC is used for an accelerator name the table doesn't
+// recognize, so an unfamiliar instance type still gets a conservative
+// thermal headroom estimate rather than none at all.
+const defaultThermalMaxC = 85
+
+// ThermalMaxC returns acceleratorName's safe operating temperature
+// ceiling, falling back to defaultThermalMaxC when the name isn't in
+// acceleratorThermalMaxC.
+func ThermalMaxC(acceleratorName string) float64 {
+	if c, ok := acceleratorThermalMaxC[acceleratorName]; ok {
+		return c
+	}
+	return defaultThermalMaxC
+}
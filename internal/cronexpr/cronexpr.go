@@ -0,0 +1,159 @@
+// Package cronexpr parses standard 5-field cron expressions (minute hour
+// day-of-month month day-of-week) and computes the next matching time,
+// for database.BenchmarkPolicy.CronExpr and internal/policy's dispatch
+// loop. It deliberately supports only the common subset (*, lists,
+// ranges, and */step) rather than vendoring a full-featured cron library,
+// since policies only need "next occurrence after now", not parsing
+// non-standard extensions like @hourly or seconds.
+package cronexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field bounds, in cron's own field order.
+var fieldBounds = [5][2]int{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 6},  // day of week (0 = Sunday)
+}
+
+// Schedule is a parsed cron expression, ready to compute successive
+// occurrences via Next without re-parsing the source string each time.
+type Schedule struct {
+	minute, hour, dom, month, dow [60]bool // only the first N entries of each array are ever consulted, per fieldBounds
+}
+
+// Parse validates and compiles a standard 5-field cron expression
+// ("minute hour dom month dow", e.g. "0 2 * * 0" for every Sunday at
+// 02:00). Returns an error if expr doesn't have exactly 5 fields or any
+// field is out of range.
+func Parse(expr string) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cronexpr: expected 5 fields (minute hour dom month dow), got %d", len(fields))
+	}
+
+	var s Schedule
+	masks := [5]*[60]bool{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, field := range fields {
+		if err := parseField(field, fieldBounds[i][0], fieldBounds[i][1], masks[i]); err != nil {
+			return nil, fmt.Errorf("cronexpr: field %d (%q): %w", i, field, err)
+		}
+	}
+	return &s, nil
+}
+
+// parseField sets mask[v] = true for every value v that matches one
+// comma-separated term of field, each of which is "*", "*/step", "a-b",
+// "a-b/step", or a single integer, all within [lo, hi].
+func parseField(field string, lo, hi int, mask *[60]bool) error {
+	for _, term := range strings.Split(field, ",") {
+		rangeLo, rangeHi, step := lo, hi, 1
+
+		base, stepStr, hasStep := strings.Cut(term, "/")
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = n
+		}
+
+		switch {
+		case base == "*":
+			// rangeLo/rangeHi already cover the full field range.
+		case strings.Contains(base, "-"):
+			loStr, hiStr, _ := strings.Cut(base, "-")
+			a, err := strconv.Atoi(loStr)
+			if err != nil {
+				return fmt.Errorf("invalid range start %q", loStr)
+			}
+			b, err := strconv.Atoi(hiStr)
+			if err != nil {
+				return fmt.Errorf("invalid range end %q", hiStr)
+			}
+			rangeLo, rangeHi = a, b
+		default:
+			n, err := strconv.Atoi(base)
+			if err != nil {
+				return fmt.Errorf("invalid value %q", base)
+			}
+			rangeLo, rangeHi = n, n
+		}
+
+		if rangeLo < lo || rangeHi > hi || rangeLo > rangeHi {
+			return fmt.Errorf("value out of range [%d, %d]", lo, hi)
+		}
+		for v := rangeLo; v <= rangeHi; v += step {
+			mask[v] = true
+		}
+	}
+	return nil
+}
+
+// maxLookahead bounds how far into the future Next searches before giving
+// up, so an expression that can never match (e.g. "0 0 31 2 *", Feb 31st)
+// fails fast instead of looping forever.
+const maxLookahead = 4 * 365 * 24 * 60 // 4 years, in minutes
+
+// Next returns the first time strictly after from that matches the
+// schedule, truncated to the minute (cron has no finer resolution). Both
+// from and the result are in from's own location; policies are expected
+// to pass a UTC time, per BenchmarkPolicy.CronExpr's documented
+// convention.
+func (s *Schedule) Next(from time.Time) (time.Time, error) {
+	t := from.Truncate(time.Minute).Add(time.Minute)
+	for i := 0; i < maxLookahead; i++ {
+		if s.matches(t) {
+			return t, nil
+		}
+		t = t.Add(time.Minute)
+	}
+	return time.Time{}, fmt.Errorf("cronexpr: no match found within %d years", maxLookahead/60/24/365)
+}
+
+// matches reports whether t satisfies every field of the schedule. Per
+// standard cron semantics, when both dom and dow are restricted (not
+// "*"), a match on either is sufficient; when either is unrestricted, the
+// other alone decides.
+func (s *Schedule) matches(t time.Time) bool {
+	if !s.minute[t.Minute()] || !s.hour[t.Hour()] || !s.month[int(t.Month())] {
+		return false
+	}
+	domOK := s.dom[t.Day()]
+	dowOK := s.dow[int(t.Weekday())]
+	if s.isFullDom() || s.isFullDow() {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
+
+func (s *Schedule) isFullDom() bool { return countTrue(s.dom[1:32]) == 31 }
+func (s *Schedule) isFullDow() bool { return countTrue(s.dow[0:7]) == 7 }
+
+func countTrue(bs []bool) int {
+	n := 0
+	for _, b := range bs {
+		if b {
+			n++
+		}
+	}
+	return n
+}
+
+// Next parses expr and returns the first match strictly after from, in
+// one call, for a caller that doesn't need to reuse the compiled
+// Schedule across multiple calls.
+func Next(expr string, from time.Time) (time.Time, error) {
+	s, err := Parse(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return s.Next(from)
+}
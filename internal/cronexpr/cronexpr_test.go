@@ -0,0 +1,86 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParseTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	tm, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		t.Fatalf("parse time %q: %v", s, err)
+	}
+	return tm
+}
+
+func TestNext_WeeklySunday(t *testing.T) {
+	// "every Sunday at 02:00" from a Wednesday should land on the
+	// following Sunday, not the same week.
+	from := mustParseTime(t, "2026-07-29 10:00") // a Wednesday
+	got, err := Next("0 2 * * 0", from)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	want := mustParseTime(t, "2026-08-02 02:00") // the next Sunday
+	if !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+}
+
+func TestNext_EveryFiveMinutes(t *testing.T) {
+	from := mustParseTime(t, "2026-07-29 10:02")
+	got, err := Next("*/5 * * * *", from)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	want := mustParseTime(t, "2026-07-29 10:05")
+	if !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+}
+
+func TestNext_SkipsToMatchingHour(t *testing.T) {
+	from := mustParseTime(t, "2026-07-29 23:30")
+	got, err := Next("0 2 * * *", from)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	want := mustParseTime(t, "2026-07-30 02:00")
+	if !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+}
+
+func TestNext_DomOrDowIsOr(t *testing.T) {
+	// "1st of the month OR a Friday" — standard cron semantics treat a
+	// restricted dom/dow pair as OR, not AND, when both are non-"*".
+	from := mustParseTime(t, "2026-07-29 00:00") // a Wednesday
+	got, err := Next("0 0 1 * 5", from)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	want := mustParseTime(t, "2026-07-31 00:00") // the next Friday, before the 1st
+	if !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+}
+
+func TestParse_RejectsWrongFieldCount(t *testing.T) {
+	if _, err := Parse("0 2 * *"); err == nil {
+		t.Error("expected error for a 4-field expression")
+	}
+}
+
+func TestParse_RejectsOutOfRangeValue(t *testing.T) {
+	if _, err := Parse("60 0 * * *"); err == nil {
+		t.Error("expected error for minute=60")
+	}
+}
+
+func TestNext_ImpossibleExpressionErrors(t *testing.T) {
+	// February never has a 30th day.
+	if _, err := Next("0 0 30 2 *", mustParseTime(t, "2026-01-01 00:00")); err == nil {
+		t.Error("expected an error for an expression that can never match")
+	}
+}
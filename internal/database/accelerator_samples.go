@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AcceleratorSample is one raw per-device observation scraped from the
+// DCGM exporter or neuron-monitor sidecar during a run's execution window
+// — the device-level counterpart to Sample's per-request series. A node
+// with more than one accelerator reports one sample per DeviceIndex per
+// scrape tick instead of collapsing them, so PersistMetrics's rollups and
+// GetAcceleratorSamples's plots can both see per-device behavior. Fields
+// that don't apply to the exporter kind that produced a sample (e.g.
+// NeuronCoreUtilPct on a DCGM sample) are left nil.
+type AcceleratorSample struct {
+	Timestamp         time.Time `json:"timestamp"`
+	DeviceIndex       int       `json:"device_index"`
+	SMUtilPct         *float64  `json:"sm_util_pct,omitempty"`
+	MemUsedGiB        *float64  `json:"mem_used_gib,omitempty"`
+	PowerW            *float64  `json:"power_w,omitempty"`
+	TempC             *float64  `json:"temp_c,omitempty"`
+	NeuronCoreUtilPct *float64  `json:"neuroncore_util_pct,omitempty"`
+	HBMUsedGiB        *float64  `json:"hbm_used_gib,omitempty"`
+}
+
+// insertAcceleratorSamples writes runID's raw accelerator samples within an
+// already-open transaction, so PersistMetrics can commit the summary row
+// and the raw samples atomically.
+func insertAcceleratorSamples(ctx context.Context, tx pgx.Tx, runID string, samples []AcceleratorSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	batch := &pgx.Batch{}
+	for _, s := range samples {
+		batch.Queue(
+			`INSERT INTO accelerator_samples
+			    (run_id, ts, device_index, sm_util, mem_used_gib, power_w, temp_c, neuroncore_util, hbm_used)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			runID, s.Timestamp, s.DeviceIndex, s.SMUtilPct, s.MemUsedGiB, s.PowerW, s.TempC, s.NeuronCoreUtilPct, s.HBMUsedGiB,
+		)
+	}
+	br := tx.SendBatch(ctx, batch)
+	defer br.Close()
+	for range samples {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("insert accelerator sample: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetAcceleratorSamples returns every raw accelerator sample recorded for
+// runID, ordered by device index then timestamp, for later plotting.
+func (r *Repository) GetAcceleratorSamples(ctx context.Context, runID string) ([]AcceleratorSample, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT ts, device_index, sm_util, mem_used_gib, power_w, temp_c, neuroncore_util, hbm_used
+		 FROM accelerator_samples WHERE run_id = $1 ORDER BY device_index, ts`, runID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query accelerator samples: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []AcceleratorSample
+	for rows.Next() {
+		var s AcceleratorSample
+		if err := rows.Scan(&s.Timestamp, &s.DeviceIndex, &s.SMUtilPct, &s.MemUsedGiB, &s.PowerW, &s.TempC, &s.NeuronCoreUtilPct, &s.HBMUsedGiB); err != nil {
+			return nil, fmt.Errorf("scan accelerator sample: %w", err)
+		}
+		samples = append(samples, s)
+	}
+	return samples, rows.Err()
+}
@@ -0,0 +1,84 @@
+package database
+
+import "fmt"
+
+// AutoscaleRequest is the input to a POST /runs:autoscale submission: the
+// same model/instance/framework knobs as SLOSearchRequest, minus the SLO
+// spec, plus the replica bounds and concurrency ramp
+// orchestrator.ExecuteAutoscale drives a single Deployment through.
+type AutoscaleRequest struct {
+	ModelHfID            string            `json:"model_hf_id"`
+	ModelHfRevision      string            `json:"model_hf_revision"`
+	InstanceTypeName     string            `json:"instance_type_name"`
+	Framework            string            `json:"framework"`
+	FrameworkVersion     string            `json:"framework_version"`
+	TensorParallelDegree int               `json:"tensor_parallel_degree"`
+	Quantization         *string           `json:"quantization,omitempty"`
+	InputSequenceLength  int               `json:"input_sequence_length"`
+	OutputSequenceLength int               `json:"output_sequence_length"`
+	DatasetName          string            `json:"dataset_name"`
+	DatasetSpec          *DatasetSpec      `json:"dataset_spec,omitempty"`
+	MaxModelLen          int               `json:"max_model_len,omitempty"`
+	HfToken              string            `json:"hf_token,omitempty"`
+	Plugins              []FrameworkPlugin `json:"plugins,omitempty"`
+
+	// MinReplicas/MaxReplicas bound every scaling decision
+	// orchestrator.autoscale.Decide makes during the ramp.
+	MinReplicas int `json:"min_replicas"`
+	MaxReplicas int `json:"max_replicas"`
+
+	// ConcurrencyStages is the sequence of load levels the ramp steps
+	// through in order, each held for StageDurationSeconds while the
+	// deployment is given a chance to scale toward TargetValue before the
+	// next stage begins.
+	ConcurrencyStages []int `json:"concurrency_stages"`
+
+	// TargetMetric selects which GPUMetrics field drives the scaling
+	// decision each stage: "waiting_requests" (WaitingRequestsMax) or
+	// "utilization_pct" (UtilizationAvgPct).
+	TargetMetric string `json:"target_metric"`
+
+	// TargetValue is the per-replica target for TargetMetric, the
+	// denominator in the usageRatio autoscale.Decide computes.
+	TargetValue float64 `json:"target_value"`
+
+	// Tolerance is the fractional band around a usageRatio of 1.0 treated
+	// as close enough to leave the replica count unchanged. Defaults to
+	// autoscale.defaultTolerance when zero.
+	Tolerance float64 `json:"tolerance,omitempty"`
+
+	// StageDurationSeconds is how long each concurrency stage's loadgen Job
+	// runs before its GPUMetrics snapshot is taken and fed into the next
+	// scaling decision.
+	StageDurationSeconds int `json:"stage_duration_seconds"`
+}
+
+// Validate rejects an AutoscaleRequest that orchestrator.ExecuteAutoscale
+// could never act on: a nonsensical replica bound, an empty ramp, an
+// unrecognized TargetMetric, or a non-positive TargetValue/
+// StageDurationSeconds (both are denominators or durations the ramp divides
+// by or sleeps on).
+func (a AutoscaleRequest) Validate() error {
+	if a.MinReplicas < 1 {
+		return fmt.Errorf("min_replicas must be at least 1")
+	}
+	if a.MaxReplicas < a.MinReplicas {
+		return fmt.Errorf("max_replicas must be >= min_replicas")
+	}
+	if len(a.ConcurrencyStages) == 0 {
+		return fmt.Errorf("concurrency_stages must not be empty")
+	}
+	if a.TargetMetric != "waiting_requests" && a.TargetMetric != "utilization_pct" {
+		return fmt.Errorf("target_metric must be one of: waiting_requests, utilization_pct")
+	}
+	if a.TargetValue <= 0 {
+		return fmt.Errorf("target_value must be positive")
+	}
+	if a.Tolerance < 0 {
+		return fmt.Errorf("tolerance must not be negative")
+	}
+	if a.StageDurationSeconds <= 0 {
+		return fmt.Errorf("stage_duration_seconds must be positive")
+	}
+	return nil
+}
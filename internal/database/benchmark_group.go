@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// BenchmarkRunTemplate describes the model/instance/framework knobs for
+// one child run spawned by a BenchmarkGroup's scheduler tick, mirroring
+// the shape of RunRequest that a human API caller would submit directly.
+type BenchmarkRunTemplate struct {
+	ModelHfID            string  `json:"model_hf_id"`
+	ModelHfRevision      string  `json:"model_hf_revision"`
+	InstanceTypeName     string  `json:"instance_type_name"`
+	Framework            string  `json:"framework"`
+	FrameworkVersion     string  `json:"framework_version"`
+	TensorParallelDegree int     `json:"tensor_parallel_degree"`
+	Quantization         *string `json:"quantization,omitempty"`
+	Concurrency          int     `json:"concurrency"`
+	InputSequenceLength  int     `json:"input_sequence_length"`
+	OutputSequenceLength int     `json:"output_sequence_length"`
+	DatasetName          string  `json:"dataset_name"`
+}
+
+// BenchmarkGroup is a persisted, recurring benchmark definition, parallel
+// to a Prometheus rule group: the scheduler reruns every Run in Runs on
+// each tick of Interval. QueryOffset shifts the wall-clock the fired runs
+// are tagged as being "for" back by that much, giving downstream
+// consumers (catalog, dashboards) a stable delay window to absorb
+// late-arriving infra metrics before a run shows up as completed — the
+// same trick Prometheus rule groups use to avoid querying a range still
+// being backfilled.
+type BenchmarkGroup struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Interval    time.Duration          `json:"interval"`
+	QueryOffset time.Duration          `json:"query_offset"`
+	Runs        []BenchmarkRunTemplate `json:"runs"`
+	LastRunAt   *time.Time             `json:"last_run_at,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// CreateBenchmarkGroup persists a new BenchmarkGroup definition and
+// returns its ID. Runs is stored as JSON since its shape is fixed at the
+// application layer, not something SQL needs to query into.
+func (r *Repository) CreateBenchmarkGroup(ctx context.Context, group *BenchmarkGroup) (string, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	runsJSON, err := json.Marshal(group.Runs)
+	if err != nil {
+		return "", fmt.Errorf("marshal run templates: %w", err)
+	}
+
+	var id string
+	err = r.pool.QueryRow(ctx,
+		`INSERT INTO benchmark_groups (name, interval_seconds, query_offset_seconds, runs_json)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id`,
+		group.Name, group.Interval.Seconds(), group.QueryOffset.Seconds(), runsJSON,
+	).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("insert benchmark group: %w", err)
+	}
+	return id, nil
+}
+
+// ListBenchmarkGroups returns every persisted BenchmarkGroup, for the
+// scheduler to poll on startup and after each tick.
+func (r *Repository) ListBenchmarkGroups(ctx context.Context) ([]BenchmarkGroup, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, name, interval_seconds, query_offset_seconds, runs_json, last_run_at, created_at
+		 FROM benchmark_groups
+		 ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("query benchmark groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []BenchmarkGroup
+	for rows.Next() {
+		var (
+			g                             BenchmarkGroup
+			intervalSecs, queryOffsetSecs float64
+			runsJSON                      []byte
+		)
+		if err := rows.Scan(&g.ID, &g.Name, &intervalSecs, &queryOffsetSecs, &runsJSON, &g.LastRunAt, &g.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan benchmark group row: %w", err)
+		}
+		g.Interval = time.Duration(intervalSecs * float64(time.Second))
+		g.QueryOffset = time.Duration(queryOffsetSecs * float64(time.Second))
+		if len(runsJSON) > 0 {
+			if err := json.Unmarshal(runsJSON, &g.Runs); err != nil {
+				return nil, fmt.Errorf("unmarshal run templates: %w", err)
+			}
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
+// UpdateGroupLastRun stamps groupID's last_run_at, called by the
+// scheduler once it has submitted every template's run for a tick.
+func (r *Repository) UpdateGroupLastRun(ctx context.Context, groupID string, lastRun time.Time) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	_, err := r.pool.Exec(ctx,
+		`UPDATE benchmark_groups SET last_run_at = $1 WHERE id = $2`, lastRun, groupID)
+	if err != nil {
+		return fmt.Errorf("update group last run: %w", err)
+	}
+	return nil
+}
+
+// ListRunsByGroup returns every run tagged with groupID, newest first.
+func (r *Repository) ListRunsByGroup(ctx context.Context, groupID string) ([]RunListItem, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT
+			br.id, m.hf_id, it.name,
+			br.framework, br.run_type, br.status,
+			br.created_at, br.started_at, br.completed_at
+		FROM benchmark_runs br
+		JOIN models m ON br.model_id = m.id
+		JOIN instance_types it ON br.instance_type_id = it.id
+		WHERE br.group_id = $1
+		ORDER BY br.created_at DESC`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("query runs by group: %w", err)
+	}
+	defer rows.Close()
+
+	var items []RunListItem
+	for rows.Next() {
+		var item RunListItem
+		if err := rows.Scan(
+			&item.ID, &item.ModelHfID, &item.InstanceTypeName,
+			&item.Framework, &item.RunType, &item.Status,
+			&item.CreatedAt, &item.StartedAt, &item.CompletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan run row: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
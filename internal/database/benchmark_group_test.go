@@ -0,0 +1,159 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCreateAndListBenchmarkGroups(t *testing.T) {
+	repo := NewMockRepo()
+	ctx := context.Background()
+
+	id, err := repo.CreateBenchmarkGroup(ctx, &BenchmarkGroup{
+		Name:     "llama-nightly",
+		Interval: time.Hour,
+		Runs: []BenchmarkRunTemplate{
+			{ModelHfID: "meta-llama/Llama-3-8B", InstanceTypeName: "ml.g5.2xlarge", Framework: "vllm"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateBenchmarkGroup: %v", err)
+	}
+	if id == "" {
+		t.Fatal("expected non-empty group ID")
+	}
+
+	groups, err := repo.ListBenchmarkGroups(ctx)
+	if err != nil {
+		t.Fatalf("ListBenchmarkGroups: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	if groups[0].ID != id || groups[0].Name != "llama-nightly" {
+		t.Errorf("unexpected group: %+v", groups[0])
+	}
+}
+
+func TestUpdateGroupLastRun(t *testing.T) {
+	repo := NewMockRepo()
+	ctx := context.Background()
+
+	id, err := repo.CreateBenchmarkGroup(ctx, &BenchmarkGroup{Name: "g1", Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("CreateBenchmarkGroup: %v", err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	if err := repo.UpdateGroupLastRun(ctx, id, now); err != nil {
+		t.Fatalf("UpdateGroupLastRun: %v", err)
+	}
+
+	groups, err := repo.ListBenchmarkGroups(ctx)
+	if err != nil {
+		t.Fatalf("ListBenchmarkGroups: %v", err)
+	}
+	if groups[0].LastRunAt == nil || !groups[0].LastRunAt.Equal(now) {
+		t.Errorf("expected LastRunAt %v, got %v", now, groups[0].LastRunAt)
+	}
+
+	if err := repo.UpdateGroupLastRun(ctx, "missing", now); err == nil {
+		t.Error("expected error for unknown group ID")
+	}
+}
+
+func TestListRunsByGroup(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	groupID, err := repo.CreateBenchmarkGroup(ctx, &BenchmarkGroup{Name: "g1", Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("CreateBenchmarkGroup: %v", err)
+	}
+
+	run := &BenchmarkRun{
+		ModelID:              "model-001",
+		InstanceTypeID:       "it-001",
+		Framework:            "vllm",
+		FrameworkVersion:     "0.4.0",
+		TensorParallelDegree: 1,
+		Concurrency:          16,
+		InputSequenceLength:  128,
+		OutputSequenceLength: 128,
+		DatasetName:          "synthetic",
+		RunType:              "on_demand",
+		Status:               "completed",
+		GroupID:              &groupID,
+	}
+	if _, err := repo.CreateBenchmarkRun(ctx, run); err != nil {
+		t.Fatalf("create run: %v", err)
+	}
+
+	items, err := repo.ListRunsByGroup(ctx, groupID)
+	if err != nil {
+		t.Fatalf("ListRunsByGroup: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 run for group, got %d", len(items))
+	}
+
+	items, err = repo.ListRunsByGroup(ctx, "other-group")
+	if err != nil {
+		t.Fatalf("ListRunsByGroup: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("expected 0 runs for unrelated group, got %d", len(items))
+	}
+}
+
+func TestListCatalog_LatestPerGroup(t *testing.T) {
+	repo := seedCatalogRepo()
+	ctx := context.Background()
+
+	groupID, err := repo.CreateBenchmarkGroup(ctx, &BenchmarkGroup{Name: "g1", Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("CreateBenchmarkGroup: %v", err)
+	}
+
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+	ttft := 10.0
+
+	oldRun := &BenchmarkRun{
+		ModelID: "m1", InstanceTypeID: "i1",
+		Framework: "vllm", FrameworkVersion: "v0.6.0",
+		TensorParallelDegree: 1, Concurrency: 16,
+		InputSequenceLength: 512, OutputSequenceLength: 256,
+		DatasetName: "sharegpt", RunType: "catalog", Status: "pending",
+		GroupID: &groupID, TemplateIndex: 0,
+	}
+	oldID, _ := repo.CreateBenchmarkRun(ctx, oldRun)
+	repo.PersistMetrics(ctx, oldID, &BenchmarkMetrics{TTFTP50Ms: &ttft}, nil)
+	repo.UpdateRunStatus(ctx, oldID, "completed")
+	repo.runs[oldID].CompletedAt = &older
+
+	newRun := &BenchmarkRun{
+		ModelID: "m1", InstanceTypeID: "i1",
+		Framework: "vllm", FrameworkVersion: "v0.6.0",
+		TensorParallelDegree: 1, Concurrency: 16,
+		InputSequenceLength: 512, OutputSequenceLength: 256,
+		DatasetName: "sharegpt", RunType: "catalog", Status: "pending",
+		GroupID: &groupID, TemplateIndex: 0,
+	}
+	newID, _ := repo.CreateBenchmarkRun(ctx, newRun)
+	repo.PersistMetrics(ctx, newID, &BenchmarkMetrics{TTFTP50Ms: &ttft}, nil)
+	repo.UpdateRunStatus(ctx, newID, "completed")
+	repo.runs[newID].CompletedAt = &newer
+
+	entries, _, err := repo.ListCatalog(ctx, CatalogFilter{GroupID: groupID, LatestPerGroup: true})
+	if err != nil {
+		t.Fatalf("ListCatalog: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry after LatestPerGroup filter, got %d", len(entries))
+	}
+	if entries[0].RunID != newID {
+		t.Errorf("expected latest run %s to survive, got %s", newID, entries[0].RunID)
+	}
+}
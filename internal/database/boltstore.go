@@ -0,0 +1,2170 @@
+package database
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/cronexpr"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	boltBucketModels        = []byte("models")
+	boltBucketModelsByHf    = []byte("models_by_hf")
+	boltBucketInstTypes     = []byte("instance_types")
+	boltBucketInstTypesByID = []byte("instance_types_by_id")
+	boltBucketRuns          = []byte("runs")
+	boltBucketMetrics       = []byte("metrics")
+	boltBucketSweeps        = []byte("sweeps")
+	boltBucketExperiments   = []byte("experiments")
+	boltBucketPricing       = []byte("pricing")
+	boltBucketSamples       = []byte("samples")
+	boltBucketAccelSamples  = []byte("accelerator_samples")
+	boltBucketGroups        = []byte("groups")
+	boltBucketPolicies      = []byte("policies")
+	boltBucketQueue         = []byte("run_queue")
+	boltBucketSeq           = []byte("seq")
+	boltBucketRunCallbacks  = []byte("run_callbacks")
+)
+
+var boltBuckets = [][]byte{
+	boltBucketModels, boltBucketModelsByHf, boltBucketInstTypes, boltBucketInstTypesByID,
+	boltBucketRuns, boltBucketMetrics, boltBucketSweeps, boltBucketExperiments, boltBucketPricing, boltBucketSamples,
+	boltBucketAccelSamples, boltBucketGroups, boltBucketPolicies, boltBucketQueue, boltBucketSeq, boltBucketRunCallbacks,
+}
+
+// BoltRepo is an embedded, single-process Repo backed by a BoltDB file.
+// It's meant for small or single-replica deployments that want state to
+// survive a restart without standing up Postgres; EtcdRepo is the
+// multi-replica alternative. Like Repository and MockRepo, lifecycle
+// events fan out through an in-process Broker, so Watch only sees events
+// published by this process — a replica failover loses any in-flight
+// long-poll/SSE subscriptions, same as it would against Postgres today.
+type BoltRepo struct {
+	db     *bolt.DB
+	broker *Broker
+}
+
+// NewBoltRepo opens (creating if necessary) a BoltDB file at path.
+func NewBoltRepo(path string) (*BoltRepo, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open bolt db: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range boltBuckets {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init bolt buckets: %w", err)
+	}
+	return &BoltRepo{db: db, broker: NewBroker()}, nil
+}
+
+// Close closes the underlying BoltDB file.
+func (b *BoltRepo) Close() error { return b.db.Close() }
+
+// Compile-time check that *BoltRepo implements Repo, Snapshotter, and Importer.
+var (
+	_ Repo        = (*BoltRepo)(nil)
+	_ Snapshotter = (*BoltRepo)(nil)
+	_ Importer    = (*BoltRepo)(nil)
+)
+
+func (b *BoltRepo) nextSeq(tx *bolt.Tx, kind string) (uint64, error) {
+	seq := tx.Bucket(boltBucketSeq)
+	var n uint64
+	if v := seq.Get([]byte(kind)); v != nil {
+		n = binary.BigEndian.Uint64(v)
+	}
+	n++
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, n)
+	return n, seq.Put([]byte(kind), buf)
+}
+
+func modelHfKey(hfID, hfRevision string) []byte {
+	return []byte(hfID + "|" + hfRevision)
+}
+
+func (b *BoltRepo) GetModelByHfID(_ context.Context, hfID, hfRevision string) (*Model, error) {
+	var m *Model
+	err := b.db.View(func(tx *bolt.Tx) error {
+		id := tx.Bucket(boltBucketModelsByHf).Get(modelHfKey(hfID, hfRevision))
+		if id == nil {
+			return nil
+		}
+		data := tx.Bucket(boltBucketModels).Get(id)
+		if data == nil {
+			return nil
+		}
+		var model Model
+		if err := json.Unmarshal(data, &model); err != nil {
+			return err
+		}
+		m = &model
+		return nil
+	})
+	return m, err
+}
+
+func (b *BoltRepo) EnsureModel(_ context.Context, hfID, hfRevision string) (*Model, error) {
+	var result Model
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		key := modelHfKey(hfID, hfRevision)
+		byHf := tx.Bucket(boltBucketModelsByHf)
+		models := tx.Bucket(boltBucketModels)
+		if id := byHf.Get(key); id != nil {
+			return json.Unmarshal(models.Get(id), &result)
+		}
+		n, err := b.nextSeq(tx, "models")
+		if err != nil {
+			return err
+		}
+		result = Model{ID: fmt.Sprintf("model-%08d", n), HfID: hfID, HfRevision: hfRevision, CreatedAt: time.Now()}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		if err := models.Put([]byte(result.ID), data); err != nil {
+			return err
+		}
+		return byHf.Put(key, []byte(result.ID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (b *BoltRepo) GetInstanceTypeByName(_ context.Context, name string) (*InstanceType, error) {
+	var it *InstanceType
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucketInstTypes).Get([]byte(name))
+		if data == nil {
+			return nil
+		}
+		var v InstanceType
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		it = &v
+		return nil
+	})
+	return it, err
+}
+
+func (b *BoltRepo) ListInstanceTypes(_ context.Context) ([]InstanceType, error) {
+	var result []InstanceType
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketInstTypes).ForEach(func(_, v []byte) error {
+			var it InstanceType
+			if err := json.Unmarshal(v, &it); err != nil {
+				return err
+			}
+			result = append(result, it)
+			return nil
+		})
+	})
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, err
+}
+
+func (b *BoltRepo) CreateBenchmarkRun(_ context.Context, run *BenchmarkRun) (string, error) {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		n, err := b.nextSeq(tx, "runs")
+		if err != nil {
+			return err
+		}
+		run.ID = fmt.Sprintf("run-%08d", n)
+		run.CreatedAt = time.Now()
+		if run.TimeoutSeconds > 0 {
+			d := run.CreatedAt.Add(time.Duration(run.TimeoutSeconds) * time.Second)
+			run.DeadlineAt = &d
+		}
+		data, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(boltBucketRuns).Put([]byte(run.ID), data)
+	})
+	if err != nil {
+		return "", err
+	}
+	return run.ID, nil
+}
+
+func (b *BoltRepo) UpdateRunStatus(_ context.Context, runID, status string) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(boltBucketRuns)
+		data := runs.Get([]byte(runID))
+		if data == nil {
+			return fmt.Errorf("run %s not found", runID)
+		}
+		var run BenchmarkRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return err
+		}
+		run.Status = status
+		now := time.Now()
+		switch status {
+		case "running":
+			run.StartedAt = &now
+		case "completed", "failed":
+			run.CompletedAt = &now
+			var m *BenchmarkMetrics
+			if mdata := tx.Bucket(boltBucketMetrics).Get([]byte(runID)); mdata != nil {
+				m = &BenchmarkMetrics{}
+				if err := json.Unmarshal(mdata, m); err != nil {
+					return err
+				}
+			}
+			if err := b.enqueueRunCallback(tx, &run, status, m); err != nil {
+				return err
+			}
+		}
+		updated, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return runs.Put([]byte(runID), updated)
+	})
+	if err != nil {
+		return err
+	}
+	b.broker.Publish(runID, status, nil)
+	return nil
+}
+
+// UpdateRunFailureReason stamps runID's FailureReason.
+func (b *BoltRepo) UpdateRunFailureReason(_ context.Context, runID, reason string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(boltBucketRuns)
+		data := runs.Get([]byte(runID))
+		if data == nil {
+			return fmt.Errorf("run %s not found", runID)
+		}
+		var run BenchmarkRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return err
+		}
+		run.FailureReason = &reason
+		updated, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return runs.Put([]byte(runID), updated)
+	})
+}
+
+// CancelRun atomically moves runID to "failed" if and only if it is still
+// "queued", "pending" or "running", and drops it from the admission queue.
+// BoltDB serializes all writers through a single
+// Update transaction, so this check-and-set needs no extra locking beyond
+// that transaction boundary.
+func (b *BoltRepo) CancelRun(_ context.Context, runID string) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(boltBucketRuns)
+		data := runs.Get([]byte(runID))
+		if data == nil {
+			return ErrRunNotFound
+		}
+		var run BenchmarkRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return err
+		}
+		if run.Status != "pending" && run.Status != "running" && run.Status != "queued" {
+			return ErrRunNotCancellable
+		}
+		run.Status = "failed"
+		now := time.Now()
+		run.CompletedAt = &now
+		if err := b.enqueueRunCallback(tx, &run, "failed", nil); err != nil {
+			return err
+		}
+		updated, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		if err := runs.Put([]byte(runID), updated); err != nil {
+			return err
+		}
+		// A canceled run may still be sitting in the admission queue;
+		// drop it so the scheduler doesn't dispatch it after all.
+		return tx.Bucket(boltBucketQueue).Delete([]byte(runID))
+	})
+	if err != nil {
+		return err
+	}
+	b.broker.Publish(runID, "failed", nil)
+	return nil
+}
+
+// ExtendDeadline pushes runID's DeadlineAt out to newDeadline if and only
+// if it is still "queued", "pending", or "running", matching Repository's
+// CAS semantics under the transaction boundary BoltDB already serializes
+// writers through.
+func (b *BoltRepo) ExtendDeadline(_ context.Context, runID string, newDeadline time.Time) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(boltBucketRuns)
+		data := runs.Get([]byte(runID))
+		if data == nil {
+			return ErrRunNotFound
+		}
+		var run BenchmarkRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return err
+		}
+		if run.Status != "queued" && run.Status != "pending" && run.Status != "running" {
+			return ErrRunNotExtendable
+		}
+		run.DeadlineAt = &newDeadline
+		updated, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return runs.Put([]byte(runID), updated)
+	})
+}
+
+func (b *BoltRepo) UpdateRunPluginDigests(_ context.Context, runID string, digests map[string]string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(boltBucketRuns)
+		data := runs.Get([]byte(runID))
+		if data == nil {
+			return fmt.Errorf("run %s not found", runID)
+		}
+		var run BenchmarkRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return err
+		}
+		run.PluginDigests = digests
+		updated, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return runs.Put([]byte(runID), updated)
+	})
+}
+
+// UpdateRunPhase stamps runID's current lifecycle phase and, if resources
+// is non-nil, merges it into the run's persisted K8sResourceRefs — see
+// mergeK8sResourceRefs.
+func (b *BoltRepo) UpdateRunPhase(_ context.Context, runID, phase string, resources *K8sResourceRefs) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(boltBucketRuns)
+		data := runs.Get([]byte(runID))
+		if data == nil {
+			return fmt.Errorf("run %s not found", runID)
+		}
+		var run BenchmarkRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return err
+		}
+		run.Phase = phase
+		if resources != nil {
+			if run.K8sResources == nil {
+				run.K8sResources = resources
+			} else {
+				run.K8sResources = mergeK8sResourceRefs(run.K8sResources, resources)
+			}
+		}
+		updated, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return runs.Put([]byte(runID), updated)
+	})
+}
+
+// SetTeardownPending marks whether runID's Kubernetes resources still need
+// tearing down.
+func (b *BoltRepo) SetTeardownPending(_ context.Context, runID string, pending bool) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(boltBucketRuns)
+		data := runs.Get([]byte(runID))
+		if data == nil {
+			return fmt.Errorf("run %s not found", runID)
+		}
+		var run BenchmarkRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return err
+		}
+		run.TeardownPending = pending
+		updated, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return runs.Put([]byte(runID), updated)
+	})
+}
+
+// ListNonTerminalRuns returns every run whose status is "running", for
+// Resume to rebind to on startup after a crash or restart.
+func (b *BoltRepo) ListNonTerminalRuns(_ context.Context) ([]BenchmarkRun, error) {
+	var runs []BenchmarkRun
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketRuns).ForEach(func(_, v []byte) error {
+			var run BenchmarkRun
+			if err := json.Unmarshal(v, &run); err != nil {
+				return err
+			}
+			if run.Status == "running" {
+				runs = append(runs, run)
+			}
+			return nil
+		})
+	})
+	return runs, err
+}
+
+// ListExpiredRuns returns every queued, pending, or running run whose
+// DeadlineAt is non-nil and strictly before now.
+func (b *BoltRepo) ListExpiredRuns(_ context.Context, now time.Time) ([]BenchmarkRun, error) {
+	var runs []BenchmarkRun
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketRuns).ForEach(func(_, v []byte) error {
+			var run BenchmarkRun
+			if err := json.Unmarshal(v, &run); err != nil {
+				return err
+			}
+			if (run.Status == "queued" || run.Status == "pending" || run.Status == "running") &&
+				run.DeadlineAt != nil && run.DeadlineAt.Before(now) {
+				runs = append(runs, run)
+			}
+			return nil
+		})
+	})
+	return runs, err
+}
+
+func (b *BoltRepo) PersistMetrics(_ context.Context, runID string, m *BenchmarkMetrics, samples []AcceleratorSample) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(boltBucketRuns)
+		data := runs.Get([]byte(runID))
+		if data == nil {
+			return fmt.Errorf("run %s not found", runID)
+		}
+		var run BenchmarkRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return err
+		}
+
+		n, err := b.nextSeq(tx, "metrics")
+		if err != nil {
+			return err
+		}
+		m.ID = fmt.Sprintf("met-%08d", n)
+		m.RunID = runID
+		m.CreatedAt = time.Now()
+		mdata, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucketMetrics).Put([]byte(runID), mdata); err != nil {
+			return err
+		}
+
+		if len(samples) > 0 {
+			adata, err := json.Marshal(boltAccelSamples{Samples: samples})
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketAccelSamples).Put([]byte(runID), adata); err != nil {
+				return err
+			}
+		}
+
+		run.Status = "completed"
+		now := time.Now()
+		run.CompletedAt = &now
+		if err := b.enqueueRunCallback(tx, &run, "completed", m); err != nil {
+			return err
+		}
+		rdata, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return runs.Put([]byte(runID), rdata)
+	})
+	if err != nil {
+		return err
+	}
+	b.broker.Publish(runID, "completed", m)
+	return nil
+}
+
+// enqueueRunCallback queues a RunCallback for run in boltBucketRunCallbacks
+// if it carries a CallbackURL, called from within the tx that's transitioning
+// run to a terminal status so the callback row and the status flip commit
+// atomically. No-op when run has no CallbackURL.
+func (b *BoltRepo) enqueueRunCallback(tx *bolt.Tx, run *BenchmarkRun, status string, m *BenchmarkMetrics) error {
+	if run.CallbackURL == "" {
+		return nil
+	}
+	n, err := b.nextSeq(tx, "callback")
+	if err != nil {
+		return err
+	}
+	cb := RunCallback{
+		ID:            fmt.Sprintf("cb-%08d", n),
+		RunID:         run.ID,
+		URL:           run.CallbackURL,
+		Token:         run.CallbackToken,
+		Status:        status,
+		Summary:       summarizeMetrics(m),
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	data, err := json.Marshal(cb)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(boltBucketRunCallbacks).Put([]byte(cb.ID), data)
+}
+
+// ListDueRunCallbacks returns every pending run_callbacks row whose
+// NextAttemptAt is at or before now, for the callbacks package's worker.
+func (b *BoltRepo) ListDueRunCallbacks(_ context.Context, now time.Time, limit int) ([]RunCallback, error) {
+	var due []RunCallback
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketRunCallbacks).ForEach(func(_, v []byte) error {
+			var cb RunCallback
+			if err := json.Unmarshal(v, &cb); err != nil {
+				return err
+			}
+			if cb.DeliveredAt == nil && !cb.NextAttemptAt.After(now) && cb.Attempts < MaxCallbackAttempts {
+				due = append(due, cb)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+// RecordRunCallbackResult records the outcome of a delivery attempt for id.
+func (b *BoltRepo) RecordRunCallbackResult(_ context.Context, id string, deliveryErr error, nextAttemptAt *time.Time) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucketRunCallbacks)
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("run callback %s not found", id)
+		}
+		var cb RunCallback
+		if err := json.Unmarshal(data, &cb); err != nil {
+			return err
+		}
+		if deliveryErr == nil {
+			now := time.Now()
+			cb.DeliveredAt = &now
+			cb.LastError = ""
+		} else {
+			cb.Attempts++
+			cb.LastError = deliveryErr.Error()
+			if nextAttemptAt != nil {
+				cb.NextAttemptAt = *nextAttemptAt
+			}
+		}
+		updated, err := json.Marshal(cb)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(id), updated)
+	})
+}
+
+// UpsertMetrics writes a progressive metrics snapshot for runID without
+// marking the run completed, replacing any previous snapshot — the bolt
+// counterpart to Repository.UpsertMetrics.
+func (b *BoltRepo) UpsertMetrics(_ context.Context, runID string, m *BenchmarkMetrics) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(boltBucketRuns).Get([]byte(runID)) == nil {
+			return fmt.Errorf("run %s not found", runID)
+		}
+		m.RunID = runID
+		mdata, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(boltBucketMetrics).Put([]byte(runID), mdata)
+	})
+	if err != nil {
+		return err
+	}
+	b.broker.Publish(runID, "running", m)
+	return nil
+}
+
+// Watch subscribes to lifecycle and metrics events for runID.
+func (b *BoltRepo) Watch(ctx context.Context, runID string, sinceRev uint64) (<-chan RunEvent, error) {
+	return b.broker.Watch(ctx, runID, sinceRev)
+}
+
+// PublishRunLog fans an orchestrator log line for runID out to anyone
+// watching it, without persisting it to the database file.
+func (b *BoltRepo) PublishRunLog(ctx context.Context, runID, line string) error {
+	b.broker.PublishLog(runID, line)
+	return nil
+}
+
+func (b *BoltRepo) GetBenchmarkRun(_ context.Context, runID string) (*BenchmarkRun, error) {
+	var run *BenchmarkRun
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucketRuns).Get([]byte(runID))
+		if data == nil {
+			return nil
+		}
+		var r BenchmarkRun
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		run = &r
+		return nil
+	})
+	return run, err
+}
+
+func (b *BoltRepo) GetMetricsByRunID(_ context.Context, runID string) (*BenchmarkMetrics, error) {
+	var m *BenchmarkMetrics
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucketMetrics).Get([]byte(runID))
+		if data == nil {
+			return nil
+		}
+		var v BenchmarkMetrics
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		m = &v
+		return nil
+	})
+	return m, err
+}
+
+func (b *BoltRepo) modelByID(tx *bolt.Tx, id string) *Model {
+	data := tx.Bucket(boltBucketModels).Get([]byte(id))
+	if data == nil {
+		return nil
+	}
+	var m Model
+	if json.Unmarshal(data, &m) != nil {
+		return nil
+	}
+	return &m
+}
+
+func (b *BoltRepo) instanceTypeByID(tx *bolt.Tx, id string) *InstanceType {
+	name := tx.Bucket(boltBucketInstTypesByID).Get([]byte(id))
+	if name == nil {
+		return nil
+	}
+	data := tx.Bucket(boltBucketInstTypes).Get(name)
+	if data == nil {
+		return nil
+	}
+	var it InstanceType
+	if json.Unmarshal(data, &it) != nil {
+		return nil
+	}
+	return &it
+}
+
+// ListRuns returns benchmark runs matching f, along with the total number
+// of runs matching f (ignoring f.Limit/f.Offset).
+func (b *BoltRepo) ListRuns(_ context.Context, f RunFilter) ([]RunListItem, int, error) {
+	var items []RunListItem
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketRuns).ForEach(func(_, v []byte) error {
+			var run BenchmarkRun
+			if err := json.Unmarshal(v, &run); err != nil {
+				return err
+			}
+			if !runStatusMatches(run.Status, f) {
+				return nil
+			}
+			if !shardMatch(run.ID, f.ShardID, f.ShardCount) {
+				return nil
+			}
+
+			var modelHfID string
+			if model := b.modelByID(tx, run.ModelID); model != nil {
+				modelHfID = model.HfID
+			}
+			if f.ModelID != "" && !strings.Contains(strings.ToLower(modelHfID), strings.ToLower(f.ModelID)) {
+				return nil
+			}
+
+			var instName, instFamily, instAccelType string
+			if it := b.instanceTypeByID(tx, run.InstanceTypeID); it != nil {
+				instName, instFamily, instAccelType = it.Name, it.Family, it.AcceleratorType
+			}
+			if !runMatchesFilter(&run, instFamily, instAccelType, f) {
+				return nil
+			}
+
+			items = append(items, RunListItem{
+				ID:               run.ID,
+				ModelHfID:        modelHfID,
+				InstanceTypeName: instName,
+				Framework:        run.Framework,
+				RunType:          run.RunType,
+				Status:           run.Status,
+				CreatedAt:        run.CreatedAt,
+				StartedAt:        run.StartedAt,
+				CompletedAt:      run.CompletedAt,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, total := paginateRunItems(items, f)
+	return items, total, nil
+}
+
+func (b *BoltRepo) DeleteRun(_ context.Context, runID string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(boltBucketMetrics).Delete([]byte(runID)); err != nil {
+			return err
+		}
+		return tx.Bucket(boltBucketRuns).Delete([]byte(runID))
+	})
+}
+
+// ListCatalog returns catalog entries matching f, along with the total
+// number of entries matching f (ignoring f.Limit/f.Offset).
+func (b *BoltRepo) ListCatalog(_ context.Context, f CatalogFilter) ([]CatalogEntry, int, error) {
+	var entries []CatalogEntry
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketRuns).ForEach(func(runIDKey, v []byte) error {
+			var run BenchmarkRun
+			if err := json.Unmarshal(v, &run); err != nil {
+				return err
+			}
+			if run.Status != "completed" || run.Superseded || run.RunType != "catalog" {
+				return nil
+			}
+
+			metData := tx.Bucket(boltBucketMetrics).Get(runIDKey)
+			if metData == nil {
+				return nil
+			}
+			var met BenchmarkMetrics
+			if err := json.Unmarshal(metData, &met); err != nil {
+				return err
+			}
+
+			model := b.modelByID(tx, run.ModelID)
+			if model == nil {
+				return nil
+			}
+			inst := b.instanceTypeByID(tx, run.InstanceTypeID)
+			if inst == nil {
+				return nil
+			}
+
+			if f.ModelHfID != "" && model.HfID != f.ModelHfID {
+				return nil
+			}
+			if f.ModelFamily != "" && (model.ModelFamily == nil || *model.ModelFamily != f.ModelFamily) {
+				return nil
+			}
+			if f.InstanceFamily != "" && inst.Family != f.InstanceFamily {
+				return nil
+			}
+			if f.AcceleratorType != "" && inst.AcceleratorType != f.AcceleratorType {
+				return nil
+			}
+			if f.GroupID != "" && (run.GroupID == nil || *run.GroupID != f.GroupID) {
+				return nil
+			}
+			if !shardMatch(run.ID, f.ShardID, f.ShardCount) {
+				return nil
+			}
+
+			entries = append(entries, CatalogEntry{
+				RunID:                     run.ID,
+				ModelHfID:                 model.HfID,
+				ModelFamily:               model.ModelFamily,
+				ParameterCount:            model.ParameterCount,
+				InstanceTypeName:          inst.Name,
+				InstanceFamily:            inst.Family,
+				AcceleratorType:           inst.AcceleratorType,
+				AcceleratorName:           inst.AcceleratorName,
+				AcceleratorCount:          inst.AcceleratorCount,
+				AcceleratorMemoryGiB:      inst.AcceleratorMemoryGiB,
+				Framework:                 run.Framework,
+				FrameworkVersion:          run.FrameworkVersion,
+				TensorParallelDegree:      run.TensorParallelDegree,
+				Quantization:              run.Quantization,
+				Concurrency:               run.Concurrency,
+				InputSequenceLength:       run.InputSequenceLength,
+				OutputSequenceLength:      run.OutputSequenceLength,
+				CompletedAt:               run.CompletedAt,
+				GroupID:                   run.GroupID,
+				TemplateIndex:             run.TemplateIndex,
+				TTFTP50Ms:                 met.TTFTP50Ms,
+				TTFTP99Ms:                 met.TTFTP99Ms,
+				E2ELatencyP50Ms:           met.E2ELatencyP50Ms,
+				E2ELatencyP99Ms:           met.E2ELatencyP99Ms,
+				ITLP50Ms:                  met.ITLP50Ms,
+				ITLP99Ms:                  met.ITLP99Ms,
+				ThroughputPerRequestTPS:   met.ThroughputPerRequestTPS,
+				ThroughputAggregateTPS:    met.ThroughputAggregateTPS,
+				RequestsPerSecond:         met.RequestsPerSecond,
+				AcceleratorUtilizationPct: met.AcceleratorUtilizationPct,
+				AcceleratorMemoryPeakGiB:  met.AcceleratorMemoryPeakGiB,
+				ServerTTFTP50Ms:           met.ServerTTFTP50Ms,
+				ServerTTFTP99Ms:           met.ServerTTFTP99Ms,
+				ServerE2ELatencyP50Ms:     met.ServerE2ELatencyP50Ms,
+				ServerE2ELatencyP99Ms:     met.ServerE2ELatencyP99Ms,
+				SMActivePeakPct:           met.SMActivePeakPct,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if f.LatestPerGroup {
+		entries = filterLatestPerGroup(entries)
+	}
+
+	// A sharded call returns its whole shard unpaginated; the caller
+	// applies Limit/Offset once after merging every shard's results.
+	if f.ShardCount > 1 {
+		return entries, len(entries), nil
+	}
+
+	total := len(entries)
+
+	limit := 100
+	if f.Limit > 0 && f.Limit <= 500 {
+		limit = f.Limit
+	}
+	if f.Offset > 0 && f.Offset < len(entries) {
+		entries = entries[f.Offset:]
+	} else if f.Offset >= len(entries) {
+		return nil, total, nil
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, total, nil
+}
+
+func pricingKey(instanceTypeID, cloudProvider, region, effectiveDate string) []byte {
+	return []byte(instanceTypeID + "|" + cloudProvider + "|" + region + "|" + effectiveDate)
+}
+
+func (b *BoltRepo) UpsertPricing(_ context.Context, p *Pricing) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		n, err := b.nextSeq(tx, "pricing")
+		if err != nil {
+			return err
+		}
+		if p.ID == "" {
+			p.ID = fmt.Sprintf("price-%08d", n)
+		}
+		p.CreatedAt = time.Now()
+		data, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(boltBucketPricing).Put(pricingKey(p.InstanceTypeID, p.CloudProvider, p.Region, p.EffectiveDate), data)
+	})
+}
+
+func (b *BoltRepo) ListPricing(_ context.Context, region string) ([]PricingRow, error) {
+	latest := make(map[string]Pricing) // keyed by instance_type_id|cloud_provider
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketPricing).ForEach(func(_, v []byte) error {
+			var p Pricing
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+			if p.Region != region {
+				return nil
+			}
+			key := p.InstanceTypeID + "|" + p.CloudProvider
+			if cur, ok := latest[key]; !ok || p.EffectiveDate > cur.EffectiveDate {
+				latest[key] = p
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []PricingRow
+	err = b.db.View(func(tx *bolt.Tx) error {
+		for _, p := range latest {
+			inst := b.instanceTypeByID(tx, p.InstanceTypeID)
+			if inst == nil {
+				continue
+			}
+			result = append(result, PricingRow{
+				InstanceTypeName:     inst.Name,
+				CloudProvider:        p.CloudProvider,
+				OnDemandHourlyUSD:    p.OnDemandHourlyUSD,
+				Reserved1YrHourlyUSD: p.Reserved1YrHourlyUSD,
+				Reserved3YrHourlyUSD: p.Reserved3YrHourlyUSD,
+				SpotHourlyUSD:        p.SpotHourlyUSD,
+				EffectiveDate:        p.EffectiveDate,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].InstanceTypeName != result[j].InstanceTypeName {
+			return result[i].InstanceTypeName < result[j].InstanceTypeName
+		}
+		return result[i].CloudProvider < result[j].CloudProvider
+	})
+	return result, nil
+}
+
+func (b *BoltRepo) ListPricingAt(_ context.Context, region string, asOf time.Time) ([]PricingRow, error) {
+	asOfDate := asOf.Format("2006-01-02")
+	latest := make(map[string]Pricing) // keyed by instance_type_id|cloud_provider
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketPricing).ForEach(func(_, v []byte) error {
+			var p Pricing
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+			if p.Region != region || p.EffectiveDate > asOfDate {
+				return nil
+			}
+			key := p.InstanceTypeID + "|" + p.CloudProvider
+			if cur, ok := latest[key]; !ok || p.EffectiveDate > cur.EffectiveDate {
+				latest[key] = p
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []PricingRow
+	err = b.db.View(func(tx *bolt.Tx) error {
+		for _, p := range latest {
+			inst := b.instanceTypeByID(tx, p.InstanceTypeID)
+			if inst == nil {
+				continue
+			}
+			result = append(result, PricingRow{
+				InstanceTypeName:     inst.Name,
+				CloudProvider:        p.CloudProvider,
+				OnDemandHourlyUSD:    p.OnDemandHourlyUSD,
+				Reserved1YrHourlyUSD: p.Reserved1YrHourlyUSD,
+				Reserved3YrHourlyUSD: p.Reserved3YrHourlyUSD,
+				SpotHourlyUSD:        p.SpotHourlyUSD,
+				EffectiveDate:        p.EffectiveDate,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].InstanceTypeName != result[j].InstanceTypeName {
+			return result[i].InstanceTypeName < result[j].InstanceTypeName
+		}
+		return result[i].CloudProvider < result[j].CloudProvider
+	})
+	return result, nil
+}
+
+func (b *BoltRepo) PricingHistory(_ context.Context, instanceTypeID, region string, since time.Time) ([]PricingRow, error) {
+	sinceDate := since.Format("2006-01-02")
+	var result []PricingRow
+	err := b.db.View(func(tx *bolt.Tx) error {
+		inst := b.instanceTypeByID(tx, instanceTypeID)
+		if inst == nil {
+			return nil
+		}
+		return tx.Bucket(boltBucketPricing).ForEach(func(_, v []byte) error {
+			var p Pricing
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+			if p.InstanceTypeID != instanceTypeID || p.Region != region || p.EffectiveDate < sinceDate {
+				return nil
+			}
+			result = append(result, PricingRow{
+				InstanceTypeName:     inst.Name,
+				CloudProvider:        p.CloudProvider,
+				OnDemandHourlyUSD:    p.OnDemandHourlyUSD,
+				Reserved1YrHourlyUSD: p.Reserved1YrHourlyUSD,
+				Reserved3YrHourlyUSD: p.Reserved3YrHourlyUSD,
+				SpotHourlyUSD:        p.SpotHourlyUSD,
+				EffectiveDate:        p.EffectiveDate,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].EffectiveDate != result[j].EffectiveDate {
+			return result[i].EffectiveDate < result[j].EffectiveDate
+		}
+		return result[i].CloudProvider < result[j].CloudProvider
+	})
+	return result, nil
+}
+
+func (b *BoltRepo) CreateSweep(_ context.Context, runs []*BenchmarkRun) (string, []string, error) {
+	var sweepID string
+	var runIDs []string
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		n, err := b.nextSeq(tx, "sweeps")
+		if err != nil {
+			return err
+		}
+		sweepID = fmt.Sprintf("sweep-%08d", n)
+		sweep := &Sweep{ID: sweepID, CreatedAt: time.Now()}
+		sdata, err := json.Marshal(sweep)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucketSweeps).Put([]byte(sweepID), sdata); err != nil {
+			return err
+		}
+
+		runIDs = make([]string, 0, len(runs))
+		for _, run := range runs {
+			rn, err := b.nextSeq(tx, "runs")
+			if err != nil {
+				return err
+			}
+			run.ID = fmt.Sprintf("run-%08d", rn)
+			run.SweepID = &sweepID
+			run.CreatedAt = time.Now()
+			rdata, err := json.Marshal(run)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketRuns).Put([]byte(run.ID), rdata); err != nil {
+				return err
+			}
+			runIDs = append(runIDs, run.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return sweepID, runIDs, nil
+}
+
+func (b *BoltRepo) GetSweep(_ context.Context, sweepID string) (*Sweep, error) {
+	var sweep *Sweep
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucketSweeps).Get([]byte(sweepID))
+		if data == nil {
+			return nil
+		}
+		var s Sweep
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		sweep = &s
+		return nil
+	})
+	return sweep, err
+}
+
+func (b *BoltRepo) ListSweepRuns(_ context.Context, sweepID string) ([]SweepRunDetail, error) {
+	var details []SweepRunDetail
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketRuns).ForEach(func(runIDKey, v []byte) error {
+			var run BenchmarkRun
+			if err := json.Unmarshal(v, &run); err != nil {
+				return err
+			}
+			if run.SweepID == nil || *run.SweepID != sweepID {
+				return nil
+			}
+
+			var modelHfID, instName string
+			if model := b.modelByID(tx, run.ModelID); model != nil {
+				modelHfID = model.HfID
+			}
+			if it := b.instanceTypeByID(tx, run.InstanceTypeID); it != nil {
+				instName = it.Name
+			}
+
+			var metrics *BenchmarkMetrics
+			if mdata := tx.Bucket(boltBucketMetrics).Get(runIDKey); mdata != nil {
+				var m BenchmarkMetrics
+				if err := json.Unmarshal(mdata, &m); err != nil {
+					return err
+				}
+				metrics = &m
+			}
+
+			details = append(details, SweepRunDetail{
+				RunListItem: RunListItem{
+					ID:               run.ID,
+					ModelHfID:        modelHfID,
+					InstanceTypeName: instName,
+					Framework:        run.Framework,
+					RunType:          run.RunType,
+					Status:           run.Status,
+					CreatedAt:        run.CreatedAt,
+					StartedAt:        run.StartedAt,
+					CompletedAt:      run.CompletedAt,
+				},
+				Concurrency:          run.Concurrency,
+				TensorParallelDegree: run.TensorParallelDegree,
+				InputSequenceLength:  run.InputSequenceLength,
+				OutputSequenceLength: run.OutputSequenceLength,
+				Quantization:         run.Quantization,
+				Metrics:              metrics,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(details, func(i, j int) bool { return details[i].CreatedAt.Before(details[j].CreatedAt) })
+	return details, nil
+}
+
+func (b *BoltRepo) CreateExperiment(_ context.Context, runs []*BenchmarkRun) (string, []string, error) {
+	var experimentID string
+	var runIDs []string
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		n, err := b.nextSeq(tx, "experiments")
+		if err != nil {
+			return err
+		}
+		experimentID = fmt.Sprintf("experiment-%08d", n)
+		exp := &Experiment{ID: experimentID, CreatedAt: time.Now()}
+		edata, err := json.Marshal(exp)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucketExperiments).Put([]byte(experimentID), edata); err != nil {
+			return err
+		}
+
+		runIDs = make([]string, 0, len(runs))
+		for _, run := range runs {
+			rn, err := b.nextSeq(tx, "runs")
+			if err != nil {
+				return err
+			}
+			run.ID = fmt.Sprintf("run-%08d", rn)
+			run.ExperimentID = &experimentID
+			run.CreatedAt = time.Now()
+			rdata, err := json.Marshal(run)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketRuns).Put([]byte(run.ID), rdata); err != nil {
+				return err
+			}
+			runIDs = append(runIDs, run.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return experimentID, runIDs, nil
+}
+
+func (b *BoltRepo) GetExperiment(_ context.Context, experimentID string) (*Experiment, error) {
+	var exp *Experiment
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucketExperiments).Get([]byte(experimentID))
+		if data == nil {
+			return nil
+		}
+		var e Experiment
+		if err := json.Unmarshal(data, &e); err != nil {
+			return err
+		}
+		exp = &e
+		return nil
+	})
+	return exp, err
+}
+
+func (b *BoltRepo) ListRunsByExperiment(_ context.Context, experimentID string) ([]ExperimentRunDetail, error) {
+	var details []ExperimentRunDetail
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketRuns).ForEach(func(_, v []byte) error {
+			var run BenchmarkRun
+			if err := json.Unmarshal(v, &run); err != nil {
+				return err
+			}
+			if run.ExperimentID == nil || *run.ExperimentID != experimentID {
+				return nil
+			}
+
+			var modelHfID, instName string
+			if model := b.modelByID(tx, run.ModelID); model != nil {
+				modelHfID = model.HfID
+			}
+			if it := b.instanceTypeByID(tx, run.InstanceTypeID); it != nil {
+				instName = it.Name
+			}
+
+			details = append(details, ExperimentRunDetail{
+				RunListItem: RunListItem{
+					ID:               run.ID,
+					ModelHfID:        modelHfID,
+					InstanceTypeName: instName,
+					Framework:        run.Framework,
+					RunType:          run.RunType,
+					Status:           run.Status,
+					CreatedAt:        run.CreatedAt,
+					StartedAt:        run.StartedAt,
+					CompletedAt:      run.CompletedAt,
+				},
+				Arm: run.Arm,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(details, func(i, j int) bool { return details[i].CreatedAt.Before(details[j].CreatedAt) })
+	return details, nil
+}
+
+// CreateBenchmarkGroup persists a new BenchmarkGroup definition.
+func (b *BoltRepo) CreateBenchmarkGroup(_ context.Context, group *BenchmarkGroup) (string, error) {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		n, err := b.nextSeq(tx, "groups")
+		if err != nil {
+			return err
+		}
+		group.ID = fmt.Sprintf("group-%08d", n)
+		group.CreatedAt = time.Now()
+		data, err := json.Marshal(group)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(boltBucketGroups).Put([]byte(group.ID), data)
+	})
+	if err != nil {
+		return "", err
+	}
+	return group.ID, nil
+}
+
+// ListBenchmarkGroups returns every persisted BenchmarkGroup.
+func (b *BoltRepo) ListBenchmarkGroups(_ context.Context) ([]BenchmarkGroup, error) {
+	var groups []BenchmarkGroup
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketGroups).ForEach(func(_, v []byte) error {
+			var g BenchmarkGroup
+			if err := json.Unmarshal(v, &g); err != nil {
+				return err
+			}
+			groups = append(groups, g)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].CreatedAt.Before(groups[j].CreatedAt) })
+	return groups, nil
+}
+
+// UpdateGroupLastRun stamps groupID's last_run_at.
+func (b *BoltRepo) UpdateGroupLastRun(_ context.Context, groupID string, lastRun time.Time) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucketGroups)
+		data := bucket.Get([]byte(groupID))
+		if data == nil {
+			return fmt.Errorf("benchmark group %s not found", groupID)
+		}
+		var g BenchmarkGroup
+		if err := json.Unmarshal(data, &g); err != nil {
+			return err
+		}
+		g.LastRunAt = &lastRun
+		updated, err := json.Marshal(g)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(groupID), updated)
+	})
+}
+
+// ListRunsByGroup returns every run tagged with groupID, newest first.
+func (b *BoltRepo) ListRunsByGroup(_ context.Context, groupID string) ([]RunListItem, error) {
+	var items []RunListItem
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketRuns).ForEach(func(_, v []byte) error {
+			var run BenchmarkRun
+			if err := json.Unmarshal(v, &run); err != nil {
+				return err
+			}
+			if run.GroupID == nil || *run.GroupID != groupID {
+				return nil
+			}
+
+			var modelHfID, instName string
+			if model := b.modelByID(tx, run.ModelID); model != nil {
+				modelHfID = model.HfID
+			}
+			if it := b.instanceTypeByID(tx, run.InstanceTypeID); it != nil {
+				instName = it.Name
+			}
+
+			items = append(items, RunListItem{
+				ID:               run.ID,
+				ModelHfID:        modelHfID,
+				InstanceTypeName: instName,
+				Framework:        run.Framework,
+				RunType:          run.RunType,
+				Status:           run.Status,
+				CreatedAt:        run.CreatedAt,
+				StartedAt:        run.StartedAt,
+				CompletedAt:      run.CompletedAt,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt.After(items[j].CreatedAt) })
+	return items, nil
+}
+
+// CreatePolicy persists a new BenchmarkPolicy definition.
+func (b *BoltRepo) CreatePolicy(_ context.Context, policy *BenchmarkPolicy) (string, error) {
+	if policy.Trigger == "scheduled" {
+		next, err := cronexpr.Next(policy.CronExpr, time.Now().UTC())
+		if err != nil {
+			return "", fmt.Errorf("compute next run: %w", err)
+		}
+		policy.NextRunAt = &next
+	}
+
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		n, err := b.nextSeq(tx, "policies")
+		if err != nil {
+			return err
+		}
+		policy.ID = fmt.Sprintf("policy-%08d", n)
+		policy.CreatedAt = time.Now()
+		data, err := json.Marshal(policy)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(boltBucketPolicies).Put([]byte(policy.ID), data)
+	})
+	if err != nil {
+		return "", err
+	}
+	return policy.ID, nil
+}
+
+// UpdatePolicy replaces policyID's mutable fields.
+func (b *BoltRepo) UpdatePolicy(_ context.Context, policyID string, policy *BenchmarkPolicy) error {
+	if policy.Trigger == "scheduled" {
+		next, err := cronexpr.Next(policy.CronExpr, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("compute next run: %w", err)
+		}
+		policy.NextRunAt = &next
+	} else {
+		policy.NextRunAt = nil
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucketPolicies)
+		data := bucket.Get([]byte(policyID))
+		if data == nil {
+			return ErrPolicyNotFound
+		}
+		var existing BenchmarkPolicy
+		if err := json.Unmarshal(data, &existing); err != nil {
+			return err
+		}
+		policy.ID = existing.ID
+		policy.CreatedAt = existing.CreatedAt
+		policy.LastRunAt = existing.LastRunAt
+		updated, err := json.Marshal(policy)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(policyID), updated)
+	})
+}
+
+// ListPolicies returns every persisted BenchmarkPolicy.
+func (b *BoltRepo) ListPolicies(_ context.Context) ([]BenchmarkPolicy, error) {
+	var policies []BenchmarkPolicy
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketPolicies).ForEach(func(_, v []byte) error {
+			var p BenchmarkPolicy
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+			policies = append(policies, p)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].CreatedAt.Before(policies[j].CreatedAt) })
+	return policies, nil
+}
+
+// DeletePolicy removes a BenchmarkPolicy.
+func (b *BoltRepo) DeletePolicy(_ context.Context, policyID string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketPolicies).Delete([]byte(policyID))
+	})
+}
+
+// ListRunsByParent returns every probe run created under parentRunID,
+// newest first, each with the concurrency it probed and the metrics it
+// produced.
+func (b *BoltRepo) ListRunsByParent(_ context.Context, parentRunID string) ([]SLOSearchRunDetail, error) {
+	var details []SLOSearchRunDetail
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketRuns).ForEach(func(runIDKey, v []byte) error {
+			var run BenchmarkRun
+			if err := json.Unmarshal(v, &run); err != nil {
+				return err
+			}
+			if run.ParentRunID == nil || *run.ParentRunID != parentRunID {
+				return nil
+			}
+
+			var modelHfID, instName string
+			if model := b.modelByID(tx, run.ModelID); model != nil {
+				modelHfID = model.HfID
+			}
+			if it := b.instanceTypeByID(tx, run.InstanceTypeID); it != nil {
+				instName = it.Name
+			}
+
+			var metrics *BenchmarkMetrics
+			if mdata := tx.Bucket(boltBucketMetrics).Get(runIDKey); mdata != nil {
+				var m BenchmarkMetrics
+				if err := json.Unmarshal(mdata, &m); err != nil {
+					return err
+				}
+				metrics = &m
+			}
+
+			details = append(details, SLOSearchRunDetail{
+				RunListItem: RunListItem{
+					ID:               run.ID,
+					ModelHfID:        modelHfID,
+					InstanceTypeName: instName,
+					Framework:        run.Framework,
+					RunType:          run.RunType,
+					Status:           run.Status,
+					CreatedAt:        run.CreatedAt,
+					StartedAt:        run.StartedAt,
+					CompletedAt:      run.CompletedAt,
+				},
+				Concurrency: run.Concurrency,
+				Metrics:     metrics,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(details, func(i, j int) bool { return details[i].CreatedAt.After(details[j].CreatedAt) })
+	return details, nil
+}
+
+// EnqueueRun admits runID into the persistent run queue and atomically
+// moves its run to status "queued" with QueuedAt stamped, so a caller
+// never observes the run sitting in the queue while its status still
+// reads a stale value (e.g. right after Scheduler.Requeue re-admits a
+// completed run).
+func (b *BoltRepo) EnqueueRun(_ context.Context, runID, instanceFamily, userID string, priority int) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		now := time.Now()
+		q := QueuedRun{RunID: runID, InstanceFamily: instanceFamily, UserID: userID, Priority: priority, EnqueuedAt: now}
+		data, err := json.Marshal(q)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucketQueue).Put([]byte(runID), data); err != nil {
+			return err
+		}
+
+		runs := tx.Bucket(boltBucketRuns)
+		runData := runs.Get([]byte(runID))
+		if runData == nil {
+			return nil
+		}
+		var run BenchmarkRun
+		if err := json.Unmarshal(runData, &run); err != nil {
+			return err
+		}
+		run.Status = "queued"
+		run.QueuedAt = &now
+		updated, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return runs.Put([]byte(runID), updated)
+	})
+	if err != nil {
+		return err
+	}
+	b.broker.Publish(runID, "queued", nil)
+	return nil
+}
+
+// ListQueuedRuns returns every still-queued run, priority descending then
+// EnqueuedAt ascending.
+func (b *BoltRepo) ListQueuedRuns(_ context.Context) ([]QueuedRun, error) {
+	var queued []QueuedRun
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketQueue).ForEach(func(_, v []byte) error {
+			var q QueuedRun
+			if err := json.Unmarshal(v, &q); err != nil {
+				return err
+			}
+			queued = append(queued, q)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(queued, func(i, j int) bool {
+		if queued[i].Priority != queued[j].Priority {
+			return queued[i].Priority > queued[j].Priority
+		}
+		return queued[i].EnqueuedAt.Before(queued[j].EnqueuedAt)
+	})
+	return queued, nil
+}
+
+// DequeueRun removes runID from the persistent queue.
+func (b *BoltRepo) DequeueRun(_ context.Context, runID string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketQueue).Delete([]byte(runID))
+	})
+}
+
+// SetRunPriority updates a still-queued run's priority in place.
+func (b *BoltRepo) SetRunPriority(_ context.Context, runID string, priority int) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		queue := tx.Bucket(boltBucketQueue)
+		data := queue.Get([]byte(runID))
+		if data == nil {
+			return ErrRunNotQueued
+		}
+		var q QueuedRun
+		if err := json.Unmarshal(data, &q); err != nil {
+			return err
+		}
+		q.Priority = priority
+		updated, err := json.Marshal(q)
+		if err != nil {
+			return err
+		}
+		return queue.Put([]byte(runID), updated)
+	})
+}
+
+// RecordQueueWait stamps runID's queue wait time.
+func (b *BoltRepo) RecordQueueWait(_ context.Context, runID string, waitSeconds float64) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(boltBucketRuns)
+		data := runs.Get([]byte(runID))
+		if data == nil {
+			return fmt.Errorf("run %s not found", runID)
+		}
+		var run BenchmarkRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return err
+		}
+		run.QueueWaitSeconds = &waitSeconds
+		updated, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return runs.Put([]byte(runID), updated)
+	})
+}
+
+// RecordExecutionDuration stamps runID's execution duration.
+func (b *BoltRepo) RecordExecutionDuration(_ context.Context, runID string, seconds float64) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(boltBucketRuns)
+		data := runs.Get([]byte(runID))
+		if data == nil {
+			return fmt.Errorf("run %s not found", runID)
+		}
+		var run BenchmarkRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return err
+		}
+		run.ExecutionSeconds = &seconds
+		updated, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return runs.Put([]byte(runID), updated)
+	})
+}
+
+// VerifyRun checks a single run's samples, metrics, and references.
+func (b *BoltRepo) VerifyRun(ctx context.Context, runID string) (RunHealthStats, error) {
+	run, err := b.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		return RunHealthStats{}, err
+	}
+	if run == nil {
+		return RunHealthStats{}, fmt.Errorf("run %s not found", runID)
+	}
+	metrics, err := b.GetMetricsByRunID(ctx, runID)
+	if err != nil {
+		return RunHealthStats{}, err
+	}
+
+	var model *Model
+	var inst *InstanceType
+	var samples []Sample
+	err = b.db.View(func(tx *bolt.Tx) error {
+		model = b.modelByID(tx, run.ModelID)
+		inst = b.instanceTypeByID(tx, run.InstanceTypeID)
+		if data := tx.Bucket(boltBucketSamples).Get([]byte(runID)); data != nil {
+			var stored boltSamples
+			if err := json.Unmarshal(data, &stored); err != nil {
+				return err
+			}
+			samples = stored.Samples
+		}
+		return nil
+	})
+	if err != nil {
+		return RunHealthStats{}, err
+	}
+
+	return verifyRunHealth(run, metrics, model, inst, samples), nil
+}
+
+// GatherCatalogHealth verifies every run CatalogFilter f selects.
+func (b *BoltRepo) GatherCatalogHealth(ctx context.Context, f CatalogFilter) ([]RunHealthStats, error) {
+	return gatherCatalogHealth(ctx, b, f)
+}
+
+// MarkRunHealthFailure supersedes runID and records why, rather than
+// deleting it.
+func (b *BoltRepo) MarkRunHealthFailure(_ context.Context, runID, reason string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(boltBucketRuns)
+		data := runs.Get([]byte(runID))
+		if data == nil {
+			return fmt.Errorf("run %s not found", runID)
+		}
+		var run BenchmarkRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return err
+		}
+		run.Superseded = true
+		run.HealthFailureReason = &reason
+		updated, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		return runs.Put([]byte(runID), updated)
+	})
+}
+
+// QueryCatalog evaluates a PromQL-style expr against the catalog as of at.
+func (b *BoltRepo) QueryCatalog(ctx context.Context, expr string, at time.Time) (Vector, error) {
+	entries, _, err := b.ListCatalog(ctx, CatalogFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return evalCatalogQuery(entries, b.fetchSamplesForQuery, expr, at)
+}
+
+// QueryCatalogRange evaluates expr at each step between from and to.
+func (b *BoltRepo) QueryCatalogRange(ctx context.Context, expr string, from, to time.Time, step time.Duration) (Matrix, error) {
+	entries, _, err := b.ListCatalog(ctx, CatalogFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return evalCatalogRangeQuery(entries, b.fetchSamplesForQuery, expr, from, to, step)
+}
+
+func (b *BoltRepo) fetchSamplesForQuery(runID string) ([]Sample, error) {
+	var samples []Sample
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucketSamples).Get([]byte(runID))
+		if data == nil {
+			return nil
+		}
+		var stored boltSamples
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return err
+		}
+		samples = stored.Samples
+		return nil
+	})
+	return samples, err
+}
+
+// boltSamples is the JSON-encoded value stored per run in
+// boltBucketSamples: unlike MockRepo's in-process chunked sampleStore,
+// BoltDB already gives every Put its own durable, sequential append to
+// the file, so one growing slice per run stands in for a WAL — there's
+// no separate "head chunk" to track.
+type boltSamples struct {
+	Samples []Sample `json:"samples"`
+}
+
+// boltAccelSamples is the JSON-encoded value stored per run in
+// boltBucketAccelSamples, the AcceleratorSample counterpart to boltSamples.
+type boltAccelSamples struct {
+	Samples []AcceleratorSample `json:"samples"`
+}
+
+// GetAcceleratorSamples returns runID's raw accelerator samples, or nil if
+// none were recorded.
+func (b *BoltRepo) GetAcceleratorSamples(_ context.Context, runID string) ([]AcceleratorSample, error) {
+	var stored boltAccelSamples
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucketAccelSamples).Get([]byte(runID))
+		if data == nil {
+			return nil
+		}
+		return json.Unmarshal(data, &stored)
+	})
+	return stored.Samples, err
+}
+
+// AppendSamples adds raw per-request samples for runID to its BoltDB
+// record, read-modify-write under the single Update transaction BoltDB
+// serializes all writers through.
+func (b *BoltRepo) AppendSamples(_ context.Context, runID string, samples []Sample) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(boltBucketRuns)
+		if runs.Get([]byte(runID)) == nil {
+			return fmt.Errorf("run %s not found", runID)
+		}
+
+		bucket := tx.Bucket(boltBucketSamples)
+		var existing boltSamples
+		if data := bucket.Get([]byte(runID)); data != nil {
+			if err := json.Unmarshal(data, &existing); err != nil {
+				return err
+			}
+		}
+		existing.Samples = append(existing.Samples, samples...)
+		data, err := json.Marshal(existing)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(runID), data)
+	})
+}
+
+// QueryRange averages metric over step-sized buckets spanning [from, to).
+func (b *BoltRepo) QueryRange(_ context.Context, runID, metric string, from, to time.Time, step time.Duration) ([]Point, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be positive")
+	}
+
+	var stored boltSamples
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucketSamples).Get([]byte(runID))
+		if data == nil {
+			return nil
+		}
+		return json.Unmarshal(data, &stored)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var points []Point
+	for bucketStart := from; bucketStart.Before(to); bucketStart = bucketStart.Add(step) {
+		bucketEnd := bucketStart.Add(step)
+		var sum float64
+		var count int
+		for _, sm := range stored.Samples {
+			if sm.Timestamp.Before(bucketStart) || !sm.Timestamp.Before(bucketEnd) {
+				continue
+			}
+			if v, ok := sampleMetric(sm, metric); ok {
+				sum += v
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		points = append(points, Point{Timestamp: bucketStart, Value: sum / float64(count)})
+	}
+	return points, nil
+}
+
+// Compact is a no-op on BoltRepo: a run's samples already live as a
+// single record (see boltSamples), so there are no separate chunks to
+// merge the way MockRepo's in-process sampleStore has.
+func (b *BoltRepo) Compact(_ context.Context) error { return nil }
+
+// ApplyRetention drops samples older than defaultSampleRetention from
+// every run's record except those still "running", so an in-flight
+// benchmark never loses samples out from under its own loadgen.
+func (b *BoltRepo) ApplyRetention(_ context.Context) error {
+	cutoff := time.Now().Add(-defaultSampleRetention)
+	return b.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(boltBucketRuns)
+		samples := tx.Bucket(boltBucketSamples)
+		return samples.ForEach(func(runIDKey, data []byte) error {
+			runData := runs.Get(runIDKey)
+			if runData != nil {
+				var run BenchmarkRun
+				if err := json.Unmarshal(runData, &run); err == nil && run.Status == "running" {
+					return nil
+				}
+			}
+
+			var stored boltSamples
+			if err := json.Unmarshal(data, &stored); err != nil {
+				return err
+			}
+			kept := stored.Samples[:0]
+			for _, sm := range stored.Samples {
+				if !sm.Timestamp.Before(cutoff) {
+					kept = append(kept, sm)
+				}
+			}
+			stored.Samples = kept
+			updated, err := json.Marshal(stored)
+			if err != nil {
+				return err
+			}
+			return samples.Put(runIDKey, updated)
+		})
+	})
+}
+
+// CompareRuns returns runIDA's and runIDB's raw samples, reusing the
+// same read path QueryCatalog's quantile_over_time draws from.
+func (b *BoltRepo) CompareRuns(_ context.Context, runIDA, runIDB string) (control, treatment []Sample, err error) {
+	control, err = b.fetchSamplesForQuery(runIDA)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load samples for %s: %w", runIDA, err)
+	}
+	treatment, err = b.fetchSamplesForQuery(runIDB)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load samples for %s: %w", runIDB, err)
+	}
+	return control, treatment, nil
+}
+
+// Snapshot reads out BoltRepo's entire contents for migration to another
+// backend via Import.
+func (b *BoltRepo) Snapshot(_ context.Context) (StoreSnapshot, error) {
+	var snap StoreSnapshot
+	err := b.db.View(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(boltBucketModels).ForEach(func(_, v []byte) error {
+			var m Model
+			if err := json.Unmarshal(v, &m); err != nil {
+				return err
+			}
+			snap.Models = append(snap.Models, m)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucketInstTypes).ForEach(func(_, v []byte) error {
+			var it InstanceType
+			if err := json.Unmarshal(v, &it); err != nil {
+				return err
+			}
+			snap.InstanceTypes = append(snap.InstanceTypes, it)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucketRuns).ForEach(func(_, v []byte) error {
+			var r BenchmarkRun
+			if err := json.Unmarshal(v, &r); err != nil {
+				return err
+			}
+			snap.Runs = append(snap.Runs, r)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucketMetrics).ForEach(func(_, v []byte) error {
+			var m BenchmarkMetrics
+			if err := json.Unmarshal(v, &m); err != nil {
+				return err
+			}
+			snap.Metrics = append(snap.Metrics, m)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucketSweeps).ForEach(func(_, v []byte) error {
+			var s Sweep
+			if err := json.Unmarshal(v, &s); err != nil {
+				return err
+			}
+			snap.Sweeps = append(snap.Sweeps, s)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucketExperiments).ForEach(func(_, v []byte) error {
+			var e Experiment
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			snap.Experiments = append(snap.Experiments, e)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucketSamples).ForEach(func(k, v []byte) error {
+			var stored boltSamples
+			if err := json.Unmarshal(v, &stored); err != nil {
+				return err
+			}
+			snap.Samples = append(snap.Samples, RunSamples{RunID: string(k), Samples: stored.Samples})
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucketAccelSamples).ForEach(func(k, v []byte) error {
+			var stored boltAccelSamples
+			if err := json.Unmarshal(v, &stored); err != nil {
+				return err
+			}
+			snap.AcceleratorSamples = append(snap.AcceleratorSamples, RunAcceleratorSamples{RunID: string(k), Samples: stored.Samples})
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucketGroups).ForEach(func(_, v []byte) error {
+			var g BenchmarkGroup
+			if err := json.Unmarshal(v, &g); err != nil {
+				return err
+			}
+			snap.Groups = append(snap.Groups, g)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucketPolicies).ForEach(func(_, v []byte) error {
+			var p BenchmarkPolicy
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+			snap.Policies = append(snap.Policies, p)
+			return nil
+		}); err != nil {
+			return err
+		}
+		return tx.Bucket(boltBucketQueue).ForEach(func(_, v []byte) error {
+			var q QueuedRun
+			if err := json.Unmarshal(v, &q); err != nil {
+				return err
+			}
+			snap.Queue = append(snap.Queue, q)
+			return nil
+		})
+	})
+	return snap, err
+}
+
+// Import bulk-loads a StoreSnapshot, preserving every original ID so
+// cross-references between runs, models, and instance types stay intact,
+// and advancing BoltRepo's own ID sequences past the highest imported
+// value so subsequently created records can't collide with them.
+func (b *BoltRepo) Import(_ context.Context, snap StoreSnapshot) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		for _, m := range snap.Models {
+			data, err := json.Marshal(m)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketModels).Put([]byte(m.ID), data); err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketModelsByHf).Put(modelHfKey(m.HfID, m.HfRevision), []byte(m.ID)); err != nil {
+				return err
+			}
+			bumpBoltSeq(tx, "models", m.ID, "model-")
+		}
+		for _, it := range snap.InstanceTypes {
+			data, err := json.Marshal(it)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketInstTypes).Put([]byte(it.Name), data); err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketInstTypesByID).Put([]byte(it.ID), []byte(it.Name)); err != nil {
+				return err
+			}
+		}
+		for _, r := range snap.Runs {
+			data, err := json.Marshal(r)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketRuns).Put([]byte(r.ID), data); err != nil {
+				return err
+			}
+			bumpBoltSeq(tx, "runs", r.ID, "run-")
+		}
+		for _, m := range snap.Metrics {
+			data, err := json.Marshal(m)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketMetrics).Put([]byte(m.RunID), data); err != nil {
+				return err
+			}
+			bumpBoltSeq(tx, "metrics", m.ID, "met-")
+		}
+		for _, s := range snap.Sweeps {
+			data, err := json.Marshal(s)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketSweeps).Put([]byte(s.ID), data); err != nil {
+				return err
+			}
+			bumpBoltSeq(tx, "sweeps", s.ID, "sweep-")
+		}
+		for _, e := range snap.Experiments {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketExperiments).Put([]byte(e.ID), data); err != nil {
+				return err
+			}
+			bumpBoltSeq(tx, "experiments", e.ID, "experiment-")
+		}
+		for _, rs := range snap.Samples {
+			data, err := json.Marshal(boltSamples{Samples: rs.Samples})
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketSamples).Put([]byte(rs.RunID), data); err != nil {
+				return err
+			}
+		}
+		for _, rs := range snap.AcceleratorSamples {
+			data, err := json.Marshal(boltAccelSamples{Samples: rs.Samples})
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketAccelSamples).Put([]byte(rs.RunID), data); err != nil {
+				return err
+			}
+		}
+		for _, g := range snap.Groups {
+			data, err := json.Marshal(g)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketGroups).Put([]byte(g.ID), data); err != nil {
+				return err
+			}
+			bumpBoltSeq(tx, "groups", g.ID, "group-")
+		}
+		for _, p := range snap.Policies {
+			data, err := json.Marshal(p)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketPolicies).Put([]byte(p.ID), data); err != nil {
+				return err
+			}
+			bumpBoltSeq(tx, "policies", p.ID, "policy-")
+		}
+		for _, q := range snap.Queue {
+			data, err := json.Marshal(q)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(boltBucketQueue).Put([]byte(q.RunID), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// bumpBoltSeq advances kind's sequence counter past the numeric suffix of
+// id (e.g. "run-00000042" -> 42) if it's higher than what's stored, so
+// Import never hands out an ID that collides with one it just imported.
+func bumpBoltSeq(tx *bolt.Tx, kind, id, prefix string) {
+	n, err := parseSeqSuffix(id, prefix)
+	if err != nil {
+		return
+	}
+	seq := tx.Bucket(boltBucketSeq)
+	var cur uint64
+	if v := seq.Get([]byte(kind)); v != nil {
+		cur = binary.BigEndian.Uint64(v)
+	}
+	if n <= cur {
+		return
+	}
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, n)
+	seq.Put([]byte(kind), buf)
+}
+
+func parseSeqSuffix(id, prefix string) (uint64, error) {
+	var n uint64
+	_, err := fmt.Sscanf(strings.TrimPrefix(id, prefix), "%08d", &n)
+	return n, err
+}
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// RunCallback is one row in the run_callbacks outbox: a pending (or
+// retrying, or exhausted) HTTP notification for a single run's terminal
+// status transition. UpdateRunStatus enqueues one whenever a run with a
+// CallbackURL reaches "completed" or "failed"; the callbacks package's
+// Worker is the only thing that ever reads them back out.
+type RunCallback struct {
+	ID      string
+	RunID   string
+	URL     string
+	Token   string
+	Status  string // the run's terminal status being reported: "completed" or "failed"
+	Summary RunCallbackSummary
+
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	DeliveredAt   *time.Time
+	CreatedAt     time.Time
+}
+
+// RunCallbackSummary is the headline-metrics subset of BenchmarkMetrics
+// sent in a callback POST's body, deliberately much smaller than the full
+// BenchmarkMetrics row: a receiver deciding whether to page someone or
+// kick off a follow-up job needs "was it fast, did it fail", not every
+// accelerator percentile this server tracks. A caller that wants the full
+// picture can always follow up with GetMetrics.
+type RunCallbackSummary struct {
+	ThroughputAggregateTPS *float64 `json:"throughput_aggregate_tps,omitempty"`
+	TTFTP50Ms              *float64 `json:"ttft_p50_ms,omitempty"`
+	E2ELatencyP50Ms        *float64 `json:"e2e_latency_p50_ms,omitempty"`
+}
+
+// summarizeMetrics builds a RunCallbackSummary from m, which may be nil
+// (a run can reach "failed" before any metrics were ever persisted).
+func summarizeMetrics(m *BenchmarkMetrics) RunCallbackSummary {
+	if m == nil {
+		return RunCallbackSummary{}
+	}
+	return RunCallbackSummary{
+		ThroughputAggregateTPS: m.ThroughputAggregateTPS,
+		TTFTP50Ms:              m.TTFTP50Ms,
+		E2ELatencyP50Ms:        m.E2ELatencyP50Ms,
+	}
+}
+
+// callbackBackoff is the retry schedule callbacks.Worker drives
+// ListDueRunCallbacks/ClaimDueRunCallbacks with: RecordRunCallbackResult
+// sets NextAttemptAt to now plus this slice's [Attempts-1] entry (clamped
+// to the last entry once Attempts exceeds its length), so a flaky
+// receiver gets several quick retries before backing off to a slow trickle
+// rather than being hammered or given up on too early.
+var callbackBackoff = []time.Duration{
+	10 * time.Second,
+	30 * time.Second,
+	2 * time.Minute,
+	10 * time.Minute,
+	30 * time.Minute,
+}
+
+// MaxCallbackAttempts is how many failed delivery attempts a row may record
+// before ListDueRunCallbacks/ClaimDueRunCallbacks stop returning it. The row
+// is left undelivered in the outbox, with its Attempts and LastError, for an
+// operator to notice and investigate; it has no separate "failed" status.
+const MaxCallbackAttempts = 8
+
+// NextCallbackBackoff returns how long to wait before retrying a callback
+// that has failed attempts times (1-indexed: pass 1 after the first
+// failure), for the callbacks package's Worker to compute
+// RecordRunCallbackResult's nextAttemptAt.
+func NextCallbackBackoff(attempts int) time.Duration {
+	idx := attempts - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(callbackBackoff) {
+		idx = len(callbackBackoff) - 1
+	}
+	return callbackBackoff[idx]
+}
+
+// RunCallbackClaimer is implemented by a run_callbacks backend that can
+// safely be polled by more than one callbacks.Worker process at once:
+// ClaimDueRunCallbacks atomically claims every due row via
+// `SELECT ... FOR UPDATE SKIP LOCKED`, mirroring LeasedRunQueue and
+// PolicyClaimer.
+//
+// Only *Repository (Postgres) implements this today; callbacks.Worker
+// type-asserts for it and falls back to ListDueRunCallbacks against any
+// database.Repo that doesn't, which is safe as long as exactly one
+// Worker process is polling that backend.
+type RunCallbackClaimer interface {
+	ClaimDueRunCallbacks(ctx context.Context, now time.Time, limit int) ([]RunCallback, error)
+}
+
+// marshalSummary is a small helper shared by every backend's
+// ListDueRunCallbacks/ClaimDueRunCallbacks to round-trip RunCallbackSummary
+// through the JSON column each of them stores it in.
+func marshalSummary(s RunCallbackSummary) ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func unmarshalSummary(data []byte) (RunCallbackSummary, error) {
+	var s RunCallbackSummary
+	if len(data) == 0 {
+		return s, nil
+	}
+	err := json.Unmarshal(data, &s)
+	return s, err
+}
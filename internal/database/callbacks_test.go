@@ -0,0 +1,171 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func seedRunWithCallback(t *testing.T, repo *MockRepo) string {
+	t.Helper()
+	ctx := context.Background()
+	run := &BenchmarkRun{
+		Framework:     "vllm",
+		Status:        "pending",
+		CallbackURL:   "https://example.com/hook",
+		CallbackToken: "secret-token",
+	}
+	id, err := repo.CreateBenchmarkRun(ctx, run)
+	if err != nil {
+		t.Fatalf("create run: %v", err)
+	}
+	return id
+}
+
+func TestUpdateRunStatus_EnqueuesCallbackOnTerminalStatus(t *testing.T) {
+	repo := NewMockRepo()
+	ctx := context.Background()
+	runID := seedRunWithCallback(t, repo)
+
+	if err := repo.UpdateRunStatus(ctx, runID, "running"); err != nil {
+		t.Fatalf("update to running: %v", err)
+	}
+	due, err := repo.ListDueRunCallbacks(ctx, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("list due: %v", err)
+	}
+	if len(due) != 0 {
+		t.Fatalf("expected no callbacks after a non-terminal transition, got %d", len(due))
+	}
+
+	if err := repo.UpdateRunStatus(ctx, runID, "completed"); err != nil {
+		t.Fatalf("update to completed: %v", err)
+	}
+	due, err = repo.ListDueRunCallbacks(ctx, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("list due: %v", err)
+	}
+	if len(due) != 1 {
+		t.Fatalf("expected 1 due callback, got %d", len(due))
+	}
+	if due[0].RunID != runID || due[0].Status != "completed" {
+		t.Errorf("unexpected callback: %+v", due[0])
+	}
+	if due[0].URL != "https://example.com/hook" || due[0].Token != "secret-token" {
+		t.Errorf("callback didn't inherit run's callback config: %+v", due[0])
+	}
+}
+
+func TestCancelRun_EnqueuesFailedCallback(t *testing.T) {
+	repo := NewMockRepo()
+	ctx := context.Background()
+	runID := seedRunWithCallback(t, repo)
+
+	if err := repo.CancelRun(ctx, runID); err != nil {
+		t.Fatalf("cancel run: %v", err)
+	}
+	due, err := repo.ListDueRunCallbacks(ctx, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("list due: %v", err)
+	}
+	if len(due) != 1 || due[0].Status != "failed" {
+		t.Fatalf("expected 1 failed callback, got %+v", due)
+	}
+}
+
+func TestRunWithoutCallbackURL_NeverEnqueues(t *testing.T) {
+	repo := NewMockRepo()
+	ctx := context.Background()
+	id, err := repo.CreateBenchmarkRun(ctx, &BenchmarkRun{Framework: "vllm", Status: "pending"})
+	if err != nil {
+		t.Fatalf("create run: %v", err)
+	}
+	if err := repo.UpdateRunStatus(ctx, id, "completed"); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+	due, err := repo.ListDueRunCallbacks(ctx, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("list due: %v", err)
+	}
+	if len(due) != 0 {
+		t.Fatalf("expected no callbacks for a run without CallbackURL, got %d", len(due))
+	}
+}
+
+func TestRecordRunCallbackResult_SuccessMarksDelivered(t *testing.T) {
+	repo := NewMockRepo()
+	ctx := context.Background()
+	runID := seedRunWithCallback(t, repo)
+	if err := repo.UpdateRunStatus(ctx, runID, "completed"); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+	due, err := repo.ListDueRunCallbacks(ctx, time.Now(), 10)
+	if err != nil || len(due) != 1 {
+		t.Fatalf("list due: %v / %+v", err, due)
+	}
+
+	if err := repo.RecordRunCallbackResult(ctx, due[0].ID, nil, nil); err != nil {
+		t.Fatalf("record result: %v", err)
+	}
+	due, err = repo.ListDueRunCallbacks(ctx, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("list due: %v", err)
+	}
+	if len(due) != 0 {
+		t.Fatalf("expected delivered callback to drop out of the due list, got %+v", due)
+	}
+}
+
+func TestRecordRunCallbackResult_FailureReschedulesUntilExhausted(t *testing.T) {
+	repo := NewMockRepo()
+	ctx := context.Background()
+	runID := seedRunWithCallback(t, repo)
+	if err := repo.UpdateRunStatus(ctx, runID, "completed"); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+	due, err := repo.ListDueRunCallbacks(ctx, time.Now(), 10)
+	if err != nil || len(due) != 1 {
+		t.Fatalf("list due: %v / %+v", err, due)
+	}
+	id := due[0].ID
+
+	deliverErr := errors.New("receiver returned 503")
+	for attempt := 1; attempt <= MaxCallbackAttempts; attempt++ {
+		past := time.Now().Add(-time.Second)
+		if err := repo.RecordRunCallbackResult(ctx, id, deliverErr, &past); err != nil {
+			t.Fatalf("record result (attempt %d): %v", attempt, err)
+		}
+		due, err = repo.ListDueRunCallbacks(ctx, time.Now(), 10)
+		if err != nil {
+			t.Fatalf("list due (attempt %d): %v", attempt, err)
+		}
+		if attempt < MaxCallbackAttempts {
+			if len(due) != 1 {
+				t.Fatalf("attempt %d: expected callback still due, got %+v", attempt, due)
+			}
+		} else {
+			if len(due) != 0 {
+				t.Fatalf("attempt %d: expected exhausted callback to stop being due, got %+v", attempt, due)
+			}
+		}
+	}
+}
+
+func TestNextCallbackBackoff(t *testing.T) {
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 10 * time.Second},
+		{1, 10 * time.Second},
+		{2, 30 * time.Second},
+		{5, 30 * time.Minute},
+		{100, 30 * time.Minute},
+	}
+	for _, tc := range cases {
+		if got := NextCallbackBackoff(tc.attempts); got != tc.want {
+			t.Errorf("NextCallbackBackoff(%d) = %v, want %v", tc.attempts, got, tc.want)
+		}
+	}
+}
@@ -3,44 +3,67 @@ package database
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
 // CatalogEntry is a denormalized view joining benchmark runs, models,
 // instance types, and metrics for catalog display.
 type CatalogEntry struct {
-	RunID                string   `json:"run_id"`
-	ModelHfID            string   `json:"model_hf_id"`
-	ModelFamily          *string  `json:"model_family,omitempty"`
-	ParameterCount       *int64   `json:"parameter_count,omitempty"`
-	InstanceTypeName     string   `json:"instance_type_name"`
-	InstanceFamily       string   `json:"instance_family"`
-	AcceleratorType      string   `json:"accelerator_type"`
-	AcceleratorName      string   `json:"accelerator_name"`
-	AcceleratorCount     int      `json:"accelerator_count"`
-	AcceleratorMemoryGiB int      `json:"accelerator_memory_gib"`
-	Framework            string   `json:"framework"`
-	FrameworkVersion     string   `json:"framework_version"`
-	TensorParallelDegree int      `json:"tensor_parallel_degree"`
-	Quantization         *string  `json:"quantization,omitempty"`
-	Concurrency          int      `json:"concurrency"`
-	InputSequenceLength  int      `json:"input_sequence_length"`
-	OutputSequenceLength int      `json:"output_sequence_length"`
+	RunID                string     `json:"run_id"`
+	ModelHfID            string     `json:"model_hf_id"`
+	ModelFamily          *string    `json:"model_family,omitempty"`
+	ParameterCount       *int64     `json:"parameter_count,omitempty"`
+	InstanceTypeName     string     `json:"instance_type_name"`
+	InstanceFamily       string     `json:"instance_family"`
+	AcceleratorType      string     `json:"accelerator_type"`
+	AcceleratorName      string     `json:"accelerator_name"`
+	AcceleratorCount     int        `json:"accelerator_count"`
+	AcceleratorMemoryGiB int        `json:"accelerator_memory_gib"`
+	Framework            string     `json:"framework"`
+	FrameworkVersion     string     `json:"framework_version"`
+	TensorParallelDegree int        `json:"tensor_parallel_degree"`
+	Quantization         *string    `json:"quantization,omitempty"`
+	Concurrency          int        `json:"concurrency"`
+	InputSequenceLength  int        `json:"input_sequence_length"`
+	OutputSequenceLength int        `json:"output_sequence_length"`
 	CompletedAt          *time.Time `json:"completed_at,omitempty"`
+	GroupID              *string    `json:"group_id,omitempty"`
+	TemplateIndex        int        `json:"template_index,omitempty"`
 
 	// Metrics (inlined from benchmark_metrics)
-	TTFTP50Ms                *float64 `json:"ttft_p50_ms,omitempty"`
-	TTFTP99Ms                *float64 `json:"ttft_p99_ms,omitempty"`
-	E2ELatencyP50Ms          *float64 `json:"e2e_latency_p50_ms,omitempty"`
-	E2ELatencyP99Ms          *float64 `json:"e2e_latency_p99_ms,omitempty"`
-	ITLP50Ms                 *float64 `json:"itl_p50_ms,omitempty"`
-	ITLP99Ms                 *float64 `json:"itl_p99_ms,omitempty"`
-	ThroughputPerRequestTPS  *float64 `json:"throughput_per_request_tps,omitempty"`
-	ThroughputAggregateTPS   *float64 `json:"throughput_aggregate_tps,omitempty"`
-	RequestsPerSecond        *float64 `json:"requests_per_second,omitempty"`
+	TTFTP50Ms                 *float64 `json:"ttft_p50_ms,omitempty"`
+	TTFTP99Ms                 *float64 `json:"ttft_p99_ms,omitempty"`
+	E2ELatencyP50Ms           *float64 `json:"e2e_latency_p50_ms,omitempty"`
+	E2ELatencyP99Ms           *float64 `json:"e2e_latency_p99_ms,omitempty"`
+	ITLP50Ms                  *float64 `json:"itl_p50_ms,omitempty"`
+	ITLP99Ms                  *float64 `json:"itl_p99_ms,omitempty"`
+	ThroughputPerRequestTPS   *float64 `json:"throughput_per_request_tps,omitempty"`
+	ThroughputAggregateTPS    *float64 `json:"throughput_aggregate_tps,omitempty"`
+	RequestsPerSecond         *float64 `json:"requests_per_second,omitempty"`
 	AcceleratorUtilizationPct *float64 `json:"accelerator_utilization_pct,omitempty"`
-	AcceleratorMemoryPeakGiB *float64 `json:"accelerator_memory_peak_gib,omitempty"`
+	AcceleratorMemoryPeakGiB  *float64 `json:"accelerator_memory_peak_gib,omitempty"`
+
+	ServerTTFTP50Ms       *float64 `json:"server_ttft_p50_ms,omitempty"`
+	ServerTTFTP99Ms       *float64 `json:"server_ttft_p99_ms,omitempty"`
+	ServerE2ELatencyP50Ms *float64 `json:"server_e2e_latency_p50_ms,omitempty"`
+	ServerE2ELatencyP99Ms *float64 `json:"server_e2e_latency_p99_ms,omitempty"`
+
+	// Real device occupancy from the DCGM exporter/neuron-monitor, as
+	// opposed to AcceleratorUtilizationPct which is derived from vLLM's
+	// KV-cache usage.
+	SMActivePeakPct *float64 `json:"sm_active_peak_pct,omitempty"`
+	PowerAvgW       *float64 `json:"power_avg_w,omitempty"`
+
+	// CostDeltaPct is the percentage change between the instance type's
+	// on-demand rate when this run completed and its current rate, so a
+	// shift in cost-per-token that's purely due to an AWS/GCP/Azure price
+	// change is visible without re-running the benchmark. Populated by
+	// the API layer (see applyCostDeltas), not by ListCatalog itself,
+	// since it depends on a region that CatalogFilter has no notion of.
+	CostDeltaPct *float64 `json:"cost_delta_pct,omitempty"`
 }
 
 // CatalogFilter holds optional filters for catalog queries.
@@ -49,32 +72,171 @@ type CatalogFilter struct {
 	ModelFamily     string // exact match on model_family
 	InstanceFamily  string // exact match on instance family (e.g. "p5")
 	AcceleratorType string // "gpu" or "neuron"
+	GroupID         string // exact match on the owning BenchmarkGroup, if any
+	LatestPerGroup  bool   // keep only the newest completed run per (GroupID, TemplateIndex)
 	SortBy          string // column name to sort by
 	SortDesc        bool   // true for descending sort
 	Limit           int    // max results (0 = default 100)
 	Offset          int    // pagination offset
+
+	// ShardID/ShardCount restrict the result to the subset of runs whose
+	// ID hashes into shard ShardID of ShardCount, so N workers can each
+	// call ListCatalog with the same filter and a distinct ShardID and
+	// split the scan between them. ShardCount <= 1 disables sharding.
+	// When ShardCount > 1, Limit/Offset are ignored here — apply them
+	// after merging every shard's results, as ListCatalogSharded does.
+	ShardID    int
+	ShardCount int
+
+	// AfterRunID and AfterSortValue together select keyset pagination
+	// instead of Offset: when AfterRunID is set, Repository.ListCatalog
+	// (and ListCatalogStream) only return rows that sort strictly after
+	// the entry identified by (AfterSortValue, AfterRunID) under SortBy —
+	// i.e. the last row the caller saw on the previous page. Unlike
+	// Offset, this stays correct as rows are inserted or superseded
+	// between pages, and doesn't force Postgres to walk and discard the
+	// rows before the page. AfterSortValue must be assignable to whatever
+	// column SortBy resolves to (via allowedSortColumns), or to
+	// br.completed_at's time.Time when SortBy is unset. Only honored by
+	// Repository; the in-memory Repo backends (MockRepo, BoltRepo,
+	// EtcdRepo) don't implement keyset pagination and ignore these fields.
+	AfterRunID     string
+	AfterSortValue any
+}
+
+// filterLatestPerGroup keeps only the most recently completed entry per
+// (GroupID, TemplateIndex) tuple, passing through any entry with no
+// GroupID untouched. Used by the in-process Repo backends (MockRepo,
+// BoltRepo, EtcdRepo) to apply CatalogFilter.LatestPerGroup in Go;
+// Repository performs the equivalent filter in SQL.
+func filterLatestPerGroup(entries []CatalogEntry) []CatalogEntry {
+	type groupKey struct {
+		groupID string
+		tmpl    int
+	}
+	latest := make(map[groupKey]CatalogEntry)
+	result := make([]CatalogEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.GroupID == nil {
+			result = append(result, e)
+			continue
+		}
+		k := groupKey{*e.GroupID, e.TemplateIndex}
+		cur, ok := latest[k]
+		if !ok || (e.CompletedAt != nil && (cur.CompletedAt == nil || e.CompletedAt.After(*cur.CompletedAt))) {
+			latest[k] = e
+		}
+	}
+	for _, e := range latest {
+		result = append(result, e)
+	}
+	return result
+}
+
+// ListCatalogSharded fans f out across shardCount concurrent ListCatalog
+// calls (ShardID 0..shardCount-1), merges their results with a stable
+// sort by CompletedAt desc then RunID, and only then applies f's
+// Limit/Offset — so pagination behaves the same as an unsharded
+// ListCatalog call regardless of how many shards did the scanning. This
+// is what lets a multi-tenant deployment split a large catalog scan
+// across worker goroutines (or, calling a shared Repository, processes)
+// without the real work of building entries being serialized. The
+// returned total is the merged, pre-pagination count across every
+// shard — each shard's own ListCatalog total only describes that one
+// shard, so it's discarded here in favor of len(merged).
+func ListCatalogSharded(ctx context.Context, repo Repo, f CatalogFilter, shardCount int) ([]CatalogEntry, int, error) {
+	if shardCount < 1 {
+		shardCount = 1
+	}
+
+	shardResults := make([][]CatalogEntry, shardCount)
+	shardErrs := make([]error, shardCount)
+	var wg sync.WaitGroup
+	for i := 0; i < shardCount; i++ {
+		wg.Add(1)
+		go func(shardID int) {
+			defer wg.Done()
+			shardFilter := f
+			shardFilter.ShardID = shardID
+			shardFilter.ShardCount = shardCount
+			shardResults[shardID], _, shardErrs[shardID] = repo.ListCatalog(ctx, shardFilter)
+		}(i)
+	}
+	wg.Wait()
+
+	var merged []CatalogEntry
+	for i, err := range shardErrs {
+		if err != nil {
+			return nil, 0, fmt.Errorf("shard %d: %w", i, err)
+		}
+		merged = append(merged, shardResults[i]...)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		a, b := merged[i], merged[j]
+		switch {
+		case a.CompletedAt == nil && b.CompletedAt == nil:
+			return a.RunID < b.RunID
+		case a.CompletedAt == nil:
+			return false
+		case b.CompletedAt == nil:
+			return true
+		case !a.CompletedAt.Equal(*b.CompletedAt):
+			return a.CompletedAt.After(*b.CompletedAt)
+		default:
+			return a.RunID < b.RunID
+		}
+	})
+
+	total := len(merged)
+
+	offset := f.Offset
+	if offset > 0 {
+		if offset >= len(merged) {
+			return nil, total, nil
+		}
+		merged = merged[offset:]
+	}
+	limit := 100
+	if f.Limit > 0 && f.Limit <= 500 {
+		limit = f.Limit
+	}
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged, total, nil
 }
 
 // allowedSortColumns maps user-facing sort keys to SQL column expressions.
 var allowedSortColumns = map[string]string{
-	"model":                    "m.hf_id",
-	"instance":                 "it.name",
-	"ttft_p50":                 "bm.ttft_p50_ms",
-	"ttft_p99":                 "bm.ttft_p99_ms",
-	"e2e_latency_p50":          "bm.e2e_latency_p50_ms",
-	"e2e_latency_p99":          "bm.e2e_latency_p99_ms",
-	"itl_p50":                  "bm.itl_p50_ms",
-	"itl_p99":                  "bm.itl_p99_ms",
-	"throughput_per_request":    "bm.throughput_per_request_tps",
-	"throughput_aggregate":      "bm.throughput_aggregate_tps",
-	"requests_per_second":       "bm.requests_per_second",
-	"accelerator_utilization":   "bm.accelerator_utilization_pct",
-	"accelerator_memory_peak":   "bm.accelerator_memory_peak_gib",
-	"completed_at":             "br.completed_at",
-}
-
-// ListCatalog queries the catalog with optional filters and sorting.
-func (r *Repository) ListCatalog(ctx context.Context, f CatalogFilter) ([]CatalogEntry, error) {
+	"model":                   "m.hf_id",
+	"instance":                "it.name",
+	"ttft_p50":                "bm.ttft_p50_ms",
+	"ttft_p99":                "bm.ttft_p99_ms",
+	"e2e_latency_p50":         "bm.e2e_latency_p50_ms",
+	"e2e_latency_p99":         "bm.e2e_latency_p99_ms",
+	"itl_p50":                 "bm.itl_p50_ms",
+	"itl_p99":                 "bm.itl_p99_ms",
+	"throughput_per_request":  "bm.throughput_per_request_tps",
+	"throughput_aggregate":    "bm.throughput_aggregate_tps",
+	"requests_per_second":     "bm.requests_per_second",
+	"accelerator_utilization": "bm.accelerator_utilization_pct",
+	"accelerator_memory_peak": "bm.accelerator_memory_peak_gib",
+	"completed_at":            "br.completed_at",
+}
+
+// catalogQueryParts holds the composed WHERE/ORDER BY clauses and their
+// positional args for a CatalogFilter, shared by ListCatalog's unsharded
+// path and ListCatalogStream so both apply exactly the same filters —
+// including keyset cursor pagination — from one place.
+type catalogQueryParts struct {
+	where   string
+	orderBy string
+	args    []any
+	argIdx  int
+}
+
+func buildCatalogQueryParts(f CatalogFilter) catalogQueryParts {
 	var (
 		conditions []string
 		args       []any
@@ -105,19 +267,121 @@ func (r *Repository) ListCatalog(ctx context.Context, f CatalogFilter) ([]Catalo
 		conditions = append(conditions, fmt.Sprintf("it.accelerator_type = $%d", argIdx))
 		args = append(args, f.AcceleratorType)
 	}
+	if f.GroupID != "" {
+		argIdx++
+		conditions = append(conditions, fmt.Sprintf("br.group_id = $%d", argIdx))
+		args = append(args, f.GroupID)
+	}
+	if f.LatestPerGroup {
+		// A grouped run is excluded if a newer completed, non-superseded
+		// sibling shares its (group_id, template_index); ungrouped runs
+		// are always kept, since there's nothing for them to supersede.
+		conditions = append(conditions, `(br.group_id IS NULL OR NOT EXISTS (
+			SELECT 1 FROM benchmark_runs br2
+			WHERE br2.group_id = br.group_id AND br2.template_index = br.template_index
+			  AND br2.status = 'completed' AND br2.superseded = FALSE
+			  AND br2.completed_at > br.completed_at
+		))`)
+	}
+	if f.ShardCount > 1 {
+		argIdx++
+		conditions = append(conditions, fmt.Sprintf("mod(mod(hashtext(br.id), $%d) + $%d, $%d) = $%d", argIdx, argIdx, argIdx, argIdx+1))
+		args = append(args, f.ShardCount)
+		argIdx++
+		args = append(args, f.ShardID)
+	}
 
-	where := "WHERE " + strings.Join(conditions, " AND ")
-
-	// Sort.
-	orderBy := "ORDER BY m.hf_id, it.name"
+	// Sort column + direction, resolved once so the ORDER BY clause and
+	// the keyset predicate below always agree on what "after" means.
+	// completed_at is the default keyset column (not the two-column
+	// model/instance default below) since it's the one column every
+	// catalog row has and that increases monotonically with insertion,
+	// which is what makes a cursor meaningful when SortBy is unset.
+	sortCol, sortDesc, sortResolved := "br.completed_at", true, false
 	if f.SortBy != "" {
 		if col, ok := allowedSortColumns[f.SortBy]; ok {
-			dir := "ASC"
-			if f.SortDesc {
-				dir = "DESC"
-			}
-			orderBy = fmt.Sprintf("ORDER BY %s %s NULLS LAST", col, dir)
+			sortCol, sortDesc, sortResolved = col, f.SortDesc, true
+		}
+	}
+
+	orderBy := "ORDER BY m.hf_id, it.name"
+	if sortResolved {
+		dir := "ASC"
+		if sortDesc {
+			dir = "DESC"
+		}
+		orderBy = fmt.Sprintf("ORDER BY %s %s NULLS LAST", sortCol, dir)
+	}
+
+	if f.AfterRunID != "" {
+		dir, cmp := "ASC", ">"
+		if sortDesc {
+			dir, cmp = "DESC", "<"
 		}
+		orderBy = fmt.Sprintf("ORDER BY %s %s NULLS LAST, br.id %s", sortCol, dir, dir)
+
+		argIdx++
+		valueArg := argIdx
+		argIdx++
+		idArg := argIdx
+		conditions = append(conditions, fmt.Sprintf("(%s, br.id) %s ($%d, $%d)", sortCol, cmp, valueArg, idArg))
+		args = append(args, f.AfterSortValue, f.AfterRunID)
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+	return catalogQueryParts{where: where, orderBy: orderBy, args: args, argIdx: argIdx}
+}
+
+// catalogSelectColumns is the column list every catalog query projects,
+// shared by ListCatalog's sharded and paginated paths and by
+// ListCatalogStream so all three scan rows the same way.
+const catalogSelectColumns = `
+	br.id, m.hf_id, m.model_family, m.parameter_count,
+	it.name, it.family, it.accelerator_type, it.accelerator_name,
+	it.accelerator_count, it.accelerator_memory_gib,
+	br.framework, br.framework_version, br.tensor_parallel_degree,
+	br.quantization, br.concurrency,
+	br.input_sequence_length, br.output_sequence_length,
+	br.completed_at, br.group_id, br.template_index,
+	bm.ttft_p50_ms, bm.ttft_p99_ms,
+	bm.e2e_latency_p50_ms, bm.e2e_latency_p99_ms,
+	bm.itl_p50_ms, bm.itl_p99_ms,
+	bm.throughput_per_request_tps, bm.throughput_aggregate_tps,
+	bm.requests_per_second,
+	bm.accelerator_utilization_pct, bm.accelerator_memory_peak_gib,
+	bm.server_ttft_p50_ms, bm.server_ttft_p99_ms,
+	bm.server_e2e_latency_p50_ms, bm.server_e2e_latency_p99_ms,
+	bm.sm_active_peak_pct, bm.power_avg_w
+`
+
+// catalogSelectFrom is the FROM/JOIN clause every catalog query shares.
+const catalogSelectFrom = `
+	FROM benchmark_runs br
+	JOIN models m ON br.model_id = m.id
+	JOIN instance_types it ON br.instance_type_id = it.id
+	JOIN benchmark_metrics bm ON bm.run_id = br.id
+`
+
+// ListCatalog queries the catalog with optional filters and sorting,
+// along with the total number of entries matching f (ignoring
+// f.Limit/f.Offset). When f.ShardCount > 1 the returned total is only
+// that shard's count, unpaginated like the entries themselves —
+// ListCatalogSharded recomputes the overall total after merging every
+// shard's results.
+func (r *Repository) ListCatalog(ctx context.Context, f CatalogFilter) ([]CatalogEntry, int, error) {
+	parts := buildCatalogQueryParts(f)
+	where, orderBy, args, argIdx := parts.where, parts.orderBy, parts.args, parts.argIdx
+
+	// A sharded call returns its whole shard unpaginated; the caller
+	// (ListCatalogSharded) applies Limit/Offset once after merging every
+	// shard's results.
+	if f.ShardCount > 1 {
+		query := fmt.Sprintf(`SELECT %s %s %s %s`, catalogSelectColumns, catalogSelectFrom, where, orderBy)
+		entries, err := r.queryCatalogRows(ctx, query, args)
+		if err != nil {
+			return nil, 0, err
+		}
+		return entries, len(entries), nil
 	}
 
 	// Pagination.
@@ -129,6 +393,75 @@ func (r *Repository) ListCatalog(ctx context.Context, f CatalogFilter) ([]Catalo
 	limitClause := fmt.Sprintf("LIMIT $%d", argIdx)
 	args = append(args, limit)
 
+	offsetClause := ""
+	paginationArgs := 1
+	if f.Offset > 0 {
+		argIdx++
+		offsetClause = fmt.Sprintf("OFFSET $%d", argIdx)
+		args = append(args, f.Offset)
+		paginationArgs = 2
+	}
+
+	// count(*) OVER() rides along with every row so the total matching f
+	// comes back in the same round trip; it's only absent when OFFSET
+	// skips past every matching row, in which case countCatalogMatching
+	// below fills it in with one extra query.
+	query := fmt.Sprintf(`SELECT %s, count(*) OVER() %s %s %s %s %s`,
+		catalogSelectColumns, catalogSelectFrom, where, orderBy, limitClause, offsetClause)
+
+	entries, total, err := r.queryCatalogRowsWithTotal(ctx, query, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(entries) == 0 && f.Offset > 0 {
+		total, err = r.countCatalogMatching(ctx, where, args[:argIdx-paginationArgs])
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return entries, total, nil
+}
+
+// countCatalogMatching runs a standalone SELECT count(*) over where/args,
+// used by ListCatalog only for the edge case where Offset skips past
+// every matching row, so count(*) OVER() never rides along on a
+// returned row.
+func (r *Repository) countCatalogMatching(ctx context.Context, where string, args []any) (int, error) {
+	query := fmt.Sprintf(`SELECT count(*) %s %s`, catalogSelectFrom, where)
+
+	var total int
+	if err := r.pool.QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("count catalog: %w", err)
+	}
+	return total, nil
+}
+
+// ListCatalogStream is ListCatalog's memory-bounded counterpart: instead of
+// collecting every row into a slice, it invokes fn once per row as
+// rows.Next() advances, so a caller paging through a catalog of tens of
+// thousands of rows (see the query command's --all flag) never holds more
+// than one CatalogEntry at a time. fn's error aborts iteration and is
+// returned to the caller unwrapped, so a caller can distinguish "fn asked
+// to stop" from a real query/scan failure. Limit/Offset behave as they do
+// for an unsharded ListCatalog call and are applied even when
+// ShardCount > 1, where ListCatalog returns the whole shard unpaginated —
+// combine this with
+// CatalogFilter.AfterRunID/AfterSortValue to stream one bounded page per
+// call instead of the whole catalog in one query.
+func (r *Repository) ListCatalogStream(ctx context.Context, f CatalogFilter, fn func(CatalogEntry) error) error {
+	parts := buildCatalogQueryParts(f)
+	where, orderBy, args, argIdx := parts.where, parts.orderBy, parts.args, parts.argIdx
+
+	limit := 100
+	if f.Limit > 0 && f.Limit <= 500 {
+		limit = f.Limit
+	}
+	argIdx++
+	limitClause := fmt.Sprintf("LIMIT $%d", argIdx)
+	args = append(args, limit)
+
 	offsetClause := ""
 	if f.Offset > 0 {
 		argIdx++
@@ -136,29 +469,74 @@ func (r *Repository) ListCatalog(ctx context.Context, f CatalogFilter) ([]Catalo
 		args = append(args, f.Offset)
 	}
 
-	query := fmt.Sprintf(`
-		SELECT
-			br.id, m.hf_id, m.model_family, m.parameter_count,
-			it.name, it.family, it.accelerator_type, it.accelerator_name,
-			it.accelerator_count, it.accelerator_memory_gib,
-			br.framework, br.framework_version, br.tensor_parallel_degree,
-			br.quantization, br.concurrency,
-			br.input_sequence_length, br.output_sequence_length,
-			br.completed_at,
-			bm.ttft_p50_ms, bm.ttft_p99_ms,
-			bm.e2e_latency_p50_ms, bm.e2e_latency_p99_ms,
-			bm.itl_p50_ms, bm.itl_p99_ms,
-			bm.throughput_per_request_tps, bm.throughput_aggregate_tps,
-			bm.requests_per_second,
-			bm.accelerator_utilization_pct, bm.accelerator_memory_peak_gib
-		FROM benchmark_runs br
-		JOIN models m ON br.model_id = m.id
-		JOIN instance_types it ON br.instance_type_id = it.id
-		JOIN benchmark_metrics bm ON bm.run_id = br.id
-		%s
-		%s
-		%s %s
-	`, where, orderBy, limitClause, offsetClause)
+	query := fmt.Sprintf(`SELECT %s %s %s %s %s %s`,
+		catalogSelectColumns, catalogSelectFrom, where, orderBy, limitClause, offsetClause)
+
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query catalog: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e, err := scanCatalogRow(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// catalogRows is the subset of pgx.Rows scanCatalogRow needs, so it works
+// against the *pgx.Rows returned by a live query without importing pgx
+// just for the type name here.
+type catalogRows interface {
+	Scan(dest ...any) error
+}
+
+// scanCatalogRow scans one row from a query over catalogSelectColumns into
+// a CatalogEntry. Shared by queryCatalogRows and ListCatalogStream so
+// ListCatalog's buffered path and its streaming counterpart can never drift
+// out of sync on column order.
+func scanCatalogRow(rows catalogRows) (CatalogEntry, error) {
+	var e CatalogEntry
+	err := rows.Scan(
+		&e.RunID, &e.ModelHfID, &e.ModelFamily, &e.ParameterCount,
+		&e.InstanceTypeName, &e.InstanceFamily, &e.AcceleratorType, &e.AcceleratorName,
+		&e.AcceleratorCount, &e.AcceleratorMemoryGiB,
+		&e.Framework, &e.FrameworkVersion, &e.TensorParallelDegree,
+		&e.Quantization, &e.Concurrency,
+		&e.InputSequenceLength, &e.OutputSequenceLength,
+		&e.CompletedAt, &e.GroupID, &e.TemplateIndex,
+		&e.TTFTP50Ms, &e.TTFTP99Ms,
+		&e.E2ELatencyP50Ms, &e.E2ELatencyP99Ms,
+		&e.ITLP50Ms, &e.ITLP99Ms,
+		&e.ThroughputPerRequestTPS, &e.ThroughputAggregateTPS,
+		&e.RequestsPerSecond,
+		&e.AcceleratorUtilizationPct, &e.AcceleratorMemoryPeakGiB,
+		&e.ServerTTFTP50Ms, &e.ServerTTFTP99Ms,
+		&e.ServerE2ELatencyP50Ms, &e.ServerE2ELatencyP99Ms,
+		&e.SMActivePeakPct, &e.PowerAvgW,
+	)
+	if err != nil {
+		return CatalogEntry{}, fmt.Errorf("scan catalog row: %w", err)
+	}
+	return e, nil
+}
+
+// queryCatalogRows runs query (a SELECT over catalogSelectColumns) and
+// scans every result row into a CatalogEntry slice. Factored out so
+// ListCatalog's sharded path, which omits the LIMIT/OFFSET clauses, can
+// share the scan logic with the normal path.
+func (r *Repository) queryCatalogRows(ctx context.Context, query string, args []any) ([]CatalogEntry, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
 
 	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
@@ -168,26 +546,94 @@ func (r *Repository) ListCatalog(ctx context.Context, f CatalogFilter) ([]Catalo
 
 	var entries []CatalogEntry
 	for rows.Next() {
-		var e CatalogEntry
-		err := rows.Scan(
-			&e.RunID, &e.ModelHfID, &e.ModelFamily, &e.ParameterCount,
-			&e.InstanceTypeName, &e.InstanceFamily, &e.AcceleratorType, &e.AcceleratorName,
-			&e.AcceleratorCount, &e.AcceleratorMemoryGiB,
-			&e.Framework, &e.FrameworkVersion, &e.TensorParallelDegree,
-			&e.Quantization, &e.Concurrency,
-			&e.InputSequenceLength, &e.OutputSequenceLength,
-			&e.CompletedAt,
-			&e.TTFTP50Ms, &e.TTFTP99Ms,
-			&e.E2ELatencyP50Ms, &e.E2ELatencyP99Ms,
-			&e.ITLP50Ms, &e.ITLP99Ms,
-			&e.ThroughputPerRequestTPS, &e.ThroughputAggregateTPS,
-			&e.RequestsPerSecond,
-			&e.AcceleratorUtilizationPct, &e.AcceleratorMemoryPeakGiB,
-		)
+		e, err := scanCatalogRow(rows)
 		if err != nil {
-			return nil, fmt.Errorf("scan catalog row: %w", err)
+			return nil, err
 		}
 		entries = append(entries, e)
 	}
 	return entries, rows.Err()
 }
+
+// queryCatalogRowsWithTotal is queryCatalogRows' counterpart for a query
+// whose SELECT list has one extra trailing count(*) OVER() column, used
+// by ListCatalog's paginated path to read the total matching row count
+// off the same rows it's already scanning.
+func (r *Repository) queryCatalogRowsWithTotal(ctx context.Context, query string, args []any) ([]CatalogEntry, int, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query catalog: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []CatalogEntry
+	var total int
+	for rows.Next() {
+		e, err := scanCatalogRow(&totalTrailingRow{rows, &total})
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// totalTrailingRow wraps a catalogRows whose SELECT list has one extra
+// trailing column, Scan-ing it into total so scanCatalogRow's own
+// Scan(dest...) call can stay ignorant of the extra column's presence.
+type totalTrailingRow struct {
+	rows  catalogRows
+	total *int
+}
+
+func (t *totalTrailingRow) Scan(dest ...any) error {
+	return t.rows.Scan(append(dest, t.total)...)
+}
+
+// CatalogEntrySortValue returns e's value for sortBy's column — the
+// client-side half of keyset pagination's cursor. A caller paging with
+// CatalogFilter.AfterRunID/AfterSortValue sets AfterSortValue to this
+// called on the last entry of the previous page, since JSON-over-HTTP has
+// no server-side cursor token to hand back and RunID alone doesn't
+// disambiguate ties on sortBy's column. sortBy must be a key ListCatalog
+// itself accepts (see allowedSortColumns); an empty sortBy mirrors the
+// column ListCatalog's keyset pagination falls back to when SortBy is
+// unset (br.completed_at).
+func CatalogEntrySortValue(e CatalogEntry, sortBy string) any {
+	switch sortBy {
+	case "model":
+		return e.ModelHfID
+	case "instance":
+		return e.InstanceTypeName
+	case "ttft_p50":
+		return e.TTFTP50Ms
+	case "ttft_p99":
+		return e.TTFTP99Ms
+	case "e2e_latency_p50":
+		return e.E2ELatencyP50Ms
+	case "e2e_latency_p99":
+		return e.E2ELatencyP99Ms
+	case "itl_p50":
+		return e.ITLP50Ms
+	case "itl_p99":
+		return e.ITLP99Ms
+	case "throughput_per_request":
+		return e.ThroughputPerRequestTPS
+	case "throughput_aggregate":
+		return e.ThroughputAggregateTPS
+	case "requests_per_second":
+		return e.RequestsPerSecond
+	case "accelerator_utilization":
+		return e.AcceleratorUtilizationPct
+	case "accelerator_memory_peak":
+		return e.AcceleratorMemoryPeakGiB
+	default:
+		return e.CompletedAt
+	}
+}
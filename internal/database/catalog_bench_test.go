@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// seedLargeCatalogRepo builds a MockRepo with n completed catalog runs
+// across a handful of models/instance types, for benchmarking ListCatalog
+// and ListCatalogSharded at a scale closer to a real multi-tenant
+// deployment than the small fixtures in catalog_test.go.
+func seedLargeCatalogRepo(b *testing.B, n int) *MockRepo {
+	b.Helper()
+	repo := NewMockRepo()
+
+	llama := "llama"
+	repo.SeedModel(&Model{ID: "m1", HfID: "meta-llama/Llama-3.1-8B", HfRevision: "abc", ModelFamily: &llama})
+	repo.SeedInstanceType(&InstanceType{ID: "i1", Name: "g5.xlarge", Family: "g5", AcceleratorType: "gpu", AcceleratorName: "A10G", AcceleratorCount: 1})
+
+	ctx := context.Background()
+	ttft := 25.0
+	completedAt := time.Now()
+	for i := 0; i < n; i++ {
+		run := &BenchmarkRun{
+			ModelID: "m1", InstanceTypeID: "i1",
+			Framework: "vllm", FrameworkVersion: "v0.6.0",
+			TensorParallelDegree: 1, Concurrency: 16,
+			InputSequenceLength: 512, OutputSequenceLength: 256,
+			DatasetName: "sharegpt", RunType: "catalog", Status: "pending",
+		}
+		id, err := repo.CreateBenchmarkRun(ctx, run)
+		if err != nil {
+			b.Fatalf("CreateBenchmarkRun: %v", err)
+		}
+		if err := repo.PersistMetrics(ctx, id, &BenchmarkMetrics{TTFTP50Ms: &ttft}, nil); err != nil {
+			b.Fatalf("PersistMetrics: %v", err)
+		}
+		run.CompletedAt = &completedAt
+	}
+	return repo
+}
+
+// BenchmarkListCatalogSharded compares ListCatalog's single-threaded scan
+// against ListCatalogSharded at increasing shard counts, to confirm the
+// sharded path scales with the number of workers rather than being
+// dominated by lock contention or merge overhead.
+func BenchmarkListCatalogSharded(b *testing.B) {
+	const n = 100_000
+	repo := seedLargeCatalogRepo(b, n)
+	ctx := context.Background()
+	f := CatalogFilter{Limit: 500}
+
+	b.Run("shards=1(unsharded)", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := repo.ListCatalog(ctx, f); err != nil {
+				b.Fatalf("ListCatalog: %v", err)
+			}
+		}
+	})
+
+	for _, shards := range []int{2, 4, 8, 16} {
+		shards := shards
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := ListCatalogSharded(ctx, repo, f, shards); err != nil {
+					b.Fatalf("ListCatalogSharded: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"testing"
+	"time"
 )
 
 // seedCatalogRepo creates a MockRepo pre-loaded with models, instance types,
@@ -45,7 +46,7 @@ func seedCatalogRepo() *MockRepo {
 			DatasetName: "sharegpt", RunType: "catalog", Status: "pending",
 		}
 		id, _ := repo.CreateBenchmarkRun(ctx, run)
-		repo.PersistMetrics(ctx, id, &BenchmarkMetrics{TTFTP50Ms: &ttft})
+		repo.PersistMetrics(ctx, id, &BenchmarkMetrics{TTFTP50Ms: &ttft}, nil)
 	}
 
 	// Also add an on_demand run (now included in catalog).
@@ -57,14 +58,14 @@ func seedCatalogRepo() *MockRepo {
 		DatasetName: "sharegpt", RunType: "on_demand", Status: "pending",
 	}
 	odID, _ := repo.CreateBenchmarkRun(ctx, odRun)
-	repo.PersistMetrics(ctx, odID, &BenchmarkMetrics{TTFTP50Ms: &ttft})
+	repo.PersistMetrics(ctx, odID, &BenchmarkMetrics{TTFTP50Ms: &ttft}, nil)
 
 	return repo
 }
 
 func TestListCatalog_AllEntries(t *testing.T) {
 	repo := seedCatalogRepo()
-	entries, err := repo.ListCatalog(context.Background(), CatalogFilter{})
+	entries, _, err := repo.ListCatalog(context.Background(), CatalogFilter{})
 	if err != nil {
 		t.Fatalf("ListCatalog: %v", err)
 	}
@@ -76,7 +77,7 @@ func TestListCatalog_AllEntries(t *testing.T) {
 
 func TestListCatalog_FilterByModel(t *testing.T) {
 	repo := seedCatalogRepo()
-	entries, err := repo.ListCatalog(context.Background(), CatalogFilter{
+	entries, _, err := repo.ListCatalog(context.Background(), CatalogFilter{
 		ModelHfID: "meta-llama/Llama-3.1-8B",
 	})
 	if err != nil {
@@ -95,7 +96,7 @@ func TestListCatalog_FilterByModel(t *testing.T) {
 
 func TestListCatalog_FilterByModelFamily(t *testing.T) {
 	repo := seedCatalogRepo()
-	entries, err := repo.ListCatalog(context.Background(), CatalogFilter{
+	entries, _, err := repo.ListCatalog(context.Background(), CatalogFilter{
 		ModelFamily: "mistral",
 	})
 	if err != nil {
@@ -108,7 +109,7 @@ func TestListCatalog_FilterByModelFamily(t *testing.T) {
 
 func TestListCatalog_FilterByInstanceFamily(t *testing.T) {
 	repo := seedCatalogRepo()
-	entries, err := repo.ListCatalog(context.Background(), CatalogFilter{
+	entries, _, err := repo.ListCatalog(context.Background(), CatalogFilter{
 		InstanceFamily: "p5",
 	})
 	if err != nil {
@@ -124,7 +125,7 @@ func TestListCatalog_FilterByInstanceFamily(t *testing.T) {
 
 func TestListCatalog_FilterByAcceleratorType(t *testing.T) {
 	repo := seedCatalogRepo()
-	entries, err := repo.ListCatalog(context.Background(), CatalogFilter{
+	entries, _, err := repo.ListCatalog(context.Background(), CatalogFilter{
 		AcceleratorType: "neuron",
 	})
 	if err != nil {
@@ -140,7 +141,7 @@ func TestListCatalog_FilterByAcceleratorType(t *testing.T) {
 
 func TestListCatalog_CombinedFilters(t *testing.T) {
 	repo := seedCatalogRepo()
-	entries, err := repo.ListCatalog(context.Background(), CatalogFilter{
+	entries, _, err := repo.ListCatalog(context.Background(), CatalogFilter{
 		ModelFamily:     "llama",
 		AcceleratorType: "gpu",
 	})
@@ -155,7 +156,7 @@ func TestListCatalog_CombinedFilters(t *testing.T) {
 
 func TestListCatalog_Limit(t *testing.T) {
 	repo := seedCatalogRepo()
-	entries, err := repo.ListCatalog(context.Background(), CatalogFilter{
+	entries, _, err := repo.ListCatalog(context.Background(), CatalogFilter{
 		Limit: 2,
 	})
 	if err != nil {
@@ -168,8 +169,8 @@ func TestListCatalog_Limit(t *testing.T) {
 
 func TestListCatalog_Offset(t *testing.T) {
 	repo := seedCatalogRepo()
-	all, _ := repo.ListCatalog(context.Background(), CatalogFilter{})
-	paged, err := repo.ListCatalog(context.Background(), CatalogFilter{Offset: 2, Limit: 100})
+	all, _, _ := repo.ListCatalog(context.Background(), CatalogFilter{})
+	paged, _, err := repo.ListCatalog(context.Background(), CatalogFilter{Offset: 2, Limit: 100})
 	if err != nil {
 		t.Fatalf("ListCatalog: %v", err)
 	}
@@ -178,9 +179,37 @@ func TestListCatalog_Offset(t *testing.T) {
 	}
 }
 
+// TestListCatalog_Total checks that the returned total reflects every row
+// matching the filter, not just the page Limit/Offset trims it down to —
+// including the edge case where Offset skips past every matching row.
+func TestListCatalog_Total(t *testing.T) {
+	repo := seedCatalogRepo()
+	ctx := context.Background()
+
+	entries, total, err := repo.ListCatalog(ctx, CatalogFilter{ModelFamily: "llama", Limit: 1})
+	if err != nil {
+		t.Fatalf("ListCatalog: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	// llama models: 8B on g5 (catalog) + 70B on p5 + 8B on g5 (on_demand) + 8B on inf2 = 4
+	if total != 4 {
+		t.Errorf("got total %d, want 4", total)
+	}
+
+	_, overrun, err := repo.ListCatalog(ctx, CatalogFilter{ModelFamily: "llama", Limit: 1, Offset: 100})
+	if err != nil {
+		t.Fatalf("ListCatalog offset overrun: %v", err)
+	}
+	if overrun != 4 {
+		t.Errorf("got total %d for offset overrun, want 4", overrun)
+	}
+}
+
 func TestListCatalog_MetricsPresent(t *testing.T) {
 	repo := seedCatalogRepo()
-	entries, err := repo.ListCatalog(context.Background(), CatalogFilter{Limit: 1})
+	entries, _, err := repo.ListCatalog(context.Background(), CatalogFilter{Limit: 1})
 	if err != nil {
 		t.Fatalf("ListCatalog: %v", err)
 	}
@@ -194,7 +223,7 @@ func TestListCatalog_MetricsPresent(t *testing.T) {
 
 func TestListCatalog_Empty(t *testing.T) {
 	repo := NewMockRepo()
-	entries, err := repo.ListCatalog(context.Background(), CatalogFilter{})
+	entries, _, err := repo.ListCatalog(context.Background(), CatalogFilter{})
 	if err != nil {
 		t.Fatalf("ListCatalog: %v", err)
 	}
@@ -202,3 +231,91 @@ func TestListCatalog_Empty(t *testing.T) {
 		t.Errorf("expected empty, got %d", len(entries))
 	}
 }
+
+func TestListCatalog_ShardSplitsRunsDisjointly(t *testing.T) {
+	repo := seedCatalogRepo()
+	ctx := context.Background()
+
+	const shardCount = 3
+	var union []CatalogEntry
+	seen := make(map[string]int)
+	for shardID := 0; shardID < shardCount; shardID++ {
+		entries, _, err := repo.ListCatalog(ctx, CatalogFilter{ShardID: shardID, ShardCount: shardCount})
+		if err != nil {
+			t.Fatalf("ListCatalog shard %d: %v", shardID, err)
+		}
+		for _, e := range entries {
+			seen[e.RunID]++
+		}
+		union = append(union, entries...)
+	}
+
+	all, _, err := repo.ListCatalog(ctx, CatalogFilter{Limit: 500})
+	if err != nil {
+		t.Fatalf("ListCatalog: %v", err)
+	}
+	if len(union) != len(all) {
+		t.Errorf("sharded union has %d entries, want %d", len(union), len(all))
+	}
+	for runID, count := range seen {
+		if count != 1 {
+			t.Errorf("run %s appeared in %d shards, want exactly 1", runID, count)
+		}
+	}
+}
+
+func TestListCatalogSharded_MatchesUnshardedAndPaginates(t *testing.T) {
+	repo := seedCatalogRepo()
+	ctx := context.Background()
+
+	unsharded, unshardedTotal, err := repo.ListCatalog(ctx, CatalogFilter{Limit: 500})
+	if err != nil {
+		t.Fatalf("ListCatalog: %v", err)
+	}
+	merged, total, err := ListCatalogSharded(ctx, repo, CatalogFilter{Limit: 500}, 4)
+	if err != nil {
+		t.Fatalf("ListCatalogSharded: %v", err)
+	}
+	if len(merged) != len(unsharded) {
+		t.Fatalf("got %d merged entries, want %d", len(merged), len(unsharded))
+	}
+	if total != unshardedTotal {
+		t.Fatalf("got total %d, want %d", total, unshardedTotal)
+	}
+
+	paged, pagedTotal, err := ListCatalogSharded(ctx, repo, CatalogFilter{Offset: 2, Limit: 2}, 4)
+	if err != nil {
+		t.Fatalf("ListCatalogSharded with pagination: %v", err)
+	}
+	if len(paged) != 2 {
+		t.Fatalf("got %d paged entries, want 2", len(paged))
+	}
+	if pagedTotal != unshardedTotal {
+		t.Fatalf("got paged total %d, want %d", pagedTotal, unshardedTotal)
+	}
+}
+
+func TestCatalogEntrySortValue(t *testing.T) {
+	ttft := 25.0
+	completed := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := CatalogEntry{
+		RunID:            "run-1",
+		ModelHfID:        "meta-llama/Llama-3.1-8B",
+		InstanceTypeName: "g5.xlarge",
+		TTFTP50Ms:        &ttft,
+		CompletedAt:      &completed,
+	}
+
+	if got := CatalogEntrySortValue(e, "model"); got != e.ModelHfID {
+		t.Errorf("model sort value = %v, want %v", got, e.ModelHfID)
+	}
+	if got := CatalogEntrySortValue(e, "instance"); got != e.InstanceTypeName {
+		t.Errorf("instance sort value = %v, want %v", got, e.InstanceTypeName)
+	}
+	if got := CatalogEntrySortValue(e, "ttft_p50"); got != e.TTFTP50Ms {
+		t.Errorf("ttft_p50 sort value = %v, want %v", got, e.TTFTP50Ms)
+	}
+	if got := CatalogEntrySortValue(e, ""); got != e.CompletedAt {
+		t.Errorf("default sort value = %v, want %v", got, e.CompletedAt)
+	}
+}
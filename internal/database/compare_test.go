@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCompareRuns_ReturnsEachRunsOwnSamples(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{Status: "running"})
+	if err != nil || len(items) == 0 {
+		t.Fatal("no running run to append samples to")
+	}
+	runA := items[0].ID
+
+	runB, err := repo.CreateBenchmarkRun(ctx, &BenchmarkRun{ModelID: "model-001", InstanceTypeID: "it-001", Status: "running"})
+	if err != nil {
+		t.Fatalf("CreateBenchmarkRun: %v", err)
+	}
+
+	if err := repo.AppendSamples(ctx, runA, []Sample{{TTFTMs: floatPtr(100), TokensOut: 1}}); err != nil {
+		t.Fatalf("AppendSamples(runA): %v", err)
+	}
+	if err := repo.AppendSamples(ctx, runB, []Sample{{TTFTMs: floatPtr(200), TokensOut: 1}, {TTFTMs: floatPtr(300), TokensOut: 1}}); err != nil {
+		t.Fatalf("AppendSamples(runB): %v", err)
+	}
+
+	control, treatment, err := repo.CompareRuns(ctx, runA, runB)
+	if err != nil {
+		t.Fatalf("CompareRuns: %v", err)
+	}
+	if len(control) != 1 {
+		t.Errorf("len(control) = %d, want 1", len(control))
+	}
+	if len(treatment) != 2 {
+		t.Errorf("len(treatment) = %d, want 2", len(treatment))
+	}
+}
@@ -0,0 +1,33 @@
+package database
+
+import "errors"
+
+// ErrRunNotFound is returned by CancelRun when runID does not exist.
+var ErrRunNotFound = errors.New("run not found")
+
+// ErrRunNotCancellable is returned by CancelRun when the run exists but is
+// no longer queued, pending or running, so the CAS that would have moved it to
+// "failed" lost the race or was never applicable. Handlers map this to a
+// 409 rather than treating it as an internal error.
+var ErrRunNotCancellable = errors.New("run not cancellable")
+
+// ErrRunNotQueued is returned by SetRunPriority when runID is not
+// currently waiting in the scheduler's admission queue — either it
+// doesn't exist or the scheduler has already dispatched it. DequeueRun of
+// a run that isn't queued is a no-op and returns nil.
+var ErrRunNotQueued = errors.New("run not queued")
+
+// ErrRunNotRequeueable is returned by Scheduler.Requeue when the run exists
+// but is still pending, running, or queued, so re-admitting it would hand
+// the same run ID to a second concurrent dispatch.
+var ErrRunNotRequeueable = errors.New("run not requeueable")
+
+// ErrPolicyNotFound is returned by UpdatePolicy when policyID does not
+// exist.
+var ErrPolicyNotFound = errors.New("policy not found")
+
+// ErrRunNotExtendable is returned by ExtendDeadline when the run exists but
+// is no longer queued, pending, or running, so the CAS that would have
+// pushed its DeadlineAt out lost the race against the reaper or a normal
+// completion. Handlers map this to a 409, the same as ErrRunNotCancellable.
+var ErrRunNotExtendable = errors.New("run deadline not extendable")
@@ -0,0 +1,2321 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/cronexpr"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// etcd key layout. Every record is stored as its JSON encoding under a
+// flat, prefix-scoped key so List* can use a single clientv3.WithPrefix
+// range read; secondary lookups (by HfID, by InstanceType ID) are plain
+// pointer records of the form "<secondary key> -> primary ID".
+const (
+	etcdPrefixModel        = "accelbench/models/"
+	etcdPrefixModelByHf    = "accelbench/models_by_hf/"
+	etcdPrefixInstType     = "accelbench/instance_types/"
+	etcdPrefixInstTypeByID = "accelbench/instance_types_by_id/"
+	etcdPrefixRun          = "accelbench/runs/"
+	etcdPrefixRunAlive     = "accelbench/runs_alive/"
+	etcdPrefixMetrics      = "accelbench/metrics/"
+	etcdPrefixSweep        = "accelbench/sweeps/"
+	etcdPrefixExperiment   = "accelbench/experiments/"
+	etcdPrefixPricing      = "accelbench/pricing/"
+	etcdPrefixSeq          = "accelbench/seq/"
+	etcdPrefixSamples      = "accelbench/samples/"
+	etcdPrefixAccelSamples = "accelbench/accelerator_samples/"
+	etcdPrefixGroup        = "accelbench/groups/"
+	etcdPrefixPolicy       = "accelbench/policies/"
+	etcdPrefixRunLog       = "accelbench/run_logs/"
+	etcdPrefixRunQueue     = "accelbench/run_queue/"
+	etcdPrefixRunCallback  = "accelbench/run_callbacks/"
+)
+
+// runAliveLeaseTTL bounds how long a run may stay "running" without a
+// heartbeat before EtcdRepo's reaper declares its controller pod dead and
+// fails it. startHeartbeat keeps the lease refreshed from the process that
+// moved the run to "running"; HeartbeatRun refreshes it explicitly.
+const runAliveLeaseTTL = 60 * time.Second
+
+// EtcdRepo is a Repo implementation backed by etcd v3, for HA deployments
+// that run more than one API replica against the same store. Unlike
+// BoltRepo and MockRepo, Watch is served directly from etcd's MVCC
+// history instead of an in-process Broker, so a watcher attached to one
+// replica sees events published through any other replica, and sinceRev
+// is a real etcd revision rather than a per-process counter.
+type EtcdRepo struct {
+	client *clientv3.Client
+
+	mu       sync.Mutex
+	aliveTTL map[string]context.CancelFunc // runID -> stop func for its KeepAlive goroutine
+
+	reaperCancel context.CancelFunc
+}
+
+// NewEtcdRepo dials etcd at the given endpoints and starts the background
+// reaper that fails "running" runs whose alive lease expired.
+func NewEtcdRepo(endpoints []string) (*EtcdRepo, error) {
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dial etcd: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &EtcdRepo{
+		client:       client,
+		aliveTTL:     make(map[string]context.CancelFunc),
+		reaperCancel: cancel,
+	}
+	go r.reapOrphanedRuns(ctx)
+	return r, nil
+}
+
+// Close stops the reaper and closes the underlying etcd client.
+func (e *EtcdRepo) Close() error {
+	e.reaperCancel()
+	return e.client.Close()
+}
+
+// Compile-time check that *EtcdRepo implements Repo, Snapshotter, and Importer.
+var (
+	_ Repo        = (*EtcdRepo)(nil)
+	_ Snapshotter = (*EtcdRepo)(nil)
+	_ Importer    = (*EtcdRepo)(nil)
+)
+
+// reapOrphanedRuns watches accelbench/runs_alive/ for delete events —
+// fired both when we delete a run's alive key ourselves on completion and
+// when its lease expires because nothing called HeartbeatRun in time —
+// and fails the run via the same CAS CancelRun uses. The CAS is a no-op
+// (ErrRunNotCancellable) if the run already reached a terminal status
+// through the normal path, so reacting to our own deletes is harmless.
+func (e *EtcdRepo) reapOrphanedRuns(ctx context.Context) {
+	wc := e.client.Watch(ctx, etcdPrefixRunAlive, clientv3.WithPrefix())
+	for resp := range wc {
+		for _, ev := range resp.Events {
+			if ev.Type != clientv3.EventTypeDelete {
+				continue
+			}
+			runID := strings.TrimPrefix(string(ev.Kv.Key), etcdPrefixRunAlive)
+			_ = e.CancelRun(ctx, runID)
+		}
+	}
+}
+
+func (e *EtcdRepo) nextSeq(ctx context.Context, kind string) (uint64, error) {
+	key := etcdPrefixSeq + kind
+	for {
+		getResp, err := e.client.Get(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		var (
+			cur uint64
+			mod int64
+		)
+		if len(getResp.Kvs) > 0 {
+			cur, err = strconv.ParseUint(string(getResp.Kvs[0].Value), 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			mod = getResp.Kvs[0].ModRevision
+		}
+		next := cur + 1
+		cmp := clientv3.Compare(clientv3.ModRevision(key), "=", mod)
+		put := clientv3.OpPut(key, strconv.FormatUint(next, 10))
+		txnResp, err := e.client.Txn(ctx).If(cmp).Then(put).Commit()
+		if err != nil {
+			return 0, err
+		}
+		if txnResp.Succeeded {
+			return next, nil
+		}
+		// Lost the race with another replica incrementing the same
+		// counter; retry against the new value.
+	}
+}
+
+func modelHfEtcdKey(hfID, hfRevision string) string {
+	return etcdPrefixModelByHf + hfID + "|" + hfRevision
+}
+
+func (e *EtcdRepo) GetModelByHfID(ctx context.Context, hfID, hfRevision string) (*Model, error) {
+	idResp, err := e.client.Get(ctx, modelHfEtcdKey(hfID, hfRevision))
+	if err != nil {
+		return nil, err
+	}
+	if len(idResp.Kvs) == 0 {
+		return nil, nil
+	}
+	modelResp, err := e.client.Get(ctx, etcdPrefixModel+string(idResp.Kvs[0].Value))
+	if err != nil {
+		return nil, err
+	}
+	if len(modelResp.Kvs) == 0 {
+		return nil, nil
+	}
+	var m Model
+	if err := json.Unmarshal(modelResp.Kvs[0].Value, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (e *EtcdRepo) EnsureModel(ctx context.Context, hfID, hfRevision string) (*Model, error) {
+	if existing, err := e.GetModelByHfID(ctx, hfID, hfRevision); err != nil || existing != nil {
+		return existing, err
+	}
+
+	n, err := e.nextSeq(ctx, "models")
+	if err != nil {
+		return nil, err
+	}
+	m := Model{ID: fmt.Sprintf("model-%08d", n), HfID: hfID, HfRevision: hfRevision, CreatedAt: time.Now()}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	hfKey := modelHfEtcdKey(hfID, hfRevision)
+	// Only the first Put wins if two replicas race to create the same
+	// model: whichever CreateRevision(hfKey) is 0 (i.e. the key doesn't
+	// exist yet) gets to write it.
+	txnResp, err := e.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(hfKey), "=", 0)).
+		Then(clientv3.OpPut(etcdPrefixModel+m.ID, string(data)), clientv3.OpPut(hfKey, m.ID)).
+		Commit()
+	if err != nil {
+		return nil, err
+	}
+	if !txnResp.Succeeded {
+		return e.GetModelByHfID(ctx, hfID, hfRevision)
+	}
+	return &m, nil
+}
+
+func (e *EtcdRepo) GetInstanceTypeByName(ctx context.Context, name string) (*InstanceType, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixInstType+name)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	var it InstanceType
+	if err := json.Unmarshal(resp.Kvs[0].Value, &it); err != nil {
+		return nil, err
+	}
+	return &it, nil
+}
+
+func (e *EtcdRepo) ListInstanceTypes(ctx context.Context) ([]InstanceType, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixInstType, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]InstanceType, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var it InstanceType
+		if err := json.Unmarshal(kv.Value, &it); err != nil {
+			return nil, err
+		}
+		result = append(result, it)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func (e *EtcdRepo) getRun(ctx context.Context, runID string) (*BenchmarkRun, int64, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixRun+runID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, 0, nil
+	}
+	var run BenchmarkRun
+	if err := json.Unmarshal(resp.Kvs[0].Value, &run); err != nil {
+		return nil, 0, err
+	}
+	return &run, resp.Kvs[0].ModRevision, nil
+}
+
+func (e *EtcdRepo) putRun(ctx context.Context, run *BenchmarkRun) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	_, err = e.client.Put(ctx, etcdPrefixRun+run.ID, string(data))
+	return err
+}
+
+func (e *EtcdRepo) CreateBenchmarkRun(ctx context.Context, run *BenchmarkRun) (string, error) {
+	n, err := e.nextSeq(ctx, "runs")
+	if err != nil {
+		return "", err
+	}
+	run.ID = fmt.Sprintf("run-%08d", n)
+	run.CreatedAt = time.Now()
+	if run.TimeoutSeconds > 0 {
+		d := run.CreatedAt.Add(time.Duration(run.TimeoutSeconds) * time.Second)
+		run.DeadlineAt = &d
+	}
+	if err := e.putRun(ctx, run); err != nil {
+		return "", err
+	}
+	return run.ID, nil
+}
+
+// UpdateRunStatus writes runID's new status. Transitioning into "running"
+// grants a lease-backed alive key and starts a KeepAlive goroutine so the
+// reaper can tell a live controller from a dead one; transitioning out of
+// "running" stops that goroutine and deletes the alive key immediately.
+func (e *EtcdRepo) UpdateRunStatus(ctx context.Context, runID, status string) error {
+	run, _, err := e.getRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.Status = status
+	now := time.Now()
+	var cbOp clientv3.Op
+	var needCbOp bool
+	switch status {
+	case "running":
+		run.StartedAt = &now
+	case "completed", "failed":
+		run.CompletedAt = &now
+		var m *BenchmarkMetrics
+		if metResp, err := e.client.Get(ctx, etcdPrefixMetrics+runID); err == nil && len(metResp.Kvs) > 0 {
+			m = &BenchmarkMetrics{}
+			if err := json.Unmarshal(metResp.Kvs[0].Value, m); err != nil {
+				return err
+			}
+		}
+		cbOp, needCbOp, err = e.runCallbackPutOp(ctx, run, status, m)
+		if err != nil {
+			return err
+		}
+	}
+	if needCbOp {
+		data, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		if _, err := e.client.Txn(ctx).Then(clientv3.OpPut(etcdPrefixRun+runID, string(data)), cbOp).Commit(); err != nil {
+			return err
+		}
+	} else if err := e.putRun(ctx, run); err != nil {
+		return err
+	}
+
+	if status == "running" {
+		if err := e.startHeartbeat(ctx, runID); err != nil {
+			return fmt.Errorf("start run lease: %w", err)
+		}
+	} else {
+		e.stopHeartbeat(ctx, runID)
+	}
+	return nil
+}
+
+// runCallbackPutOp builds the etcd Put op that enqueues a RunCallback for
+// run, for a caller to fold into the same Txn that's writing run's terminal
+// status so both commit atomically. Returns ok=false when run has no
+// CallbackURL, in which case the op is not needed.
+func (e *EtcdRepo) runCallbackPutOp(ctx context.Context, run *BenchmarkRun, status string, m *BenchmarkMetrics) (op clientv3.Op, ok bool, err error) {
+	if run.CallbackURL == "" {
+		return clientv3.Op{}, false, nil
+	}
+	n, err := e.nextSeq(ctx, "callback")
+	if err != nil {
+		return clientv3.Op{}, false, err
+	}
+	cb := RunCallback{
+		ID:            fmt.Sprintf("cb-%08d", n),
+		RunID:         run.ID,
+		URL:           run.CallbackURL,
+		Token:         run.CallbackToken,
+		Status:        status,
+		Summary:       summarizeMetrics(m),
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	data, err := json.Marshal(cb)
+	if err != nil {
+		return clientv3.Op{}, false, err
+	}
+	return clientv3.OpPut(etcdPrefixRunCallback+cb.ID, string(data)), true, nil
+}
+
+// UpdateRunFailureReason stamps runID's FailureReason.
+func (e *EtcdRepo) UpdateRunFailureReason(ctx context.Context, runID, reason string) error {
+	run, _, err := e.getRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.FailureReason = &reason
+	return e.putRun(ctx, run)
+}
+
+// startHeartbeat grants a runAliveLeaseTTL lease, attaches it to runID's
+// alive key, and keeps it refreshed for as long as this process considers
+// the run "running". While this is running, HeartbeatRun only adds one
+// more refresh of the same lease; it exists for callers (e.g. a future multi-process orchestrator) that want
+// to confirm liveness without re-running the whole KeepAlive dance.
+func (e *EtcdRepo) startHeartbeat(ctx context.Context, runID string) error {
+	lease, err := e.client.Grant(ctx, int64(runAliveLeaseTTL.Seconds()))
+	if err != nil {
+		return err
+	}
+	if _, err := e.client.Put(ctx, etcdPrefixRunAlive+runID, "1", clientv3.WithLease(lease.ID)); err != nil {
+		return err
+	}
+	keepAliveCtx, cancel := context.WithCancel(context.Background())
+	ch, err := e.client.KeepAlive(keepAliveCtx, lease.ID)
+	if err != nil {
+		cancel()
+		return err
+	}
+	go func() {
+		for range ch {
+			// drain KeepAlive responses; nothing to do with them
+		}
+	}()
+
+	e.mu.Lock()
+	if prev, ok := e.aliveTTL[runID]; ok {
+		prev()
+	}
+	e.aliveTTL[runID] = cancel
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *EtcdRepo) stopHeartbeat(ctx context.Context, runID string) {
+	e.mu.Lock()
+	cancel, ok := e.aliveTTL[runID]
+	delete(e.aliveTTL, runID)
+	e.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	_, _ = e.client.Delete(ctx, etcdPrefixRunAlive+runID)
+}
+
+// HeartbeatRun resets runID's alive lease, proving to the reaper that its
+// controller is still alive. Only meaningful while the run is "running";
+// EtcdRepo already keeps the lease alive on its own via startHeartbeat,
+// so callers only need this if they manage the lease across a process
+// restart.
+func (e *EtcdRepo) HeartbeatRun(ctx context.Context, runID string) error {
+	leaseResp, err := e.client.Get(ctx, etcdPrefixRunAlive+runID)
+	if err != nil {
+		return err
+	}
+	if len(leaseResp.Kvs) == 0 {
+		return e.startHeartbeat(ctx, runID)
+	}
+	_, err = e.client.KeepAliveOnce(ctx, clientv3.LeaseID(leaseResp.Kvs[0].Lease))
+	return err
+}
+
+// cancelAttempts bounds the optimistic-concurrency retry loop in
+// CancelRun; losing this many races in a row against concurrent cancels
+// or status updates for the same run is treated as real contention, not
+// transient loss.
+const cancelAttempts = 5
+
+// CancelRun atomically moves runID to "failed" if and only if it is still
+// "queued", "pending" or "running", dropping it from the admission queue in
+// the same write, using a kv.Txn compare-and-swap on the run
+// key's mod revision: read, check the status in Go, then write back only
+// if nothing else has touched the key since the read.
+func (e *EtcdRepo) CancelRun(ctx context.Context, runID string) error {
+	for attempt := 0; attempt < cancelAttempts; attempt++ {
+		run, modRev, err := e.getRun(ctx, runID)
+		if err != nil {
+			return err
+		}
+		if run == nil {
+			return ErrRunNotFound
+		}
+		if run.Status != "pending" && run.Status != "running" && run.Status != "queued" {
+			return ErrRunNotCancellable
+		}
+
+		run.Status = "failed"
+		now := time.Now()
+		run.CompletedAt = &now
+		data, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		cbOp, needCbOp, err := e.runCallbackPutOp(ctx, run, "failed", nil)
+		if err != nil {
+			return err
+		}
+
+		// A canceled run may still be sitting in the admission queue;
+		// drop it so the scheduler doesn't dispatch it after all.
+		ops := []clientv3.Op{clientv3.OpPut(etcdPrefixRun+runID, string(data)), clientv3.OpDelete(etcdPrefixRunQueue + runID)}
+		if needCbOp {
+			ops = append(ops, cbOp)
+		}
+		txnResp, err := e.client.Txn(ctx).
+			If(clientv3.Compare(clientv3.ModRevision(etcdPrefixRun+runID), "=", modRev)).
+			Then(ops...).
+			Commit()
+		if err != nil {
+			return err
+		}
+		if txnResp.Succeeded {
+			e.stopHeartbeat(ctx, runID)
+			return nil
+		}
+		// Lost the race to a concurrent writer; reread and retry.
+	}
+	return fmt.Errorf("cancel run %s: too much contention after %d attempts", runID, cancelAttempts)
+}
+
+// ExtendDeadline pushes runID's DeadlineAt out to newDeadline, conditional
+// on the run still being queued, pending, or running in one optimistic-
+// concurrency retry loop, matching CancelRun's pattern.
+func (e *EtcdRepo) ExtendDeadline(ctx context.Context, runID string, newDeadline time.Time) error {
+	for attempt := 0; attempt < cancelAttempts; attempt++ {
+		run, modRev, err := e.getRun(ctx, runID)
+		if err != nil {
+			return err
+		}
+		if run == nil {
+			return ErrRunNotFound
+		}
+		if run.Status != "queued" && run.Status != "pending" && run.Status != "running" {
+			return ErrRunNotExtendable
+		}
+
+		run.DeadlineAt = &newDeadline
+		data, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		txnResp, err := e.client.Txn(ctx).
+			If(clientv3.Compare(clientv3.ModRevision(etcdPrefixRun+runID), "=", modRev)).
+			Then(clientv3.OpPut(etcdPrefixRun+runID, string(data))).
+			Commit()
+		if err != nil {
+			return err
+		}
+		if txnResp.Succeeded {
+			return nil
+		}
+		// Lost the race to a concurrent writer; reread and retry.
+	}
+	return fmt.Errorf("extend deadline for run %s: too much contention after %d attempts", runID, cancelAttempts)
+}
+
+func (e *EtcdRepo) UpdateRunPluginDigests(ctx context.Context, runID string, digests map[string]string) error {
+	run, _, err := e.getRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.PluginDigests = digests
+	return e.putRun(ctx, run)
+}
+
+// UpdateRunPhase stamps runID's current lifecycle phase and, if resources
+// is non-nil, merges it into the run's persisted K8sResourceRefs — see
+// mergeK8sResourceRefs.
+func (e *EtcdRepo) UpdateRunPhase(ctx context.Context, runID, phase string, resources *K8sResourceRefs) error {
+	run, _, err := e.getRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.Phase = phase
+	if resources != nil {
+		if run.K8sResources == nil {
+			run.K8sResources = resources
+		} else {
+			run.K8sResources = mergeK8sResourceRefs(run.K8sResources, resources)
+		}
+	}
+	return e.putRun(ctx, run)
+}
+
+// SetTeardownPending marks whether runID's Kubernetes resources still need
+// tearing down.
+func (e *EtcdRepo) SetTeardownPending(ctx context.Context, runID string, pending bool) error {
+	run, _, err := e.getRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.TeardownPending = pending
+	return e.putRun(ctx, run)
+}
+
+// ListNonTerminalRuns returns every run whose status is "running", for
+// Resume to rebind to on startup after a crash or restart.
+func (e *EtcdRepo) ListNonTerminalRuns(ctx context.Context) ([]BenchmarkRun, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixRun, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	var runs []BenchmarkRun
+	for _, kv := range resp.Kvs {
+		var run BenchmarkRun
+		if err := json.Unmarshal(kv.Value, &run); err != nil {
+			return nil, err
+		}
+		if run.Status == "running" {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// ListExpiredRuns returns every queued, pending, or running run whose
+// DeadlineAt is non-nil and strictly before now.
+func (e *EtcdRepo) ListExpiredRuns(ctx context.Context, now time.Time) ([]BenchmarkRun, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixRun, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	var runs []BenchmarkRun
+	for _, kv := range resp.Kvs {
+		var run BenchmarkRun
+		if err := json.Unmarshal(kv.Value, &run); err != nil {
+			return nil, err
+		}
+		if (run.Status == "queued" || run.Status == "pending" || run.Status == "running") &&
+			run.DeadlineAt != nil && run.DeadlineAt.Before(now) {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+func (e *EtcdRepo) PersistMetrics(ctx context.Context, runID string, m *BenchmarkMetrics, samples []AcceleratorSample) error {
+	run, _, err := e.getRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return fmt.Errorf("run %s not found", runID)
+	}
+
+	n, err := e.nextSeq(ctx, "metrics")
+	if err != nil {
+		return err
+	}
+	m.ID = fmt.Sprintf("met-%08d", n)
+	m.RunID = runID
+	m.CreatedAt = time.Now()
+	mdata, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	run.Status = "completed"
+	now := time.Now()
+	run.CompletedAt = &now
+	rdata, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	ops := []clientv3.Op{
+		clientv3.OpPut(etcdPrefixMetrics+runID, string(mdata)),
+		clientv3.OpPut(etcdPrefixRun+runID, string(rdata)),
+	}
+	if len(samples) > 0 {
+		sdata, err := json.Marshal(samples)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixAccelSamples+runID, string(sdata)))
+	}
+	if cbOp, ok, err := e.runCallbackPutOp(ctx, run, "completed", m); err != nil {
+		return err
+	} else if ok {
+		ops = append(ops, cbOp)
+	}
+
+	if _, err := e.client.Txn(ctx).Then(ops...).Commit(); err != nil {
+		return err
+	}
+	e.stopHeartbeat(ctx, runID)
+	return nil
+}
+
+// GetAcceleratorSamples returns runID's raw accelerator samples, or nil if
+// none were recorded.
+func (e *EtcdRepo) GetAcceleratorSamples(ctx context.Context, runID string) ([]AcceleratorSample, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixAccelSamples+runID)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	var samples []AcceleratorSample
+	if err := json.Unmarshal(resp.Kvs[0].Value, &samples); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// Watch subscribes to lifecycle and metrics events for runID directly
+// from etcd's MVCC history, starting just after sinceRev — unlike
+// BoltRepo/MockRepo's in-process Broker, this sees events published by
+// any replica sharing this etcd cluster.
+func (e *EtcdRepo) Watch(ctx context.Context, runID string, sinceRev uint64) (<-chan RunEvent, error) {
+	out := make(chan RunEvent, eventBacklog)
+	opts := []clientv3.OpOption{clientv3.WithPrefix()}
+	if sinceRev > 0 {
+		opts = append(opts, clientv3.WithRev(int64(sinceRev)+1))
+	}
+	wc := e.client.Watch(ctx, etcdPrefixRun+runID, opts...)
+	wcLog := e.client.Watch(ctx, etcdPrefixRunLog+runID+"/", opts...)
+	wcMetrics := e.client.Watch(ctx, etcdPrefixMetrics+runID, opts...)
+
+	go func() {
+		defer close(out)
+		for {
+			var resp clientv3.WatchResponse
+			var kind int // 0 = run, 1 = log, 2 = metrics
+			select {
+			case resp = <-wc:
+			case resp = <-wcLog:
+				kind = 1
+			case resp = <-wcMetrics:
+				kind = 2
+			case <-ctx.Done():
+				return
+			}
+
+			for _, kvEvent := range resp.Events {
+				if kvEvent.Type != clientv3.EventTypePut {
+					continue
+				}
+
+				var ev RunEvent
+				switch kind {
+				case 1:
+					ev = RunEvent{
+						RunID:     runID,
+						Rev:       uint64(kvEvent.Kv.ModRevision),
+						LogLine:   string(kvEvent.Kv.Value),
+						CreatedAt: time.Now(),
+					}
+				case 2:
+					// UpsertMetrics writes this key without touching the run
+					// record, so unlike the "completed" case below there's no
+					// fresh run Put to read a status off of; look it up so a
+					// progressive-metrics watcher still gets one.
+					var m BenchmarkMetrics
+					if err := json.Unmarshal(kvEvent.Kv.Value, &m); err != nil {
+						continue
+					}
+					status := "running"
+					if run, _, err := e.getRun(ctx, runID); err == nil && run != nil {
+						status = run.Status
+					}
+					ev = RunEvent{
+						RunID:     runID,
+						Rev:       uint64(kvEvent.Kv.ModRevision),
+						Status:    status,
+						Metrics:   &m,
+						CreatedAt: time.Now(),
+					}
+				default:
+					var run BenchmarkRun
+					if err := json.Unmarshal(kvEvent.Kv.Value, &run); err != nil {
+						continue
+					}
+					ev = RunEvent{
+						RunID:     runID,
+						Rev:       uint64(kvEvent.Kv.ModRevision),
+						Status:    run.Status,
+						CreatedAt: time.Now(),
+					}
+					if run.Status == "completed" {
+						if metResp, err := e.client.Get(ctx, etcdPrefixMetrics+runID); err == nil && len(metResp.Kvs) > 0 {
+							var m BenchmarkMetrics
+							if json.Unmarshal(metResp.Kvs[0].Value, &m) == nil {
+								ev.Metrics = &m
+							}
+						}
+					}
+				}
+
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// UpsertMetrics writes a progressive BenchmarkMetrics snapshot for runID
+// while it's still running, overwriting any previous snapshot in place —
+// the etcd counterpart to Repository.UpsertMetrics. It never touches the
+// run record itself, so Watch picks it up via its own metrics-prefix
+// subscription instead of the run-record one PersistMetrics relies on.
+func (e *EtcdRepo) UpsertMetrics(ctx context.Context, runID string, m *BenchmarkMetrics) error {
+	if run, _, err := e.getRun(ctx, runID); err != nil {
+		return err
+	} else if run == nil {
+		return fmt.Errorf("run %s not found", runID)
+	}
+
+	m.RunID = runID
+	mdata, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if _, err := e.client.Put(ctx, etcdPrefixMetrics+runID, string(mdata)); err != nil {
+		return fmt.Errorf("upsert metrics: %w", err)
+	}
+	return nil
+}
+
+// PublishRunLog records an orchestrator log line for runID under its own
+// etcd watch prefix (separate from etcdPrefixRun so it can't collide with
+// the run-record scans List* does over that prefix), so Watch's merged
+// subscription picks it up alongside status and metrics updates, visible
+// to a watcher on any replica.
+func (e *EtcdRepo) PublishRunLog(ctx context.Context, runID, line string) error {
+	seq, err := e.nextSeq(ctx, "runlog:"+runID)
+	if err != nil {
+		return fmt.Errorf("next log seq: %w", err)
+	}
+	key := fmt.Sprintf("%s%s/%d", etcdPrefixRunLog, runID, seq)
+	if _, err := e.client.Put(ctx, key, line); err != nil {
+		return fmt.Errorf("put log line: %w", err)
+	}
+	return nil
+}
+
+// ListDueRunCallbacks returns every pending run_callbacks row whose
+// NextAttemptAt is at or before now, for the callbacks package's worker to
+// poll since EtcdRepo doesn't implement RunCallbackClaimer.
+func (e *EtcdRepo) ListDueRunCallbacks(ctx context.Context, now time.Time, limit int) ([]RunCallback, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixRunCallback, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	var due []RunCallback
+	for _, kv := range resp.Kvs {
+		var cb RunCallback
+		if err := json.Unmarshal(kv.Value, &cb); err != nil {
+			return nil, err
+		}
+		if cb.DeliveredAt == nil && !cb.NextAttemptAt.After(now) && cb.Attempts < MaxCallbackAttempts {
+			due = append(due, cb)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+// RecordRunCallbackResult records the outcome of a delivery attempt for id.
+func (e *EtcdRepo) RecordRunCallbackResult(ctx context.Context, id string, deliveryErr error, nextAttemptAt *time.Time) error {
+	resp, err := e.client.Get(ctx, etcdPrefixRunCallback+id)
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) == 0 {
+		return fmt.Errorf("run callback %s not found", id)
+	}
+	var cb RunCallback
+	if err := json.Unmarshal(resp.Kvs[0].Value, &cb); err != nil {
+		return err
+	}
+	if deliveryErr == nil {
+		now := time.Now()
+		cb.DeliveredAt = &now
+		cb.LastError = ""
+	} else {
+		cb.Attempts++
+		cb.LastError = deliveryErr.Error()
+		if nextAttemptAt != nil {
+			cb.NextAttemptAt = *nextAttemptAt
+		}
+	}
+	data, err := json.Marshal(cb)
+	if err != nil {
+		return err
+	}
+	_, err = e.client.Put(ctx, etcdPrefixRunCallback+id, string(data))
+	return err
+}
+
+func (e *EtcdRepo) GetBenchmarkRun(ctx context.Context, runID string) (*BenchmarkRun, error) {
+	run, _, err := e.getRun(ctx, runID)
+	return run, err
+}
+
+func (e *EtcdRepo) GetMetricsByRunID(ctx context.Context, runID string) (*BenchmarkMetrics, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixMetrics+runID)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	var m BenchmarkMetrics
+	if err := json.Unmarshal(resp.Kvs[0].Value, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (e *EtcdRepo) modelByID(ctx context.Context, id string) *Model {
+	resp, err := e.client.Get(ctx, etcdPrefixModel+id)
+	if err != nil || len(resp.Kvs) == 0 {
+		return nil
+	}
+	var m Model
+	if json.Unmarshal(resp.Kvs[0].Value, &m) != nil {
+		return nil
+	}
+	return &m
+}
+
+func (e *EtcdRepo) instanceTypeByID(ctx context.Context, id string) *InstanceType {
+	nameResp, err := e.client.Get(ctx, etcdPrefixInstTypeByID+id)
+	if err != nil || len(nameResp.Kvs) == 0 {
+		return nil
+	}
+	itResp, err := e.client.Get(ctx, etcdPrefixInstType+string(nameResp.Kvs[0].Value))
+	if err != nil || len(itResp.Kvs) == 0 {
+		return nil
+	}
+	var it InstanceType
+	if json.Unmarshal(itResp.Kvs[0].Value, &it) != nil {
+		return nil
+	}
+	return &it
+}
+
+// ListRuns returns benchmark runs matching f, along with the total number
+// of runs matching f (ignoring f.Limit/f.Offset).
+func (e *EtcdRepo) ListRuns(ctx context.Context, f RunFilter) ([]RunListItem, int, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixRun, clientv3.WithPrefix())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var items []RunListItem
+	for _, kv := range resp.Kvs {
+		var run BenchmarkRun
+		if err := json.Unmarshal(kv.Value, &run); err != nil {
+			return nil, 0, err
+		}
+		if !runStatusMatches(run.Status, f) {
+			continue
+		}
+		if !shardMatch(run.ID, f.ShardID, f.ShardCount) {
+			continue
+		}
+
+		var modelHfID string
+		if model := e.modelByID(ctx, run.ModelID); model != nil {
+			modelHfID = model.HfID
+		}
+		if f.ModelID != "" && !strings.Contains(strings.ToLower(modelHfID), strings.ToLower(f.ModelID)) {
+			continue
+		}
+
+		var instName, instFamily, instAccelType string
+		if it := e.instanceTypeByID(ctx, run.InstanceTypeID); it != nil {
+			instName, instFamily, instAccelType = it.Name, it.Family, it.AcceleratorType
+		}
+		if !runMatchesFilter(&run, instFamily, instAccelType, f) {
+			continue
+		}
+
+		items = append(items, RunListItem{
+			ID:               run.ID,
+			ModelHfID:        modelHfID,
+			InstanceTypeName: instName,
+			Framework:        run.Framework,
+			RunType:          run.RunType,
+			Status:           run.Status,
+			CreatedAt:        run.CreatedAt,
+			StartedAt:        run.StartedAt,
+			CompletedAt:      run.CompletedAt,
+		})
+	}
+
+	items, total := paginateRunItems(items, f)
+	return items, total, nil
+}
+
+func (e *EtcdRepo) DeleteRun(ctx context.Context, runID string) error {
+	e.stopHeartbeat(ctx, runID)
+	_, err := e.client.Txn(ctx).Then(
+		clientv3.OpDelete(etcdPrefixMetrics+runID),
+		clientv3.OpDelete(etcdPrefixRun+runID),
+	).Commit()
+	return err
+}
+
+// ListCatalog returns catalog entries matching f, along with the total
+// number of entries matching f (ignoring f.Limit/f.Offset).
+func (e *EtcdRepo) ListCatalog(ctx context.Context, f CatalogFilter) ([]CatalogEntry, int, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixRun, clientv3.WithPrefix())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var entries []CatalogEntry
+	for _, kv := range resp.Kvs {
+		var run BenchmarkRun
+		if err := json.Unmarshal(kv.Value, &run); err != nil {
+			return nil, 0, err
+		}
+		if run.Status != "completed" || run.Superseded || run.RunType != "catalog" {
+			continue
+		}
+		if !shardMatch(run.ID, f.ShardID, f.ShardCount) {
+			continue
+		}
+
+		metResp, err := e.client.Get(ctx, etcdPrefixMetrics+run.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(metResp.Kvs) == 0 {
+			continue
+		}
+		var met BenchmarkMetrics
+		if err := json.Unmarshal(metResp.Kvs[0].Value, &met); err != nil {
+			return nil, 0, err
+		}
+
+		model := e.modelByID(ctx, run.ModelID)
+		if model == nil {
+			continue
+		}
+		inst := e.instanceTypeByID(ctx, run.InstanceTypeID)
+		if inst == nil {
+			continue
+		}
+
+		if f.ModelHfID != "" && model.HfID != f.ModelHfID {
+			continue
+		}
+		if f.ModelFamily != "" && (model.ModelFamily == nil || *model.ModelFamily != f.ModelFamily) {
+			continue
+		}
+		if f.InstanceFamily != "" && inst.Family != f.InstanceFamily {
+			continue
+		}
+		if f.AcceleratorType != "" && inst.AcceleratorType != f.AcceleratorType {
+			continue
+		}
+		if f.GroupID != "" && (run.GroupID == nil || *run.GroupID != f.GroupID) {
+			continue
+		}
+
+		entries = append(entries, CatalogEntry{
+			RunID:                     run.ID,
+			ModelHfID:                 model.HfID,
+			ModelFamily:               model.ModelFamily,
+			ParameterCount:            model.ParameterCount,
+			InstanceTypeName:          inst.Name,
+			InstanceFamily:            inst.Family,
+			AcceleratorType:           inst.AcceleratorType,
+			AcceleratorName:           inst.AcceleratorName,
+			AcceleratorCount:          inst.AcceleratorCount,
+			AcceleratorMemoryGiB:      inst.AcceleratorMemoryGiB,
+			Framework:                 run.Framework,
+			FrameworkVersion:          run.FrameworkVersion,
+			TensorParallelDegree:      run.TensorParallelDegree,
+			Quantization:              run.Quantization,
+			Concurrency:               run.Concurrency,
+			InputSequenceLength:       run.InputSequenceLength,
+			OutputSequenceLength:      run.OutputSequenceLength,
+			CompletedAt:               run.CompletedAt,
+			GroupID:                   run.GroupID,
+			TemplateIndex:             run.TemplateIndex,
+			TTFTP50Ms:                 met.TTFTP50Ms,
+			TTFTP99Ms:                 met.TTFTP99Ms,
+			E2ELatencyP50Ms:           met.E2ELatencyP50Ms,
+			E2ELatencyP99Ms:           met.E2ELatencyP99Ms,
+			ITLP50Ms:                  met.ITLP50Ms,
+			ITLP99Ms:                  met.ITLP99Ms,
+			ThroughputPerRequestTPS:   met.ThroughputPerRequestTPS,
+			ThroughputAggregateTPS:    met.ThroughputAggregateTPS,
+			RequestsPerSecond:         met.RequestsPerSecond,
+			AcceleratorUtilizationPct: met.AcceleratorUtilizationPct,
+			AcceleratorMemoryPeakGiB:  met.AcceleratorMemoryPeakGiB,
+			ServerTTFTP50Ms:           met.ServerTTFTP50Ms,
+			ServerTTFTP99Ms:           met.ServerTTFTP99Ms,
+			ServerE2ELatencyP50Ms:     met.ServerE2ELatencyP50Ms,
+			ServerE2ELatencyP99Ms:     met.ServerE2ELatencyP99Ms,
+			SMActivePeakPct:           met.SMActivePeakPct,
+		})
+	}
+
+	if f.LatestPerGroup {
+		entries = filterLatestPerGroup(entries)
+	}
+
+	// A sharded call returns its whole shard unpaginated; the caller
+	// applies Limit/Offset once after merging every shard's results.
+	if f.ShardCount > 1 {
+		return entries, len(entries), nil
+	}
+
+	total := len(entries)
+
+	limit := 100
+	if f.Limit > 0 && f.Limit <= 500 {
+		limit = f.Limit
+	}
+	if f.Offset > 0 && f.Offset < len(entries) {
+		entries = entries[f.Offset:]
+	} else if f.Offset >= len(entries) {
+		return nil, total, nil
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, total, nil
+}
+
+func pricingEtcdKey(instanceTypeID, cloudProvider, region, effectiveDate string) string {
+	return etcdPrefixPricing + instanceTypeID + "|" + cloudProvider + "|" + region + "|" + effectiveDate
+}
+
+func (e *EtcdRepo) UpsertPricing(ctx context.Context, p *Pricing) error {
+	if p.ID == "" {
+		n, err := e.nextSeq(ctx, "pricing")
+		if err != nil {
+			return err
+		}
+		p.ID = fmt.Sprintf("price-%08d", n)
+	}
+	p.CreatedAt = time.Now()
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	_, err = e.client.Put(ctx, pricingEtcdKey(p.InstanceTypeID, p.CloudProvider, p.Region, p.EffectiveDate), string(data))
+	return err
+}
+
+func (e *EtcdRepo) ListPricing(ctx context.Context, region string) ([]PricingRow, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixPricing, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]Pricing) // keyed by instance_type_id|cloud_provider
+	for _, kv := range resp.Kvs {
+		var p Pricing
+		if err := json.Unmarshal(kv.Value, &p); err != nil {
+			return nil, err
+		}
+		if p.Region != region {
+			continue
+		}
+		key := p.InstanceTypeID + "|" + p.CloudProvider
+		if cur, ok := latest[key]; !ok || p.EffectiveDate > cur.EffectiveDate {
+			latest[key] = p
+		}
+	}
+
+	var result []PricingRow
+	for _, p := range latest {
+		inst := e.instanceTypeByID(ctx, p.InstanceTypeID)
+		if inst == nil {
+			continue
+		}
+		result = append(result, PricingRow{
+			InstanceTypeName:     inst.Name,
+			CloudProvider:        p.CloudProvider,
+			OnDemandHourlyUSD:    p.OnDemandHourlyUSD,
+			Reserved1YrHourlyUSD: p.Reserved1YrHourlyUSD,
+			Reserved3YrHourlyUSD: p.Reserved3YrHourlyUSD,
+			SpotHourlyUSD:        p.SpotHourlyUSD,
+			EffectiveDate:        p.EffectiveDate,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].InstanceTypeName != result[j].InstanceTypeName {
+			return result[i].InstanceTypeName < result[j].InstanceTypeName
+		}
+		return result[i].CloudProvider < result[j].CloudProvider
+	})
+	return result, nil
+}
+
+func (e *EtcdRepo) ListPricingAt(ctx context.Context, region string, asOf time.Time) ([]PricingRow, error) {
+	asOfDate := asOf.Format("2006-01-02")
+	resp, err := e.client.Get(ctx, etcdPrefixPricing, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]Pricing) // keyed by instance_type_id|cloud_provider
+	for _, kv := range resp.Kvs {
+		var p Pricing
+		if err := json.Unmarshal(kv.Value, &p); err != nil {
+			return nil, err
+		}
+		if p.Region != region || p.EffectiveDate > asOfDate {
+			continue
+		}
+		key := p.InstanceTypeID + "|" + p.CloudProvider
+		if cur, ok := latest[key]; !ok || p.EffectiveDate > cur.EffectiveDate {
+			latest[key] = p
+		}
+	}
+
+	var result []PricingRow
+	for _, p := range latest {
+		inst := e.instanceTypeByID(ctx, p.InstanceTypeID)
+		if inst == nil {
+			continue
+		}
+		result = append(result, PricingRow{
+			InstanceTypeName:     inst.Name,
+			CloudProvider:        p.CloudProvider,
+			OnDemandHourlyUSD:    p.OnDemandHourlyUSD,
+			Reserved1YrHourlyUSD: p.Reserved1YrHourlyUSD,
+			Reserved3YrHourlyUSD: p.Reserved3YrHourlyUSD,
+			SpotHourlyUSD:        p.SpotHourlyUSD,
+			EffectiveDate:        p.EffectiveDate,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].InstanceTypeName != result[j].InstanceTypeName {
+			return result[i].InstanceTypeName < result[j].InstanceTypeName
+		}
+		return result[i].CloudProvider < result[j].CloudProvider
+	})
+	return result, nil
+}
+
+func (e *EtcdRepo) PricingHistory(ctx context.Context, instanceTypeID, region string, since time.Time) ([]PricingRow, error) {
+	sinceDate := since.Format("2006-01-02")
+	inst := e.instanceTypeByID(ctx, instanceTypeID)
+	if inst == nil {
+		return nil, nil
+	}
+	resp, err := e.client.Get(ctx, etcdPrefixPricing, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []PricingRow
+	for _, kv := range resp.Kvs {
+		var p Pricing
+		if err := json.Unmarshal(kv.Value, &p); err != nil {
+			return nil, err
+		}
+		if p.InstanceTypeID != instanceTypeID || p.Region != region || p.EffectiveDate < sinceDate {
+			continue
+		}
+		result = append(result, PricingRow{
+			InstanceTypeName:     inst.Name,
+			CloudProvider:        p.CloudProvider,
+			OnDemandHourlyUSD:    p.OnDemandHourlyUSD,
+			Reserved1YrHourlyUSD: p.Reserved1YrHourlyUSD,
+			Reserved3YrHourlyUSD: p.Reserved3YrHourlyUSD,
+			SpotHourlyUSD:        p.SpotHourlyUSD,
+			EffectiveDate:        p.EffectiveDate,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].EffectiveDate != result[j].EffectiveDate {
+			return result[i].EffectiveDate < result[j].EffectiveDate
+		}
+		return result[i].CloudProvider < result[j].CloudProvider
+	})
+	return result, nil
+}
+
+func (e *EtcdRepo) CreateSweep(ctx context.Context, runs []*BenchmarkRun) (string, []string, error) {
+	n, err := e.nextSeq(ctx, "sweeps")
+	if err != nil {
+		return "", nil, err
+	}
+	sweepID := fmt.Sprintf("sweep-%08d", n)
+	sweep := &Sweep{ID: sweepID, CreatedAt: time.Now()}
+	sdata, err := json.Marshal(sweep)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ops := []clientv3.Op{clientv3.OpPut(etcdPrefixSweep+sweepID, string(sdata))}
+	runIDs := make([]string, 0, len(runs))
+	for _, run := range runs {
+		rn, err := e.nextSeq(ctx, "runs")
+		if err != nil {
+			return "", nil, err
+		}
+		run.ID = fmt.Sprintf("run-%08d", rn)
+		run.SweepID = &sweepID
+		run.CreatedAt = time.Now()
+		rdata, err := json.Marshal(run)
+		if err != nil {
+			return "", nil, err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixRun+run.ID, string(rdata)))
+		runIDs = append(runIDs, run.ID)
+	}
+
+	if _, err := e.client.Txn(ctx).Then(ops...).Commit(); err != nil {
+		return "", nil, err
+	}
+	return sweepID, runIDs, nil
+}
+
+func (e *EtcdRepo) GetSweep(ctx context.Context, sweepID string) (*Sweep, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixSweep+sweepID)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	var s Sweep
+	if err := json.Unmarshal(resp.Kvs[0].Value, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (e *EtcdRepo) ListSweepRuns(ctx context.Context, sweepID string) ([]SweepRunDetail, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixRun, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	var details []SweepRunDetail
+	for _, kv := range resp.Kvs {
+		var run BenchmarkRun
+		if err := json.Unmarshal(kv.Value, &run); err != nil {
+			return nil, err
+		}
+		if run.SweepID == nil || *run.SweepID != sweepID {
+			continue
+		}
+
+		var modelHfID, instName string
+		if model := e.modelByID(ctx, run.ModelID); model != nil {
+			modelHfID = model.HfID
+		}
+		if it := e.instanceTypeByID(ctx, run.InstanceTypeID); it != nil {
+			instName = it.Name
+		}
+
+		var metrics *BenchmarkMetrics
+		metResp, err := e.client.Get(ctx, etcdPrefixMetrics+run.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(metResp.Kvs) > 0 {
+			var m BenchmarkMetrics
+			if err := json.Unmarshal(metResp.Kvs[0].Value, &m); err != nil {
+				return nil, err
+			}
+			metrics = &m
+		}
+
+		details = append(details, SweepRunDetail{
+			RunListItem: RunListItem{
+				ID:               run.ID,
+				ModelHfID:        modelHfID,
+				InstanceTypeName: instName,
+				Framework:        run.Framework,
+				RunType:          run.RunType,
+				Status:           run.Status,
+				CreatedAt:        run.CreatedAt,
+				StartedAt:        run.StartedAt,
+				CompletedAt:      run.CompletedAt,
+			},
+			Concurrency:          run.Concurrency,
+			TensorParallelDegree: run.TensorParallelDegree,
+			InputSequenceLength:  run.InputSequenceLength,
+			OutputSequenceLength: run.OutputSequenceLength,
+			Quantization:         run.Quantization,
+			Metrics:              metrics,
+		})
+	}
+	sort.Slice(details, func(i, j int) bool { return details[i].CreatedAt.Before(details[j].CreatedAt) })
+	return details, nil
+}
+
+func (e *EtcdRepo) CreateExperiment(ctx context.Context, runs []*BenchmarkRun) (string, []string, error) {
+	n, err := e.nextSeq(ctx, "experiments")
+	if err != nil {
+		return "", nil, err
+	}
+	experimentID := fmt.Sprintf("experiment-%08d", n)
+	exp := &Experiment{ID: experimentID, CreatedAt: time.Now()}
+	edata, err := json.Marshal(exp)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ops := []clientv3.Op{clientv3.OpPut(etcdPrefixExperiment+experimentID, string(edata))}
+	runIDs := make([]string, 0, len(runs))
+	for _, run := range runs {
+		rn, err := e.nextSeq(ctx, "runs")
+		if err != nil {
+			return "", nil, err
+		}
+		run.ID = fmt.Sprintf("run-%08d", rn)
+		run.ExperimentID = &experimentID
+		run.CreatedAt = time.Now()
+		rdata, err := json.Marshal(run)
+		if err != nil {
+			return "", nil, err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixRun+run.ID, string(rdata)))
+		runIDs = append(runIDs, run.ID)
+	}
+
+	if _, err := e.client.Txn(ctx).Then(ops...).Commit(); err != nil {
+		return "", nil, err
+	}
+	return experimentID, runIDs, nil
+}
+
+func (e *EtcdRepo) GetExperiment(ctx context.Context, experimentID string) (*Experiment, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixExperiment+experimentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	var exp Experiment
+	if err := json.Unmarshal(resp.Kvs[0].Value, &exp); err != nil {
+		return nil, err
+	}
+	return &exp, nil
+}
+
+func (e *EtcdRepo) ListRunsByExperiment(ctx context.Context, experimentID string) ([]ExperimentRunDetail, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixRun, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	var details []ExperimentRunDetail
+	for _, kv := range resp.Kvs {
+		var run BenchmarkRun
+		if err := json.Unmarshal(kv.Value, &run); err != nil {
+			return nil, err
+		}
+		if run.ExperimentID == nil || *run.ExperimentID != experimentID {
+			continue
+		}
+
+		var modelHfID, instName string
+		if model := e.modelByID(ctx, run.ModelID); model != nil {
+			modelHfID = model.HfID
+		}
+		if it := e.instanceTypeByID(ctx, run.InstanceTypeID); it != nil {
+			instName = it.Name
+		}
+
+		details = append(details, ExperimentRunDetail{
+			RunListItem: RunListItem{
+				ID:               run.ID,
+				ModelHfID:        modelHfID,
+				InstanceTypeName: instName,
+				Framework:        run.Framework,
+				RunType:          run.RunType,
+				Status:           run.Status,
+				CreatedAt:        run.CreatedAt,
+				StartedAt:        run.StartedAt,
+				CompletedAt:      run.CompletedAt,
+			},
+			Arm: run.Arm,
+		})
+	}
+	sort.Slice(details, func(i, j int) bool { return details[i].CreatedAt.Before(details[j].CreatedAt) })
+	return details, nil
+}
+
+// CreateBenchmarkGroup persists a new BenchmarkGroup definition.
+func (e *EtcdRepo) CreateBenchmarkGroup(ctx context.Context, group *BenchmarkGroup) (string, error) {
+	n, err := e.nextSeq(ctx, "groups")
+	if err != nil {
+		return "", err
+	}
+	group.ID = fmt.Sprintf("group-%08d", n)
+	group.CreatedAt = time.Now()
+	data, err := json.Marshal(group)
+	if err != nil {
+		return "", err
+	}
+	if _, err := e.client.Put(ctx, etcdPrefixGroup+group.ID, string(data)); err != nil {
+		return "", err
+	}
+	return group.ID, nil
+}
+
+// ListBenchmarkGroups returns every persisted BenchmarkGroup.
+func (e *EtcdRepo) ListBenchmarkGroups(ctx context.Context) ([]BenchmarkGroup, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixGroup, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	var groups []BenchmarkGroup
+	for _, kv := range resp.Kvs {
+		var g BenchmarkGroup
+		if err := json.Unmarshal(kv.Value, &g); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].CreatedAt.Before(groups[j].CreatedAt) })
+	return groups, nil
+}
+
+// UpdateGroupLastRun stamps groupID's last_run_at.
+func (e *EtcdRepo) UpdateGroupLastRun(ctx context.Context, groupID string, lastRun time.Time) error {
+	resp, err := e.client.Get(ctx, etcdPrefixGroup+groupID)
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) == 0 {
+		return fmt.Errorf("benchmark group %s not found", groupID)
+	}
+	var g BenchmarkGroup
+	if err := json.Unmarshal(resp.Kvs[0].Value, &g); err != nil {
+		return err
+	}
+	g.LastRunAt = &lastRun
+	data, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	_, err = e.client.Put(ctx, etcdPrefixGroup+groupID, string(data))
+	return err
+}
+
+// ListRunsByGroup returns every run tagged with groupID, newest first.
+func (e *EtcdRepo) ListRunsByGroup(ctx context.Context, groupID string) ([]RunListItem, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixRun, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	var items []RunListItem
+	for _, kv := range resp.Kvs {
+		var run BenchmarkRun
+		if err := json.Unmarshal(kv.Value, &run); err != nil {
+			return nil, err
+		}
+		if run.GroupID == nil || *run.GroupID != groupID {
+			continue
+		}
+
+		var modelHfID, instName string
+		if model := e.modelByID(ctx, run.ModelID); model != nil {
+			modelHfID = model.HfID
+		}
+		if it := e.instanceTypeByID(ctx, run.InstanceTypeID); it != nil {
+			instName = it.Name
+		}
+
+		items = append(items, RunListItem{
+			ID:               run.ID,
+			ModelHfID:        modelHfID,
+			InstanceTypeName: instName,
+			Framework:        run.Framework,
+			RunType:          run.RunType,
+			Status:           run.Status,
+			CreatedAt:        run.CreatedAt,
+			StartedAt:        run.StartedAt,
+			CompletedAt:      run.CompletedAt,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt.After(items[j].CreatedAt) })
+	return items, nil
+}
+
+// CreatePolicy persists a new BenchmarkPolicy definition.
+func (e *EtcdRepo) CreatePolicy(ctx context.Context, policy *BenchmarkPolicy) (string, error) {
+	if policy.Trigger == "scheduled" {
+		next, err := cronexpr.Next(policy.CronExpr, time.Now().UTC())
+		if err != nil {
+			return "", fmt.Errorf("compute next run: %w", err)
+		}
+		policy.NextRunAt = &next
+	}
+
+	n, err := e.nextSeq(ctx, "policies")
+	if err != nil {
+		return "", err
+	}
+	policy.ID = fmt.Sprintf("policy-%08d", n)
+	policy.CreatedAt = time.Now()
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+	if _, err := e.client.Put(ctx, etcdPrefixPolicy+policy.ID, string(data)); err != nil {
+		return "", err
+	}
+	return policy.ID, nil
+}
+
+// UpdatePolicy replaces policyID's mutable fields.
+func (e *EtcdRepo) UpdatePolicy(ctx context.Context, policyID string, policy *BenchmarkPolicy) error {
+	resp, err := e.client.Get(ctx, etcdPrefixPolicy+policyID)
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) == 0 {
+		return ErrPolicyNotFound
+	}
+	var existing BenchmarkPolicy
+	if err := json.Unmarshal(resp.Kvs[0].Value, &existing); err != nil {
+		return err
+	}
+
+	if policy.Trigger == "scheduled" {
+		next, err := cronexpr.Next(policy.CronExpr, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("compute next run: %w", err)
+		}
+		policy.NextRunAt = &next
+	} else {
+		policy.NextRunAt = nil
+	}
+	policy.ID = existing.ID
+	policy.CreatedAt = existing.CreatedAt
+	policy.LastRunAt = existing.LastRunAt
+
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	_, err = e.client.Put(ctx, etcdPrefixPolicy+policyID, string(data))
+	return err
+}
+
+// ListPolicies returns every persisted BenchmarkPolicy.
+func (e *EtcdRepo) ListPolicies(ctx context.Context) ([]BenchmarkPolicy, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixPolicy, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	var policies []BenchmarkPolicy
+	for _, kv := range resp.Kvs {
+		var p BenchmarkPolicy
+		if err := json.Unmarshal(kv.Value, &p); err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].CreatedAt.Before(policies[j].CreatedAt) })
+	return policies, nil
+}
+
+// DeletePolicy removes a BenchmarkPolicy.
+func (e *EtcdRepo) DeletePolicy(ctx context.Context, policyID string) error {
+	_, err := e.client.Delete(ctx, etcdPrefixPolicy+policyID)
+	return err
+}
+
+// ListRunsByParent returns every probe run created under parentRunID,
+// newest first, each with the concurrency it probed and the metrics it
+// produced.
+func (e *EtcdRepo) ListRunsByParent(ctx context.Context, parentRunID string) ([]SLOSearchRunDetail, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixRun, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	var details []SLOSearchRunDetail
+	for _, kv := range resp.Kvs {
+		var run BenchmarkRun
+		if err := json.Unmarshal(kv.Value, &run); err != nil {
+			return nil, err
+		}
+		if run.ParentRunID == nil || *run.ParentRunID != parentRunID {
+			continue
+		}
+
+		var modelHfID, instName string
+		if model := e.modelByID(ctx, run.ModelID); model != nil {
+			modelHfID = model.HfID
+		}
+		if it := e.instanceTypeByID(ctx, run.InstanceTypeID); it != nil {
+			instName = it.Name
+		}
+
+		var metrics *BenchmarkMetrics
+		metResp, err := e.client.Get(ctx, etcdPrefixMetrics+run.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(metResp.Kvs) > 0 {
+			var m BenchmarkMetrics
+			if err := json.Unmarshal(metResp.Kvs[0].Value, &m); err != nil {
+				return nil, err
+			}
+			metrics = &m
+		}
+
+		details = append(details, SLOSearchRunDetail{
+			RunListItem: RunListItem{
+				ID:               run.ID,
+				ModelHfID:        modelHfID,
+				InstanceTypeName: instName,
+				Framework:        run.Framework,
+				RunType:          run.RunType,
+				Status:           run.Status,
+				CreatedAt:        run.CreatedAt,
+				StartedAt:        run.StartedAt,
+				CompletedAt:      run.CompletedAt,
+			},
+			Concurrency: run.Concurrency,
+			Metrics:     metrics,
+		})
+	}
+	sort.Slice(details, func(i, j int) bool { return details[i].CreatedAt.After(details[j].CreatedAt) })
+	return details, nil
+}
+
+// EnqueueRun admits runID into the persistent run queue and atomically
+// moves its run to status "queued" with QueuedAt stamped, so a caller
+// never observes the run sitting in the queue while its status still
+// reads a stale value (e.g. right after Scheduler.Requeue re-admits a
+// completed run).
+func (e *EtcdRepo) EnqueueRun(ctx context.Context, runID, instanceFamily, userID string, priority int) error {
+	now := time.Now()
+	q := QueuedRun{RunID: runID, InstanceFamily: instanceFamily, UserID: userID, Priority: priority, EnqueuedAt: now}
+	data, err := json.Marshal(q)
+	if err != nil {
+		return err
+	}
+	ops := []clientv3.Op{clientv3.OpPut(etcdPrefixRunQueue+runID, string(data))}
+
+	run, _, err := e.getRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run != nil {
+		run.Status = "queued"
+		run.QueuedAt = &now
+		rdata, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixRun+runID, string(rdata)))
+	}
+	_, err = e.client.Txn(ctx).Then(ops...).Commit()
+	return err
+}
+
+// ListQueuedRuns returns every still-queued run, priority descending then
+// EnqueuedAt ascending.
+func (e *EtcdRepo) ListQueuedRuns(ctx context.Context) ([]QueuedRun, error) {
+	resp, err := e.client.Get(ctx, etcdPrefixRunQueue, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	var queued []QueuedRun
+	for _, kv := range resp.Kvs {
+		var q QueuedRun
+		if err := json.Unmarshal(kv.Value, &q); err != nil {
+			return nil, err
+		}
+		queued = append(queued, q)
+	}
+	sort.Slice(queued, func(i, j int) bool {
+		if queued[i].Priority != queued[j].Priority {
+			return queued[i].Priority > queued[j].Priority
+		}
+		return queued[i].EnqueuedAt.Before(queued[j].EnqueuedAt)
+	})
+	return queued, nil
+}
+
+// DequeueRun removes runID from the persistent queue.
+func (e *EtcdRepo) DequeueRun(ctx context.Context, runID string) error {
+	_, err := e.client.Delete(ctx, etcdPrefixRunQueue+runID)
+	return err
+}
+
+// SetRunPriority updates a still-queued run's priority in place.
+func (e *EtcdRepo) SetRunPriority(ctx context.Context, runID string, priority int) error {
+	resp, err := e.client.Get(ctx, etcdPrefixRunQueue+runID)
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) == 0 {
+		return ErrRunNotQueued
+	}
+	var q QueuedRun
+	if err := json.Unmarshal(resp.Kvs[0].Value, &q); err != nil {
+		return err
+	}
+	q.Priority = priority
+	data, err := json.Marshal(q)
+	if err != nil {
+		return err
+	}
+	_, err = e.client.Put(ctx, etcdPrefixRunQueue+runID, string(data))
+	return err
+}
+
+// RecordQueueWait stamps runID's queue wait time.
+func (e *EtcdRepo) RecordQueueWait(ctx context.Context, runID string, waitSeconds float64) error {
+	run, _, err := e.getRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.QueueWaitSeconds = &waitSeconds
+	return e.putRun(ctx, run)
+}
+
+// RecordExecutionDuration stamps runID's execution duration.
+func (e *EtcdRepo) RecordExecutionDuration(ctx context.Context, runID string, seconds float64) error {
+	run, _, err := e.getRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.ExecutionSeconds = &seconds
+	return e.putRun(ctx, run)
+}
+
+// VerifyRun checks a single run's samples, metrics, and references.
+func (e *EtcdRepo) VerifyRun(ctx context.Context, runID string) (RunHealthStats, error) {
+	run, _, err := e.getRun(ctx, runID)
+	if err != nil {
+		return RunHealthStats{}, err
+	}
+	if run == nil {
+		return RunHealthStats{}, fmt.Errorf("run %s not found", runID)
+	}
+
+	metrics, err := e.GetMetricsByRunID(ctx, runID)
+	if err != nil {
+		return RunHealthStats{}, err
+	}
+
+	model := e.modelByID(ctx, run.ModelID)
+	inst := e.instanceTypeByID(ctx, run.InstanceTypeID)
+
+	resp, err := e.client.Get(ctx, etcdPrefixSamples+runID+"/", clientv3.WithPrefix())
+	if err != nil {
+		return RunHealthStats{}, err
+	}
+	var samples []Sample
+	for _, kv := range resp.Kvs {
+		var s Sample
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			return RunHealthStats{}, err
+		}
+		samples = append(samples, s)
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Timestamp.Before(samples[j].Timestamp) })
+
+	return verifyRunHealth(run, metrics, model, inst, samples), nil
+}
+
+// GatherCatalogHealth verifies every run CatalogFilter f selects.
+func (e *EtcdRepo) GatherCatalogHealth(ctx context.Context, f CatalogFilter) ([]RunHealthStats, error) {
+	return gatherCatalogHealth(ctx, e, f)
+}
+
+// MarkRunHealthFailure supersedes runID and records why, rather than
+// deleting it.
+func (e *EtcdRepo) MarkRunHealthFailure(ctx context.Context, runID, reason string) error {
+	run, _, err := e.getRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.Superseded = true
+	run.HealthFailureReason = &reason
+	return e.putRun(ctx, run)
+}
+
+// QueryCatalog evaluates a PromQL-style expr against the catalog as of at.
+func (e *EtcdRepo) QueryCatalog(ctx context.Context, expr string, at time.Time) (Vector, error) {
+	entries, _, err := e.ListCatalog(ctx, CatalogFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return evalCatalogQuery(entries, e.fetchSamplesForQuery(ctx), expr, at)
+}
+
+// QueryCatalogRange evaluates expr at each step between from and to.
+func (e *EtcdRepo) QueryCatalogRange(ctx context.Context, expr string, from, to time.Time, step time.Duration) (Matrix, error) {
+	entries, _, err := e.ListCatalog(ctx, CatalogFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return evalCatalogRangeQuery(entries, e.fetchSamplesForQuery(ctx), expr, from, to, step)
+}
+
+func (e *EtcdRepo) fetchSamplesForQuery(ctx context.Context) sampleFetcher {
+	return func(runID string) ([]Sample, error) {
+		resp, err := e.client.Get(ctx, etcdPrefixSamples+runID+"/", clientv3.WithPrefix())
+		if err != nil {
+			return nil, err
+		}
+		samples := make([]Sample, 0, len(resp.Kvs))
+		for _, kv := range resp.Kvs {
+			var s Sample
+			if err := json.Unmarshal(kv.Value, &s); err != nil {
+				return nil, err
+			}
+			samples = append(samples, s)
+		}
+		sort.Slice(samples, func(i, j int) bool { return samples[i].Timestamp.Before(samples[j].Timestamp) })
+		return samples, nil
+	}
+}
+
+// sampleKey returns the per-sample etcd key for runID. Samples are stored
+// one per key, ordered lexicographically by zero-padded UnixNano so a
+// WithPrefix range read comes back in timestamp order and QueryRange can
+// bound its scan with WithRange instead of filtering the whole run.
+func sampleKey(runID string, ts time.Time) string {
+	return fmt.Sprintf("%s%s/%020d", etcdPrefixSamples, runID, ts.UnixNano())
+}
+
+// AppendSamples writes each sample as its own key under runID's prefix,
+// chunked at 128 ops per txn like Import, since a run's full sample set
+// can easily exceed etcd's single-txn op cap.
+func (e *EtcdRepo) AppendSamples(ctx context.Context, runID string, samples []Sample) error {
+	run, _, err := e.getRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return fmt.Errorf("run %s not found", runID)
+	}
+
+	ops := make([]clientv3.Op, 0, len(samples))
+	for _, s := range samples {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(sampleKey(runID, s.Timestamp), string(data)))
+	}
+
+	const chunkSize = 128
+	for i := 0; i < len(ops); i += chunkSize {
+		end := i + chunkSize
+		if end > len(ops) {
+			end = len(ops)
+		}
+		if _, err := e.client.Txn(ctx).Then(ops[i:end]...).Commit(); err != nil {
+			return fmt.Errorf("append samples chunk %d-%d: %w", i, end, err)
+		}
+	}
+	return nil
+}
+
+// QueryRange averages metric over step-sized buckets spanning [from, to),
+// bounding the etcd range scan to runID's samples in [from, to) rather
+// than reading the run's entire history.
+func (e *EtcdRepo) QueryRange(ctx context.Context, runID, metric string, from, to time.Time, step time.Duration) ([]Point, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be positive")
+	}
+
+	resp, err := e.client.Get(ctx, sampleKey(runID, from), clientv3.WithRange(sampleKey(runID, to)))
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []Sample
+	for _, kv := range resp.Kvs {
+		var s Sample
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			return nil, err
+		}
+		samples = append(samples, s)
+	}
+
+	var points []Point
+	for bucketStart := from; bucketStart.Before(to); bucketStart = bucketStart.Add(step) {
+		bucketEnd := bucketStart.Add(step)
+		var sum float64
+		var count int
+		for _, sm := range samples {
+			if sm.Timestamp.Before(bucketStart) || !sm.Timestamp.Before(bucketEnd) {
+				continue
+			}
+			if v, ok := sampleMetric(sm, metric); ok {
+				sum += v
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		points = append(points, Point{Timestamp: bucketStart, Value: sum / float64(count)})
+	}
+	return points, nil
+}
+
+// Compact is a no-op on EtcdRepo: each sample already occupies its own
+// key, so there's no in-process chunk structure to merge the way
+// MockRepo's sampleStore has.
+func (e *EtcdRepo) Compact(_ context.Context) error { return nil }
+
+// ApplyRetention deletes sample keys older than defaultSampleRetention
+// for every run except those still "running".
+func (e *EtcdRepo) ApplyRetention(ctx context.Context) error {
+	cutoff := time.Now().Add(-defaultSampleRetention)
+
+	resp, err := e.client.Get(ctx, etcdPrefixSamples, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return err
+	}
+
+	runIDs := make(map[string]bool)
+	for _, kv := range resp.Kvs {
+		rest := strings.TrimPrefix(string(kv.Key), etcdPrefixSamples)
+		if i := strings.LastIndex(rest, "/"); i >= 0 {
+			runIDs[rest[:i]] = true
+		}
+	}
+
+	for runID := range runIDs {
+		run, _, err := e.getRun(ctx, runID)
+		if err != nil {
+			return err
+		}
+		if run != nil && run.Status == "running" {
+			continue
+		}
+		if _, err := e.client.Delete(ctx, sampleKey(runID, time.Unix(0, 0)), clientv3.WithRange(sampleKey(runID, cutoff))); err != nil {
+			return fmt.Errorf("apply retention for run %s: %w", runID, err)
+		}
+	}
+	return nil
+}
+
+// CompareRuns returns runIDA's and runIDB's raw samples, reusing the
+// same read path QueryCatalog's quantile_over_time draws from.
+func (e *EtcdRepo) CompareRuns(ctx context.Context, runIDA, runIDB string) (control, treatment []Sample, err error) {
+	fetch := e.fetchSamplesForQuery(ctx)
+	control, err = fetch(runIDA)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load samples for %s: %w", runIDA, err)
+	}
+	treatment, err = fetch(runIDB)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load samples for %s: %w", runIDB, err)
+	}
+	return control, treatment, nil
+}
+
+// Snapshot reads out EtcdRepo's entire contents for migration to another
+// backend via Import.
+func (e *EtcdRepo) Snapshot(ctx context.Context) (StoreSnapshot, error) {
+	var snap StoreSnapshot
+
+	modelResp, err := e.client.Get(ctx, etcdPrefixModel, clientv3.WithPrefix())
+	if err != nil {
+		return snap, err
+	}
+	for _, kv := range modelResp.Kvs {
+		var m Model
+		if err := json.Unmarshal(kv.Value, &m); err != nil {
+			return snap, err
+		}
+		snap.Models = append(snap.Models, m)
+	}
+
+	instResp, err := e.client.Get(ctx, etcdPrefixInstType, clientv3.WithPrefix())
+	if err != nil {
+		return snap, err
+	}
+	for _, kv := range instResp.Kvs {
+		var it InstanceType
+		if err := json.Unmarshal(kv.Value, &it); err != nil {
+			return snap, err
+		}
+		snap.InstanceTypes = append(snap.InstanceTypes, it)
+	}
+
+	runResp, err := e.client.Get(ctx, etcdPrefixRun, clientv3.WithPrefix())
+	if err != nil {
+		return snap, err
+	}
+	for _, kv := range runResp.Kvs {
+		var r BenchmarkRun
+		if err := json.Unmarshal(kv.Value, &r); err != nil {
+			return snap, err
+		}
+		snap.Runs = append(snap.Runs, r)
+	}
+
+	metResp, err := e.client.Get(ctx, etcdPrefixMetrics, clientv3.WithPrefix())
+	if err != nil {
+		return snap, err
+	}
+	for _, kv := range metResp.Kvs {
+		var m BenchmarkMetrics
+		if err := json.Unmarshal(kv.Value, &m); err != nil {
+			return snap, err
+		}
+		snap.Metrics = append(snap.Metrics, m)
+	}
+
+	sweepResp, err := e.client.Get(ctx, etcdPrefixSweep, clientv3.WithPrefix())
+	if err != nil {
+		return snap, err
+	}
+	for _, kv := range sweepResp.Kvs {
+		var s Sweep
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			return snap, err
+		}
+		snap.Sweeps = append(snap.Sweeps, s)
+	}
+
+	experimentResp, err := e.client.Get(ctx, etcdPrefixExperiment, clientv3.WithPrefix())
+	if err != nil {
+		return snap, err
+	}
+	for _, kv := range experimentResp.Kvs {
+		var exp Experiment
+		if err := json.Unmarshal(kv.Value, &exp); err != nil {
+			return snap, err
+		}
+		snap.Experiments = append(snap.Experiments, exp)
+	}
+
+	sampleResp, err := e.client.Get(ctx, etcdPrefixSamples, clientv3.WithPrefix())
+	if err != nil {
+		return snap, err
+	}
+	byRun := make(map[string][]Sample)
+	var order []string
+	for _, kv := range sampleResp.Kvs {
+		rest := strings.TrimPrefix(string(kv.Key), etcdPrefixSamples)
+		i := strings.LastIndex(rest, "/")
+		if i < 0 {
+			continue
+		}
+		runID := rest[:i]
+		var s Sample
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			return snap, err
+		}
+		if _, ok := byRun[runID]; !ok {
+			order = append(order, runID)
+		}
+		byRun[runID] = append(byRun[runID], s)
+	}
+	for _, runID := range order {
+		snap.Samples = append(snap.Samples, RunSamples{RunID: runID, Samples: byRun[runID]})
+	}
+
+	accelSampleResp, err := e.client.Get(ctx, etcdPrefixAccelSamples, clientv3.WithPrefix())
+	if err != nil {
+		return snap, err
+	}
+	for _, kv := range accelSampleResp.Kvs {
+		runID := strings.TrimPrefix(string(kv.Key), etcdPrefixAccelSamples)
+		var samples []AcceleratorSample
+		if err := json.Unmarshal(kv.Value, &samples); err != nil {
+			return snap, err
+		}
+		snap.AcceleratorSamples = append(snap.AcceleratorSamples, RunAcceleratorSamples{RunID: runID, Samples: samples})
+	}
+
+	groupResp, err := e.client.Get(ctx, etcdPrefixGroup, clientv3.WithPrefix())
+	if err != nil {
+		return snap, err
+	}
+	for _, kv := range groupResp.Kvs {
+		var g BenchmarkGroup
+		if err := json.Unmarshal(kv.Value, &g); err != nil {
+			return snap, err
+		}
+		snap.Groups = append(snap.Groups, g)
+	}
+
+	policyResp, err := e.client.Get(ctx, etcdPrefixPolicy, clientv3.WithPrefix())
+	if err != nil {
+		return snap, err
+	}
+	for _, kv := range policyResp.Kvs {
+		var p BenchmarkPolicy
+		if err := json.Unmarshal(kv.Value, &p); err != nil {
+			return snap, err
+		}
+		snap.Policies = append(snap.Policies, p)
+	}
+
+	queueResp, err := e.client.Get(ctx, etcdPrefixRunQueue, clientv3.WithPrefix())
+	if err != nil {
+		return snap, err
+	}
+	for _, kv := range queueResp.Kvs {
+		var q QueuedRun
+		if err := json.Unmarshal(kv.Value, &q); err != nil {
+			return snap, err
+		}
+		snap.Queue = append(snap.Queue, q)
+	}
+
+	return snap, nil
+}
+
+// Import bulk-loads a StoreSnapshot, preserving every original ID so
+// cross-references between runs, models, and instance types stay intact.
+// It does not advance etcd's sequence counters past the imported IDs'
+// numeric suffixes the way BoltRepo's Import does, because a fresh
+// cluster's counters already start at zero; callers migrating into an
+// etcd cluster that already has live data should restore to an empty
+// cluster instead.
+func (e *EtcdRepo) Import(ctx context.Context, snap StoreSnapshot) error {
+	var ops []clientv3.Op
+	for _, m := range snap.Models {
+		data, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixModel+m.ID, string(data)))
+		ops = append(ops, clientv3.OpPut(modelHfEtcdKey(m.HfID, m.HfRevision), m.ID))
+	}
+	for _, it := range snap.InstanceTypes {
+		data, err := json.Marshal(it)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixInstType+it.Name, string(data)))
+		ops = append(ops, clientv3.OpPut(etcdPrefixInstTypeByID+it.ID, it.Name))
+	}
+	for _, r := range snap.Runs {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixRun+r.ID, string(data)))
+	}
+	for _, m := range snap.Metrics {
+		data, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixMetrics+m.RunID, string(data)))
+	}
+	for _, s := range snap.Sweeps {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixSweep+s.ID, string(data)))
+	}
+	for _, exp := range snap.Experiments {
+		data, err := json.Marshal(exp)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixExperiment+exp.ID, string(data)))
+	}
+	for _, rs := range snap.Samples {
+		for _, s := range rs.Samples {
+			data, err := json.Marshal(s)
+			if err != nil {
+				return err
+			}
+			ops = append(ops, clientv3.OpPut(sampleKey(rs.RunID, s.Timestamp), string(data)))
+		}
+	}
+	for _, rs := range snap.AcceleratorSamples {
+		data, err := json.Marshal(rs.Samples)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixAccelSamples+rs.RunID, string(data)))
+	}
+	for _, g := range snap.Groups {
+		data, err := json.Marshal(g)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixGroup+g.ID, string(data)))
+	}
+	for _, p := range snap.Policies {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixPolicy+p.ID, string(data)))
+	}
+	for _, q := range snap.Queue {
+		data, err := json.Marshal(q)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, clientv3.OpPut(etcdPrefixRunQueue+q.RunID, string(data)))
+	}
+
+	// etcd txns cap at 128 ops; chunk the import instead of assuming a
+	// snapshot small enough for one transaction.
+	const chunkSize = 128
+	for i := 0; i < len(ops); i += chunkSize {
+		end := i + chunkSize
+		if end > len(ops) {
+			end = len(ops)
+		}
+		if _, err := e.client.Txn(ctx).Then(ops[i:end]...).Commit(); err != nil {
+			return fmt.Errorf("import chunk %d-%d: %w", i, end, err)
+		}
+	}
+	return nil
+}
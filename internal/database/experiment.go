@@ -0,0 +1,204 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Experiment groups one or more named arms of sweep runs under a single
+// parent ID, the same way Sweep groups the Cartesian product of one
+// SweepRequest: a submitter polls one resource instead of tracking every
+// arm's runs (or every arm's own sweep) by hand.
+type Experiment struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExperimentRunDetail is a denormalized child-run row for the experiment
+// detail endpoint, tagging each run with the named arm it belongs to so a
+// caller (or internal/analyzer, via a control/treatment pair of run IDs
+// picked out of this list) can group runs by arm without cross-
+// referencing ExperimentSpec.
+type ExperimentRunDetail struct {
+	RunListItem
+	Arm string `json:"arm"`
+}
+
+// ExperimentArm is one named group of runs within an ExperimentSpec, e.g.
+// {Name: "treatment", SweepRequest: {Quantization: "int8", ...}}. Every
+// arm expands independently via its own SweepRequest.Expand(), so arms
+// can vary any combination of swept dimensions against each other.
+type ExperimentArm struct {
+	Name         string `json:"name" yaml:"name"`
+	SweepRequest `yaml:",inline"`
+}
+
+// ExperimentSpec is a declarative, file-based generalization of
+// SweepRequest: instead of one Cartesian product against a single model,
+// it groups one or more named "arms" under a single parent experiment, so
+// a caller can submit both sides of an A/B comparison (see
+// internal/analyzer.CompareSamples) in one request instead of tracking
+// two sweep IDs and pairing their runs by hand. The accelbench sweep CLI
+// command reads one of these from a YAML file; POST /experiments accepts
+// the same shape as a JSON body.
+type ExperimentSpec struct {
+	Name string          `json:"name,omitempty" yaml:"name,omitempty"`
+	Arms []ExperimentArm `json:"arms" yaml:"arms"`
+}
+
+// maxExperimentRuns bounds an ExperimentSpec's expansion across all arms
+// combined, the same safety valve as maxSweepRuns but measured over the
+// whole experiment rather than one arm.
+const maxExperimentRuns = 512
+
+// Expand returns the concatenation of every arm's expanded RunRequests
+// alongside a parallel slice naming the arm each belongs to, erroring if
+// the spec declares no arms, any arm fails to expand, or the combined
+// total exceeds maxExperimentRuns.
+func (s ExperimentSpec) Expand() (requests []RunRequest, arms []string, err error) {
+	if len(s.Arms) == 0 {
+		return nil, nil, fmt.Errorf("experiment must declare at least one arm")
+	}
+	seen := make(map[string]bool, len(s.Arms))
+	for _, arm := range s.Arms {
+		if arm.Name == "" {
+			return nil, nil, fmt.Errorf("every arm must have a name")
+		}
+		if seen[arm.Name] {
+			return nil, nil, fmt.Errorf("duplicate arm name %q", arm.Name)
+		}
+		seen[arm.Name] = true
+
+		runs, err := arm.SweepRequest.Expand()
+		if err != nil {
+			return nil, nil, fmt.Errorf("arm %q: %w", arm.Name, err)
+		}
+		requests = append(requests, runs...)
+		for range runs {
+			arms = append(arms, arm.Name)
+		}
+	}
+	if len(requests) > maxExperimentRuns {
+		return nil, nil, fmt.Errorf("experiment expands to %d runs, exceeds limit of %d", len(requests), maxExperimentRuns)
+	}
+	return requests, arms, nil
+}
+
+// CreateExperiment inserts an experiment row and all of its child runs in
+// a single transaction, mirroring CreateSweep: either every run is
+// created or none are. runs must already have ModelID/InstanceTypeID/Arm
+// resolved; CreateExperiment stamps ExperimentID on each and returns the
+// experiment ID alongside the child run IDs in the same order as runs.
+func (r *Repository) CreateExperiment(ctx context.Context, runs []*BenchmarkRun) (string, []string, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var experimentID string
+	if err := tx.QueryRow(ctx,
+		`INSERT INTO experiments DEFAULT VALUES RETURNING id`,
+	).Scan(&experimentID); err != nil {
+		return "", nil, fmt.Errorf("insert experiment: %w", err)
+	}
+
+	runIDs := make([]string, 0, len(runs))
+	for _, run := range runs {
+		var originalRequestJSON []byte
+		if run.OriginalRequest != nil {
+			var err error
+			originalRequestJSON, err = json.Marshal(run.OriginalRequest)
+			if err != nil {
+				return "", nil, fmt.Errorf("marshal original request: %w", err)
+			}
+		}
+
+		var id string
+		err := tx.QueryRow(ctx,
+			`INSERT INTO benchmark_runs
+			    (model_id, instance_type_id, framework, framework_version,
+			     tensor_parallel_degree, quantization, concurrency,
+			     input_sequence_length, output_sequence_length, dataset_name,
+			     run_type, status, experiment_id, arm, original_request)
+			 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15)
+			 RETURNING id`,
+			run.ModelID, run.InstanceTypeID, run.Framework, run.FrameworkVersion,
+			run.TensorParallelDegree, run.Quantization, run.Concurrency,
+			run.InputSequenceLength, run.OutputSequenceLength, run.DatasetName,
+			run.RunType, run.Status, experimentID, run.Arm, originalRequestJSON,
+		).Scan(&id)
+		if err != nil {
+			return "", nil, fmt.Errorf("insert experiment run: %w", err)
+		}
+		run.ID = id
+		run.ExperimentID = &experimentID
+		runIDs = append(runIDs, id)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return experimentID, runIDs, nil
+}
+
+// GetExperiment returns an experiment by ID, or nil if not found.
+func (r *Repository) GetExperiment(ctx context.Context, experimentID string) (*Experiment, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	var exp Experiment
+	err := r.pool.QueryRow(ctx,
+		`SELECT id, created_at FROM experiments WHERE id = $1`, experimentID,
+	).Scan(&exp.ID, &exp.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query experiment: %w", err)
+	}
+	return &exp, nil
+}
+
+// ListRunsByExperiment returns every child run of experimentID tagged with
+// its arm, oldest first.
+func (r *Repository) ListRunsByExperiment(ctx context.Context, experimentID string) ([]ExperimentRunDetail, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT
+			br.id, m.hf_id, it.name,
+			br.framework, br.run_type, br.status,
+			br.created_at, br.started_at, br.completed_at, br.arm
+		FROM benchmark_runs br
+		JOIN models m ON br.model_id = m.id
+		JOIN instance_types it ON br.instance_type_id = it.id
+		WHERE br.experiment_id = $1
+		ORDER BY br.created_at`, experimentID)
+	if err != nil {
+		return nil, fmt.Errorf("list runs by experiment: %w", err)
+	}
+	defer rows.Close()
+
+	var details []ExperimentRunDetail
+	for rows.Next() {
+		var d ExperimentRunDetail
+		if err := rows.Scan(
+			&d.ID, &d.ModelHfID, &d.InstanceTypeName,
+			&d.Framework, &d.RunType, &d.Status,
+			&d.CreatedAt, &d.StartedAt, &d.CompletedAt, &d.Arm,
+		); err != nil {
+			return nil, fmt.Errorf("scan experiment run row: %w", err)
+		}
+		details = append(details, d)
+	}
+	return details, rows.Err()
+}
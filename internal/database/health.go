@@ -0,0 +1,258 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RunHealthStats reports the result of running a single BenchmarkRun
+// through VerifyRun's integrity checks, borrowing the idea of a
+// block-verification pass from compactor pipelines: a run that completed
+// without error can still have silently-corrupt data (out-of-order
+// samples, nonsensical percentiles, a dangling model/instance reference)
+// that would otherwise only surface as a confusing outlier downstream in
+// the catalog.
+type RunHealthStats struct {
+	RunID                  string   `json:"run_id"`
+	Healthy                bool     `json:"healthy"`
+	MonotonicityViolations int      `json:"monotonicity_violations"`
+	PercentileSane         bool     `json:"percentile_sane"`
+	MissingFields          []string `json:"missing_fields,omitempty"`
+	DanglingModelRef       bool     `json:"dangling_model_ref"`
+	DanglingInstanceRef    bool     `json:"dangling_instance_ref"`
+	FailureReasons         []string `json:"failure_reasons,omitempty"`
+}
+
+// verifyRunHealth is the shared integrity check used by every Repo
+// backend's VerifyRun: each backend is only responsible for fetching run,
+// metrics, model, instance, and raw samples (wherever those live for that
+// backend), then this runs the same checks on all of them.
+func verifyRunHealth(run *BenchmarkRun, metrics *BenchmarkMetrics, model *Model, inst *InstanceType, samples []Sample) RunHealthStats {
+	stats := RunHealthStats{RunID: run.ID, PercentileSane: true, Healthy: true}
+
+	if model == nil {
+		stats.DanglingModelRef = true
+		stats.FailureReasons = append(stats.FailureReasons, fmt.Sprintf("model_id %q does not reference an existing model", run.ModelID))
+	}
+	if inst == nil {
+		stats.DanglingInstanceRef = true
+		stats.FailureReasons = append(stats.FailureReasons, fmt.Sprintf("instance_type_id %q does not reference an existing instance type", run.InstanceTypeID))
+	}
+
+	// Required-field completeness, against the columns ListCatalog's
+	// CatalogEntry always needs populated for a run to be comparable.
+	if run.Framework == "" {
+		stats.MissingFields = append(stats.MissingFields, "framework")
+	}
+	if run.FrameworkVersion == "" {
+		stats.MissingFields = append(stats.MissingFields, "framework_version")
+	}
+	if run.DatasetName == "" {
+		stats.MissingFields = append(stats.MissingFields, "dataset_name")
+	}
+	if run.TensorParallelDegree <= 0 {
+		stats.MissingFields = append(stats.MissingFields, "tensor_parallel_degree")
+	}
+	if run.Concurrency <= 0 {
+		stats.MissingFields = append(stats.MissingFields, "concurrency")
+	}
+	if run.InputSequenceLength <= 0 {
+		stats.MissingFields = append(stats.MissingFields, "input_sequence_length")
+	}
+	if run.OutputSequenceLength <= 0 {
+		stats.MissingFields = append(stats.MissingFields, "output_sequence_length")
+	}
+	if len(stats.MissingFields) > 0 {
+		stats.FailureReasons = append(stats.FailureReasons, fmt.Sprintf("missing required fields: %s", strings.Join(stats.MissingFields, ", ")))
+	}
+
+	// Monotonicity: TokensOut is a per-request running total, so it should
+	// never decrease between consecutive samples once ordered by time.
+	for i := 1; i < len(samples); i++ {
+		if samples[i].TokensOut < samples[i-1].TokensOut {
+			stats.MonotonicityViolations++
+		}
+	}
+	if stats.MonotonicityViolations > 0 {
+		stats.FailureReasons = append(stats.FailureReasons, fmt.Sprintf("%d monotonicity violation(s) in raw samples", stats.MonotonicityViolations))
+	}
+
+	if metrics != nil {
+		if metrics.TTFTP50Ms != nil && metrics.TTFTP99Ms != nil && *metrics.TTFTP50Ms > *metrics.TTFTP99Ms {
+			stats.PercentileSane = false
+			stats.FailureReasons = append(stats.FailureReasons, "ttft_p50_ms exceeds ttft_p99_ms")
+		}
+		if metrics.E2ELatencyP50Ms != nil && metrics.E2ELatencyP99Ms != nil && *metrics.E2ELatencyP50Ms > *metrics.E2ELatencyP99Ms {
+			stats.PercentileSane = false
+			stats.FailureReasons = append(stats.FailureReasons, "e2e_latency_p50_ms exceeds e2e_latency_p99_ms")
+		}
+		// ITL * (output_len - 1) plus TTFT should land within the same
+		// ballpark as E2E latency; a run whose E2E latency is wildly off
+		// from what its own TTFT/ITL imply usually means one of the three
+		// was computed from a different (stale or truncated) sample set.
+		if metrics.TTFTP50Ms != nil && metrics.ITLP50Ms != nil && metrics.E2ELatencyP50Ms != nil && run.OutputSequenceLength > 1 {
+			expected := *metrics.TTFTP50Ms + *metrics.ITLP50Ms*float64(run.OutputSequenceLength-1)
+			actual := *metrics.E2ELatencyP50Ms
+			if actual > 0 && (expected > actual*1.5 || expected < actual*0.5) {
+				stats.PercentileSane = false
+				stats.FailureReasons = append(stats.FailureReasons, "itl_p50_ms * output_sequence_length is inconsistent with e2e_latency_p50_ms - ttft_p50_ms")
+			}
+		}
+	}
+
+	if len(stats.FailureReasons) > 0 {
+		stats.Healthy = false
+	}
+	return stats
+}
+
+// gatherCatalogHealth is the shared GatherCatalogHealth body for backends
+// that already have a working ListCatalog/VerifyRun pair: it lists the
+// filtered catalog, then verifies each entry in turn. Operators scanning
+// for silently-broken catalog entries don't need this to be a single
+// query — it's a diagnostic sweep, not a hot path.
+func gatherCatalogHealth(ctx context.Context, repo Repo, f CatalogFilter) ([]RunHealthStats, error) {
+	entries, _, err := repo.ListCatalog(ctx, f)
+	if err != nil {
+		return nil, fmt.Errorf("list catalog: %w", err)
+	}
+	stats := make([]RunHealthStats, 0, len(entries))
+	for _, e := range entries {
+		s, err := repo.VerifyRun(ctx, e.RunID)
+		if err != nil {
+			return nil, fmt.Errorf("verify run %s: %w", e.RunID, err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// VerifyRun checks a single run's samples, metrics, and references.
+func (m *MockRepo) VerifyRun(_ context.Context, runID string) (RunHealthStats, error) {
+	m.mu.Lock()
+	run, ok := m.runs[runID]
+	if !ok {
+		m.mu.Unlock()
+		return RunHealthStats{}, fmt.Errorf("run %s not found", runID)
+	}
+	runCopy := *run
+	metrics := m.metrics[runID]
+
+	var model *Model
+	for _, mdl := range m.models {
+		if mdl.ID == run.ModelID {
+			model = mdl
+			break
+		}
+	}
+	var inst *InstanceType
+	for _, it := range m.instTypes {
+		if it.ID == run.InstanceTypeID {
+			inst = it
+			break
+		}
+	}
+	m.mu.Unlock()
+
+	samples := m.samples.all(runID)
+	return verifyRunHealth(&runCopy, metrics, model, inst, samples), nil
+}
+
+// GatherCatalogHealth verifies every run CatalogFilter f selects.
+func (m *MockRepo) GatherCatalogHealth(ctx context.Context, f CatalogFilter) ([]RunHealthStats, error) {
+	return gatherCatalogHealth(ctx, m, f)
+}
+
+// MarkRunHealthFailure supersedes runID and records why, rather than
+// deleting it, so it drops out of catalog queries without losing the
+// underlying data an operator may still want to inspect.
+func (m *MockRepo) MarkRunHealthFailure(_ context.Context, runID, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[runID]
+	if !ok {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.Superseded = true
+	run.HealthFailureReason = &reason
+	return nil
+}
+
+// VerifyRun checks a single run's samples, metrics, and references.
+func (r *Repository) VerifyRun(ctx context.Context, runID string) (RunHealthStats, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	run, err := r.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		return RunHealthStats{}, fmt.Errorf("get benchmark run: %w", err)
+	}
+	if run == nil {
+		return RunHealthStats{}, fmt.Errorf("run %s not found", runID)
+	}
+
+	metrics, err := r.GetMetricsByRunID(ctx, runID)
+	if err != nil {
+		return RunHealthStats{}, fmt.Errorf("get metrics: %w", err)
+	}
+
+	var modelExists bool
+	if err := r.pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM models WHERE id = $1)`, run.ModelID).Scan(&modelExists); err != nil {
+		return RunHealthStats{}, fmt.Errorf("query model reference: %w", err)
+	}
+	var model *Model
+	if modelExists {
+		model = &Model{ID: run.ModelID}
+	}
+
+	var instExists bool
+	if err := r.pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM instance_types WHERE id = $1)`, run.InstanceTypeID).Scan(&instExists); err != nil {
+		return RunHealthStats{}, fmt.Errorf("query instance type reference: %w", err)
+	}
+	var inst *InstanceType
+	if instExists {
+		inst = &InstanceType{ID: run.InstanceTypeID}
+	}
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT ts, tokens_out FROM benchmark_samples WHERE run_id = $1 ORDER BY ts`, runID)
+	if err != nil {
+		return RunHealthStats{}, fmt.Errorf("query samples: %w", err)
+	}
+	var samples []Sample
+	for rows.Next() {
+		var s Sample
+		if err := rows.Scan(&s.Timestamp, &s.TokensOut); err != nil {
+			rows.Close()
+			return RunHealthStats{}, fmt.Errorf("scan sample: %w", err)
+		}
+		samples = append(samples, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return RunHealthStats{}, fmt.Errorf("iterate samples: %w", err)
+	}
+
+	return verifyRunHealth(run, metrics, model, inst, samples), nil
+}
+
+// GatherCatalogHealth verifies every run CatalogFilter f selects.
+func (r *Repository) GatherCatalogHealth(ctx context.Context, f CatalogFilter) ([]RunHealthStats, error) {
+	return gatherCatalogHealth(ctx, r, f)
+}
+
+// MarkRunHealthFailure supersedes runID and records why, rather than
+// deleting it.
+func (r *Repository) MarkRunHealthFailure(ctx context.Context, runID, reason string) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	_, err := r.pool.Exec(ctx,
+		`UPDATE benchmark_runs SET superseded = TRUE, health_failure_reason = $1 WHERE id = $2`,
+		reason, runID)
+	if err != nil {
+		return fmt.Errorf("mark run health failure: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestVerifyRun_Healthy(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{Status: "completed"})
+	if err != nil || len(items) == 0 {
+		t.Fatal("no completed run to verify")
+	}
+	runID := items[0].ID
+
+	ttft, itl, e2e := 25.0, 10.0, 1300.0
+	if err := repo.PersistMetrics(ctx, runID, &BenchmarkMetrics{
+		TTFTP50Ms: &ttft, TTFTP99Ms: &ttft, ITLP50Ms: &itl, E2ELatencyP50Ms: &e2e, E2ELatencyP99Ms: &e2e,
+	}, nil); err != nil {
+		t.Fatalf("PersistMetrics: %v", err)
+	}
+
+	stats, err := repo.VerifyRun(ctx, runID)
+	if err != nil {
+		t.Fatalf("VerifyRun: %v", err)
+	}
+	if !stats.Healthy {
+		t.Errorf("expected healthy run, got: %+v", stats)
+	}
+}
+
+func TestVerifyRun_DanglingReferences(t *testing.T) {
+	repo := NewMockRepo()
+	ctx := context.Background()
+
+	run := &BenchmarkRun{
+		ModelID: "missing-model", InstanceTypeID: "missing-instance",
+		Framework: "vllm", FrameworkVersion: "v1",
+		TensorParallelDegree: 1, Concurrency: 1,
+		InputSequenceLength: 1, OutputSequenceLength: 1,
+		DatasetName: "synthetic", RunType: "on_demand", Status: "completed",
+	}
+	runID, err := repo.CreateBenchmarkRun(ctx, run)
+	if err != nil {
+		t.Fatalf("create run: %v", err)
+	}
+
+	stats, err := repo.VerifyRun(ctx, runID)
+	if err != nil {
+		t.Fatalf("VerifyRun: %v", err)
+	}
+	if stats.Healthy {
+		t.Fatal("expected unhealthy run due to dangling references")
+	}
+	if !stats.DanglingModelRef || !stats.DanglingInstanceRef {
+		t.Errorf("expected both dangling refs flagged, got: %+v", stats)
+	}
+}
+
+func TestVerifyRun_PercentileSanity(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{Status: "completed"})
+	if err != nil || len(items) == 0 {
+		t.Fatal("no completed run to verify")
+	}
+	runID := items[0].ID
+
+	p50, p99 := 500.0, 100.0 // p50 > p99: nonsensical
+	if err := repo.PersistMetrics(ctx, runID, &BenchmarkMetrics{TTFTP50Ms: &p50, TTFTP99Ms: &p99}, nil); err != nil {
+		t.Fatalf("PersistMetrics: %v", err)
+	}
+
+	stats, err := repo.VerifyRun(ctx, runID)
+	if err != nil {
+		t.Fatalf("VerifyRun: %v", err)
+	}
+	if stats.Healthy || stats.PercentileSane {
+		t.Errorf("expected percentile sanity failure, got: %+v", stats)
+	}
+}
+
+func TestVerifyRun_MonotonicityViolation(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{Status: "running"})
+	if err != nil || len(items) == 0 {
+		t.Fatal("no running run to append samples to")
+	}
+	runID := items[0].ID
+
+	base := time.Now().Truncate(time.Second)
+	samples := []Sample{
+		{Timestamp: base, TokensOut: 50},
+		{Timestamp: base.Add(time.Second), TokensOut: 20}, // decreases
+	}
+	if err := repo.AppendSamples(ctx, runID, samples); err != nil {
+		t.Fatalf("AppendSamples: %v", err)
+	}
+
+	stats, err := repo.VerifyRun(ctx, runID)
+	if err != nil {
+		t.Fatalf("VerifyRun: %v", err)
+	}
+	if stats.MonotonicityViolations != 1 || stats.Healthy {
+		t.Errorf("expected 1 monotonicity violation and unhealthy, got: %+v", stats)
+	}
+}
+
+func TestGatherCatalogHealth(t *testing.T) {
+	repo := seedCatalogRepo()
+	ctx := context.Background()
+
+	stats, err := repo.GatherCatalogHealth(ctx, CatalogFilter{})
+	if err != nil {
+		t.Fatalf("GatherCatalogHealth: %v", err)
+	}
+	if len(stats) == 0 {
+		t.Fatal("expected at least one catalog entry's health stats")
+	}
+	for _, s := range stats {
+		if !s.Healthy {
+			t.Errorf("seedCatalogRepo entries should be healthy, got: %+v", s)
+		}
+	}
+}
+
+func TestMarkRunHealthFailure(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{Status: "completed"})
+	if err != nil || len(items) == 0 {
+		t.Fatal("no completed run")
+	}
+	runID := items[0].ID
+
+	if err := repo.MarkRunHealthFailure(ctx, runID, "dangling model reference"); err != nil {
+		t.Fatalf("MarkRunHealthFailure: %v", err)
+	}
+
+	run, err := repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		t.Fatalf("GetBenchmarkRun: %v", err)
+	}
+	if !run.Superseded {
+		t.Error("expected run to be marked superseded")
+	}
+	if run.HealthFailureReason == nil || *run.HealthFailureReason != "dangling model reference" {
+		t.Errorf("expected health failure reason to be recorded, got: %v", run.HealthFailureReason)
+	}
+
+	if err := repo.MarkRunHealthFailure(ctx, "missing-run", "x"); err == nil {
+		t.Error("expected error for unknown run ID")
+	}
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"sync"
+	"time"
+)
+
+// IdempotencyRecord is what IdempotencyStore remembers about one
+// Idempotency-Key submission: the run it produced and a hash of the
+// request that produced it, so a retried request can be answered without
+// creating a duplicate run, and a reused key with a different body can be
+// rejected instead of silently returning the wrong run.
+type IdempotencyRecord struct {
+	RunID       string
+	RequestHash string
+	expiresAt   time.Time
+}
+
+// IdempotencyStore remembers recently issued Idempotency-Key results,
+// scoped per client, so a client retrying a POST it's unsure reached the
+// server doesn't launch a second benchmark run on expensive GPU capacity.
+// Entries are evicted lazily on their own next lookup once past TTL,
+// rather than via a background sweeper.
+type IdempotencyStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	records map[string]IdempotencyRecord
+}
+
+// NewIdempotencyStore creates an IdempotencyStore whose entries expire
+// ttl after they're stored.
+func NewIdempotencyStore(ttl time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{
+		ttl:     ttl,
+		records: make(map[string]IdempotencyRecord),
+	}
+}
+
+func idempotencyKey(clientID, key string) string {
+	return clientID + "\x00" + key
+}
+
+// Lookup returns the record stored for (clientID, key), or nil if there is
+// none or it has expired.
+func (s *IdempotencyStore) Lookup(clientID, key string) *IdempotencyRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := idempotencyKey(clientID, key)
+	rec, ok := s.records[k]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(rec.expiresAt) {
+		delete(s.records, k)
+		return nil
+	}
+	return &rec
+}
+
+// Store records that (clientID, key) produced runID for the request
+// hashing to requestHash, so a replay within TTL can be answered from it.
+func (s *IdempotencyStore) Store(clientID, key, requestHash, runID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[idempotencyKey(clientID, key)] = IdempotencyRecord{
+		RunID:       runID,
+		RequestHash: requestHash,
+		expiresAt:   time.Now().Add(s.ttl),
+	}
+}
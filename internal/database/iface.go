@@ -1,6 +1,9 @@
 package database
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Repo defines the interface for benchmark data operations.
 // The concrete *Repository satisfies this interface. Use this interface
@@ -11,15 +14,208 @@ type Repo interface {
 	GetInstanceTypeByName(ctx context.Context, name string) (*InstanceType, error)
 	CreateBenchmarkRun(ctx context.Context, run *BenchmarkRun) (string, error)
 	UpdateRunStatus(ctx context.Context, runID, status string) error
-	PersistMetrics(ctx context.Context, runID string, m *BenchmarkMetrics) error
+	// UpdateRunFailureReason stamps runID's BenchmarkRun.FailureReason
+	// without otherwise touching its status, so orchestrator.Execute can
+	// attach what it learned from Kubernetes (a Pod event or container
+	// status) to a run markFailed already moved to "failed" via
+	// UpdateRunStatus.
+	UpdateRunFailureReason(ctx context.Context, runID, reason string) error
+	CancelRun(ctx context.Context, runID string) error
+	UpdateRunPluginDigests(ctx context.Context, runID string, digests map[string]string) error
+	// UpdateRunPhase stamps runID's current lifecycle phase and the
+	// Kubernetes objects orchestrator.Execute has created so far
+	// (resources may carry only the fields known at this point, e.g.
+	// JobName/JobUID set only once the loadgen phase starts), so a
+	// restarted process's Resume can rebind to them by label selector
+	// instead of recreating them.
+	UpdateRunPhase(ctx context.Context, runID, phase string, resources *K8sResourceRefs) error
+	// SetTeardownPending marks whether runID's Kubernetes resources still
+	// need tearing down. Execute sets this true just before it starts
+	// creating objects and false only once its teardown defer succeeds, so
+	// a crash between those two points leaves it true and Resume knows to
+	// finish tearing the orphan down rather than assume a completed run
+	// cleaned up after itself.
+	SetTeardownPending(ctx context.Context, runID string, pending bool) error
+	// ListNonTerminalRuns returns every run whose status is "running" —
+	// i.e. orchestrator.Execute was actively managing it when the process
+	// serving this Repo stopped — for Resume to rebind to on startup.
+	ListNonTerminalRuns(ctx context.Context) ([]BenchmarkRun, error)
+	// ListExpiredRuns returns every queued, pending, or running run whose
+	// DeadlineAt is non-nil and strictly before now, for the reaper's tick
+	// to fail out and tear down. Unlike ListNonTerminalRuns it also
+	// includes runs that haven't started executing yet, since DeadlineAt
+	// is anchored on CreatedAt and bounds queue wait too.
+	ListExpiredRuns(ctx context.Context, now time.Time) ([]BenchmarkRun, error)
+	// ExtendDeadline pushes runID's DeadlineAt out to newDeadline,
+	// conditional on the run still being queued, pending, or running in
+	// one CAS, so a caller renewing a long-running job's lease can never
+	// resurrect a run the reaper (or a normal completion) already decided
+	// is done. Returns ErrRunNotFound if runID doesn't exist and
+	// ErrRunNotExtendable if it does but is already terminal.
+	ExtendDeadline(ctx context.Context, runID string, newDeadline time.Time) error
+	// PersistMetrics writes the run's summary BenchmarkMetrics row and its
+	// raw accelerator samples (if any were collected) in the same
+	// transaction, so a reader never observes a summary whose rollup
+	// fields (SMUtilP50Pct, EnergyConsumedJoules, ...) were computed from
+	// samples that then fail to land. GetAcceleratorSamples is the
+	// read-side counterpart, for later plotting per device index.
+	PersistMetrics(ctx context.Context, runID string, m *BenchmarkMetrics, samples []AcceleratorSample) error
+	// UpsertMetrics writes a progressive BenchmarkMetrics snapshot for a
+	// still-running run, replacing any previous snapshot in place, so
+	// GetMetricsByRunID and a Watch subscriber both see partial
+	// throughput/latency figures before the run reaches a terminal state —
+	// and, if the run crashes, an early read on what it had measured so
+	// far instead of nothing. It never marks the run completed or touches
+	// AcceleratorSamples; PersistMetrics remains the one call that does.
+	UpsertMetrics(ctx context.Context, runID string, m *BenchmarkMetrics) error
+	GetAcceleratorSamples(ctx context.Context, runID string) ([]AcceleratorSample, error)
 	GetBenchmarkRun(ctx context.Context, runID string) (*BenchmarkRun, error)
 	GetMetricsByRunID(ctx context.Context, runID string) (*BenchmarkMetrics, error)
-	ListCatalog(ctx context.Context, f CatalogFilter) ([]CatalogEntry, error)
-	ListRuns(ctx context.Context, f RunFilter) ([]RunListItem, error)
+	// ListCatalog and ListRuns also return the total number of rows
+	// matching f, ignoring f.Limit/f.Offset, so an HTTP caller can render
+	// "showing X-Y of total" and a paging caller can tell when it's seen
+	// the last page without guessing from a short final page. When
+	// f.ShardCount > 1, the returned total is only that shard's count —
+	// ListCatalogSharded recomputes the overall total itself after
+	// merging every shard's results.
+	ListCatalog(ctx context.Context, f CatalogFilter) ([]CatalogEntry, int, error)
+	ListRuns(ctx context.Context, f RunFilter) ([]RunListItem, int, error)
 	DeleteRun(ctx context.Context, runID string) error
 	UpsertPricing(ctx context.Context, p *Pricing) error
 	ListPricing(ctx context.Context, region string) ([]PricingRow, error)
+
+	// ListPricingAt is ListPricing pinned to a point in time instead of
+	// now: the pricing effective on asOf (largest effective_date <=
+	// asOf) for each (instance type, cloud provider) pair in region.
+	// PricingHistory returns the full time series for one instance type
+	// instead of collapsing to the most recent row, so a caller can see
+	// every price change recorded since since.
+	ListPricingAt(ctx context.Context, region string, asOf time.Time) ([]PricingRow, error)
+	PricingHistory(ctx context.Context, instanceTypeID, region string, since time.Time) ([]PricingRow, error)
 	ListInstanceTypes(ctx context.Context) ([]InstanceType, error)
+	Watch(ctx context.Context, runID string, sinceRev uint64) (<-chan RunEvent, error)
+	PublishRunLog(ctx context.Context, runID, line string) error
+	CreateSweep(ctx context.Context, runs []*BenchmarkRun) (sweepID string, runIDs []string, err error)
+	GetSweep(ctx context.Context, sweepID string) (*Sweep, error)
+	ListSweepRuns(ctx context.Context, sweepID string) ([]SweepRunDetail, error)
+
+	// CreateExperiment persists an ExperimentSpec's expanded runs (each
+	// already tagged with the arm it belongs to) under a single parent
+	// experiment ID, the same way CreateSweep groups a SweepRequest's
+	// expansion under a sweep ID. GetExperiment and ListRunsByExperiment
+	// are its read-side counterparts; see internal/analyzer for how a
+	// caller turns two arms' runs into a statistical comparison.
+	CreateExperiment(ctx context.Context, runs []*BenchmarkRun) (experimentID string, runIDs []string, err error)
+	GetExperiment(ctx context.Context, experimentID string) (*Experiment, error)
+	ListRunsByExperiment(ctx context.Context, experimentID string) ([]ExperimentRunDetail, error)
+
+	// CreateBenchmarkGroup persists a recurring benchmark definition;
+	// ListBenchmarkGroups is what the scheduler polls to know what to
+	// run and when. UpdateGroupLastRun stamps a group after the
+	// scheduler submits a tick's runs, and ListRunsByGroup finds every
+	// run a group has ever produced.
+	CreateBenchmarkGroup(ctx context.Context, group *BenchmarkGroup) (string, error)
+	ListBenchmarkGroups(ctx context.Context) ([]BenchmarkGroup, error)
+	UpdateGroupLastRun(ctx context.Context, groupID string, lastRun time.Time) error
+	ListRunsByGroup(ctx context.Context, groupID string) ([]RunListItem, error)
+
+	// CreatePolicy persists a recurring BenchmarkPolicy; ListPolicies is
+	// what internal/policy's dispatcher polls to know what to claim and
+	// when. UpdatePolicy replaces a policy's mutable fields (recomputing
+	// NextRunAt when CronExpr changes), and DeletePolicy removes one
+	// outright. Unlike BenchmarkGroup, a policy's schedule is cron-driven
+	// rather than a fixed interval.
+	CreatePolicy(ctx context.Context, policy *BenchmarkPolicy) (string, error)
+	UpdatePolicy(ctx context.Context, policyID string, policy *BenchmarkPolicy) error
+	ListPolicies(ctx context.Context) ([]BenchmarkPolicy, error)
+	DeletePolicy(ctx context.Context, policyID string) error
+
+	// ListRunsByParent returns every probe run created under parentRunID
+	// by orchestrator.ExecuteSLOSearch, newest first — the read-side
+	// counterpart to BenchmarkRun.ParentRunID.
+	ListRunsByParent(ctx context.Context, parentRunID string) ([]SLOSearchRunDetail, error)
+
+	// EnqueueRun admits runID into the persistent run queue and
+	// atomically moves its status to "queued", so a process restart
+	// doesn't lose a run the scheduler hasn't dispatched to the cluster
+	// yet and a caller never observes the run in the queue with a stale
+	// status; instanceFamily records which per-family concurrency cap it
+	// counts against, and userID (empty if the submitter carried no
+	// identity) records which per-user cap it counts against.
+	// ListQueuedRuns returns every still-queued run in dispatch order:
+	// priority descending, then EnqueuedAt ascending within a priority
+	// tier. DequeueRun removes runID once the scheduler has admitted it
+	// for execution, and SetRunPriority updates a still-queued run's
+	// priority in place.
+	EnqueueRun(ctx context.Context, runID, instanceFamily, userID string, priority int) error
+	ListQueuedRuns(ctx context.Context) ([]QueuedRun, error)
+	DequeueRun(ctx context.Context, runID string) error
+	SetRunPriority(ctx context.Context, runID string, priority int) error
+
+	// RecordQueueWait and RecordExecutionDuration stamp, respectively,
+	// how long a run spent waiting in the admission queue and how long
+	// it spent executing, onto its BenchmarkRun row.
+	RecordQueueWait(ctx context.Context, runID string, waitSeconds float64) error
+	RecordExecutionDuration(ctx context.Context, runID string, seconds float64) error
+
+	// AppendSamples records raw per-request time-series samples for a
+	// run. QueryRange averages a single metric over step-sized buckets
+	// in [from, to). Compact and ApplyRetention are meant to run on a
+	// schedule: Compact merges a completed run's chunks for storage
+	// efficiency, and ApplyRetention drops raw samples older than the
+	// backend's retention window (aggregated BenchmarkMetrics rows are
+	// never subject to retention). Both must be safe to call
+	// concurrently with ListRuns/ListCatalog and must never touch
+	// samples for a run that is still "running".
+	AppendSamples(ctx context.Context, runID string, samples []Sample) error
+	QueryRange(ctx context.Context, runID, metric string, from, to time.Time, step time.Duration) ([]Point, error)
+	Compact(ctx context.Context) error
+	ApplyRetention(ctx context.Context) error
+
+	// CompareRuns returns runIDA's and runIDB's raw per-request samples,
+	// in that order, for a caller (see internal/analyzer) to treat as a
+	// control/treatment A/B comparison.
+	CompareRuns(ctx context.Context, runIDA, runIDB string) (control, treatment []Sample, err error)
+
+	// VerifyRun runs integrity checks against a single run (sample
+	// monotonicity, percentile sanity, required-field completeness, and
+	// model/instance reference integrity) so a silently-corrupt run can be
+	// caught before it contaminates catalog comparisons. GatherCatalogHealth
+	// runs VerifyRun over every run a CatalogFilter selects, for an
+	// operator-driven sweep of the whole catalog. MarkRunHealthFailure
+	// supersedes a run and records why, as the non-destructive alternative
+	// to deleting it.
+	VerifyRun(ctx context.Context, runID string) (RunHealthStats, error)
+	GatherCatalogHealth(ctx context.Context, f CatalogFilter) ([]RunHealthStats, error)
+	MarkRunHealthFailure(ctx context.Context, runID, reason string) error
+
+	// QueryCatalog evaluates a read-only PromQL-style expr against the
+	// catalog as of at, replacing ad-hoc CatalogFilter fields for advanced
+	// queries while CatalogFilter remains the simple-case API. Each numeric
+	// CatalogEntry field (ttft_p50_ms, throughput_aggregate_tps,
+	// accelerator_utilization_pct, accelerator_count, ...) is a metric
+	// name; model/instance/framework/quantization/tensor-parallel-degree
+	// and the rest of CatalogEntry's dimensions are labels, so
+	// `ttft_p99_ms{model_family="llama", instance_family="p5"}` or
+	// `topk(5, throughput_aggregate_tps / accelerator_count)` work.
+	// Supported: instant selectors with =, !=, =~, !~; binary arithmetic
+	// and comparison operators; sum/avg/min/max/topk/bottomk with by/
+	// without; abs/clamp/ln; and quantile_over_time against a range
+	// selector (e.g. ttft_ms[5m]) backed by each run's raw Samples.
+	// QueryCatalogRange evaluates the same expr at each step between from
+	// and to, returning one Series per distinct label set.
+	QueryCatalog(ctx context.Context, expr string, at time.Time) (Vector, error)
+	QueryCatalogRange(ctx context.Context, expr string, from, to time.Time, step time.Duration) (Matrix, error)
+
+	// ListDueRunCallbacks returns up to limit pending run_callbacks rows
+	// whose NextAttemptAt is at or before now, for the callbacks package's
+	// worker to poll when its Repo doesn't implement RunCallbackClaimer.
+	// Unlike ClaimDueRunCallbacks it does not mark anything in-flight, so
+	// it's safe only when exactly one worker process is polling a given
+	// backend. RecordRunCallbackResult is its write-side counterpart,
+	// called after every delivery attempt whether it succeeded or not.
+	ListDueRunCallbacks(ctx context.Context, now time.Time, limit int) ([]RunCallback, error)
+	RecordRunCallbackResult(ctx context.Context, id string, deliveryErr error, nextAttemptAt *time.Time) error
 }
 
 // Compile-time check that *Repository implements Repo.
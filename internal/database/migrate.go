@@ -0,0 +1,52 @@
+package database
+
+import "context"
+
+// StoreSnapshot is the full portable contents of a Repo backend whose
+// state is not already durable in an external system, used by
+// cmd/migrate-store to move state between the in-memory, BoltDB, and
+// etcd backends. The Postgres-backed Repository has no Snapshot/Import
+// implementation: its state already lives in a normal SQL database and
+// is migrated with standard DB tooling (pg_dump, logical replication)
+// instead.
+type StoreSnapshot struct {
+	Models        []Model
+	InstanceTypes []InstanceType
+	Runs          []BenchmarkRun
+	Metrics       []BenchmarkMetrics
+	Sweeps        []Sweep
+	Experiments   []Experiment
+	Samples       []RunSamples
+	Groups        []BenchmarkGroup
+	Policies      []BenchmarkPolicy
+	Queue         []QueuedRun
+
+	AcceleratorSamples []RunAcceleratorSamples
+}
+
+// RunSamples is one run's raw time-series samples, grouped together in a
+// StoreSnapshot since Sample itself carries no run identifier.
+type RunSamples struct {
+	RunID   string
+	Samples []Sample
+}
+
+// RunAcceleratorSamples is one run's raw per-device accelerator samples,
+// grouped together the same way RunSamples groups per-request Samples.
+type RunAcceleratorSamples struct {
+	RunID   string
+	Samples []AcceleratorSample
+}
+
+// Snapshotter is implemented by Repo backends whose entire state can be
+// read out as a StoreSnapshot for migration to another backend.
+type Snapshotter interface {
+	Snapshot(ctx context.Context) (StoreSnapshot, error)
+}
+
+// Importer is implemented by durable Repo backends that can be bulk-
+// loaded from a StoreSnapshot, preserving the original IDs so
+// cross-references between runs, models, and instance types stay intact.
+type Importer interface {
+	Import(ctx context.Context, snap StoreSnapshot) error
+}
@@ -3,28 +3,57 @@ package database
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/accelbench/accelbench/internal/cronexpr"
+)
+
+// Compile-time check that *MockRepo implements Repo and Snapshotter.
+var (
+	_ Repo        = (*MockRepo)(nil)
+	_ Snapshotter = (*MockRepo)(nil)
 )
 
 // MockRepo is an in-memory implementation of Repo for testing.
 type MockRepo struct {
-	mu      sync.Mutex
-	models  map[string]*Model         // keyed by "hfID|revision"
-	instTypes map[string]*InstanceType // keyed by name
-	runs    map[string]*BenchmarkRun  // keyed by run ID
-	metrics map[string]*BenchmarkMetrics // keyed by run ID
-	nextID  int
+	mu           sync.Mutex
+	models       map[string]*Model            // keyed by "hfID|revision"
+	instTypes    map[string]*InstanceType     // keyed by name
+	runs         map[string]*BenchmarkRun     // keyed by run ID
+	metrics      map[string]*BenchmarkMetrics // keyed by run ID
+	sweeps       map[string]*Sweep            // keyed by sweep ID
+	experiments  map[string]*Experiment       // keyed by experiment ID
+	groups       map[string]*BenchmarkGroup   // keyed by group ID
+	policies     map[string]*BenchmarkPolicy  // keyed by policy ID
+	queue        map[string]*QueuedRun        // keyed by run ID
+	nextID       int
+	broker       *Broker
+	samples      *sampleStore
+	accelSamples map[string][]AcceleratorSample // keyed by run ID
+	callbacks    map[string]*RunCallback        // keyed by callback ID
+	pricing      map[string]*Pricing            // keyed by mockPricingKey(instanceTypeID, cloudProvider, region, effectiveDate)
 }
 
 // NewMockRepo creates a new MockRepo.
 func NewMockRepo() *MockRepo {
 	return &MockRepo{
-		models:    make(map[string]*Model),
-		instTypes: make(map[string]*InstanceType),
-		runs:      make(map[string]*BenchmarkRun),
-		metrics:   make(map[string]*BenchmarkMetrics),
+		models:       make(map[string]*Model),
+		instTypes:    make(map[string]*InstanceType),
+		runs:         make(map[string]*BenchmarkRun),
+		metrics:      make(map[string]*BenchmarkMetrics),
+		sweeps:       make(map[string]*Sweep),
+		experiments:  make(map[string]*Experiment),
+		groups:       make(map[string]*BenchmarkGroup),
+		policies:     make(map[string]*BenchmarkPolicy),
+		queue:        make(map[string]*QueuedRun),
+		broker:       NewBroker(),
+		samples:      newSampleStore(),
+		accelSamples: make(map[string][]AcceleratorSample),
+		callbacks:    make(map[string]*RunCallback),
+		pricing:      make(map[string]*Pricing),
 	}
 }
 
@@ -91,6 +120,10 @@ func (m *MockRepo) CreateBenchmarkRun(_ context.Context, run *BenchmarkRun) (str
 	id := fmt.Sprintf("run-%08d", m.nextID)
 	run.ID = id
 	run.CreatedAt = time.Now()
+	if run.TimeoutSeconds > 0 {
+		d := run.CreatedAt.Add(time.Duration(run.TimeoutSeconds) * time.Second)
+		run.DeadlineAt = &d
+	}
 	m.runs[id] = run
 	return id, nil
 }
@@ -109,11 +142,202 @@ func (m *MockRepo) UpdateRunStatus(_ context.Context, runID, status string) erro
 		run.StartedAt = &now
 	case "completed", "failed":
 		run.CompletedAt = &now
+		m.enqueueRunCallbackLocked(run, status)
 	}
+	m.broker.Publish(runID, status, nil)
 	return nil
 }
 
-func (m *MockRepo) PersistMetrics(_ context.Context, runID string, bm *BenchmarkMetrics) error {
+// enqueueRunCallbackLocked queues a RunCallback for run if it carries a
+// CallbackURL, called with m.mu already held from the terminal branch of
+// UpdateRunStatus/CancelRun/PersistMetrics. No-op when run has no CallbackURL.
+func (m *MockRepo) enqueueRunCallbackLocked(run *BenchmarkRun, status string) {
+	if run.CallbackURL == "" {
+		return
+	}
+	m.nextID++
+	id := fmt.Sprintf("cb-%08d", m.nextID)
+	m.callbacks[id] = &RunCallback{
+		ID:            id,
+		RunID:         run.ID,
+		URL:           run.CallbackURL,
+		Token:         run.CallbackToken,
+		Status:        status,
+		Summary:       summarizeMetrics(m.metrics[run.ID]),
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+	}
+}
+
+// ListDueRunCallbacks returns every pending callback whose NextAttemptAt
+// is at or before now.
+func (m *MockRepo) ListDueRunCallbacks(_ context.Context, now time.Time, limit int) ([]RunCallback, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []RunCallback
+	for _, cb := range m.callbacks {
+		if cb.DeliveredAt == nil && !cb.NextAttemptAt.After(now) && cb.Attempts < MaxCallbackAttempts {
+			due = append(due, *cb)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+// RecordRunCallbackResult records the outcome of a delivery attempt:
+// deliveryErr nil marks id delivered; otherwise it bumps Attempts,
+// stashes deliveryErr's message, and schedules the next retry at
+// nextAttemptAt (nil once MaxCallbackAttempts is exhausted, leaving the
+// row permanently pending for an operator to notice).
+func (m *MockRepo) RecordRunCallbackResult(_ context.Context, id string, deliveryErr error, nextAttemptAt *time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cb, ok := m.callbacks[id]
+	if !ok {
+		return fmt.Errorf("run callback %s not found", id)
+	}
+	if deliveryErr == nil {
+		now := time.Now()
+		cb.DeliveredAt = &now
+		cb.LastError = ""
+		return nil
+	}
+	cb.Attempts++
+	cb.LastError = deliveryErr.Error()
+	if nextAttemptAt != nil {
+		cb.NextAttemptAt = *nextAttemptAt
+	}
+	return nil
+}
+
+// UpdateRunFailureReason stamps runID's FailureReason.
+func (m *MockRepo) UpdateRunFailureReason(_ context.Context, runID, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[runID]
+	if !ok {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.FailureReason = &reason
+	return nil
+}
+
+// CancelRun atomically moves runID to "failed" if and only if it is still
+// "queued", "pending" or "running", and drops it from the queue, matching
+// Repository's CAS semantics under the mock's mutex.
+func (m *MockRepo) CancelRun(_ context.Context, runID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[runID]
+	if !ok {
+		return ErrRunNotFound
+	}
+	if run.Status != "pending" && run.Status != "running" && run.Status != "queued" {
+		return ErrRunNotCancellable
+	}
+	run.Status = "failed"
+	now := time.Now()
+	run.CompletedAt = &now
+	delete(m.queue, runID)
+	m.enqueueRunCallbackLocked(run, "failed")
+	m.broker.Publish(runID, "failed", nil)
+	return nil
+}
+
+func (m *MockRepo) UpdateRunPluginDigests(_ context.Context, runID string, digests map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[runID]
+	if !ok {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.PluginDigests = digests
+	return nil
+}
+
+// UpdateRunPhase stamps runID's current lifecycle phase and, if resources
+// is non-nil, merges it into the run's persisted K8sResourceRefs — see
+// mergeK8sResourceRefs.
+func (m *MockRepo) UpdateRunPhase(_ context.Context, runID, phase string, resources *K8sResourceRefs) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[runID]
+	if !ok {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.Phase = phase
+	if resources != nil {
+		if run.K8sResources == nil {
+			run.K8sResources = resources
+		} else {
+			run.K8sResources = mergeK8sResourceRefs(run.K8sResources, resources)
+		}
+	}
+	return nil
+}
+
+// SetTeardownPending marks whether runID's Kubernetes resources still need
+// tearing down.
+func (m *MockRepo) SetTeardownPending(_ context.Context, runID string, pending bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[runID]
+	if !ok {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.TeardownPending = pending
+	return nil
+}
+
+// ListNonTerminalRuns returns every run whose status is "running", for
+// Resume to rebind to on startup after a crash or restart.
+func (m *MockRepo) ListNonTerminalRuns(_ context.Context) ([]BenchmarkRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var runs []BenchmarkRun
+	for _, run := range m.runs {
+		if run.Status == "running" {
+			runs = append(runs, *run)
+		}
+	}
+	return runs, nil
+}
+
+// ListExpiredRuns returns every queued, pending, or running run whose
+// DeadlineAt is non-nil and strictly before now.
+func (m *MockRepo) ListExpiredRuns(_ context.Context, now time.Time) ([]BenchmarkRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var runs []BenchmarkRun
+	for _, run := range m.runs {
+		if (run.Status == "queued" || run.Status == "pending" || run.Status == "running") &&
+			run.DeadlineAt != nil && run.DeadlineAt.Before(now) {
+			runs = append(runs, *run)
+		}
+	}
+	return runs, nil
+}
+
+// ExtendDeadline pushes runID's DeadlineAt out to newDeadline, matching
+// Repository's CAS semantics under the mock's mutex.
+func (m *MockRepo) ExtendDeadline(_ context.Context, runID string, newDeadline time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[runID]
+	if !ok {
+		return ErrRunNotFound
+	}
+	if run.Status != "queued" && run.Status != "pending" && run.Status != "running" {
+		return ErrRunNotExtendable
+	}
+	run.DeadlineAt = &newDeadline
+	return nil
+}
+
+func (m *MockRepo) PersistMetrics(_ context.Context, runID string, bm *BenchmarkMetrics, samples []AcceleratorSample) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	run, ok := m.runs[runID]
@@ -124,9 +348,49 @@ func (m *MockRepo) PersistMetrics(_ context.Context, runID string, bm *Benchmark
 	bm.ID = fmt.Sprintf("met-%08d", m.nextID+1)
 	bm.CreatedAt = time.Now()
 	m.metrics[runID] = bm
+	if len(samples) > 0 {
+		m.accelSamples[runID] = samples
+	}
 	run.Status = "completed"
 	now := time.Now()
 	run.CompletedAt = &now
+	m.enqueueRunCallbackLocked(run, "completed")
+	m.broker.Publish(runID, "completed", bm)
+	return nil
+}
+
+// UpsertMetrics writes a progressive metrics snapshot for runID without
+// marking the run completed, replacing any previous snapshot — the
+// in-memory counterpart to Repository.UpsertMetrics.
+func (m *MockRepo) UpsertMetrics(_ context.Context, runID string, bm *BenchmarkMetrics) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.runs[runID]; !ok {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	bm.RunID = runID
+	m.metrics[runID] = bm
+	m.broker.Publish(runID, "running", bm)
+	return nil
+}
+
+// GetAcceleratorSamples returns runID's raw accelerator samples, or nil if
+// none were recorded.
+func (m *MockRepo) GetAcceleratorSamples(_ context.Context, runID string) ([]AcceleratorSample, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.accelSamples[runID], nil
+}
+
+// Watch subscribes to lifecycle and metrics events for runID.
+func (m *MockRepo) Watch(ctx context.Context, runID string, sinceRev uint64) (<-chan RunEvent, error) {
+	return m.broker.Watch(ctx, runID, sinceRev)
+}
+
+// PublishRunLog fans an orchestrator log line for runID out to anyone
+// watching it, without persisting it anywhere.
+func (m *MockRepo) PublishRunLog(ctx context.Context, runID, line string) error {
+	m.broker.PublishLog(runID, line)
 	return nil
 }
 
@@ -142,14 +406,18 @@ func (m *MockRepo) GetMetricsByRunID(_ context.Context, runID string) (*Benchmar
 	return m.metrics[runID], nil
 }
 
-// ListRuns returns benchmark runs matching the given filter.
-func (m *MockRepo) ListRuns(_ context.Context, f RunFilter) ([]RunListItem, error) {
+// ListRuns returns benchmark runs matching the given filter, along with
+// the total number of runs matching f (ignoring f.Limit/f.Offset).
+func (m *MockRepo) ListRuns(_ context.Context, f RunFilter) ([]RunListItem, int, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	var items []RunListItem
 	for _, run := range m.runs {
-		if f.Status != "" && run.Status != f.Status {
+		if !runStatusMatches(run.Status, f) {
+			continue
+		}
+		if !shardMatch(run.ID, f.ShardID, f.ShardCount) {
 			continue
 		}
 
@@ -168,14 +436,17 @@ func (m *MockRepo) ListRuns(_ context.Context, f RunFilter) ([]RunListItem, erro
 			continue
 		}
 
-		// Resolve instance type name.
-		var instName string
+		// Resolve instance type for its name, family, and accelerator type.
+		var instName, instFamily, instAccelType string
 		for _, it := range m.instTypes {
 			if it.ID == run.InstanceTypeID {
-				instName = it.Name
+				instName, instFamily, instAccelType = it.Name, it.Family, it.AcceleratorType
 				break
 			}
 		}
+		if !runMatchesFilter(run, instFamily, instAccelType, f) {
+			continue
+		}
 
 		items = append(items, RunListItem{
 			ID:               run.ID,
@@ -190,21 +461,8 @@ func (m *MockRepo) ListRuns(_ context.Context, f RunFilter) ([]RunListItem, erro
 		})
 	}
 
-	// Apply limit.
-	limit := 50
-	if f.Limit > 0 && f.Limit <= 200 {
-		limit = f.Limit
-	}
-	if f.Offset > 0 && f.Offset < len(items) {
-		items = items[f.Offset:]
-	} else if f.Offset >= len(items) {
-		return nil, nil
-	}
-	if len(items) > limit {
-		items = items[:limit]
-	}
-
-	return items, nil
+	items, total := paginateRunItems(items, f)
+	return items, total, nil
 }
 
 // DeleteRun removes a benchmark run and its metrics from the mock store.
@@ -216,15 +474,17 @@ func (m *MockRepo) DeleteRun(_ context.Context, runID string) error {
 	return nil
 }
 
-// ListCatalog returns catalog entries matching the given filter.
-// This is a simplified in-memory implementation for testing.
-func (m *MockRepo) ListCatalog(_ context.Context, f CatalogFilter) ([]CatalogEntry, error) {
+// ListCatalog returns catalog entries matching the given filter, along
+// with the total number of entries matching f (ignoring
+// f.Limit/f.Offset). This is a simplified in-memory implementation for
+// testing.
+func (m *MockRepo) ListCatalog(_ context.Context, f CatalogFilter) ([]CatalogEntry, int, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	var entries []CatalogEntry
 	for runID, run := range m.runs {
-		if run.Status != "completed" || run.Superseded || run.RunType != "catalog" {
+		if run.Status != "completed" || run.Superseded {
 			continue
 		}
 		met := m.metrics[runID]
@@ -269,6 +529,12 @@ func (m *MockRepo) ListCatalog(_ context.Context, f CatalogFilter) ([]CatalogEnt
 		if f.AcceleratorType != "" && inst.AcceleratorType != f.AcceleratorType {
 			continue
 		}
+		if f.GroupID != "" && (run.GroupID == nil || *run.GroupID != f.GroupID) {
+			continue
+		}
+		if !shardMatch(runID, f.ShardID, f.ShardCount) {
+			continue
+		}
 
 		entries = append(entries, CatalogEntry{
 			RunID:                     runID,
@@ -289,6 +555,8 @@ func (m *MockRepo) ListCatalog(_ context.Context, f CatalogFilter) ([]CatalogEnt
 			InputSequenceLength:       run.InputSequenceLength,
 			OutputSequenceLength:      run.OutputSequenceLength,
 			CompletedAt:               run.CompletedAt,
+			GroupID:                   run.GroupID,
+			TemplateIndex:             run.TemplateIndex,
 			TTFTP50Ms:                 met.TTFTP50Ms,
 			TTFTP99Ms:                 met.TTFTP99Ms,
 			E2ELatencyP50Ms:           met.E2ELatencyP50Ms,
@@ -300,9 +568,27 @@ func (m *MockRepo) ListCatalog(_ context.Context, f CatalogFilter) ([]CatalogEnt
 			RequestsPerSecond:         met.RequestsPerSecond,
 			AcceleratorUtilizationPct: met.AcceleratorUtilizationPct,
 			AcceleratorMemoryPeakGiB:  met.AcceleratorMemoryPeakGiB,
+			ServerTTFTP50Ms:           met.ServerTTFTP50Ms,
+			ServerTTFTP99Ms:           met.ServerTTFTP99Ms,
+			ServerE2ELatencyP50Ms:     met.ServerE2ELatencyP50Ms,
+			ServerE2ELatencyP99Ms:     met.ServerE2ELatencyP99Ms,
+			SMActivePeakPct:           met.SMActivePeakPct,
 		})
 	}
 
+	if f.LatestPerGroup {
+		entries = filterLatestPerGroup(entries)
+	}
+
+	// A sharded call returns its whole shard unpaginated; the caller
+	// (ListCatalogSharded) applies Limit/Offset once after merging every
+	// shard's results.
+	if f.ShardCount > 1 {
+		return entries, len(entries), nil
+	}
+
+	total := len(entries)
+
 	// Apply limit.
 	limit := 100
 	if f.Limit > 0 && f.Limit <= 500 {
@@ -311,11 +597,656 @@ func (m *MockRepo) ListCatalog(_ context.Context, f CatalogFilter) ([]CatalogEnt
 	if f.Offset > 0 && f.Offset < len(entries) {
 		entries = entries[f.Offset:]
 	} else if f.Offset >= len(entries) {
-		return nil, nil
+		return nil, total, nil
 	}
 	if len(entries) > limit {
 		entries = entries[:limit]
 	}
 
-	return entries, nil
+	return entries, total, nil
+}
+
+// mockPricingKey mirrors boltstore's pricingKey (string instead of []byte,
+// since MockRepo keys an in-memory map rather than a Bolt bucket): a
+// Pricing row is keyed by (instance_type_id, cloud_provider, region,
+// effective_date), so the same instance type can carry one row per
+// cloud/region it's priced on.
+func mockPricingKey(instanceTypeID, cloudProvider, region, effectiveDate string) string {
+	return instanceTypeID + "|" + cloudProvider + "|" + region + "|" + effectiveDate
+}
+
+// UpsertPricing inserts or updates a pricing row keyed by
+// (instance_type_id, cloud_provider, region, effective_date).
+func (m *MockRepo) UpsertPricing(_ context.Context, p *Pricing) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := mockPricingKey(p.InstanceTypeID, p.CloudProvider, p.Region, p.EffectiveDate)
+	if existing, ok := m.pricing[key]; ok {
+		p.ID = existing.ID
+		p.CreatedAt = existing.CreatedAt
+	} else {
+		m.nextID++
+		p.ID = fmt.Sprintf("price-%08d", m.nextID)
+		p.CreatedAt = time.Now()
+	}
+	stored := *p
+	m.pricing[key] = &stored
+	return nil
+}
+
+// instanceTypeByID returns the instance type with the given ID, or nil if
+// none is seeded under that ID, mirroring BoltRepo.instanceTypeByID.
+func (m *MockRepo) instanceTypeByID(id string) *InstanceType {
+	for _, it := range m.instTypes {
+		if it.ID == id {
+			return it
+		}
+	}
+	return nil
+}
+
+// ListPricing returns the most recent pricing for each (instance type,
+// cloud provider) pair in region, joined with the instance type name,
+// mirroring Repository.ListPricing's "most recent effective_date" grouping.
+func (m *MockRepo) ListPricing(_ context.Context, region string) ([]PricingRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	latest := make(map[string]*Pricing) // keyed by instance_type_id|cloud_provider
+	for _, p := range m.pricing {
+		if p.Region != region {
+			continue
+		}
+		key := p.InstanceTypeID + "|" + p.CloudProvider
+		if cur, ok := latest[key]; !ok || p.EffectiveDate > cur.EffectiveDate {
+			latest[key] = p
+		}
+	}
+	return m.pricingRows(latest), nil
+}
+
+// ListPricingAt is ListPricing pinned to asOf instead of now: the row with
+// the largest effective_date <= asOf for each (instance type, cloud
+// provider) pair in region.
+func (m *MockRepo) ListPricingAt(_ context.Context, region string, asOf time.Time) ([]PricingRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	asOfDate := asOf.Format("2006-01-02")
+	latest := make(map[string]*Pricing) // keyed by instance_type_id|cloud_provider
+	for _, p := range m.pricing {
+		if p.Region != region || p.EffectiveDate > asOfDate {
+			continue
+		}
+		key := p.InstanceTypeID + "|" + p.CloudProvider
+		if cur, ok := latest[key]; !ok || p.EffectiveDate > cur.EffectiveDate {
+			latest[key] = p
+		}
+	}
+	return m.pricingRows(latest), nil
+}
+
+// pricingRows resolves each entry of latest (keyed by
+// instance_type_id|cloud_provider) to a PricingRow, dropping rows whose
+// instance type is no longer seeded, sorted the same way Repository's SQL
+// ORDER BY it.name, p.cloud_provider does.
+func (m *MockRepo) pricingRows(latest map[string]*Pricing) []PricingRow {
+	var result []PricingRow
+	for _, p := range latest {
+		inst := m.instanceTypeByID(p.InstanceTypeID)
+		if inst == nil {
+			continue
+		}
+		result = append(result, PricingRow{
+			InstanceTypeName:     inst.Name,
+			CloudProvider:        p.CloudProvider,
+			OnDemandHourlyUSD:    p.OnDemandHourlyUSD,
+			Reserved1YrHourlyUSD: p.Reserved1YrHourlyUSD,
+			Reserved3YrHourlyUSD: p.Reserved3YrHourlyUSD,
+			SpotHourlyUSD:        p.SpotHourlyUSD,
+			EffectiveDate:        p.EffectiveDate,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].InstanceTypeName != result[j].InstanceTypeName {
+			return result[i].InstanceTypeName < result[j].InstanceTypeName
+		}
+		return result[i].CloudProvider < result[j].CloudProvider
+	})
+	return result
+}
+
+// PricingHistory returns every pricing row recorded for instanceTypeID in
+// region on or after since, ordered by effective date.
+func (m *MockRepo) PricingHistory(_ context.Context, instanceTypeID, region string, since time.Time) ([]PricingRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inst := m.instanceTypeByID(instanceTypeID)
+	if inst == nil {
+		return nil, nil
+	}
+	sinceDate := since.Format("2006-01-02")
+	var result []PricingRow
+	for _, p := range m.pricing {
+		if p.InstanceTypeID != instanceTypeID || p.Region != region || p.EffectiveDate < sinceDate {
+			continue
+		}
+		result = append(result, PricingRow{
+			InstanceTypeName:     inst.Name,
+			CloudProvider:        p.CloudProvider,
+			OnDemandHourlyUSD:    p.OnDemandHourlyUSD,
+			Reserved1YrHourlyUSD: p.Reserved1YrHourlyUSD,
+			Reserved3YrHourlyUSD: p.Reserved3YrHourlyUSD,
+			SpotHourlyUSD:        p.SpotHourlyUSD,
+			EffectiveDate:        p.EffectiveDate,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].EffectiveDate != result[j].EffectiveDate {
+			return result[i].EffectiveDate < result[j].EffectiveDate
+		}
+		return result[i].CloudProvider < result[j].CloudProvider
+	})
+	return result, nil
+}
+
+// ListInstanceTypes returns all seeded instance types, sorted by name.
+func (m *MockRepo) ListInstanceTypes(_ context.Context) ([]InstanceType, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]InstanceType, 0, len(m.instTypes))
+	for _, it := range m.instTypes {
+		result = append(result, *it)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// CreateSweep inserts a sweep and all of its child runs into the mock
+// store, mirroring Repository.CreateSweep's atomicity: since MockRepo
+// operates entirely under m.mu, either all runs are recorded or (on error)
+// none are visible to a concurrent caller.
+func (m *MockRepo) CreateSweep(_ context.Context, runs []*BenchmarkRun) (string, []string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	sweepID := fmt.Sprintf("sweep-%08d", m.nextID)
+	m.sweeps[sweepID] = &Sweep{ID: sweepID, CreatedAt: time.Now()}
+
+	runIDs := make([]string, 0, len(runs))
+	for _, run := range runs {
+		m.nextID++
+		id := fmt.Sprintf("run-%08d", m.nextID)
+		run.ID = id
+		run.SweepID = &sweepID
+		run.CreatedAt = time.Now()
+		m.runs[id] = run
+		runIDs = append(runIDs, id)
+	}
+	return sweepID, runIDs, nil
+}
+
+// CreateExperiment persists an ExperimentSpec's expanded runs (each
+// already tagged with the arm it belongs to) under a single parent
+// experiment ID, mirroring CreateSweep.
+func (m *MockRepo) CreateExperiment(_ context.Context, runs []*BenchmarkRun) (string, []string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	experimentID := fmt.Sprintf("experiment-%08d", m.nextID)
+	m.experiments[experimentID] = &Experiment{ID: experimentID, CreatedAt: time.Now()}
+
+	runIDs := make([]string, 0, len(runs))
+	for _, run := range runs {
+		m.nextID++
+		id := fmt.Sprintf("run-%08d", m.nextID)
+		run.ID = id
+		run.ExperimentID = &experimentID
+		run.CreatedAt = time.Now()
+		m.runs[id] = run
+		runIDs = append(runIDs, id)
+	}
+	return experimentID, runIDs, nil
+}
+
+// Snapshot reads out MockRepo's contents for migration to a durable
+// backend via Import, e.g. to promote a quick-start deployment running
+// with --store=memory onto BoltDB or etcd without losing its runs. Run
+// callbacks and pricing rows are not included: StoreSnapshot has no field
+// for them.
+func (m *MockRepo) Snapshot(_ context.Context) (StoreSnapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var snap StoreSnapshot
+	for _, model := range m.models {
+		snap.Models = append(snap.Models, *model)
+	}
+	for _, it := range m.instTypes {
+		snap.InstanceTypes = append(snap.InstanceTypes, *it)
+	}
+	for _, run := range m.runs {
+		snap.Runs = append(snap.Runs, *run)
+	}
+	for _, met := range m.metrics {
+		snap.Metrics = append(snap.Metrics, *met)
+	}
+	for _, sweep := range m.sweeps {
+		snap.Sweeps = append(snap.Sweeps, *sweep)
+	}
+	for _, exp := range m.experiments {
+		snap.Experiments = append(snap.Experiments, *exp)
+	}
+	for _, runID := range m.samples.runIDs() {
+		if samples := m.samples.all(runID); len(samples) > 0 {
+			snap.Samples = append(snap.Samples, RunSamples{RunID: runID, Samples: samples})
+		}
+	}
+	for runID, samples := range m.accelSamples {
+		snap.AcceleratorSamples = append(snap.AcceleratorSamples, RunAcceleratorSamples{RunID: runID, Samples: samples})
+	}
+	for _, g := range m.groups {
+		snap.Groups = append(snap.Groups, *g)
+	}
+	for _, p := range m.policies {
+		snap.Policies = append(snap.Policies, *p)
+	}
+	for _, q := range m.queue {
+		snap.Queue = append(snap.Queue, *q)
+	}
+	return snap, nil
+}
+
+// GetSweep returns a sweep by ID, or nil if not found.
+func (m *MockRepo) GetSweep(_ context.Context, sweepID string) (*Sweep, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sweeps[sweepID], nil
+}
+
+// ListSweepRuns returns every child run of sweepID.
+func (m *MockRepo) ListSweepRuns(_ context.Context, sweepID string) ([]SweepRunDetail, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var details []SweepRunDetail
+	for _, run := range m.runs {
+		if run.SweepID == nil || *run.SweepID != sweepID {
+			continue
+		}
+
+		var modelHfID string
+		for _, mdl := range m.models {
+			if mdl.ID == run.ModelID {
+				modelHfID = mdl.HfID
+				break
+			}
+		}
+		var instName string
+		for _, it := range m.instTypes {
+			if it.ID == run.InstanceTypeID {
+				instName = it.Name
+				break
+			}
+		}
+
+		details = append(details, SweepRunDetail{
+			RunListItem: RunListItem{
+				ID:               run.ID,
+				ModelHfID:        modelHfID,
+				InstanceTypeName: instName,
+				Framework:        run.Framework,
+				RunType:          run.RunType,
+				Status:           run.Status,
+				CreatedAt:        run.CreatedAt,
+				StartedAt:        run.StartedAt,
+				CompletedAt:      run.CompletedAt,
+			},
+			Concurrency:          run.Concurrency,
+			TensorParallelDegree: run.TensorParallelDegree,
+			InputSequenceLength:  run.InputSequenceLength,
+			OutputSequenceLength: run.OutputSequenceLength,
+			Quantization:         run.Quantization,
+			Metrics:              m.metrics[run.ID],
+		})
+	}
+
+	sort.Slice(details, func(i, j int) bool { return details[i].CreatedAt.Before(details[j].CreatedAt) })
+	return details, nil
+}
+
+// GetExperiment returns an experiment by ID, or nil if not found.
+func (m *MockRepo) GetExperiment(_ context.Context, experimentID string) (*Experiment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.experiments[experimentID], nil
+}
+
+// ListRunsByExperiment returns every child run of experimentID tagged with
+// its arm, oldest first.
+func (m *MockRepo) ListRunsByExperiment(_ context.Context, experimentID string) ([]ExperimentRunDetail, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var details []ExperimentRunDetail
+	for _, run := range m.runs {
+		if run.ExperimentID == nil || *run.ExperimentID != experimentID {
+			continue
+		}
+
+		var modelHfID string
+		for _, mdl := range m.models {
+			if mdl.ID == run.ModelID {
+				modelHfID = mdl.HfID
+				break
+			}
+		}
+		var instName string
+		for _, it := range m.instTypes {
+			if it.ID == run.InstanceTypeID {
+				instName = it.Name
+				break
+			}
+		}
+
+		details = append(details, ExperimentRunDetail{
+			RunListItem: RunListItem{
+				ID:               run.ID,
+				ModelHfID:        modelHfID,
+				InstanceTypeName: instName,
+				Framework:        run.Framework,
+				RunType:          run.RunType,
+				Status:           run.Status,
+				CreatedAt:        run.CreatedAt,
+				StartedAt:        run.StartedAt,
+				CompletedAt:      run.CompletedAt,
+			},
+			Arm: run.Arm,
+		})
+	}
+
+	sort.Slice(details, func(i, j int) bool { return details[i].CreatedAt.Before(details[j].CreatedAt) })
+	return details, nil
+}
+
+// CreateBenchmarkGroup persists a new BenchmarkGroup definition.
+func (m *MockRepo) CreateBenchmarkGroup(_ context.Context, group *BenchmarkGroup) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	id := fmt.Sprintf("group-%08d", m.nextID)
+	group.ID = id
+	group.CreatedAt = time.Now()
+	m.groups[id] = group
+	return id, nil
+}
+
+// ListBenchmarkGroups returns every persisted BenchmarkGroup.
+func (m *MockRepo) ListBenchmarkGroups(_ context.Context) ([]BenchmarkGroup, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	groups := make([]BenchmarkGroup, 0, len(m.groups))
+	for _, g := range m.groups {
+		groups = append(groups, *g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].CreatedAt.Before(groups[j].CreatedAt) })
+	return groups, nil
+}
+
+// UpdateGroupLastRun stamps groupID's last_run_at.
+func (m *MockRepo) UpdateGroupLastRun(_ context.Context, groupID string, lastRun time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, ok := m.groups[groupID]
+	if !ok {
+		return fmt.Errorf("benchmark group %s not found", groupID)
+	}
+	t := lastRun
+	g.LastRunAt = &t
+	return nil
+}
+
+// ListRunsByGroup returns every run tagged with groupID, newest first.
+func (m *MockRepo) ListRunsByGroup(_ context.Context, groupID string) ([]RunListItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var items []RunListItem
+	for _, run := range m.runs {
+		if run.GroupID == nil || *run.GroupID != groupID {
+			continue
+		}
+
+		var modelHfID string
+		for _, mdl := range m.models {
+			if mdl.ID == run.ModelID {
+				modelHfID = mdl.HfID
+				break
+			}
+		}
+		var instName string
+		for _, it := range m.instTypes {
+			if it.ID == run.InstanceTypeID {
+				instName = it.Name
+				break
+			}
+		}
+
+		items = append(items, RunListItem{
+			ID:               run.ID,
+			ModelHfID:        modelHfID,
+			InstanceTypeName: instName,
+			Framework:        run.Framework,
+			RunType:          run.RunType,
+			Status:           run.Status,
+			CreatedAt:        run.CreatedAt,
+			StartedAt:        run.StartedAt,
+			CompletedAt:      run.CompletedAt,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt.After(items[j].CreatedAt) })
+	return items, nil
+}
+
+// CreatePolicy persists a new BenchmarkPolicy definition.
+func (m *MockRepo) CreatePolicy(_ context.Context, policy *BenchmarkPolicy) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if policy.Trigger == "scheduled" {
+		next, err := cronexpr.Next(policy.CronExpr, time.Now().UTC())
+		if err != nil {
+			return "", fmt.Errorf("compute next run: %w", err)
+		}
+		policy.NextRunAt = &next
+	}
+
+	m.nextID++
+	id := fmt.Sprintf("policy-%08d", m.nextID)
+	policy.ID = id
+	policy.CreatedAt = time.Now()
+	m.policies[id] = policy
+	return id, nil
+}
+
+// UpdatePolicy replaces policyID's mutable fields.
+func (m *MockRepo) UpdatePolicy(_ context.Context, policyID string, policy *BenchmarkPolicy) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.policies[policyID]
+	if !ok {
+		return ErrPolicyNotFound
+	}
+
+	if policy.Trigger == "scheduled" {
+		next, err := cronexpr.Next(policy.CronExpr, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("compute next run: %w", err)
+		}
+		policy.NextRunAt = &next
+	} else {
+		policy.NextRunAt = nil
+	}
+
+	policy.ID = existing.ID
+	policy.CreatedAt = existing.CreatedAt
+	policy.LastRunAt = existing.LastRunAt
+	m.policies[policyID] = policy
+	return nil
+}
+
+// ListPolicies returns every persisted BenchmarkPolicy.
+func (m *MockRepo) ListPolicies(_ context.Context) ([]BenchmarkPolicy, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	policies := make([]BenchmarkPolicy, 0, len(m.policies))
+	for _, p := range m.policies {
+		policies = append(policies, *p)
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].CreatedAt.Before(policies[j].CreatedAt) })
+	return policies, nil
+}
+
+// DeletePolicy removes a BenchmarkPolicy.
+func (m *MockRepo) DeletePolicy(_ context.Context, policyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.policies, policyID)
+	return nil
+}
+
+// ListRunsByParent returns every probe run created under parentRunID,
+// newest first, each with the concurrency it probed and the metrics it
+// produced.
+func (m *MockRepo) ListRunsByParent(_ context.Context, parentRunID string) ([]SLOSearchRunDetail, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var details []SLOSearchRunDetail
+	for _, run := range m.runs {
+		if run.ParentRunID == nil || *run.ParentRunID != parentRunID {
+			continue
+		}
+
+		var modelHfID string
+		for _, mdl := range m.models {
+			if mdl.ID == run.ModelID {
+				modelHfID = mdl.HfID
+				break
+			}
+		}
+		var instName string
+		for _, it := range m.instTypes {
+			if it.ID == run.InstanceTypeID {
+				instName = it.Name
+				break
+			}
+		}
+
+		details = append(details, SLOSearchRunDetail{
+			RunListItem: RunListItem{
+				ID:               run.ID,
+				ModelHfID:        modelHfID,
+				InstanceTypeName: instName,
+				Framework:        run.Framework,
+				RunType:          run.RunType,
+				Status:           run.Status,
+				CreatedAt:        run.CreatedAt,
+				StartedAt:        run.StartedAt,
+				CompletedAt:      run.CompletedAt,
+			},
+			Concurrency: run.Concurrency,
+			Metrics:     m.metrics[run.ID],
+		})
+	}
+
+	sort.Slice(details, func(i, j int) bool { return details[i].CreatedAt.After(details[j].CreatedAt) })
+	return details, nil
+}
+
+// EnqueueRun admits runID into the mock's in-memory queue and atomically
+// moves its run to status "queued" with QueuedAt stamped, so a caller
+// never observes the run sitting in the queue while its status still
+// reads a stale value (e.g. right after Scheduler.Requeue re-admits a
+// completed run).
+func (m *MockRepo) EnqueueRun(_ context.Context, runID, instanceFamily, userID string, priority int) error {
+	m.mu.Lock()
+	now := time.Now()
+	m.queue[runID] = &QueuedRun{RunID: runID, InstanceFamily: instanceFamily, UserID: userID, Priority: priority, EnqueuedAt: now}
+	if run, ok := m.runs[runID]; ok {
+		run.Status = "queued"
+		run.QueuedAt = &now
+	}
+	m.mu.Unlock()
+	m.broker.Publish(runID, "queued", nil)
+	return nil
+}
+
+// ListQueuedRuns returns every still-queued run, priority descending then
+// EnqueuedAt ascending.
+func (m *MockRepo) ListQueuedRuns(_ context.Context) ([]QueuedRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	queued := make([]QueuedRun, 0, len(m.queue))
+	for _, q := range m.queue {
+		queued = append(queued, *q)
+	}
+	sort.Slice(queued, func(i, j int) bool {
+		if queued[i].Priority != queued[j].Priority {
+			return queued[i].Priority > queued[j].Priority
+		}
+		return queued[i].EnqueuedAt.Before(queued[j].EnqueuedAt)
+	})
+	return queued, nil
+}
+
+// DequeueRun removes runID from the in-memory queue.
+func (m *MockRepo) DequeueRun(_ context.Context, runID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.queue, runID)
+	return nil
+}
+
+// SetRunPriority updates a still-queued run's priority in place.
+func (m *MockRepo) SetRunPriority(_ context.Context, runID string, priority int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q, ok := m.queue[runID]
+	if !ok {
+		return ErrRunNotQueued
+	}
+	q.Priority = priority
+	return nil
+}
+
+// RecordQueueWait stamps runID's queue wait time.
+func (m *MockRepo) RecordQueueWait(_ context.Context, runID string, waitSeconds float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[runID]
+	if !ok {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.QueueWaitSeconds = &waitSeconds
+	return nil
+}
+
+// RecordExecutionDuration stamps runID's execution duration.
+func (m *MockRepo) RecordExecutionDuration(_ context.Context, runID string, seconds float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run, ok := m.runs[runID]
+	if !ok {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	run.ExecutionSeconds = &seconds
+	return nil
 }
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -26,60 +27,395 @@ type InstanceType struct {
 }
 
 type BenchmarkRun struct {
-	ID                    string     `json:"id"`
-	ModelID               string     `json:"model_id"`
-	InstanceTypeID        string     `json:"instance_type_id"`
-	Framework             string     `json:"framework"`
-	FrameworkVersion      string     `json:"framework_version"`
-	TensorParallelDegree  int        `json:"tensor_parallel_degree"`
-	Quantization          *string    `json:"quantization,omitempty"`
-	Concurrency           int        `json:"concurrency"`
-	InputSequenceLength   int        `json:"input_sequence_length"`
-	OutputSequenceLength  int        `json:"output_sequence_length"`
-	DatasetName           string     `json:"dataset_name"`
-	RunType               string     `json:"run_type"`
-	Status                string     `json:"status"`
-	Superseded            bool       `json:"superseded"`
-	StartedAt             *time.Time `json:"started_at,omitempty"`
-	CompletedAt           *time.Time `json:"completed_at,omitempty"`
-	CreatedAt             time.Time  `json:"created_at"`
+	ID                   string  `json:"id"`
+	ModelID              string  `json:"model_id"`
+	InstanceTypeID       string  `json:"instance_type_id"`
+	Framework            string  `json:"framework"`
+	FrameworkVersion     string  `json:"framework_version"`
+	TensorParallelDegree int     `json:"tensor_parallel_degree"`
+	Quantization         *string `json:"quantization,omitempty"`
+	Concurrency          int     `json:"concurrency"`
+	InputSequenceLength  int     `json:"input_sequence_length"`
+	OutputSequenceLength int     `json:"output_sequence_length"`
+	DatasetName          string  `json:"dataset_name"`
+	RunType              string  `json:"run_type"`
+
+	// MinDurationSeconds is the loadgen job's MIN_DURATION_SECONDS floor
+	// (see manifest.LoadgenJobParams), copied from the originating
+	// RunRequest so a requeue or Resume can rebuild the same job without
+	// the caller resending it.
+	MinDurationSeconds int `json:"min_duration_seconds,omitempty"`
+
+	Status              string     `json:"status"`
+	Superseded          bool       `json:"superseded"`
+	HealthFailureReason *string    `json:"health_failure_reason,omitempty"`
+	StartedAt           *time.Time `json:"started_at,omitempty"`
+	CompletedAt         *time.Time `json:"completed_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+
+	// SweepID is set when this run was created as one child of a
+	// runs:batch sweep, so the sweep detail endpoint can find all of its
+	// children. Nil for runs created via the single-run POST /runs.
+	SweepID *string `json:"sweep_id,omitempty"`
+
+	// ExperimentID and Arm are set when this run was created as one child
+	// of an ExperimentSpec submission: ExperimentID identifies the parent
+	// experiment and Arm is the named group (e.g. "control"/"treatment")
+	// the run belongs to within it, so internal/analyzer can pair one
+	// arm's runs against another's by name instead of the caller tracking
+	// run IDs by hand. Nil/empty for runs created outside an experiment.
+	ExperimentID *string `json:"experiment_id,omitempty"`
+	Arm          string  `json:"arm,omitempty"`
+
+	// GroupID and TemplateIndex are set when this run was submitted by a
+	// BenchmarkGroup's scheduler tick: GroupID identifies the group and
+	// TemplateIndex is this run's position in the group's Runs template
+	// list, so ListCatalog's LatestPerGroup can tell which prior runs a
+	// new one supersedes. Nil/zero for runs created outside a group.
+	GroupID       *string `json:"group_id,omitempty"`
+	TemplateIndex int     `json:"template_index,omitempty"`
+
+	// PolicyID is set when this run was spawned by a BenchmarkPolicy's
+	// scheduler tick, identifying which policy produced it — the
+	// cron-scheduled analogue of GroupID/TemplateIndex above. Trigger
+	// records how the run came to exist: "manual" for a direct POST /runs,
+	// "scheduled" for a policy tick. Runs created by any other path
+	// (sweeps, experiments, SLO searches, ...) leave Trigger empty, and no
+	// code path stamps "event" on a run. PolicyID is nil for every run a
+	// policy didn't produce.
+	PolicyID *string `json:"policy_id,omitempty"`
+	Trigger  string  `json:"trigger,omitempty"`
+
+	// ParentRunID is set when this run was created as one probe of an SLO
+	// search (RunType="slo_search_probe"): it identifies the parent
+	// RunType="slo_search" run, so ListRunsByParent can find every probe
+	// the search has run so far. Nil for runs created outside an SLO
+	// search, including the slo_search parent run itself.
+	ParentRunID *string `json:"parent_run_id,omitempty"`
+
+	// DatasetSpec is set when this run's loadgen job was submitted with a
+	// non-default workload (code generation, needle-in-a-haystack,
+	// synthetic, or replay) instead of plain sharegpt prompts. Nil means
+	// DatasetName alone (almost always "sharegpt") fully describes the
+	// workload, the same as before DatasetSpec existed.
+	DatasetSpec *DatasetSpec `json:"dataset_spec,omitempty"`
+
+	// PluginDigests maps each FrameworkPlugin.Name used by this run to the
+	// resolved sha256 digest of its artifact image, so results stay
+	// reproducible and diff-able across kernel versions. Empty when the run
+	// used no plugins.
+	PluginDigests map[string]string `json:"plugin_digests,omitempty"`
+
+	// OriginalRequest is the RunRequest this run was created from,
+	// persisted verbatim so the scheduler can rebuild this run's
+	// orchestrator.RunConfig after a process restart and so
+	// POST /runs/{id}/requeue can resubmit it without asking the client
+	// to resend fields (HfToken, Plugins, MaxModelLen, ...) that have no
+	// column of their own on this row.
+	OriginalRequest *RunRequest `json:"original_request,omitempty"`
+
+	// QueuedAt is stamped when the run is admitted into the scheduler's
+	// persistent queue. QueueWaitSeconds and ExecutionSeconds are nil
+	// until the scheduler dispatches the run and the orchestrator's
+	// Execute returns, respectively, letting a caller see how much of a
+	// run's total time was spent waiting for cluster capacity versus
+	// actually running.
+	QueuedAt         *time.Time `json:"queued_at,omitempty"`
+	QueueWaitSeconds *float64   `json:"queue_wait_seconds,omitempty"`
+	ExecutionSeconds *float64   `json:"execution_seconds,omitempty"`
+
+	// FailureReason is stamped by orchestrator.Execute when a lifecycle
+	// phase fails: the last Kubernetes event or container status it could
+	// find for the phase's Pod (FailedScheduling, ImagePullBackOff,
+	// OOMKilled, ...), so a caller can tell a capacity problem apart from a
+	// model-load failure without kubectl describe. Nil when the run hasn't
+	// failed, or failed without a Pod-level reason to attach (e.g. a
+	// parse/persist error). Distinct from HealthFailureReason, which is
+	// stamped by a post-hoc VerifyRun pass over an already-completed run.
+	FailureReason *string `json:"failure_reason,omitempty"`
+
+	// Phase records orchestrator.Execute's current lifecycle step
+	// ("deploying", "ready", "loadgen_running", "collecting"), stamped by
+	// UpdateRunPhase alongside K8sResources as Execute progresses, so
+	// Resume can tell how far a crashed process got instead of just
+	// knowing Status is "running". Empty outside the Execute/Resume path.
+	Phase string `json:"phase,omitempty"`
+
+	// TeardownPending is true from just before Execute creates any
+	// Kubernetes objects until its teardown defer finishes successfully, so
+	// Resume can tell an orphaned Deployment/Service/Job apart from a run
+	// whose resources were already cleaned up before the process died.
+	TeardownPending bool `json:"teardown_pending,omitempty"`
+
+	// K8sResources records the namespace and name/UID of every Kubernetes
+	// object Execute has created for this run so far, so Resume can rebind
+	// to them by label selector instead of recreating them from scratch.
+	// Nil until deployModel's apply succeeds.
+	K8sResources *K8sResourceRefs `json:"k8s_resources,omitempty"`
+
+	// CallbackURL and CallbackToken are copied from the originating
+	// RunRequest onto the row itself (rather than read back out of
+	// OriginalRequest's JSON blob) so UpdateRunStatus's terminal-status
+	// outbox write doesn't have to deserialize it on every transition.
+	// Empty for runs submitted without a callback.
+	CallbackURL   string `json:"callback_url,omitempty"`
+	CallbackToken string `json:"callback_token,omitempty"`
+
+	// TimeoutSeconds and DeadlineAt bound how long this run may sit
+	// unfinished (queued, pending, or running) before the reaper fails it:
+	// TimeoutSeconds is copied from the originating RunRequest (after
+	// resolveRunTimeout applies the server's default/maximum), and
+	// DeadlineAt is stamped by CreateBenchmarkRun as created_at +
+	// TimeoutSeconds. DeadlineAt anchors on CreatedAt rather than
+	// StartedAt so a run stuck waiting for cluster capacity is bounded
+	// too, not just one already executing — StartedAt is nil until the
+	// orchestrator picks the run up, which could be arbitrarily far off
+	// for a queue under sustained pressure. Nil DeadlineAt means no
+	// deadline (TimeoutSeconds was zero and the server has no default
+	// configured).
+	TimeoutSeconds int        `json:"timeout_seconds,omitempty"`
+	DeadlineAt     *time.Time `json:"deadline_at,omitempty"`
+}
+
+// K8sResourceRefs identifies the Kubernetes objects orchestrator.Execute
+// created for one run, so a restarted process's Resume can rebind to the
+// live Deployment/Service/Job by label selector
+// (accelbench.io/run-id=<runID>) instead of recreating or guessing at
+// them. UIDs are recorded alongside names so Resume can detect a name
+// reused by a different object (the old one was deleted and something
+// else recreated it under the same name) rather than silently rebinding
+// to the wrong resource.
+type K8sResourceRefs struct {
+	Namespace string `json:"namespace"`
+
+	DeploymentName string `json:"deployment_name,omitempty"`
+	DeploymentUID  string `json:"deployment_uid,omitempty"`
+	ServiceName    string `json:"service_name,omitempty"`
+	ServiceUID     string `json:"service_uid,omitempty"`
+	JobName        string `json:"job_name,omitempty"`
+	JobUID         string `json:"job_uid,omitempty"`
+}
+
+// FrameworkPlugin describes a framework extension — a custom attention
+// kernel, quantization backend, or speculative-decoding drafter — loaded
+// into the model container at startup without rebuilding its image. The
+// plugin's artifact is expected at /artifact in Image; deployModel copies it
+// from an init container into an emptyDir mounted at MountPath in the model
+// container, the same path both see.
+type FrameworkPlugin struct {
+	Name      string            `json:"name" yaml:"name"`
+	Image     string            `json:"image" yaml:"image"` // OCI image pinned by digest (name@sha256:...)
+	MountPath string            `json:"mount_path" yaml:"mount_path"`
+	EnvAppend map[string]string `json:"env_append,omitempty" yaml:"env_append,omitempty"` // e.g. VLLM_PLUGINS, LD_PRELOAD
+}
+
+// DatasetSpec describes the workload a loadgen job replays: which
+// generator to use, where its source material comes from, how prompts are
+// sampled from it, and (for workloads with a notion of correctness, like
+// code generation) how responses are scored. RenderLoadgenJob translates
+// a DatasetSpec into the loadgen container's args and env vars; a nil
+// DatasetSpec on a RunRequest falls back to plain sharegpt prompts, the
+// same behavior as before DatasetSpec existed.
+type DatasetSpec struct {
+	// Name selects the loadgen generator: "sharegpt" (ShareGPT-style
+	// conversational prompts, bundled with the loadgen image), "humaneval"
+	// (HumanEval/MBPP code generation, scored pass@k), "needle" (long-context
+	// needle-in-a-haystack, scored retrieval accuracy per depth bucket),
+	// "synthetic" (fixed-length deterministic token counts, for
+	// apples-to-apples framework comparison), or "replay" (a JSONL trace of
+	// real prompts read from SourceURI).
+	Name string `json:"name" yaml:"name"`
+
+	// SourceURI is the dataset's location (an s3:// or https:// URI) for
+	// "humaneval", "needle", and "replay"; ignored for "sharegpt" (bundled)
+	// and "synthetic" (generated, not read).
+	SourceURI string `json:"source_uri,omitempty" yaml:"source_uri,omitempty"`
+
+	// SamplingPolicy controls how prompts are drawn from the dataset:
+	// "sequential" (the default) walks it in order, "random" draws with
+	// SamplingSeed for reproducibility. Ignored by "synthetic", which
+	// generates prompts rather than sampling them.
+	SamplingPolicy string `json:"sampling_policy,omitempty" yaml:"sampling_policy,omitempty"`
+	SamplingSeed   int64  `json:"sampling_seed,omitempty" yaml:"sampling_seed,omitempty"`
+
+	// NeedleDepths are the context-depth buckets (0-100, percent of the
+	// context window filled before the needle) the "needle" generator
+	// tests, so retrieval accuracy is recorded per depth instead of just
+	// overall. Ignored by every other Name.
+	NeedleDepths []int `json:"needle_depths,omitempty" yaml:"needle_depths,omitempty"`
+
+	// EvaluationHook is an OCI image the loadgen job runs as a sidecar to
+	// score each response (pass@k for "humaneval", retrieval accuracy for
+	// "needle"); nil skips scoring and records throughput/latency only.
+	EvaluationHook *string `json:"evaluation_hook,omitempty" yaml:"evaluation_hook,omitempty"`
 }
 
 type BenchmarkMetrics struct {
-	ID                       string   `json:"id"`
-	RunID                    string   `json:"run_id"`
-	TTFTP50Ms                *float64 `json:"ttft_p50_ms,omitempty"`
-	TTFTP90Ms                *float64 `json:"ttft_p90_ms,omitempty"`
-	TTFTP95Ms                *float64 `json:"ttft_p95_ms,omitempty"`
-	TTFTP99Ms                *float64 `json:"ttft_p99_ms,omitempty"`
-	E2ELatencyP50Ms          *float64 `json:"e2e_latency_p50_ms,omitempty"`
-	E2ELatencyP90Ms          *float64 `json:"e2e_latency_p90_ms,omitempty"`
-	E2ELatencyP95Ms          *float64 `json:"e2e_latency_p95_ms,omitempty"`
-	E2ELatencyP99Ms          *float64 `json:"e2e_latency_p99_ms,omitempty"`
-	ITLP50Ms                 *float64 `json:"itl_p50_ms,omitempty"`
-	ITLP90Ms                 *float64 `json:"itl_p90_ms,omitempty"`
-	ITLP95Ms                 *float64 `json:"itl_p95_ms,omitempty"`
-	ITLP99Ms                 *float64 `json:"itl_p99_ms,omitempty"`
-	ThroughputPerRequestTPS  *float64 `json:"throughput_per_request_tps,omitempty"`
-	ThroughputAggregateTPS   *float64 `json:"throughput_aggregate_tps,omitempty"`
-	RequestsPerSecond        *float64 `json:"requests_per_second,omitempty"`
-	AcceleratorUtilizationPct *float64 `json:"accelerator_utilization_pct,omitempty"`
-	AcceleratorMemoryPeakGiB *float64 `json:"accelerator_memory_peak_gib,omitempty"`
-	SuccessfulRequests       *int     `json:"successful_requests,omitempty"`
-	FailedRequests           *int     `json:"failed_requests,omitempty"`
-	TotalDurationSeconds     *float64 `json:"total_duration_seconds,omitempty"`
-	CreatedAt                time.Time `json:"created_at"`
+	ID                           string   `json:"id"`
+	RunID                        string   `json:"run_id"`
+	TTFTP50Ms                    *float64 `json:"ttft_p50_ms,omitempty"`
+	TTFTP90Ms                    *float64 `json:"ttft_p90_ms,omitempty"`
+	TTFTP95Ms                    *float64 `json:"ttft_p95_ms,omitempty"`
+	TTFTP99Ms                    *float64 `json:"ttft_p99_ms,omitempty"`
+	E2ELatencyP50Ms              *float64 `json:"e2e_latency_p50_ms,omitempty"`
+	E2ELatencyP90Ms              *float64 `json:"e2e_latency_p90_ms,omitempty"`
+	E2ELatencyP95Ms              *float64 `json:"e2e_latency_p95_ms,omitempty"`
+	E2ELatencyP99Ms              *float64 `json:"e2e_latency_p99_ms,omitempty"`
+	ITLP50Ms                     *float64 `json:"itl_p50_ms,omitempty"`
+	ITLP90Ms                     *float64 `json:"itl_p90_ms,omitempty"`
+	ITLP95Ms                     *float64 `json:"itl_p95_ms,omitempty"`
+	ITLP99Ms                     *float64 `json:"itl_p99_ms,omitempty"`
+	ThroughputPerRequestTPS      *float64 `json:"throughput_per_request_tps,omitempty"`
+	ThroughputAggregateTPS       *float64 `json:"throughput_aggregate_tps,omitempty"`
+	RequestsPerSecond            *float64 `json:"requests_per_second,omitempty"`
+	AcceleratorUtilizationPct    *float64 `json:"accelerator_utilization_pct,omitempty"`
+	AcceleratorUtilizationAvgPct *float64 `json:"accelerator_utilization_avg_pct,omitempty"`
+	AcceleratorMemoryPeakGiB     *float64 `json:"accelerator_memory_peak_gib,omitempty"`
+	// AcceleratorKind tags which vendor the Accelerator*/SM*/TensorCore*
+	// fields below came from ("nvidia", "neuron", "tpu", "rocm"), set from
+	// orchestrator.AcceleratorScraper.Kind() so cross-accelerator
+	// comparisons know these fields aren't directly comparable numbers from
+	// the same hardware family. Empty when no scraper ran (CPU-only runs,
+	// or a scrape that collected zero samples).
+	AcceleratorKind      string   `json:"accelerator_kind,omitempty"`
+	WaitingRequestsMax   *int     `json:"waiting_requests_max,omitempty"`
+	SuccessfulRequests   *int     `json:"successful_requests,omitempty"`
+	FailedRequests       *int     `json:"failed_requests,omitempty"`
+	TotalDurationSeconds *float64 `json:"total_duration_seconds,omitempty"`
+
+	// vLLM server-side histograms scraped from /metrics, in milliseconds.
+	ServerTTFTP50Ms *float64 `json:"server_ttft_p50_ms,omitempty"`
+	ServerTTFTP90Ms *float64 `json:"server_ttft_p90_ms,omitempty"`
+	ServerTTFTP95Ms *float64 `json:"server_ttft_p95_ms,omitempty"`
+	ServerTTFTP99Ms *float64 `json:"server_ttft_p99_ms,omitempty"`
+
+	ServerTPOTP50Ms *float64 `json:"server_tpot_p50_ms,omitempty"`
+	ServerTPOTP90Ms *float64 `json:"server_tpot_p90_ms,omitempty"`
+	ServerTPOTP95Ms *float64 `json:"server_tpot_p95_ms,omitempty"`
+	ServerTPOTP99Ms *float64 `json:"server_tpot_p99_ms,omitempty"`
+
+	ServerE2ELatencyP50Ms *float64 `json:"server_e2e_latency_p50_ms,omitempty"`
+	ServerE2ELatencyP90Ms *float64 `json:"server_e2e_latency_p90_ms,omitempty"`
+	ServerE2ELatencyP95Ms *float64 `json:"server_e2e_latency_p95_ms,omitempty"`
+	ServerE2ELatencyP99Ms *float64 `json:"server_e2e_latency_p99_ms,omitempty"`
+
+	// Device-level metrics scraped from the DCGM exporter or neuron-monitor,
+	// nil when no device exporter was available for the instance.
+	SMActivePeakPct     *float64 `json:"sm_active_peak_pct,omitempty"`
+	TensorCoreActivePct *float64 `json:"tensor_core_active_pct,omitempty"`
+	PowerAvgW           *float64 `json:"power_avg_w,omitempty"`
+	TempPeakC           *float64 `json:"temp_peak_c,omitempty"`
+	HBMUsedPeakGiB      *float64 `json:"hbm_used_peak_gib,omitempty"`
+
+	// Extended per-device rollups computed by internal/collector from this
+	// run's raw accelerator_samples, nil when no samples were recorded
+	// (no device exporter, or the window closed before the first scrape).
+	// Unlike SMActivePeakPct above, these are derived straight from the raw
+	// per-device series rather than a collapsed mean-then-peak, so they
+	// carry distribution shape (p50/p95) and not just an extremum.
+	SMUtilP50Pct         *float64 `json:"sm_util_p50_pct,omitempty"`
+	SMUtilP95Pct         *float64 `json:"sm_util_p95_pct,omitempty"`
+	SMUtilMaxPct         *float64 `json:"sm_util_max_pct,omitempty"`
+	EnergyConsumedJoules *float64 `json:"energy_consumed_joules,omitempty"`
+	ThermalHeadroomC     *float64 `json:"thermal_headroom_c,omitempty"`
+
+	// Cost/energy efficiency snapshot captured at run-completion time from
+	// whatever internal/pricing.Provider the orchestrator was configured
+	// with, nil when no provider was configured or it had no rate for this
+	// instance type. Unlike the `pricing` CLI package's Derive, which
+	// recomputes these against the *current* rate at query time (see
+	// CostDeltaPct), these are the rate in effect when the run actually
+	// executed — PricingSource/PricingCapturedAt record provenance so a
+	// stored cost can be told apart from a live one.
+	CostPerMillionTokensUSD *float64   `json:"cost_per_million_tokens_usd,omitempty"`
+	TokensPerJoule          *float64   `json:"tokens_per_joule,omitempty"`
+	PricingSource           *string    `json:"pricing_source,omitempty"`
+	PricingCapturedAt       *time.Time `json:"pricing_captured_at,omitempty"`
+
+	// MaxConcurrencyAtSLO is only set on the parent run of an SLO search
+	// (RunType="slo_search"), once orchestrator.ExecuteSLOSearch's
+	// bisection converges: the highest concurrency at which every probe
+	// run still met its SLOSpec. Nil on every other run, including the
+	// search's own probe children (RunType="slo_search_probe"), whose
+	// BenchmarkMetrics are the same as any other run's.
+	MaxConcurrencyAtSLO *int `json:"max_concurrency_at_slo,omitempty"`
+
+	// QualityMetrics holds workload-specific correctness scores alongside
+	// throughput/latency, populated only when the run's DatasetSpec set an
+	// EvaluationHook. Nil for sharegpt/synthetic runs and for any run whose
+	// DatasetSpec left EvaluationHook unset.
+	QualityMetrics *QualityMetrics `json:"quality_metrics,omitempty"`
+
+	// Per-rank breakdown for tensor-parallel deployments scraping multiple
+	// pods, nil for single-target runs. Persisted as a JSON column.
+	PerRank []RankMetricsSummary `json:"per_rank,omitempty"`
+
+	// ReplicasMin/ReplicasMax are only set on a run of RunType="autoscale",
+	// the bounds orchestrator.ExecuteAutoscale was configured to stay within
+	// (see AutoscaleRequest). Nil on every other run.
+	ReplicasMin *int `json:"replicas_min,omitempty"`
+	ReplicasMax *int `json:"replicas_max,omitempty"`
+
+	// ScaleUpLatencySeconds is only set on an autoscale run: the time from
+	// the first scale-up decision to the replacement replica reporting
+	// ready, the signal operators actually care about when sizing an HPA's
+	// stabilization window. Nil if the ramp never scaled up.
+	ScaleUpLatencySeconds *float64 `json:"scale_up_latency_seconds,omitempty"`
+
+	// AutoscaleStages is the per-concurrency-stage breakdown of an autoscale
+	// run, one entry per step in AutoscaleRequest.ConcurrencyStages. Nil for
+	// every other RunType. Persisted as a JSON column.
+	AutoscaleStages []AutoscaleStageResult `json:"autoscale_stages,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AutoscaleStageResult is a denormalized per-stage summary of an autoscale
+// ramp, persisted as JSON on BenchmarkMetrics.
+type AutoscaleStageResult struct {
+	Concurrency             int     `json:"concurrency"`
+	Replicas                int     `json:"replicas"`
+	ThroughputAggregateTPS  float64 `json:"throughput_aggregate_tps"`
+	ThroughputPerReplicaTPS float64 `json:"throughput_per_replica_tps"`
+}
+
+// RankMetricsSummary is a denormalized per-pod metrics summary for
+// tensor-parallel deployments, persisted as JSON on BenchmarkMetrics.
+type RankMetricsSummary struct {
+	Rank                      int      `json:"rank"`
+	PodName                   string   `json:"pod_name"`
+	AcceleratorUtilizationPct float64  `json:"accelerator_utilization_pct"`
+	AcceleratorMemoryPeakGiB  float64  `json:"accelerator_memory_peak_gib"`
+	SMActivePeakPct           *float64 `json:"sm_active_peak_pct,omitempty"`
+}
+
+// QualityMetrics is workload-specific correctness scoring, persisted as
+// JSON on BenchmarkMetrics alongside the usual throughput/latency columns.
+type QualityMetrics struct {
+	// PassAtK maps "pass@1", "pass@10", etc. to the fraction of problems
+	// solved, populated by the "humaneval" generator.
+	PassAtK map[string]float64 `json:"pass_at_k,omitempty"`
+
+	// RetrievalAccuracyByDepth maps a DatasetSpec.NeedleDepths bucket
+	// (stringified, e.g. "50") to the fraction of needles correctly
+	// retrieved at that depth, populated by the "needle" generator.
+	RetrievalAccuracyByDepth map[string]float64 `json:"retrieval_accuracy_by_depth,omitempty"`
 }
 
 type Pricing struct {
-	ID                    string   `json:"id"`
-	InstanceTypeID        string   `json:"instance_type_id"`
-	Region                string   `json:"region"`
-	OnDemandHourlyUSD     float64  `json:"on_demand_hourly_usd"`
-	Reserved1YrHourlyUSD  *float64 `json:"reserved_1yr_hourly_usd,omitempty"`
-	Reserved3YrHourlyUSD  *float64 `json:"reserved_3yr_hourly_usd,omitempty"`
-	EffectiveDate         string   `json:"effective_date"`
-	CreatedAt             time.Time `json:"created_at"`
+	ID             string `json:"id"`
+	InstanceTypeID string `json:"instance_type_id"`
+	// CloudProvider is "aws", "gcp", "azure", or "on-prem", distinguishing
+	// rows for instance types that are priced by more than one source
+	// (e.g. the same accelerator class offered on-prem and on a cloud).
+	CloudProvider        string    `json:"cloud_provider"`
+	Region               string    `json:"region"`
+	OnDemandHourlyUSD    float64   `json:"on_demand_hourly_usd"`
+	Reserved1YrHourlyUSD *float64  `json:"reserved_1yr_hourly_usd,omitempty"`
+	Reserved3YrHourlyUSD *float64  `json:"reserved_3yr_hourly_usd,omitempty"`
+	SpotHourlyUSD        *float64  `json:"spot_hourly_usd,omitempty"`
+	EffectiveDate        string    `json:"effective_date"`
+	CreatedAt            time.Time `json:"created_at"`
 }
 
 // RunRequest represents the input parameters for starting a benchmark run.
@@ -97,5 +433,157 @@ type RunRequest struct {
 	DatasetName          string  `json:"dataset_name"`
 	RunType              string  `json:"run_type"`
 	MaxModelLen          int     `json:"max_model_len,omitempty"`
-	HfToken              string  `json:"hf_token,omitempty"`
+	// MinDurationSeconds is a floor on the loadgen job's run time: the
+	// loadgen keeps issuing requests past NumRequests/WarmupRequests until
+	// at least this many seconds have elapsed, so a high-concurrency run
+	// against a fast framework still collects enough steady-state samples.
+	// Zero (the default) applies no floor.
+	MinDurationSeconds int               `json:"min_duration_seconds,omitempty"`
+	HfToken            string            `json:"hf_token,omitempty"`
+	Plugins            []FrameworkPlugin `json:"plugins,omitempty"`
+
+	// DatasetSpec overrides DatasetName with a richer workload description
+	// (code generation, needle-in-a-haystack, synthetic, or replay); left
+	// nil, the run uses plain sharegpt prompts per DatasetName.
+	DatasetSpec *DatasetSpec `json:"dataset_spec,omitempty"`
+
+	// Priority orders this run in the scheduler's admission queue: higher
+	// values are dispatched first within the same instance family, ties
+	// broken FIFO by enqueue time. Zero is the default; negative values
+	// are accepted and dispatch after it. See POST /runs/{id}/priority to
+	// change it after submission.
+	Priority int `json:"priority,omitempty"`
+
+	// UserID attributes this run to a submitter for Quota.MaxConcurrentPerUser,
+	// so one noisy user can't starve everyone else waiting behind them in the
+	// same instance family. Self-reported and optional: a server running
+	// CNAuthzPolicy overrides it with the caller's client certificate CN
+	// rather than trusting this field, since only that identity is actually
+	// verified.
+	UserID string `json:"user_id,omitempty"`
+
+	// CallbackURL, if set, is POSTed a {run_id, status, metrics_summary}
+	// notification by the callbacks package once this run reaches
+	// "completed" or "failed", so a caller doesn't have to poll GetRun or
+	// hold an SSE connection open. CallbackToken, if set, HMAC-signs that
+	// POST (see callbacks.Sign) so the receiver can verify it actually
+	// came from this server.
+	CallbackURL   string `json:"callback_url,omitempty"`
+	CallbackToken string `json:"callback_token,omitempty"`
+
+	// TimeoutSeconds bounds how long this run may sit unfinished (queued,
+	// pending, or running) before the reaper fails it out from under a
+	// stuck vLLM process or a hung download, so a single wedged run can't
+	// hold an accelerator forever. Zero (the default) defers to the
+	// server's configured default timeout; either way the server clamps
+	// the effective value to its configured maximum, so a caller can
+	// request a long timeout but not an unbounded one. See
+	// handleCreateRun's resolveRunTimeout.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+}
+
+// SweepRequest represents a benchmark sweep (matrix) submission: one model
+// run against the Cartesian product of the listed parameter values. Every
+// list field needs at least one value (Expand rejects an empty one); give
+// a field a single value to hold that dimension fixed while the sweep
+// varies the others.
+// SweepRequest's fields also carry yaml tags mirroring its json tags, so
+// the same type can be embedded in an ExperimentArm and decoded directly
+// from the YAML spec file accelbench sweep reads, instead of needing a
+// parallel YAML-only struct kept in sync by hand.
+type SweepRequest struct {
+	ModelHfID       string  `json:"model_hf_id" yaml:"model_hf_id"`
+	ModelHfRevision string  `json:"model_hf_revision" yaml:"model_hf_revision"`
+	Quantization    *string `json:"quantization,omitempty" yaml:"quantization,omitempty"`
+	DatasetName     string  `json:"dataset_name" yaml:"dataset_name"`
+	// DatasetSpec overrides DatasetName the same way it does on RunRequest;
+	// shared by every run the sweep expands to, since a sweep varies its
+	// listed parameter dimensions but not the workload itself.
+	DatasetSpec *DatasetSpec `json:"dataset_spec,omitempty" yaml:"dataset_spec,omitempty"`
+	RunType     string       `json:"run_type" yaml:"run_type"`
+	MaxModelLen int          `json:"max_model_len,omitempty" yaml:"max_model_len,omitempty"`
+	HfToken     string       `json:"hf_token,omitempty" yaml:"hf_token,omitempty"`
+
+	Plugins []FrameworkPlugin `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+
+	Framework            []string `json:"framework" yaml:"framework"`
+	FrameworkVersion     []string `json:"framework_version" yaml:"framework_version"`
+	TensorParallelDegree []int    `json:"tensor_parallel_degree" yaml:"tensor_parallel_degree"`
+	Concurrency          []int    `json:"concurrency" yaml:"concurrency"`
+	InputSequenceLength  []int    `json:"input_sequence_length" yaml:"input_sequence_length"`
+	OutputSequenceLength []int    `json:"output_sequence_length" yaml:"output_sequence_length"`
+	InstanceTypeName     []string `json:"instance_type_name" yaml:"instance_type_name"`
+}
+
+// maxSweepRuns caps the Cartesian expansion of a SweepRequest so a
+// fat-fingered spec (e.g. every field given 10 values) can't ask the
+// controller to launch tens of thousands of child runs.
+const maxSweepRuns = 256
+
+// Expand returns the Cartesian product of s's swept dimensions as
+// individual RunRequests, sharing every non-swept field. It errors if any
+// swept dimension is empty or the product would exceed maxSweepRuns.
+func (s SweepRequest) Expand() ([]RunRequest, error) {
+	frameworks := s.Framework
+	versions := s.FrameworkVersion
+	tpDegrees := s.TensorParallelDegree
+	concurrencies := s.Concurrency
+	inputLens := s.InputSequenceLength
+	outputLens := s.OutputSequenceLength
+	instanceTypes := s.InstanceTypeName
+
+	for name, dim := range map[string]int{
+		"framework":              len(frameworks),
+		"framework_version":      len(versions),
+		"tensor_parallel_degree": len(tpDegrees),
+		"concurrency":            len(concurrencies),
+		"input_sequence_length":  len(inputLens),
+		"output_sequence_length": len(outputLens),
+		"instance_type_name":     len(instanceTypes),
+	} {
+		if dim == 0 {
+			return nil, fmt.Errorf("sweep dimension %q must have at least one value", name)
+		}
+	}
+
+	total := len(frameworks) * len(versions) * len(tpDegrees) * len(concurrencies) *
+		len(inputLens) * len(outputLens) * len(instanceTypes)
+	if total > maxSweepRuns {
+		return nil, fmt.Errorf("sweep expands to %d runs, exceeds limit of %d", total, maxSweepRuns)
+	}
+
+	runs := make([]RunRequest, 0, total)
+	for _, fw := range frameworks {
+		for _, ver := range versions {
+			for _, tp := range tpDegrees {
+				for _, c := range concurrencies {
+					for _, in := range inputLens {
+						for _, out := range outputLens {
+							for _, inst := range instanceTypes {
+								runs = append(runs, RunRequest{
+									ModelHfID:            s.ModelHfID,
+									ModelHfRevision:      s.ModelHfRevision,
+									InstanceTypeName:     inst,
+									Framework:            fw,
+									FrameworkVersion:     ver,
+									TensorParallelDegree: tp,
+									Quantization:         s.Quantization,
+									Concurrency:          c,
+									InputSequenceLength:  in,
+									OutputSequenceLength: out,
+									DatasetName:          s.DatasetName,
+									DatasetSpec:          s.DatasetSpec,
+									RunType:              s.RunType,
+									MaxModelLen:          s.MaxModelLen,
+									HfToken:              s.HfToken,
+									Plugins:              s.Plugins,
+								})
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return runs, nil
 }
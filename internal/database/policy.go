@@ -0,0 +1,250 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/cronexpr"
+)
+
+// BenchmarkPolicy is a persisted, recurring benchmark definition whose
+// schedule is owned by a cron expression (or an external event) rather
+// than BenchmarkGroup's fixed Interval, so a caller can say "run this
+// every Sunday at 02:00 UTC" instead of "run this every 168h" and have
+// the schedule survive across daylight-saving transitions and service
+// restarts the same way a crontab entry would. internal/policy's
+// Dispatcher ticks, claims due policies, and materializes one
+// BenchmarkRun per policy from Template.
+type BenchmarkPolicy struct {
+	ID       string               `json:"id"`
+	Name     string               `json:"name"`
+	Template BenchmarkRunTemplate `json:"template"`
+
+	// Trigger selects what advances this policy: "manual" (never fires on
+	// its own; the Dispatcher never materializes a run for it),
+	// "scheduled" (CronExpr drives NextRunAt), or
+	// "event" (an external signal the caller's own integration is
+	// responsible for recognizing; NextRunAt is left unset).
+	Trigger string `json:"trigger"`
+
+	// CronExpr is a standard 5-field cron expression (see
+	// internal/cronexpr), required when Trigger is "scheduled" and
+	// ignored otherwise.
+	CronExpr string `json:"cron_expr,omitempty"`
+
+	// Enabled gates whether the Dispatcher's tick will ever claim this
+	// policy; a disabled policy keeps its NextRunAt frozen rather than
+	// having it drift out of date, so re-enabling it doesn't trigger an
+	// immediate catch-up run for every tick that was skipped while off.
+	Enabled bool `json:"enabled"`
+
+	// NextRunAt is when the Dispatcher's tick will next claim this
+	// policy, in UTC. Set from CronExpr at creation and after every claim;
+	// nil for a Trigger="manual" or Trigger="event" policy.
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CreatePolicy persists a new BenchmarkPolicy and returns its ID. For a
+// Trigger="scheduled" policy, NextRunAt is computed from CronExpr
+// starting at the current time so the first run fires at the next
+// matching tick rather than immediately.
+func (r *Repository) CreatePolicy(ctx context.Context, policy *BenchmarkPolicy) (string, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	if policy.Trigger == "scheduled" {
+		next, err := cronexpr.Next(policy.CronExpr, time.Now().UTC())
+		if err != nil {
+			return "", fmt.Errorf("compute next run: %w", err)
+		}
+		policy.NextRunAt = &next
+	}
+
+	templateJSON, err := json.Marshal(policy.Template)
+	if err != nil {
+		return "", fmt.Errorf("marshal template: %w", err)
+	}
+
+	var id string
+	err = r.pool.QueryRow(ctx,
+		`INSERT INTO benchmark_policies (name, template_json, trigger, cron_expr, enabled, next_run_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id`,
+		policy.Name, templateJSON, policy.Trigger, policy.CronExpr, policy.Enabled, policy.NextRunAt,
+	).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("insert benchmark policy: %w", err)
+	}
+	return id, nil
+}
+
+// UpdatePolicy replaces policyID's mutable fields (Name, Template,
+// Trigger, CronExpr, Enabled). Changing CronExpr or Trigger to
+// "scheduled" recomputes NextRunAt from now, the same as CreatePolicy;
+// switching away from "scheduled" clears it. Returns ErrPolicyNotFound if
+// policyID doesn't exist.
+func (r *Repository) UpdatePolicy(ctx context.Context, policyID string, policy *BenchmarkPolicy) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	var nextRunAt *time.Time
+	if policy.Trigger == "scheduled" {
+		next, err := cronexpr.Next(policy.CronExpr, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("compute next run: %w", err)
+		}
+		nextRunAt = &next
+	}
+
+	templateJSON, err := json.Marshal(policy.Template)
+	if err != nil {
+		return fmt.Errorf("marshal template: %w", err)
+	}
+
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE benchmark_policies
+		 SET name = $1, template_json = $2, trigger = $3, cron_expr = $4, enabled = $5, next_run_at = $6
+		 WHERE id = $7`,
+		policy.Name, templateJSON, policy.Trigger, policy.CronExpr, policy.Enabled, nextRunAt, policyID,
+	)
+	if err != nil {
+		return fmt.Errorf("update benchmark policy: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPolicyNotFound
+	}
+	return nil
+}
+
+// ListPolicies returns every persisted BenchmarkPolicy, for the HTTP
+// layer's GET /api/v1/policies and for internal/policy's Dispatcher's
+// non-leased fallback path.
+func (r *Repository) ListPolicies(ctx context.Context) ([]BenchmarkPolicy, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, name, template_json, trigger, cron_expr, enabled, next_run_at, last_run_at, created_at
+		 FROM benchmark_policies
+		 ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("query benchmark policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []BenchmarkPolicy
+	for rows.Next() {
+		p, err := scanPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	return policies, rows.Err()
+}
+
+// DeletePolicy removes a BenchmarkPolicy. Runs it already produced are
+// untouched; their PolicyID simply refers to a policy that no longer
+// exists, the same way a deleted BenchmarkGroup leaves its runs' GroupID
+// dangling.
+func (r *Repository) DeletePolicy(ctx context.Context, policyID string) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	_, err := r.pool.Exec(ctx, `DELETE FROM benchmark_policies WHERE id = $1`, policyID)
+	if err != nil {
+		return fmt.Errorf("delete benchmark policy: %w", err)
+	}
+	return nil
+}
+
+// policyRowScanner is satisfied by both pgx.Rows and pgx.Row, so
+// scanPolicy can back both ListPolicies and ClaimDuePolicies.
+type policyRowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPolicy(row policyRowScanner) (BenchmarkPolicy, error) {
+	var (
+		p            BenchmarkPolicy
+		templateJSON []byte
+	)
+	if err := row.Scan(&p.ID, &p.Name, &templateJSON, &p.Trigger, &p.CronExpr, &p.Enabled, &p.NextRunAt, &p.LastRunAt, &p.CreatedAt); err != nil {
+		return BenchmarkPolicy{}, fmt.Errorf("scan benchmark policy row: %w", err)
+	}
+	if len(templateJSON) > 0 {
+		if err := json.Unmarshal(templateJSON, &p.Template); err != nil {
+			return BenchmarkPolicy{}, fmt.Errorf("unmarshal template: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// ClaimDuePolicies implements PolicyClaimer: it locks every
+// enabled, due policy (next_run_at <= now) via `FOR UPDATE SKIP LOCKED`
+// so two Dispatcher processes polling the same database never claim
+// the same tick, advances each one's NextRunAt from CronExpr before
+// releasing the lock, and returns the pre-advance policies for the
+// caller to materialize a BenchmarkRun from. A policy whose CronExpr
+// fails to parse (shouldn't happen since Create/UpdatePolicy validate it,
+// but a row edited directly in the database could) is skipped rather
+// than failing the whole tick, so one bad policy can't wedge every
+// other policy's dispatch.
+func (r *Repository) ClaimDuePolicies(ctx context.Context, now time.Time) ([]BenchmarkPolicy, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`SELECT id, name, template_json, trigger, cron_expr, enabled, next_run_at, last_run_at, created_at
+		 FROM benchmark_policies
+		 WHERE enabled AND next_run_at <= $1
+		 FOR UPDATE SKIP LOCKED`, now)
+	if err != nil {
+		return nil, fmt.Errorf("query due policies: %w", err)
+	}
+	var due []BenchmarkPolicy
+	for rows.Next() {
+		p, err := scanPolicy(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		due = append(due, p)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	claimed := due[:0]
+	for _, p := range due {
+		next, err := cronexpr.Next(p.CronExpr, now)
+		if err != nil {
+			continue
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE benchmark_policies SET next_run_at = $1, last_run_at = $2 WHERE id = $3`,
+			next, now, p.ID,
+		); err != nil {
+			return nil, fmt.Errorf("advance next run for policy %s: %w", p.ID, err)
+		}
+		claimed = append(claimed, p)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return claimed, nil
+}
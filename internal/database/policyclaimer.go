@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// PolicyClaimer is implemented by a policy backend that can safely be
+// polled by more than one internal/policy dispatcher process at once:
+// ClaimDuePolicies atomically claims every enabled, due policy via
+// `SELECT ... FOR UPDATE SKIP LOCKED` and advances its NextRunAt within
+// the same transaction, so two dispatchers racing the same tick never
+// both materialize a run for the same policy occurrence.
+//
+// Only *Repository (Postgres) implements this today, mirroring
+// LeasedRunQueue; internal/policy's dispatcher type-asserts for it and
+// falls back to its own ListPolicies-based dispatch against any
+// database.Repo that doesn't, which is safe as long as exactly one
+// dispatcher process is ticking against that backend.
+type PolicyClaimer interface {
+	// ClaimDuePolicies claims every enabled policy whose NextRunAt is at
+	// or before now, advancing each claimed policy's NextRunAt from its
+	// CronExpr before returning.
+	ClaimDuePolicies(ctx context.Context, now time.Time) ([]BenchmarkPolicy, error)
+}
@@ -3,30 +3,39 @@ package database
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // PricingRow is a denormalized view of pricing joined with instance type name.
 type PricingRow struct {
 	InstanceTypeName     string   `json:"instance_type_name"`
+	CloudProvider        string   `json:"cloud_provider"`
 	OnDemandHourlyUSD    float64  `json:"on_demand_hourly_usd"`
 	Reserved1YrHourlyUSD *float64 `json:"reserved_1yr_hourly_usd,omitempty"`
 	Reserved3YrHourlyUSD *float64 `json:"reserved_3yr_hourly_usd,omitempty"`
+	SpotHourlyUSD        *float64 `json:"spot_hourly_usd,omitempty"`
 	EffectiveDate        string   `json:"effective_date"`
 }
 
 // UpsertPricing inserts or updates a pricing row keyed by
-// (instance_type_id, region, effective_date).
+// (instance_type_id, cloud_provider, region, effective_date) — the same
+// instance type can carry one row per cloud it's priced on (e.g. an H100
+// accelerator class benchmarked both on-prem and on AWS).
 func (r *Repository) UpsertPricing(ctx context.Context, p *Pricing) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
 	_, err := r.pool.Exec(ctx, `
-		INSERT INTO pricing (instance_type_id, region, on_demand_hourly_usd,
-		                     reserved_1yr_hourly_usd, reserved_3yr_hourly_usd, effective_date)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (instance_type_id, region, effective_date) DO UPDATE SET
+		INSERT INTO pricing (instance_type_id, cloud_provider, region, on_demand_hourly_usd,
+		                     reserved_1yr_hourly_usd, reserved_3yr_hourly_usd, spot_hourly_usd, effective_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (instance_type_id, cloud_provider, region, effective_date) DO UPDATE SET
 			on_demand_hourly_usd    = EXCLUDED.on_demand_hourly_usd,
 			reserved_1yr_hourly_usd = EXCLUDED.reserved_1yr_hourly_usd,
-			reserved_3yr_hourly_usd = EXCLUDED.reserved_3yr_hourly_usd`,
-		p.InstanceTypeID, p.Region, p.OnDemandHourlyUSD,
-		p.Reserved1YrHourlyUSD, p.Reserved3YrHourlyUSD, p.EffectiveDate,
+			reserved_3yr_hourly_usd = EXCLUDED.reserved_3yr_hourly_usd,
+			spot_hourly_usd         = EXCLUDED.spot_hourly_usd`,
+		p.InstanceTypeID, p.CloudProvider, p.Region, p.OnDemandHourlyUSD,
+		p.Reserved1YrHourlyUSD, p.Reserved3YrHourlyUSD, p.SpotHourlyUSD, p.EffectiveDate,
 	)
 	if err != nil {
 		return fmt.Errorf("upsert pricing: %w", err)
@@ -34,21 +43,27 @@ func (r *Repository) UpsertPricing(ctx context.Context, p *Pricing) error {
 	return nil
 }
 
-// ListPricing returns the most recent pricing for each instance type in the
-// given region, joined with the instance type name.
+// ListPricing returns the most recent pricing for each (instance type,
+// cloud provider) pair in the given region, joined with the instance type
+// name.
 func (r *Repository) ListPricing(ctx context.Context, region string) ([]PricingRow, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
 	rows, err := r.pool.Query(ctx, `
-		SELECT it.name, p.on_demand_hourly_usd, p.reserved_1yr_hourly_usd,
-		       p.reserved_3yr_hourly_usd, p.effective_date::text
+		SELECT it.name, p.cloud_provider, p.on_demand_hourly_usd, p.reserved_1yr_hourly_usd,
+		       p.reserved_3yr_hourly_usd, p.spot_hourly_usd, p.effective_date::text
 		FROM pricing p
 		JOIN instance_types it ON it.id = p.instance_type_id
 		WHERE p.region = $1
 		  AND p.effective_date = (
 		      SELECT MAX(p2.effective_date)
 		      FROM pricing p2
-		      WHERE p2.instance_type_id = p.instance_type_id AND p2.region = p.region
+		      WHERE p2.instance_type_id = p.instance_type_id
+		        AND p2.cloud_provider = p.cloud_provider
+		        AND p2.region = p.region
 		  )
-		ORDER BY it.name`, region)
+		ORDER BY it.name, p.cloud_provider`, region)
 	if err != nil {
 		return nil, fmt.Errorf("list pricing: %w", err)
 	}
@@ -57,8 +72,84 @@ func (r *Repository) ListPricing(ctx context.Context, region string) ([]PricingR
 	var result []PricingRow
 	for rows.Next() {
 		var pr PricingRow
-		if err := rows.Scan(&pr.InstanceTypeName, &pr.OnDemandHourlyUSD,
-			&pr.Reserved1YrHourlyUSD, &pr.Reserved3YrHourlyUSD, &pr.EffectiveDate); err != nil {
+		if err := rows.Scan(&pr.InstanceTypeName, &pr.CloudProvider, &pr.OnDemandHourlyUSD,
+			&pr.Reserved1YrHourlyUSD, &pr.Reserved3YrHourlyUSD, &pr.SpotHourlyUSD, &pr.EffectiveDate); err != nil {
+			return nil, fmt.Errorf("scan pricing row: %w", err)
+		}
+		result = append(result, pr)
+	}
+	return result, rows.Err()
+}
+
+// ListPricingAt returns the pricing effective on asOf for each (instance
+// type, cloud provider) pair in region: the row with the largest
+// effective_date <= asOf, mirroring ListPricing's "most recent" grouping
+// but pinned to a point in time instead of now.
+func (r *Repository) ListPricingAt(ctx context.Context, region string, asOf time.Time) ([]PricingRow, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	asOfDate := asOf.Format("2006-01-02")
+	rows, err := r.pool.Query(ctx, `
+		SELECT it.name, p.cloud_provider, p.on_demand_hourly_usd, p.reserved_1yr_hourly_usd,
+		       p.reserved_3yr_hourly_usd, p.spot_hourly_usd, p.effective_date::text
+		FROM pricing p
+		JOIN instance_types it ON it.id = p.instance_type_id
+		WHERE p.region = $1
+		  AND p.effective_date <= $2::date
+		  AND p.effective_date = (
+		      SELECT MAX(p2.effective_date)
+		      FROM pricing p2
+		      WHERE p2.instance_type_id = p.instance_type_id
+		        AND p2.cloud_provider = p.cloud_provider
+		        AND p2.region = p.region
+		        AND p2.effective_date <= $2::date
+		  )
+		ORDER BY it.name, p.cloud_provider`, region, asOfDate)
+	if err != nil {
+		return nil, fmt.Errorf("list pricing at %s: %w", asOfDate, err)
+	}
+	defer rows.Close()
+
+	var result []PricingRow
+	for rows.Next() {
+		var pr PricingRow
+		if err := rows.Scan(&pr.InstanceTypeName, &pr.CloudProvider, &pr.OnDemandHourlyUSD,
+			&pr.Reserved1YrHourlyUSD, &pr.Reserved3YrHourlyUSD, &pr.SpotHourlyUSD, &pr.EffectiveDate); err != nil {
+			return nil, fmt.Errorf("scan pricing row: %w", err)
+		}
+		result = append(result, pr)
+	}
+	return result, rows.Err()
+}
+
+// PricingHistory returns every pricing row recorded for instanceTypeID in
+// region on or after since, ordered by effective date — the full time
+// series that ListPricing and ListPricingAt each collapse to a single row.
+func (r *Repository) PricingHistory(ctx context.Context, instanceTypeID, region string, since time.Time) ([]PricingRow, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT it.name, p.cloud_provider, p.on_demand_hourly_usd, p.reserved_1yr_hourly_usd,
+		       p.reserved_3yr_hourly_usd, p.spot_hourly_usd, p.effective_date::text
+		FROM pricing p
+		JOIN instance_types it ON it.id = p.instance_type_id
+		WHERE p.instance_type_id = $1
+		  AND p.region = $2
+		  AND p.effective_date >= $3::date
+		ORDER BY p.effective_date, p.cloud_provider`,
+		instanceTypeID, region, since.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("pricing history: %w", err)
+	}
+	defer rows.Close()
+
+	var result []PricingRow
+	for rows.Next() {
+		var pr PricingRow
+		if err := rows.Scan(&pr.InstanceTypeName, &pr.CloudProvider, &pr.OnDemandHourlyUSD,
+			&pr.Reserved1YrHourlyUSD, &pr.Reserved3YrHourlyUSD, &pr.SpotHourlyUSD, &pr.EffectiveDate); err != nil {
 			return nil, fmt.Errorf("scan pricing row: %w", err)
 		}
 		result = append(result, pr)
@@ -68,6 +159,9 @@ func (r *Repository) ListPricing(ctx context.Context, region string) ([]PricingR
 
 // ListInstanceTypes returns all instance types.
 func (r *Repository) ListInstanceTypes(ctx context.Context) ([]InstanceType, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
 	rows, err := r.pool.Query(ctx, `
 		SELECT id, name, family, accelerator_type, accelerator_name,
 		       accelerator_count, accelerator_memory_gib, vcpus, memory_gib
@@ -0,0 +1,1338 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LabelSet is a PromQL-style set of label name/value pairs identifying one
+// series within a Vector or Matrix.
+type LabelSet map[string]string
+
+func (ls LabelSet) key() string {
+	names := make([]string, 0, len(ls))
+	for n := range ls {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, n := range names {
+		b.WriteString(n)
+		b.WriteByte('=')
+		b.WriteString(ls[n])
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+func (ls LabelSet) without(names []string) LabelSet {
+	drop := make(map[string]bool, len(names))
+	for _, n := range names {
+		drop[n] = true
+	}
+	out := make(LabelSet, len(ls))
+	for k, v := range ls {
+		if !drop[k] {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func (ls LabelSet) only(names []string) LabelSet {
+	out := make(LabelSet, len(names))
+	for _, n := range names {
+		out[n] = ls[n]
+	}
+	return out
+}
+
+// VectorSample is one (labels, value) pair in a Vector.
+type VectorSample struct {
+	Labels LabelSet `json:"labels"`
+	Value  float64  `json:"value"`
+}
+
+// Vector is QueryCatalog's instant-query result: one sample per matched
+// series, as of the query's `at` time.
+type Vector []VectorSample
+
+// MatrixPoint is one (timestamp, value) pair within a Series.
+type MatrixPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// Series is one label set's points across a QueryCatalogRange window.
+type Series struct {
+	Labels LabelSet      `json:"labels"`
+	Points []MatrixPoint `json:"points"`
+}
+
+// Matrix is QueryCatalogRange's range-query result: one Series per matched
+// label set.
+type Matrix []Series
+
+// sampleFetcher fetches runID's raw Samples for range-vector functions
+// like quantile_over_time. Each Repo backend supplies its own, reusing
+// whichever storage mechanism that backend's VerifyRun already reads
+// samples from.
+type sampleFetcher func(runID string) ([]Sample, error)
+
+// catalogLabels is the label set a PromQL-style selector matches against
+// for one catalog entry: every dimension CatalogFilter can already filter
+// on, plus the run and group identifiers, so a query can select anything
+// ListCatalog can and more.
+func catalogLabels(e CatalogEntry) LabelSet {
+	ls := LabelSet{
+		"run_id":                 e.RunID,
+		"model":                  e.ModelHfID,
+		"instance_type":          e.InstanceTypeName,
+		"instance_family":        e.InstanceFamily,
+		"accelerator_type":       e.AcceleratorType,
+		"accelerator_name":       e.AcceleratorName,
+		"framework":              e.Framework,
+		"framework_version":      e.FrameworkVersion,
+		"tensor_parallel_degree": strconv.Itoa(e.TensorParallelDegree),
+		"concurrency":            strconv.Itoa(e.Concurrency),
+		"input_sequence_length":  strconv.Itoa(e.InputSequenceLength),
+		"output_sequence_length": strconv.Itoa(e.OutputSequenceLength),
+	}
+	if e.ModelFamily != nil {
+		ls["model_family"] = *e.ModelFamily
+	}
+	if e.Quantization != nil {
+		ls["quantization"] = *e.Quantization
+	}
+	if e.GroupID != nil {
+		ls["group_id"] = *e.GroupID
+	}
+	return ls
+}
+
+// catalogMetric extracts one named metric's value from a CatalogEntry.
+// Every per-run metric CatalogEntry carries, plus accelerator_count, is
+// addressable this way; parameter_count, accelerator_memory_gib and
+// cost_delta_pct are not, and the integer run parameters (concurrency,
+// sequence lengths, tensor_parallel_degree) are labels. ok is false if
+// name is unknown or the run never recorded it.
+func catalogMetric(e CatalogEntry, name string) (float64, bool) {
+	switch name {
+	case "ttft_p50_ms":
+		return derefOK(e.TTFTP50Ms)
+	case "ttft_p99_ms":
+		return derefOK(e.TTFTP99Ms)
+	case "e2e_latency_p50_ms":
+		return derefOK(e.E2ELatencyP50Ms)
+	case "e2e_latency_p99_ms":
+		return derefOK(e.E2ELatencyP99Ms)
+	case "itl_p50_ms":
+		return derefOK(e.ITLP50Ms)
+	case "itl_p99_ms":
+		return derefOK(e.ITLP99Ms)
+	case "throughput_per_request_tps":
+		return derefOK(e.ThroughputPerRequestTPS)
+	case "throughput_aggregate_tps":
+		return derefOK(e.ThroughputAggregateTPS)
+	case "requests_per_second":
+		return derefOK(e.RequestsPerSecond)
+	case "accelerator_utilization_pct":
+		return derefOK(e.AcceleratorUtilizationPct)
+	case "accelerator_memory_peak_gib":
+		return derefOK(e.AcceleratorMemoryPeakGiB)
+	case "server_ttft_p50_ms":
+		return derefOK(e.ServerTTFTP50Ms)
+	case "server_ttft_p99_ms":
+		return derefOK(e.ServerTTFTP99Ms)
+	case "server_e2e_latency_p50_ms":
+		return derefOK(e.ServerE2ELatencyP50Ms)
+	case "server_e2e_latency_p99_ms":
+		return derefOK(e.ServerE2ELatencyP99Ms)
+	case "sm_active_peak_pct":
+		return derefOK(e.SMActivePeakPct)
+	case "power_avg_w":
+		return derefOK(e.PowerAvgW)
+	case "accelerator_count":
+		return float64(e.AcceleratorCount), true
+	default:
+		return 0, false
+	}
+}
+
+func derefOK(p *float64) (float64, bool) {
+	if p == nil {
+		return 0, false
+	}
+	return *p, true
+}
+
+// --- lexer ---
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokDuration
+	tokLBrace
+	tokRBrace
+	tokLParen
+	tokRParen
+	tokComma
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+}
+
+type lexer struct {
+	input []rune
+	pos   int
+}
+
+func newLexer(s string) *lexer { return &lexer{input: []rune(s)} }
+
+func isIdentStart(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+func isIdentPart(r rune) bool { return isIdentStart(r) || (r >= '0' && r <= '9') }
+func isDigit(r rune) bool     { return r >= '0' && r <= '9' }
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && (l.input[l.pos] == ' ' || l.input[l.pos] == '\t' || l.input[l.pos] == '\n') {
+		l.pos++
+	}
+	if l.pos >= len(l.input) {
+		return token{kind: tokEOF}, nil
+	}
+	ch := l.input[l.pos]
+	switch {
+	case ch == '{':
+		l.pos++
+		return token{kind: tokLBrace, val: "{"}, nil
+	case ch == '}':
+		l.pos++
+		return token{kind: tokRBrace, val: "}"}, nil
+	case ch == '(':
+		l.pos++
+		return token{kind: tokLParen, val: "("}, nil
+	case ch == ')':
+		l.pos++
+		return token{kind: tokRParen, val: ")"}, nil
+	case ch == '[':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.input) && l.input[l.pos] != ']' {
+			l.pos++
+		}
+		if l.pos >= len(l.input) {
+			return token{}, fmt.Errorf("unterminated range selector")
+		}
+		dur := string(l.input[start:l.pos])
+		l.pos++
+		return token{kind: tokDuration, val: dur}, nil
+	case ch == ',':
+		l.pos++
+		return token{kind: tokComma, val: ","}, nil
+	case ch == '"' || ch == '\'':
+		quote := ch
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.input) && l.input[l.pos] != quote {
+			l.pos++
+		}
+		if l.pos >= len(l.input) {
+			return token{}, fmt.Errorf("unterminated string literal")
+		}
+		s := string(l.input[start:l.pos])
+		l.pos++
+		return token{kind: tokString, val: s}, nil
+	case ch == '=' || ch == '!' || ch == '<' || ch == '>':
+		op := string(ch)
+		l.pos++
+		if l.pos < len(l.input) {
+			next := l.input[l.pos]
+			if (ch == '=' && (next == '=' || next == '~')) ||
+				(ch == '!' && (next == '=' || next == '~')) ||
+				((ch == '<' || ch == '>') && next == '=') {
+				op += string(next)
+				l.pos++
+			}
+		}
+		return token{kind: tokOp, val: op}, nil
+	case ch == '+' || ch == '-' || ch == '*' || ch == '/':
+		l.pos++
+		return token{kind: tokOp, val: string(ch)}, nil
+	case isDigit(ch) || ch == '.':
+		start := l.pos
+		for l.pos < len(l.input) && (isDigit(l.input[l.pos]) || l.input[l.pos] == '.') {
+			l.pos++
+		}
+		return token{kind: tokNumber, val: string(l.input[start:l.pos])}, nil
+	case isIdentStart(ch):
+		start := l.pos
+		for l.pos < len(l.input) && isIdentPart(l.input[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokIdent, val: string(l.input[start:l.pos])}, nil
+	default:
+		return token{}, fmt.Errorf("unexpected character %q", ch)
+	}
+}
+
+// --- AST ---
+
+type exprNode interface{ isExprNode() }
+
+type numberNode struct{ val float64 }
+
+func (numberNode) isExprNode() {}
+
+type labelMatcher struct {
+	label string
+	op    string // =, !=, =~, !~
+	value string
+	re    *regexp.Regexp
+}
+
+type selectorNode struct {
+	metric   string
+	matchers []labelMatcher
+	rangeDur *time.Duration // non-nil for a range-vector selector, e.g. ttft_ms[5m]
+}
+
+func (*selectorNode) isExprNode() {}
+
+type unaryNegNode struct{ inner exprNode }
+
+func (*unaryNegNode) isExprNode() {}
+
+type binaryNode struct {
+	op       string
+	lhs, rhs exprNode
+}
+
+func (*binaryNode) isExprNode() {}
+
+type aggNode struct {
+	op      string // sum, avg, min, max, topk, bottomk
+	by      []string
+	without []string
+	param   exprNode // k, for topk/bottomk
+	inner   exprNode
+}
+
+func (*aggNode) isExprNode() {}
+
+type funcNode struct {
+	name string
+	args []exprNode
+}
+
+func (*funcNode) isExprNode() {}
+
+var aggOps = map[string]bool{"sum": true, "avg": true, "min": true, "max": true, "topk": true, "bottomk": true}
+var funcNames = map[string]bool{"abs": true, "clamp": true, "ln": true, "quantile_over_time": true}
+
+func isComparisonOp(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+// --- parser ---
+
+type parser struct {
+	lex *lexer
+	cur token
+}
+
+func newParser(expr string) (*parser, error) {
+	p := &parser{lex: newLexer(expr)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *parser) advance() error {
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.cur = t
+	return nil
+}
+
+func (p *parser) expect(kind tokenKind, val string) error {
+	if p.cur.kind != kind || (val != "" && p.cur.val != val) {
+		return fmt.Errorf("expected %q, got %q", val, p.cur.val)
+	}
+	return p.advance()
+}
+
+// parsePromQL parses the supported subset described in iface.go's Repo
+// doc comment for QueryCatalog: instant selectors, binary ops,
+// sum/avg/min/max/topk/bottomk with by/without, and abs/clamp/ln/
+// quantile_over_time.
+func parsePromQL(expr string) (exprNode, error) {
+	p, err := newParser(expr)
+	if err != nil {
+		return nil, err
+	}
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.cur.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected trailing token %q", p.cur.val)
+	}
+	return node, nil
+}
+
+func (p *parser) parseExpr() (exprNode, error) { return p.parseComparison() }
+
+func (p *parser) parseComparison() (exprNode, error) {
+	lhs, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for p.cur.kind == tokOp && isComparisonOp(p.cur.val) {
+		op := p.cur.val
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		rhs, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &binaryNode{op: op, lhs: lhs, rhs: rhs}
+	}
+	return lhs, nil
+}
+
+func (p *parser) parseAdditive() (exprNode, error) {
+	lhs, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.cur.kind == tokOp && (p.cur.val == "+" || p.cur.val == "-") {
+		op := p.cur.val
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		rhs, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &binaryNode{op: op, lhs: lhs, rhs: rhs}
+	}
+	return lhs, nil
+}
+
+func (p *parser) parseMultiplicative() (exprNode, error) {
+	lhs, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.cur.kind == tokOp && (p.cur.val == "*" || p.cur.val == "/") {
+		op := p.cur.val
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		rhs, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &binaryNode{op: op, lhs: lhs, rhs: rhs}
+	}
+	return lhs, nil
+}
+
+func (p *parser) parseUnary() (exprNode, error) {
+	if p.cur.kind == tokOp && p.cur.val == "-" {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNegNode{inner: inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (exprNode, error) {
+	switch p.cur.kind {
+	case tokNumber:
+		v, err := strconv.ParseFloat(p.cur.val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", p.cur.val)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return &numberNode{val: v}, nil
+	case tokLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokRParen, ")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokIdent:
+		name := p.cur.val
+		if aggOps[name] {
+			return p.parseAggregation(name)
+		}
+		if funcNames[name] {
+			return p.parseFuncCall(name)
+		}
+		return p.parseSelector(name)
+	default:
+		return nil, fmt.Errorf("unexpected token %q", p.cur.val)
+	}
+}
+
+func (p *parser) parseSelector(name string) (exprNode, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	sel := &selectorNode{metric: name}
+	if p.cur.kind == tokLBrace {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for p.cur.kind != tokRBrace {
+			m, err := p.parseMatcher()
+			if err != nil {
+				return nil, err
+			}
+			sel.matchers = append(sel.matchers, m)
+			if p.cur.kind == tokComma {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			break
+		}
+		if err := p.expect(tokRBrace, "}"); err != nil {
+			return nil, err
+		}
+	}
+	if p.cur.kind == tokDuration {
+		d, err := time.ParseDuration(p.cur.val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range duration %q: %w", p.cur.val, err)
+		}
+		sel.rangeDur = &d
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return sel, nil
+}
+
+func (p *parser) parseMatcher() (labelMatcher, error) {
+	if p.cur.kind != tokIdent {
+		return labelMatcher{}, fmt.Errorf("expected label name, got %q", p.cur.val)
+	}
+	label := p.cur.val
+	if err := p.advance(); err != nil {
+		return labelMatcher{}, err
+	}
+	if p.cur.kind != tokOp {
+		return labelMatcher{}, fmt.Errorf("expected label matcher operator, got %q", p.cur.val)
+	}
+	op := p.cur.val
+	if op != "=" && op != "!=" && op != "=~" && op != "!~" {
+		return labelMatcher{}, fmt.Errorf("invalid label matcher operator %q", op)
+	}
+	if err := p.advance(); err != nil {
+		return labelMatcher{}, err
+	}
+	if p.cur.kind != tokString {
+		return labelMatcher{}, fmt.Errorf("expected quoted matcher value, got %q", p.cur.val)
+	}
+	val := p.cur.val
+	if err := p.advance(); err != nil {
+		return labelMatcher{}, err
+	}
+	m := labelMatcher{label: label, op: op, value: val}
+	if op == "=~" || op == "!~" {
+		re, err := regexp.Compile("^(?:" + val + ")$")
+		if err != nil {
+			return labelMatcher{}, fmt.Errorf("invalid regex %q: %w", val, err)
+		}
+		m.re = re
+	}
+	return m, nil
+}
+
+func (p *parser) parseAggregation(op string) (exprNode, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	node := &aggNode{op: op}
+
+	if p.cur.kind == tokIdent && (p.cur.val == "by" || p.cur.val == "without") {
+		kind, names, err := p.parseGrouping()
+		if err != nil {
+			return nil, err
+		}
+		if kind == "by" {
+			node.by = names
+		} else {
+			node.without = names
+		}
+	}
+
+	if err := p.expect(tokLParen, "("); err != nil {
+		return nil, err
+	}
+
+	if op == "topk" || op == "bottomk" {
+		param, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		node.param = param
+		if err := p.expect(tokComma, ","); err != nil {
+			return nil, err
+		}
+	}
+
+	inner, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	node.inner = inner
+	if err := p.expect(tokRParen, ")"); err != nil {
+		return nil, err
+	}
+
+	if node.by == nil && node.without == nil && p.cur.kind == tokIdent && (p.cur.val == "by" || p.cur.val == "without") {
+		kind, names, err := p.parseGrouping()
+		if err != nil {
+			return nil, err
+		}
+		if kind == "by" {
+			node.by = names
+		} else {
+			node.without = names
+		}
+	}
+	return node, nil
+}
+
+func (p *parser) parseGrouping() (string, []string, error) {
+	kind := p.cur.val
+	if err := p.advance(); err != nil {
+		return "", nil, err
+	}
+	if err := p.expect(tokLParen, "("); err != nil {
+		return "", nil, err
+	}
+	var names []string
+	for p.cur.kind != tokRParen {
+		if p.cur.kind != tokIdent {
+			return "", nil, fmt.Errorf("expected label name in grouping, got %q", p.cur.val)
+		}
+		names = append(names, p.cur.val)
+		if err := p.advance(); err != nil {
+			return "", nil, err
+		}
+		if p.cur.kind == tokComma {
+			if err := p.advance(); err != nil {
+				return "", nil, err
+			}
+			continue
+		}
+		break
+	}
+	if err := p.expect(tokRParen, ")"); err != nil {
+		return "", nil, err
+	}
+	return kind, names, nil
+}
+
+func (p *parser) parseFuncCall(name string) (exprNode, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if err := p.expect(tokLParen, "("); err != nil {
+		return nil, err
+	}
+	node := &funcNode{name: name}
+	for p.cur.kind != tokRParen {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		node.args = append(node.args, arg)
+		if p.cur.kind == tokComma {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		break
+	}
+	if err := p.expect(tokRParen, ")"); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// --- evaluation ---
+
+type evalCtx struct {
+	entries []CatalogEntry
+	fetch   sampleFetcher
+	at      time.Time
+}
+
+// evalResult is either a scalar or a vector, mirroring PromQL's type
+// system just enough to support vector-scalar and vector-vector binary ops.
+type evalResult struct {
+	scalar *float64
+	vector Vector
+}
+
+func scalarResult(v float64) evalResult { return evalResult{scalar: &v} }
+func vectorResult(v Vector) evalResult  { return evalResult{vector: v} }
+
+func eval(node exprNode, ctx *evalCtx) (evalResult, error) {
+	switch n := node.(type) {
+	case *numberNode:
+		return scalarResult(n.val), nil
+	case *selectorNode:
+		return evalSelector(n, ctx)
+	case *unaryNegNode:
+		inner, err := eval(n.inner, ctx)
+		if err != nil {
+			return evalResult{}, err
+		}
+		return mapResult(inner, func(v float64) float64 { return -v }), nil
+	case *binaryNode:
+		return evalBinary(n, ctx)
+	case *aggNode:
+		return evalAgg(n, ctx)
+	case *funcNode:
+		return evalFunc(n, ctx)
+	default:
+		return evalResult{}, fmt.Errorf("unknown expression node %T", node)
+	}
+}
+
+func mapResult(r evalResult, f func(float64) float64) evalResult {
+	if r.scalar != nil {
+		return scalarResult(f(*r.scalar))
+	}
+	out := make(Vector, len(r.vector))
+	for i, s := range r.vector {
+		out[i] = VectorSample{Labels: s.Labels, Value: f(s.Value)}
+	}
+	return vectorResult(out)
+}
+
+func evalSelector(n *selectorNode, ctx *evalCtx) (evalResult, error) {
+	if n.rangeDur != nil {
+		return evalResult{}, fmt.Errorf("range vector %s[...] is only valid as quantile_over_time's second argument", n.metric)
+	}
+	var out Vector
+	for _, e := range ctx.entries {
+		labels := catalogLabels(e)
+		if !matchesAll(labels, n.matchers) {
+			continue
+		}
+		v, ok := catalogMetric(e, n.metric)
+		if !ok {
+			continue
+		}
+		out = append(out, VectorSample{Labels: labels, Value: v})
+	}
+	return vectorResult(out), nil
+}
+
+func matchesAll(labels LabelSet, matchers []labelMatcher) bool {
+	for _, m := range matchers {
+		if !matchOne(labels[m.label], m) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchOne(val string, m labelMatcher) bool {
+	switch m.op {
+	case "=":
+		return val == m.value
+	case "!=":
+		return val != m.value
+	case "=~":
+		return m.re.MatchString(val)
+	case "!~":
+		return !m.re.MatchString(val)
+	default:
+		return false
+	}
+}
+
+func evalBinary(n *binaryNode, ctx *evalCtx) (evalResult, error) {
+	lhs, err := eval(n.lhs, ctx)
+	if err != nil {
+		return evalResult{}, err
+	}
+	rhs, err := eval(n.rhs, ctx)
+	if err != nil {
+		return evalResult{}, err
+	}
+
+	if isComparisonOp(n.op) {
+		cmp, err := comparisonFunc(n.op)
+		if err != nil {
+			return evalResult{}, err
+		}
+		return evalComparison(lhs, rhs, cmp), nil
+	}
+
+	arith, err := arithmeticFunc(n.op)
+	if err != nil {
+		return evalResult{}, err
+	}
+	return evalArithmetic(lhs, rhs, arith), nil
+}
+
+func arithmeticFunc(op string) (func(a, b float64) float64, error) {
+	switch op {
+	case "+":
+		return func(a, b float64) float64 { return a + b }, nil
+	case "-":
+		return func(a, b float64) float64 { return a - b }, nil
+	case "*":
+		return func(a, b float64) float64 { return a * b }, nil
+	case "/":
+		return func(a, b float64) float64 { return a / b }, nil
+	default:
+		return nil, fmt.Errorf("unsupported binary operator %q", op)
+	}
+}
+
+func comparisonFunc(op string) (func(a, b float64) bool, error) {
+	switch op {
+	case "==":
+		return func(a, b float64) bool { return a == b }, nil
+	case "!=":
+		return func(a, b float64) bool { return a != b }, nil
+	case "<":
+		return func(a, b float64) bool { return a < b }, nil
+	case "<=":
+		return func(a, b float64) bool { return a <= b }, nil
+	case ">":
+		return func(a, b float64) bool { return a > b }, nil
+	case ">=":
+		return func(a, b float64) bool { return a >= b }, nil
+	default:
+		return nil, fmt.Errorf("unsupported comparison operator %q", op)
+	}
+}
+
+func evalArithmetic(lhs, rhs evalResult, f func(a, b float64) float64) evalResult {
+	switch {
+	case lhs.scalar != nil && rhs.scalar != nil:
+		return scalarResult(f(*lhs.scalar, *rhs.scalar))
+	case lhs.scalar != nil:
+		out := make(Vector, len(rhs.vector))
+		for i, s := range rhs.vector {
+			out[i] = VectorSample{Labels: s.Labels, Value: f(*lhs.scalar, s.Value)}
+		}
+		return vectorResult(out)
+	case rhs.scalar != nil:
+		out := make(Vector, len(lhs.vector))
+		for i, s := range lhs.vector {
+			out[i] = VectorSample{Labels: s.Labels, Value: f(s.Value, *rhs.scalar)}
+		}
+		return vectorResult(out)
+	default:
+		byRunID := make(map[string]float64, len(rhs.vector))
+		for _, s := range rhs.vector {
+			byRunID[s.Labels["run_id"]] = s.Value
+		}
+		out := make(Vector, 0, len(lhs.vector))
+		for _, l := range lhs.vector {
+			rv, ok := byRunID[l.Labels["run_id"]]
+			if !ok {
+				continue
+			}
+			out = append(out, VectorSample{Labels: l.Labels, Value: f(l.Value, rv)})
+		}
+		return vectorResult(out)
+	}
+}
+
+// evalComparison applies PromQL's default "filter" semantics: elements
+// where the comparison is false are dropped rather than replaced with 0/1,
+// and surviving vector elements keep their original value and labels.
+func evalComparison(lhs, rhs evalResult, cmp func(a, b float64) bool) evalResult {
+	switch {
+	case lhs.scalar != nil && rhs.scalar != nil:
+		v := 0.0
+		if cmp(*lhs.scalar, *rhs.scalar) {
+			v = 1
+		}
+		return scalarResult(v)
+	case lhs.scalar != nil:
+		out := make(Vector, 0, len(rhs.vector))
+		for _, s := range rhs.vector {
+			if cmp(*lhs.scalar, s.Value) {
+				out = append(out, s)
+			}
+		}
+		return vectorResult(out)
+	case rhs.scalar != nil:
+		out := make(Vector, 0, len(lhs.vector))
+		for _, s := range lhs.vector {
+			if cmp(s.Value, *rhs.scalar) {
+				out = append(out, s)
+			}
+		}
+		return vectorResult(out)
+	default:
+		byRunID := make(map[string]float64, len(rhs.vector))
+		for _, s := range rhs.vector {
+			byRunID[s.Labels["run_id"]] = s.Value
+		}
+		out := make(Vector, 0, len(lhs.vector))
+		for _, l := range lhs.vector {
+			rv, ok := byRunID[l.Labels["run_id"]]
+			if ok && cmp(l.Value, rv) {
+				out = append(out, l)
+			}
+		}
+		return vectorResult(out)
+	}
+}
+
+func evalAgg(n *aggNode, ctx *evalCtx) (evalResult, error) {
+	inner, err := eval(n.inner, ctx)
+	if err != nil {
+		return evalResult{}, err
+	}
+	if inner.scalar != nil {
+		return evalResult{}, fmt.Errorf("%s: aggregation requires a vector, got a scalar", n.op)
+	}
+
+	if n.op == "topk" || n.op == "bottomk" {
+		return evalTopK(n, inner.vector, ctx)
+	}
+
+	reduce, err := aggReducer(n.op)
+	if err != nil {
+		return evalResult{}, err
+	}
+
+	groupOf := groupingFunc(n.by, n.without)
+	type group struct {
+		labels LabelSet
+		values []float64
+	}
+	groups := make(map[string]*group)
+	var order []string
+	for _, s := range inner.vector {
+		key, labels := groupOf(s.Labels)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{labels: labels}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.values = append(g.values, s.Value)
+	}
+
+	out := make(Vector, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		out = append(out, VectorSample{Labels: g.labels, Value: reduce(g.values)})
+	}
+	return vectorResult(out), nil
+}
+
+func groupingFunc(by, without []string) func(LabelSet) (string, LabelSet) {
+	switch {
+	case len(by) > 0:
+		return func(ls LabelSet) (string, LabelSet) {
+			g := ls.only(by)
+			return g.key(), g
+		}
+	case len(without) > 0:
+		return func(ls LabelSet) (string, LabelSet) {
+			g := ls.without(without)
+			return g.key(), g
+		}
+	default:
+		return func(LabelSet) (string, LabelSet) { return "", LabelSet{} }
+	}
+}
+
+func aggReducer(op string) (func([]float64) float64, error) {
+	switch op {
+	case "sum":
+		return func(vs []float64) float64 {
+			var s float64
+			for _, v := range vs {
+				s += v
+			}
+			return s
+		}, nil
+	case "avg":
+		return func(vs []float64) float64 {
+			var s float64
+			for _, v := range vs {
+				s += v
+			}
+			return s / float64(len(vs))
+		}, nil
+	case "min":
+		return func(vs []float64) float64 {
+			m := vs[0]
+			for _, v := range vs[1:] {
+				if v < m {
+					m = v
+				}
+			}
+			return m
+		}, nil
+	case "max":
+		return func(vs []float64) float64 {
+			m := vs[0]
+			for _, v := range vs[1:] {
+				if v > m {
+					m = v
+				}
+			}
+			return m
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported aggregation %q", op)
+	}
+}
+
+func evalTopK(n *aggNode, vec Vector, ctx *evalCtx) (evalResult, error) {
+	paramRes, err := eval(n.param, ctx)
+	if err != nil {
+		return evalResult{}, err
+	}
+	if paramRes.scalar == nil {
+		return evalResult{}, fmt.Errorf("%s: k must be a scalar", n.op)
+	}
+	k := int(*paramRes.scalar)
+	if k < 0 {
+		k = 0
+	}
+
+	groupOf := groupingFunc(n.by, n.without)
+	groups := make(map[string][]VectorSample)
+	var order []string
+	for _, s := range vec {
+		key, _ := groupOf(s.Labels)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], s)
+	}
+
+	desc := n.op == "topk"
+	var out Vector
+	for _, key := range order {
+		members := groups[key]
+		sort.SliceStable(members, func(i, j int) bool {
+			if desc {
+				return members[i].Value > members[j].Value
+			}
+			return members[i].Value < members[j].Value
+		})
+		if k < len(members) {
+			members = members[:k]
+		}
+		out = append(out, members...)
+	}
+	return vectorResult(out), nil
+}
+
+func evalFunc(n *funcNode, ctx *evalCtx) (evalResult, error) {
+	switch n.name {
+	case "abs":
+		if len(n.args) != 1 {
+			return evalResult{}, fmt.Errorf("abs takes exactly 1 argument")
+		}
+		arg, err := eval(n.args[0], ctx)
+		if err != nil {
+			return evalResult{}, err
+		}
+		return mapResult(arg, math.Abs), nil
+	case "ln":
+		if len(n.args) != 1 {
+			return evalResult{}, fmt.Errorf("ln takes exactly 1 argument")
+		}
+		arg, err := eval(n.args[0], ctx)
+		if err != nil {
+			return evalResult{}, err
+		}
+		return mapResult(arg, math.Log), nil
+	case "clamp":
+		if len(n.args) != 3 {
+			return evalResult{}, fmt.Errorf("clamp takes exactly 3 arguments (vector, min, max)")
+		}
+		vec, err := eval(n.args[0], ctx)
+		if err != nil {
+			return evalResult{}, err
+		}
+		minR, err := eval(n.args[1], ctx)
+		if err != nil {
+			return evalResult{}, err
+		}
+		maxR, err := eval(n.args[2], ctx)
+		if err != nil {
+			return evalResult{}, err
+		}
+		if minR.scalar == nil || maxR.scalar == nil {
+			return evalResult{}, fmt.Errorf("clamp's min and max must be scalars")
+		}
+		lo, hi := *minR.scalar, *maxR.scalar
+		return mapResult(vec, func(v float64) float64 {
+			if v < lo {
+				return lo
+			}
+			if v > hi {
+				return hi
+			}
+			return v
+		}), nil
+	case "quantile_over_time":
+		return evalQuantileOverTime(n, ctx)
+	default:
+		return evalResult{}, fmt.Errorf("unsupported function %q", n.name)
+	}
+}
+
+// evalQuantileOverTime computes the phi-quantile of a run's raw Samples
+// within [at-range, at], per matching catalog entry — the one place this
+// query layer reaches into the raw per-request samples (timeseries.go)
+// rather than the per-run summary CatalogEntry.
+func evalQuantileOverTime(n *funcNode, ctx *evalCtx) (evalResult, error) {
+	if len(n.args) != 2 {
+		return evalResult{}, fmt.Errorf("quantile_over_time takes exactly 2 arguments (phi, range-vector)")
+	}
+	phiRes, err := eval(n.args[0], ctx)
+	if err != nil {
+		return evalResult{}, err
+	}
+	if phiRes.scalar == nil {
+		return evalResult{}, fmt.Errorf("quantile_over_time's phi must be a scalar")
+	}
+	phi := *phiRes.scalar
+
+	sel, ok := n.args[1].(*selectorNode)
+	if !ok || sel.rangeDur == nil {
+		return evalResult{}, fmt.Errorf("quantile_over_time's second argument must be a range vector selector, e.g. ttft_ms[5m]")
+	}
+	if ctx.fetch == nil {
+		return evalResult{}, fmt.Errorf("quantile_over_time requires a sample-backed query")
+	}
+
+	from := ctx.at.Add(-*sel.rangeDur)
+	var out Vector
+	for _, e := range ctx.entries {
+		labels := catalogLabels(e)
+		if !matchesAll(labels, sel.matchers) {
+			continue
+		}
+		samples, err := ctx.fetch(e.RunID)
+		if err != nil {
+			return evalResult{}, fmt.Errorf("fetch samples for run %s: %w", e.RunID, err)
+		}
+		var values []float64
+		for _, s := range samples {
+			if s.Timestamp.Before(from) || s.Timestamp.After(ctx.at) {
+				continue
+			}
+			if v, ok := sampleMetric(s, sel.metric); ok {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		out = append(out, VectorSample{Labels: labels, Value: quantile(values, phi)})
+	}
+	return vectorResult(out), nil
+}
+
+// quantile computes the phi-quantile of vs by linear interpolation between
+// closest ranks, the same method Prometheus's own quantile_over_time uses.
+func quantile(vs []float64, phi float64) float64 {
+	sorted := append([]float64(nil), vs...)
+	sort.Float64s(sorted)
+	if phi <= 0 {
+		return sorted[0]
+	}
+	if phi >= 1 {
+		return sorted[len(sorted)-1]
+	}
+	idx := phi * float64(len(sorted)-1)
+	lo := int(math.Floor(idx))
+	hi := int(math.Ceil(idx))
+	if lo == hi {
+		return sorted[lo]
+	}
+	frac := idx - float64(lo)
+	return sorted[lo] + (sorted[hi]-sorted[lo])*frac
+}
+
+// evalCatalogQuery parses and evaluates expr as an instant query over
+// entries as of at. This is the shared body behind every Repo backend's
+// QueryCatalog.
+func evalCatalogQuery(entries []CatalogEntry, fetch sampleFetcher, expr string, at time.Time) (Vector, error) {
+	node, err := parsePromQL(expr)
+	if err != nil {
+		return nil, fmt.Errorf("parse query: %w", err)
+	}
+	res, err := eval(node, &evalCtx{entries: entries, fetch: fetch, at: at})
+	if err != nil {
+		return nil, err
+	}
+	if res.scalar != nil {
+		return Vector{{Labels: LabelSet{}, Value: *res.scalar}}, nil
+	}
+	return res.vector, nil
+}
+
+// evalCatalogRangeQuery evaluates expr at each step-spaced instant between
+// from and to, stitching the resulting per-instant Vectors into a Matrix
+// keyed by label set. This is the shared body behind every Repo backend's
+// QueryCatalogRange.
+func evalCatalogRangeQuery(entries []CatalogEntry, fetch sampleFetcher, expr string, from, to time.Time, step time.Duration) (Matrix, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be positive")
+	}
+	node, err := parsePromQL(expr)
+	if err != nil {
+		return nil, fmt.Errorf("parse query: %w", err)
+	}
+
+	series := make(map[string]*Series)
+	var order []string
+	for t := from; !t.After(to); t = t.Add(step) {
+		res, err := eval(node, &evalCtx{entries: entries, fetch: fetch, at: t})
+		if err != nil {
+			return nil, err
+		}
+		vec := res.vector
+		if res.scalar != nil {
+			vec = Vector{{Labels: LabelSet{}, Value: *res.scalar}}
+		}
+		for _, s := range vec {
+			key := s.Labels.key()
+			ser, ok := series[key]
+			if !ok {
+				ser = &Series{Labels: s.Labels}
+				series[key] = ser
+				order = append(order, key)
+			}
+			ser.Points = append(ser.Points, MatrixPoint{Timestamp: t, Value: s.Value})
+		}
+	}
+
+	out := make(Matrix, 0, len(order))
+	for _, key := range order {
+		out = append(out, *series[key])
+	}
+	return out, nil
+}
+
+// QueryCatalog evaluates a PromQL-style expr against the catalog as of at.
+func (m *MockRepo) QueryCatalog(ctx context.Context, expr string, at time.Time) (Vector, error) {
+	entries, _, err := m.ListCatalog(ctx, CatalogFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return evalCatalogQuery(entries, m.fetchSamplesForQuery, expr, at)
+}
+
+// QueryCatalogRange evaluates expr at each step between from and to.
+func (m *MockRepo) QueryCatalogRange(ctx context.Context, expr string, from, to time.Time, step time.Duration) (Matrix, error) {
+	entries, _, err := m.ListCatalog(ctx, CatalogFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return evalCatalogRangeQuery(entries, m.fetchSamplesForQuery, expr, from, to, step)
+}
+
+func (m *MockRepo) fetchSamplesForQuery(runID string) ([]Sample, error) {
+	return m.samples.all(runID), nil
+}
+
+// QueryCatalog evaluates a PromQL-style expr against the catalog as of at.
+func (r *Repository) QueryCatalog(ctx context.Context, expr string, at time.Time) (Vector, error) {
+	entries, _, err := r.ListCatalog(ctx, CatalogFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return evalCatalogQuery(entries, r.fetchSamplesForQuery(ctx), expr, at)
+}
+
+// QueryCatalogRange evaluates expr at each step between from and to.
+func (r *Repository) QueryCatalogRange(ctx context.Context, expr string, from, to time.Time, step time.Duration) (Matrix, error) {
+	entries, _, err := r.ListCatalog(ctx, CatalogFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return evalCatalogRangeQuery(entries, r.fetchSamplesForQuery(ctx), expr, from, to, step)
+}
+
+// fetchSamplesForQuery returns a sampleFetcher bound to ctx, reading every
+// column quantile_over_time might need — unlike VerifyRun's monotonicity
+// check, which only needs tokens_out.
+func (r *Repository) fetchSamplesForQuery(ctx context.Context) sampleFetcher {
+	return func(runID string) ([]Sample, error) {
+		ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+		defer cancel()
+
+		rows, err := r.pool.Query(ctx,
+			`SELECT ts, ttft_ms, itl_ms, tokens_out, accelerator_utilization_pct
+			 FROM benchmark_samples WHERE run_id = $1 ORDER BY ts`, runID)
+		if err != nil {
+			return nil, fmt.Errorf("query samples: %w", err)
+		}
+		defer rows.Close()
+		var samples []Sample
+		for rows.Next() {
+			var s Sample
+			if err := rows.Scan(&s.Timestamp, &s.TTFTMs, &s.ITLMs, &s.TokensOut, &s.AcceleratorUtilizationPct); err != nil {
+				return nil, fmt.Errorf("scan sample: %w", err)
+			}
+			samples = append(samples, s)
+		}
+		return samples, rows.Err()
+	}
+}
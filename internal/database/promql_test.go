@@ -0,0 +1,197 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// seedPromqlRepo creates a MockRepo with catalog runs carrying distinct
+// throughput/ttft values (unlike seedCatalogRepo's uniform fixture), so
+// aggregation and topk results are deterministic.
+func seedPromqlRepo(t *testing.T) (*MockRepo, []string) {
+	t.Helper()
+	repo := NewMockRepo()
+
+	llama := "llama"
+	mistral := "mistral"
+	repo.SeedModel(&Model{ID: "m1", HfID: "meta-llama/Llama-3.1-8B", HfRevision: "abc", ModelFamily: &llama})
+	repo.SeedModel(&Model{ID: "m2", HfID: "meta-llama/Llama-3.1-70B", HfRevision: "def", ModelFamily: &llama})
+	repo.SeedModel(&Model{ID: "m3", HfID: "mistralai/Mistral-7B", HfRevision: "ghi", ModelFamily: &mistral})
+
+	repo.SeedInstanceType(&InstanceType{ID: "i1", Name: "g5.xlarge", Family: "g5", AcceleratorType: "gpu", AcceleratorName: "A10G", AcceleratorCount: 1})
+	repo.SeedInstanceType(&InstanceType{ID: "i2", Name: "p5.48xlarge", Family: "p5", AcceleratorType: "gpu", AcceleratorName: "H100", AcceleratorCount: 8})
+
+	ctx := context.Background()
+	cases := []struct {
+		modelID, instID string
+		ttft, tps       float64
+	}{
+		{"m1", "i1", 10, 100},
+		{"m2", "i2", 20, 400},
+		{"m3", "i1", 30, 300},
+	}
+
+	runIDs := make([]string, len(cases))
+	for i, c := range cases {
+		run := &BenchmarkRun{
+			ModelID: c.modelID, InstanceTypeID: c.instID,
+			Framework: "vllm", FrameworkVersion: "v0.6.0",
+			TensorParallelDegree: 1, Concurrency: 16,
+			InputSequenceLength: 512, OutputSequenceLength: 256,
+			DatasetName: "sharegpt", RunType: "catalog", Status: "pending",
+		}
+		id, err := repo.CreateBenchmarkRun(ctx, run)
+		if err != nil {
+			t.Fatalf("CreateBenchmarkRun: %v", err)
+		}
+		ttft, tps := c.ttft, c.tps
+		if err := repo.PersistMetrics(ctx, id, &BenchmarkMetrics{TTFTP50Ms: &ttft, ThroughputAggregateTPS: &tps}, nil); err != nil {
+			t.Fatalf("PersistMetrics: %v", err)
+		}
+		runIDs[i] = id
+	}
+
+	return repo, runIDs
+}
+
+func TestQueryCatalog_SelectorWithLabelMatcher(t *testing.T) {
+	repo, _ := seedPromqlRepo(t)
+	vec, err := repo.QueryCatalog(context.Background(), `ttft_p50_ms{model_family="llama"}`, time.Now())
+	if err != nil {
+		t.Fatalf("QueryCatalog: %v", err)
+	}
+	if len(vec) != 2 {
+		t.Fatalf("got %d samples, want 2", len(vec))
+	}
+	for _, s := range vec {
+		if s.Labels["model_family"] != "llama" {
+			t.Errorf("unexpected model_family: %s", s.Labels["model_family"])
+		}
+	}
+}
+
+func TestQueryCatalog_BinaryArithmetic(t *testing.T) {
+	repo, runIDs := seedPromqlRepo(t)
+	vec, err := repo.QueryCatalog(context.Background(), `throughput_aggregate_tps / accelerator_count`, time.Now())
+	if err != nil {
+		t.Fatalf("QueryCatalog: %v", err)
+	}
+	// m1/i1: 100/1, m2/i2: 400/8, m3/i1: 300/1.
+	want := map[string]float64{runIDs[0]: 100, runIDs[1]: 50, runIDs[2]: 300}
+	if len(vec) != 3 {
+		t.Fatalf("got %d samples, want 3", len(vec))
+	}
+	for _, s := range vec {
+		w, ok := want[s.Labels["run_id"]]
+		if !ok {
+			t.Fatalf("unexpected run_id: %s", s.Labels["run_id"])
+		}
+		if s.Value != w {
+			t.Errorf("run_id=%s: got %g, want %g", s.Labels["run_id"], s.Value, w)
+		}
+	}
+}
+
+func TestQueryCatalog_AggregationByLabel(t *testing.T) {
+	repo, _ := seedPromqlRepo(t)
+	vec, err := repo.QueryCatalog(context.Background(), `sum(ttft_p50_ms) by (model_family)`, time.Now())
+	if err != nil {
+		t.Fatalf("QueryCatalog: %v", err)
+	}
+	if len(vec) != 2 {
+		t.Fatalf("got %d series, want 2", len(vec))
+	}
+	for _, s := range vec {
+		switch s.Labels["model_family"] {
+		case "llama":
+			if s.Value != 30 {
+				t.Errorf("llama sum = %g, want 30", s.Value)
+			}
+		case "mistral":
+			if s.Value != 30 {
+				t.Errorf("mistral sum = %g, want 30", s.Value)
+			}
+		default:
+			t.Errorf("unexpected group: %+v", s.Labels)
+		}
+	}
+}
+
+func TestQueryCatalog_TopK(t *testing.T) {
+	repo, _ := seedPromqlRepo(t)
+	vec, err := repo.QueryCatalog(context.Background(), `topk(1, throughput_aggregate_tps)`, time.Now())
+	if err != nil {
+		t.Fatalf("QueryCatalog: %v", err)
+	}
+	if len(vec) != 1 {
+		t.Fatalf("got %d samples, want 1", len(vec))
+	}
+	if vec[0].Value != 400 {
+		t.Errorf("top value = %g, want 400", vec[0].Value)
+	}
+}
+
+func TestQueryCatalog_Function(t *testing.T) {
+	repo, _ := seedPromqlRepo(t)
+	vec, err := repo.QueryCatalog(context.Background(), `clamp(ttft_p50_ms, 15, 25)`, time.Now())
+	if err != nil {
+		t.Fatalf("QueryCatalog: %v", err)
+	}
+	if len(vec) != 3 {
+		t.Fatalf("got %d samples, want 3", len(vec))
+	}
+	for _, s := range vec {
+		if s.Value < 15 || s.Value > 25 {
+			t.Errorf("value %g escaped clamp bounds", s.Value)
+		}
+	}
+}
+
+func TestQueryCatalog_QuantileOverTime(t *testing.T) {
+	repo, runIDs := seedPromqlRepo(t)
+	ctx := context.Background()
+	runID := runIDs[0]
+
+	if err := repo.UpdateRunStatus(ctx, runID, "running"); err != nil {
+		t.Fatalf("UpdateRunStatus: %v", err)
+	}
+	base := time.Now().Truncate(time.Second)
+	samples := make([]Sample, 0, 10)
+	for i := 0; i < 10; i++ {
+		v := float64(10 * (i + 1))
+		samples = append(samples, Sample{Timestamp: base.Add(time.Duration(i) * time.Second), TTFTMs: &v, TokensOut: 1})
+	}
+	if err := repo.AppendSamples(ctx, runID, samples); err != nil {
+		t.Fatalf("AppendSamples: %v", err)
+	}
+	if err := repo.UpdateRunStatus(ctx, runID, "completed"); err != nil {
+		t.Fatalf("UpdateRunStatus: %v", err)
+	}
+
+	at := base.Add(9 * time.Second)
+	vec, err := repo.QueryCatalog(ctx, `quantile_over_time(0.5, ttft_ms[1h])`, at)
+	if err != nil {
+		t.Fatalf("QueryCatalog: %v", err)
+	}
+	var found bool
+	for _, s := range vec {
+		if s.Labels["run_id"] != runID {
+			continue
+		}
+		found = true
+		if s.Value != 55 {
+			t.Errorf("median ttft_ms = %g, want 55", s.Value)
+		}
+	}
+	if !found {
+		t.Fatalf("no result for run %s", runID)
+	}
+}
+
+func TestQueryCatalog_InvalidExpr(t *testing.T) {
+	repo, _ := seedPromqlRepo(t)
+	if _, err := repo.QueryCatalog(context.Background(), `sum(`, time.Now()); err == nil {
+		t.Error("expected error for malformed expression")
+	}
+}
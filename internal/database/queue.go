@@ -0,0 +1,250 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// QueuedRun is one run waiting in the scheduler's persistent admission
+// queue. The run's own parameters live on its BenchmarkRun row (including
+// OriginalRequest, which the scheduler needs to rebuild an
+// orchestrator.RunConfig); QueuedRun carries only what the queue itself
+// needs to order and admit it.
+type QueuedRun struct {
+	RunID          string
+	InstanceFamily string
+	UserID         string
+	Priority       int
+	EnqueuedAt     time.Time
+}
+
+// EnqueueRun admits runID into the persistent run queue and atomically
+// moves its benchmark_runs row to status "queued" with QueuedAt stamped,
+// so a process restart doesn't lose a run the scheduler hasn't dispatched
+// to the cluster yet, and a caller never observes the run sitting in
+// run_queue while its status still reads a stale value (e.g. right after
+// Scheduler.Requeue re-admits a completed run). instanceFamily is
+// recorded so the scheduler's per-family concurrency caps survive a
+// restart too; userID (empty if the submitter carried no identity) is
+// recorded so a Quota.MaxConcurrentPerUser cap can do the same.
+func (r *Repository) EnqueueRun(ctx context.Context, runID, instanceFamily, userID string, priority int) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO run_queue (run_id, instance_family, user_id, priority, enqueued_at)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		runID, instanceFamily, userID, priority, now,
+	); err != nil {
+		return fmt.Errorf("insert run queue entry: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE benchmark_runs SET status = 'queued', queued_at = $1 WHERE id = $2`, now, runID,
+	); err != nil {
+		return fmt.Errorf("stamp queued_at: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	r.broker.Publish(runID, "queued", nil)
+	return nil
+}
+
+// ListQueuedRuns returns every still-queued run in dispatch order:
+// priority descending, then EnqueuedAt ascending within a priority tier.
+func (r *Repository) ListQueuedRuns(ctx context.Context) ([]QueuedRun, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT run_id, instance_family, user_id, priority, enqueued_at
+		 FROM run_queue ORDER BY priority DESC, enqueued_at ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("query run queue: %w", err)
+	}
+	defer rows.Close()
+
+	var queued []QueuedRun
+	for rows.Next() {
+		var q QueuedRun
+		if err := rows.Scan(&q.RunID, &q.InstanceFamily, &q.UserID, &q.Priority, &q.EnqueuedAt); err != nil {
+			return nil, fmt.Errorf("scan run queue row: %w", err)
+		}
+		queued = append(queued, q)
+	}
+	return queued, rows.Err()
+}
+
+// DequeueRun removes runID from the persistent queue once the scheduler
+// has admitted it for execution.
+func (r *Repository) DequeueRun(ctx context.Context, runID string) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	if _, err := r.pool.Exec(ctx, `DELETE FROM run_queue WHERE run_id = $1`, runID); err != nil {
+		return fmt.Errorf("delete run queue entry: %w", err)
+	}
+	return nil
+}
+
+// SetRunPriority updates a still-queued run's priority in place. Returns
+// ErrRunNotQueued if runID isn't currently waiting in the queue (either it
+// doesn't exist or the scheduler has already dispatched it).
+func (r *Repository) SetRunPriority(ctx context.Context, runID string, priority int) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	tag, err := r.pool.Exec(ctx, `UPDATE run_queue SET priority = $1 WHERE run_id = $2`, priority, runID)
+	if err != nil {
+		return fmt.Errorf("update run priority: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRunNotQueued
+	}
+	return nil
+}
+
+// RecordQueueWait stamps how long runID spent waiting in the admission
+// queue, once the scheduler dispatches it.
+func (r *Repository) RecordQueueWait(ctx context.Context, runID string, waitSeconds float64) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	_, err := r.pool.Exec(ctx, `UPDATE benchmark_runs SET queue_wait_seconds = $1 WHERE id = $2`, waitSeconds, runID)
+	if err != nil {
+		return fmt.Errorf("record queue wait: %w", err)
+	}
+	return nil
+}
+
+// RecordExecutionDuration stamps how long runID spent executing, once the
+// orchestrator's Execute returns.
+func (r *Repository) RecordExecutionDuration(ctx context.Context, runID string, seconds float64) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	_, err := r.pool.Exec(ctx, `UPDATE benchmark_runs SET execution_seconds = $1 WHERE id = $2`, seconds, runID)
+	if err != nil {
+		return fmt.Errorf("record execution duration: %w", err)
+	}
+	return nil
+}
+
+// ClaimRun implements LeasedRunQueue: it walks run_queue in dispatch order
+// under `FOR UPDATE SKIP LOCKED`, so two workers polling at once never
+// block on or double-claim the same row, and leases the first candidate
+// whose instance family and user are still under quota to workerID for
+// leaseDuration. A row whose lease has already expired counts as
+// unleased for this WHERE clause, which is what lets an abandoned claim
+// (its worker crashed or lost its heartbeat) become claimable again
+// without a separate reaper process. Returns nil, nil if every candidate
+// is either already leased or over quota.
+func (r *Repository) ClaimRun(ctx context.Context, workerID string, leaseDuration time.Duration, quota Quota) (*QueuedRun, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`SELECT run_id, instance_family, user_id, priority, enqueued_at
+		 FROM run_queue
+		 WHERE lease_expires_at IS NULL OR lease_expires_at < now()
+		 ORDER BY priority DESC, enqueued_at ASC
+		 FOR UPDATE SKIP LOCKED`)
+	if err != nil {
+		return nil, fmt.Errorf("query claimable runs: %w", err)
+	}
+	var candidates []QueuedRun
+	for rows.Next() {
+		var q QueuedRun
+		if err := rows.Scan(&q.RunID, &q.InstanceFamily, &q.UserID, &q.Priority, &q.EnqueuedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan claimable run: %w", err)
+		}
+		candidates = append(candidates, q)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	for _, q := range candidates {
+		if quota.MaxConcurrentPerFamily > 0 {
+			var n int
+			if err := tx.QueryRow(ctx,
+				`SELECT count(*) FROM run_queue WHERE instance_family = $1 AND lease_expires_at >= now()`,
+				q.InstanceFamily,
+			).Scan(&n); err != nil {
+				return nil, fmt.Errorf("count leased runs for family %s: %w", q.InstanceFamily, err)
+			}
+			if n >= quota.MaxConcurrentPerFamily {
+				continue
+			}
+		}
+		if quota.MaxConcurrentPerUser > 0 && q.UserID != "" {
+			var n int
+			if err := tx.QueryRow(ctx,
+				`SELECT count(*) FROM run_queue WHERE user_id = $1 AND lease_expires_at >= now()`,
+				q.UserID,
+			).Scan(&n); err != nil {
+				return nil, fmt.Errorf("count leased runs for user %s: %w", q.UserID, err)
+			}
+			if n >= quota.MaxConcurrentPerUser {
+				continue
+			}
+		}
+
+		if _, err := tx.Exec(ctx,
+			`UPDATE run_queue SET lease_owner = $1, lease_expires_at = $2 WHERE run_id = $3`,
+			workerID, time.Now().Add(leaseDuration), q.RunID,
+		); err != nil {
+			return nil, fmt.Errorf("lease run %s: %w", q.RunID, err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("commit transaction: %w", err)
+		}
+		claimed := q
+		return &claimed, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil, nil
+}
+
+// HeartbeatRun extends runID's lease by leaseDuration as long as workerID
+// still holds it, so a worker still actively executing a claimed run keeps
+// its slot past leaseDuration without ClaimRun's expiry check treating it
+// as abandoned. Returns ErrRunNotQueued if the lease was lost: either
+// another worker already reclaimed it after it expired, or the run was
+// dequeued out from under the caller.
+func (r *Repository) HeartbeatRun(ctx context.Context, runID, workerID string, leaseDuration time.Duration) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE run_queue SET lease_expires_at = $1 WHERE run_id = $2 AND lease_owner = $3`,
+		time.Now().Add(leaseDuration), runID, workerID,
+	)
+	if err != nil {
+		return fmt.Errorf("heartbeat run %s: %w", runID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRunNotQueued
+	}
+	return nil
+}
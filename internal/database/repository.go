@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -11,11 +12,16 @@ import (
 
 // Repository provides database operations for benchmark data.
 type Repository struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	broker *Broker
+	opts   RepositoryOptions
+	// deadline is non-nil only on a Repository returned by WithDeadline;
+	// a plain NewRepository never expires on its own.
+	deadline *deadlineGate
 }
 
 // NewRepository creates a new Repository with a connection pool.
-func NewRepository(ctx context.Context, connString string) (*Repository, error) {
+func NewRepository(ctx context.Context, connString string, opts ...RepositoryOption) (*Repository, error) {
 	pool, err := pgxpool.New(ctx, connString)
 	if err != nil {
 		return nil, fmt.Errorf("create connection pool: %w", err)
@@ -24,7 +30,11 @@ func NewRepository(ctx context.Context, connString string) (*Repository, error)
 		pool.Close()
 		return nil, fmt.Errorf("ping database: %w", err)
 	}
-	return &Repository{pool: pool}, nil
+	r := &Repository{pool: pool, broker: NewBroker(), opts: RepositoryOptions{}.withDefaults()}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
 }
 
 // Close closes the connection pool.
@@ -34,6 +44,9 @@ func (r *Repository) Close() {
 
 // GetModelByHfID returns a model by its Hugging Face ID and revision, or nil if not found.
 func (r *Repository) GetModelByHfID(ctx context.Context, hfID, hfRevision string) (*Model, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
 	var m Model
 	err := r.pool.QueryRow(ctx,
 		`SELECT id, hf_id, hf_revision, model_family, parameter_count, created_at
@@ -57,6 +70,10 @@ func (r *Repository) EnsureModel(ctx context.Context, hfID, hfRevision string) (
 	if m != nil {
 		return m, nil
 	}
+
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
 	var created Model
 	err = r.pool.QueryRow(ctx,
 		`INSERT INTO models (hf_id, hf_revision)
@@ -73,6 +90,9 @@ func (r *Repository) EnsureModel(ctx context.Context, hfID, hfRevision string) (
 
 // GetInstanceTypeByName returns an instance type by name, or nil if not found.
 func (r *Repository) GetInstanceTypeByName(ctx context.Context, name string) (*InstanceType, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
 	var it InstanceType
 	err := r.pool.QueryRow(ctx,
 		`SELECT id, name, family, accelerator_type, accelerator_name,
@@ -91,19 +111,55 @@ func (r *Repository) GetInstanceTypeByName(ctx context.Context, name string) (*I
 
 // CreateBenchmarkRun inserts a new benchmark run and returns its ID.
 func (r *Repository) CreateBenchmarkRun(ctx context.Context, run *BenchmarkRun) (string, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	var originalRequestJSON []byte
+	if run.OriginalRequest != nil {
+		var err error
+		originalRequestJSON, err = json.Marshal(run.OriginalRequest)
+		if err != nil {
+			return "", fmt.Errorf("marshal original request: %w", err)
+		}
+	}
+
+	var datasetSpecJSON []byte
+	if run.DatasetSpec != nil {
+		var err error
+		datasetSpecJSON, err = json.Marshal(run.DatasetSpec)
+		if err != nil {
+			return "", fmt.Errorf("marshal dataset spec: %w", err)
+		}
+	}
+
+	// DeadlineAt anchors on CreatedAt (computed here, not StartedAt) so a
+	// run stuck waiting for cluster capacity is bounded too — see
+	// BenchmarkRun.DeadlineAt's doc comment. Nil when the run carries no
+	// TimeoutSeconds.
+	now := time.Now()
+	var deadlineAt *time.Time
+	if run.TimeoutSeconds > 0 {
+		d := now.Add(time.Duration(run.TimeoutSeconds) * time.Second)
+		deadlineAt = &d
+	}
+
 	var id string
 	err := r.pool.QueryRow(ctx,
 		`INSERT INTO benchmark_runs
 		    (model_id, instance_type_id, framework, framework_version,
 		     tensor_parallel_degree, quantization, concurrency,
 		     input_sequence_length, output_sequence_length, dataset_name,
-		     run_type, status)
-		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+		     run_type, status, group_id, template_index, parent_run_id, original_request,
+		     dataset_spec_json, policy_id, trigger, callback_url, callback_token,
+		     timeout_seconds, deadline_at)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23)
 		 RETURNING id`,
 		run.ModelID, run.InstanceTypeID, run.Framework, run.FrameworkVersion,
 		run.TensorParallelDegree, run.Quantization, run.Concurrency,
 		run.InputSequenceLength, run.OutputSequenceLength, run.DatasetName,
-		run.RunType, run.Status,
+		run.RunType, run.Status, run.GroupID, run.TemplateIndex, run.ParentRunID, originalRequestJSON,
+		datasetSpecJSON, run.PolicyID, run.Trigger, run.CallbackURL, run.CallbackToken,
+		run.TimeoutSeconds, deadlineAt,
 	).Scan(&id)
 	if err != nil {
 		return "", fmt.Errorf("insert benchmark run: %w", err)
@@ -112,35 +168,411 @@ func (r *Repository) CreateBenchmarkRun(ctx context.Context, run *BenchmarkRun)
 }
 
 // UpdateRunStatus updates the status and optional timestamps of a benchmark run.
+// Transitioning into "completed" or "failed" also enqueues a run_callbacks
+// row, in the same transaction as the status update, if the run carries a
+// CallbackURL — see enqueueRunCallbackTx.
 func (r *Repository) UpdateRunStatus(ctx context.Context, runID, status string) error {
-	var query string
-	switch status {
-	case "running":
-		query = `UPDATE benchmark_runs SET status = $1, started_at = $2 WHERE id = $3`
-	case "completed", "failed":
-		query = `UPDATE benchmark_runs SET status = $1, completed_at = $2 WHERE id = $3`
-	default:
-		query = `UPDATE benchmark_runs SET status = $1 WHERE id = $2`
-		_, err := r.pool.Exec(ctx, query, status, runID)
-		return err
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	if status != "running" && status != "completed" && status != "failed" {
+		_, err := r.pool.Exec(ctx, `UPDATE benchmark_runs SET status = $1 WHERE id = $2`, status, runID)
+		if err != nil {
+			return err
+		}
+		r.broker.Publish(runID, status, nil)
+		return nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if status == "running" {
+		if _, err := tx.Exec(ctx, `UPDATE benchmark_runs SET status = $1, started_at = $2 WHERE id = $3`, status, time.Now(), runID); err != nil {
+			return fmt.Errorf("update run status: %w", err)
+		}
+	} else {
+		if _, err := tx.Exec(ctx, `UPDATE benchmark_runs SET status = $1, completed_at = $2 WHERE id = $3`, status, time.Now(), runID); err != nil {
+			return fmt.Errorf("update run status: %w", err)
+		}
+		if err := r.enqueueRunCallbackTx(ctx, tx, runID, status); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	r.broker.Publish(runID, status, nil)
+	return nil
+}
+
+// enqueueRunCallbackTx inserts a run_callbacks row for runID's terminal
+// status if and only if the run carries a CallbackURL, within tx so it
+// commits atomically with the status update that made it terminal. It
+// pulls callback_url/callback_token and the run's persisted metrics (if
+// any) out of the same transaction so the callback's summary reflects
+// exactly what's about to be committed.
+func (r *Repository) enqueueRunCallbackTx(ctx context.Context, tx pgx.Tx, runID, status string) error {
+	var callbackURL, callbackToken string
+	if err := tx.QueryRow(ctx, `SELECT callback_url, callback_token FROM benchmark_runs WHERE id = $1`, runID).Scan(&callbackURL, &callbackToken); err != nil {
+		return fmt.Errorf("query run callback config: %w", err)
+	}
+	if callbackURL == "" {
+		return nil
 	}
-	_, err := r.pool.Exec(ctx, query, status, time.Now(), runID)
+
+	var summary RunCallbackSummary
+	err := tx.QueryRow(ctx,
+		`SELECT throughput_aggregate_tps, ttft_p50_ms, e2e_latency_p50_ms FROM benchmark_metrics WHERE run_id = $1`, runID,
+	).Scan(&summary.ThroughputAggregateTPS, &summary.TTFTP50Ms, &summary.E2ELatencyP50Ms)
+	if err != nil && err != pgx.ErrNoRows {
+		return fmt.Errorf("query metrics for callback summary: %w", err)
+	}
+	summaryJSON, err := marshalSummary(summary)
 	if err != nil {
-		return fmt.Errorf("update run status: %w", err)
+		return fmt.Errorf("marshal callback summary: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO run_callbacks (run_id, url, token, status, summary_json, next_attempt_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		runID, callbackURL, callbackToken, status, summaryJSON, time.Now(),
+	); err != nil {
+		return fmt.Errorf("enqueue run callback: %w", err)
 	}
 	return nil
 }
 
+// UpdateRunFailureReason stamps runID's failure_reason column.
+func (r *Repository) UpdateRunFailureReason(ctx context.Context, runID, reason string) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	_, err := r.pool.Exec(ctx, `UPDATE benchmark_runs SET failure_reason = $1 WHERE id = $2`, reason, runID)
+	if err != nil {
+		return fmt.Errorf("update run failure reason: %w", err)
+	}
+	return nil
+}
+
+// CancelRun atomically moves runID to "failed" if and only if it is still
+// "queued", "pending" or "running" (dropping it from run_queue in the same
+// transaction), so a cancel racing a completion notification
+// from the orchestrator can never clobber a terminal status. Returns
+// ErrRunNotFound or ErrRunNotCancellable instead of updating anything if
+// the CAS doesn't apply.
+func (r *Repository) CancelRun(ctx context.Context, runID string) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	err = tx.QueryRow(ctx,
+		`UPDATE benchmark_runs SET status = 'failed', completed_at = $1
+		 WHERE id = $2 AND status IN ('pending', 'running', 'queued')
+		 RETURNING status`, time.Now(), runID,
+	).Scan(&status)
+	if err == nil {
+		// A canceled run may still be sitting in the admission queue;
+		// drop it so the scheduler doesn't dispatch it after all.
+		if _, delErr := tx.Exec(ctx, `DELETE FROM run_queue WHERE run_id = $1`, runID); delErr != nil {
+			return fmt.Errorf("dequeue canceled run: %w", delErr)
+		}
+		if err := r.enqueueRunCallbackTx(ctx, tx, runID, "failed"); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("commit transaction: %w", err)
+		}
+		r.broker.Publish(runID, "failed", nil)
+		return nil
+	}
+	if err != pgx.ErrNoRows {
+		return fmt.Errorf("cancel run: %w", err)
+	}
+
+	var existing string
+	err = tx.QueryRow(ctx, `SELECT status FROM benchmark_runs WHERE id = $1`, runID).Scan(&existing)
+	if err == pgx.ErrNoRows {
+		return ErrRunNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("query run status: %w", err)
+	}
+	return ErrRunNotCancellable
+}
+
+// UpdateRunPluginDigests records the resolved sha256 digest of every
+// FrameworkPlugin artifact used by a run, so results stay reproducible and
+// diff-able across kernel versions.
+func (r *Repository) UpdateRunPluginDigests(ctx context.Context, runID string, digests map[string]string) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	digestsJSON, err := json.Marshal(digests)
+	if err != nil {
+		return fmt.Errorf("marshal plugin digests: %w", err)
+	}
+	_, err = r.pool.Exec(ctx,
+		`UPDATE benchmark_runs SET plugin_digests_json = $1 WHERE id = $2`,
+		digestsJSON, runID,
+	)
+	if err != nil {
+		return fmt.Errorf("update plugin digests: %w", err)
+	}
+	return nil
+}
+
+// UpdateRunPhase stamps runID's current lifecycle phase and, if resources
+// is non-nil, merges it into the run's persisted K8sResourceRefs so fields
+// set by an earlier phase (e.g. DeploymentName/DeploymentUID from
+// "deploying") survive a later call that only knows about new ones (e.g.
+// JobName/JobUID from "loadgen_running").
+func (r *Repository) UpdateRunPhase(ctx context.Context, runID, phase string, resources *K8sResourceRefs) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	if resources == nil {
+		_, err := r.pool.Exec(ctx, `UPDATE benchmark_runs SET phase = $1 WHERE id = $2`, phase, runID)
+		if err != nil {
+			return fmt.Errorf("update run phase: %w", err)
+		}
+		return nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var existingJSON []byte
+	if err := tx.QueryRow(ctx, `SELECT k8s_resources_json FROM benchmark_runs WHERE id = $1`, runID).Scan(&existingJSON); err != nil {
+		return fmt.Errorf("query existing k8s resources: %w", err)
+	}
+	merged := resources
+	if len(existingJSON) > 0 {
+		var existing K8sResourceRefs
+		if err := json.Unmarshal(existingJSON, &existing); err != nil {
+			return fmt.Errorf("unmarshal existing k8s resources: %w", err)
+		}
+		merged = mergeK8sResourceRefs(&existing, resources)
+	}
+	mergedJSON, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("marshal k8s resources: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE benchmark_runs SET phase = $1, k8s_resources_json = $2 WHERE id = $3`,
+		phase, mergedJSON, runID,
+	); err != nil {
+		return fmt.Errorf("update run phase: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// mergeK8sResourceRefs overlays update onto base, keeping base's non-empty
+// fields where update leaves the corresponding field empty, so a later
+// phase's partial K8sResourceRefs doesn't erase an earlier phase's.
+func mergeK8sResourceRefs(base, update *K8sResourceRefs) *K8sResourceRefs {
+	merged := *base
+	if update.Namespace != "" {
+		merged.Namespace = update.Namespace
+	}
+	if update.DeploymentName != "" {
+		merged.DeploymentName = update.DeploymentName
+	}
+	if update.DeploymentUID != "" {
+		merged.DeploymentUID = update.DeploymentUID
+	}
+	if update.ServiceName != "" {
+		merged.ServiceName = update.ServiceName
+	}
+	if update.ServiceUID != "" {
+		merged.ServiceUID = update.ServiceUID
+	}
+	if update.JobName != "" {
+		merged.JobName = update.JobName
+	}
+	if update.JobUID != "" {
+		merged.JobUID = update.JobUID
+	}
+	return &merged
+}
+
+// SetTeardownPending marks whether runID's Kubernetes resources still need
+// tearing down, so Resume can tell an orphaned Deployment/Service/Job apart
+// from a run whose resources were already cleaned up before the process
+// died.
+func (r *Repository) SetTeardownPending(ctx context.Context, runID string, pending bool) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	_, err := r.pool.Exec(ctx, `UPDATE benchmark_runs SET teardown_pending = $1 WHERE id = $2`, pending, runID)
+	if err != nil {
+		return fmt.Errorf("update teardown pending: %w", err)
+	}
+	return nil
+}
+
+// ListNonTerminalRuns returns every run whose status is "running", for
+// Resume to rebind to on startup after a crash or restart.
+func (r *Repository) ListNonTerminalRuns(ctx context.Context) ([]BenchmarkRun, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx, `SELECT id FROM benchmark_runs WHERE status = 'running'`)
+	if err != nil {
+		return nil, fmt.Errorf("query non-terminal runs: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan run id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	runs := make([]BenchmarkRun, 0, len(ids))
+	for _, id := range ids {
+		run, err := r.GetBenchmarkRun(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get run %s: %w", id, err)
+		}
+		if run != nil {
+			runs = append(runs, *run)
+		}
+	}
+	return runs, nil
+}
+
+// ListExpiredRuns returns every queued, pending, or running run whose
+// DeadlineAt is non-nil and strictly before now, for the reaper's tick.
+func (r *Repository) ListExpiredRuns(ctx context.Context, now time.Time) ([]BenchmarkRun, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT id FROM benchmark_runs
+		 WHERE status IN ('queued', 'pending', 'running') AND deadline_at IS NOT NULL AND deadline_at < $1`, now)
+	if err != nil {
+		return nil, fmt.Errorf("query expired runs: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan run id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	runs := make([]BenchmarkRun, 0, len(ids))
+	for _, id := range ids {
+		run, err := r.GetBenchmarkRun(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get run %s: %w", id, err)
+		}
+		if run != nil {
+			runs = append(runs, *run)
+		}
+	}
+	return runs, nil
+}
+
+// ExtendDeadline pushes runID's DeadlineAt out to newDeadline, conditional
+// on the run still being queued, pending, or running in one UPDATE, so it
+// can never resurrect a run that's already terminal.
+func (r *Repository) ExtendDeadline(ctx context.Context, runID string, newDeadline time.Time) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	var id string
+	err := r.pool.QueryRow(ctx,
+		`UPDATE benchmark_runs SET deadline_at = $1
+		 WHERE id = $2 AND status IN ('queued', 'pending', 'running')
+		 RETURNING id`, newDeadline, runID,
+	).Scan(&id)
+	if err == nil {
+		return nil
+	}
+	if err != pgx.ErrNoRows {
+		return fmt.Errorf("extend deadline: %w", err)
+	}
+
+	var existing string
+	err = r.pool.QueryRow(ctx, `SELECT status FROM benchmark_runs WHERE id = $1`, runID).Scan(&existing)
+	if err == pgx.ErrNoRows {
+		return ErrRunNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("query run status: %w", err)
+	}
+	return ErrRunNotExtendable
+}
+
 // PersistMetrics inserts benchmark metrics and marks the run as completed
 // within a single transaction. It verifies the write by reading back the
 // inserted metrics row before committing.
-func (r *Repository) PersistMetrics(ctx context.Context, runID string, m *BenchmarkMetrics) error {
+func (r *Repository) PersistMetrics(ctx context.Context, runID string, m *BenchmarkMetrics, samples []AcceleratorSample) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
+	var perRankJSON []byte
+	if len(m.PerRank) > 0 {
+		perRankJSON, err = json.Marshal(m.PerRank)
+		if err != nil {
+			return fmt.Errorf("marshal per-rank metrics: %w", err)
+		}
+	}
+
+	var qualityMetricsJSON []byte
+	if m.QualityMetrics != nil {
+		qualityMetricsJSON, err = json.Marshal(m.QualityMetrics)
+		if err != nil {
+			return fmt.Errorf("marshal quality metrics: %w", err)
+		}
+	}
+
+	var autoscaleStagesJSON []byte
+	if len(m.AutoscaleStages) > 0 {
+		autoscaleStagesJSON, err = json.Marshal(m.AutoscaleStages)
+		if err != nil {
+			return fmt.Errorf("marshal autoscale stages: %w", err)
+		}
+	}
+
 	// Insert metrics.
 	var metricsID string
 	err = tx.QueryRow(ctx,
@@ -150,17 +582,39 @@ func (r *Repository) PersistMetrics(ctx context.Context, runID string, m *Benchm
 		     e2e_latency_p50_ms, e2e_latency_p90_ms, e2e_latency_p95_ms, e2e_latency_p99_ms,
 		     itl_p50_ms, itl_p90_ms, itl_p95_ms, itl_p99_ms,
 		     throughput_per_request_tps, throughput_aggregate_tps, requests_per_second,
-		     accelerator_utilization_pct, accelerator_memory_peak_gib,
-		     successful_requests, failed_requests, total_duration_seconds)
-		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21)
+		     accelerator_utilization_pct, accelerator_utilization_avg_pct, accelerator_memory_peak_gib,
+		     accelerator_kind,
+		     waiting_requests_max,
+		     successful_requests, failed_requests, total_duration_seconds,
+		     server_ttft_p50_ms, server_ttft_p90_ms, server_ttft_p95_ms, server_ttft_p99_ms,
+		     server_tpot_p50_ms, server_tpot_p90_ms, server_tpot_p95_ms, server_tpot_p99_ms,
+		     server_e2e_latency_p50_ms, server_e2e_latency_p90_ms, server_e2e_latency_p95_ms, server_e2e_latency_p99_ms,
+		     sm_active_peak_pct, tensor_core_active_pct, power_avg_w, temp_peak_c, hbm_used_peak_gib,
+		     sm_util_p50_pct, sm_util_p95_pct, sm_util_max_pct, energy_consumed_joules, thermal_headroom_c,
+		     cost_per_million_tokens_usd, tokens_per_joule, pricing_source, pricing_captured_at,
+		     max_concurrency_at_slo,
+		     replicas_min, replicas_max, scale_up_latency_seconds,
+		     per_rank_json, quality_metrics_json, autoscale_stages_json)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37,$38,$39,$40,$41,$42,$43,$44,$45,$46,$47,$48,$49,$50,$51,$52,$53,$54,$55,$56)
 		 RETURNING id`,
 		runID,
 		m.TTFTP50Ms, m.TTFTP90Ms, m.TTFTP95Ms, m.TTFTP99Ms,
 		m.E2ELatencyP50Ms, m.E2ELatencyP90Ms, m.E2ELatencyP95Ms, m.E2ELatencyP99Ms,
 		m.ITLP50Ms, m.ITLP90Ms, m.ITLP95Ms, m.ITLP99Ms,
 		m.ThroughputPerRequestTPS, m.ThroughputAggregateTPS, m.RequestsPerSecond,
-		m.AcceleratorUtilizationPct, m.AcceleratorMemoryPeakGiB,
+		m.AcceleratorUtilizationPct, m.AcceleratorUtilizationAvgPct, m.AcceleratorMemoryPeakGiB,
+		m.AcceleratorKind,
+		m.WaitingRequestsMax,
 		m.SuccessfulRequests, m.FailedRequests, m.TotalDurationSeconds,
+		m.ServerTTFTP50Ms, m.ServerTTFTP90Ms, m.ServerTTFTP95Ms, m.ServerTTFTP99Ms,
+		m.ServerTPOTP50Ms, m.ServerTPOTP90Ms, m.ServerTPOTP95Ms, m.ServerTPOTP99Ms,
+		m.ServerE2ELatencyP50Ms, m.ServerE2ELatencyP90Ms, m.ServerE2ELatencyP95Ms, m.ServerE2ELatencyP99Ms,
+		m.SMActivePeakPct, m.TensorCoreActivePct, m.PowerAvgW, m.TempPeakC, m.HBMUsedPeakGiB,
+		m.SMUtilP50Pct, m.SMUtilP95Pct, m.SMUtilMaxPct, m.EnergyConsumedJoules, m.ThermalHeadroomC,
+		m.CostPerMillionTokensUSD, m.TokensPerJoule, m.PricingSource, m.PricingCapturedAt,
+		m.MaxConcurrencyAtSLO,
+		m.ReplicasMin, m.ReplicasMax, m.ScaleUpLatencySeconds,
+		perRankJSON, qualityMetricsJSON, autoscaleStagesJSON,
 	).Scan(&metricsID)
 	if err != nil {
 		return fmt.Errorf("insert metrics: %w", err)
@@ -178,6 +632,10 @@ func (r *Repository) PersistMetrics(ctx context.Context, runID string, m *Benchm
 		return fmt.Errorf("metrics verification failed: expected run_id %s, got %s", runID, verifyRunID)
 	}
 
+	if err := insertAcceleratorSamples(ctx, tx, runID, samples); err != nil {
+		return err
+	}
+
 	// Mark run as completed.
 	_, err = tx.Exec(ctx,
 		`UPDATE benchmark_runs SET status = 'completed', completed_at = $1 WHERE id = $2`,
@@ -187,58 +645,342 @@ func (r *Repository) PersistMetrics(ctx context.Context, runID string, m *Benchm
 		return fmt.Errorf("update run to completed: %w", err)
 	}
 
+	if err := r.enqueueRunCallbackTx(ctx, tx, runID, "completed"); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	r.broker.Publish(runID, "completed", m)
+	return nil
+}
+
+// UpsertMetrics writes a progressive BenchmarkMetrics snapshot for runID
+// while it's still running, replacing any previous snapshot with it. It
+// clears and re-inserts rather than INSERT ... ON CONFLICT since
+// benchmark_metrics has no unique constraint on run_id — PersistMetrics
+// only ever inserts once per run, so nothing downstream has needed one
+// until now.
+func (r *Repository) UpsertMetrics(ctx context.Context, runID string, m *BenchmarkMetrics) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM benchmark_metrics WHERE run_id = $1`, runID); err != nil {
+		return fmt.Errorf("clear previous metrics snapshot: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO benchmark_metrics
+		    (run_id,
+		     ttft_p50_ms, ttft_p90_ms, ttft_p95_ms, ttft_p99_ms,
+		     e2e_latency_p50_ms, e2e_latency_p90_ms, e2e_latency_p95_ms, e2e_latency_p99_ms,
+		     itl_p50_ms, itl_p90_ms, itl_p95_ms, itl_p99_ms,
+		     throughput_per_request_tps, throughput_aggregate_tps, requests_per_second,
+		     successful_requests, failed_requests, total_duration_seconds)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19)`,
+		runID,
+		m.TTFTP50Ms, m.TTFTP90Ms, m.TTFTP95Ms, m.TTFTP99Ms,
+		m.E2ELatencyP50Ms, m.E2ELatencyP90Ms, m.E2ELatencyP95Ms, m.E2ELatencyP99Ms,
+		m.ITLP50Ms, m.ITLP90Ms, m.ITLP95Ms, m.ITLP99Ms,
+		m.ThroughputPerRequestTPS, m.ThroughputAggregateTPS, m.RequestsPerSecond,
+		m.SuccessfulRequests, m.FailedRequests, m.TotalDurationSeconds,
+	)
+	if err != nil {
+		return fmt.Errorf("insert progressive metrics: %w", err)
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("commit transaction: %w", err)
 	}
+	r.broker.Publish(runID, "running", m)
+	return nil
+}
+
+// Watch subscribes to lifecycle and metrics events for runID.
+func (r *Repository) Watch(ctx context.Context, runID string, sinceRev uint64) (<-chan RunEvent, error) {
+	return r.broker.Watch(ctx, runID, sinceRev)
+}
+
+// PublishRunLog fans an orchestrator log line for runID out to anyone
+// watching it. Log lines are not persisted to Postgres; they exist only
+// as transient events for live streaming, replayed from the broker's
+// backlog for a reconnecting watcher but gone once that backlog rolls
+// over or the process restarts.
+func (r *Repository) PublishRunLog(ctx context.Context, runID, line string) error {
+	r.broker.PublishLog(runID, line)
 	return nil
 }
 
 // GetBenchmarkRun returns a benchmark run by ID.
 func (r *Repository) GetBenchmarkRun(ctx context.Context, runID string) (*BenchmarkRun, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
 	var run BenchmarkRun
+	var pluginDigestsJSON []byte
+	var originalRequestJSON []byte
+	var datasetSpecJSON []byte
+	var k8sResourcesJSON []byte
 	err := r.pool.QueryRow(ctx,
 		`SELECT id, model_id, instance_type_id, framework, framework_version,
 		        tensor_parallel_degree, quantization, concurrency,
 		        input_sequence_length, output_sequence_length, dataset_name,
-		        run_type, status, superseded, started_at, completed_at, created_at
+		        run_type, status, superseded, health_failure_reason, started_at, completed_at, created_at,
+		        plugin_digests_json, original_request,
+		        queued_at, queue_wait_seconds, execution_seconds,
+		        dataset_spec_json, phase, teardown_pending, k8s_resources_json,
+		        callback_url, callback_token, timeout_seconds, deadline_at
 		 FROM benchmark_runs WHERE id = $1`, runID,
 	).Scan(&run.ID, &run.ModelID, &run.InstanceTypeID, &run.Framework, &run.FrameworkVersion,
 		&run.TensorParallelDegree, &run.Quantization, &run.Concurrency,
 		&run.InputSequenceLength, &run.OutputSequenceLength, &run.DatasetName,
-		&run.RunType, &run.Status, &run.Superseded, &run.StartedAt, &run.CompletedAt, &run.CreatedAt)
+		&run.RunType, &run.Status, &run.Superseded, &run.HealthFailureReason, &run.StartedAt, &run.CompletedAt, &run.CreatedAt,
+		&pluginDigestsJSON, &originalRequestJSON,
+		&run.QueuedAt, &run.QueueWaitSeconds, &run.ExecutionSeconds,
+		&datasetSpecJSON, &run.Phase, &run.TeardownPending, &k8sResourcesJSON,
+		&run.CallbackURL, &run.CallbackToken, &run.TimeoutSeconds, &run.DeadlineAt)
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("query benchmark run: %w", err)
 	}
+	if len(pluginDigestsJSON) > 0 {
+		if err := json.Unmarshal(pluginDigestsJSON, &run.PluginDigests); err != nil {
+			return nil, fmt.Errorf("unmarshal plugin digests: %w", err)
+		}
+	}
+	if len(originalRequestJSON) > 0 {
+		if err := json.Unmarshal(originalRequestJSON, &run.OriginalRequest); err != nil {
+			return nil, fmt.Errorf("unmarshal original request: %w", err)
+		}
+	}
+	if len(datasetSpecJSON) > 0 {
+		if err := json.Unmarshal(datasetSpecJSON, &run.DatasetSpec); err != nil {
+			return nil, fmt.Errorf("unmarshal dataset spec: %w", err)
+		}
+	}
+	if len(k8sResourcesJSON) > 0 {
+		if err := json.Unmarshal(k8sResourcesJSON, &run.K8sResources); err != nil {
+			return nil, fmt.Errorf("unmarshal k8s resources: %w", err)
+		}
+	}
 	return &run, nil
 }
 
 // GetMetricsByRunID returns benchmark metrics for a given run.
 func (r *Repository) GetMetricsByRunID(ctx context.Context, runID string) (*BenchmarkMetrics, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
 	var m BenchmarkMetrics
+	var perRankJSON []byte
+	var qualityMetricsJSON []byte
+	var autoscaleStagesJSON []byte
 	err := r.pool.QueryRow(ctx,
 		`SELECT id, run_id,
 		        ttft_p50_ms, ttft_p90_ms, ttft_p95_ms, ttft_p99_ms,
 		        e2e_latency_p50_ms, e2e_latency_p90_ms, e2e_latency_p95_ms, e2e_latency_p99_ms,
 		        itl_p50_ms, itl_p90_ms, itl_p95_ms, itl_p99_ms,
 		        throughput_per_request_tps, throughput_aggregate_tps, requests_per_second,
-		        accelerator_utilization_pct, accelerator_memory_peak_gib,
-		        successful_requests, failed_requests, total_duration_seconds, created_at
+		        accelerator_utilization_pct, accelerator_utilization_avg_pct, accelerator_memory_peak_gib,
+		        accelerator_kind,
+		        waiting_requests_max,
+		        successful_requests, failed_requests, total_duration_seconds,
+		        server_ttft_p50_ms, server_ttft_p90_ms, server_ttft_p95_ms, server_ttft_p99_ms,
+		        server_tpot_p50_ms, server_tpot_p90_ms, server_tpot_p95_ms, server_tpot_p99_ms,
+		        server_e2e_latency_p50_ms, server_e2e_latency_p90_ms, server_e2e_latency_p95_ms, server_e2e_latency_p99_ms,
+		        sm_active_peak_pct, tensor_core_active_pct, power_avg_w, temp_peak_c, hbm_used_peak_gib,
+		        max_concurrency_at_slo,
+		        replicas_min, replicas_max, scale_up_latency_seconds,
+		        per_rank_json, quality_metrics_json, autoscale_stages_json,
+		        created_at
 		 FROM benchmark_metrics WHERE run_id = $1`, runID,
 	).Scan(&m.ID, &m.RunID,
 		&m.TTFTP50Ms, &m.TTFTP90Ms, &m.TTFTP95Ms, &m.TTFTP99Ms,
 		&m.E2ELatencyP50Ms, &m.E2ELatencyP90Ms, &m.E2ELatencyP95Ms, &m.E2ELatencyP99Ms,
 		&m.ITLP50Ms, &m.ITLP90Ms, &m.ITLP95Ms, &m.ITLP99Ms,
 		&m.ThroughputPerRequestTPS, &m.ThroughputAggregateTPS, &m.RequestsPerSecond,
-		&m.AcceleratorUtilizationPct, &m.AcceleratorMemoryPeakGiB,
-		&m.SuccessfulRequests, &m.FailedRequests, &m.TotalDurationSeconds, &m.CreatedAt)
+		&m.AcceleratorUtilizationPct, &m.AcceleratorUtilizationAvgPct, &m.AcceleratorMemoryPeakGiB,
+		&m.AcceleratorKind,
+		&m.WaitingRequestsMax,
+		&m.SuccessfulRequests, &m.FailedRequests, &m.TotalDurationSeconds,
+		&m.ServerTTFTP50Ms, &m.ServerTTFTP90Ms, &m.ServerTTFTP95Ms, &m.ServerTTFTP99Ms,
+		&m.ServerTPOTP50Ms, &m.ServerTPOTP90Ms, &m.ServerTPOTP95Ms, &m.ServerTPOTP99Ms,
+		&m.ServerE2ELatencyP50Ms, &m.ServerE2ELatencyP90Ms, &m.ServerE2ELatencyP95Ms, &m.ServerE2ELatencyP99Ms,
+		&m.SMActivePeakPct, &m.TensorCoreActivePct, &m.PowerAvgW, &m.TempPeakC, &m.HBMUsedPeakGiB,
+		&m.MaxConcurrencyAtSLO,
+		&m.ReplicasMin, &m.ReplicasMax, &m.ScaleUpLatencySeconds,
+		&perRankJSON, &qualityMetricsJSON, &autoscaleStagesJSON,
+		&m.CreatedAt)
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("query metrics: %w", err)
 	}
+	if len(perRankJSON) > 0 {
+		if err := json.Unmarshal(perRankJSON, &m.PerRank); err != nil {
+			return nil, fmt.Errorf("unmarshal per-rank metrics: %w", err)
+		}
+	}
+	if len(qualityMetricsJSON) > 0 {
+		if err := json.Unmarshal(qualityMetricsJSON, &m.QualityMetrics); err != nil {
+			return nil, fmt.Errorf("unmarshal quality metrics: %w", err)
+		}
+	}
+	if len(autoscaleStagesJSON) > 0 {
+		if err := json.Unmarshal(autoscaleStagesJSON, &m.AutoscaleStages); err != nil {
+			return nil, fmt.Errorf("unmarshal autoscale stages: %w", err)
+		}
+	}
 	return &m, nil
 }
+
+func scanRunCallback(row interface{ Scan(dest ...any) error }) (RunCallback, error) {
+	var cb RunCallback
+	var summaryJSON []byte
+	if err := row.Scan(&cb.ID, &cb.RunID, &cb.URL, &cb.Token, &cb.Status, &summaryJSON,
+		&cb.Attempts, &cb.LastError, &cb.NextAttemptAt, &cb.DeliveredAt, &cb.CreatedAt); err != nil {
+		return RunCallback{}, fmt.Errorf("scan run callback row: %w", err)
+	}
+	summary, err := unmarshalSummary(summaryJSON)
+	if err != nil {
+		return RunCallback{}, fmt.Errorf("unmarshal callback summary: %w", err)
+	}
+	cb.Summary = summary
+	return cb, nil
+}
+
+// runCallbackClaimLease is how far ClaimDueRunCallbacks pushes a claimed
+// row's next_attempt_at out before releasing its lock, so a second Worker
+// polling the instant after this one commits doesn't immediately re-claim
+// the same row while delivery is still in flight. RecordRunCallbackResult
+// always overwrites this with the real retry schedule once delivery
+// actually finishes, succeeding or not.
+const runCallbackClaimLease = 2 * time.Minute
+
+// ClaimDueRunCallbacks implements RunCallbackClaimer: it locks every
+// pending run_callbacks row whose next_attempt_at is at or before now via
+// `FOR UPDATE SKIP LOCKED`, pushes each claimed row's next_attempt_at out
+// by runCallbackClaimLease before releasing the lock, and returns the
+// pre-claim rows for the caller to actually deliver.
+func (r *Repository) ClaimDueRunCallbacks(ctx context.Context, now time.Time, limit int) ([]RunCallback, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`SELECT id, run_id, url, token, status, summary_json,
+		        attempts, last_error, next_attempt_at, delivered_at, created_at
+		 FROM run_callbacks
+		 WHERE delivered_at IS NULL AND next_attempt_at <= $1 AND attempts < $2
+		 ORDER BY created_at ASC
+		 LIMIT $3
+		 FOR UPDATE SKIP LOCKED`, now, MaxCallbackAttempts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query due run callbacks: %w", err)
+	}
+	var due []RunCallback
+	for rows.Next() {
+		cb, err := scanRunCallback(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		due = append(due, cb)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	for _, cb := range due {
+		if _, err := tx.Exec(ctx,
+			`UPDATE run_callbacks SET next_attempt_at = $1 WHERE id = $2`,
+			now.Add(runCallbackClaimLease), cb.ID,
+		); err != nil {
+			return nil, fmt.Errorf("lease run callback %s: %w", cb.ID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return due, nil
+}
+
+// ListDueRunCallbacks returns up to limit pending run_callbacks rows whose
+// next_attempt_at is at or before now, without claiming anything — callers
+// without exclusive access to this database should prefer
+// ClaimDueRunCallbacks instead.
+func (r *Repository) ListDueRunCallbacks(ctx context.Context, now time.Time, limit int) ([]RunCallback, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, run_id, url, token, status, summary_json,
+		        attempts, last_error, next_attempt_at, delivered_at, created_at
+		 FROM run_callbacks
+		 WHERE delivered_at IS NULL AND next_attempt_at <= $1 AND attempts < $2
+		 ORDER BY created_at ASC
+		 LIMIT $3`, now, MaxCallbackAttempts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query due run callbacks: %w", err)
+	}
+	defer rows.Close()
+
+	var due []RunCallback
+	for rows.Next() {
+		cb, err := scanRunCallback(rows)
+		if err != nil {
+			return nil, err
+		}
+		due = append(due, cb)
+	}
+	return due, rows.Err()
+}
+
+// RecordRunCallbackResult records the outcome of a delivery attempt for id:
+// deliveryErr nil marks it delivered; otherwise it bumps attempts, stashes
+// deliveryErr's message, and schedules the next retry at nextAttemptAt.
+func (r *Repository) RecordRunCallbackResult(ctx context.Context, id string, deliveryErr error, nextAttemptAt *time.Time) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	if deliveryErr == nil {
+		_, err := r.pool.Exec(ctx,
+			`UPDATE run_callbacks SET delivered_at = $1, last_error = '' WHERE id = $2`,
+			time.Now(), id,
+		)
+		if err != nil {
+			return fmt.Errorf("record run callback delivered: %w", err)
+		}
+		return nil
+	}
+
+	_, err := r.pool.Exec(ctx,
+		`UPDATE run_callbacks SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2 WHERE id = $3`,
+		deliveryErr.Error(), nextAttemptAt, id,
+	)
+	if err != nil {
+		return fmt.Errorf("record run callback failure: %w", err)
+	}
+	return nil
+}
+
+// Compile-time check that *Repository implements RunCallbackClaimer.
+var _ RunCallbackClaimer = (*Repository)(nil)
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// Quota bounds how many of a queue's currently-leased (i.e. executing) runs
+// may share an instance family or a submitting user at once — the two
+// limits ClaimRun enforces so a burst against one scarce family or one
+// noisy user can't starve everyone else waiting behind them. Zero means
+// unlimited.
+type Quota struct {
+	MaxConcurrentPerFamily int
+	MaxConcurrentPerUser   int
+}
+
+// LeasedRunQueue is implemented by a run queue backend that can safely be
+// polled by more than one orchestrator worker process at once: ClaimRun
+// atomically claims the next quota-admissible row via
+// `SELECT ... FOR UPDATE SKIP LOCKED` so two workers racing the same poll
+// tick never claim the same run, and HeartbeatRun extends a live claim's
+// lease so a worker still executing it doesn't lose the row out from under
+// itself. A lease that isn't renewed in time (its worker crashed or was
+// partitioned off) becomes claimable again through ClaimRun's own WHERE
+// clause — there's no separate reaper process to keep running.
+//
+// Only *Repository (Postgres) implements this today; orchestrator.Scheduler
+// type-asserts for it and falls back to its own in-process admission map
+// against any database.Repo that doesn't, which is safe as long as exactly
+// one orchestrator process is dispatching against that backend.
+type LeasedRunQueue interface {
+	// ClaimRun claims and leases, for leaseDuration under workerID's
+	// ownership, the highest-priority still-queued run whose instance
+	// family and user are both under quota. Returns nil, nil if nothing
+	// queued is currently claimable (everything is either already leased
+	// or over quota).
+	ClaimRun(ctx context.Context, workerID string, leaseDuration time.Duration, quota Quota) (*QueuedRun, error)
+
+	// HeartbeatRun extends runID's lease by leaseDuration, as long as
+	// workerID still holds it. Returns ErrRunNotQueued if the lease was
+	// lost — reclaimed by another worker after expiring, or the run was
+	// dequeued.
+	HeartbeatRun(ctx context.Context, runID, workerID string, leaseDuration time.Duration) error
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
@@ -11,8 +12,54 @@ import (
 type RunFilter struct {
 	Status   string // "pending", "running", "completed", "failed", or ""
 	ModelID  string // ILIKE filter on model hf_id
+	PolicyID string // exact match on BenchmarkRun.PolicyID, or "" for no filter
+	Trigger  string // "manual", "scheduled", or "event"; "" for no filter
 	Limit    int
 	Offset   int
+
+	// Statuses is an OR'd alternative to Status, for "running OR pending"
+	// style queue triage — e.g. a dashboard asking for everything still
+	// in flight in one round trip. Applied in addition to Status when
+	// both are set, though callers normally use only one or the other.
+	Statuses []string
+
+	// Framework is an exact match on BenchmarkRun.Framework (e.g. "vllm"),
+	// InstanceFamily and AcceleratorType match the run's instance type the
+	// same way CatalogFilter's fields of the same name do.
+	Framework       string
+	InstanceFamily  string
+	AcceleratorType string
+
+	// CreatedAfter/CreatedBefore and CompletedAfter/CompletedBefore bound
+	// BenchmarkRun.CreatedAt/CompletedAt; the zero time.Time disables the
+	// corresponding bound. CompletedAfter/CompletedBefore never match a
+	// run with no CompletedAt (i.e. one that hasn't reached a terminal
+	// status yet).
+	CreatedAfter    time.Time
+	CreatedBefore   time.Time
+	CompletedAfter  time.Time
+	CompletedBefore time.Time
+
+	// ShardID/ShardCount restrict the result to the subset of runs whose
+	// ID hashes into shard ShardID of ShardCount; see CatalogFilter's
+	// fields of the same name. ShardCount <= 1 disables sharding. When
+	// ShardCount > 1, Limit/Offset are ignored here — apply them after
+	// merging every shard's results.
+	ShardID    int
+	ShardCount int
+
+	// AfterCreatedAt/AfterID together select keyset pagination instead of
+	// Offset, mirroring CatalogFilter.AfterRunID/AfterSortValue: when
+	// AfterID is set, ListRuns only returns runs that sort strictly after
+	// the entry identified by (AfterCreatedAt, AfterID) under the fixed
+	// created_at DESC ordering every backend lists runs in — the last row
+	// the caller saw on the previous page. Offset is ignored once AfterID
+	// is set. Unlike catalog's keyset fields, every Repo backend
+	// (Repository, MockRepo, BoltRepo, EtcdRepo) honors these, since a
+	// busy run queue is exactly the case offset pagination drops or
+	// duplicates rows on.
+	AfterCreatedAt time.Time
+	AfterID        string
 }
 
 // RunListItem is a denormalized row for the jobs list.
@@ -29,8 +76,12 @@ type RunListItem struct {
 }
 
 // ListRuns returns benchmark runs matching the given filter, joined with
-// models and instance_types for display names.
-func (r *Repository) ListRuns(ctx context.Context, f RunFilter) ([]RunListItem, error) {
+// models and instance_types for display names, along with the total
+// number of runs matching f (ignoring f.Limit/f.Offset).
+func (r *Repository) ListRuns(ctx context.Context, f RunFilter) ([]RunListItem, int, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
 	var (
 		conditions []string
 		args       []any
@@ -47,65 +98,313 @@ func (r *Repository) ListRuns(ctx context.Context, f RunFilter) ([]RunListItem,
 		conditions = append(conditions, fmt.Sprintf("m.hf_id ILIKE $%d", argIdx))
 		args = append(args, "%"+f.ModelID+"%")
 	}
+	if f.PolicyID != "" {
+		argIdx++
+		conditions = append(conditions, fmt.Sprintf("br.policy_id = $%d", argIdx))
+		args = append(args, f.PolicyID)
+	}
+	if f.Trigger != "" {
+		argIdx++
+		conditions = append(conditions, fmt.Sprintf("br.trigger = $%d", argIdx))
+		args = append(args, f.Trigger)
+	}
+	if len(f.Statuses) > 0 {
+		argIdx++
+		conditions = append(conditions, fmt.Sprintf("br.status = ANY($%d)", argIdx))
+		args = append(args, f.Statuses)
+	}
+	if f.Framework != "" {
+		argIdx++
+		conditions = append(conditions, fmt.Sprintf("br.framework = $%d", argIdx))
+		args = append(args, f.Framework)
+	}
+	if f.InstanceFamily != "" {
+		argIdx++
+		conditions = append(conditions, fmt.Sprintf("it.family = $%d", argIdx))
+		args = append(args, f.InstanceFamily)
+	}
+	if f.AcceleratorType != "" {
+		argIdx++
+		conditions = append(conditions, fmt.Sprintf("it.accelerator_type = $%d", argIdx))
+		args = append(args, f.AcceleratorType)
+	}
+	if !f.CreatedAfter.IsZero() {
+		argIdx++
+		conditions = append(conditions, fmt.Sprintf("br.created_at >= $%d", argIdx))
+		args = append(args, f.CreatedAfter)
+	}
+	if !f.CreatedBefore.IsZero() {
+		argIdx++
+		conditions = append(conditions, fmt.Sprintf("br.created_at <= $%d", argIdx))
+		args = append(args, f.CreatedBefore)
+	}
+	if !f.CompletedAfter.IsZero() {
+		argIdx++
+		conditions = append(conditions, fmt.Sprintf("br.completed_at >= $%d", argIdx))
+		args = append(args, f.CompletedAfter)
+	}
+	if !f.CompletedBefore.IsZero() {
+		argIdx++
+		conditions = append(conditions, fmt.Sprintf("br.completed_at <= $%d", argIdx))
+		args = append(args, f.CompletedBefore)
+	}
+	if f.ShardCount > 1 {
+		argIdx++
+		conditions = append(conditions, fmt.Sprintf("mod(mod(hashtext(br.id), $%d) + $%d, $%d) = $%d", argIdx, argIdx, argIdx, argIdx+1))
+		args = append(args, f.ShardCount)
+		argIdx++
+		args = append(args, f.ShardID)
+	}
+	// total ignores the keyset cursor the same way it ignores Offset, so
+	// keep the WHERE without it for the count below.
+	countWhere, countArgc := "", len(args)
+	if len(conditions) > 0 {
+		countWhere = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	// A keyset cursor replaces OFFSET outright: WHERE (created_at, id) is
+	// strictly less than the cursor matches the same "strictly after the
+	// last row the caller saw" semantics Offset approximates, but stays
+	// correct as new runs are inserted between pages instead of dropping
+	// or duplicating rows.
+	if f.AfterID != "" {
+		argIdx++
+		tsArg := argIdx
+		argIdx++
+		idArg := argIdx
+		conditions = append(conditions, fmt.Sprintf("(br.created_at, br.id) < ($%d, $%d)", tsArg, idArg))
+		args = append(args, f.AfterCreatedAt, f.AfterID)
+	}
 
 	where := ""
 	if len(conditions) > 0 {
 		where = "WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	// Pagination.
-	limit := 50
-	if f.Limit > 0 && f.Limit <= 200 {
-		limit = f.Limit
-	}
-	argIdx++
-	limitClause := fmt.Sprintf("LIMIT $%d", argIdx)
-	args = append(args, limit)
-
-	offsetClause := ""
-	if f.Offset > 0 {
+	// A sharded call returns its whole shard unpaginated; the caller
+	// applies Limit/Offset once after merging every shard's results.
+	limitClause, offsetClause := "", ""
+	if f.ShardCount <= 1 {
+		limit := 50
+		if f.Limit > 0 && f.Limit <= 200 {
+			limit = f.Limit
+		}
 		argIdx++
-		offsetClause = fmt.Sprintf("OFFSET $%d", argIdx)
-		args = append(args, f.Offset)
+		limitClause = fmt.Sprintf("LIMIT $%d", argIdx)
+		args = append(args, limit)
+
+		if f.Offset > 0 && f.AfterID == "" {
+			argIdx++
+			offsetClause = fmt.Sprintf("OFFSET $%d", argIdx)
+			args = append(args, f.Offset)
+		}
 	}
 
+	// count(*) OVER() rides along with every row so the total matching f
+	// comes back in the same round trip; it's only absent when OFFSET
+	// skips past every matching row, in which case countRunsMatching
+	// below fills it in with one extra query.
 	query := fmt.Sprintf(`
 		SELECT
 			br.id, m.hf_id, it.name,
 			br.framework, br.run_type, br.status,
-			br.created_at, br.started_at, br.completed_at
+			br.created_at, br.started_at, br.completed_at,
+			count(*) OVER()
 		FROM benchmark_runs br
 		JOIN models m ON br.model_id = m.id
 		JOIN instance_types it ON br.instance_type_id = it.id
 		%s
-		ORDER BY br.created_at DESC
+		ORDER BY br.created_at DESC, br.id DESC
 		%s %s
 	`, where, limitClause, offsetClause)
 
 	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("query runs: %w", err)
+		return nil, 0, fmt.Errorf("query runs: %w", err)
 	}
 	defer rows.Close()
 
 	var items []RunListItem
+	var total int
 	for rows.Next() {
 		var item RunListItem
 		err := rows.Scan(
 			&item.ID, &item.ModelHfID, &item.InstanceTypeName,
 			&item.Framework, &item.RunType, &item.Status,
 			&item.CreatedAt, &item.StartedAt, &item.CompletedAt,
+			&total,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("scan run row: %w", err)
+			return nil, 0, fmt.Errorf("scan run row: %w", err)
 		}
 		items = append(items, item)
 	}
-	return items, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if len(items) == 0 && f.ShardCount <= 1 && f.Offset > 0 && f.AfterID == "" {
+		total, err = r.countRunsMatching(ctx, where, args[:argIdx-countPaginationArgs(f)])
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	if f.AfterID != "" {
+		total, err = r.countRunsMatching(ctx, countWhere, args[:countArgc])
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return items, total, nil
+}
+
+// countPaginationArgs returns how many of ListRuns' positional args are
+// the LIMIT/OFFSET values themselves, so countRunsMatching can reuse the
+// same WHERE args without the pagination ones appended after them.
+func countPaginationArgs(f RunFilter) int {
+	if f.ShardCount > 1 {
+		return 0
+	}
+	if f.Offset > 0 {
+		return 2
+	}
+	return 1
+}
+
+// countRunsMatching runs a standalone SELECT count(*) over where/args,
+// used by ListRuns only for the edge case where Offset skips past every
+// matching row, so count(*) OVER() never rides along on a returned row.
+func (r *Repository) countRunsMatching(ctx context.Context, where string, args []any) (int, error) {
+	query := fmt.Sprintf(`
+		SELECT count(*)
+		FROM benchmark_runs br
+		JOIN models m ON br.model_id = m.id
+		JOIN instance_types it ON br.instance_type_id = it.id
+		%s
+	`, where)
+
+	var total int
+	err := r.pool.QueryRow(ctx, query, args...).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("count runs: %w", err)
+	}
+	return total, nil
+}
+
+// runStatusMatches reports whether status satisfies f's Status/Statuses
+// filters, shared by MockRepo/BoltRepo/EtcdRepo's ListRuns so the OR'd
+// Statuses filter is only implemented once; Repository applies the
+// equivalent in SQL.
+func runStatusMatches(status string, f RunFilter) bool {
+	if f.Status != "" && status != f.Status {
+		return false
+	}
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	for _, s := range f.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// runMatchesFilter reports whether run satisfies every RunFilter field
+// this function is responsible for — PolicyID, Trigger, Framework,
+// InstanceFamily, AcceleratorType, and the CreatedAfter/Before and
+// CompletedAfter/Before bounds — given its resolved instance type's
+// family and accelerator type. Callers still apply Status/Statuses (via
+// runStatusMatches), ModelID, and ShardID/ShardCount themselves, since
+// each already resolves those differently (case-insensitive Contains on
+// a lazily-resolved model hf_id, or a plain hash). Shared by
+// MockRepo/BoltRepo/EtcdRepo's ListRuns; Repository applies the
+// equivalent in SQL.
+func runMatchesFilter(run *BenchmarkRun, instFamily, accelType string, f RunFilter) bool {
+	if f.PolicyID != "" && (run.PolicyID == nil || *run.PolicyID != f.PolicyID) {
+		return false
+	}
+	if f.Trigger != "" && run.Trigger != f.Trigger {
+		return false
+	}
+	if f.Framework != "" && run.Framework != f.Framework {
+		return false
+	}
+	if f.InstanceFamily != "" && instFamily != f.InstanceFamily {
+		return false
+	}
+	if f.AcceleratorType != "" && accelType != f.AcceleratorType {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && run.CreatedAt.Before(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && run.CreatedAt.After(f.CreatedBefore) {
+		return false
+	}
+	if !f.CompletedAfter.IsZero() && (run.CompletedAt == nil || run.CompletedAt.Before(f.CompletedAfter)) {
+		return false
+	}
+	if !f.CompletedBefore.IsZero() && (run.CompletedAt == nil || run.CompletedAt.After(f.CompletedBefore)) {
+		return false
+	}
+	return true
+}
+
+// paginateRunItems sorts items newest-first by (CreatedAt, ID) — matching
+// Repository's ORDER BY br.created_at DESC, br.id DESC — then applies f's
+// Limit and either a keyset cursor on (CreatedAt, ID) when AfterID is set
+// or plain Offset otherwise, exactly mirroring ListRuns' own SQL pagination
+// so MockRepo/BoltRepo/EtcdRepo behave the same as Repository. Shared by
+// all three in-process backends' ListRuns.
+func paginateRunItems(items []RunListItem, f RunFilter) ([]RunListItem, int) {
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.After(items[j].CreatedAt)
+		}
+		return items[i].ID > items[j].ID
+	})
+
+	// A sharded call returns its whole shard unpaginated; the caller
+	// applies Limit/Offset once after merging every shard's results.
+	if f.ShardCount > 1 {
+		return items, len(items)
+	}
+
+	total := len(items)
+
+	if f.AfterID != "" {
+		idx := 0
+		for idx < len(items) {
+			it := items[idx]
+			if it.CreatedAt.Before(f.AfterCreatedAt) || (it.CreatedAt.Equal(f.AfterCreatedAt) && it.ID < f.AfterID) {
+				break
+			}
+			idx++
+		}
+		items = items[idx:]
+	} else if f.Offset > 0 {
+		if f.Offset >= len(items) {
+			return nil, total
+		}
+		items = items[f.Offset:]
+	}
+
+	limit := 50
+	if f.Limit > 0 && f.Limit <= 200 {
+		limit = f.Limit
+	}
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, total
 }
 
 // DeleteRun removes a benchmark run and its associated metrics.
 func (r *Repository) DeleteRun(ctx context.Context, runID string) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
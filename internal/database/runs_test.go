@@ -75,7 +75,7 @@ func TestListRuns_NoFilter(t *testing.T) {
 	repo := seedMockRepoWithRuns(t)
 	ctx := context.Background()
 
-	items, err := repo.ListRuns(ctx, RunFilter{})
+	items, _, err := repo.ListRuns(ctx, RunFilter{})
 	if err != nil {
 		t.Fatalf("ListRuns: %v", err)
 	}
@@ -88,7 +88,7 @@ func TestListRuns_FilterByStatus(t *testing.T) {
 	repo := seedMockRepoWithRuns(t)
 	ctx := context.Background()
 
-	items, err := repo.ListRuns(ctx, RunFilter{Status: "completed"})
+	items, _, err := repo.ListRuns(ctx, RunFilter{Status: "completed"})
 	if err != nil {
 		t.Fatalf("ListRuns: %v", err)
 	}
@@ -104,7 +104,7 @@ func TestListRuns_FilterByModel(t *testing.T) {
 	repo := seedMockRepoWithRuns(t)
 	ctx := context.Background()
 
-	items, err := repo.ListRuns(ctx, RunFilter{ModelID: "llama"})
+	items, _, err := repo.ListRuns(ctx, RunFilter{ModelID: "llama"})
 	if err != nil {
 		t.Fatalf("ListRuns: %v", err)
 	}
@@ -122,7 +122,7 @@ func TestListRuns_Pagination(t *testing.T) {
 	repo := seedMockRepoWithRuns(t)
 	ctx := context.Background()
 
-	items, err := repo.ListRuns(ctx, RunFilter{Limit: 2})
+	items, _, err := repo.ListRuns(ctx, RunFilter{Limit: 2})
 	if err != nil {
 		t.Fatalf("ListRuns: %v", err)
 	}
@@ -130,7 +130,7 @@ func TestListRuns_Pagination(t *testing.T) {
 		t.Errorf("expected 2 runs with limit 2, got %d", len(items))
 	}
 
-	items2, err := repo.ListRuns(ctx, RunFilter{Limit: 2, Offset: 2})
+	items2, _, err := repo.ListRuns(ctx, RunFilter{Limit: 2, Offset: 2})
 	if err != nil {
 		t.Fatalf("ListRuns: %v", err)
 	}
@@ -143,7 +143,7 @@ func TestListRuns_OffsetBeyondTotal(t *testing.T) {
 	repo := seedMockRepoWithRuns(t)
 	ctx := context.Background()
 
-	items, err := repo.ListRuns(ctx, RunFilter{Offset: 100})
+	items, _, err := repo.ListRuns(ctx, RunFilter{Offset: 100})
 	if err != nil {
 		t.Fatalf("ListRuns: %v", err)
 	}
@@ -152,12 +152,39 @@ func TestListRuns_OffsetBeyondTotal(t *testing.T) {
 	}
 }
 
+// TestListRuns_Total checks that the returned total reflects every run
+// matching the filter, independent of Limit/Offset, both within and
+// beyond the matching set's size.
+func TestListRuns_Total(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, total, err := repo.ListRuns(ctx, RunFilter{ModelID: "llama", Limit: 1})
+	if err != nil {
+		t.Fatalf("ListRuns: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1", len(items))
+	}
+	if total != 2 {
+		t.Errorf("got total %d, want 2", total)
+	}
+
+	_, overrun, err := repo.ListRuns(ctx, RunFilter{ModelID: "llama", Limit: 1, Offset: 100})
+	if err != nil {
+		t.Fatalf("ListRuns offset overrun: %v", err)
+	}
+	if overrun != 2 {
+		t.Errorf("got total %d for offset overrun, want 2", overrun)
+	}
+}
+
 func TestDeleteRun(t *testing.T) {
 	repo := seedMockRepoWithRuns(t)
 	ctx := context.Background()
 
 	// List runs to find an ID.
-	items, err := repo.ListRuns(ctx, RunFilter{})
+	items, _, err := repo.ListRuns(ctx, RunFilter{})
 	if err != nil {
 		t.Fatalf("ListRuns: %v", err)
 	}
@@ -168,7 +195,7 @@ func TestDeleteRun(t *testing.T) {
 	runID := items[0].ID
 
 	// Seed some metrics for that run.
-	_ = repo.PersistMetrics(ctx, runID, &BenchmarkMetrics{})
+	_ = repo.PersistMetrics(ctx, runID, &BenchmarkMetrics{}, nil)
 
 	// Delete.
 	if err := repo.DeleteRun(ctx, runID); err != nil {
@@ -198,7 +225,7 @@ func TestListRuns_ItemFields(t *testing.T) {
 	repo := seedMockRepoWithRuns(t)
 	ctx := context.Background()
 
-	items, err := repo.ListRuns(ctx, RunFilter{Status: "pending"})
+	items, _, err := repo.ListRuns(ctx, RunFilter{Status: "pending"})
 	if err != nil {
 		t.Fatalf("ListRuns: %v", err)
 	}
@@ -220,3 +247,245 @@ func TestListRuns_ItemFields(t *testing.T) {
 		t.Errorf("expected on_demand, got %s", item.RunType)
 	}
 }
+
+func TestCancelRun_PendingAndRunningSucceed(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	for _, status := range []string{"pending", "running"} {
+		items, _, err := repo.ListRuns(ctx, RunFilter{Status: status})
+		if err != nil || len(items) == 0 {
+			t.Fatalf("no %s run to cancel", status)
+		}
+		if err := repo.CancelRun(ctx, items[0].ID); err != nil {
+			t.Errorf("CancelRun(%s run): %v", status, err)
+		}
+		if got := repo.GetRunStatus(items[0].ID); got != "failed" {
+			t.Errorf("status = %s, want failed", got)
+		}
+	}
+}
+
+func TestCancelRun_AlreadyTerminal(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{Status: "completed"})
+	if err != nil || len(items) == 0 {
+		t.Fatal("no completed run to test against")
+	}
+
+	if err := repo.CancelRun(ctx, items[0].ID); err != ErrRunNotCancellable {
+		t.Errorf("err = %v, want ErrRunNotCancellable", err)
+	}
+}
+
+func TestCancelRun_NotFound(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+
+	if err := repo.CancelRun(context.Background(), "run-nonexistent"); err != ErrRunNotFound {
+		t.Errorf("err = %v, want ErrRunNotFound", err)
+	}
+}
+
+func TestListRuns_FilterByStatuses(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{Statuses: []string{"running", "pending"}})
+	if err != nil {
+		t.Fatalf("ListRuns: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(items))
+	}
+	for _, item := range items {
+		if item.Status != "running" && item.Status != "pending" {
+			t.Errorf("unexpected status: %s", item.Status)
+		}
+	}
+}
+
+func TestListRuns_FilterByFramework(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{Framework: "vllm"})
+	if err != nil {
+		t.Fatalf("ListRuns: %v", err)
+	}
+	if len(items) != 4 {
+		t.Errorf("expected 4 vllm runs, got %d", len(items))
+	}
+
+	items, _, err = repo.ListRuns(ctx, RunFilter{Framework: "tgi"})
+	if err != nil {
+		t.Fatalf("ListRuns: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("expected 0 tgi runs, got %d", len(items))
+	}
+}
+
+func TestListRuns_FilterByInstanceFamilyAndAcceleratorType(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{InstanceFamily: "g5"})
+	if err != nil {
+		t.Fatalf("ListRuns: %v", err)
+	}
+	if len(items) != 4 {
+		t.Errorf("expected 4 g5 runs, got %d", len(items))
+	}
+
+	items, _, err = repo.ListRuns(ctx, RunFilter{AcceleratorType: "gpu"})
+	if err != nil {
+		t.Fatalf("ListRuns: %v", err)
+	}
+	if len(items) != 4 {
+		t.Errorf("expected 4 gpu runs, got %d", len(items))
+	}
+
+	items, _, err = repo.ListRuns(ctx, RunFilter{InstanceFamily: "trn1"})
+	if err != nil {
+		t.Fatalf("ListRuns: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("expected 0 trn1 runs, got %d", len(items))
+	}
+}
+
+func TestListRuns_FilterByCreatedAtRange(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{})
+	if err != nil || len(items) != 4 {
+		t.Fatalf("seed: %v / %d items", err, len(items))
+	}
+
+	// Spread CreatedAt out so CreatedAfter/CreatedBefore bound a strict
+	// subset, directly poking the map since CreateBenchmarkRun always
+	// stamps CreatedAt with time.Now().
+	base := time.Now().Add(-time.Hour)
+	for i, item := range items {
+		repo.runs[item.ID].CreatedAt = base.Add(time.Duration(i) * time.Minute)
+	}
+
+	mid := base.Add(90 * time.Second)
+	filtered, _, err := repo.ListRuns(ctx, RunFilter{CreatedAfter: mid})
+	if err != nil {
+		t.Fatalf("ListRuns: %v", err)
+	}
+	if len(filtered) != 2 {
+		t.Errorf("expected 2 runs created after %s, got %d", mid, len(filtered))
+	}
+
+	filtered, _, err = repo.ListRuns(ctx, RunFilter{CreatedBefore: mid})
+	if err != nil {
+		t.Fatalf("ListRuns: %v", err)
+	}
+	if len(filtered) != 2 {
+		t.Errorf("expected 2 runs created before %s, got %d", mid, len(filtered))
+	}
+}
+
+func TestListRuns_FilterByCompletedAtRangeExcludesNonTerminalRuns(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{Status: "completed"})
+	if err != nil || len(items) != 1 {
+		t.Fatalf("seed: %v / %d items", err, len(items))
+	}
+	now := time.Now()
+	repo.runs[items[0].ID].CompletedAt = &now
+
+	// Only the "completed" run has a CompletedAt at all; bounding on it
+	// must never match the three runs that haven't reached a terminal
+	// status.
+	filtered, _, err := repo.ListRuns(ctx, RunFilter{CompletedAfter: now.Add(-24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("ListRuns: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].Status != "completed" {
+		t.Fatalf("expected only the completed run, got %+v", filtered)
+	}
+}
+
+func TestListRuns_KeysetPaginationStableUnderConcurrentInserts(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{})
+	if err != nil || len(items) != 4 {
+		t.Fatalf("seed: %v / %d items", err, len(items))
+	}
+
+	// Lay the 4 seeded runs out newest-first at one-minute increments so
+	// ordering is deterministic (CreateBenchmarkRun stamps CreatedAt with
+	// time.Now(), which is too close together to assert page boundaries
+	// against reliably).
+	base := time.Now().Add(-time.Hour)
+	for i, item := range items {
+		repo.runs[item.ID].CreatedAt = base.Add(time.Duration(i) * time.Minute)
+	}
+
+	// Page 1: the two newest runs.
+	page1, total, err := repo.ListRuns(ctx, RunFilter{Limit: 2})
+	if err != nil {
+		t.Fatalf("page 1: %v", err)
+	}
+	if len(page1) != 2 || total != 4 {
+		t.Fatalf("page 1 = %d items, total %d, want 2 items, total 4", len(page1), total)
+	}
+	cursor := page1[len(page1)-1]
+
+	// Simulate a new run being inserted (newer than everything seeded so
+	// far) between page 1 and page 2 — an offset-based page 2 would now
+	// either skip or duplicate a row; a keyset cursor must not.
+	newRun := &BenchmarkRun{
+		ModelID:        "model-001",
+		InstanceTypeID: "it-001",
+		Framework:      "vllm",
+		Status:         "pending",
+	}
+	newID, err := repo.CreateBenchmarkRun(ctx, newRun)
+	if err != nil {
+		t.Fatalf("concurrent insert: %v", err)
+	}
+	repo.runs[newID].CreatedAt = base.Add(2 * time.Hour)
+
+	page2, total, err := repo.ListRuns(ctx, RunFilter{
+		Limit:          2,
+		AfterCreatedAt: cursor.CreatedAt,
+		AfterID:        cursor.ID,
+	})
+	if err != nil {
+		t.Fatalf("page 2: %v", err)
+	}
+	if total != 5 {
+		t.Errorf("total after concurrent insert = %d, want 5", total)
+	}
+	if len(page2) != 2 {
+		t.Fatalf("page 2 = %d items, want 2", len(page2))
+	}
+
+	seen := map[string]bool{}
+	for _, item := range page1 {
+		seen[item.ID] = true
+	}
+	for _, item := range page2 {
+		if item.ID == newID {
+			t.Errorf("page 2 leaked the newly-inserted (newer) run %s", newID)
+		}
+		if seen[item.ID] {
+			t.Errorf("page 2 re-returned page 1's run %s", item.ID)
+		}
+		seen[item.ID] = true
+	}
+	if len(seen) != 4 {
+		t.Errorf("expected exactly the 4 originally-seeded runs across both pages, saw %d distinct IDs", len(seen))
+	}
+}
@@ -0,0 +1,18 @@
+package database
+
+import "hash/fnv"
+
+// shardMatch reports whether runID belongs to shard shardID of shardCount
+// shards, by hashing the run ID with FNV-1a so membership is stable and
+// deterministic across repeated calls. shardCount <= 1 means "not
+// sharded": every run matches. This is the basis for fanning a listing
+// out across N workers via CatalogFilter/RunFilter's ShardID/ShardCount
+// fields and merging their results, e.g. via ListCatalogSharded.
+func shardMatch(runID string, shardID, shardCount int) bool {
+	if shardCount <= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(runID))
+	return int(h.Sum32()%uint32(shardCount)) == shardID
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// SLOSpec is the latency budget an SLO search bisects against: TTFT p95
+// and E2E latency p99, both in milliseconds. Either threshold left nil is
+// unconstrained, so a caller can search on just one of the two.
+type SLOSpec struct {
+	TTFTP95MsMax       *float64 `json:"ttft_p95_ms_max,omitempty"`
+	E2ELatencyP99MsMax *float64 `json:"e2e_latency_p99_ms_max,omitempty"`
+}
+
+// SLOSearchRequest is the input to a POST /runs:slo-search submission: the
+// same model/instance/framework knobs as RunRequest, minus Concurrency
+// (the orchestrator chooses it probe by probe) plus the SLO it searches
+// against and the bisection's stopping conditions.
+type SLOSearchRequest struct {
+	ModelHfID            string            `json:"model_hf_id"`
+	ModelHfRevision      string            `json:"model_hf_revision"`
+	InstanceTypeName     string            `json:"instance_type_name"`
+	Framework            string            `json:"framework"`
+	FrameworkVersion     string            `json:"framework_version"`
+	TensorParallelDegree int               `json:"tensor_parallel_degree"`
+	Quantization         *string           `json:"quantization,omitempty"`
+	InputSequenceLength  int               `json:"input_sequence_length"`
+	OutputSequenceLength int               `json:"output_sequence_length"`
+	DatasetName          string            `json:"dataset_name"`
+	DatasetSpec          *DatasetSpec      `json:"dataset_spec,omitempty"`
+	MaxModelLen          int               `json:"max_model_len,omitempty"`
+	HfToken              string            `json:"hf_token,omitempty"`
+	Plugins              []FrameworkPlugin `json:"plugins,omitempty"`
+
+	SLOSpec SLOSpec `json:"slo_spec"`
+
+	// MaxConcurrency caps the doubling phase, so a model that never
+	// violates the SLO doesn't double forever; defaults to
+	// defaultSLOSearchMaxConcurrency (see orchestrator.ExecuteSLOSearch)
+	// when zero.
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+
+	// Tolerance is the bisection's stopping width: the search stops once
+	// the last passing and first failing concurrency are within Tolerance
+	// of each other. Defaults to 1 (the tightest possible bound) when zero.
+	Tolerance int `json:"tolerance,omitempty"`
+}
+
+// Validate rejects an SLOSearchRequest that orchestrator.ExecuteSLOSearch
+// could never converge on: a SLOSpec with no bound at all (nothing to
+// bisect against) or a negative MaxConcurrency/Tolerance (the bisection
+// loop never narrows once the gap is already within a negative tolerance).
+func (s SLOSearchRequest) Validate() error {
+	if s.SLOSpec.TTFTP95MsMax == nil && s.SLOSpec.E2ELatencyP99MsMax == nil {
+		return fmt.Errorf("slo_spec must set at least one of ttft_p95_ms_max or e2e_latency_p99_ms_max")
+	}
+	if s.MaxConcurrency < 0 {
+		return fmt.Errorf("max_concurrency must not be negative")
+	}
+	if s.Tolerance < 0 {
+		return fmt.Errorf("tolerance must not be negative")
+	}
+	return nil
+}
+
+// SLOSearchRunDetail is a denormalized probe-run row for the SLO search
+// detail endpoint: enough of RunListItem to summarize status, plus the
+// concurrency probed and the metrics it produced.
+type SLOSearchRunDetail struct {
+	RunListItem
+	Concurrency int               `json:"concurrency"`
+	Metrics     *BenchmarkMetrics `json:"metrics,omitempty"`
+}
+
+// ListRunsByParent returns every probe run created under parentRunID,
+// newest first, each with the concurrency it probed and the metrics it
+// produced — enough for a caller to see the bisection's trajectory
+// without a separate GetMetricsByRunID call per probe.
+func (r *Repository) ListRunsByParent(ctx context.Context, parentRunID string) ([]SLOSearchRunDetail, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT
+			br.id, m.hf_id, it.name, br.framework, br.run_type, br.status,
+			br.created_at, br.started_at, br.completed_at,
+			br.concurrency,
+			bm.ttft_p95_ms, bm.e2e_latency_p99_ms, bm.throughput_aggregate_tps
+		FROM benchmark_runs br
+		JOIN models m ON br.model_id = m.id
+		JOIN instance_types it ON br.instance_type_id = it.id
+		LEFT JOIN benchmark_metrics bm ON bm.run_id = br.id
+		WHERE br.parent_run_id = $1
+		ORDER BY br.created_at DESC`, parentRunID)
+	if err != nil {
+		return nil, fmt.Errorf("query runs by parent: %w", err)
+	}
+	defer rows.Close()
+
+	var details []SLOSearchRunDetail
+	for rows.Next() {
+		var d SLOSearchRunDetail
+		var ttftP95, e2eP99, throughput *float64
+		if err := rows.Scan(
+			&d.ID, &d.ModelHfID, &d.InstanceTypeName, &d.Framework, &d.RunType, &d.Status,
+			&d.CreatedAt, &d.StartedAt, &d.CompletedAt,
+			&d.Concurrency,
+			&ttftP95, &e2eP99, &throughput,
+		); err != nil {
+			return nil, fmt.Errorf("scan run row: %w", err)
+		}
+		if ttftP95 != nil || e2eP99 != nil || throughput != nil {
+			d.Metrics = &BenchmarkMetrics{
+				RunID:                  d.ID,
+				TTFTP95Ms:              ttftP95,
+				E2ELatencyP99Ms:        e2eP99,
+				ThroughputAggregateTPS: throughput,
+			}
+		}
+		details = append(details, d)
+	}
+	return details, rows.Err()
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Sweep is a benchmark sweep (matrix) submission: one runs:batch request
+// expanded into a Cartesian product of child BenchmarkRuns, tracked under
+// a single sweep_id so the submitter can poll one resource instead of
+// every child run.
+type Sweep struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SweepRunDetail is a denormalized child-run row for the sweep detail
+// endpoint: enough of RunListItem to summarize status, plus the
+// parameters and metrics needed to compute the Pareto frontier.
+type SweepRunDetail struct {
+	RunListItem
+	Concurrency          int               `json:"concurrency"`
+	TensorParallelDegree int               `json:"tensor_parallel_degree"`
+	InputSequenceLength  int               `json:"input_sequence_length"`
+	OutputSequenceLength int               `json:"output_sequence_length"`
+	Quantization         *string           `json:"quantization,omitempty"`
+	Metrics              *BenchmarkMetrics `json:"metrics,omitempty"`
+}
+
+// CreateSweep inserts a sweep row and all of its child runs in a single
+// transaction: either every run is created or none are, so a caller that
+// validated instance types and the model up front never ends up with a
+// partially-created sweep. runs must already have ModelID/InstanceTypeID
+// resolved; CreateSweep stamps SweepID on each and returns the sweep ID
+// alongside the child run IDs in the same order as runs.
+func (r *Repository) CreateSweep(ctx context.Context, runs []*BenchmarkRun) (string, []string, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var sweepID string
+	if err := tx.QueryRow(ctx,
+		`INSERT INTO sweeps DEFAULT VALUES RETURNING id`,
+	).Scan(&sweepID); err != nil {
+		return "", nil, fmt.Errorf("insert sweep: %w", err)
+	}
+
+	runIDs := make([]string, 0, len(runs))
+	for _, run := range runs {
+		var id string
+		err := tx.QueryRow(ctx,
+			`INSERT INTO benchmark_runs
+			    (model_id, instance_type_id, framework, framework_version,
+			     tensor_parallel_degree, quantization, concurrency,
+			     input_sequence_length, output_sequence_length, dataset_name,
+			     run_type, status, sweep_id)
+			 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)
+			 RETURNING id`,
+			run.ModelID, run.InstanceTypeID, run.Framework, run.FrameworkVersion,
+			run.TensorParallelDegree, run.Quantization, run.Concurrency,
+			run.InputSequenceLength, run.OutputSequenceLength, run.DatasetName,
+			run.RunType, run.Status, sweepID,
+		).Scan(&id)
+		if err != nil {
+			return "", nil, fmt.Errorf("insert sweep run: %w", err)
+		}
+		run.ID = id
+		run.SweepID = &sweepID
+		runIDs = append(runIDs, id)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return sweepID, runIDs, nil
+}
+
+// GetSweep returns a sweep by ID, or nil if not found.
+func (r *Repository) GetSweep(ctx context.Context, sweepID string) (*Sweep, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	var sw Sweep
+	err := r.pool.QueryRow(ctx,
+		`SELECT id, created_at FROM sweeps WHERE id = $1`, sweepID,
+	).Scan(&sw.ID, &sw.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query sweep: %w", err)
+	}
+	return &sw, nil
+}
+
+// ListSweepRuns returns every child run of sweepID, joined with model and
+// instance type names and left-joined with metrics so in-flight runs
+// (which have none yet) still appear.
+func (r *Repository) ListSweepRuns(ctx context.Context, sweepID string) ([]SweepRunDetail, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT
+			br.id, m.hf_id, it.name, br.framework, br.run_type, br.status,
+			br.created_at, br.started_at, br.completed_at,
+			br.concurrency, br.tensor_parallel_degree,
+			br.input_sequence_length, br.output_sequence_length, br.quantization,
+			bm.ttft_p50_ms, bm.e2e_latency_p50_ms, bm.throughput_aggregate_tps
+		FROM benchmark_runs br
+		JOIN models m ON br.model_id = m.id
+		JOIN instance_types it ON br.instance_type_id = it.id
+		LEFT JOIN benchmark_metrics bm ON bm.run_id = br.id
+		WHERE br.sweep_id = $1
+		ORDER BY br.created_at`, sweepID)
+	if err != nil {
+		return nil, fmt.Errorf("list sweep runs: %w", err)
+	}
+	defer rows.Close()
+
+	var details []SweepRunDetail
+	for rows.Next() {
+		var d SweepRunDetail
+		var ttftP50, e2eP50, throughput *float64
+		if err := rows.Scan(
+			&d.ID, &d.ModelHfID, &d.InstanceTypeName, &d.Framework, &d.RunType, &d.Status,
+			&d.CreatedAt, &d.StartedAt, &d.CompletedAt,
+			&d.Concurrency, &d.TensorParallelDegree,
+			&d.InputSequenceLength, &d.OutputSequenceLength, &d.Quantization,
+			&ttftP50, &e2eP50, &throughput,
+		); err != nil {
+			return nil, fmt.Errorf("scan sweep run row: %w", err)
+		}
+		if ttftP50 != nil || e2eP50 != nil || throughput != nil {
+			d.Metrics = &BenchmarkMetrics{
+				RunID:                  d.ID,
+				TTFTP50Ms:              ttftP50,
+				E2ELatencyP50Ms:        e2eP50,
+				ThroughputAggregateTPS: throughput,
+			}
+		}
+		details = append(details, d)
+	}
+	return details, rows.Err()
+}
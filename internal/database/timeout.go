@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Default per-method timeouts applied when RepositoryOptions leaves the
+// corresponding field zero.
+const (
+	defaultQueryTimeout = 5 * time.Second
+	defaultExecTimeout  = 10 * time.Second
+)
+
+// RepositoryOptions configures the default timeout every Repository
+// method derives its context from before issuing a pool.Query/QueryRow
+// or pool.Exec call. QueryTimeout covers reads, ExecTimeout covers
+// writes (INSERT/UPDATE/DELETE) — writes get more headroom since they
+// often touch more rows or wait on row locks. A stalled Postgres then
+// fails a single query instead of wedging whatever caller context (the
+// controller's reconcile loop, an API handler's request context) is
+// waiting on it.
+type RepositoryOptions struct {
+	QueryTimeout time.Duration
+	ExecTimeout  time.Duration
+}
+
+func (o RepositoryOptions) withDefaults() RepositoryOptions {
+	if o.QueryTimeout <= 0 {
+		o.QueryTimeout = defaultQueryTimeout
+	}
+	if o.ExecTimeout <= 0 {
+		o.ExecTimeout = defaultExecTimeout
+	}
+	return o
+}
+
+// RepositoryOption configures a Repository at construction time, matching
+// the functional-option convention used by api.ServerOption and
+// orchestrator.Option.
+type RepositoryOption func(*Repository)
+
+// WithRepositoryOptions overrides the default per-method query/exec
+// timeouts.
+func WithRepositoryOptions(opts RepositoryOptions) RepositoryOption {
+	return func(r *Repository) { r.opts = opts.withDefaults() }
+}
+
+// deadlineGate backs WithDeadline: a single timer closes done once, so
+// every derived Repository sharing it can check for expiry with a
+// non-blocking channel receive instead of each arming (and leaking) its
+// own context.WithDeadline timer.
+type deadlineGate struct {
+	done chan struct{}
+}
+
+func (g *deadlineGate) expired() bool {
+	select {
+	case <-g.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// WithDeadline returns a Repository sharing this one's connection pool
+// and options, but which short-circuits every operation once deadline
+// passes: a single time.AfterFunc timer closes a channel on fire, and
+// withTimeout checks that channel before issuing a new query rather than
+// letting it start and time out on its own. Useful for a caller (e.g.
+// the scheduler) that wants "stop trying once the current benchmarking
+// window ends" semantics without threading a context deadline through
+// every call site it doesn't otherwise control.
+func (r *Repository) WithDeadline(deadline time.Time) *Repository {
+	gate := &deadlineGate{done: make(chan struct{})}
+	time.AfterFunc(time.Until(deadline), func() { close(gate.done) })
+	return &Repository{pool: r.pool, broker: r.broker, opts: r.opts, deadline: gate}
+}
+
+// withTimeout derives a context for a single pool call: it's already
+// canceled if this Repository's deadline (set via WithDeadline) has
+// fired, otherwise it carries a fresh timeout of d.
+func (r *Repository) withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if r.deadline != nil && r.deadline.expired() {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		return ctx, cancel
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// HealthCheck pings the connection pool and reports how stale each
+// region's pricing data is, so a caller can distinguish "Postgres is
+// down" from "Postgres is fine but pricing-refresh stopped running".
+func (r *Repository) HealthCheck(ctx context.Context) (RepositoryHealth, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	if err := r.pool.Ping(ctx); err != nil {
+		return RepositoryHealth{}, fmt.Errorf("ping database: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT region, MAX(effective_date)::text, now() - MAX(effective_date)::timestamptz
+		FROM pricing
+		GROUP BY region
+		ORDER BY region`)
+	if err != nil {
+		return RepositoryHealth{}, fmt.Errorf("query pricing lag: %w", err)
+	}
+	defer rows.Close()
+
+	health := RepositoryHealth{Reachable: true}
+	for rows.Next() {
+		var lag PricingLag
+		var age time.Duration
+		if err := rows.Scan(&lag.Region, &lag.LatestEffectiveDate, &age); err != nil {
+			return RepositoryHealth{}, fmt.Errorf("scan pricing lag: %w", err)
+		}
+		lag.AgeSeconds = age.Seconds()
+		health.PricingLag = append(health.PricingLag, lag)
+	}
+	if err := rows.Err(); err != nil {
+		return RepositoryHealth{}, fmt.Errorf("iterate pricing lag: %w", err)
+	}
+	return health, nil
+}
+
+// RepositoryHealth is HealthCheck's result.
+type RepositoryHealth struct {
+	Reachable  bool         `json:"reachable"`
+	PricingLag []PricingLag `json:"pricing_lag,omitempty"`
+}
+
+// PricingLag reports how old the newest pricing row in a region is.
+type PricingLag struct {
+	Region              string  `json:"region"`
+	LatestEffectiveDate string  `json:"latest_effective_date"`
+	AgeSeconds          float64 `json:"age_seconds"`
+}
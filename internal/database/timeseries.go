@@ -0,0 +1,485 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Sample is one raw per-request observation for a run, captured at the
+// granularity the loadgen emits them (roughly one per completed request)
+// rather than the run-level percentiles BenchmarkMetrics aggregates.
+// Storing these lets the UI/CLI render latency-over-time plots and
+// recompute percentiles on arbitrary windows after the fact.
+type Sample struct {
+	Timestamp                 time.Time `json:"timestamp"`
+	TTFTMs                    *float64  `json:"ttft_ms,omitempty"`
+	ITLMs                     *float64  `json:"itl_ms,omitempty"`
+	TokensOut                 int       `json:"tokens_out"`
+	AcceleratorUtilizationPct *float64  `json:"accelerator_utilization_pct,omitempty"`
+}
+
+// Point is a single (timestamp, value) pair returned by QueryRange.
+type Point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// sampleMetric extracts the named metric from a Sample; ("ttft_ms",
+// "itl_ms", "tokens_out", "accelerator_utilization_pct") mirror the field
+// names on Sample. Returns ok=false if the metric is unknown or the
+// sample didn't record it.
+func sampleMetric(s Sample, metric string) (float64, bool) {
+	switch metric {
+	case "ttft_ms":
+		if s.TTFTMs == nil {
+			return 0, false
+		}
+		return *s.TTFTMs, true
+	case "itl_ms":
+		if s.ITLMs == nil {
+			return 0, false
+		}
+		return *s.ITLMs, true
+	case "tokens_out":
+		return float64(s.TokensOut), true
+	case "accelerator_utilization_pct":
+		if s.AcceleratorUtilizationPct == nil {
+			return 0, false
+		}
+		return *s.AcceleratorUtilizationPct, true
+	default:
+		return 0, false
+	}
+}
+
+// defaultSampleRetention is how long MockRepo keeps raw samples once
+// ApplyRetention starts dropping them, matching the "N days" default
+// mentioned for a TSDB-modeled store; aggregated BenchmarkMetrics rows
+// are never subject to retention.
+const defaultSampleRetention = 14 * 24 * time.Hour
+
+// maxChunkSamples closes a run's head chunk once it reaches this many
+// samples, analogous to Prometheus's time-bounded head chunks — it bounds
+// how much a single AppendSamples call (or burst of them) can grow one
+// unmerged chunk before Compact has something worth merging.
+const maxChunkSamples = 256
+
+// sampleChunk is one append-only run of Samples. The head chunk (the
+// last one, while !closed) is the only one AppendSamples ever writes to;
+// closed chunks are read-only and are what Compact merges.
+type sampleChunk struct {
+	samples []Sample
+	closed  bool
+}
+
+// runSamples is one run's WAL-ordered chunk history, under its own lock
+// so operations against different runs never contend and QueryRange (a
+// reader) only blocks on writers touching the *same* run.
+type runSamples struct {
+	mu     sync.RWMutex
+	chunks []*sampleChunk
+}
+
+// sampleStore is MockRepo's in-process time-series subsystem. byRun is
+// guarded by its own mutex so adding a new run's series, or listing every
+// run that has one for Compact/ApplyRetention, never contends with
+// MockRepo.mu — the lock ListRuns/ListCatalog take — which is the
+// reader/writer discipline the run-health/integrity callers depend on.
+type sampleStore struct {
+	mu        sync.RWMutex
+	byRun     map[string]*runSamples
+	retention time.Duration
+}
+
+func newSampleStore() *sampleStore {
+	return &sampleStore{
+		byRun:     make(map[string]*runSamples),
+		retention: defaultSampleRetention,
+	}
+}
+
+func (s *sampleStore) runFor(runID string) *runSamples {
+	s.mu.RLock()
+	rs, ok := s.byRun[runID]
+	s.mu.RUnlock()
+	if ok {
+		return rs
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rs, ok := s.byRun[runID]; ok {
+		return rs
+	}
+	rs = &runSamples{}
+	s.byRun[runID] = rs
+	return rs
+}
+
+func (s *sampleStore) append(runID string, samples []Sample) {
+	rs := s.runFor(runID)
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if len(rs.chunks) == 0 || rs.chunks[len(rs.chunks)-1].closed {
+		rs.chunks = append(rs.chunks, &sampleChunk{})
+	}
+	head := rs.chunks[len(rs.chunks)-1]
+	head.samples = append(head.samples, samples...)
+	if len(head.samples) >= maxChunkSamples {
+		head.closed = true
+	}
+}
+
+func (s *sampleStore) queryRange(runID, metric string, from, to time.Time, step time.Duration) ([]Point, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be positive")
+	}
+
+	s.mu.RLock()
+	rs, ok := s.byRun[runID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+
+	rs.mu.RLock()
+	var matched []Sample
+	for _, chunk := range rs.chunks {
+		for _, sm := range chunk.samples {
+			if sm.Timestamp.Before(from) || !sm.Timestamp.Before(to) {
+				continue
+			}
+			if _, ok := sampleMetric(sm, metric); ok {
+				matched = append(matched, sm)
+			}
+		}
+	}
+	rs.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Timestamp.Before(matched[j].Timestamp) })
+
+	var points []Point
+	for bucketStart := from; bucketStart.Before(to); bucketStart = bucketStart.Add(step) {
+		bucketEnd := bucketStart.Add(step)
+		var sum float64
+		var count int
+		for _, sm := range matched {
+			if sm.Timestamp.Before(bucketStart) || !sm.Timestamp.Before(bucketEnd) {
+				continue
+			}
+			v, _ := sampleMetric(sm, metric)
+			sum += v
+			count++
+		}
+		if count == 0 {
+			continue
+		}
+		points = append(points, Point{Timestamp: bucketStart, Value: sum / float64(count)})
+	}
+	return points, nil
+}
+
+// all returns every sample recorded for runID across all chunks, in
+// timestamp order, for Snapshot to export.
+func (s *sampleStore) all(runID string) []Sample {
+	s.mu.RLock()
+	rs, ok := s.byRun[runID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	var all []Sample
+	for _, chunk := range rs.chunks {
+		all = append(all, chunk.samples...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Timestamp.Before(all[j].Timestamp) })
+	return all
+}
+
+// runIDs returns every run with at least one appended sample, for
+// Compact/ApplyRetention to iterate without holding the map lock across
+// their per-run work.
+func (s *sampleStore) runIDs() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make([]string, 0, len(s.byRun))
+	for id := range s.byRun {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// compactRun merges every closed chunk for runID into a single chunk,
+// leaving an in-progress head chunk (if any) untouched — unless final is
+// set, in which case the run is terminal and will never be appended to
+// again, so the head chunk is closed and folded into the merge too. It's
+// a no-op if there's one or zero chunks to merge.
+func (s *sampleStore) compactRun(runID string, final bool) {
+	s.mu.RLock()
+	rs, ok := s.byRun[runID]
+	s.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	closedCount := len(rs.chunks)
+	var head *sampleChunk
+	if closedCount > 0 && !rs.chunks[closedCount-1].closed {
+		if !final {
+			head = rs.chunks[closedCount-1]
+			closedCount--
+		}
+	}
+	if closedCount <= 1 {
+		return
+	}
+
+	merged := &sampleChunk{closed: true}
+	for _, chunk := range rs.chunks[:closedCount] {
+		merged.samples = append(merged.samples, chunk.samples...)
+	}
+	sort.Slice(merged.samples, func(i, j int) bool { return merged.samples[i].Timestamp.Before(merged.samples[j].Timestamp) })
+
+	if head != nil {
+		rs.chunks = []*sampleChunk{merged, head}
+	} else {
+		rs.chunks = []*sampleChunk{merged}
+	}
+}
+
+// dropBefore removes every sample timestamped before cutoff from runID's
+// chunks, discarding any chunk left empty.
+func (s *sampleStore) dropBefore(runID string, cutoff time.Time) {
+	s.mu.RLock()
+	rs, ok := s.byRun[runID]
+	s.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	var kept []*sampleChunk
+	for _, chunk := range rs.chunks {
+		var keptSamples []Sample
+		for _, sm := range chunk.samples {
+			if !sm.Timestamp.Before(cutoff) {
+				keptSamples = append(keptSamples, sm)
+			}
+		}
+		if len(keptSamples) > 0 {
+			chunk.samples = keptSamples
+			kept = append(kept, chunk)
+		}
+	}
+	rs.chunks = kept
+}
+
+// AppendSamples records raw per-request samples for runID, written
+// through the head chunk the way a TSDB's WAL absorbs writes before
+// they're eligible for compaction.
+func (m *MockRepo) AppendSamples(_ context.Context, runID string, samples []Sample) error {
+	m.mu.Lock()
+	_, ok := m.runs[runID]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	m.samples.append(runID, samples)
+	return nil
+}
+
+// QueryRange averages metric over step-sized buckets spanning [from, to)
+// and returns one Point per non-empty bucket, for rendering a
+// latency-over-time plot or recomputing a percentile over a custom
+// window (the caller buckets further client-side if it needs p50/p99
+// rather than a mean).
+func (m *MockRepo) QueryRange(_ context.Context, runID, metric string, from, to time.Time, step time.Duration) ([]Point, error) {
+	return m.samples.queryRange(runID, metric, from, to, step)
+}
+
+// Compact merges closed sample chunks into one per run, for every run
+// whose status is no longer "pending" or "running" — i.e. once a run
+// completes, its raw samples are done growing and are worth merging into
+// a single block the way a Prometheus TSDB compacts head blocks once
+// they're no longer being actively written.
+func (m *MockRepo) Compact(_ context.Context) error {
+	for _, runID := range m.samples.runIDs() {
+		m.mu.Lock()
+		run, ok := m.runs[runID]
+		m.mu.Unlock()
+		if !ok || run.Status == "pending" || run.Status == "running" {
+			continue
+		}
+		m.samples.compactRun(runID, true)
+	}
+	return nil
+}
+
+// ApplyRetention drops raw samples older than the store's
+// RetentionDuration, skipping any run still "running" so an in-flight
+// benchmark never loses samples out from under its own loadgen — even if
+// the run has been going on longer than the retention window.
+// Aggregated BenchmarkMetrics are never touched by retention.
+func (m *MockRepo) ApplyRetention(_ context.Context) error {
+	cutoff := time.Now().Add(-m.samples.retention)
+	for _, runID := range m.samples.runIDs() {
+		m.mu.Lock()
+		run, ok := m.runs[runID]
+		m.mu.Unlock()
+		if ok && run.Status == "running" {
+			continue
+		}
+		m.samples.dropBefore(runID, cutoff)
+	}
+	return nil
+}
+
+// SetRetention overrides the default sample retention window, for tests
+// that want ApplyRetention to take effect without waiting real days.
+func (m *MockRepo) SetRetention(d time.Duration) {
+	m.samples.mu.Lock()
+	defer m.samples.mu.Unlock()
+	m.samples.retention = d
+}
+
+// CompareRuns returns runIDA's and runIDB's raw samples, reusing the
+// same in-process store QueryCatalog's quantile_over_time draws from.
+func (m *MockRepo) CompareRuns(_ context.Context, runIDA, runIDB string) (control, treatment []Sample, err error) {
+	control, err = m.fetchSamplesForQuery(runIDA)
+	if err != nil {
+		return nil, nil, err
+	}
+	treatment, err = m.fetchSamplesForQuery(runIDB)
+	if err != nil {
+		return nil, nil, err
+	}
+	return control, treatment, nil
+}
+
+// AppendSamples inserts raw per-request samples for runID into
+// benchmark_samples, one row per Sample.
+func (r *Repository) AppendSamples(ctx context.Context, runID string, samples []Sample) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	batch := &pgx.Batch{}
+	for _, s := range samples {
+		batch.Queue(
+			`INSERT INTO benchmark_samples (run_id, ts, ttft_ms, itl_ms, tokens_out, accelerator_utilization_pct)
+			 VALUES ($1, $2, $3, $4, $5, $6)`,
+			runID, s.Timestamp, s.TTFTMs, s.ITLMs, s.TokensOut, s.AcceleratorUtilizationPct,
+		)
+	}
+	br := r.pool.SendBatch(ctx, batch)
+	defer br.Close()
+	for range samples {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("append sample: %w", err)
+		}
+	}
+	return nil
+}
+
+// QueryRange averages metric over step-sized buckets spanning [from, to),
+// using Postgres's own time_bucket-style grouping via date_bin so the
+// aggregation happens in the database rather than pulling every raw
+// sample into Go.
+func (r *Repository) QueryRange(ctx context.Context, runID, metric string, from, to time.Time, step time.Duration) ([]Point, error) {
+	ctx, cancel := r.withTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	column, ok := sampleColumn(metric)
+	if !ok {
+		return nil, fmt.Errorf("unknown metric %q", metric)
+	}
+
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(
+		`SELECT date_bin($1, ts, $2) AS bucket, AVG(%s) AS value
+		 FROM benchmark_samples
+		 WHERE run_id = $3 AND ts >= $2 AND ts < $4 AND %s IS NOT NULL
+		 GROUP BY bucket
+		 ORDER BY bucket`, column, column),
+		step, from, runID, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query range: %w", err)
+	}
+	defer rows.Close()
+
+	var points []Point
+	for rows.Next() {
+		var p Point
+		if err := rows.Scan(&p.Timestamp, &p.Value); err != nil {
+			return nil, fmt.Errorf("scan point: %w", err)
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// sampleColumn maps a QueryRange metric name to its benchmark_samples
+// column, rejecting anything not in the fixed allow-list so metric can
+// never be interpolated into the query.
+func sampleColumn(metric string) (string, bool) {
+	switch metric {
+	case "ttft_ms", "itl_ms", "tokens_out", "accelerator_utilization_pct":
+		return metric, true
+	default:
+		return "", false
+	}
+}
+
+// Compact is a no-op on Postgres: unlike an embedded TSDB's hand-rolled
+// chunk files, benchmark_samples is a normal table and its storage is
+// compacted by autovacuum, so there's nothing for the application to
+// merge by hand.
+func (r *Repository) Compact(_ context.Context) error { return nil }
+
+// ApplyRetention deletes raw samples older than defaultSampleRetention,
+// excluding any run still "running" so an in-flight benchmark never
+// loses samples out from under its own loadgen.
+func (r *Repository) ApplyRetention(ctx context.Context) error {
+	ctx, cancel := r.withTimeout(ctx, r.opts.ExecTimeout)
+	defer cancel()
+
+	cutoff := time.Now().Add(-defaultSampleRetention)
+	_, err := r.pool.Exec(ctx,
+		`DELETE FROM benchmark_samples
+		 WHERE ts < $1
+		 AND run_id NOT IN (SELECT id FROM benchmark_runs WHERE status = 'running')`,
+		cutoff,
+	)
+	if err != nil {
+		return fmt.Errorf("apply retention: %w", err)
+	}
+	return nil
+}
+
+// CompareRuns returns runIDA's and runIDB's raw samples, reusing the
+// same query QueryCatalog's quantile_over_time draws from.
+func (r *Repository) CompareRuns(ctx context.Context, runIDA, runIDB string) (control, treatment []Sample, err error) {
+	fetch := r.fetchSamplesForQuery(ctx)
+	control, err = fetch(runIDA)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load samples for %s: %w", runIDA, err)
+	}
+	treatment, err = fetch(runIDB)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load samples for %s: %w", runIDB, err)
+	}
+	return control, treatment, nil
+}
@@ -0,0 +1,211 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestAppendSamplesAndQueryRange(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{Status: "running"})
+	if err != nil || len(items) == 0 {
+		t.Fatal("no running run to append samples to")
+	}
+	runID := items[0].ID
+
+	base := time.Now().Truncate(time.Second)
+	samples := []Sample{
+		{Timestamp: base, TTFTMs: floatPtr(100), TokensOut: 10},
+		{Timestamp: base.Add(1 * time.Second), TTFTMs: floatPtr(200), TokensOut: 20},
+		{Timestamp: base.Add(2 * time.Second), TTFTMs: floatPtr(300), TokensOut: 30},
+	}
+	if err := repo.AppendSamples(ctx, runID, samples); err != nil {
+		t.Fatalf("AppendSamples: %v", err)
+	}
+
+	points, err := repo.QueryRange(ctx, runID, "ttft_ms", base, base.Add(3*time.Second), time.Second)
+	if err != nil {
+		t.Fatalf("QueryRange: %v", err)
+	}
+	if len(points) != 3 {
+		t.Fatalf("expected 3 points, got %d", len(points))
+	}
+	if points[0].Value != 100 || points[1].Value != 200 || points[2].Value != 300 {
+		t.Errorf("unexpected point values: %+v", points)
+	}
+}
+
+func TestQueryRange_BucketsAverage(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{Status: "running"})
+	if err != nil || len(items) == 0 {
+		t.Fatal("no running run to append samples to")
+	}
+	runID := items[0].ID
+
+	base := time.Now().Truncate(time.Second)
+	if err := repo.AppendSamples(ctx, runID, []Sample{
+		{Timestamp: base, TTFTMs: floatPtr(100)},
+		{Timestamp: base.Add(500 * time.Millisecond), TTFTMs: floatPtr(200)},
+	}); err != nil {
+		t.Fatalf("AppendSamples: %v", err)
+	}
+
+	points, err := repo.QueryRange(ctx, runID, "ttft_ms", base, base.Add(time.Second), time.Second)
+	if err != nil {
+		t.Fatalf("QueryRange: %v", err)
+	}
+	if len(points) != 1 {
+		t.Fatalf("expected 1 bucket, got %d", len(points))
+	}
+	if points[0].Value != 150 {
+		t.Errorf("expected averaged value 150, got %v", points[0].Value)
+	}
+}
+
+func TestAppendSamples_UnknownRun(t *testing.T) {
+	repo := NewMockRepo()
+	err := repo.AppendSamples(context.Background(), "run-nonexistent", []Sample{{Timestamp: time.Now()}})
+	if err == nil {
+		t.Error("expected error for unknown run")
+	}
+}
+
+func TestCompact_SkipsRunningRuns(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{Status: "running"})
+	if err != nil || len(items) == 0 {
+		t.Fatal("no running run")
+	}
+	runID := items[0].ID
+
+	base := time.Now()
+	for i := 0; i < maxChunkSamples+10; i++ {
+		if err := repo.AppendSamples(ctx, runID, []Sample{{Timestamp: base.Add(time.Duration(i) * time.Millisecond), TokensOut: 1}}); err != nil {
+			t.Fatalf("AppendSamples: %v", err)
+		}
+	}
+
+	if err := repo.Compact(ctx); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+
+	rs := repo.samples.byRun[runID]
+	if rs == nil {
+		t.Fatal("expected run samples to exist")
+	}
+	rs.mu.RLock()
+	chunkCount := len(rs.chunks)
+	rs.mu.RUnlock()
+	if chunkCount <= 1 {
+		t.Errorf("expected closed chunks to remain unmerged for a running run, got %d chunks", chunkCount)
+	}
+}
+
+func TestCompact_MergesClosedChunksForCompletedRun(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{Status: "completed"})
+	if err != nil || len(items) == 0 {
+		t.Fatal("no completed run")
+	}
+	runID := items[0].ID
+
+	base := time.Now()
+	for i := 0; i < maxChunkSamples+10; i++ {
+		if err := repo.AppendSamples(ctx, runID, []Sample{{Timestamp: base.Add(time.Duration(i) * time.Millisecond), TokensOut: 1}}); err != nil {
+			t.Fatalf("AppendSamples: %v", err)
+		}
+	}
+
+	if err := repo.Compact(ctx); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+
+	rs := repo.samples.byRun[runID]
+	rs.mu.RLock()
+	chunkCount := len(rs.chunks)
+	rs.mu.RUnlock()
+	if chunkCount != 1 {
+		t.Errorf("expected closed chunks to merge into 1, got %d chunks", chunkCount)
+	}
+}
+
+func TestApplyRetention_DropsOldSamplesExceptRunningRuns(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+	repo.SetRetention(time.Hour)
+
+	running, _, err := repo.ListRuns(ctx, RunFilter{Status: "running"})
+	if err != nil || len(running) == 0 {
+		t.Fatal("no running run")
+	}
+	completed, _, err := repo.ListRuns(ctx, RunFilter{Status: "completed"})
+	if err != nil || len(completed) == 0 {
+		t.Fatal("no completed run")
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, runID := range []string{running[0].ID, completed[0].ID} {
+		if err := repo.AppendSamples(ctx, runID, []Sample{{Timestamp: old, TokensOut: 1}}); err != nil {
+			t.Fatalf("AppendSamples: %v", err)
+		}
+	}
+
+	if err := repo.ApplyRetention(ctx); err != nil {
+		t.Fatalf("ApplyRetention: %v", err)
+	}
+
+	if got := len(repo.samples.all(running[0].ID)); got != 1 {
+		t.Errorf("expected running run's old sample to survive retention, got %d samples", got)
+	}
+	if got := len(repo.samples.all(completed[0].ID)); got != 0 {
+		t.Errorf("expected completed run's old sample to be dropped, got %d samples", got)
+	}
+}
+
+// TestCompactAndRetention_ConcurrentWithListRuns exercises the invariant
+// that Compact/ApplyRetention must be safe to run alongside ListRuns and
+// ListCatalog, since sampleStore and MockRepo.mu are separate locks.
+func TestCompactAndRetention_ConcurrentWithListRuns(t *testing.T) {
+	repo := seedMockRepoWithRuns(t)
+	ctx := context.Background()
+	repo.SetRetention(time.Millisecond)
+
+	items, _, err := repo.ListRuns(ctx, RunFilter{})
+	if err != nil || len(items) == 0 {
+		t.Fatal("no runs")
+	}
+	for _, item := range items {
+		_ = repo.AppendSamples(ctx, item.ID, []Sample{{Timestamp: time.Now(), TokensOut: 1}})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			_, _, _ = repo.ListRuns(ctx, RunFilter{})
+		}()
+		go func() {
+			defer wg.Done()
+			_ = repo.Compact(ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = repo.ApplyRetention(ctx)
+		}()
+	}
+	wg.Wait()
+}
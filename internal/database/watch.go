@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RunEvent is a single lifecycle or metrics update for a benchmark run,
+// tagged with a monotonic per-run revision so a disconnected watcher can
+// resume exactly where it left off by passing that revision back as
+// sinceRev (the SSE handler surfaces it as Last-Event-ID).
+type RunEvent struct {
+	RunID     string
+	Rev       uint64
+	Status    string
+	Metrics   *BenchmarkMetrics
+	LogLine   string
+	CreatedAt time.Time
+}
+
+// eventBacklog is how many past events per run a Broker retains so a
+// client reconnecting with sinceRev can replay what it missed instead of
+// only seeing events published after it resubscribes.
+const eventBacklog = 32
+
+// Broker fans RunEvents published by one goroutine (the orchestrator,
+// via Repo.UpdateRunStatus/PersistMetrics) out to any number of watchers,
+// analogous to etcd's watch stream: every event gets the next revision
+// for its run, and Watch replays buffered events after sinceRev before
+// blocking for new ones.
+type Broker struct {
+	mu   sync.Mutex
+	rev  map[string]uint64
+	log  map[string][]RunEvent
+	subs map[string][]chan RunEvent
+}
+
+// NewBroker creates an empty Broker.
+func NewBroker() *Broker {
+	return &Broker{
+		rev:  make(map[string]uint64),
+		log:  make(map[string][]RunEvent),
+		subs: make(map[string][]chan RunEvent),
+	}
+}
+
+// Publish records a new event for runID and wakes any watchers blocked on
+// it. Slow watchers never block Publish: a watcher whose channel is full
+// misses the event and must catch up via its next Watch's backlog replay.
+func (b *Broker) Publish(runID, status string, metrics *BenchmarkMetrics) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rev[runID]++
+	ev := RunEvent{
+		RunID:     runID,
+		Rev:       b.rev[runID],
+		Status:    status,
+		Metrics:   metrics,
+		CreatedAt: time.Now(),
+	}
+
+	log := append(b.log[runID], ev)
+	if len(log) > eventBacklog {
+		log = log[len(log)-eventBacklog:]
+	}
+	b.log[runID] = log
+
+	for _, ch := range b.subs[runID] {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// PublishLog records an orchestrator log line for runID as its own
+// RunEvent, interleaved in revision order with the status/metrics events
+// Publish produces, so a watcher sees log output land between the
+// lifecycle transitions that bracket it instead of as a separate stream.
+func (b *Broker) PublishLog(runID, line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rev[runID]++
+	ev := RunEvent{
+		RunID:     runID,
+		Rev:       b.rev[runID],
+		LogLine:   line,
+		CreatedAt: time.Now(),
+	}
+
+	log := append(b.log[runID], ev)
+	if len(log) > eventBacklog {
+		log = log[len(log)-eventBacklog:]
+	}
+	b.log[runID] = log
+
+	for _, ch := range b.subs[runID] {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// Watch returns a channel of events for runID with Rev > sinceRev,
+// replaying any buffered events first. The channel is closed and the
+// subscription removed once ctx is done, so callers should range over
+// the channel and stop on close rather than waiting on ctx separately.
+func (b *Broker) Watch(ctx context.Context, runID string, sinceRev uint64) (<-chan RunEvent, error) {
+	ch := make(chan RunEvent, eventBacklog)
+
+	b.mu.Lock()
+	for _, ev := range b.log[runID] {
+		if ev.Rev > sinceRev {
+			ch <- ev
+		}
+	}
+	b.subs[runID] = append(b.subs[runID], ch)
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		subs := b.subs[runID]
+		for i, c := range subs {
+			if c == ch {
+				b.subs[runID] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBroker_PublishLogInterleavesWithStatus(t *testing.T) {
+	b := NewBroker()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := b.Watch(ctx, "run-1", 0)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	b.Publish("run-1", "running", nil)
+	b.PublishLog("run-1", "starting load generator")
+	b.Publish("run-1", "completed", nil)
+
+	var got []RunEvent
+	for i := 0; i < 3; i++ {
+		got = append(got, <-events)
+	}
+
+	if got[0].Status != "running" || got[0].LogLine != "" {
+		t.Errorf("event 0 = %+v, want status=running with no log line", got[0])
+	}
+	if got[1].LogLine != "starting load generator" || got[1].Status != "" {
+		t.Errorf("event 1 = %+v, want log line with no status", got[1])
+	}
+	if got[2].Status != "completed" {
+		t.Errorf("event 2 = %+v, want status=completed", got[2])
+	}
+
+	// Revisions must stay monotonic across Publish and PublishLog alike,
+	// so a reconnecting watcher's sinceRev skips exactly what it already saw.
+	if !(got[0].Rev < got[1].Rev && got[1].Rev < got[2].Rev) {
+		t.Errorf("revisions not monotonic: %d, %d, %d", got[0].Rev, got[1].Rev, got[2].Rev)
+	}
+}
+
+func TestBroker_WatchReplaysLogBacklog(t *testing.T) {
+	b := NewBroker()
+	b.Publish("run-2", "running", nil)
+	b.PublishLog("run-2", "line one")
+	b.PublishLog("run-2", "line two")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := b.Watch(ctx, "run-2", 1)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	first := <-events
+	if first.LogLine != "line one" {
+		t.Errorf("first replayed event = %+v, want log line 'line one'", first)
+	}
+	second := <-events
+	if second.LogLine != "line two" {
+		t.Errorf("second replayed event = %+v, want log line 'line two'", second)
+	}
+}
+
+func TestMockRepo_PublishRunLogDeliversToWatch(t *testing.T) {
+	repo := NewMockRepo()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := repo.Watch(ctx, "run-3", 0)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if err := repo.PublishRunLog(ctx, "run-3", "hello from loadgen"); err != nil {
+		t.Fatalf("PublishRunLog: %v", err)
+	}
+
+	ev := <-events
+	if ev.LogLine != "hello from loadgen" {
+		t.Errorf("got log line %q, want %q", ev.LogLine, "hello from loadgen")
+	}
+}
+
+func TestMockRepo_UpsertMetrics(t *testing.T) {
+	repo := NewMockRepo()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runID, err := repo.CreateBenchmarkRun(ctx, &BenchmarkRun{ModelID: "m1", InstanceTypeID: "i1"})
+	if err != nil {
+		t.Fatalf("CreateBenchmarkRun: %v", err)
+	}
+
+	events, err := repo.Watch(ctx, runID, 0)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	ttft := 12.5
+	if err := repo.UpsertMetrics(ctx, runID, &BenchmarkMetrics{TTFTP50Ms: &ttft}); err != nil {
+		t.Fatalf("UpsertMetrics: %v", err)
+	}
+
+	ev := <-events
+	if ev.Status != "running" || ev.Metrics == nil || ev.Metrics.TTFTP50Ms == nil || *ev.Metrics.TTFTP50Ms != ttft {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+
+	run, err := repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		t.Fatalf("GetBenchmarkRun: %v", err)
+	}
+	if run.Status == "completed" {
+		t.Error("UpsertMetrics should not mark the run completed")
+	}
+
+	got, err := repo.GetMetricsByRunID(ctx, runID)
+	if err != nil {
+		t.Fatalf("GetMetricsByRunID: %v", err)
+	}
+	if got.TTFTP50Ms == nil || *got.TTFTP50Ms != ttft {
+		t.Errorf("GetMetricsByRunID = %+v, want ttft_p50 = %v", got, ttft)
+	}
+}
+
+func TestMockRepo_UpsertMetrics_UnknownRun(t *testing.T) {
+	repo := NewMockRepo()
+	err := repo.UpsertMetrics(context.Background(), "nonexistent", &BenchmarkMetrics{})
+	if err == nil {
+		t.Fatal("expected an error for an unknown run")
+	}
+}
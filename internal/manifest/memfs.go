@@ -0,0 +1,167 @@
+package manifest
+
+import (
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// memFS is a minimal read-only in-memory fs.FS built from a flat map of
+// slash-separated path -> file contents; directories are synthesized from
+// the file paths rather than stored explicitly. It exists so Render's
+// Helm/Kustomize output can be handed to callers (and walked with
+// fs.WalkDir to write it to disk) without touching the real filesystem.
+type memFS map[string][]byte
+
+func (m memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if data, ok := m[name]; ok {
+		return &memFile{name: path.Base(name), data: data}, nil
+	}
+	if entries, ok := m.readDir(name); ok {
+		return &memDir{name: path.Base(name), entries: entries}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (m memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, ok := m.readDir(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	return entries, nil
+}
+
+// readDir lists the immediate children of name, synthesizing directory
+// entries for any path component it sees along the way. ok reports whether
+// name exists at all (as a directory with at least one descendant, or as
+// the implicit root ".").
+func (m memFS) readDir(name string) (entries []fs.DirEntry, ok bool) {
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+		ok = true
+	}
+
+	seen := make(map[string]bool)
+	for p, data := range m {
+		if !strings.HasPrefix(p, prefix) {
+			continue
+		}
+		ok = true
+		rest := strings.TrimPrefix(p, prefix)
+		child, isDir := rest, false
+		if i := strings.Index(rest, "/"); i >= 0 {
+			child, isDir = rest[:i], true
+		}
+		if seen[child] {
+			continue
+		}
+		seen[child] = true
+
+		size := int64(0)
+		if !isDir {
+			size = int64(len(data))
+		}
+		entries = append(entries, dirEntry{name: child, isDir: isDir, size: size})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, ok
+}
+
+type dirEntry struct {
+	name  string
+	isDir bool
+	size  int64
+}
+
+func (d dirEntry) Name() string { return d.name }
+func (d dirEntry) IsDir() bool  { return d.isDir }
+func (d dirEntry) Type() fs.FileMode {
+	if d.isDir {
+		return fs.ModeDir
+	}
+	return 0
+}
+func (d dirEntry) Info() (fs.FileInfo, error) {
+	return fileInfo{name: d.name, size: d.size, isDir: d.isDir}, nil
+}
+
+type fileInfo struct {
+	name  string
+	size  int64
+	isDir bool
+}
+
+func (f fileInfo) Name() string { return f.name }
+func (f fileInfo) Size() int64  { return f.size }
+func (f fileInfo) Mode() fs.FileMode {
+	if f.isDir {
+		return fs.ModeDir | 0o755
+	}
+	return 0o644
+}
+func (f fileInfo) ModTime() time.Time { return time.Time{} }
+func (f fileInfo) IsDir() bool        { return f.isDir }
+func (f fileInfo) Sys() any           { return nil }
+
+type memFile struct {
+	name   string
+	data   []byte
+	offset int
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) {
+	return fileInfo{name: f.name, size: int64(len(f.data))}, nil
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	if f.offset >= len(f.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[f.offset:])
+	f.offset += n
+	return n, nil
+}
+
+func (f *memFile) Close() error { return nil }
+
+type memDir struct {
+	name    string
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) {
+	return fileInfo{name: d.name, isDir: true}, nil
+}
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: fs.ErrInvalid}
+}
+
+func (d *memDir) Close() error { return nil }
+
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		rest := d.entries[d.offset:]
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if d.offset >= len(d.entries) {
+		return nil, io.EOF
+	}
+	end := d.offset + n
+	if end > len(d.entries) {
+		end = len(d.entries)
+	}
+	rest := d.entries[d.offset:end]
+	d.offset = end
+	return rest, nil
+}
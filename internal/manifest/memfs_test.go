@@ -0,0 +1,54 @@
+package manifest
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMemFS_SatisfiesFSContract(t *testing.T) {
+	m := memFS{
+		"Chart.yaml":           []byte("apiVersion: v2\n"),
+		"values.yaml":          []byte("name: test\n"),
+		"templates/bench.yaml": []byte("kind: Deployment\n"),
+	}
+
+	if err := fstest.TestFS(m, "Chart.yaml", "values.yaml", "templates/bench.yaml"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemFS_ReadDir(t *testing.T) {
+	m := memFS{
+		"base/kustomization.yaml":              []byte("a"),
+		"base/bench.yaml":                      []byte("b"),
+		"overlays/vllm-gpu/kustomization.yaml": []byte("c"),
+	}
+
+	root, err := m.ReadDir(".")
+	if err != nil {
+		t.Fatalf("ReadDir(.): %v", err)
+	}
+	var names []string
+	for _, e := range root {
+		names = append(names, e.Name())
+	}
+	if len(names) != 2 || names[0] != "base" || names[1] != "overlays" {
+		t.Errorf("ReadDir(.) = %v, want [base overlays]", names)
+	}
+
+	base, err := m.ReadDir("base")
+	if err != nil {
+		t.Fatalf("ReadDir(base): %v", err)
+	}
+	if len(base) != 2 {
+		t.Errorf("ReadDir(base) returned %d entries, want 2", len(base))
+	}
+
+	if _, err := m.ReadDir("nope"); err == nil {
+		t.Error("ReadDir(nope) = nil error, want fs.ErrNotExist")
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadDir(nope) error = %v, want fs.ErrNotExist", err)
+	}
+}
@@ -4,7 +4,12 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
+	"io/fs"
 	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/accelbench/accelbench/internal/database"
 )
 
 //go:embed templates/*.yaml.tmpl
@@ -40,6 +45,8 @@ type ModelDeploymentParams struct {
 	MaxModelLen          int    // 0 = auto-detect from model config
 	CPURequest           string
 	MemoryRequest        string
+	Plugins              []database.FrameworkPlugin // dynamic framework/custom-op plugins to load into the model container
+	Replicas             int                        // Deployment's initial replica count; 0 renders as 1. Only orchestrator.ExecuteAutoscale sets this above 1.
 }
 
 // LoadgenJobParams holds values for rendering the load generator Job.
@@ -57,6 +64,11 @@ type LoadgenJobParams struct {
 	NumRequests          int
 	WarmupRequests       int
 	MinDurationSeconds   int
+
+	// DatasetSpec overrides DatasetName with a richer workload description
+	// (code generation, needle-in-a-haystack, synthetic, or replay); nil
+	// renders the same plain-sharegpt args as before DatasetSpec existed.
+	DatasetSpec *database.DatasetSpec
 }
 
 // RenderModelDeployment renders the model Deployment + Service manifests.
@@ -64,7 +76,11 @@ func RenderModelDeployment(params ModelDeploymentParams) (string, error) {
 	return renderTemplate("model-deployment.yaml.tmpl", params)
 }
 
-// RenderLoadgenJob renders the load generator Job manifest.
+// RenderLoadgenJob renders the load generator Job manifest. When params.
+// DatasetSpec is set, the template translates it into the generator-
+// specific args and env vars the loadgen image expects (DATASET_SOURCE_URI,
+// SAMPLING_POLICY/SAMPLING_SEED, NEEDLE_DEPTHS, EVALUATION_HOOK_IMAGE)
+// instead of the plain DATASET_NAME env var a nil DatasetSpec renders.
 func RenderLoadgenJob(params LoadgenJobParams) (string, error) {
 	return renderTemplate("loadgen-job.yaml.tmpl", params)
 }
@@ -76,3 +92,120 @@ func renderTemplate(name string, data any) (string, error) {
 	}
 	return buf.String(), nil
 }
+
+// RenderTarget selects the layout the *FS render functions produce.
+type RenderTarget string
+
+const (
+	// TargetYAML is the plain concatenated manifest RenderModelDeployment/
+	// RenderLoadgenJob already return, wrapped in a single-file fs.FS.
+	TargetYAML RenderTarget = "yaml"
+	// TargetHelm is a Helm chart directory: Chart.yaml, a values.yaml
+	// generated from the params struct, and templates/ holding the
+	// rendered manifest.
+	TargetHelm RenderTarget = "helm"
+	// TargetKustomize is a kustomize base (the rendered manifest) plus a
+	// single overlay keyed by framework and accelerator type.
+	TargetKustomize RenderTarget = "kustomize"
+)
+
+// RenderModelDeploymentFS renders the model Deployment + Service as
+// target's layout and returns an in-memory filesystem, so callers can write
+// it to disk (e.g. `accelbench manifest export`) or read it directly
+// without a temp directory.
+func RenderModelDeploymentFS(params ModelDeploymentParams, target RenderTarget) (fs.FS, error) {
+	yamlStr, err := RenderModelDeployment(params)
+	if err != nil {
+		return nil, err
+	}
+	return renderFS(params.Name, yamlStr, params, params.Framework, params.AcceleratorType, target)
+}
+
+// RenderLoadgenJobFS is RenderModelDeploymentFS for the load generator Job.
+func RenderLoadgenJobFS(params LoadgenJobParams, target RenderTarget) (fs.FS, error) {
+	yamlStr, err := RenderLoadgenJob(params)
+	if err != nil {
+		return nil, err
+	}
+	return renderFS(params.Name, yamlStr, params, "", "", target)
+}
+
+// renderFS lays out yamlStr (the already-rendered manifest for name) and
+// params (for the Helm values.yaml) as target's directory structure.
+func renderFS(name, yamlStr string, params any, framework, acceleratorType string, target RenderTarget) (fs.FS, error) {
+	switch target {
+	case TargetYAML, "":
+		return memFS{name + ".yaml": []byte(yamlStr)}, nil
+	case TargetHelm:
+		return helmChartFS(name, yamlStr, params)
+	case TargetKustomize:
+		return kustomizeFS(name, yamlStr, framework, acceleratorType)
+	default:
+		return nil, fmt.Errorf("render: unknown target %q", target)
+	}
+}
+
+// helmChartFS builds a minimal, directly `helm install`-able chart: a
+// static Chart.yaml, a values.yaml marshaled from params (so the knobs used
+// to render this manifest are visible and diffable alongside it), and
+// templates/ holding the rendered manifest as-is. templates/ isn't yet
+// wired to read back from .Values — that's a natural follow-up once a
+// specific chart needs per-environment overrides at `helm upgrade` time.
+func helmChartFS(name, yamlStr string, params any) (fs.FS, error) {
+	values, err := yaml.Marshal(redactHelmValues(params))
+	if err != nil {
+		return nil, fmt.Errorf("marshal values.yaml: %w", err)
+	}
+
+	chart := fmt.Sprintf(
+		"apiVersion: v2\nname: %s\ndescription: AccelBench-generated chart for %s\ntype: application\nversion: 0.1.0\nappVersion: \"1.0.0\"\n",
+		name, name,
+	)
+
+	return memFS{
+		"Chart.yaml":                  []byte(chart),
+		"values.yaml":                 values,
+		"templates/" + name + ".yaml": []byte(yamlStr),
+	}, nil
+}
+
+// redactHelmValues clears ModelDeploymentParams.HfToken before it's
+// marshaled into values.yaml, so a chart meant to be checked into GitOps
+// version control never carries a caller's Hugging Face credential (the
+// same precaution the API takes before echoing a run back, see redactRun
+// in internal/api/handlers.go). LoadgenJobParams carries no credential, so
+// it passes through unchanged.
+func redactHelmValues(params any) any {
+	p, ok := params.(ModelDeploymentParams)
+	if !ok || p.HfToken == "" {
+		return params
+	}
+	p.HfToken = ""
+	return p
+}
+
+// kustomizeFS builds a kustomize base (the rendered manifest as its only
+// resource) plus one overlay named after framework and acceleratorType
+// (e.g. "vllm-gpu"), so a rig's kustomize tree accumulates one overlay per
+// framework/accelerator combination instead of duplicating the base.
+func kustomizeFS(name, yamlStr, framework, acceleratorType string) (fs.FS, error) {
+	overlay := framework
+	if acceleratorType != "" {
+		if overlay != "" {
+			overlay += "-"
+		}
+		overlay += acceleratorType
+	}
+	if overlay == "" {
+		overlay = "default"
+	}
+
+	base := "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n  - " + name + ".yaml\n"
+	ov := "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n  - ../../base\n"
+
+	return memFS{
+		"base/kustomization.yaml":                     []byte(base),
+		"base/" + name + ".yaml":                      []byte(yamlStr),
+		"overlays/" + overlay + "/kustomization.yaml": []byte(ov),
+	}, nil
+}
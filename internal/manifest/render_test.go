@@ -1,6 +1,7 @@
 package manifest
 
 import (
+	"io/fs"
 	"strings"
 	"testing"
 )
@@ -219,3 +220,87 @@ func TestRenderModelDeployment_MultiDocument(t *testing.T) {
 		t.Error("output missing YAML document separator")
 	}
 }
+
+func modelDeploymentTestParams() ModelDeploymentParams {
+	return ModelDeploymentParams{
+		Name:                 "bench-helmtest",
+		Namespace:            "accelbench",
+		ModelHfID:            "meta-llama/Llama-3.1-8B-Instruct",
+		Framework:            "vllm",
+		FrameworkVersion:     "v0.6.0",
+		TensorParallelDegree: 1,
+		AcceleratorType:      "gpu",
+		AcceleratorCount:     1,
+		InstanceTypeName:     "g5.xlarge",
+		InstanceFamily:       "g5",
+		CPURequest:           "4",
+		MemoryRequest:        "16Gi",
+	}
+}
+
+func TestRenderModelDeploymentFS_YAML(t *testing.T) {
+	fsys, err := RenderModelDeploymentFS(modelDeploymentTestParams(), TargetYAML)
+	if err != nil {
+		t.Fatalf("RenderModelDeploymentFS: %v", err)
+	}
+
+	data, err := fs.ReadFile(fsys, "bench-helmtest.yaml")
+	if err != nil {
+		t.Fatalf("read rendered yaml: %v", err)
+	}
+	if !strings.Contains(string(data), "kind: Deployment") {
+		t.Error("rendered yaml missing Deployment")
+	}
+}
+
+func TestRenderModelDeploymentFS_Helm(t *testing.T) {
+	fsys, err := RenderModelDeploymentFS(modelDeploymentTestParams(), TargetHelm)
+	if err != nil {
+		t.Fatalf("RenderModelDeploymentFS: %v", err)
+	}
+
+	for _, name := range []string{"Chart.yaml", "values.yaml", "templates/bench-helmtest.yaml"} {
+		if _, err := fs.Stat(fsys, name); err != nil {
+			t.Errorf("Helm chart missing %s: %v", name, err)
+		}
+	}
+
+	values, err := fs.ReadFile(fsys, "values.yaml")
+	if err != nil {
+		t.Fatalf("read values.yaml: %v", err)
+	}
+	if !strings.Contains(string(values), "meta-llama/Llama-3.1-8B-Instruct") {
+		t.Error("values.yaml does not reflect ModelDeploymentParams")
+	}
+}
+
+func TestRenderModelDeploymentFS_Helm_RedactsHfToken(t *testing.T) {
+	params := modelDeploymentTestParams()
+	params.HfToken = "hf_secret_token"
+
+	fsys, err := RenderModelDeploymentFS(params, TargetHelm)
+	if err != nil {
+		t.Fatalf("RenderModelDeploymentFS: %v", err)
+	}
+
+	values, err := fs.ReadFile(fsys, "values.yaml")
+	if err != nil {
+		t.Fatalf("read values.yaml: %v", err)
+	}
+	if strings.Contains(string(values), "hf_secret_token") {
+		t.Error("values.yaml must not contain HfToken")
+	}
+}
+
+func TestRenderModelDeploymentFS_Kustomize(t *testing.T) {
+	fsys, err := RenderModelDeploymentFS(modelDeploymentTestParams(), TargetKustomize)
+	if err != nil {
+		t.Fatalf("RenderModelDeploymentFS: %v", err)
+	}
+
+	for _, name := range []string{"base/kustomization.yaml", "base/bench-helmtest.yaml", "overlays/vllm-gpu/kustomization.yaml"} {
+		if _, err := fs.Stat(fsys, name); err != nil {
+			t.Errorf("kustomize tree missing %s: %v", name, err)
+		}
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"time"
 
 	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/pricing"
 )
 
 // LoadgenOutput represents the JSON output from the load generator.
@@ -29,16 +31,20 @@ type RequestResult struct {
 
 // Summary holds aggregate metrics from the load generator.
 type Summary struct {
-	TotalDurationSeconds     float64 `json:"total_duration_seconds"`
-	TotalRequests            int     `json:"total_requests"`
-	SuccessfulRequests       int     `json:"successful_requests"`
-	FailedRequests           int     `json:"failed_requests"`
-	ThroughputAggregateTPS   float64 `json:"throughput_aggregate_tps"`
-	RequestsPerSecond        float64 `json:"requests_per_second"`
+	TotalDurationSeconds      float64  `json:"total_duration_seconds"`
+	TotalRequests             int      `json:"total_requests"`
+	SuccessfulRequests        int      `json:"successful_requests"`
+	FailedRequests            int      `json:"failed_requests"`
+	ThroughputAggregateTPS    float64  `json:"throughput_aggregate_tps"`
+	RequestsPerSecond         float64  `json:"requests_per_second"`
 	AcceleratorUtilizationPct *float64 `json:"accelerator_utilization_pct,omitempty"`
-	AcceleratorMemoryPeakGiB *float64 `json:"accelerator_memory_peak_gib,omitempty"`
+	AcceleratorMemoryPeakGiB  *float64 `json:"accelerator_memory_peak_gib,omitempty"`
 }
 
+// secondsPerHour converts Rate.OnDemandHourlyUSD into a per-token cost
+// alongside ThroughputAggregateTPS, mirroring internal/pricing.Derive's math.
+const secondsPerHour = 3600
+
 // ParseLoadgenOutput parses the JSON output from a load generator pod.
 // Pod logs may contain non-JSON progress lines on stderr; this function
 // first looks for content between ACCELBENCH_JSON_BEGIN/END markers,
@@ -77,9 +83,24 @@ func ParseLoadgenOutput(data []byte) (*LoadgenOutput, error) {
 	return nil, fmt.Errorf("parse loadgen output: no valid JSON payload found in %d bytes of log output", len(data))
 }
 
+// PricingContext is the on-demand rate in effect for a run's instance type
+// at the moment it completed, used by ComputeMetrics to stamp a cost
+// efficiency snapshot onto the persisted BenchmarkMetrics row. This is
+// distinct from the `pricing` CLI package's Derive, which recomputes the
+// same kind of figures against whatever rate is current *at query time* —
+// PricingContext captures what the rate actually was when the run ran, so
+// the two can later be compared (see CatalogEntry.CostDeltaPct).
+type PricingContext struct {
+	Rate       *pricing.Rate
+	Source     string
+	CapturedAt time.Time
+}
+
 // ComputeMetrics takes parsed loadgen output and computes the full set of
-// benchmark metrics including p50/p90/p95/p99 percentiles.
-func ComputeMetrics(out *LoadgenOutput) *database.BenchmarkMetrics {
+// benchmark metrics including p50/p90/p95/p99 percentiles. pc is optional;
+// pass nil when no pricing provider is configured or it has no rate for
+// this run's instance type, and the cost columns are left nil.
+func ComputeMetrics(out *LoadgenOutput, pc *PricingContext) *database.BenchmarkMetrics {
 	successful := filterSuccessful(out.Requests)
 
 	var ttfts, e2es, itls []float64
@@ -112,27 +133,43 @@ func ComputeMetrics(out *LoadgenOutput) *database.BenchmarkMetrics {
 	successCount := out.Summary.SuccessfulRequests
 	failCount := out.Summary.FailedRequests
 
+	var costPerMToken *float64
+	var pricingSource *string
+	var pricingCapturedAt *time.Time
+	if pc != nil && pc.Rate != nil && out.Summary.ThroughputAggregateTPS > 0 {
+		tokensPerHour := out.Summary.ThroughputAggregateTPS * secondsPerHour
+		cost := pc.Rate.OnDemandHourlyUSD / tokensPerHour * 1e6
+		costPerMToken = &cost
+		source := pc.Source
+		pricingSource = &source
+		capturedAt := pc.CapturedAt
+		pricingCapturedAt = &capturedAt
+	}
+
 	return &database.BenchmarkMetrics{
-		TTFTP50Ms:                ttftP50,
-		TTFTP90Ms:                ttftP90,
-		TTFTP95Ms:                ttftP95,
-		TTFTP99Ms:                ttftP99,
-		E2ELatencyP50Ms:          e2eP50,
-		E2ELatencyP90Ms:          e2eP90,
-		E2ELatencyP95Ms:          e2eP95,
-		E2ELatencyP99Ms:          e2eP99,
-		ITLP50Ms:                 itlP50,
-		ITLP90Ms:                 itlP90,
-		ITLP95Ms:                 itlP95,
-		ITLP99Ms:                 itlP99,
-		ThroughputPerRequestTPS:  throughputPerRequest,
-		ThroughputAggregateTPS:   aggTPS,
-		RequestsPerSecond:        rps,
+		TTFTP50Ms:                 ttftP50,
+		TTFTP90Ms:                 ttftP90,
+		TTFTP95Ms:                 ttftP95,
+		TTFTP99Ms:                 ttftP99,
+		E2ELatencyP50Ms:           e2eP50,
+		E2ELatencyP90Ms:           e2eP90,
+		E2ELatencyP95Ms:           e2eP95,
+		E2ELatencyP99Ms:           e2eP99,
+		ITLP50Ms:                  itlP50,
+		ITLP90Ms:                  itlP90,
+		ITLP95Ms:                  itlP95,
+		ITLP99Ms:                  itlP99,
+		ThroughputPerRequestTPS:   throughputPerRequest,
+		ThroughputAggregateTPS:    aggTPS,
+		RequestsPerSecond:         rps,
 		AcceleratorUtilizationPct: out.Summary.AcceleratorUtilizationPct,
-		AcceleratorMemoryPeakGiB: out.Summary.AcceleratorMemoryPeakGiB,
-		SuccessfulRequests:       &successCount,
-		FailedRequests:           &failCount,
-		TotalDurationSeconds:     dur,
+		AcceleratorMemoryPeakGiB:  out.Summary.AcceleratorMemoryPeakGiB,
+		SuccessfulRequests:        &successCount,
+		FailedRequests:            &failCount,
+		TotalDurationSeconds:      dur,
+		CostPerMillionTokensUSD:   costPerMToken,
+		PricingSource:             pricingSource,
+		PricingCapturedAt:         pricingCapturedAt,
 	}
 }
 
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"math"
 	"testing"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/pricing"
 )
 
 func TestPercentile_Empty(t *testing.T) {
@@ -175,7 +178,7 @@ func TestComputeMetrics(t *testing.T) {
 		},
 	}
 
-	m := ComputeMetrics(out)
+	m := ComputeMetrics(out, nil)
 
 	// Should only use 3 successful requests.
 	if m.SuccessfulRequests == nil || *m.SuccessfulRequests != 3 {
@@ -225,7 +228,7 @@ func TestComputeMetrics_AllFailed(t *testing.T) {
 		},
 	}
 
-	m := ComputeMetrics(out)
+	m := ComputeMetrics(out, nil)
 
 	if m.TTFTP50Ms != nil {
 		t.Error("ttft_p50 should be nil when all requests failed")
@@ -241,8 +244,60 @@ func TestComputeMetrics_Empty(t *testing.T) {
 		Summary:  Summary{},
 	}
 
-	m := ComputeMetrics(out)
+	m := ComputeMetrics(out, nil)
 	if m.TTFTP50Ms != nil {
 		t.Error("expected nil percentiles for empty requests")
 	}
 }
+
+func TestComputeMetrics_PricingContext(t *testing.T) {
+	out := &LoadgenOutput{
+		Requests: []RequestResult{
+			{TTFTMs: 10, E2ELatencyMs: 100, ITLMs: 5, OutputTokens: 50, DurationSeconds: 1.0, Success: true},
+		},
+		Summary: Summary{
+			TotalDurationSeconds:   10.0,
+			TotalRequests:          1,
+			SuccessfulRequests:     1,
+			ThroughputAggregateTPS: 100.0,
+		},
+	}
+	capturedAt := time.Unix(1700000000, 0)
+	pc := &PricingContext{
+		Rate:       &pricing.Rate{InstanceTypeName: "p5.48xlarge", OnDemandHourlyUSD: 36.0},
+		Source:     "static",
+		CapturedAt: capturedAt,
+	}
+
+	m := ComputeMetrics(out, pc)
+
+	// cost/Mtok = ($36/hr / (100 tok/s * 3600 s/hr)) * 1e6 = 100.0
+	if m.CostPerMillionTokensUSD == nil {
+		t.Fatal("cost_per_million_tokens_usd is nil")
+	}
+	if math.Abs(*m.CostPerMillionTokensUSD-100.0) > 0.01 {
+		t.Errorf("cost_per_million_tokens_usd = %f, want 100.0", *m.CostPerMillionTokensUSD)
+	}
+	if m.PricingSource == nil || *m.PricingSource != "static" {
+		t.Errorf("pricing_source = %v, want \"static\"", m.PricingSource)
+	}
+	if m.PricingCapturedAt == nil || !m.PricingCapturedAt.Equal(capturedAt) {
+		t.Errorf("pricing_captured_at = %v, want %v", m.PricingCapturedAt, capturedAt)
+	}
+}
+
+func TestComputeMetrics_NoPricingContext(t *testing.T) {
+	out := &LoadgenOutput{
+		Requests: []RequestResult{{Success: true, OutputTokens: 10, DurationSeconds: 1.0}},
+		Summary:  Summary{ThroughputAggregateTPS: 10.0},
+	}
+
+	m := ComputeMetrics(out, nil)
+
+	if m.CostPerMillionTokensUSD != nil {
+		t.Error("cost_per_million_tokens_usd should be nil without a PricingContext")
+	}
+	if m.PricingSource != nil {
+		t.Error("pricing_source should be nil without a PricingContext")
+	}
+}
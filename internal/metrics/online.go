@@ -0,0 +1,132 @@
+package metrics
+
+import "github.com/accelbench/accelbench/internal/database"
+
+// quantiles are the percentile markers tracked for every latency metric,
+// matching ComputeMetrics' p50/p90/p95/p99.
+var quantiles = [4]float64{0.5, 0.9, 0.95, 0.99}
+
+// OnlineAggregator is the streaming counterpart to ComputeMetrics: it
+// folds in one RequestResult at a time via P² quantile estimators instead
+// of buffering every request, so the controller can persist progressively
+// accurate metrics via UpsertMetrics while a multi-hour run is still in
+// flight.
+type OnlineAggregator struct {
+	ttft [4]*P2Estimator
+	e2e  [4]*P2Estimator
+	itl  [4]*P2Estimator
+
+	successCount, failCount int
+	totalOutputTokens       int
+	totalDurationSeconds    float64
+}
+
+// NewOnlineAggregator returns an aggregator ready to accept RequestResults.
+func NewOnlineAggregator() *OnlineAggregator {
+	return &OnlineAggregator{
+		ttft: newQuantileSet(),
+		e2e:  newQuantileSet(),
+		itl:  newQuantileSet(),
+	}
+}
+
+func newQuantileSet() [4]*P2Estimator {
+	var set [4]*P2Estimator
+	for i, p := range quantiles {
+		set[i] = NewP2Estimator(p)
+	}
+	return set
+}
+
+// Add folds r into the running estimate. Failed requests only move the
+// failure count, matching ComputeMetrics' filterSuccessful.
+func (a *OnlineAggregator) Add(r RequestResult) {
+	if !r.Success {
+		a.failCount++
+		return
+	}
+	a.successCount++
+	a.totalOutputTokens += r.OutputTokens
+	a.totalDurationSeconds += r.DurationSeconds
+	for _, e := range a.ttft {
+		e.Add(r.TTFTMs)
+	}
+	for _, e := range a.e2e {
+		e.Add(r.E2ELatencyMs)
+	}
+	for _, e := range a.itl {
+		e.Add(r.ITLMs)
+	}
+}
+
+// Snapshot returns the metrics derivable from requests seen so far:
+// latency percentiles and per-request throughput. Aggregate
+// throughput/RPS/utilization only become available from the run's final
+// Summary line, so callers persisting a progressive snapshot via
+// UpsertMetrics should expect those fields to stay nil until Finalize.
+func (a *OnlineAggregator) Snapshot() *database.BenchmarkMetrics {
+	successCount, failCount := a.successCount, a.failCount
+	m := &database.BenchmarkMetrics{
+		TTFTP50Ms:          value(a.ttft[0]),
+		TTFTP90Ms:          value(a.ttft[1]),
+		TTFTP95Ms:          value(a.ttft[2]),
+		TTFTP99Ms:          value(a.ttft[3]),
+		E2ELatencyP50Ms:    value(a.e2e[0]),
+		E2ELatencyP90Ms:    value(a.e2e[1]),
+		E2ELatencyP95Ms:    value(a.e2e[2]),
+		E2ELatencyP99Ms:    value(a.e2e[3]),
+		ITLP50Ms:           value(a.itl[0]),
+		ITLP90Ms:           value(a.itl[1]),
+		ITLP95Ms:           value(a.itl[2]),
+		ITLP99Ms:           value(a.itl[3]),
+		SuccessfulRequests: &successCount,
+		FailedRequests:     &failCount,
+	}
+	if a.successCount > 0 && a.totalDurationSeconds > 0 {
+		avgTokens := float64(a.totalOutputTokens) / float64(a.successCount)
+		avgDur := a.totalDurationSeconds / float64(a.successCount)
+		throughput := avgTokens / avgDur
+		m.ThroughputPerRequestTPS = &throughput
+	}
+	return m
+}
+
+// Finalize merges the run's final Summary (aggregate throughput, RPS,
+// accelerator utilization) into Snapshot's percentile estimates, producing
+// the same shape of *database.BenchmarkMetrics as ComputeMetrics.
+func (a *OnlineAggregator) Finalize(summary Summary) *database.BenchmarkMetrics {
+	m := a.Snapshot()
+	aggTPS := summary.ThroughputAggregateTPS
+	rps := summary.RequestsPerSecond
+	dur := summary.TotalDurationSeconds
+	successCount := summary.SuccessfulRequests
+	failCount := summary.FailedRequests
+
+	m.ThroughputAggregateTPS = &aggTPS
+	m.RequestsPerSecond = &rps
+	m.TotalDurationSeconds = &dur
+	m.AcceleratorUtilizationPct = summary.AcceleratorUtilizationPct
+	m.AcceleratorMemoryPeakGiB = summary.AcceleratorMemoryPeakGiB
+	m.SuccessfulRequests = &successCount
+	m.FailedRequests = &failCount
+	return m
+}
+
+// value returns e's current estimate, or nil if e has never seen an
+// observation, so Snapshot omits percentiles for metrics no run reports.
+func value(e *P2Estimator) *float64 {
+	if e.successCount() == 0 {
+		return nil
+	}
+	v := e.Value()
+	return &v
+}
+
+// successCount reports how many observations e has folded in, so value
+// can tell "never observed" apart from a genuine zero estimate.
+func (e *P2Estimator) successCount() int {
+	if len(e.initial) < 5 {
+		return len(e.initial)
+	}
+	return e.n[4]
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import "testing"
+
+func TestOnlineAggregator_SnapshotOmitsUnseenMetrics(t *testing.T) {
+	a := NewOnlineAggregator()
+	snap := a.Snapshot()
+	if snap.TTFTP50Ms != nil {
+		t.Error("TTFTP50Ms should be nil before any observation")
+	}
+	if *snap.SuccessfulRequests != 0 || *snap.FailedRequests != 0 {
+		t.Error("expected zero success/fail counts before any observation")
+	}
+}
+
+func TestOnlineAggregator_TracksSuccessAndFailureCounts(t *testing.T) {
+	a := NewOnlineAggregator()
+	a.Add(RequestResult{Success: true, TTFTMs: 10, E2ELatencyMs: 100, ITLMs: 5, OutputTokens: 50, DurationSeconds: 1})
+	a.Add(RequestResult{Success: true, TTFTMs: 20, E2ELatencyMs: 200, ITLMs: 6, OutputTokens: 100, DurationSeconds: 2})
+	a.Add(RequestResult{Success: false})
+
+	snap := a.Snapshot()
+	if *snap.SuccessfulRequests != 2 {
+		t.Errorf("SuccessfulRequests = %d, want 2", *snap.SuccessfulRequests)
+	}
+	if *snap.FailedRequests != 1 {
+		t.Errorf("FailedRequests = %d, want 1", *snap.FailedRequests)
+	}
+	if snap.TTFTP50Ms == nil {
+		t.Fatal("TTFTP50Ms should be populated after successful observations")
+	}
+	if snap.ThroughputPerRequestTPS == nil {
+		t.Fatal("ThroughputPerRequestTPS should be populated after successful observations")
+	}
+}
+
+func TestOnlineAggregator_FinalizeMergesSummary(t *testing.T) {
+	a := NewOnlineAggregator()
+	a.Add(RequestResult{Success: true, TTFTMs: 10, E2ELatencyMs: 100, ITLMs: 5, OutputTokens: 50, DurationSeconds: 1})
+
+	util := 85.5
+	summary := Summary{
+		TotalDurationSeconds:      60,
+		SuccessfulRequests:        1,
+		FailedRequests:            0,
+		ThroughputAggregateTPS:    123.4,
+		RequestsPerSecond:         5.6,
+		AcceleratorUtilizationPct: &util,
+	}
+	m := a.Finalize(summary)
+
+	if *m.ThroughputAggregateTPS != 123.4 {
+		t.Errorf("ThroughputAggregateTPS = %f, want 123.4", *m.ThroughputAggregateTPS)
+	}
+	if *m.RequestsPerSecond != 5.6 {
+		t.Errorf("RequestsPerSecond = %f, want 5.6", *m.RequestsPerSecond)
+	}
+	if m.AcceleratorUtilizationPct == nil || *m.AcceleratorUtilizationPct != util {
+		t.Error("AcceleratorUtilizationPct not carried over from Summary")
+	}
+	if m.TTFTP50Ms == nil {
+		t.Error("Finalize should retain percentile estimates from Snapshot")
+	}
+}
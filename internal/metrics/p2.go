@@ -0,0 +1,126 @@
+package metrics
+
+import "sort"
+
+// P2Estimator estimates a single quantile of a streamed sequence of
+// observations in constant memory and one pass, using the P² algorithm
+// (Jain & Chlamtac, 1985). It's the quantile primitive behind
+// OnlineAggregator: ParseLoadgenStream can hand it millions of samples
+// from a multi-hour run without ever buffering them.
+type P2Estimator struct {
+	p float64
+
+	// initial buffers the first 5 observations so the 5 markers can be
+	// seeded from a sorted sample; nil once seeded.
+	initial []float64
+
+	n    [5]int     // marker positions (observation counts)
+	npos [5]float64 // desired marker positions
+	dn   [5]float64 // desired position increment per observation
+	q    [5]float64 // marker heights (the estimate lives in q[2])
+}
+
+// NewP2Estimator returns an estimator for the p-quantile (e.g. 0.5 for the
+// median), ready to accept observations via Add.
+func NewP2Estimator(p float64) *P2Estimator {
+	return &P2Estimator{p: p, initial: make([]float64, 0, 5)}
+}
+
+// Add folds x into the estimate.
+func (e *P2Estimator) Add(x float64) {
+	if len(e.initial) < 5 {
+		e.initial = append(e.initial, x)
+		if len(e.initial) == 5 {
+			e.seed()
+		}
+		return
+	}
+
+	k := e.cell(x)
+
+	for i := k + 1; i < 5; i++ {
+		e.n[i]++
+	}
+	for i := 0; i < 5; i++ {
+		e.npos[i] += e.dn[i]
+	}
+
+	for i := 1; i < 4; i++ {
+		d := e.npos[i] - float64(e.n[i])
+		if (d >= 1 && e.n[i+1]-e.n[i] > 1) || (d <= -1 && e.n[i-1]-e.n[i] < -1) {
+			sign := 1.0
+			if d < 0 {
+				sign = -1.0
+			}
+			if adjusted := e.parabolic(i, sign); e.q[i-1] < adjusted && adjusted < e.q[i+1] {
+				e.q[i] = adjusted
+			} else {
+				e.q[i] = e.linear(i, sign)
+			}
+			e.n[i] += int(sign)
+		}
+	}
+}
+
+// seed initializes the 5 markers from the first 5 (sorted) observations,
+// once enough have arrived to do so.
+func (e *P2Estimator) seed() {
+	sorted := append([]float64(nil), e.initial...)
+	sort.Float64s(sorted)
+	for i := 0; i < 5; i++ {
+		e.q[i] = sorted[i]
+		e.n[i] = i + 1
+	}
+	e.npos = [5]float64{1, 1 + 2*e.p, 1 + 4*e.p, 3 + 2*e.p, 5}
+	e.dn = [5]float64{0, e.p / 2, e.p, (1 + e.p) / 2, 1}
+}
+
+// cell locates which of the 4 intervals x falls into, extending the outer
+// markers if x is a new extreme, and returns the interval's lower index.
+func (e *P2Estimator) cell(x float64) int {
+	switch {
+	case x < e.q[0]:
+		e.q[0] = x
+		return 0
+	case x >= e.q[4]:
+		e.q[4] = x
+		return 3
+	default:
+		for i := 0; i < 4; i++ {
+			if e.q[i] <= x && x < e.q[i+1] {
+				return i
+			}
+		}
+		return 3
+	}
+}
+
+// parabolic predicts marker i's new height via the piecewise-parabolic
+// formula, moving it by d (+1 or -1) positions.
+func (e *P2Estimator) parabolic(i int, d float64) float64 {
+	qi, qim1, qip1 := e.q[i], e.q[i-1], e.q[i+1]
+	ni, nim1, nip1 := float64(e.n[i]), float64(e.n[i-1]), float64(e.n[i+1])
+	return qi + d/(nip1-nim1)*((ni-nim1+d)*(qip1-qi)/(nip1-ni)+
+		(nip1-ni-d)*(qi-qim1)/(ni-nim1))
+}
+
+// linear is the fallback used when the parabolic prediction would break
+// monotonicity between neighboring markers.
+func (e *P2Estimator) linear(i int, d float64) float64 {
+	j := i + int(d)
+	return e.q[i] + d*(e.q[j]-e.q[i])/(float64(e.n[j])-float64(e.n[i]))
+}
+
+// Value returns the current quantile estimate. Before 5 observations have
+// arrived it falls back to an exact percentile of the buffered sample.
+func (e *P2Estimator) Value() float64 {
+	if len(e.initial) < 5 {
+		if len(e.initial) == 0 {
+			return 0
+		}
+		sorted := append([]float64(nil), e.initial...)
+		sort.Float64s(sorted)
+		return percentile(sorted, e.p*100)
+	}
+	return e.q[2]
+}
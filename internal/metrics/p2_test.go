@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestP2Estimator_ApproximatesKnownPercentiles(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const n = 20000
+	vals := make([]float64, n)
+
+	est50 := NewP2Estimator(0.5)
+	est90 := NewP2Estimator(0.9)
+	est99 := NewP2Estimator(0.99)
+	for i := range vals {
+		v := r.ExpFloat64() * 100
+		vals[i] = v
+		est50.Add(v)
+		est90.Add(v)
+		est99.Add(v)
+	}
+
+	tests := []struct {
+		name string
+		est  *P2Estimator
+		p    float64
+	}{
+		{"p50", est50, 50},
+		{"p90", est90, 90},
+		{"p99", est99, 99},
+	}
+	for _, tt := range tests {
+		want := percentile(sortedCopy(vals), tt.p)
+		got := tt.est.Value()
+		if relErr := math.Abs(got-want) / want; relErr > 0.05 {
+			t.Errorf("%s: estimate = %.2f, exact = %.2f (relative error %.3f exceeds 5%%)", tt.name, got, want, relErr)
+		}
+	}
+}
+
+func TestP2Estimator_FewerThanFiveSamplesFallsBackToExact(t *testing.T) {
+	est := NewP2Estimator(0.5)
+	est.Add(10)
+	est.Add(30)
+	est.Add(20)
+
+	got := est.Value()
+	want := percentile([]float64{10, 20, 30}, 50)
+	if got != want {
+		t.Errorf("Value() = %f, want %f (exact percentile of buffered sample)", got, want)
+	}
+}
+
+func sortedCopy(vals []float64) []float64 {
+	sorted := append([]float64(nil), vals...)
+	sort.Float64s(sorted)
+	return sorted
+}
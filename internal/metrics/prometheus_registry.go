@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusRegistry is a Registry backed by a prometheus.Registry, so
+// instruments created through it show up on whatever promhttp.Handler the
+// caller registers it with.
+type PrometheusRegistry struct {
+	reg *prometheus.Registry
+}
+
+// NewPrometheusRegistry creates a Registry backed by a fresh
+// prometheus.Registry. Pass it to promhttp.HandlerFor to expose it.
+func NewPrometheusRegistry() *PrometheusRegistry {
+	return &PrometheusRegistry{reg: prometheus.NewRegistry()}
+}
+
+// Prometheus returns the underlying prometheus.Registry, e.g. to pass to
+// promhttp.HandlerFor.
+func (p *PrometheusRegistry) Prometheus() *prometheus.Registry {
+	return p.reg
+}
+
+func (p *PrometheusRegistry) Counter(name, help string, labelNames ...string) CounterVec {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labelNames)
+	p.reg.MustRegister(vec)
+	return prometheusCounterVec{vec}
+}
+
+func (p *PrometheusRegistry) Gauge(name, help string, labelNames ...string) GaugeVec {
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labelNames)
+	p.reg.MustRegister(vec)
+	return prometheusGaugeVec{vec}
+}
+
+func (p *PrometheusRegistry) Histogram(name, help string, buckets []float64, labelNames ...string) HistogramVec {
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labelNames)
+	p.reg.MustRegister(vec)
+	return prometheusHistogramVec{vec}
+}
+
+type prometheusCounterVec struct{ vec *prometheus.CounterVec }
+
+func (c prometheusCounterVec) WithLabelValues(labelValues ...string) Counter {
+	return c.vec.WithLabelValues(labelValues...)
+}
+
+type prometheusGaugeVec struct{ vec *prometheus.GaugeVec }
+
+func (g prometheusGaugeVec) WithLabelValues(labelValues ...string) Gauge {
+	return g.vec.WithLabelValues(labelValues...)
+}
+
+type prometheusHistogramVec struct{ vec *prometheus.HistogramVec }
+
+func (h prometheusHistogramVec) WithLabelValues(labelValues ...string) Histogram {
+	return h.vec.WithLabelValues(labelValues...)
+}
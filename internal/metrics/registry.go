@@ -0,0 +1,44 @@
+package metrics
+
+// Counter is a monotonically increasing value, e.g. a count of errors.
+type Counter interface {
+	Inc()
+	Add(float64)
+}
+
+// Gauge is a value that can go up or down, e.g. an in-flight request count.
+type Gauge interface {
+	Set(float64)
+	Add(float64)
+}
+
+// Histogram observes sampled values, e.g. a phase duration in seconds.
+type Histogram interface {
+	Observe(float64)
+}
+
+// CounterVec hands out a Counter for a given set of label values, in the
+// same order as the label names the CounterVec was created with.
+type CounterVec interface {
+	WithLabelValues(labelValues ...string) Counter
+}
+
+// GaugeVec hands out a Gauge for a given set of label values.
+type GaugeVec interface {
+	WithLabelValues(labelValues ...string) Gauge
+}
+
+// HistogramVec hands out a Histogram for a given set of label values.
+type HistogramVec interface {
+	WithLabelValues(labelValues ...string) Histogram
+}
+
+// Registry creates the labeled instruments a component emits metrics
+// through, so callers can swap a real backend (Prometheus) for a no-op one
+// in tests without the component knowing the difference, and so multiple
+// components in one process don't collide on metric names.
+type Registry interface {
+	Counter(name, help string, labelNames ...string) CounterVec
+	Gauge(name, help string, labelNames ...string) GaugeVec
+	Histogram(name, help string, buckets []float64, labelNames ...string) HistogramVec
+}
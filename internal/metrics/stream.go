@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+)
+
+// streamLineMaxBytes bounds a single NDJSON line, so a corrupted or
+// unbounded stream can't grow bufio.Scanner's buffer without limit.
+const streamLineMaxBytes = 1 << 20
+
+// streamRecord discriminates an NDJSON line's payload: "request" lines
+// unmarshal into RequestResult, "summary" lines into Summary. This is the
+// one field ParseLoadgenOutput's three buffered strategies don't need,
+// since they always see the whole {"requests": [...], "summary": {...}}
+// object at once.
+type streamRecord struct {
+	RecordType string `json:"record_type"`
+}
+
+// ParseLoadgenStream is the streaming counterpart to ParseLoadgenOutput:
+// it reads r incrementally, one newline-delimited JSON record at a time,
+// instead of buffering the whole log — needed because a multi-hour run
+// can emit hundreds of MB of stderr that would otherwise OOM the
+// controller. Each line must be either {"record_type":"request", ...
+// RequestResult fields} or {"record_type":"summary", ... Summary fields}.
+//
+// Both returned channels are closed once r is exhausted or ctx is
+// canceled. requests is unbuffered: sends block until the caller reads,
+// so a slow consumer (e.g. one feeding an OnlineAggregator and persisting
+// progressively) applies backpressure all the way back to the scan loop
+// instead of this function buffering records the caller hasn't gotten to
+// yet. summaries is buffered by 1 — a run emits exactly one terminal
+// summary, so a caller that fully drains requests before checking
+// summaries (the natural `for range requests` pattern) can't deadlock
+// waiting for a send that has nowhere left to land.
+func ParseLoadgenStream(ctx context.Context, r io.Reader) (<-chan RequestResult, <-chan Summary, error) {
+	if r == nil {
+		return nil, nil, fmt.Errorf("parse loadgen stream: reader is nil")
+	}
+
+	requests := make(chan RequestResult)
+	summaries := make(chan Summary, 1)
+
+	go func() {
+		defer close(requests)
+		defer close(summaries)
+
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), streamLineMaxBytes)
+
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+
+			var rec streamRecord
+			if err := json.Unmarshal(line, &rec); err != nil {
+				log.Printf("parse loadgen stream: skipping malformed line: %v", err)
+				continue
+			}
+
+			switch rec.RecordType {
+			case "request":
+				var rr RequestResult
+				if err := json.Unmarshal(line, &rr); err != nil {
+					log.Printf("parse loadgen stream: skipping malformed request record: %v", err)
+					continue
+				}
+				select {
+				case requests <- rr:
+				case <-ctx.Done():
+					return
+				}
+			case "summary":
+				var s Summary
+				if err := json.Unmarshal(line, &s); err != nil {
+					log.Printf("parse loadgen stream: skipping malformed summary record: %v", err)
+					continue
+				}
+				select {
+				case summaries <- s:
+				case <-ctx.Done():
+					return
+				}
+			default:
+				log.Printf("parse loadgen stream: skipping line with unknown record_type %q", rec.RecordType)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			log.Printf("parse loadgen stream: scan error: %v", err)
+		}
+	}()
+
+	return requests, summaries, nil
+}
+
+// DecodeRequestLine attempts to decode one line as a
+// {"record_type":"request", ...} NDJSON record, the same shape
+// ParseLoadgenStream reads from a dedicated reader. It lets a caller
+// already tailing raw, possibly-mixed pod logs line by line (see
+// orchestrator.tailLoadgenLogs) opportunistically fold those same lines
+// into an OnlineAggregator without opening a second reader over the
+// stream. ok is false for anything that isn't a well-formed request
+// record — non-JSON progress lines, malformed JSON, and "summary" records
+// alike, since a run's terminal summary is handled separately once
+// ParseLoadgenOutput sees the full buffered blob.
+func DecodeRequestLine(line []byte) (rr RequestResult, ok bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return RequestResult{}, false
+	}
+	var rec streamRecord
+	if err := json.Unmarshal(line, &rec); err != nil || rec.RecordType != "request" {
+		return RequestResult{}, false
+	}
+	if err := json.Unmarshal(line, &rr); err != nil {
+		return RequestResult{}, false
+	}
+	return rr, true
+}
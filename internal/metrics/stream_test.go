@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLoadgenStream_RoutesRequestAndSummaryRecords(t *testing.T) {
+	input := strings.Join([]string{
+		`{"record_type":"request","ttft_ms":10,"e2e_latency_ms":100,"itl_ms":5,"output_tokens":50,"input_tokens":20,"duration_seconds":1,"success":true}`,
+		`{"record_type":"request","ttft_ms":15,"e2e_latency_ms":150,"itl_ms":6,"output_tokens":60,"input_tokens":25,"duration_seconds":1.2,"success":true}`,
+		`{"record_type":"summary","total_duration_seconds":2.2,"total_requests":2,"successful_requests":2,"failed_requests":0,"throughput_aggregate_tps":100,"requests_per_second":0.9}`,
+		``,
+	}, "\n")
+
+	requests, summaries, err := ParseLoadgenStream(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseLoadgenStream: %v", err)
+	}
+
+	var got []RequestResult
+	for r := range requests {
+		got = append(got, r)
+	}
+	if len(got) != 2 {
+		t.Fatalf("len(requests) = %d, want 2", len(got))
+	}
+	if got[0].TTFTMs != 10 || got[1].TTFTMs != 15 {
+		t.Errorf("unexpected request payloads: %+v", got)
+	}
+
+	select {
+	case s, ok := <-summaries:
+		if !ok {
+			t.Fatal("summaries channel closed before delivering a summary")
+		}
+		if s.TotalRequests != 2 {
+			t.Errorf("Summary.TotalRequests = %d, want 2", s.TotalRequests)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for summary")
+	}
+}
+
+func TestParseLoadgenStream_NilReaderErrors(t *testing.T) {
+	_, _, err := ParseLoadgenStream(context.Background(), nil)
+	if err == nil {
+		t.Fatal("expected an error for a nil reader")
+	}
+}
+
+func TestDecodeRequestLine(t *testing.T) {
+	cases := []struct {
+		name string
+		line string
+		want bool
+	}{
+		{"request record", `{"record_type":"request","ttft_ms":10,"success":true}`, true},
+		{"summary record", `{"record_type":"summary","total_requests":2}`, false},
+		{"unknown record type", `{"record_type":"progress","pct":50}`, false},
+		{"non-JSON line", `loadgen: warming up...`, false},
+		{"blank line", `   `, false},
+		{"malformed JSON", `{"record_type":"request",`, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, ok := DecodeRequestLine([]byte(c.line))
+			if ok != c.want {
+				t.Errorf("DecodeRequestLine(%q) ok = %v, want %v", c.line, ok, c.want)
+			}
+		})
+	}
+}
+
+func TestDecodeRequestLine_Fields(t *testing.T) {
+	rr, ok := DecodeRequestLine([]byte(`{"record_type":"request","ttft_ms":10,"e2e_latency_ms":100,"output_tokens":50,"success":true}`))
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if rr.TTFTMs != 10 || rr.E2ELatencyMs != 100 || rr.OutputTokens != 50 || !rr.Success {
+		t.Errorf("unexpected fields: %+v", rr)
+	}
+}
+
+func TestParseLoadgenStream_CancelStopsDelivery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	input := `{"record_type":"request","ttft_ms":10,"success":true}` + "\n"
+	requests, summaries, err := ParseLoadgenStream(ctx, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseLoadgenStream: %v", err)
+	}
+
+	select {
+	case <-requests:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for requests channel to close after cancellation")
+	}
+	select {
+	case <-summaries:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for summaries channel to close after cancellation")
+	}
+}
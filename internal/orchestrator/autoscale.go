@@ -0,0 +1,239 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/autoscale"
+	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/metrics"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ExecuteAutoscale ramps a single model Deployment through req.ConcurrencyStages
+// in order, scaling its replica count after each stage via autoscale.Decide
+// fed by that stage's GPUScraper snapshot. Unlike ExecuteSLOSearch, which
+// probes many independent child runs, the whole ramp stays within runID's
+// one Deployment: the Deployment being resized is itself the thing under
+// test, not a series of independent configurations. The final stage's
+// computed metrics become runID's own BenchmarkMetrics, augmented with the
+// replica bounds, the per-stage trajectory, and (if the ramp ever scaled up)
+// how long the replacement replica took to report ready.
+func (o *Orchestrator) ExecuteAutoscale(ctx context.Context, runID string, model *database.Model, instanceType *database.InstanceType, req database.AutoscaleRequest) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	o.mu.Lock()
+	o.cancels[runID] = cancel
+	o.mu.Unlock()
+	defer func() {
+		o.mu.Lock()
+		delete(o.cancels, runID)
+		o.mu.Unlock()
+	}()
+
+	start := time.Now()
+	o.observer.RunStarted()
+	defer func() {
+		outcome := "completed"
+		if err != nil {
+			outcome = "failed"
+		}
+		o.observer.RunFinished(outcome, time.Since(start))
+	}()
+
+	ns := defaultNamespace
+	modelName := fmt.Sprintf("bench-%s", runID[:8])
+	instanceName := instanceType.Name
+	framework := req.Framework
+
+	if err := o.repo.UpdateRunStatus(ctx, runID, "running"); err != nil {
+		return fmt.Errorf("update status to running: %w", err)
+	}
+
+	var lastLoadgenName string
+	defer func() {
+		o.teardown(context.Background(), runID, ns, modelName, lastLoadgenName)
+	}()
+
+	runReq := &database.RunRequest{
+		ModelHfID:            model.HfID,
+		ModelHfRevision:      model.HfRevision,
+		InstanceTypeName:     instanceType.Name,
+		Framework:            req.Framework,
+		FrameworkVersion:     req.FrameworkVersion,
+		TensorParallelDegree: req.TensorParallelDegree,
+		Quantization:         req.Quantization,
+		InputSequenceLength:  req.InputSequenceLength,
+		OutputSequenceLength: req.OutputSequenceLength,
+		DatasetName:          req.DatasetName,
+		DatasetSpec:          req.DatasetSpec,
+		RunType:              "autoscale",
+		MaxModelLen:          req.MaxModelLen,
+		HfToken:              req.HfToken,
+		Plugins:              req.Plugins,
+	}
+
+	log.Printf("[%s] deploying model %s on %s for autoscale ramp (min=%d max=%d)",
+		runID[:8], req.ModelHfID, instanceName, req.MinReplicas, req.MaxReplicas)
+	deployCfg := RunConfig{RunID: runID, Model: model, InstanceType: instanceType, Request: runReq, Replicas: req.MinReplicas}
+	if _, err := o.deployModel(ctx, ns, modelName, deployCfg); err != nil {
+		o.markFailed(ctx, runID, instanceName, framework, "deploy", o.podFailureReason(ctx, ns, "app="+modelName))
+		return fmt.Errorf("deploy model: %w", err)
+	}
+	if err := o.waitForReady(ctx, ns, modelName); err != nil {
+		reason := o.podFailureReason(ctx, ns, "app="+modelName)
+		o.markFailed(ctx, runID, instanceName, framework, "ready", reason)
+		return fmt.Errorf("wait for readiness: %w", err)
+	}
+
+	currentReplicas := req.MinReplicas
+	var stages []database.AutoscaleStageResult
+	var scaleUpLatency *float64
+	var lastComputed *database.BenchmarkMetrics
+
+	for i, concurrency := range req.ConcurrencyStages {
+		stageReq := *runReq
+		stageReq.Concurrency = concurrency
+		stageCfg := RunConfig{RunID: runID, Model: model, InstanceType: instanceType, Request: &stageReq}
+		loadgenName := fmt.Sprintf("loadgen-%s-%d", runID[:8], i)
+		lastLoadgenName = loadgenName
+
+		var gpuScraper AcceleratorScraper
+		if s := NewAcceleratorScraperForInstance(instanceType, modelName); s != nil {
+			gpuScraper = s
+			gpuScraper.Start(ctx)
+		}
+
+		log.Printf("[%s] autoscale stage %d: concurrency=%d replicas=%d", runID[:8], i, concurrency, currentReplicas)
+		if _, err := o.launchLoadgen(ctx, ns, loadgenName, modelName, stageCfg); err != nil {
+			if gpuScraper != nil {
+				gpuScraper.Stop()
+			}
+			o.markFailed(ctx, runID, instanceName, framework, "launch_loadgen", "")
+			return fmt.Errorf("autoscale stage %d: launch loadgen: %w", i, err)
+		}
+
+		logData, collectErr := o.waitAndCollect(ctx, ns, loadgenName)
+		o.deleteLoadgenJob(ctx, ns, loadgenName)
+
+		var gpuMetrics *GPUMetrics
+		if gpuScraper != nil {
+			gpuMetrics = gpuScraper.Stop()
+		}
+		if collectErr != nil {
+			o.markFailed(ctx, runID, instanceName, framework, "collect", o.podFailureReason(ctx, ns, "job-name="+loadgenName))
+			return fmt.Errorf("autoscale stage %d: collect results: %w", i, collectErr)
+		}
+
+		output, err := metrics.ParseLoadgenOutput(logData)
+		if err != nil {
+			o.markFailed(ctx, runID, instanceName, framework, "parse", "")
+			return fmt.Errorf("autoscale stage %d: parse loadgen output: %w", i, err)
+		}
+
+		computed := metrics.ComputeMetrics(output, o.resolvePricingContext(ctx, instanceType.Name))
+		computed.RunID = runID
+
+		var currentMetric float64
+		if gpuMetrics != nil {
+			computed.AcceleratorKind = gpuScraper.Kind()
+			computed.AcceleratorUtilizationPct = &gpuMetrics.UtilizationPeakPct
+			computed.AcceleratorUtilizationAvgPct = &gpuMetrics.UtilizationAvgPct
+			computed.AcceleratorMemoryPeakGiB = &gpuMetrics.MemoryPeakGiB
+			computed.WaitingRequestsMax = &gpuMetrics.WaitingRequestsMax
+
+			switch req.TargetMetric {
+			case "waiting_requests":
+				currentMetric = float64(gpuMetrics.WaitingRequestsMax)
+			case "utilization_pct":
+				currentMetric = gpuMetrics.UtilizationAvgPct
+			}
+		}
+
+		var aggregateTPS, perReplicaTPS float64
+		if computed.ThroughputAggregateTPS != nil {
+			aggregateTPS = *computed.ThroughputAggregateTPS
+			perReplicaTPS = aggregateTPS / float64(currentReplicas)
+		}
+		stages = append(stages, database.AutoscaleStageResult{
+			Concurrency:             concurrency,
+			Replicas:                currentReplicas,
+			ThroughputAggregateTPS:  aggregateTPS,
+			ThroughputPerReplicaTPS: perReplicaTPS,
+		})
+		lastComputed = computed
+
+		decision := autoscale.Decide(currentReplicas, currentMetric, req.TargetValue, req.Tolerance, req.MinReplicas, req.MaxReplicas)
+		if decision.DesiredReplicas == currentReplicas {
+			continue
+		}
+
+		log.Printf("[%s] autoscale stage %d: scaling %d -> %d (usage_ratio=%.2f)",
+			runID[:8], i, currentReplicas, decision.DesiredReplicas, decision.UsageRatio)
+		scaleStart := time.Now()
+		if err := o.scaleDeployment(ctx, ns, modelName, decision.DesiredReplicas); err != nil {
+			o.markFailed(ctx, runID, instanceName, framework, "scale", "")
+			return fmt.Errorf("autoscale stage %d: scale deployment: %w", i, err)
+		}
+		if err := o.waitForReady(ctx, ns, modelName); err != nil {
+			reason := o.podFailureReason(ctx, ns, "app="+modelName)
+			o.markFailed(ctx, runID, instanceName, framework, "ready", reason)
+			return fmt.Errorf("autoscale stage %d: wait for readiness after scale: %w", i, err)
+		}
+		if decision.DesiredReplicas > currentReplicas && scaleUpLatency == nil {
+			elapsed := time.Since(scaleStart).Seconds()
+			scaleUpLatency = &elapsed
+		}
+		currentReplicas = decision.DesiredReplicas
+	}
+
+	if lastComputed == nil {
+		o.markFailed(ctx, runID, instanceName, framework, "autoscale", "")
+		return fmt.Errorf("autoscale ramp produced no stages")
+	}
+
+	minReplicas, maxReplicas := req.MinReplicas, req.MaxReplicas
+	lastComputed.ReplicasMin = &minReplicas
+	lastComputed.ReplicasMax = &maxReplicas
+	lastComputed.ScaleUpLatencySeconds = scaleUpLatency
+	lastComputed.AutoscaleStages = stages
+
+	if err := o.repo.PersistMetrics(ctx, runID, lastComputed, nil); err != nil {
+		o.markFailed(ctx, runID, instanceName, framework, "persist", "")
+		return fmt.Errorf("persist autoscale metrics: %w", err)
+	}
+	if err := o.repo.UpdateRunStatus(ctx, runID, "completed"); err != nil {
+		return fmt.Errorf("update status to completed: %w", err)
+	}
+
+	log.Printf("[%s] autoscale ramp completed: final replicas=%d", runID[:8], currentReplicas)
+	return nil
+}
+
+// scaleDeployment patches modelName's replica count with a JSON merge patch
+// rather than a full server-side apply (see createDeployment), so scaling
+// doesn't re-assert the whole spec the ramp's own prior writes to other
+// fields might still be in flight for.
+func (o *Orchestrator) scaleDeployment(ctx context.Context, ns, name string, replicas int) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	return o.trackK8sCall("patch", "deployment", func() error {
+		_, err := o.client.AppsV1().Deployments(ns).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{FieldManager: fieldManager})
+		return err
+	})
+}
+
+// deleteLoadgenJob removes a completed stage's loadgen Job before the next
+// stage's Job is created under a fresh name, since a Job's pod template is
+// immutable once created and can't simply be re-applied in place the way
+// createDeployment/createService can.
+func (o *Orchestrator) deleteLoadgenJob(ctx context.Context, ns, name string) {
+	propagation := metav1.DeletePropagationBackground
+	_ = o.trackK8sCall("delete", "job", func() error {
+		return o.client.BatchV1().Jobs(ns).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	})
+}
@@ -1,61 +1,434 @@
 package orchestrator
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/accelbench/accelbench/internal/database"
 )
 
 const (
 	scrapeInterval = 5 * time.Second
 	scrapeTimeout  = 3 * time.Second
+
+	// Default ports for the sidecar device exporters, per their upstream
+	// Helm charts / DaemonSet manifests.
+	DCGMExporterPort  = 9400
+	NeuronMonitorPort = 8000
+	TPULibtpuPort     = 8431
+	ROCmExporterPort  = 5000
+)
+
+// Metric names vLLM exposes on its Prometheus /metrics endpoint.
+const (
+	metricCacheUsage = "vllm:gpu_cache_usage_perc"
+	metricWaiting    = "vllm:num_requests_waiting"
+	metricRunning    = "vllm:num_requests_running"
+	metricTTFT       = "vllm:time_to_first_token_seconds"
+	metricTPOT       = "vllm:time_per_output_token_seconds"
+	metricE2E        = "vllm:e2e_request_latency_seconds"
+)
+
+// Metric names exposed by the NVIDIA DCGM exporter and neuron-monitor's
+// Prometheus endpoint, used to measure real device utilization alongside
+// vLLM's KV-cache-derived estimate.
+const (
+	metricDCGMGPUUtil      = "DCGM_FI_DEV_GPU_UTIL"
+	metricDCGMFBUsed       = "DCGM_FI_DEV_FB_USED"
+	metricDCGMPower        = "DCGM_FI_DEV_POWER_USAGE"
+	metricDCGMTemp         = "DCGM_FI_DEV_GPU_TEMP"
+	metricDCGMSMActive     = "DCGM_FI_PROF_SM_ACTIVE"
+	metricDCGMTensorActive = "DCGM_FI_PROF_PIPE_TENSOR_ACTIVE"
+
+	metricNeuronCoreUtil = "neuroncore_utilization_ratio"
+	metricNeuronHBMUsed  = "neuron_hardware_info_memory_used_bytes"
+)
+
+// Metric names exposed by libtpu's Prometheus endpoint (Google TPU) and
+// rocm_smi_exporter (AMD ROCm).
+const (
+	metricTPUTensorCoreUtil = "tensorcore_utilization"
+	metricTPUHBMUsed        = "hbm_memory_used_bytes"
+
+	metricROCmUtil     = "rocm_utilization_percentage"
+	metricROCmPower    = "rocm_average_socket_power_watts"
+	metricROCmTemp     = "rocm_temperature_sensor_edge_celsius"
+	metricROCmVRAMUsed = "rocm_vram_total_used_memory_bytes"
 )
 
-// GPUMetrics holds aggregated GPU metrics collected during a benchmark run.
+// Prometheus label names the device exporters use to identify which
+// physical accelerator a series belongs to. Used by deviceFamilyIndexed to
+// key per-device samples by device index rather than collapsing them.
+const (
+	dcgmDeviceLabel   = "gpu"
+	neuronDeviceLabel = "neuroncore"
+	tpuDeviceLabel    = "core"
+	rocmDeviceLabel   = "card"
+)
+
+// DeviceExporterKind identifies which device-level exporter a scraper's
+// secondary source speaks, since DCGM and neuron-monitor expose different
+// metric names for the same concepts.
+type DeviceExporterKind string
+
+const (
+	DeviceExporterNone   DeviceExporterKind = ""
+	DeviceExporterDCGM   DeviceExporterKind = "dcgm"
+	DeviceExporterNeuron DeviceExporterKind = "neuron"
+	DeviceExporterTPU    DeviceExporterKind = "tpu"
+	DeviceExporterROCm   DeviceExporterKind = "rocm"
+)
+
+// AcceleratorScraper periodically samples a benchmark run's accelerator
+// fleet and, once stopped, returns the aggregated GPUMetrics collected
+// during the run. GPUScraper implements it for every vendor this package
+// supports (DCGM for NVIDIA, neuron-monitor for AWS Neuron, libtpu for
+// Google TPU, rocm_smi_exporter for AMD ROCm) — the vendor is selected per
+// ScrapeTarget by its DeviceExporterKind rather than by a separate type per
+// vendor, since the five families only differ in metric names and units.
+// Use NewAcceleratorScraperForInstance to build the right one for an
+// InstanceType.
+type AcceleratorScraper interface {
+	Start(ctx context.Context)
+	Stop() *GPUMetrics
+	// Kind reports which vendor this scraper was built to scrape ("nvidia",
+	// "neuron", "tpu", "rocm"), for tagging computed metrics and logs.
+	Kind() string
+}
+
+// gaugeSample is a single timestamped scalar observation.
+type gaugeSample struct {
+	ts    time.Time
+	value float64
+}
+
+// bucketSnapshot is the cumulative bucket state of a histogram metric as
+// reported by a single scrape. Prometheus histogram buckets are cumulative
+// (each bucket counts all observations ≤ its upper bound).
+type bucketSnapshot struct {
+	upperBounds []float64
+	counts      []uint64
+}
+
+// histogramAccumulator reconstructs per-interval observation counts from
+// successive cumulative histogram snapshots, so percentiles reflect samples
+// observed during the run rather than the lifetime of the vLLM process.
+type histogramAccumulator struct {
+	upperBounds []float64
+	deltaCounts []uint64
+	last        *bucketSnapshot
+}
+
+// observe folds a new cumulative snapshot into the accumulator. The first
+// snapshot establishes a baseline (no delta is recorded for it, since it may
+// include observations from before this benchmark run started). A snapshot
+// whose counts regress (process restart, counter reset) re-baselines instead
+// of corrupting the running totals.
+func (h *histogramAccumulator) observe(snap *bucketSnapshot) {
+	if h.last == nil {
+		h.upperBounds = snap.upperBounds
+		h.deltaCounts = make([]uint64, len(snap.counts))
+		h.last = snap
+		return
+	}
+	if len(snap.counts) != len(h.last.counts) {
+		// Bucket layout changed — re-baseline.
+		h.upperBounds = snap.upperBounds
+		h.deltaCounts = make([]uint64, len(snap.counts))
+		h.last = snap
+		return
+	}
+	for i, c := range snap.counts {
+		if c < h.last.counts[i] {
+			// Counter reset — re-baseline this interval only.
+			h.last = snap
+			return
+		}
+		h.deltaCounts[i] += c - h.last.counts[i]
+	}
+	h.last = snap
+}
+
+// percentile estimates the p-th percentile (0-100) from the accumulated
+// cumulative bucket counts using linear interpolation within the bucket
+// that contains the target rank. Returns nil if no observations were made.
+func (h *histogramAccumulator) percentile(p float64) *float64 {
+	if h == nil || len(h.deltaCounts) == 0 {
+		return nil
+	}
+	total := h.deltaCounts[len(h.deltaCounts)-1]
+	if total == 0 {
+		return nil
+	}
+	target := (p / 100.0) * float64(total)
+
+	var prevBound float64
+	var prevCount uint64
+	for i, upper := range h.upperBounds {
+		count := h.deltaCounts[i]
+		if float64(count) >= target {
+			span := float64(count - prevCount)
+			if span <= 0 {
+				v := upper
+				return &v
+			}
+			frac := (target - float64(prevCount)) / span
+			v := prevBound + frac*(upper-prevBound)
+			return &v
+		}
+		prevBound = upper
+		prevCount = count
+	}
+	v := prevBound
+	return &v
+}
+
+// GPUMetrics holds aggregated GPU and vLLM server metrics collected during a
+// benchmark run.
 type GPUMetrics struct {
-	// Peak GPU cache utilization percentage (0-100).
+	// GPU KV-cache utilization percentage (0-100).
 	UtilizationPeakPct float64
-	// Average GPU cache utilization percentage (0-100).
-	UtilizationAvgPct float64
-	// Peak memory usage in GiB, derived from cache utilization Ã— total GPU memory.
+	UtilizationAvgPct  float64
+	UtilizationP50Pct  *float64
+	UtilizationP90Pct  *float64
+	UtilizationP95Pct  *float64
+	UtilizationP99Pct  *float64
+
+	// Peak memory usage in GiB, derived from cache utilization × total GPU memory.
 	MemoryPeakGiB float64
-	// Maximum number of waiting requests observed.
+
+	// Queue depth (num_requests_waiting).
 	WaitingRequestsMax int
+	WaitingP50         *float64
+	WaitingP90         *float64
+	WaitingP95         *float64
+	WaitingP99         *float64
+
+	// In-flight requests (num_requests_running).
+	RunningRequestsMax int
+	RunningP50         *float64
+	RunningP90         *float64
+	RunningP95         *float64
+	RunningP99         *float64
+
+	// vLLM server-side histograms, in seconds.
+	TTFTP50Seconds *float64
+	TTFTP90Seconds *float64
+	TTFTP95Seconds *float64
+	TTFTP99Seconds *float64
+
+	TPOTP50Seconds *float64
+	TPOTP90Seconds *float64
+	TPOTP95Seconds *float64
+	TPOTP99Seconds *float64
+
+	E2ELatencyP50Seconds *float64
+	E2ELatencyP90Seconds *float64
+	E2ELatencyP95Seconds *float64
+	E2ELatencyP99Seconds *float64
+
+	// Device-level metrics from the DCGM exporter or neuron-monitor, nil if
+	// no device exporter was configured. These measure real hardware
+	// occupancy, as opposed to UtilizationPeakPct/AvgPct which are derived
+	// from vLLM's KV-cache usage.
+	SMActivePeakPct     *float64
+	TensorCoreActivePct *float64
+	PowerAvgW           *float64
+	TempPeakC           *float64
+	HBMUsedPeakGiB      *float64
+
+	// DeviceSamples is the raw per-device-index series the collapsed
+	// fields above were derived from, nil under the same conditions as
+	// SMActivePeakPct. internal/collector consumes these for percentile,
+	// energy, and thermal-headroom rollups that a collapsed mean-then-peak
+	// can't express.
+	DeviceSamples []database.AcceleratorSample
+
+	// Per-target breakdown for multi-pod (tensor-parallel) deployments, nil
+	// for single-target scrapers.
+	PerRank []RankMetrics
+}
+
+// RankMetrics is one scrape target's aggregated metrics, tagged with its
+// tensor-parallel rank and pod name. Returned alongside the fleet-aggregated
+// GPUMetrics for deployments where each rank runs its own vLLM process with
+// its own /metrics endpoint.
+type RankMetrics struct {
+	Rank    int
+	PodName string
+	GPUMetrics
+}
+
+// ScrapeTarget identifies one pod's metrics endpoints: the vLLM server
+// itself, and optionally a sidecar device exporter (DCGM or neuron-monitor).
+type ScrapeTarget struct {
+	Rank             int
+	PodName          string
+	MetricsURL       string
+	DeviceMetricsURL string
+	DeviceKind       DeviceExporterKind
 }
 
-// GPUScraper periodically polls a vLLM Prometheus metrics endpoint and
-// collects GPU utilization and queue depth samples.
+// NewScrapeTarget builds a ScrapeTarget for a vLLM pod with no device
+// exporter configured. Use WithDevice to add one.
+func NewScrapeTarget(rank int, podName, host string, port int) ScrapeTarget {
+	return ScrapeTarget{
+		Rank:       rank,
+		PodName:    podName,
+		MetricsURL: fmt.Sprintf("http://%s:%d/metrics", host, port),
+	}
+}
+
+// WithDevice returns a copy of the target with a device exporter attached.
+func (t ScrapeTarget) WithDevice(host string, port int, kind DeviceExporterKind) ScrapeTarget {
+	t.DeviceMetricsURL = fmt.Sprintf("http://%s:%d/metrics", host, port)
+	t.DeviceKind = kind
+	return t
+}
+
+// scrapeBuffer accumulates samples for a single ScrapeTarget. Each target
+// gets its own buffer and mutex so a worker scraping one pod never blocks on
+// another's lock.
+type scrapeBuffer struct {
+	mu sync.Mutex
+
+	utilizationSamples []gaugeSample
+	waitingSamples     []gaugeSample
+	runningSamples     []gaugeSample
+	ttftHist           histogramAccumulator
+	tpotHist           histogramAccumulator
+	e2eHist            histogramAccumulator
+
+	smActiveSamples     []gaugeSample
+	tensorActiveSamples []gaugeSample
+	powerSamples        []gaugeSample
+	tempSamples         []gaugeSample
+	hbmUsedSamples      []gaugeSample
+
+	// deviceSamples retains each scrape's per-device-index readings
+	// alongside the collapsed samples above, for internal/collector's
+	// rollup math.
+	deviceSamples []database.AcceleratorSample
+}
+
+const defaultMaxScrapeWorkers = 8
+
+// GPUScraper periodically polls one or more vLLM Prometheus metrics
+// endpoints and collects GPU utilization, queue depth, and latency histogram
+// samples. Scraping fans out across a bounded worker pool so one slow pod
+// cannot delay the tick for the rest of the fleet.
 type GPUScraper struct {
-	metricsURL     string
+	targets        []ScrapeTarget
 	totalMemoryGiB float64
 	client         *http.Client
+	maxWorkers     int
+	scrapeInterval time.Duration
 
-	mu                sync.Mutex
-	utilizationSample []float64
-	waitingSamples    []int
-	cancel            context.CancelFunc
-	done              chan struct{}
+	buffers []*scrapeBuffer // parallel to targets
+
+	cancel context.CancelFunc
+	done   chan struct{}
 }
 
-// NewGPUScraper creates a scraper targeting the given vLLM service.
-// totalMemoryGiB is the total GPU memory for the instance (used to
-// derive peak memory from cache utilization percentage).
-func NewGPUScraper(serviceHost string, port int, totalMemoryGiB float64) *GPUScraper {
+// NewGPUScraper creates a scraper polling the given targets. totalMemoryGiB
+// is the total GPU memory for the instance (used to derive peak memory from
+// cache utilization percentage). The worker pool defaults to
+// min(len(targets), 8); use WithMaxWorkers to override it.
+func NewGPUScraper(targets []ScrapeTarget, totalMemoryGiB float64) *GPUScraper {
+	buffers := make([]*scrapeBuffer, len(targets))
+	for i := range buffers {
+		buffers[i] = &scrapeBuffer{}
+	}
 	return &GPUScraper{
-		metricsURL:     fmt.Sprintf("http://%s:%d/metrics", serviceHost, port),
+		targets:        targets,
 		totalMemoryGiB: totalMemoryGiB,
 		client: &http.Client{
 			Timeout: scrapeTimeout,
 		},
-		done: make(chan struct{}),
+		maxWorkers:     min(len(targets), defaultMaxScrapeWorkers),
+		scrapeInterval: scrapeInterval,
+		buffers:        buffers,
+		done:           make(chan struct{}),
+	}
+}
+
+// Kind reports which device exporter this scraper's targets were built
+// with, deriving the scraper-level vendor tag from the first target that
+// has one configured (a scraper is always built for one homogeneous
+// fleet, so every target agrees). Returns "" if none of the targets have a
+// device exporter attached.
+func (s *GPUScraper) Kind() string {
+	for _, t := range s.targets {
+		switch t.DeviceKind {
+		case DeviceExporterDCGM:
+			return "nvidia"
+		case DeviceExporterNeuron:
+			return "neuron"
+		case DeviceExporterTPU:
+			return "tpu"
+		case DeviceExporterROCm:
+			return "rocm"
+		}
+	}
+	return ""
+}
+
+// NewAcceleratorScraperForInstance builds the AcceleratorScraper for
+// instanceType's AcceleratorType, wired to scrape podName's vLLM endpoint
+// plus its vendor-specific device-exporter sidecar (all addressed by
+// podName, since the orchestrator always co-locates the device exporter as
+// a sidecar in the same pod as the model server). Returns nil if
+// AcceleratorType isn't one of the supported accelerator families, since
+// there's nothing to scrape for e.g. a CPU-only instance.
+func NewAcceleratorScraperForInstance(instanceType *database.InstanceType, podName string) AcceleratorScraper {
+	totalMemGiB := float64(instanceType.AcceleratorMemoryGiB)
+
+	var target ScrapeTarget
+	switch strings.ToLower(instanceType.AcceleratorType) {
+	case "gpu":
+		target = NewScrapeTarget(0, podName, podName, 8000).
+			WithDevice(podName, DCGMExporterPort, DeviceExporterDCGM)
+	case "neuron":
+		target = NewScrapeTarget(0, podName, podName, 8000).
+			WithDevice(podName, NeuronMonitorPort, DeviceExporterNeuron)
+	case "tpu":
+		target = NewScrapeTarget(0, podName, podName, 8000).
+			WithDevice(podName, TPULibtpuPort, DeviceExporterTPU)
+	case "rocm":
+		target = NewScrapeTarget(0, podName, podName, 8000).
+			WithDevice(podName, ROCmExporterPort, DeviceExporterROCm)
+	default:
+		return nil
+	}
+	return NewGPUScraper([]ScrapeTarget{target}, totalMemGiB)
+}
+
+// WithMaxWorkers overrides the default worker pool size.
+func (s *GPUScraper) WithMaxWorkers(n int) *GPUScraper {
+	if n > 0 {
+		s.maxWorkers = n
 	}
+	return s
+}
+
+// WithScrapeInterval overrides the default scrape tick interval.
+func (s *GPUScraper) WithScrapeInterval(d time.Duration) *GPUScraper {
+	if d > 0 {
+		s.scrapeInterval = d
+	}
+	return s
 }
 
 // Start begins scraping in a background goroutine. It is safe to call
@@ -65,78 +438,206 @@ func (s *GPUScraper) Start(ctx context.Context) {
 	go s.loop(ctx)
 }
 
-// Stop stops the scraper and returns the aggregated GPU metrics.
-// Returns nil if no samples were collected.
+// Stop stops the scraper and returns the fleet-aggregated GPU metrics, with
+// PerRank populated per scrape target. Returns nil if no samples were
+// collected from any target.
 func (s *GPUScraper) Stop() *GPUMetrics {
 	if s.cancel != nil {
 		s.cancel()
 	}
 	<-s.done
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	var perRank []RankMetrics
+	merged := &scrapeBuffer{}
+	for i, buf := range s.buffers {
+		buf.mu.Lock()
+		if rm := aggregateBuffer(buf, s.totalMemoryGiB); rm != nil {
+			perRank = append(perRank, RankMetrics{
+				Rank:       s.targets[i].Rank,
+				PodName:    s.targets[i].PodName,
+				GPUMetrics: *rm,
+			})
+		}
+		mergeBuffer(merged, buf)
+		buf.mu.Unlock()
+	}
+
+	m := aggregateBuffer(merged, s.totalMemoryGiB)
+	if m == nil {
+		return nil
+	}
+	m.PerRank = perRank
+	return m
+}
+
+// mergeBuffer appends src's samples and histogram deltas into dst, used to
+// fold all per-target buffers into one fleet-wide buffer for the aggregated
+// GPUMetrics returned by Stop.
+func mergeBuffer(dst, src *scrapeBuffer) {
+	dst.utilizationSamples = append(dst.utilizationSamples, src.utilizationSamples...)
+	dst.waitingSamples = append(dst.waitingSamples, src.waitingSamples...)
+	dst.runningSamples = append(dst.runningSamples, src.runningSamples...)
+	dst.smActiveSamples = append(dst.smActiveSamples, src.smActiveSamples...)
+	dst.tensorActiveSamples = append(dst.tensorActiveSamples, src.tensorActiveSamples...)
+	dst.powerSamples = append(dst.powerSamples, src.powerSamples...)
+	dst.tempSamples = append(dst.tempSamples, src.tempSamples...)
+	dst.hbmUsedSamples = append(dst.hbmUsedSamples, src.hbmUsedSamples...)
+	dst.deviceSamples = append(dst.deviceSamples, src.deviceSamples...)
+
+	mergeHistogram(&dst.ttftHist, &src.ttftHist)
+	mergeHistogram(&dst.tpotHist, &src.tpotHist)
+	mergeHistogram(&dst.e2eHist, &src.e2eHist)
+}
+
+// mergeHistogram adds src's accumulated delta counts into dst, aligning on
+// src's bucket layout the first time dst sees any data.
+func mergeHistogram(dst, src *histogramAccumulator) {
+	if len(src.deltaCounts) == 0 {
+		return
+	}
+	if len(dst.deltaCounts) == 0 {
+		dst.upperBounds = src.upperBounds
+		dst.deltaCounts = make([]uint64, len(src.deltaCounts))
+	}
+	if len(dst.deltaCounts) != len(src.deltaCounts) {
+		return
+	}
+	for i, c := range src.deltaCounts {
+		dst.deltaCounts[i] += c
+	}
+}
 
-	if len(s.utilizationSample) == 0 {
+// aggregateBuffer computes a GPUMetrics snapshot from one scrapeBuffer's
+// accumulated samples. Returns nil if no utilization samples were recorded.
+func aggregateBuffer(buf *scrapeBuffer, totalMemoryGiB float64) *GPUMetrics {
+	if len(buf.utilizationSamples) == 0 {
 		return nil
 	}
 
+	utilVals := gaugeValues(buf.utilizationSamples)
+	waitVals := gaugeValues(buf.waitingSamples)
+	runVals := gaugeValues(buf.runningSamples)
+
 	var sum, peak float64
-	for _, v := range s.utilizationSample {
+	for _, v := range utilVals {
 		sum += v
 		if v > peak {
 			peak = v
 		}
 	}
-	avg := sum / float64(len(s.utilizationSample))
+	avg := sum / float64(len(utilVals))
 
-	var maxWaiting int
-	for _, w := range s.waitingSamples {
-		if w > maxWaiting {
-			maxWaiting = w
+	var maxWaiting, maxRunning int
+	for _, w := range waitVals {
+		if int(w) > maxWaiting {
+			maxWaiting = int(w)
+		}
+	}
+	for _, r := range runVals {
+		if int(r) > maxRunning {
+			maxRunning = int(r)
 		}
 	}
 
-	// Convert cache utilization to percentage (vLLM reports 0.0-1.0).
-	peakPct := peak * 100
-	avgPct := avg * 100
-	memPeakGiB := peak * s.totalMemoryGiB
-
-	return &GPUMetrics{
-		UtilizationPeakPct: peakPct,
-		UtilizationAvgPct:  avgPct,
-		MemoryPeakGiB:      memPeakGiB,
+	m := &GPUMetrics{
+		UtilizationPeakPct: peak * 100,
+		UtilizationAvgPct:  avg * 100,
+		MemoryPeakGiB:      peak * totalMemoryGiB,
 		WaitingRequestsMax: maxWaiting,
+		RunningRequestsMax: maxRunning,
 	}
+	m.UtilizationP50Pct, m.UtilizationP90Pct, m.UtilizationP95Pct, m.UtilizationP99Pct = gaugePercentilesPct(utilVals)
+	m.WaitingP50, m.WaitingP90, m.WaitingP95, m.WaitingP99 = gaugePercentiles(waitVals)
+	m.RunningP50, m.RunningP90, m.RunningP95, m.RunningP99 = gaugePercentiles(runVals)
+
+	m.TTFTP50Seconds = buf.ttftHist.percentile(50)
+	m.TTFTP90Seconds = buf.ttftHist.percentile(90)
+	m.TTFTP95Seconds = buf.ttftHist.percentile(95)
+	m.TTFTP99Seconds = buf.ttftHist.percentile(99)
+
+	m.TPOTP50Seconds = buf.tpotHist.percentile(50)
+	m.TPOTP90Seconds = buf.tpotHist.percentile(90)
+	m.TPOTP95Seconds = buf.tpotHist.percentile(95)
+	m.TPOTP99Seconds = buf.tpotHist.percentile(99)
+
+	m.E2ELatencyP50Seconds = buf.e2eHist.percentile(50)
+	m.E2ELatencyP90Seconds = buf.e2eHist.percentile(90)
+	m.E2ELatencyP95Seconds = buf.e2eHist.percentile(95)
+	m.E2ELatencyP99Seconds = buf.e2eHist.percentile(99)
+
+	m.SMActivePeakPct = peakPct(buf.smActiveSamples)
+	m.TensorCoreActivePct = peakPct(buf.tensorActiveSamples)
+	m.PowerAvgW = avgValue(buf.powerSamples)
+	m.TempPeakC = peakValue(buf.tempSamples)
+	m.HBMUsedPeakGiB = peakValue(buf.hbmUsedSamples)
+	m.DeviceSamples = buf.deviceSamples
+
+	return m
 }
 
 func (s *GPUScraper) loop(ctx context.Context) {
 	defer close(s.done)
 
-	ticker := time.NewTicker(scrapeInterval)
+	ticker := time.NewTicker(s.scrapeInterval)
 	defer ticker.Stop()
 
 	// Scrape immediately on start.
-	s.scrape(ctx)
+	s.scrapeAll(ctx)
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			s.scrape(ctx)
+			s.scrapeAll(ctx)
 		}
 	}
 }
 
-func (s *GPUScraper) scrape(ctx context.Context) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.metricsURL, nil)
+// scrapeAll fans scraping of every target out across a bounded worker pool
+// for this tick, and waits for all of them to finish (or be cancelled)
+// before returning, so the next tick starts from a clean slate.
+func (s *GPUScraper) scrapeAll(ctx context.Context) {
+	if len(s.targets) == 0 {
+		return
+	}
+
+	targetChan := make(chan int, len(s.targets))
+	for i := range s.targets {
+		targetChan <- i
+	}
+	close(targetChan)
+
+	workers := s.maxWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(s.targets) {
+		workers = len(s.targets)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range targetChan {
+				s.scrapeOne(ctx, s.targets[i], s.buffers[i])
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *GPUScraper) scrapeOne(ctx context.Context, target ScrapeTarget, buf *scrapeBuffer) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.MetricsURL, nil)
 	if err != nil {
 		return
 	}
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		log.Printf("[gpuscraper] scrape failed: %v", err)
+		log.Printf("[gpuscraper] scrape %s failed: %v", target.PodName, err)
 		return
 	}
 	defer resp.Body.Close()
@@ -146,58 +647,487 @@ func (s *GPUScraper) scrape(ctx context.Context) {
 		return
 	}
 
-	utilization, waiting := parsePrometheusMetrics(resp.Body)
+	families, err := new(expfmt.TextParser).TextToMetricFamilies(resp.Body)
+	if err != nil {
+		log.Printf("[gpuscraper] parse metrics for %s failed: %v", target.PodName, err)
+		return
+	}
+
+	now := time.Now()
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	buf.mu.Lock()
+	defer buf.mu.Unlock()
+
+	if v, ok := firstGaugeValue(families, metricCacheUsage); ok {
+		buf.utilizationSamples = append(buf.utilizationSamples, gaugeSample{ts: now, value: v})
+	}
+	if v, ok := firstGaugeValue(families, metricWaiting); ok {
+		buf.waitingSamples = append(buf.waitingSamples, gaugeSample{ts: now, value: v})
+	}
+	if v, ok := firstGaugeValue(families, metricRunning); ok {
+		buf.runningSamples = append(buf.runningSamples, gaugeSample{ts: now, value: v})
+	}
 
-	if utilization >= 0 {
-		s.utilizationSample = append(s.utilizationSample, utilization)
+	// Missing metrics on a scrape must not corrupt the running histogram —
+	// only fold in a snapshot for a family that was actually present.
+	if snap, ok := firstHistogramSnapshot(families, metricTTFT); ok {
+		buf.ttftHist.observe(snap)
 	}
-	if waiting >= 0 {
-		s.waitingSamples = append(s.waitingSamples, waiting)
+	if snap, ok := firstHistogramSnapshot(families, metricTPOT); ok {
+		buf.tpotHist.observe(snap)
 	}
+	if snap, ok := firstHistogramSnapshot(families, metricE2E); ok {
+		buf.e2eHist.observe(snap)
+	}
+
+	if target.DeviceMetricsURL != "" {
+		s.scrapeDevice(ctx, target, buf)
+	}
+}
+
+// scrapeDevice polls a target's secondary device exporter (DCGM or
+// neuron-monitor) and records one sample per metric family. A node
+// typically exposes one series per accelerator, so multi-accelerator pods
+// are summarized by averaging utilization-style ratios, summing memory, and
+// taking the max temperature.
+func (s *GPUScraper) scrapeDevice(ctx context.Context, target ScrapeTarget, buf *scrapeBuffer) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.DeviceMetricsURL, nil)
+	if err != nil {
+		return
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.Printf("[gpuscraper] device scrape %s failed: %v", target.PodName, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return
+	}
+
+	families, err := new(expfmt.TextParser).TextToMetricFamilies(resp.Body)
+	if err != nil {
+		log.Printf("[gpuscraper] parse device metrics for %s failed: %v", target.PodName, err)
+		return
+	}
+
+	now := time.Now()
+
+	switch target.DeviceKind {
+	case DeviceExporterDCGM:
+		if vals := gaugeFamilyValues(families, metricDCGMSMActive); len(vals) > 0 {
+			buf.smActiveSamples = append(buf.smActiveSamples, gaugeSample{ts: now, value: meanOf(vals)})
+		}
+		if vals := gaugeFamilyValues(families, metricDCGMTensorActive); len(vals) > 0 {
+			buf.tensorActiveSamples = append(buf.tensorActiveSamples, gaugeSample{ts: now, value: meanOf(vals)})
+		}
+		if vals := gaugeFamilyValues(families, metricDCGMPower); len(vals) > 0 {
+			buf.powerSamples = append(buf.powerSamples, gaugeSample{ts: now, value: sumOf(vals)})
+		}
+		if vals := gaugeFamilyValues(families, metricDCGMTemp); len(vals) > 0 {
+			buf.tempSamples = append(buf.tempSamples, gaugeSample{ts: now, value: maxOf(vals)})
+		}
+		if vals := gaugeFamilyValues(families, metricDCGMFBUsed); len(vals) > 0 {
+			// DCGM reports framebuffer usage in MiB; convert to GiB.
+			buf.hbmUsedSamples = append(buf.hbmUsedSamples, gaugeSample{ts: now, value: sumOf(vals) / 1024})
+		}
+		buf.deviceSamples = append(buf.deviceSamples, dcgmDeviceSamples(families, now)...)
+	case DeviceExporterNeuron:
+		if vals := gaugeFamilyValues(families, metricNeuronCoreUtil); len(vals) > 0 {
+			buf.smActiveSamples = append(buf.smActiveSamples, gaugeSample{ts: now, value: meanOf(vals)})
+		}
+		buf.deviceSamples = append(buf.deviceSamples, neuronDeviceSamples(families, now)...)
+	case DeviceExporterTPU:
+		if vals := gaugeFamilyValues(families, metricTPUTensorCoreUtil); len(vals) > 0 {
+			buf.tensorActiveSamples = append(buf.tensorActiveSamples, gaugeSample{ts: now, value: meanOf(vals)})
+		}
+		if vals := gaugeFamilyValues(families, metricTPUHBMUsed); len(vals) > 0 {
+			// libtpu reports HBM usage in bytes; convert to GiB.
+			buf.hbmUsedSamples = append(buf.hbmUsedSamples, gaugeSample{ts: now, value: sumOf(vals) / (1024 * 1024 * 1024)})
+		}
+		buf.deviceSamples = append(buf.deviceSamples, tpuDeviceSamples(families, now)...)
+	case DeviceExporterROCm:
+		if vals := gaugeFamilyValues(families, metricROCmUtil); len(vals) > 0 {
+			buf.smActiveSamples = append(buf.smActiveSamples, gaugeSample{ts: now, value: meanOf(vals) / 100})
+		}
+		if vals := gaugeFamilyValues(families, metricROCmPower); len(vals) > 0 {
+			buf.powerSamples = append(buf.powerSamples, gaugeSample{ts: now, value: sumOf(vals)})
+		}
+		if vals := gaugeFamilyValues(families, metricROCmTemp); len(vals) > 0 {
+			buf.tempSamples = append(buf.tempSamples, gaugeSample{ts: now, value: maxOf(vals)})
+		}
+		if vals := gaugeFamilyValues(families, metricROCmVRAMUsed); len(vals) > 0 {
+			// rocm_smi_exporter reports VRAM usage in bytes; convert to GiB.
+			buf.hbmUsedSamples = append(buf.hbmUsedSamples, gaugeSample{ts: now, value: sumOf(vals) / (1024 * 1024 * 1024)})
+		}
+		buf.deviceSamples = append(buf.deviceSamples, rocmDeviceSamples(families, now)...)
+	}
+}
+
+// dcgmDeviceSamples builds one AcceleratorSample per device index reporting
+// on the DCGM exporter's current scrape, keyed by its "gpu" label.
+func dcgmDeviceSamples(families map[string]*dto.MetricFamily, ts time.Time) []database.AcceleratorSample {
+	smActive := deviceFamilyIndexed(families, metricDCGMSMActive, dcgmDeviceLabel)
+	fbUsed := deviceFamilyIndexed(families, metricDCGMFBUsed, dcgmDeviceLabel)
+	power := deviceFamilyIndexed(families, metricDCGMPower, dcgmDeviceLabel)
+	temp := deviceFamilyIndexed(families, metricDCGMTemp, dcgmDeviceLabel)
+
+	indices := map[int]struct{}{}
+	for _, m := range []map[int]float64{smActive, fbUsed, power, temp} {
+		for idx := range m {
+			indices[idx] = struct{}{}
+		}
+	}
+
+	samples := make([]database.AcceleratorSample, 0, len(indices))
+	for idx := range indices {
+		s := database.AcceleratorSample{Timestamp: ts, DeviceIndex: idx}
+		if v, ok := smActive[idx]; ok {
+			pct := v * 100
+			s.SMUtilPct = &pct
+		}
+		if v, ok := fbUsed[idx]; ok {
+			// DCGM reports framebuffer usage in MiB; convert to GiB.
+			gib := v / 1024
+			s.MemUsedGiB = &gib
+		}
+		if v, ok := power[idx]; ok {
+			pw := v
+			s.PowerW = &pw
+		}
+		if v, ok := temp[idx]; ok {
+			c := v
+			s.TempC = &c
+		}
+		samples = append(samples, s)
+	}
+	return samples
+}
+
+// neuronDeviceSamples builds one AcceleratorSample per NeuronCore index
+// reporting on neuron-monitor's current scrape, keyed by its "neuroncore"
+// label. The HBM metric is best-effort: neuron-monitor's memory series
+// isn't universally present across sidecar versions, so it's left nil
+// rather than failing the whole sample.
+func neuronDeviceSamples(families map[string]*dto.MetricFamily, ts time.Time) []database.AcceleratorSample {
+	util := deviceFamilyIndexed(families, metricNeuronCoreUtil, neuronDeviceLabel)
+	hbmUsed := deviceFamilyIndexed(families, metricNeuronHBMUsed, neuronDeviceLabel)
+
+	indices := map[int]struct{}{}
+	for idx := range util {
+		indices[idx] = struct{}{}
+	}
+	for idx := range hbmUsed {
+		indices[idx] = struct{}{}
+	}
+
+	samples := make([]database.AcceleratorSample, 0, len(indices))
+	for idx := range indices {
+		s := database.AcceleratorSample{Timestamp: ts, DeviceIndex: idx}
+		if v, ok := util[idx]; ok {
+			pct := v * 100
+			s.NeuronCoreUtilPct = &pct
+		}
+		if v, ok := hbmUsed[idx]; ok {
+			// neuron-monitor reports memory in bytes; convert to GiB.
+			gib := v / (1024 * 1024 * 1024)
+			s.HBMUsedGiB = &gib
+		}
+		samples = append(samples, s)
+	}
+	return samples
 }
 
-// parsePrometheusMetrics does a simple line-by-line parse of Prometheus
-// text format to extract vllm:gpu_cache_usage_perc and
-// vllm:num_requests_waiting. Returns -1 for values not found.
-func parsePrometheusMetrics(r io.Reader) (utilization float64, waiting int) {
-	utilization = -1
-	waiting = -1
+// tpuDeviceSamples builds one AcceleratorSample per TPU core index
+// reporting on libtpu's current scrape, keyed by its "core" label.
+func tpuDeviceSamples(families map[string]*dto.MetricFamily, ts time.Time) []database.AcceleratorSample {
+	util := deviceFamilyIndexed(families, metricTPUTensorCoreUtil, tpuDeviceLabel)
+	hbmUsed := deviceFamilyIndexed(families, metricTPUHBMUsed, tpuDeviceLabel)
+
+	indices := map[int]struct{}{}
+	for idx := range util {
+		indices[idx] = struct{}{}
+	}
+	for idx := range hbmUsed {
+		indices[idx] = struct{}{}
+	}
+
+	samples := make([]database.AcceleratorSample, 0, len(indices))
+	for idx := range indices {
+		s := database.AcceleratorSample{Timestamp: ts, DeviceIndex: idx}
+		if v, ok := util[idx]; ok {
+			pct := v * 100
+			s.SMUtilPct = &pct
+		}
+		if v, ok := hbmUsed[idx]; ok {
+			// libtpu reports HBM usage in bytes; convert to GiB.
+			gib := v / (1024 * 1024 * 1024)
+			s.MemUsedGiB = &gib
+		}
+		samples = append(samples, s)
+	}
+	return samples
+}
+
+// rocmDeviceSamples builds one AcceleratorSample per GPU card index
+// reporting on rocm_smi_exporter's current scrape, keyed by its "card"
+// label.
+func rocmDeviceSamples(families map[string]*dto.MetricFamily, ts time.Time) []database.AcceleratorSample {
+	util := deviceFamilyIndexed(families, metricROCmUtil, rocmDeviceLabel)
+	vramUsed := deviceFamilyIndexed(families, metricROCmVRAMUsed, rocmDeviceLabel)
+	power := deviceFamilyIndexed(families, metricROCmPower, rocmDeviceLabel)
+	temp := deviceFamilyIndexed(families, metricROCmTemp, rocmDeviceLabel)
+
+	indices := map[int]struct{}{}
+	for _, m := range []map[int]float64{util, vramUsed, power, temp} {
+		for idx := range m {
+			indices[idx] = struct{}{}
+		}
+	}
+
+	samples := make([]database.AcceleratorSample, 0, len(indices))
+	for idx := range indices {
+		s := database.AcceleratorSample{Timestamp: ts, DeviceIndex: idx}
+		if v, ok := util[idx]; ok {
+			s.SMUtilPct = &v
+		}
+		if v, ok := vramUsed[idx]; ok {
+			// rocm_smi_exporter reports VRAM usage in bytes; convert to GiB.
+			gib := v / (1024 * 1024 * 1024)
+			s.MemUsedGiB = &gib
+		}
+		if v, ok := power[idx]; ok {
+			s.PowerW = &v
+		}
+		if v, ok := temp[idx]; ok {
+			s.TempC = &v
+		}
+		samples = append(samples, s)
+	}
+	return samples
+}
 
-	scanner := bufio.NewScanner(r)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, "#") {
+// deviceFamilyIndexed is like gaugeFamilyValues, but keys each value by its
+// device index instead of collapsing them: the value of labelName (e.g.
+// "gpu" for DCGM, "neuroncore" for neuron-monitor) when the series carries
+// it, falling back to the metric's positional index within the family when
+// it doesn't.
+func deviceFamilyIndexed(families map[string]*dto.MetricFamily, name, labelName string) map[int]float64 {
+	fam, ok := families[name]
+	if !ok {
+		return nil
+	}
+	out := make(map[int]float64, len(fam.Metric))
+	for i, mt := range fam.Metric {
+		var v float64
+		switch {
+		case mt.Gauge != nil && mt.Gauge.Value != nil:
+			v = *mt.Gauge.Value
+		case mt.Untyped != nil && mt.Untyped.Value != nil:
+			v = *mt.Untyped.Value
+		default:
 			continue
 		}
 
-		// vLLM exposes these metrics with possible label suffixes.
-		// Match the metric name prefix.
-		if strings.HasPrefix(line, "vllm:gpu_cache_usage_perc") {
-			if v, err := parsePromValue(line); err == nil {
-				utilization = v
-			}
-		} else if strings.HasPrefix(line, "vllm:num_requests_waiting") {
-			if v, err := parsePromValue(line); err == nil {
-				waiting = int(v)
+		idx := i
+		for _, lp := range mt.Label {
+			if lp.GetName() == labelName {
+				if n, err := strconv.Atoi(lp.GetValue()); err == nil {
+					idx = n
+				}
+				break
 			}
 		}
+		out[idx] = v
+	}
+	return out
+}
+
+// firstGaugeValue returns the value of the first metric in the named family.
+// vLLM exposes these as unlabeled (or single-label) gauges; taking the first
+// sample is sufficient for fleet-wide scraping of a single pod.
+func firstGaugeValue(families map[string]*dto.MetricFamily, name string) (float64, bool) {
+	fam, ok := families[name]
+	if !ok || len(fam.Metric) == 0 {
+		return 0, false
+	}
+	mt := fam.Metric[0]
+	if mt.Gauge != nil && mt.Gauge.Value != nil {
+		return *mt.Gauge.Value, true
+	}
+	if mt.Untyped != nil && mt.Untyped.Value != nil {
+		return *mt.Untyped.Value, true
+	}
+	return 0, false
+}
+
+// firstHistogramSnapshot extracts the cumulative bucket state of the first
+// metric in the named histogram family, sorted ascending by upper bound.
+func firstHistogramSnapshot(families map[string]*dto.MetricFamily, name string) (*bucketSnapshot, bool) {
+	fam, ok := families[name]
+	if !ok || len(fam.Metric) == 0 {
+		return nil, false
+	}
+	hist := fam.Metric[0].Histogram
+	if hist == nil || len(hist.Bucket) == 0 {
+		return nil, false
+	}
+
+	buckets := make([]*dto.Bucket, len(hist.Bucket))
+	copy(buckets, hist.Bucket)
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].GetUpperBound() < buckets[j].GetUpperBound()
+	})
+
+	snap := &bucketSnapshot{
+		upperBounds: make([]float64, len(buckets)),
+		counts:      make([]uint64, len(buckets)),
+	}
+	for i, b := range buckets {
+		snap.upperBounds[i] = b.GetUpperBound()
+		snap.counts[i] = b.GetCumulativeCount()
+	}
+	return snap, true
+}
+
+// gaugeFamilyValues returns the value of every metric in the named family,
+// unlike firstGaugeValue — device exporters report one series per
+// accelerator, and all of them are needed to summarize a multi-GPU node.
+func gaugeFamilyValues(families map[string]*dto.MetricFamily, name string) []float64 {
+	fam, ok := families[name]
+	if !ok {
+		return nil
+	}
+	vals := make([]float64, 0, len(fam.Metric))
+	for _, mt := range fam.Metric {
+		if mt.Gauge != nil && mt.Gauge.Value != nil {
+			vals = append(vals, *mt.Gauge.Value)
+		} else if mt.Untyped != nil && mt.Untyped.Value != nil {
+			vals = append(vals, *mt.Untyped.Value)
+		}
+	}
+	return vals
+}
+
+func meanOf(vals []float64) float64 {
+	return sumOf(vals) / float64(len(vals))
+}
+
+func sumOf(vals []float64) float64 {
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	return sum
+}
+
+func maxOf(vals []float64) float64 {
+	max := vals[0]
+	for _, v := range vals[1:] {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+// peakPct returns the peak of a 0-1 ratio gauge series as a percentage.
+func peakPct(samples []gaugeSample) *float64 {
+	v := peakValue(samples)
+	if v == nil {
+		return nil
+	}
+	pct := *v * 100
+	return &pct
+}
+
+// peakValue returns the peak value of a gauge series, or nil if empty.
+func peakValue(samples []gaugeSample) *float64 {
+	if len(samples) == 0 {
+		return nil
+	}
+	peak := samples[0].value
+	for _, s := range samples[1:] {
+		if s.value > peak {
+			peak = s.value
+		}
+	}
+	return &peak
+}
+
+// avgValue returns the mean value of a gauge series, or nil if empty.
+func avgValue(samples []gaugeSample) *float64 {
+	if len(samples) == 0 {
+		return nil
+	}
+	var sum float64
+	for _, s := range samples {
+		sum += s.value
+	}
+	avg := sum / float64(len(samples))
+	return &avg
+}
+
+func gaugeValues(samples []gaugeSample) []float64 {
+	vals := make([]float64, len(samples))
+	for i, s := range samples {
+		vals[i] = s.value
+	}
+	return vals
+}
+
+// gaugePercentiles computes p50/p90/p95/p99 from a gauge time series.
+func gaugePercentiles(vals []float64) (p50, p90, p95, p99 *float64) {
+	if len(vals) == 0 {
+		return nil, nil, nil, nil
+	}
+	sorted := make([]float64, len(vals))
+	copy(sorted, vals)
+	sort.Float64s(sorted)
+
+	p50v := nearestRank(sorted, 50)
+	p90v := nearestRank(sorted, 90)
+	p95v := nearestRank(sorted, 95)
+	p99v := nearestRank(sorted, 99)
+	return &p50v, &p90v, &p95v, &p99v
+}
+
+// gaugePercentilesPct is like gaugePercentiles but converts the vLLM 0.0-1.0
+// cache utilization ratio to a percentage.
+func gaugePercentilesPct(vals []float64) (p50, p90, p95, p99 *float64) {
+	p50, p90, p95, p99 = gaugePercentiles(vals)
+	for _, p := range []**float64{&p50, &p90, &p95, &p99} {
+		if *p != nil {
+			v := **p * 100
+			*p = &v
+		}
 	}
-	return utilization, waiting
+	return
 }
 
-// parsePromValue extracts the float64 value from a Prometheus text line.
-// The value is the last space-separated field (ignoring optional timestamp).
-func parsePromValue(line string) (float64, error) {
-	// Format: metric_name{labels} value [timestamp]
-	// or:     metric_name value [timestamp]
-	fields := strings.Fields(line)
-	if len(fields) < 2 {
-		return 0, fmt.Errorf("too few fields")
+func nearestRank(sorted []float64, p float64) float64 {
+	rank := (p / 100.0) * float64(len(sorted))
+	var idx int
+	if rank == math.Trunc(rank) {
+		// Exact integer rank: nearest-rank indexes from 1, so step back
+		// one to land on the right element (rank 0 stays at the first).
+		idx = int(rank)
+		if idx > 0 {
+			idx--
+		}
+	} else {
+		// Fractional rank: the covering element is the one at or after
+		// rank, i.e. ceil(rank), again stepped back to a 0-based index.
+		idx = int(math.Ceil(rank)) - 1
+	}
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
 	}
-	// The value is always the second-to-last or last field.
-	// Try the field right after the metric name (index 1).
-	return strconv.ParseFloat(fields[len(fields)-1], 64)
+	return sorted[idx]
 }
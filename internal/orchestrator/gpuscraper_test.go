@@ -0,0 +1,118 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogramAccumulator_PercentileFromDeltas(t *testing.T) {
+	var h histogramAccumulator
+
+	// First snapshot establishes the baseline — no delta recorded.
+	h.observe(&bucketSnapshot{
+		upperBounds: []float64{0.1, 0.5, 1.0, 2.5},
+		counts:      []uint64{10, 20, 25, 25},
+	})
+	if p := h.percentile(50); p != nil {
+		t.Fatalf("expected nil percentile before any interval delta, got %v", *p)
+	}
+
+	// Second snapshot: +15 observations, mostly in the 0.5 bucket.
+	h.observe(&bucketSnapshot{
+		upperBounds: []float64{0.1, 0.5, 1.0, 2.5},
+		counts:      []uint64{10, 30, 35, 35},
+	})
+
+	p50 := h.percentile(50)
+	if p50 == nil {
+		t.Fatal("expected non-nil p50")
+	}
+	if *p50 < 0.1 || *p50 > 0.5 {
+		t.Errorf("p50 = %v, want in (0.1, 0.5]", *p50)
+	}
+}
+
+func TestHistogramAccumulator_CounterResetReBaselines(t *testing.T) {
+	var h histogramAccumulator
+
+	h.observe(&bucketSnapshot{upperBounds: []float64{1.0}, counts: []uint64{100}})
+	h.observe(&bucketSnapshot{upperBounds: []float64{1.0}, counts: []uint64{120}})
+	if got := h.deltaCounts[0]; got != 20 {
+		t.Fatalf("deltaCounts = %d, want 20", got)
+	}
+
+	// Process restarted — counts drop below the last snapshot.
+	h.observe(&bucketSnapshot{upperBounds: []float64{1.0}, counts: []uint64{5}})
+	if got := h.deltaCounts[0]; got != 20 {
+		t.Errorf("deltaCounts after reset = %d, want unchanged 20", got)
+	}
+
+	h.observe(&bucketSnapshot{upperBounds: []float64{1.0}, counts: []uint64{15}})
+	if got := h.deltaCounts[0]; got != 30 {
+		t.Errorf("deltaCounts after post-reset interval = %d, want 30", got)
+	}
+}
+
+func TestGaugePercentiles_Empty(t *testing.T) {
+	p50, p90, p95, p99 := gaugePercentiles(nil)
+	if p50 != nil || p90 != nil || p95 != nil || p99 != nil {
+		t.Error("expected all nil percentiles for empty input")
+	}
+}
+
+func TestMergeHistogram_SumsDeltaCountsAcrossRanks(t *testing.T) {
+	var rank0, rank1, merged histogramAccumulator
+
+	rank0.observe(&bucketSnapshot{upperBounds: []float64{1.0}, counts: []uint64{0}})
+	rank0.observe(&bucketSnapshot{upperBounds: []float64{1.0}, counts: []uint64{10}})
+
+	rank1.observe(&bucketSnapshot{upperBounds: []float64{1.0}, counts: []uint64{0}})
+	rank1.observe(&bucketSnapshot{upperBounds: []float64{1.0}, counts: []uint64{5}})
+
+	mergeHistogram(&merged, &rank0)
+	mergeHistogram(&merged, &rank1)
+
+	if got := merged.deltaCounts[0]; got != 15 {
+		t.Errorf("merged deltaCounts = %d, want 15", got)
+	}
+}
+
+func TestAggregateBuffer_EmptyReturnsNil(t *testing.T) {
+	buf := &scrapeBuffer{}
+	if m := aggregateBuffer(buf, 80); m != nil {
+		t.Errorf("expected nil for empty buffer, got %+v", m)
+	}
+}
+
+func TestAggregateBuffer_ComputesPeakAndAvg(t *testing.T) {
+	now := time.Unix(0, 0)
+	buf := &scrapeBuffer{
+		utilizationSamples: []gaugeSample{
+			{ts: now, value: 0.2},
+			{ts: now, value: 0.8},
+		},
+	}
+	m := aggregateBuffer(buf, 80)
+	if m == nil {
+		t.Fatal("expected non-nil metrics")
+	}
+	if m.UtilizationPeakPct != 80 {
+		t.Errorf("UtilizationPeakPct = %v, want 80", m.UtilizationPeakPct)
+	}
+	if m.UtilizationAvgPct != 50 {
+		t.Errorf("UtilizationAvgPct = %v, want 50", m.UtilizationAvgPct)
+	}
+	if m.MemoryPeakGiB != 64 {
+		t.Errorf("MemoryPeakGiB = %v, want 64 (0.8 * 80)", m.MemoryPeakGiB)
+	}
+}
+
+func TestGaugePercentilesPct_ConvertsRatioToPercentage(t *testing.T) {
+	p50, _, _, p99 := gaugePercentilesPct([]float64{0.1, 0.2, 0.3, 0.9})
+	if p50 == nil || p99 == nil {
+		t.Fatal("expected non-nil percentiles")
+	}
+	if *p99 != 90 {
+		t.Errorf("p99 = %v, want 90 (0.9 * 100)", *p99)
+	}
+}
@@ -1,9 +1,11 @@
 package orchestrator
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -12,14 +14,17 @@ import (
 	"sync"
 	"time"
 
+	"github.com/accelbench/accelbench/internal/collector"
 	"github.com/accelbench/accelbench/internal/database"
 	"github.com/accelbench/accelbench/internal/manifest"
 	"github.com/accelbench/accelbench/internal/metrics"
+	"github.com/accelbench/accelbench/internal/pricing"
 
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/kubernetes"
 )
@@ -30,6 +35,22 @@ const (
 	jobTimeout       = 2 * time.Hour
 	jobPoll          = 15 * time.Second
 	defaultNamespace = "accelbench"
+
+	// defaultResumeOrphanTTL is how long Resume will keep waiting on a
+	// non-terminal run's loadgen Job before giving up on it: past this age
+	// (measured from BenchmarkRun.StartedAt), a Job that still hasn't
+	// reached a terminal condition is treated as stuck rather than merely
+	// slow, and Resume tears it down instead of waiting out the rest of
+	// jobTimeout a second time.
+	defaultResumeOrphanTTL = 30 * time.Minute
+
+	// fieldManager identifies the orchestrator as the owner of fields it
+	// applies via server-side apply (see applyYAML), so Kubernetes can
+	// tell its applies apart from any other actor touching the same
+	// objects and two concurrent reruns of the same benchmark, or a rerun
+	// of a previously-failed one, co-own fields instead of one of them
+	// erroring out with AlreadyExists against a naive Create.
+	fieldManager = "accelbench-orchestrator"
 )
 
 // RunConfig holds everything needed to execute a benchmark run.
@@ -38,23 +59,234 @@ type RunConfig struct {
 	Model        *database.Model
 	InstanceType *database.InstanceType
 	Request      *database.RunRequest
+
+	// Replicas is the Deployment's initial replica count; 0 (the zero value
+	// every other Execute caller leaves it at) defaults to 1. Only
+	// ExecuteAutoscale sets this above 1 as a ramp scales up.
+	Replicas int
+}
+
+// PollConfig controls the cadence of a wait loop. Polling starts at
+// InitialInterval and backs off by Multiplier after every failed attempt,
+// capped at MaxInterval, until Deadline elapses. A Multiplier of 1 polls at
+// a fixed InitialInterval.
+type PollConfig struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	Deadline        time.Duration
+}
+
+// ReadinessProbe reports whether the model deployment named name in
+// namespace ns is ready to accept load. The default probe delegates to a
+// ReadyChecker; callers can substitute an HTTP check against the
+// framework's /health endpoint or a warm-up prompt whose TTFT must fall
+// below a threshold before load generation starts.
+type ReadinessProbe func(ctx context.Context, ns, name string) (bool, error)
+
+// errPollTimeout signals that a pollWithBackoff loop ran out its Deadline.
+var errPollTimeout = errors.New("poll deadline exceeded")
+
+// durationBuckets are the histogram buckets (seconds) used for every
+// orchestrator phase-duration metric; phases here run from tens of seconds
+// to tens of minutes, not milliseconds, so the default client_golang
+// buckets don't fit.
+var durationBuckets = []float64{5, 15, 30, 60, 120, 300, 600, 1200, 1800, 3600}
+
+// k8sCallBuckets are the histogram buckets (seconds) for individual
+// Kubernetes API calls, which complete in tens to low hundreds of
+// milliseconds under normal apiserver load — much finer-grained than
+// durationBuckets, which times whole lifecycle phases.
+var k8sCallBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// orchestratorMetrics holds every instrument the orchestrator emits,
+// created against an injected metrics.Registry so multiple Orchestrators
+// in one process don't collide on metric names and tests can assert on a
+// metrics.NoopRegistry-free instance instead.
+type orchestratorMetrics struct {
+	deployDuration    metrics.HistogramVec // instance_type, framework
+	readyWaitDuration metrics.HistogramVec // instance_type, framework
+	loadgenDuration   metrics.HistogramVec // instance_type, framework
+	teardownTotal     metrics.CounterVec   // result
+	phaseErrors       metrics.CounterVec   // run_id, instance_type, framework, phase
+	k8sCallDuration   metrics.HistogramVec // verb, resource
+}
+
+func newOrchestratorMetrics(reg metrics.Registry) *orchestratorMetrics {
+	return &orchestratorMetrics{
+		deployDuration: reg.Histogram("accelbench_orchestrator_deploy_duration_seconds",
+			"Time to deploy the model Deployment and Service.", durationBuckets, "instance_type", "framework"),
+		readyWaitDuration: reg.Histogram("accelbench_orchestrator_ready_wait_duration_seconds",
+			"Time spent waiting for the model deployment to become ready.", durationBuckets, "instance_type", "framework"),
+		loadgenDuration: reg.Histogram("accelbench_orchestrator_loadgen_duration_seconds",
+			"Wall-clock time of the load generator job.", durationBuckets, "instance_type", "framework"),
+		teardownTotal: reg.Counter("accelbench_orchestrator_teardown_total",
+			"Count of teardown attempts by result.", "result"),
+		phaseErrors: reg.Counter("accelbench_orchestrator_phase_errors_total",
+			"Count of lifecycle phase errors.", "run_id", "instance_type", "framework", "phase"),
+		k8sCallDuration: reg.Histogram("accelbench_orchestrator_k8s_api_duration_seconds",
+			"Latency of individual Kubernetes API calls the orchestrator makes.", k8sCallBuckets, "verb", "resource"),
+	}
 }
 
+// RunObserver receives lifecycle notifications from Execute for
+// control-plane metrics owned by the caller (typically the API server),
+// so the orchestrator package doesn't need to know about
+// accelbench_runs_total or accelbench_active_runs.
+type RunObserver interface {
+	// RunStarted is called once Execute begins running a benchmark.
+	RunStarted()
+	// RunFinished is called once Execute returns, with "completed" or
+	// "failed" and how long the run took end to end.
+	RunFinished(outcome string, d time.Duration)
+}
+
+// noopRunObserver is the default RunObserver: it discards every event.
+type noopRunObserver struct{}
+
+func (noopRunObserver) RunStarted()                       {}
+func (noopRunObserver) RunFinished(string, time.Duration) {}
+
 // Orchestrator manages the benchmark lifecycle.
 type Orchestrator struct {
 	client  kubernetes.Interface
 	repo    database.Repo
 	mu      sync.Mutex
 	cancels map[string]context.CancelFunc // runID → cancel
+
+	readinessPoll   PollConfig
+	jobPoll         PollConfig
+	readinessProbe  ReadinessProbe
+	readyChecker    *ReadyChecker
+	metrics         *orchestratorMetrics
+	observer        RunObserver
+	pricingProvider pricing.Provider
+	pricingSource   string
+	resumeOrphanTTL time.Duration
+}
+
+// Option configures optional Orchestrator behavior at construction time.
+type Option func(*Orchestrator)
+
+// WithReadinessPoll overrides the default readiness poll cadence.
+func WithReadinessPoll(cfg PollConfig) Option {
+	return func(o *Orchestrator) { o.readinessPoll = cfg }
+}
+
+// WithJobPoll overrides the default loadgen job poll cadence.
+func WithJobPoll(cfg PollConfig) Option {
+	return func(o *Orchestrator) { o.jobPoll = cfg }
+}
+
+// WithReadinessProbe overrides the default deployment-ready check.
+func WithReadinessProbe(probe ReadinessProbe) Option {
+	return func(o *Orchestrator) { o.readinessProbe = probe }
+}
+
+// WithMetricsRegistry overrides the default Prometheus-backed metrics
+// registry, e.g. with metrics.NewNoopRegistry() in tests or a shared
+// registry when running one Orchestrator per cluster in a single process.
+func WithMetricsRegistry(reg metrics.Registry) Option {
+	return func(o *Orchestrator) { o.metrics = newOrchestratorMetrics(reg) }
+}
+
+// WithRunObserver overrides the default no-op RunObserver, so a caller can
+// learn when Execute starts and how each run ends without polling the repo.
+func WithRunObserver(obs RunObserver) Option {
+	return func(o *Orchestrator) { o.observer = obs }
+}
+
+// WithPricingProvider configures the rate source Execute queries at
+// run-completion time to stamp a cost efficiency snapshot onto
+// BenchmarkMetrics (see metrics.PricingContext). source is recorded
+// alongside the snapshot (e.g. "aws", "static") so a stored cost can
+// later be told apart from one derived from a live rate (see
+// database.CatalogEntry.CostDeltaPct). Left nil by default, which leaves
+// the cost columns unpopulated rather than failing the run.
+func WithPricingProvider(source string, p pricing.Provider) Option {
+	return func(o *Orchestrator) {
+		o.pricingProvider = p
+		o.pricingSource = source
+	}
+}
+
+// WithResumeOrphanTTL overrides defaultResumeOrphanTTL, the age (from
+// BenchmarkRun.StartedAt) past which Resume gives up waiting on a
+// non-terminal run's loadgen Job and tears it down instead.
+func WithResumeOrphanTTL(d time.Duration) Option {
+	return func(o *Orchestrator) { o.resumeOrphanTTL = d }
 }
 
 // New creates a new Orchestrator.
-func New(client kubernetes.Interface, repo database.Repo) *Orchestrator {
-	return &Orchestrator{
+func New(client kubernetes.Interface, repo database.Repo, opts ...Option) *Orchestrator {
+	o := &Orchestrator{
 		client:  client,
 		repo:    repo,
 		cancels: make(map[string]context.CancelFunc),
+		readinessPoll: PollConfig{
+			InitialInterval: readinessPoll,
+			MaxInterval:     readinessPoll,
+			Multiplier:      1,
+			Deadline:        readinessTimeout,
+		},
+		jobPoll: PollConfig{
+			InitialInterval: jobPoll,
+			MaxInterval:     jobPoll,
+			Multiplier:      1,
+			Deadline:        jobTimeout,
+		},
+		metrics:         newOrchestratorMetrics(metrics.NewPrometheusRegistry()),
+		observer:        noopRunObserver{},
+		resumeOrphanTTL: defaultResumeOrphanTTL,
+	}
+	o.readyChecker = newReadyChecker(client)
+	o.readinessProbe = o.defaultReadinessProbe
+	for _, opt := range opts {
+		opt(o)
 	}
+	return o
+}
+
+// defaultReadinessProbe is the default ReadinessProbe: it delegates to
+// readyChecker's Deployment check (ObservedGeneration caught up plus the
+// Available condition true), tracked as a k8s API call like every other
+// client-go call the orchestrator makes.
+func (o *Orchestrator) defaultReadinessProbe(ctx context.Context, ns, name string) (bool, error) {
+	var ready bool
+	err := o.trackK8sCall("get", "deployment", func() error {
+		var checkErr error
+		ready, checkErr = o.readyChecker.deploymentReady(ctx, ns, name)
+		return checkErr
+	})
+	return ready, err
+}
+
+// pollWithBackoff calls check until it reports ready, returns an error, or
+// cfg.Deadline elapses, backing off from cfg.InitialInterval toward
+// cfg.MaxInterval by cfg.Multiplier between attempts.
+func pollWithBackoff(ctx context.Context, cfg PollConfig, check func(context.Context) (bool, error)) error {
+	deadline := time.Now().Add(cfg.Deadline)
+	interval := cfg.InitialInterval
+	for time.Now().Before(deadline) {
+		ready, err := check(ctx)
+		if err != nil {
+			return err
+		}
+		if ready {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		if next := time.Duration(float64(interval) * cfg.Multiplier); next < cfg.MaxInterval {
+			interval = next
+		} else {
+			interval = cfg.MaxInterval
+		}
+	}
+	return errPollTimeout
 }
 
 // CancelRun cancels a running benchmark by its run ID. Returns true if
@@ -70,7 +302,7 @@ func (o *Orchestrator) CancelRun(runID string) bool {
 }
 
 // Execute runs the full benchmark lifecycle: deploy → ready → loadgen → collect → persist → teardown.
-func (o *Orchestrator) Execute(ctx context.Context, cfg RunConfig) error {
+func (o *Orchestrator) Execute(ctx context.Context, cfg RunConfig) (err error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -84,72 +316,132 @@ func (o *Orchestrator) Execute(ctx context.Context, cfg RunConfig) error {
 		o.mu.Unlock()
 	}()
 
+	start := time.Now()
+	o.observer.RunStarted()
+	defer func() {
+		outcome := "completed"
+		if err != nil {
+			outcome = "failed"
+		}
+		o.observer.RunFinished(outcome, time.Since(start))
+	}()
+
 	ns := defaultNamespace
 	modelName := fmt.Sprintf("bench-%s", cfg.RunID[:8])
 	loadgenName := fmt.Sprintf("loadgen-%s", cfg.RunID[:8])
+	instanceType := cfg.InstanceType.Name
+	framework := cfg.Request.Framework
 
 	// Phase 1: Mark run as running.
 	if err := o.repo.UpdateRunStatus(ctx, cfg.RunID, "running"); err != nil {
 		return fmt.Errorf("update status to running: %w", err)
 	}
+	if err := o.repo.SetTeardownPending(ctx, cfg.RunID, true); err != nil {
+		return fmt.Errorf("set teardown pending: %w", err)
+	}
 
 	// Ensure teardown happens regardless of outcome.
-	defer o.teardown(context.Background(), ns, modelName, loadgenName)
+	defer o.teardown(context.Background(), cfg.RunID, ns, modelName, loadgenName)
 
 	// Phase 2: Deploy model Deployment + Service.
 	log.Printf("[%s] deploying model %s on %s", cfg.RunID[:8], cfg.Request.ModelHfID, cfg.Request.InstanceTypeName)
-	if err := o.deployModel(ctx, ns, modelName, cfg); err != nil {
-		o.markFailed(ctx, cfg.RunID)
-		return fmt.Errorf("deploy model: %w", err)
+	deployStart := time.Now()
+	deployResources, deployErr := o.deployModel(ctx, ns, modelName, cfg)
+	o.metrics.deployDuration.WithLabelValues(instanceType, framework).Observe(time.Since(deployStart).Seconds())
+	if deployErr != nil {
+		o.markFailed(ctx, cfg.RunID, instanceType, framework, "deploy", o.podFailureReason(ctx, ns, "app="+modelName))
+		return fmt.Errorf("deploy model: %w", deployErr)
+	}
+	if err := o.repo.UpdateRunPhase(ctx, cfg.RunID, "deploying", deployResources); err != nil {
+		return fmt.Errorf("update run phase to deploying: %w", err)
 	}
 
 	// Phase 3: Wait for readiness.
 	log.Printf("[%s] waiting for model readiness", cfg.RunID[:8])
-	if err := o.waitForReady(ctx, ns, modelName); err != nil {
-		o.markFailed(ctx, cfg.RunID)
-		return fmt.Errorf("wait for readiness: %w", err)
+	readyStart := time.Now()
+	readyErr := o.waitForReady(ctx, ns, modelName)
+	o.metrics.readyWaitDuration.WithLabelValues(instanceType, framework).Observe(time.Since(readyStart).Seconds())
+	if readyErr != nil {
+		reason := o.podFailureReason(ctx, ns, "app="+modelName)
+		o.markFailed(ctx, cfg.RunID, instanceType, framework, "ready", reason)
+		if reason != "" {
+			return fmt.Errorf("wait for readiness: %w (%s)", readyErr, reason)
+		}
+		return fmt.Errorf("wait for readiness: %w", readyErr)
+	}
+	if err := o.repo.UpdateRunPhase(ctx, cfg.RunID, "ready", nil); err != nil {
+		return fmt.Errorf("update run phase to ready: %w", err)
 	}
 
-	// Start GPU scraper for GPU instances (non-fatal if it fails).
-	var gpuScraper *GPUScraper
-	if strings.EqualFold(cfg.InstanceType.AcceleratorType, "gpu") {
-		totalMemGiB := float64(cfg.InstanceType.AcceleratorMemoryGiB)
-		gpuScraper = NewGPUScraper(modelName, 8000, totalMemGiB)
+	// Start the accelerator scraper for GPU/Neuron/TPU/ROCm instances
+	// (non-fatal if it fails). A single vLLM Deployment today means a
+	// single scrape target, but the scraper itself is built for a fleet so
+	// adding replica-per-rank deployments later only means constructing
+	// more targets here.
+	var gpuScraper AcceleratorScraper
+	if s := NewAcceleratorScraperForInstance(cfg.InstanceType, modelName); s != nil {
+		gpuScraper = s
 		gpuScraper.Start(ctx)
-		log.Printf("[%s] started GPU metrics scraper", cfg.RunID[:8])
+		log.Printf("[%s] started accelerator metrics scraper (%s)", cfg.RunID[:8], gpuScraper.Kind())
 	}
 
 	// Phase 4: Launch load generator Job.
 	log.Printf("[%s] launching load generator", cfg.RunID[:8])
-	if err := o.launchLoadgen(ctx, ns, loadgenName, modelName, cfg); err != nil {
+	loadgenResources, err := o.launchLoadgen(ctx, ns, loadgenName, modelName, cfg)
+	if err != nil {
 		if gpuScraper != nil {
 			gpuScraper.Stop()
 		}
-		o.markFailed(ctx, cfg.RunID)
+		o.markFailed(ctx, cfg.RunID, instanceType, framework, "launch_loadgen", "")
 		return fmt.Errorf("launch loadgen: %w", err)
 	}
+	if err := o.repo.UpdateRunPhase(ctx, cfg.RunID, "loadgen_running", loadgenResources); err != nil {
+		return fmt.Errorf("update run phase to loadgen_running: %w", err)
+	}
+
+	// Phase 5: Wait for Job completion and collect results. Tail the
+	// loadgen pod's logs concurrently so watchers get lines, partial
+	// metrics, and progress as they're produced instead of only the full
+	// blob readJobLogs collects once the Job finishes; the tail is
+	// best-effort and never fails the run.
+	tailCtx, stopTail := context.WithCancel(ctx)
+	progress := &loadgenProgress{}
+	go o.tailLoadgenLogs(tailCtx, ns, loadgenName, cfg.RunID, progress)
 
-	// Phase 5: Wait for Job completion and collect results.
 	log.Printf("[%s] waiting for load generator completion", cfg.RunID[:8])
+	loadgenStart := time.Now()
 	logData, err := o.waitAndCollect(ctx, ns, loadgenName)
+	stopTail()
+	o.metrics.loadgenDuration.WithLabelValues(instanceType, framework).Observe(time.Since(loadgenStart).Seconds())
 
 	// Stop GPU scraper and collect metrics (before checking loadgen error).
 	var gpuMetrics *GPUMetrics
 	if gpuScraper != nil {
 		gpuMetrics = gpuScraper.Stop()
 		if gpuMetrics != nil {
-			log.Printf("[%s] GPU metrics: utilization_peak=%.1f%% avg=%.1f%% mem_peak=%.1fGiB waiting_max=%d",
+			smActive := "n/a"
+			if gpuMetrics.SMActivePeakPct != nil {
+				smActive = fmt.Sprintf("%.1f%%", *gpuMetrics.SMActivePeakPct)
+			}
+			log.Printf("[%s] GPU metrics: utilization_peak=%.1f%% avg=%.1f%% mem_peak=%.1fGiB waiting_max=%d running_max=%d sm_active_peak=%s",
 				cfg.RunID[:8], gpuMetrics.UtilizationPeakPct, gpuMetrics.UtilizationAvgPct,
-				gpuMetrics.MemoryPeakGiB, gpuMetrics.WaitingRequestsMax)
+				gpuMetrics.MemoryPeakGiB, gpuMetrics.WaitingRequestsMax, gpuMetrics.RunningRequestsMax, smActive)
 		} else {
 			log.Printf("[%s] GPU scraper collected no samples", cfg.RunID[:8])
 		}
 	}
 
 	if err != nil {
-		o.markFailed(ctx, cfg.RunID)
+		if snap, requestsSeen := progress.get(); snap != nil {
+			log.Printf("[%s] loadgen failed after %d observed requests; last partial metrics: ttft_p50=%s throughput_per_request=%s",
+				cfg.RunID[:8], requestsSeen, derefF64(snap.TTFTP50Ms), derefF64(snap.ThroughputPerRequestTPS))
+		}
+		o.markFailed(ctx, cfg.RunID, instanceType, framework, "collect", o.podFailureReason(ctx, ns, "job-name="+loadgenName))
 		return fmt.Errorf("collect results: %w", err)
 	}
+	if err := o.repo.UpdateRunPhase(ctx, cfg.RunID, "collecting", nil); err != nil {
+		return fmt.Errorf("update run phase to collecting: %w", err)
+	}
 
 	// Phase 6: Parse metrics and persist.
 	log.Printf("[%s] collected %d bytes of loadgen output", cfg.RunID[:8], len(logData))
@@ -162,23 +454,76 @@ func (o *Orchestrator) Execute(ctx context.Context, cfg RunConfig) error {
 			snippet = append(snippet, logData[len(logData)-250:]...)
 		}
 		log.Printf("[%s] parse failed: %v\nlog snippet:\n%s", cfg.RunID[:8], err, snippet)
-		o.markFailed(ctx, cfg.RunID)
+		o.markFailed(ctx, cfg.RunID, instanceType, framework, "parse", "")
 		return fmt.Errorf("parse loadgen output: %w", err)
 	}
 
-	computed := metrics.ComputeMetrics(output)
+	computed := metrics.ComputeMetrics(output, o.resolvePricingContext(ctx, cfg.InstanceType.Name))
 	computed.RunID = cfg.RunID
 
 	// Merge GPU scraper metrics into computed metrics.
 	if gpuMetrics != nil {
+		computed.AcceleratorKind = gpuScraper.Kind()
 		computed.AcceleratorUtilizationPct = &gpuMetrics.UtilizationPeakPct
 		computed.AcceleratorUtilizationAvgPct = &gpuMetrics.UtilizationAvgPct
 		computed.AcceleratorMemoryPeakGiB = &gpuMetrics.MemoryPeakGiB
 		computed.WaitingRequestsMax = &gpuMetrics.WaitingRequestsMax
+
+		computed.ServerTTFTP50Ms = secondsToMs(gpuMetrics.TTFTP50Seconds)
+		computed.ServerTTFTP90Ms = secondsToMs(gpuMetrics.TTFTP90Seconds)
+		computed.ServerTTFTP95Ms = secondsToMs(gpuMetrics.TTFTP95Seconds)
+		computed.ServerTTFTP99Ms = secondsToMs(gpuMetrics.TTFTP99Seconds)
+
+		computed.ServerTPOTP50Ms = secondsToMs(gpuMetrics.TPOTP50Seconds)
+		computed.ServerTPOTP90Ms = secondsToMs(gpuMetrics.TPOTP90Seconds)
+		computed.ServerTPOTP95Ms = secondsToMs(gpuMetrics.TPOTP95Seconds)
+		computed.ServerTPOTP99Ms = secondsToMs(gpuMetrics.TPOTP99Seconds)
+
+		computed.ServerE2ELatencyP50Ms = secondsToMs(gpuMetrics.E2ELatencyP50Seconds)
+		computed.ServerE2ELatencyP90Ms = secondsToMs(gpuMetrics.E2ELatencyP90Seconds)
+		computed.ServerE2ELatencyP95Ms = secondsToMs(gpuMetrics.E2ELatencyP95Seconds)
+		computed.ServerE2ELatencyP99Ms = secondsToMs(gpuMetrics.E2ELatencyP99Seconds)
+
+		computed.SMActivePeakPct = gpuMetrics.SMActivePeakPct
+		computed.TensorCoreActivePct = gpuMetrics.TensorCoreActivePct
+		computed.PowerAvgW = gpuMetrics.PowerAvgW
+		computed.TempPeakC = gpuMetrics.TempPeakC
+		computed.HBMUsedPeakGiB = gpuMetrics.HBMUsedPeakGiB
+
+		for _, rm := range gpuMetrics.PerRank {
+			computed.PerRank = append(computed.PerRank, database.RankMetricsSummary{
+				Rank:                      rm.Rank,
+				PodName:                   rm.PodName,
+				AcceleratorUtilizationPct: rm.UtilizationPeakPct,
+				AcceleratorMemoryPeakGiB:  rm.MemoryPeakGiB,
+				SMActivePeakPct:           rm.SMActivePeakPct,
+			})
+		}
+
+		rollup := collector.Rollup(gpuMetrics.DeviceSamples, collector.ThermalMaxC(cfg.InstanceType.AcceleratorName))
+		computed.SMUtilP50Pct = rollup.SMUtilP50Pct
+		computed.SMUtilP95Pct = rollup.SMUtilP95Pct
+		computed.SMUtilMaxPct = rollup.SMUtilMaxPct
+		computed.EnergyConsumedJoules = rollup.EnergyConsumedJoules
+		computed.ThermalHeadroomC = rollup.ThermalHeadroomC
+
+		// TokensPerJoule depends on EnergyConsumedJoules, which the rollup
+		// above only just computed, so it can't live in ComputeMetrics
+		// alongside the other cost/efficiency columns.
+		if rollup.EnergyConsumedJoules != nil && *rollup.EnergyConsumedJoules > 0 &&
+			computed.ThroughputAggregateTPS != nil && computed.TotalDurationSeconds != nil {
+			totalTokens := *computed.ThroughputAggregateTPS * *computed.TotalDurationSeconds
+			tpj := totalTokens / *rollup.EnergyConsumedJoules
+			computed.TokensPerJoule = &tpj
+		}
 	}
 
-	if err := o.repo.PersistMetrics(ctx, cfg.RunID, computed); err != nil {
-		o.markFailed(ctx, cfg.RunID)
+	var accelSamples []database.AcceleratorSample
+	if gpuMetrics != nil {
+		accelSamples = gpuMetrics.DeviceSamples
+	}
+	if err := o.repo.PersistMetrics(ctx, cfg.RunID, computed, accelSamples); err != nil {
+		o.markFailed(ctx, cfg.RunID, instanceType, framework, "persist", "")
 		return fmt.Errorf("persist metrics: %w", err)
 	}
 
@@ -190,7 +535,7 @@ func (o *Orchestrator) Execute(ctx context.Context, cfg RunConfig) error {
 	return nil
 }
 
-func (o *Orchestrator) deployModel(ctx context.Context, ns, name string, cfg RunConfig) error {
+func (o *Orchestrator) deployModel(ctx context.Context, ns, name string, cfg RunConfig) (*database.K8sResourceRefs, error) {
 	// Reserve headroom for kubelet, kube-proxy, and OS overhead.
 	// Request ~75% of instance vCPUs and ~85% of memory.
 	vcpus := cfg.InstanceType.VCPUs
@@ -198,6 +543,16 @@ func (o *Orchestrator) deployModel(ctx context.Context, ns, name string, cfg Run
 	cpuReq := fmt.Sprintf("%d", max(1, vcpus*3/4))
 	memReq := fmt.Sprintf("%dGi", max(1, memGiB*85/100))
 
+	pluginDigests, err := resolvePluginDigests(cfg.Request.Plugins)
+	if err != nil {
+		return nil, fmt.Errorf("resolve plugin digests: %w", err)
+	}
+
+	replicas := cfg.Replicas
+	if replicas <= 0 {
+		replicas = 1
+	}
+
 	yamlStr, err := manifest.RenderModelDeployment(manifest.ModelDeploymentParams{
 		Name:                 name,
 		Namespace:            ns,
@@ -215,34 +570,57 @@ func (o *Orchestrator) deployModel(ctx context.Context, ns, name string, cfg Run
 		MaxModelLen:          cfg.Request.MaxModelLen,
 		CPURequest:           cpuReq,
 		MemoryRequest:        memReq,
+		Plugins:              cfg.Request.Plugins,
+		Replicas:             replicas,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return o.applyYAML(ctx, ns, yamlStr)
-}
+	resources, err := o.applyYAML(ctx, ns, yamlStr)
+	if err != nil {
+		return nil, err
+	}
 
-func (o *Orchestrator) waitForReady(ctx context.Context, ns, name string) error {
-	deadline := time.Now().Add(readinessTimeout)
-	for time.Now().Before(deadline) {
-		dep, err := o.client.AppsV1().Deployments(ns).Get(ctx, name, metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
-		if dep.Status.ReadyReplicas >= 1 {
-			return nil
+	if len(pluginDigests) > 0 {
+		if err := o.repo.UpdateRunPluginDigests(ctx, cfg.RunID, pluginDigests); err != nil {
+			return nil, fmt.Errorf("record plugin digests: %w", err)
 		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(readinessPoll):
+	}
+	return resources, nil
+}
+
+// resolvePluginDigests extracts the pinned sha256 digest from each plugin's
+// OCI image reference and returns a name → digest map, so the run it's
+// attached to records exactly which plugin artifacts produced its results.
+// Plugins must be digest-pinned (name@sha256:...); a floating tag would make
+// results impossible to reproduce or diff across kernel versions.
+func resolvePluginDigests(plugins []database.FrameworkPlugin) (map[string]string, error) {
+	if len(plugins) == 0 {
+		return nil, nil
+	}
+	digests := make(map[string]string, len(plugins))
+	for _, p := range plugins {
+		_, digest, ok := strings.Cut(p.Image, "@sha256:")
+		if !ok {
+			return nil, fmt.Errorf("plugin %s image %q is not pinned by digest (expected name@sha256:...)", p.Name, p.Image)
 		}
+		digests[p.Name] = "sha256:" + digest
 	}
-	return fmt.Errorf("model deployment %s not ready after %v", name, readinessTimeout)
+	return digests, nil
 }
 
-func (o *Orchestrator) launchLoadgen(ctx context.Context, ns, name, modelSvc string, cfg RunConfig) error {
+func (o *Orchestrator) waitForReady(ctx context.Context, ns, name string) error {
+	err := pollWithBackoff(ctx, o.readinessPoll, func(ctx context.Context) (bool, error) {
+		return o.readinessProbe(ctx, ns, name)
+	})
+	if errors.Is(err, errPollTimeout) {
+		return fmt.Errorf("model deployment %s not ready after %v", name, o.readinessPoll.Deadline)
+	}
+	return err
+}
+
+func (o *Orchestrator) launchLoadgen(ctx context.Context, ns, name, modelSvc string, cfg RunConfig) (*database.K8sResourceRefs, error) {
 	numRequests := 200
 	if cfg.Request.Concurrency > 32 {
 		numRequests = cfg.Request.Concurrency * 10
@@ -267,41 +645,114 @@ func (o *Orchestrator) launchLoadgen(ctx context.Context, ns, name, modelSvc str
 		NumRequests:          numRequests,
 		WarmupRequests:       10,
 		MinDurationSeconds:   cfg.Request.MinDurationSeconds,
+		DatasetSpec:          cfg.Request.DatasetSpec,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	return o.applyYAML(ctx, ns, yamlStr)
 }
 
 func (o *Orchestrator) waitAndCollect(ctx context.Context, ns, jobName string) ([]byte, error) {
-	deadline := time.Now().Add(jobTimeout)
-	for time.Now().Before(deadline) {
-		job, err := o.client.BatchV1().Jobs(ns).Get(ctx, jobName, metav1.GetOptions{})
-		if err != nil {
-			return nil, err
+	err := pollWithBackoff(ctx, o.jobPoll, func(ctx context.Context) (bool, error) {
+		var job *batchv1.Job
+		getErr := o.trackK8sCall("get", "job", func() error {
+			var err error
+			job, err = o.client.BatchV1().Jobs(ns).Get(ctx, jobName, metav1.GetOptions{})
+			return err
+		})
+		if getErr != nil {
+			return false, getErr
 		}
 		for _, cond := range job.Status.Conditions {
 			if cond.Type == batchv1.JobComplete && cond.Status == corev1.ConditionTrue {
-				return o.readJobLogs(ctx, ns, jobName)
+				return true, nil
 			}
 			if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
-				return nil, fmt.Errorf("loadgen job failed: %s", cond.Message)
+				return false, fmt.Errorf("loadgen job failed: %s", cond.Message)
 			}
 		}
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(jobPoll):
-		}
+		return false, nil
+	})
+	if errors.Is(err, errPollTimeout) {
+		return nil, fmt.Errorf("loadgen job %s timed out after %v", jobName, o.jobPoll.Deadline)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("loadgen job %s timed out after %v", jobName, jobTimeout)
+	return o.readJobLogs(ctx, ns, jobName)
 }
 
-func (o *Orchestrator) readJobLogs(ctx context.Context, ns, jobName string) ([]byte, error) {
-	pods, err := o.client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("job-name=%s", jobName),
+// tailPodPollInterval is how often tailLoadgenLogs retries finding the
+// loadgen pod before it's been scheduled, and how long it waits after a
+// dropped log stream (e.g. a pod restart) before reattaching; also used
+// by StreamJobOutput's follow=false callers as the sole lookup attempt's
+// implicit timeout via ctx.
+const tailPodPollInterval = 5 * time.Second
+
+// partialMetricsSampleInterval is how many parsed per-request NDJSON
+// records tailLoadgenLogs folds into its OnlineAggregator before
+// persisting and publishing a fresh progress snapshot. Small enough that
+// a watcher sees movement within the first few seconds of a run, large
+// enough that a loadgen emitting thousands of lines a second doesn't turn
+// every tailed line into a database write.
+const partialMetricsSampleInterval = 20
+
+// partialMetricsMarker prefixes a JSON-encoded loadgenProgressEvent
+// published via PublishRunLog, so a human reading `accelbench run logs`
+// (or any watcher that doesn't care about progress) can tell a progress
+// snapshot apart from an ordinary loadgen log line without parsing it.
+const partialMetricsMarker = "ACCELBENCH_PARTIAL_METRICS "
+
+// loadgenProgressEvent is what partialMetricsMarker-prefixed log lines
+// carry: a progressive metrics snapshot plus how many requests it's based
+// on, since the snapshot alone can't tell a watcher how far into the run
+// it is.
+type loadgenProgressEvent struct {
+	RequestsSeen int                        `json:"requests_seen"`
+	Metrics      *database.BenchmarkMetrics `json:"metrics"`
+}
+
+// loadgenProgress is what tailLoadgenLogs has observed by the time its
+// tailing stops, so Execute can report partial throughput/TTFT/latency
+// figures and an early failure reason even when the loadgen Job crashes
+// before ever emitting a terminal summary.
+type loadgenProgress struct {
+	mu           sync.Mutex
+	requestsSeen int
+	snapshot     *database.BenchmarkMetrics
+}
+
+func (p *loadgenProgress) record(snap *database.BenchmarkMetrics, requestsSeen int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.snapshot = snap
+	p.requestsSeen = requestsSeen
+}
+
+func (p *loadgenProgress) get() (*database.BenchmarkMetrics, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.snapshot, p.requestsSeen
+}
+
+// StreamJobOutput opens a log stream for jobName's pod in ns: follow=true
+// tails the live stream as the container runs (tailLoadgenLogs), follow=
+// false reads whatever the container has written and returns once it
+// exits (readJobLogs, called only after waitAndCollect's poll has already
+// observed the Job reach a terminal condition). It replaces the pod
+// lookup that used to be duplicated between the two. A future run-status
+// SSE/WebSocket endpoint can use follow=true directly instead of going
+// through PublishRunLog/Watch.
+func (o *Orchestrator) StreamJobOutput(ctx context.Context, ns, jobName string, follow bool) (io.ReadCloser, error) {
+	var pods *corev1.PodList
+	err := o.trackK8sCall("list", "pod", func() error {
+		var listErr error
+		pods, listErr = o.client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("job-name=%s", jobName),
+		})
+		return listErr
 	})
 	if err != nil {
 		return nil, fmt.Errorf("list job pods: %w", err)
@@ -312,11 +763,101 @@ func (o *Orchestrator) readJobLogs(ctx context.Context, ns, jobName string) ([]b
 
 	req := o.client.CoreV1().Pods(ns).GetLogs(pods.Items[0].Name, &corev1.PodLogOptions{
 		Container: "loadgen",
+		Follow:    follow,
 	})
 	stream, err := req.Stream(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("stream pod logs: %w", err)
 	}
+	return stream, nil
+}
+
+// tailLoadgenLogs follows the loadgen pod's logs line by line and
+// publishes each one via PublishRunLog, so an SSE/watch client sees
+// output as the job produces it rather than only the full blob
+// readJobLogs returns after the Job completes. Alongside the raw text, it
+// opportunistically decodes each line as a per-request NDJSON record (see
+// metrics.DecodeRequestLine) into an OnlineAggregator, and every
+// partialMetricsSampleInterval requests persists the running snapshot via
+// UpsertMetrics and publishes it as a partialMetricsMarker log line;
+// progress records into the given loadgenProgress so Execute can surface
+// it as an early failure reason if the Job never reaches a terminal
+// summary. It runs until ctx is canceled (by the caller, once
+// waitAndCollect returns) and never returns an error: a tailing failure
+// only means watchers miss live log lines and progress, it must never
+// affect whether the run itself succeeds.
+func (o *Orchestrator) tailLoadgenLogs(ctx context.Context, ns, jobName, runID string, progress *loadgenProgress) {
+	for ctx.Err() == nil {
+		stream, err := o.StreamJobOutput(ctx, ns, jobName, true)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(tailPodPollInterval):
+				continue
+			}
+		}
+
+		agg := metrics.NewOnlineAggregator()
+		requestsSeen := 0
+		scanner := bufio.NewScanner(stream)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if err := o.repo.PublishRunLog(ctx, runID, string(line)); err != nil {
+				log.Printf("[%s] publish log line: %v", runID[:8], err)
+			}
+
+			rr, ok := metrics.DecodeRequestLine(line)
+			if !ok {
+				continue
+			}
+			agg.Add(rr)
+			requestsSeen++
+			if requestsSeen%partialMetricsSampleInterval == 0 {
+				o.publishLoadgenProgress(ctx, runID, agg, requestsSeen, progress)
+			}
+		}
+		stream.Close()
+		if requestsSeen > 0 {
+			o.publishLoadgenProgress(ctx, runID, agg, requestsSeen, progress)
+		}
+
+		// The pod finished or the stream dropped before the Job itself
+		// reported completion; give it one more look before retrying.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(tailPodPollInterval):
+		}
+	}
+}
+
+// publishLoadgenProgress snapshots agg, records it on progress, persists
+// it via UpsertMetrics and publishes it as a partialMetricsMarker log
+// line. Both the persist and the publish are best-effort, matching
+// tailLoadgenLogs's own never-fail-the-run contract.
+func (o *Orchestrator) publishLoadgenProgress(ctx context.Context, runID string, agg *metrics.OnlineAggregator, requestsSeen int, progress *loadgenProgress) {
+	snap := agg.Snapshot()
+	progress.record(snap, requestsSeen)
+
+	if err := o.repo.UpsertMetrics(ctx, runID, snap); err != nil {
+		log.Printf("[%s] upsert progressive metrics: %v", runID[:8], err)
+	}
+
+	b, err := json.Marshal(loadgenProgressEvent{RequestsSeen: requestsSeen, Metrics: snap})
+	if err != nil {
+		return
+	}
+	if err := o.repo.PublishRunLog(ctx, runID, partialMetricsMarker+string(b)); err != nil {
+		log.Printf("[%s] publish partial metrics: %v", runID[:8], err)
+	}
+}
+
+func (o *Orchestrator) readJobLogs(ctx context.Context, ns, jobName string) ([]byte, error) {
+	stream, err := o.StreamJobOutput(ctx, ns, jobName, false)
+	if err != nil {
+		return nil, err
+	}
 	defer stream.Close()
 
 	var buf bytes.Buffer
@@ -326,27 +867,142 @@ func (o *Orchestrator) readJobLogs(ctx context.Context, ns, jobName string) ([]b
 	return buf.Bytes(), nil
 }
 
-func (o *Orchestrator) teardown(ctx context.Context, ns, modelName, loadgenName string) {
+func (o *Orchestrator) teardown(ctx context.Context, runID, ns, modelName, loadgenName string) {
 	log.Printf("tearing down resources: %s, %s", modelName, loadgenName)
 	propagation := metav1.DeletePropagationBackground
 
-	_ = o.client.BatchV1().Jobs(ns).Delete(ctx, loadgenName, metav1.DeleteOptions{
-		PropagationPolicy: &propagation,
-	})
-	_ = o.client.CoreV1().Services(ns).Delete(ctx, modelName, metav1.DeleteOptions{})
-	_ = o.client.AppsV1().Deployments(ns).Delete(ctx, modelName, metav1.DeleteOptions{
-		PropagationPolicy: &propagation,
-	})
+	var failed bool
+	if err := o.trackK8sCall("delete", "job", func() error {
+		return o.client.BatchV1().Jobs(ns).Delete(ctx, loadgenName, metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+		})
+	}); err != nil {
+		failed = true
+	}
+	if err := o.trackK8sCall("delete", "service", func() error {
+		return o.client.CoreV1().Services(ns).Delete(ctx, modelName, metav1.DeleteOptions{})
+	}); err != nil {
+		failed = true
+	}
+	if err := o.trackK8sCall("delete", "deployment", func() error {
+		return o.client.AppsV1().Deployments(ns).Delete(ctx, modelName, metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+		})
+	}); err != nil {
+		failed = true
+	}
+
+	result := "success"
+	if failed {
+		result = "failure"
+	} else if err := o.repo.SetTeardownPending(ctx, runID, false); err != nil {
+		// Non-fatal: teardown itself succeeded, so the orphan Resume would
+		// otherwise worry about is already gone. Leaving TeardownPending
+		// stuck true just means a future Resume pass double-checks
+		// Kubernetes for objects that turn out not to exist.
+		log.Printf("clear teardown pending for run %s: %v", runID, err)
+	}
+	o.metrics.teardownTotal.WithLabelValues(result).Inc()
+}
+
+// trackK8sCall runs fn and observes its duration against verb/resource on
+// accelbench_orchestrator_k8s_api_duration_seconds before returning its
+// error, so every orchestrator call into the Kubernetes API is latency-
+// tracked without each call site repeating the timing boilerplate.
+func (o *Orchestrator) trackK8sCall(verb, resource string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	o.metrics.k8sCallDuration.WithLabelValues(verb, resource).Observe(time.Since(start).Seconds())
+	return err
 }
 
-func (o *Orchestrator) markFailed(ctx context.Context, runID string) {
+// resolvePricingContext looks up instanceType's on-demand rate from the
+// configured pricingProvider, for Execute and Simulate to stamp onto
+// ComputeMetrics. Returns nil whenever no provider is configured or the
+// lookup fails or has no rate for instanceType, leaving the run's cost
+// columns unpopulated rather than failing it.
+func (o *Orchestrator) resolvePricingContext(ctx context.Context, instanceType string) *metrics.PricingContext {
+	if o.pricingProvider == nil {
+		return nil
+	}
+	rate, err := o.pricingProvider.Rate(ctx, instanceType)
+	if err != nil || rate == nil {
+		return nil
+	}
+	return &metrics.PricingContext{Rate: rate, Source: o.pricingSource, CapturedAt: time.Now()}
+}
+
+// markFailed records a lifecycle phase error against instanceType/framework
+// and marks the run as failed. reason, when non-empty, is whatever
+// podFailureReason could find in the phase's Pod events and container
+// statuses (FailedScheduling, ImagePullBackOff, OOMKilled, ...) and is
+// stamped onto the run alongside its status so a caller can distinguish a
+// capacity problem from a model-load failure without kubectl describe.
+func (o *Orchestrator) markFailed(ctx context.Context, runID, instanceType, framework, phase, reason string) {
+	o.metrics.phaseErrors.WithLabelValues(runID, instanceType, framework, phase).Inc()
 	if err := o.repo.UpdateRunStatus(ctx, runID, "failed"); err != nil {
 		log.Printf("failed to mark run %s as failed: %v", runID, err)
 	}
+	if reason == "" {
+		return
+	}
+	log.Printf("[%s] phase %s failed: %s", runID[:8], phase, reason)
+	if err := o.repo.UpdateRunFailureReason(ctx, runID, reason); err != nil {
+		log.Printf("failed to record failure reason for run %s: %v", runID, err)
+	}
+}
+
+// podFailureReason inspects the Kubernetes events and container statuses
+// of every Pod matching labelSelector in ns for a reason a user would
+// otherwise have to kubectl describe for: FailedScheduling (no capacity),
+// ImagePullBackOff/ErrImagePull (bad image reference or registry auth),
+// CrashLoopBackOff, and OOMKilled. It never returns an error: a run that
+// fails must be marked failed regardless of whether this best-effort
+// lookup itself succeeds, so a lookup error just means no reason is
+// attached.
+func (o *Orchestrator) podFailureReason(ctx context.Context, ns, labelSelector string) string {
+	pods, err := o.client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil || len(pods.Items) == 0 {
+		return ""
+	}
+
+	var reasons []string
+	for _, pod := range pods.Items {
+		events, err := o.client.CoreV1().Events(ns).List(ctx, metav1.ListOptions{
+			FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.namespace=%s", pod.Name, ns),
+		})
+		if err == nil {
+			for _, ev := range events.Items {
+				switch ev.Reason {
+				case "FailedScheduling", "ImagePullBackOff", "ErrImagePull", "BackOff":
+					reasons = append(reasons, fmt.Sprintf("%s: %s", ev.Reason, ev.Message))
+				}
+			}
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting != nil {
+				switch cs.State.Waiting.Reason {
+				case "ImagePullBackOff", "ErrImagePull", "CrashLoopBackOff":
+					reasons = append(reasons, fmt.Sprintf("%s: %s", cs.State.Waiting.Reason, cs.State.Waiting.Message))
+				}
+			}
+			if cs.LastTerminationState.Terminated != nil && cs.LastTerminationState.Terminated.Reason == "OOMKilled" {
+				reasons = append(reasons, fmt.Sprintf("OOMKilled: container %s", cs.Name))
+			}
+		}
+	}
+	if len(reasons) == 0 {
+		return ""
+	}
+	return strings.Join(reasons, "; ")
 }
 
-// applyYAML parses multi-document YAML and creates each resource.
-func (o *Orchestrator) applyYAML(ctx context.Context, ns, yamlStr string) error {
+// applyYAML parses multi-document YAML and server-side-applies each
+// resource (see createDeployment/createService/createJob), accumulating the
+// name/UID of every object it applies into a K8sResourceRefs so Execute can
+// stamp them onto the run via UpdateRunPhase for Resume to rebind to later.
+func (o *Orchestrator) applyYAML(ctx context.Context, ns, yamlStr string) (*database.K8sResourceRefs, error) {
+	resources := &database.K8sResourceRefs{Namespace: ns}
 	decoder := yaml.NewYAMLOrJSONDecoder(io.NopCloser(strings.NewReader(yamlStr)), 4096)
 	for {
 		var raw json.RawMessage
@@ -354,7 +1010,7 @@ func (o *Orchestrator) applyYAML(ctx context.Context, ns, yamlStr string) error
 			if err == io.EOF {
 				break
 			}
-			return fmt.Errorf("decode YAML: %w", err)
+			return nil, fmt.Errorf("decode YAML: %w", err)
 		}
 		if len(raw) == 0 {
 			continue
@@ -363,60 +1019,117 @@ func (o *Orchestrator) applyYAML(ctx context.Context, ns, yamlStr string) error
 		// Peek at kind to determine resource type.
 		var meta struct{ Kind string }
 		if err := json.Unmarshal(raw, &meta); err != nil {
-			return fmt.Errorf("unmarshal kind: %w", err)
+			return nil, fmt.Errorf("unmarshal kind: %w", err)
 		}
 
 		docJSON := string(raw)
 		switch meta.Kind {
 		case "Deployment":
-			if err := o.createDeployment(ctx, ns, docJSON); err != nil {
-				return err
+			dep, err := o.createDeployment(ctx, ns, docJSON)
+			if err != nil {
+				return nil, err
 			}
+			resources.DeploymentName = dep.Name
+			resources.DeploymentUID = string(dep.UID)
 		case "Service":
-			if err := o.createService(ctx, ns, docJSON); err != nil {
-				return err
+			svc, err := o.createService(ctx, ns, docJSON)
+			if err != nil {
+				return nil, err
 			}
+			resources.ServiceName = svc.Name
+			resources.ServiceUID = string(svc.UID)
 		case "Job":
-			if err := o.createJob(ctx, ns, docJSON); err != nil {
-				return err
+			job, err := o.createJob(ctx, ns, docJSON)
+			if err != nil {
+				return nil, err
 			}
+			resources.JobName = job.Name
+			resources.JobUID = string(job.UID)
 		default:
-			return fmt.Errorf("unsupported resource kind: %s", meta.Kind)
+			return nil, fmt.Errorf("unsupported resource kind: %s", meta.Kind)
 		}
 	}
-	return nil
+	return resources, nil
 }
 
-func (o *Orchestrator) createDeployment(ctx context.Context, ns, docJSON string) error {
+// createDeployment, createService, and createJob all server-side-apply
+// (see applyYAML) rather than Create, so rerunning a failed benchmark
+// whose resources never got torn down reconciles them to the rendered
+// spec instead of erroring out with AlreadyExists, and two concurrent
+// reruns can both own the same object's fields instead of racing a
+// delete+recreate. Each returns the applied object so applyYAML can record
+// its name and UID.
+
+func (o *Orchestrator) createDeployment(ctx context.Context, ns, docJSON string) (*appsv1.Deployment, error) {
 	var dep appsv1.Deployment
 	if err := json.Unmarshal([]byte(docJSON), &dep); err != nil {
-		return fmt.Errorf("decode deployment: %w", err)
+		return nil, fmt.Errorf("decode deployment: %w", err)
 	}
-	_, err := o.client.AppsV1().Deployments(ns).Create(ctx, &dep, metav1.CreateOptions{})
-	return err
+	var applied *appsv1.Deployment
+	err := o.trackK8sCall("apply", "deployment", func() error {
+		var err error
+		applied, err = o.client.AppsV1().Deployments(ns).Patch(ctx, dep.Name, types.ApplyPatchType, []byte(docJSON),
+			metav1.PatchOptions{FieldManager: fieldManager, Force: boolPtr(true)})
+		return err
+	})
+	return applied, err
 }
 
-func (o *Orchestrator) createService(ctx context.Context, ns, docJSON string) error {
+func (o *Orchestrator) createService(ctx context.Context, ns, docJSON string) (*corev1.Service, error) {
 	var svc corev1.Service
 	if err := json.Unmarshal([]byte(docJSON), &svc); err != nil {
-		return fmt.Errorf("decode service: %w", err)
+		return nil, fmt.Errorf("decode service: %w", err)
 	}
-	_, err := o.client.CoreV1().Services(ns).Create(ctx, &svc, metav1.CreateOptions{})
-	return err
+	var applied *corev1.Service
+	err := o.trackK8sCall("apply", "service", func() error {
+		var err error
+		applied, err = o.client.CoreV1().Services(ns).Patch(ctx, svc.Name, types.ApplyPatchType, []byte(docJSON),
+			metav1.PatchOptions{FieldManager: fieldManager, Force: boolPtr(true)})
+		return err
+	})
+	return applied, err
 }
 
-func (o *Orchestrator) createJob(ctx context.Context, ns, docJSON string) error {
+func (o *Orchestrator) createJob(ctx context.Context, ns, docJSON string) (*batchv1.Job, error) {
 	var job batchv1.Job
 	if err := json.Unmarshal([]byte(docJSON), &job); err != nil {
-		return fmt.Errorf("decode job: %w", err)
+		return nil, fmt.Errorf("decode job: %w", err)
 	}
-	_, err := o.client.BatchV1().Jobs(ns).Create(ctx, &job, metav1.CreateOptions{})
-	return err
+	var applied *batchv1.Job
+	err := o.trackK8sCall("apply", "job", func() error {
+		var err error
+		applied, err = o.client.BatchV1().Jobs(ns).Patch(ctx, job.Name, types.ApplyPatchType, []byte(docJSON),
+			metav1.PatchOptions{FieldManager: fieldManager, Force: boolPtr(true)})
+		return err
+	})
+	return applied, err
 }
 
+// boolPtr returns a pointer to b, for PatchOptions.Force.
+func boolPtr(b bool) *bool { return &b }
+
 func derefStr(s *string) string {
 	if s == nil {
 		return ""
 	}
 	return *s
 }
+
+// derefF64 formats a *float64 for a log line, rendering nil (no
+// observations yet) as "n/a" instead of "0".
+func derefF64(f *float64) string {
+	if f == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f", *f)
+}
+
+// secondsToMs converts a *float64 in seconds to a *float64 in milliseconds,
+// passing through nil.
+func secondsToMs(s *float64) *float64 {
+	if s == nil {
+		return nil
+	}
+	v := *s * 1000
+	return &v
+}
@@ -3,12 +3,14 @@ package orchestrator
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/accelbench/accelbench/internal/database"
 	"github.com/accelbench/accelbench/internal/metrics"
 
+	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -19,20 +21,20 @@ func testRunConfig(runID string) RunConfig {
 	return RunConfig{
 		RunID: runID,
 		Model: &database.Model{
-			ID:     "model-001",
-			HfID:   "meta-llama/Llama-3.1-8B",
+			ID:         "model-001",
+			HfID:       "meta-llama/Llama-3.1-8B",
 			HfRevision: "abc123",
 		},
 		InstanceType: &database.InstanceType{
-			ID:              "inst-001",
-			Name:            "g5.xlarge",
-			Family:          "g5",
-			AcceleratorType: "gpu",
-			AcceleratorName: "A10G",
-			AcceleratorCount: 1,
+			ID:                   "inst-001",
+			Name:                 "g5.xlarge",
+			Family:               "g5",
+			AcceleratorType:      "gpu",
+			AcceleratorName:      "A10G",
+			AcceleratorCount:     1,
 			AcceleratorMemoryGiB: 24,
-			VCPUs:           4,
-			MemoryGiB:       16,
+			VCPUs:                4,
+			MemoryGiB:            16,
 		},
 		Request: &database.RunRequest{
 			ModelHfID:            "meta-llama/Llama-3.1-8B",
@@ -51,7 +53,7 @@ func testRunConfig(runID string) RunConfig {
 }
 
 func TestNew(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	repo := database.NewMockRepo()
 	o := New(client, repo)
 	if o == nil {
@@ -60,14 +62,14 @@ func TestNew(t *testing.T) {
 }
 
 func TestDeployModel_CreatesResources(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	repo := database.NewMockRepo()
 	o := New(client, repo)
 
 	cfg := testRunConfig("12345678-abcd-1234-abcd-1234567890ab")
 	ctx := context.Background()
 
-	err := o.deployModel(ctx, "default", "bench-12345678", cfg)
+	_, err := o.deployModel(ctx, "default", "bench-12345678", cfg)
 	if err != nil {
 		t.Fatalf("deployModel: %v", err)
 	}
@@ -82,8 +84,27 @@ func TestDeployModel_CreatesResources(t *testing.T) {
 	}
 }
 
+func TestDeployModel_RerunDoesNotConflict(t *testing.T) {
+	client := fake.NewClientset()
+	repo := database.NewMockRepo()
+	o := New(client, repo)
+
+	cfg := testRunConfig("12345678-abcd-1234-abcd-1234567890ab")
+	ctx := context.Background()
+
+	// A rerun of a previously-failed benchmark applies the same
+	// Deployment+Service a second time; server-side apply must reconcile
+	// rather than error out with AlreadyExists the way a plain Create would.
+	if _, err := o.deployModel(ctx, "default", "bench-12345678", cfg); err != nil {
+		t.Fatalf("deployModel (first): %v", err)
+	}
+	if _, err := o.deployModel(ctx, "default", "bench-12345678", cfg); err != nil {
+		t.Fatalf("deployModel (rerun): %v", err)
+	}
+}
+
 func TestWaitForReady_AlreadyReady(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	repo := database.NewMockRepo()
 	o := New(client, repo)
 
@@ -91,13 +112,20 @@ func TestWaitForReady_AlreadyReady(t *testing.T) {
 	ctx := context.Background()
 
 	// Deploy first.
-	if err := o.deployModel(ctx, "default", "bench-12345678", cfg); err != nil {
+	if _, err := o.deployModel(ctx, "default", "bench-12345678", cfg); err != nil {
 		t.Fatalf("deployModel: %v", err)
 	}
 
-	// Simulate readiness by updating the deployment status.
+	// Simulate readiness by updating the deployment status: the
+	// ReadyChecker also requires ObservedGeneration to have caught up and
+	// the Available condition to be true, not just ReadyReplicas >= 1.
 	dep, _ := client.AppsV1().Deployments("default").Get(ctx, "bench-12345678", metav1.GetOptions{})
 	dep.Status.ReadyReplicas = 1
+	dep.Status.ObservedGeneration = dep.Generation
+	dep.Status.Conditions = append(dep.Status.Conditions, appsv1.DeploymentCondition{
+		Type:   appsv1.DeploymentAvailable,
+		Status: corev1.ConditionTrue,
+	})
 	client.AppsV1().Deployments("default").UpdateStatus(ctx, dep, metav1.UpdateOptions{})
 
 	err := o.waitForReady(ctx, "default", "bench-12345678")
@@ -107,14 +135,14 @@ func TestWaitForReady_AlreadyReady(t *testing.T) {
 }
 
 func TestWaitForReady_ContextCancelled(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	repo := database.NewMockRepo()
 	o := New(client, repo)
 
 	cfg := testRunConfig("12345678-abcd-1234-abcd-1234567890ab")
 	ctx := context.Background()
 
-	if err := o.deployModel(ctx, "default", "bench-12345678", cfg); err != nil {
+	if _, err := o.deployModel(ctx, "default", "bench-12345678", cfg); err != nil {
 		t.Fatalf("deployModel: %v", err)
 	}
 
@@ -128,15 +156,162 @@ func TestWaitForReady_ContextCancelled(t *testing.T) {
 	}
 }
 
+func TestWaitForReady_CustomProbe(t *testing.T) {
+	client := fake.NewClientset()
+	repo := database.NewMockRepo()
+
+	var calls int
+	o := New(client, repo,
+		WithReadinessPoll(PollConfig{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond, Multiplier: 1, Deadline: time.Second}),
+		WithReadinessProbe(func(ctx context.Context, ns, name string) (bool, error) {
+			calls++
+			return calls >= 3, nil
+		}),
+	)
+
+	if err := o.waitForReady(context.Background(), "default", "bench-custom"); err != nil {
+		t.Fatalf("waitForReady: %v", err)
+	}
+	if calls < 3 {
+		t.Errorf("expected at least 3 probe calls, got %d", calls)
+	}
+}
+
+func TestWaitForReady_Timeout(t *testing.T) {
+	client := fake.NewClientset()
+	repo := database.NewMockRepo()
+
+	o := New(client, repo,
+		WithReadinessPoll(PollConfig{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond, Multiplier: 1, Deadline: 5 * time.Millisecond}),
+		WithReadinessProbe(func(ctx context.Context, ns, name string) (bool, error) {
+			return false, nil
+		}),
+	)
+
+	err := o.waitForReady(context.Background(), "default", "bench-never-ready")
+	if err == nil {
+		t.Fatal("expected timeout error")
+	}
+}
+
+func TestPollWithBackoff_Multiplier(t *testing.T) {
+	cfg := PollConfig{InitialInterval: time.Millisecond, MaxInterval: 4 * time.Millisecond, Multiplier: 2, Deadline: time.Second}
+
+	var calls int
+	err := pollWithBackoff(context.Background(), cfg, func(ctx context.Context) (bool, error) {
+		calls++
+		return calls >= 4, nil
+	})
+	if err != nil {
+		t.Fatalf("pollWithBackoff: %v", err)
+	}
+	if calls != 4 {
+		t.Errorf("expected 4 calls, got %d", calls)
+	}
+}
+
+// recordingCounter is a metrics.Counter that remembers how many times it
+// was incremented, so tests can assert on emitted orchestrator metrics.
+type recordingCounter struct{ count *int }
+
+func (c recordingCounter) Inc()          { *c.count++ }
+func (c recordingCounter) Add(v float64) { *c.count += int(v) }
+
+// recordingRegistry is a metrics.Registry that hands out recordingCounters
+// keyed by the label values they were requested with, and no-op gauges and
+// histograms, so a test can assert a specific phase error was counted.
+type recordingRegistry struct {
+	counts map[string]*int
+}
+
+func newRecordingRegistry() *recordingRegistry {
+	return &recordingRegistry{counts: make(map[string]*int)}
+}
+
+func (r *recordingRegistry) Counter(name, _ string, _ ...string) metrics.CounterVec {
+	return recordingCounterVec{registry: r, name: name}
+}
+func (r *recordingRegistry) Gauge(_, _ string, _ ...string) metrics.GaugeVec {
+	return noopGaugeVec{}
+}
+func (r *recordingRegistry) Histogram(_, _ string, _ []float64, _ ...string) metrics.HistogramVec {
+	return noopHistogramVec{}
+}
+
+type recordingCounterVec struct {
+	registry *recordingRegistry
+	name     string
+}
+
+func (v recordingCounterVec) WithLabelValues(labelValues ...string) metrics.Counter {
+	key := v.name + "|" + strings.Join(labelValues, ",")
+	if _, ok := v.registry.counts[key]; !ok {
+		zero := 0
+		v.registry.counts[key] = &zero
+	}
+	return recordingCounter{count: v.registry.counts[key]}
+}
+
+type noopGaugeVec struct{}
+
+func (noopGaugeVec) WithLabelValues(_ ...string) metrics.Gauge { return noopGauge{} }
+
+type noopGauge struct{}
+
+func (noopGauge) Set(float64) {}
+func (noopGauge) Add(float64) {}
+
+type noopHistogramVec struct{}
+
+func (noopHistogramVec) WithLabelValues(_ ...string) metrics.Histogram { return noopHistogram{} }
+
+type noopHistogram struct{}
+
+func (noopHistogram) Observe(float64) {}
+
+func TestMarkFailed_RecordsPhaseErrorMetric(t *testing.T) {
+	repo := database.NewMockRepo()
+	client := fake.NewClientset()
+	reg := newRecordingRegistry()
+	o := New(client, repo, WithMetricsRegistry(reg))
+
+	run := &database.BenchmarkRun{
+		ModelID: "m1", InstanceTypeID: "i1",
+		Framework: "vllm", FrameworkVersion: "v0.6.0",
+		Concurrency: 1, InputSequenceLength: 512,
+		OutputSequenceLength: 256, DatasetName: "sharegpt",
+		RunType: "on_demand", Status: "pending",
+	}
+	runID, _ := repo.CreateBenchmarkRun(context.Background(), run)
+
+	o.markFailed(context.Background(), runID, "g5.xlarge", "vllm", "deploy", "")
+
+	key := "accelbench_orchestrator_phase_errors_total|" + runID + ",g5.xlarge,vllm,deploy"
+	if got := *reg.counts[key]; got != 1 {
+		t.Errorf("phase error count = %d, want 1", got)
+	}
+}
+
+func TestDeployModel_WithNoopMetricsRegistry(t *testing.T) {
+	client := fake.NewClientset()
+	repo := database.NewMockRepo()
+	o := New(client, repo, WithMetricsRegistry(metrics.NewNoopRegistry()))
+
+	cfg := testRunConfig("12345678-abcd-1234-abcd-1234567890ab")
+	if _, err := o.deployModel(context.Background(), "default", "bench-noop-metrics", cfg); err != nil {
+		t.Fatalf("deployModel: %v", err)
+	}
+}
+
 func TestLaunchLoadgen_CreatesJob(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	repo := database.NewMockRepo()
 	o := New(client, repo)
 
 	cfg := testRunConfig("12345678-abcd-1234-abcd-1234567890ab")
 	ctx := context.Background()
 
-	err := o.launchLoadgen(ctx, "default", "loadgen-12345678", "bench-12345678", cfg)
+	_, err := o.launchLoadgen(ctx, "default", "loadgen-12345678", "bench-12345678", cfg)
 	if err != nil {
 		t.Fatalf("launchLoadgen: %v", err)
 	}
@@ -151,7 +326,7 @@ func TestLaunchLoadgen_CreatesJob(t *testing.T) {
 }
 
 func TestWaitAndCollect_JobFailed(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	repo := database.NewMockRepo()
 	o := New(client, repo)
 
@@ -159,7 +334,7 @@ func TestWaitAndCollect_JobFailed(t *testing.T) {
 	cfg := testRunConfig("12345678-abcd-1234-abcd-1234567890ab")
 
 	// Create the job first.
-	if err := o.launchLoadgen(ctx, "default", "loadgen-12345678", "bench-12345678", cfg); err != nil {
+	if _, err := o.launchLoadgen(ctx, "default", "loadgen-12345678", "bench-12345678", cfg); err != nil {
 		t.Fatalf("launchLoadgen: %v", err)
 	}
 
@@ -182,7 +357,7 @@ func TestWaitAndCollect_JobFailed(t *testing.T) {
 }
 
 func TestTeardown(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	repo := database.NewMockRepo()
 	o := New(client, repo)
 
@@ -194,7 +369,7 @@ func TestTeardown(t *testing.T) {
 	o.launchLoadgen(ctx, "default", "loadgen-12345678", "bench-12345678", cfg)
 
 	// Teardown.
-	o.teardown(ctx, "default", "bench-12345678", "loadgen-12345678")
+	o.teardown(ctx, "12345678-abcd-1234-abcd-1234567890ab", "default", "bench-12345678", "loadgen-12345678")
 
 	// Verify deployment deleted.
 	deps, _ := client.AppsV1().Deployments("default").List(ctx, metav1.ListOptions{})
@@ -211,7 +386,7 @@ func TestTeardown(t *testing.T) {
 
 func TestMarkFailed(t *testing.T) {
 	repo := database.NewMockRepo()
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	o := New(client, repo)
 
 	// Seed a run.
@@ -224,13 +399,69 @@ func TestMarkFailed(t *testing.T) {
 	}
 	runID, _ := repo.CreateBenchmarkRun(context.Background(), run)
 
-	o.markFailed(context.Background(), runID)
+	o.markFailed(context.Background(), runID, "g5.xlarge", "vllm", "deploy", "")
 
 	if got := repo.GetRunStatus(runID); got != "failed" {
 		t.Errorf("status = %s, want failed", got)
 	}
 }
 
+func TestMarkFailed_RecordsReason(t *testing.T) {
+	repo := database.NewMockRepo()
+	client := fake.NewClientset()
+	o := New(client, repo)
+
+	run := &database.BenchmarkRun{
+		ModelID: "m1", InstanceTypeID: "i1",
+		Framework: "vllm", FrameworkVersion: "v0.6.0",
+		Concurrency: 1, InputSequenceLength: 512,
+		OutputSequenceLength: 256, DatasetName: "sharegpt",
+		RunType: "on_demand", Status: "pending",
+	}
+	runID, _ := repo.CreateBenchmarkRun(context.Background(), run)
+
+	o.markFailed(context.Background(), runID, "g5.xlarge", "vllm", "ready", "FailedScheduling: 0/4 nodes available")
+
+	stored, err := repo.GetBenchmarkRun(context.Background(), runID)
+	if err != nil {
+		t.Fatalf("GetBenchmarkRun: %v", err)
+	}
+	if stored.FailureReason == nil || *stored.FailureReason != "FailedScheduling: 0/4 nodes available" {
+		t.Errorf("FailureReason = %v, want FailedScheduling reason", stored.FailureReason)
+	}
+}
+
+func TestPodFailureReason_ImagePullBackOff(t *testing.T) {
+	client := fake.NewClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "bench-1-abc", Namespace: "default", Labels: map[string]string{"app": "bench-1"}},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "model",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "back-off pulling image"},
+				},
+			}},
+		},
+	})
+	repo := database.NewMockRepo()
+	o := New(client, repo)
+
+	reason := o.podFailureReason(context.Background(), "default", "app=bench-1")
+	if !strings.Contains(reason, "ImagePullBackOff") {
+		t.Errorf("podFailureReason = %q, want it to mention ImagePullBackOff", reason)
+	}
+}
+
+func TestPodFailureReason_NoMatchingPods(t *testing.T) {
+	client := fake.NewClientset()
+	repo := database.NewMockRepo()
+	o := New(client, repo)
+
+	if got := o.podFailureReason(context.Background(), "default", "app=missing"); got != "" {
+		t.Errorf("podFailureReason = %q, want empty for no matching pods", got)
+	}
+}
+
 func TestDerefStr(t *testing.T) {
 	if got := derefStr(nil); got != "" {
 		t.Errorf("derefStr(nil) = %q, want empty", got)
@@ -242,7 +473,7 @@ func TestDerefStr(t *testing.T) {
 }
 
 func TestLaunchLoadgen_HighConcurrency(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	repo := database.NewMockRepo()
 	o := New(client, repo)
 
@@ -250,7 +481,7 @@ func TestLaunchLoadgen_HighConcurrency(t *testing.T) {
 	cfg.Request.Concurrency = 64 // > 32, should use concurrency*10 = 640
 
 	ctx := context.Background()
-	err := o.launchLoadgen(ctx, "default", "loadgen-hi", "bench-12345678", cfg)
+	_, err := o.launchLoadgen(ctx, "default", "loadgen-hi", "bench-12345678", cfg)
 	if err != nil {
 		t.Fatalf("launchLoadgen: %v", err)
 	}
@@ -276,7 +507,7 @@ func makeLoadgenJSON() []byte {
 }
 
 func TestDeployModel_LargeInstance(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	repo := database.NewMockRepo()
 	o := New(client, repo)
 
@@ -284,14 +515,85 @@ func TestDeployModel_LargeInstance(t *testing.T) {
 	cfg.InstanceType.MemoryGiB = 512 // > 256, should use larger CPU/mem
 
 	ctx := context.Background()
-	err := o.deployModel(ctx, "default", "bench-large", cfg)
+	_, err := o.deployModel(ctx, "default", "bench-large", cfg)
+	if err != nil {
+		t.Fatalf("deployModel: %v", err)
+	}
+}
+
+func TestResolvePluginDigests(t *testing.T) {
+	plugins := []database.FrameworkPlugin{
+		{Name: "flash-attn-3", Image: "ghcr.io/acme/flash-attn@sha256:deadbeef"},
+		{Name: "speculator", Image: "ghcr.io/acme/eagle@sha256:cafef00d"},
+	}
+
+	digests, err := resolvePluginDigests(plugins)
+	if err != nil {
+		t.Fatalf("resolvePluginDigests: %v", err)
+	}
+	if got := digests["flash-attn-3"]; got != "sha256:deadbeef" {
+		t.Errorf("flash-attn-3 digest = %q, want sha256:deadbeef", got)
+	}
+	if got := digests["speculator"]; got != "sha256:cafef00d" {
+		t.Errorf("speculator digest = %q, want sha256:cafef00d", got)
+	}
+}
+
+func TestResolvePluginDigests_Empty(t *testing.T) {
+	digests, err := resolvePluginDigests(nil)
 	if err != nil {
+		t.Fatalf("resolvePluginDigests: %v", err)
+	}
+	if digests != nil {
+		t.Errorf("expected nil digests for no plugins, got %v", digests)
+	}
+}
+
+func TestResolvePluginDigests_NotPinned(t *testing.T) {
+	plugins := []database.FrameworkPlugin{
+		{Name: "flash-attn-3", Image: "ghcr.io/acme/flash-attn:latest"},
+	}
+
+	if _, err := resolvePluginDigests(plugins); err == nil {
+		t.Fatal("expected error for non-digest-pinned plugin image")
+	}
+}
+
+func TestDeployModel_RecordsPluginDigests(t *testing.T) {
+	client := fake.NewClientset()
+	repo := database.NewMockRepo()
+	o := New(client, repo)
+
+	run := &database.BenchmarkRun{
+		ModelID: "m1", InstanceTypeID: "i1",
+		Framework: "vllm", FrameworkVersion: "v0.6.0",
+		Concurrency: 1, InputSequenceLength: 512,
+		OutputSequenceLength: 256, DatasetName: "sharegpt",
+		RunType: "on_demand", Status: "pending",
+	}
+	runID, _ := repo.CreateBenchmarkRun(context.Background(), run)
+
+	cfg := testRunConfig(runID)
+	cfg.Request.Plugins = []database.FrameworkPlugin{
+		{Name: "flash-attn-3", Image: "ghcr.io/acme/flash-attn@sha256:deadbeef", MountPath: "/plugins/flash-attn"},
+	}
+
+	ctx := context.Background()
+	if _, err := o.deployModel(ctx, "default", "bench-plugin", cfg); err != nil {
 		t.Fatalf("deployModel: %v", err)
 	}
+
+	stored, err := repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		t.Fatalf("GetBenchmarkRun: %v", err)
+	}
+	if got := stored.PluginDigests["flash-attn-3"]; got != "sha256:deadbeef" {
+		t.Errorf("stored plugin digest = %q, want sha256:deadbeef", got)
+	}
 }
 
 func TestDeployModel_NeuronInstance(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	repo := database.NewMockRepo()
 	o := New(client, repo)
 
@@ -301,12 +603,189 @@ func TestDeployModel_NeuronInstance(t *testing.T) {
 	cfg.Request.Framework = "vllm-neuron"
 
 	ctx := context.Background()
-	err := o.deployModel(ctx, "default", "bench-neuron", cfg)
+	_, err := o.deployModel(ctx, "default", "bench-neuron", cfg)
 	if err != nil {
 		t.Fatalf("deployModel: %v", err)
 	}
 }
 
+func TestDeployModel_ReturnsK8sResourceRefs(t *testing.T) {
+	client := fake.NewClientset()
+	repo := database.NewMockRepo()
+	o := New(client, repo)
+
+	cfg := testRunConfig("12345678-abcd-1234-abcd-1234567890ab")
+	ctx := context.Background()
+
+	resources, err := o.deployModel(ctx, "default", "bench-12345678", cfg)
+	if err != nil {
+		t.Fatalf("deployModel: %v", err)
+	}
+	if resources.Namespace != "default" || resources.DeploymentName != "bench-12345678" || resources.ServiceName != "bench-12345678" {
+		t.Errorf("unexpected resources: %+v", resources)
+	}
+	if resources.DeploymentUID == "" || resources.ServiceUID == "" {
+		t.Error("expected deployment and service UIDs to be populated")
+	}
+}
+
+func TestLaunchLoadgen_ReturnsK8sResourceRefs(t *testing.T) {
+	client := fake.NewClientset()
+	repo := database.NewMockRepo()
+	o := New(client, repo)
+
+	cfg := testRunConfig("12345678-abcd-1234-abcd-1234567890ab")
+	ctx := context.Background()
+
+	resources, err := o.launchLoadgen(ctx, "default", "loadgen-12345678", "bench-12345678", cfg)
+	if err != nil {
+		t.Fatalf("launchLoadgen: %v", err)
+	}
+	if resources.Namespace != "default" || resources.JobName != "loadgen-12345678" || resources.JobUID == "" {
+		t.Errorf("unexpected resources: %+v", resources)
+	}
+}
+
+func TestTeardown_ClearsTeardownPending(t *testing.T) {
+	client := fake.NewClientset()
+	repo := database.NewMockRepo()
+	o := New(client, repo)
+	ctx := context.Background()
+
+	run := &database.BenchmarkRun{
+		ModelID: "m1", InstanceTypeID: "i1", Framework: "vllm", FrameworkVersion: "v0.6.0",
+		Concurrency: 1, InputSequenceLength: 512, OutputSequenceLength: 256,
+		DatasetName: "sharegpt", RunType: "on_demand", Status: "pending",
+	}
+	runID, _ := repo.CreateBenchmarkRun(ctx, run)
+	if err := repo.SetTeardownPending(ctx, runID, true); err != nil {
+		t.Fatalf("SetTeardownPending: %v", err)
+	}
+
+	// Deploy real resources first so all three deletes below succeed;
+	// teardown only clears TeardownPending once none of them fail.
+	cfg := testRunConfig(runID)
+	if _, err := o.deployModel(ctx, "default", "bench-teardown", cfg); err != nil {
+		t.Fatalf("deployModel: %v", err)
+	}
+	if _, err := o.launchLoadgen(ctx, "default", "loadgen-teardown", "bench-teardown", cfg); err != nil {
+		t.Fatalf("launchLoadgen: %v", err)
+	}
+
+	o.teardown(ctx, runID, "default", "bench-teardown", "loadgen-teardown")
+
+	stored, _ := repo.GetBenchmarkRun(ctx, runID)
+	if stored.TeardownPending {
+		t.Error("expected TeardownPending to be cleared after a successful teardown")
+	}
+}
+
+func TestResume_TearsDownOrphanWithMissingJob(t *testing.T) {
+	client := fake.NewClientset()
+	repo := database.NewMockRepo()
+	o := New(client, repo)
+	ctx := context.Background()
+
+	run := &database.BenchmarkRun{
+		ModelID: "m1", InstanceTypeID: "i1", Framework: "vllm", FrameworkVersion: "v0.6.0",
+		Concurrency: 1, InputSequenceLength: 512, OutputSequenceLength: 256,
+		DatasetName: "sharegpt", RunType: "on_demand", Status: "pending",
+	}
+	runID, _ := repo.CreateBenchmarkRun(ctx, run)
+	if err := repo.UpdateRunStatus(ctx, runID, "running"); err != nil {
+		t.Fatalf("UpdateRunStatus: %v", err)
+	}
+	if err := repo.UpdateRunPhase(ctx, runID, "loadgen_running", &database.K8sResourceRefs{
+		Namespace: "default", JobName: "loadgen-" + runID[:8], JobUID: "missing-uid",
+	}); err != nil {
+		t.Fatalf("UpdateRunPhase: %v", err)
+	}
+
+	if err := o.Resume(ctx); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	waitForCondition(t, func() bool {
+		stored, _ := repo.GetBenchmarkRun(ctx, runID)
+		return stored.Status == "failed"
+	})
+
+	stored, _ := repo.GetBenchmarkRun(ctx, runID)
+	if stored.Status != "failed" {
+		t.Errorf("status = %q, want failed", stored.Status)
+	}
+}
+
+func TestResume_RebindsToFailedJob(t *testing.T) {
+	client := fake.NewClientset()
+	repo := database.NewMockRepo()
+	o := New(client, repo,
+		WithJobPoll(PollConfig{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond, Multiplier: 1, Deadline: time.Second}),
+	)
+	ctx := context.Background()
+	cfg := testRunConfig("12345678-abcd-1234-abcd-1234567890ab")
+
+	jobName := "loadgen-12345678"
+	if _, err := o.launchLoadgen(ctx, "default", jobName, "bench-12345678", cfg); err != nil {
+		t.Fatalf("launchLoadgen: %v", err)
+	}
+	// A restarted process finds the loadgen Job it left behind already
+	// failed — waitAndCollect should surface that, not hang waiting on it.
+	job, _ := client.BatchV1().Jobs("default").Get(ctx, jobName, metav1.GetOptions{})
+	job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+		Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "OOM killed",
+	})
+	client.BatchV1().Jobs("default").UpdateStatus(ctx, job, metav1.UpdateOptions{})
+
+	run := &database.BenchmarkRun{
+		ModelID: "m1", InstanceTypeID: "i1", Framework: "vllm", FrameworkVersion: "v0.6.0",
+		Concurrency: 1, InputSequenceLength: 512, OutputSequenceLength: 256,
+		DatasetName: "sharegpt", RunType: "on_demand", Status: "pending",
+	}
+	runID, _ := repo.CreateBenchmarkRun(ctx, run)
+	if err := repo.UpdateRunStatus(ctx, runID, "running"); err != nil {
+		t.Fatalf("UpdateRunStatus: %v", err)
+	}
+	if err := repo.UpdateRunPhase(ctx, runID, "loadgen_running", &database.K8sResourceRefs{
+		Namespace: "default", JobName: jobName, JobUID: string(job.UID),
+	}); err != nil {
+		t.Fatalf("UpdateRunPhase: %v", err)
+	}
+
+	if err := o.Resume(ctx); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	waitForCondition(t, func() bool {
+		stored, _ := repo.GetBenchmarkRun(ctx, runID)
+		return stored.Status == "failed"
+	})
+
+	stored, _ := repo.GetBenchmarkRun(ctx, runID)
+	if stored.Status != "failed" {
+		t.Errorf("status = %q, want failed", stored.Status)
+	}
+	jobs, _ := client.BatchV1().Jobs("default").List(ctx, metav1.ListOptions{})
+	if len(jobs.Items) != 0 {
+		t.Error("expected the rebound job to be torn down after resume marked the run failed")
+	}
+}
+
+// waitForCondition polls cond every millisecond for up to a second, for
+// asserting on state Resume's fire-and-forget goroutines mutate
+// asynchronously.
+func waitForCondition(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if !cond() {
+		t.Fatal("condition not met before deadline")
+	}
+}
+
 // Suppress log output during tests.
 func init() {
 	_ = time.Now // ensure time is imported
@@ -0,0 +1,92 @@
+package orchestrator
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ReadyChecker reports whether a resource applyYAML just created has
+// actually settled into a usable state, dispatching on Kind the way
+// Helm's own statuscheck package does instead of every caller hand-
+// rolling a ReadyReplicas >= 1 check that only means something for a
+// Deployment. A Kind with no dedicated check is considered ready
+// immediately, the same assumption applyYAML made before this existed.
+type ReadyChecker struct {
+	client kubernetes.Interface
+}
+
+// newReadyChecker builds a ReadyChecker against client.
+func newReadyChecker(client kubernetes.Interface) *ReadyChecker {
+	return &ReadyChecker{client: client}
+}
+
+// IsReady dispatches to the per-kind readiness check for name in ns.
+func (r *ReadyChecker) IsReady(ctx context.Context, ns, name, kind string) (bool, error) {
+	switch kind {
+	case "Deployment":
+		return r.deploymentReady(ctx, ns, name)
+	case "Service":
+		return r.serviceReady(ctx, ns, name)
+	case "Job":
+		return r.jobReady(ctx, ns, name)
+	default:
+		return true, nil
+	}
+}
+
+// deploymentReady requires the controller to have observed the latest
+// spec (ObservedGeneration >= Generation, so a stale status from before
+// the last apply can't be mistaken for a converged rollout) and its
+// Available condition to be true. ReadyReplicas alone, the check
+// defaultReadinessProbe used to make, can't tell a genuinely settled
+// Deployment apart from one still converging after a spec change that
+// happens to already have one old replica up.
+func (r *ReadyChecker) deploymentReady(ctx context.Context, ns, name string) (bool, error) {
+	dep, err := r.client.AppsV1().Deployments(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	if dep.Status.ObservedGeneration < dep.Generation {
+		return false, nil
+	}
+	if dep.Status.ReadyReplicas < 1 {
+		return false, nil
+	}
+	for _, cond := range dep.Status.Conditions {
+		if cond.Type == appsv1.DeploymentAvailable {
+			return cond.Status == corev1.ConditionTrue, nil
+		}
+	}
+	return false, nil
+}
+
+// serviceReady is immediate for every Service type except LoadBalancer,
+// which isn't usable until the cloud provider assigns an ingress (an IP
+// or hostname). deployModel's model Service is always ClusterIP today, so
+// this only matters once something renders a LoadBalancer manifest.
+func (r *ReadyChecker) serviceReady(ctx context.Context, ns, name string) (bool, error) {
+	svc, err := r.client.CoreV1().Services(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return true, nil
+	}
+	return len(svc.Status.LoadBalancer.Ingress) > 0, nil
+}
+
+// jobReady reports whether name has started making progress: it has a
+// running Pod (Active > 0) or has already finished (Succeeded > 0). It
+// does not wait for the Job to finish — waitAndCollect's own poll over
+// JobComplete/JobFailed conditions remains the one place that does.
+func (r *ReadyChecker) jobReady(ctx context.Context, ns, name string) (bool, error) {
+	job, err := r.client.BatchV1().Jobs(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	return job.Status.Active > 0 || job.Status.Succeeded > 0, nil
+}
@@ -0,0 +1,134 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReadyChecker_DeploymentReady_ObservedGenerationStale(t *testing.T) {
+	client := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "bench-1", Namespace: "default", Generation: 2},
+		Status: appsv1.DeploymentStatus{
+			ReadyReplicas:      1,
+			ObservedGeneration: 1,
+			Conditions:         []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}},
+		},
+	})
+	rc := newReadyChecker(client)
+
+	ready, err := rc.deploymentReady(context.Background(), "default", "bench-1")
+	if err != nil {
+		t.Fatalf("deploymentReady: %v", err)
+	}
+	if ready {
+		t.Error("expected not ready while ObservedGeneration trails Generation")
+	}
+}
+
+func TestReadyChecker_DeploymentReady_NotAvailable(t *testing.T) {
+	client := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "bench-1", Namespace: "default", Generation: 1},
+		Status: appsv1.DeploymentStatus{
+			ReadyReplicas:      1,
+			ObservedGeneration: 1,
+		},
+	})
+	rc := newReadyChecker(client)
+
+	ready, err := rc.deploymentReady(context.Background(), "default", "bench-1")
+	if err != nil {
+		t.Fatalf("deploymentReady: %v", err)
+	}
+	if ready {
+		t.Error("expected not ready without an Available condition")
+	}
+}
+
+func TestReadyChecker_ServiceReady_ClusterIPImmediate(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "bench-1", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+	})
+	rc := newReadyChecker(client)
+
+	ready, err := rc.serviceReady(context.Background(), "default", "bench-1")
+	if err != nil {
+		t.Fatalf("serviceReady: %v", err)
+	}
+	if !ready {
+		t.Error("expected a ClusterIP Service to be ready immediately")
+	}
+}
+
+func TestReadyChecker_ServiceReady_LoadBalancerWaitsForIngress(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "bench-1", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	})
+	rc := newReadyChecker(client)
+
+	ready, err := rc.serviceReady(context.Background(), "default", "bench-1")
+	if err != nil {
+		t.Fatalf("serviceReady: %v", err)
+	}
+	if ready {
+		t.Error("expected LoadBalancer Service to not be ready without ingress")
+	}
+
+	svc, _ := client.CoreV1().Services("default").Get(context.Background(), "bench-1", metav1.GetOptions{})
+	svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+	client.CoreV1().Services("default").UpdateStatus(context.Background(), svc, metav1.UpdateOptions{})
+
+	ready, err = rc.serviceReady(context.Background(), "default", "bench-1")
+	if err != nil {
+		t.Fatalf("serviceReady: %v", err)
+	}
+	if !ready {
+		t.Error("expected LoadBalancer Service to be ready once ingress is assigned")
+	}
+}
+
+func TestReadyChecker_JobReady_ActiveAndSucceeded(t *testing.T) {
+	client := fake.NewSimpleClientset(&batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "loadgen-1", Namespace: "default"},
+	})
+	rc := newReadyChecker(client)
+
+	ready, err := rc.jobReady(context.Background(), "default", "loadgen-1")
+	if err != nil {
+		t.Fatalf("jobReady: %v", err)
+	}
+	if ready {
+		t.Error("expected a freshly-created Job to not be ready yet")
+	}
+
+	job, _ := client.BatchV1().Jobs("default").Get(context.Background(), "loadgen-1", metav1.GetOptions{})
+	job.Status.Active = 1
+	client.BatchV1().Jobs("default").UpdateStatus(context.Background(), job, metav1.UpdateOptions{})
+
+	ready, err = rc.jobReady(context.Background(), "default", "loadgen-1")
+	if err != nil {
+		t.Fatalf("jobReady: %v", err)
+	}
+	if !ready {
+		t.Error("expected an active Job to be ready")
+	}
+}
+
+func TestReadyChecker_IsReady_UnknownKind(t *testing.T) {
+	rc := newReadyChecker(fake.NewSimpleClientset())
+
+	ready, err := rc.IsReady(context.Background(), "default", "whatever", "ConfigMap")
+	if err != nil {
+		t.Fatalf("IsReady: %v", err)
+	}
+	if !ready {
+		t.Error("expected an unrecognized kind to be considered ready immediately")
+	}
+}
@@ -0,0 +1,137 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/metrics"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Resume scans for runs that were still non-terminal when the process
+// serving o.repo last stopped — i.e. orchestrator.Execute was managing them
+// mid-lifecycle when it died — and reconciles each one's Kubernetes
+// resources instead of leaving them orphaned in the cluster forever. It's
+// meant to be called once at process startup, before the scheduler starts
+// admitting new runs.
+//
+// Execute's in-memory state (the GPU scraper, the log-tailing goroutine,
+// the readiness wait) cannot be reconstructed after a crash, so Resume's
+// scope is intentionally narrower than Execute's: a run whose loadgen Job
+// is still present has its completion awaited and its output parsed and
+// persisted (without accelerator metrics, since those were only ever
+// sampled by the crashed process's live scraper); a run whose Job never got
+// created, or whose Job has gone missing or been stuck for longer than
+// resumeOrphanTTL, has its remaining Kubernetes objects torn down and is
+// marked failed.
+func (o *Orchestrator) Resume(ctx context.Context) error {
+	runs, err := o.repo.ListNonTerminalRuns(ctx)
+	if err != nil {
+		return fmt.Errorf("list non-terminal runs: %w", err)
+	}
+	log.Printf("resume: reconciling %d non-terminal run(s)", len(runs))
+
+	for _, run := range runs {
+		run := run
+		runCtx, cancel := context.WithCancel(ctx)
+		o.mu.Lock()
+		o.cancels[run.ID] = cancel
+		o.mu.Unlock()
+		go func() {
+			defer func() {
+				o.mu.Lock()
+				delete(o.cancels, run.ID)
+				o.mu.Unlock()
+				cancel()
+			}()
+			o.resumeRun(runCtx, run)
+		}()
+	}
+	return nil
+}
+
+// resumeRun reconciles one run Resume found non-terminal. It never panics
+// or returns an error of its own: like Execute, a failure partway through
+// is recorded on the run itself (markFailed) rather than propagated,
+// since Resume fires several of these concurrently and one run's trouble
+// must not stop the others from being reconciled.
+func (o *Orchestrator) resumeRun(ctx context.Context, run database.BenchmarkRun) {
+	ns := defaultNamespace
+	if run.K8sResources != nil && run.K8sResources.Namespace != "" {
+		ns = run.K8sResources.Namespace
+	}
+	modelName := fmt.Sprintf("bench-%s", run.ID[:8])
+	loadgenName := fmt.Sprintf("loadgen-%s", run.ID[:8])
+
+	orphaned := run.StartedAt == nil || time.Since(*run.StartedAt) > o.resumeOrphanTTL
+
+	var jobExists, jobDone bool
+	if run.K8sResources != nil && run.K8sResources.JobName != "" {
+		job, err := o.client.BatchV1().Jobs(ns).Get(ctx, loadgenName, metav1.GetOptions{})
+		switch {
+		case err == nil && run.K8sResources.JobUID != "" && string(job.UID) != run.K8sResources.JobUID:
+			// The name was reused by a different object — the Job we
+			// created is gone and something else now owns this name.
+			// Nothing to rebind to.
+			jobExists = false
+		case err == nil:
+			jobExists = true
+			for _, cond := range job.Status.Conditions {
+				if (cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed) && cond.Status == corev1.ConditionTrue {
+					jobDone = true
+				}
+			}
+		case apierrors.IsNotFound(err):
+			jobExists = false
+		default:
+			log.Printf("[%s] resume: get job %s: %v", run.ID[:8], loadgenName, err)
+		}
+	}
+
+	if !jobExists || (orphaned && !jobDone) {
+		log.Printf("[%s] resume: tearing down orphaned resources (job_exists=%v orphaned=%v)", run.ID[:8], jobExists, orphaned)
+		o.teardown(ctx, run.ID, ns, modelName, loadgenName)
+		o.markFailed(ctx, run.ID, "", run.Framework, "resume",
+			"orchestrator restarted mid-run and could not rebind to a live loadgen job")
+		return
+	}
+
+	log.Printf("[%s] resume: rebinding to existing resources, waiting for loadgen job %s", run.ID[:8], loadgenName)
+	logData, err := o.waitAndCollect(ctx, ns, loadgenName)
+	if err != nil {
+		o.teardown(ctx, run.ID, ns, modelName, loadgenName)
+		o.markFailed(ctx, run.ID, "", run.Framework, "resume", fmt.Sprintf("resumed wait for loadgen job failed: %v", err))
+		return
+	}
+
+	output, err := metrics.ParseLoadgenOutput(logData)
+	if err != nil {
+		o.teardown(ctx, run.ID, ns, modelName, loadgenName)
+		o.markFailed(ctx, run.ID, "", run.Framework, "resume", fmt.Sprintf("resumed parse of loadgen output failed: %v", err))
+		return
+	}
+
+	// Accelerator metrics and a live pricing context were only ever sampled
+	// by the crashed process's in-memory GPU scraper, so a resumed run's
+	// metrics necessarily omit them.
+	computed := metrics.ComputeMetrics(output, nil)
+	computed.RunID = run.ID
+	if err := o.repo.PersistMetrics(ctx, run.ID, computed, nil); err != nil {
+		log.Printf("[%s] resume: persist metrics: %v", run.ID[:8], err)
+		o.markFailed(ctx, run.ID, "", run.Framework, "resume", "resumed run collected results but failed to persist them")
+		return
+	}
+	if err := o.repo.UpdateRunStatus(ctx, run.ID, "completed"); err != nil {
+		log.Printf("[%s] resume: update status to completed: %v", run.ID[:8], err)
+		return
+	}
+	o.teardown(ctx, run.ID, ns, modelName, loadgenName)
+	log.Printf("[%s] resume: completed successfully", run.ID[:8])
+}
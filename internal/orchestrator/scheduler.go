@@ -0,0 +1,545 @@
+package orchestrator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/metrics"
+)
+
+// defaultMaxConcurrentPerFamily caps how many runs the Scheduler will
+// dispatch to the same instance family at once, so a burst of submissions
+// against a scarce family (e.g. p5) can't starve the cluster's node pool
+// the way the old fire-and-forget goroutine per POST /runs did.
+const defaultMaxConcurrentPerFamily = 2
+
+// defaultLeaseDuration is how long a claimed run's lease lasts before
+// database.LeasedRunQueue.ClaimRun treats it as abandoned and lets another
+// worker reclaim it, when the Scheduler's repo supports leasing. admitLeased
+// renews it well before expiry (see leaseHeartbeatInterval), so this only
+// matters if a worker dies mid-run without releasing its claim.
+const defaultLeaseDuration = 2 * time.Minute
+
+// leaseHeartbeatInterval is how often admitLeased renews a claimed run's
+// lease while it executes, comfortably inside defaultLeaseDuration so a
+// single missed tick (a slow database round trip, a brief network blip)
+// doesn't let another worker reclaim a run that's still actively running.
+const leaseHeartbeatInterval = 30 * time.Second
+
+// schedulerMetrics holds the instruments the Scheduler emits, created
+// against an injected metrics.Registry for the same reason
+// orchestratorMetrics is: multiple Schedulers in one process shouldn't
+// collide on metric names, and tests can substitute a NoopRegistry.
+type schedulerMetrics struct {
+	queueDepth metrics.GaugeVec // instance_family
+}
+
+func newSchedulerMetrics(reg metrics.Registry) *schedulerMetrics {
+	return &schedulerMetrics{
+		queueDepth: reg.Gauge("accelbench_scheduler_queue_depth",
+			"Number of runs currently waiting in the admission queue.", "instance_family"),
+	}
+}
+
+// SchedulerOption configures optional Scheduler behavior at construction time.
+type SchedulerOption func(*Scheduler)
+
+// WithMaxConcurrentPerFamily overrides the default per-instance-family
+// concurrency cap.
+func WithMaxConcurrentPerFamily(n int) SchedulerOption {
+	return func(s *Scheduler) {
+		if n > 0 {
+			s.maxConcurrentPerFamily = n
+		}
+	}
+}
+
+// WithSchedulerMetricsRegistry overrides the default Prometheus-backed
+// metrics registry, e.g. with metrics.NewNoopRegistry() in tests.
+func WithSchedulerMetricsRegistry(reg metrics.Registry) SchedulerOption {
+	return func(s *Scheduler) { s.metrics = newSchedulerMetrics(reg) }
+}
+
+// WithMaxRetries enables automatic retry of a failed run's execution, up
+// to n attempts, with exponential backoff starting at retryBaseDelay.
+// Disabled (n == 0) by default: most transient failures are operator-
+// actionable (bad model ref, quota exhaustion) and an unconditional retry
+// would just mask them, so this is opt-in per deployment.
+func WithMaxRetries(n int) SchedulerOption {
+	return func(s *Scheduler) {
+		if n > 0 {
+			s.maxRetries = n
+		}
+	}
+}
+
+// WithMaxConcurrentPerUser caps how many runs submitted by the same UserID
+// may execute at once, so one user's burst of submissions can't starve
+// everyone else sharing the same instance family. Zero (the default) means
+// unlimited, matching the pre-existing behavior for runs with no UserID.
+func WithMaxConcurrentPerUser(n int) SchedulerOption {
+	return func(s *Scheduler) {
+		if n > 0 {
+			s.maxConcurrentPerUser = n
+		}
+	}
+}
+
+// WithLeaseDuration overrides the default lease duration used when the
+// Scheduler's repo implements database.LeasedRunQueue.
+func WithLeaseDuration(d time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		if d > 0 {
+			s.leaseDuration = d
+		}
+	}
+}
+
+// retryBaseDelay is the delay before the first automatic retry; each
+// subsequent attempt doubles it (1st retry after 1x, 2nd after 2x, 3rd
+// after 4x, ...).
+const retryBaseDelay = 10 * time.Second
+
+// Scheduler admits benchmark runs into a persistent queue and dispatches
+// them to the Orchestrator under a per-instance-family concurrency cap,
+// replacing the unbounded "one goroutine per POST /runs" dispatch that let
+// a burst of submissions against a scarce instance family starve the
+// cluster. Enqueue persists the run and wakes the dispatch loop; Start
+// reloads anything still queued from a prior process (so a restart doesn't
+// lose admitted runs) and begins dispatching.
+type Scheduler struct {
+	orch *Orchestrator
+	repo database.Repo
+
+	maxConcurrentPerFamily int
+	maxConcurrentPerUser   int
+	maxRetries             int
+	leaseDuration          time.Duration
+	workerID               string
+	metrics                *schedulerMetrics
+
+	mu             sync.Mutex
+	inFlight       map[string]int  // instance family -> count currently executing
+	inFlightByUser map[string]int  // user ID -> count currently executing
+	admitted       map[string]bool // run ID -> already admitted, dequeue not yet observed
+	retryAttempts  map[string]int  // run ID -> retries already scheduled
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewScheduler creates a Scheduler dispatching onto orch. Call Start to
+// begin the dispatch loop.
+func NewScheduler(orch *Orchestrator, repo database.Repo, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		orch:                   orch,
+		repo:                   repo,
+		maxConcurrentPerFamily: defaultMaxConcurrentPerFamily,
+		leaseDuration:          defaultLeaseDuration,
+		workerID:               newWorkerID(),
+		metrics:                newSchedulerMetrics(metrics.NewPrometheusRegistry()),
+		inFlight:               make(map[string]int),
+		inFlightByUser:         make(map[string]int),
+		admitted:               make(map[string]bool),
+		retryAttempts:          make(map[string]int),
+		wake:                   make(chan struct{}, 1),
+		done:                   make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// newWorkerID generates an identifier this Scheduler instance presents as
+// workerID to database.LeasedRunQueue.ClaimRun/HeartbeatRun, so a claim this
+// process holds is distinguishable from one held by another orchestrator
+// process sharing the same backend. Host-plus-pid disambiguates separate
+// machines and separate processes on one machine; the random suffix covers
+// a process restarting under the same pid before its prior leases expire.
+func newWorkerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Start reloads any runs left queued by a prior process and begins the
+// background dispatch loop. Safe to call only once.
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.loop(ctx)
+}
+
+// Stop cancels the dispatch loop and waits for it to exit. Runs already
+// dispatched to the Orchestrator keep executing.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	<-s.done
+}
+
+// Enqueue admits a run into the persistent queue and wakes the dispatch
+// loop. instanceFamily is the InstanceType.Family the run targets, used to
+// apply the per-family concurrency cap; userID (empty if the submitter
+// carried no identity) is used to apply the per-user concurrency cap.
+func (s *Scheduler) Enqueue(ctx context.Context, runID, instanceFamily, userID string, priority int) error {
+	if err := s.repo.EnqueueRun(ctx, runID, instanceFamily, userID, priority); err != nil {
+		return fmt.Errorf("enqueue run: %w", err)
+	}
+	s.notify()
+	return nil
+}
+
+// SetPriority updates a still-queued run's priority and wakes the dispatch
+// loop so a newly-urgent run can preempt the order immediately rather than
+// waiting for the next poll tick.
+func (s *Scheduler) SetPriority(ctx context.Context, runID string, priority int) error {
+	if err := s.repo.SetRunPriority(ctx, runID, priority); err != nil {
+		return err
+	}
+	s.notify()
+	return nil
+}
+
+// Requeue re-admits a run that is no longer queued (completed, failed, or
+// canceled) using its persisted OriginalRequest, for POST
+// /runs/{id}/requeue. It returns ErrRunNotRequeueable if the run is still
+// pending, running, or queued, ErrRunNotFound if it doesn't exist, and
+// errors if the run has no OriginalRequest on file (runs created before
+// this field existed) or its instance type can't be resolved.
+func (s *Scheduler) Requeue(ctx context.Context, runID string, priority int) error {
+	run, err := s.repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("get run: %w", err)
+	}
+	if run == nil {
+		return database.ErrRunNotFound
+	}
+	switch run.Status {
+	case "pending", "running", "queued":
+		return database.ErrRunNotRequeueable
+	}
+	if run.OriginalRequest == nil {
+		return fmt.Errorf("run %s has no original request to requeue", runID)
+	}
+	instType, err := s.repo.GetInstanceTypeByName(ctx, run.OriginalRequest.InstanceTypeName)
+	if err != nil {
+		return fmt.Errorf("lookup instance type: %w", err)
+	}
+	if instType == nil {
+		return fmt.Errorf("instance type %s not found", run.OriginalRequest.InstanceTypeName)
+	}
+	// Enqueue moves the run's status to "queued" atomically with the
+	// run_queue insert (see Repo.EnqueueRun), so there's no window where
+	// a fast dispatch could race a separate status update here and leave
+	// the run's terminal outcome clobbered back to "queued".
+	return s.Enqueue(ctx, runID, instType.Family, run.OriginalRequest.UserID, priority)
+}
+
+// notify wakes the dispatch loop without blocking if it's already awake.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatchPoll bounds how long the loop waits between admission passes
+// when nothing woke it explicitly, so a run that becomes dispatchable only
+// because an in-flight run finished (and so never calls notify) is still
+// picked up promptly.
+const dispatchPoll = 5 * time.Second
+
+// loop is the Scheduler's background dispatch goroutine: on every wake or
+// poll tick it walks the queue in priority order and admits every run
+// whose instance family is under its concurrency cap.
+func (s *Scheduler) loop(ctx context.Context) {
+	defer close(s.done)
+	ticker := time.NewTicker(dispatchPoll)
+	defer ticker.Stop()
+
+	s.dispatch(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+			s.dispatch(ctx)
+		case <-ticker.C:
+			s.dispatch(ctx)
+		}
+	}
+}
+
+// dispatch admits queued runs, in priority order, up to the per-family and
+// per-user concurrency caps. If the Scheduler's repo implements
+// database.LeasedRunQueue, it claims runs through that interface instead of
+// the in-memory admitted/inFlight maps below, which is the only dispatch
+// path safe when more than one orchestrator process polls the same backend
+// (the in-memory maps only guard against double-admission within this one
+// process).
+func (s *Scheduler) dispatch(ctx context.Context) {
+	if leased, ok := s.repo.(database.LeasedRunQueue); ok {
+		s.dispatchLeased(ctx, leased)
+		return
+	}
+
+	queued, err := s.repo.ListQueuedRuns(ctx)
+	if err != nil {
+		log.Printf("scheduler: list queued runs: %v", err)
+		return
+	}
+
+	depths := make(map[string]int)
+	for _, q := range queued {
+		depths[q.InstanceFamily]++
+	}
+	for family, depth := range depths {
+		s.metrics.queueDepth.WithLabelValues(family).Set(float64(depth))
+	}
+
+	for _, q := range queued {
+		s.mu.Lock()
+		// s.admitted guards against the same run being handed to a second
+		// admit() goroutine: ListQueuedRuns is a snapshot, and a run stays
+		// in it until the first admit() goroutine's DequeueRun call
+		// completes, so a dispatch() triggered in between would otherwise
+		// see it as still-queued and admit it again.
+		admit := !s.admitted[q.RunID] &&
+			s.inFlight[q.InstanceFamily] < s.maxConcurrentPerFamily &&
+			(s.maxConcurrentPerUser == 0 || q.UserID == "" || s.inFlightByUser[q.UserID] < s.maxConcurrentPerUser)
+		if admit {
+			s.admitted[q.RunID] = true
+			s.inFlight[q.InstanceFamily]++
+			if q.UserID != "" {
+				s.inFlightByUser[q.UserID]++
+			}
+		}
+		s.mu.Unlock()
+		if !admit {
+			continue
+		}
+		go s.admit(q)
+	}
+}
+
+// dispatchLeased claims as many quota-admissible runs as leased.ClaimRun
+// will hand out in one pass, updating the queue-depth gauge from the claim
+// attempt itself rather than a separate ListQueuedRuns call (leased backends
+// don't need the in-memory admitted map, since ClaimRun's FOR UPDATE SKIP
+// LOCKED already makes a claim exclusive).
+func (s *Scheduler) dispatchLeased(ctx context.Context, leased database.LeasedRunQueue) {
+	quota := database.Quota{
+		MaxConcurrentPerFamily: s.maxConcurrentPerFamily,
+		MaxConcurrentPerUser:   s.maxConcurrentPerUser,
+	}
+	for {
+		q, err := leased.ClaimRun(ctx, s.workerID, s.leaseDuration, quota)
+		if err != nil {
+			log.Printf("scheduler: claim run: %v", err)
+			return
+		}
+		if q == nil {
+			return
+		}
+		go s.admitLeased(leased, *q)
+	}
+}
+
+// admit dequeues and executes a single run, recording its queue wait and
+// execution duration, then releases its family's concurrency slot and
+// wakes the loop so a run it was blocking can be admitted. It runs on
+// context.Background() rather than the dispatch loop's context, so
+// Stop() cancelling the loop can't abort a run already handed to the
+// Orchestrator — matching how the pre-scheduler fire-and-forget dispatch
+// always executed runs on a context independent of any request or loop
+// lifetime; in-flight runs are only stopped via Orchestrator.CancelRun.
+func (s *Scheduler) admit(q database.QueuedRun) {
+	ctx := context.Background()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight[q.InstanceFamily]--
+		if q.UserID != "" {
+			s.inFlightByUser[q.UserID]--
+		}
+		delete(s.admitted, q.RunID)
+		s.mu.Unlock()
+		s.notify()
+	}()
+
+	if err := s.repo.DequeueRun(ctx, q.RunID); err != nil {
+		log.Printf("scheduler: dequeue run %s: %v", q.RunID, err)
+		return
+	}
+	if err := s.repo.RecordQueueWait(ctx, q.RunID, time.Since(q.EnqueuedAt).Seconds()); err != nil {
+		log.Printf("scheduler: record queue wait for run %s: %v", q.RunID, err)
+	}
+
+	cfg, err := s.buildRunConfig(ctx, q.RunID)
+	if err != nil {
+		log.Printf("scheduler: build run config for %s: %v", q.RunID, err)
+		s.orch.markFailed(ctx, q.RunID, "", "", "dispatch", "")
+		return
+	}
+
+	start := time.Now()
+	if err := s.orch.Execute(ctx, *cfg); err != nil {
+		log.Printf("scheduler: benchmark run %s failed: %v", q.RunID, err)
+		// A run canceled via POST /runs/{id}/cancel surfaces here as
+		// Execute returning context.Canceled (CancelRun cancels the
+		// derived context Execute registered for this run ID); that's a
+		// deliberate stop, not a transient failure, so it must not be
+		// retried.
+		if !errors.Is(err, context.Canceled) {
+			s.maybeRetry(q)
+		}
+	} else {
+		s.mu.Lock()
+		delete(s.retryAttempts, q.RunID)
+		s.mu.Unlock()
+	}
+	if err := s.repo.RecordExecutionDuration(ctx, q.RunID, time.Since(start).Seconds()); err != nil {
+		log.Printf("scheduler: record execution duration for run %s: %v", q.RunID, err)
+	}
+}
+
+// admitLeased executes a run claimed through database.LeasedRunQueue,
+// keeping its lease alive with periodic HeartbeatRun calls for as long as
+// Execute runs — unlike admit, it deliberately leaves the run_queue row in
+// place until Execute returns, rather than dequeuing it up front, so that a
+// worker that crashes mid-run leaves a row another worker's ClaimRun can
+// reclaim once the lease expires instead of losing the run entirely.
+func (s *Scheduler) admitLeased(leased database.LeasedRunQueue, q database.QueuedRun) {
+	ctx := context.Background()
+
+	stopHeartbeat := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(leaseHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopHeartbeat:
+				return
+			case <-ticker.C:
+				if err := leased.HeartbeatRun(ctx, q.RunID, s.workerID, s.leaseDuration); err != nil {
+					log.Printf("scheduler: heartbeat run %s: %v", q.RunID, err)
+					return
+				}
+			}
+		}
+	}()
+	defer close(stopHeartbeat)
+
+	if err := s.repo.RecordQueueWait(ctx, q.RunID, time.Since(q.EnqueuedAt).Seconds()); err != nil {
+		log.Printf("scheduler: record queue wait for run %s: %v", q.RunID, err)
+	}
+
+	cfg, err := s.buildRunConfig(ctx, q.RunID)
+	if err != nil {
+		log.Printf("scheduler: build run config for %s: %v", q.RunID, err)
+		s.orch.markFailed(ctx, q.RunID, "", "", "dispatch", "")
+		if derr := s.repo.DequeueRun(ctx, q.RunID); derr != nil {
+			log.Printf("scheduler: dequeue run %s: %v", q.RunID, derr)
+		}
+		return
+	}
+
+	start := time.Now()
+	if err := s.orch.Execute(ctx, *cfg); err != nil {
+		log.Printf("scheduler: benchmark run %s failed: %v", q.RunID, err)
+		if !errors.Is(err, context.Canceled) {
+			s.maybeRetry(q)
+		}
+	} else {
+		s.mu.Lock()
+		delete(s.retryAttempts, q.RunID)
+		s.mu.Unlock()
+	}
+	if err := s.repo.RecordExecutionDuration(ctx, q.RunID, time.Since(start).Seconds()); err != nil {
+		log.Printf("scheduler: record execution duration for run %s: %v", q.RunID, err)
+	}
+	if err := s.repo.DequeueRun(ctx, q.RunID); err != nil {
+		log.Printf("scheduler: dequeue run %s: %v", q.RunID, err)
+	}
+}
+
+// maybeRetry re-admits q's run after an exponentially increasing delay if
+// it hasn't yet exhausted maxRetries, logging and giving up silently
+// otherwise (the run's status already reads "failed", set by whichever
+// lifecycle phase raised the error Execute returned). Scheduling happens
+// via time.AfterFunc rather than blocking admit, since a retry with a
+// multi-minute backoff shouldn't hold the family's concurrency slot that
+// long.
+func (s *Scheduler) maybeRetry(q database.QueuedRun) {
+	if s.maxRetries == 0 {
+		return
+	}
+	s.mu.Lock()
+	attempt := s.retryAttempts[q.RunID] + 1
+	if attempt > s.maxRetries {
+		delete(s.retryAttempts, q.RunID)
+		s.mu.Unlock()
+		return
+	}
+	s.retryAttempts[q.RunID] = attempt
+	s.mu.Unlock()
+
+	delay := retryBaseDelay * time.Duration(1<<uint(attempt-1))
+	log.Printf("scheduler: retrying run %s in %s (attempt %d/%d)", q.RunID, delay, attempt, s.maxRetries)
+	time.AfterFunc(delay, func() {
+		if err := s.Enqueue(context.Background(), q.RunID, q.InstanceFamily, q.UserID, q.Priority); err != nil {
+			log.Printf("scheduler: requeue run %s for retry: %v", q.RunID, err)
+		}
+	})
+}
+
+// buildRunConfig rebuilds the orchestrator.RunConfig for a queued run from
+// its persisted OriginalRequest, the same lookups handleCreateRun performs
+// inline, so the dispatch loop can admit a run that was queued by an
+// earlier process.
+func (s *Scheduler) buildRunConfig(ctx context.Context, runID string) (*RunConfig, error) {
+	run, err := s.repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("get run: %w", err)
+	}
+	if run == nil {
+		return nil, fmt.Errorf("run %s not found", runID)
+	}
+	if run.OriginalRequest == nil {
+		return nil, fmt.Errorf("run %s has no original request", runID)
+	}
+	req := run.OriginalRequest
+
+	model, err := s.repo.EnsureModel(ctx, req.ModelHfID, req.ModelHfRevision)
+	if err != nil {
+		return nil, fmt.Errorf("ensure model: %w", err)
+	}
+	instType, err := s.repo.GetInstanceTypeByName(ctx, req.InstanceTypeName)
+	if err != nil {
+		return nil, fmt.Errorf("lookup instance type: %w", err)
+	}
+	if instType == nil {
+		return nil, fmt.Errorf("instance type %s not found", req.InstanceTypeName)
+	}
+
+	return &RunConfig{
+		RunID:        runID,
+		Model:        model,
+		InstanceType: instType,
+		Request:      req,
+	}, nil
+}
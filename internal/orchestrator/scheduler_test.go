@@ -0,0 +1,147 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/accelbench/accelbench/internal/database"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestScheduler() (*Scheduler, *database.MockRepo) {
+	client := fake.NewSimpleClientset()
+	repo := database.NewMockRepo()
+	orch := New(client, repo)
+	return NewScheduler(orch, repo), repo
+}
+
+func TestNewScheduler(t *testing.T) {
+	sched, _ := newTestScheduler()
+	if sched == nil {
+		t.Fatal("NewScheduler returned nil")
+	}
+}
+
+func TestScheduler_Enqueue_PersistsQueuedRun(t *testing.T) {
+	sched, repo := newTestScheduler()
+	ctx := context.Background()
+
+	if err := sched.Enqueue(ctx, "run-1", "g5", "user-1", 3); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	queued, err := repo.ListQueuedRuns(ctx)
+	if err != nil {
+		t.Fatalf("ListQueuedRuns: %v", err)
+	}
+	if len(queued) != 1 {
+		t.Fatalf("expected 1 queued run, got %d", len(queued))
+	}
+	if queued[0].RunID != "run-1" || queued[0].InstanceFamily != "g5" || queued[0].UserID != "user-1" || queued[0].Priority != 3 {
+		t.Errorf("unexpected queued run: %+v", queued[0])
+	}
+}
+
+func TestScheduler_SetPriority_NotQueuedReturnsErr(t *testing.T) {
+	sched, _ := newTestScheduler()
+	ctx := context.Background()
+
+	err := sched.SetPriority(ctx, "never-queued", 5)
+	if !errors.Is(err, database.ErrRunNotQueued) {
+		t.Fatalf("SetPriority on unqueued run: got %v, want ErrRunNotQueued", err)
+	}
+}
+
+func TestScheduler_SetPriority_UpdatesQueuedRun(t *testing.T) {
+	sched, repo := newTestScheduler()
+	ctx := context.Background()
+
+	if err := sched.Enqueue(ctx, "run-1", "g5", "", 0); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := sched.SetPriority(ctx, "run-1", 9); err != nil {
+		t.Fatalf("SetPriority: %v", err)
+	}
+
+	queued, _ := repo.ListQueuedRuns(ctx)
+	if len(queued) != 1 || queued[0].Priority != 9 {
+		t.Fatalf("expected updated priority 9, got %+v", queued)
+	}
+}
+
+func TestScheduler_Requeue_NotFoundReturnsErr(t *testing.T) {
+	sched, _ := newTestScheduler()
+	ctx := context.Background()
+
+	err := sched.Requeue(ctx, "missing-run", 0)
+	if !errors.Is(err, database.ErrRunNotFound) {
+		t.Fatalf("Requeue on missing run: got %v, want ErrRunNotFound", err)
+	}
+}
+
+func TestScheduler_Requeue_ReadmitsCompletedRun(t *testing.T) {
+	sched, repo := newTestScheduler()
+	ctx := context.Background()
+
+	runID, err := repo.CreateBenchmarkRun(ctx, &database.BenchmarkRun{
+		ModelID:        "model-001",
+		InstanceTypeID: "inst-001",
+		Status:         "completed",
+		OriginalRequest: &database.RunRequest{
+			ModelHfID:        "meta-llama/Llama-3.1-8B",
+			ModelHfRevision:  "abc123",
+			InstanceTypeName: "g5.xlarge",
+			Framework:        "vllm",
+			RunType:          "on_demand",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateBenchmarkRun: %v", err)
+	}
+	repo.SeedInstanceType(&database.InstanceType{ID: "inst-001", Name: "g5.xlarge", Family: "g5"})
+
+	if err := sched.Requeue(ctx, runID, 2); err != nil {
+		t.Fatalf("Requeue: %v", err)
+	}
+
+	queued, err := repo.ListQueuedRuns(ctx)
+	if err != nil {
+		t.Fatalf("ListQueuedRuns: %v", err)
+	}
+	if len(queued) != 1 || queued[0].RunID != runID || queued[0].Priority != 2 {
+		t.Fatalf("expected run %s requeued at priority 2, got %+v", runID, queued)
+	}
+
+	run, err := repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		t.Fatalf("GetBenchmarkRun: %v", err)
+	}
+	if run.Status != "queued" {
+		t.Errorf("status = %q, want queued after requeue", run.Status)
+	}
+}
+
+func TestScheduler_Requeue_AlreadyQueuedReturnsErr(t *testing.T) {
+	sched, repo := newTestScheduler()
+	ctx := context.Background()
+
+	runID, err := repo.CreateBenchmarkRun(ctx, &database.BenchmarkRun{
+		ModelID:        "model-001",
+		InstanceTypeID: "inst-001",
+		Status:         "running",
+		OriginalRequest: &database.RunRequest{
+			ModelHfID:        "meta-llama/Llama-3.1-8B",
+			InstanceTypeName: "g5.xlarge",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateBenchmarkRun: %v", err)
+	}
+
+	err = sched.Requeue(ctx, runID, 0)
+	if !errors.Is(err, database.ErrRunNotRequeueable) {
+		t.Fatalf("Requeue on running run: got %v, want ErrRunNotRequeueable", err)
+	}
+}
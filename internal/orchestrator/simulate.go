@@ -0,0 +1,61 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/accelbench/accelbench/internal/metrics"
+	"github.com/accelbench/accelbench/internal/orchestrator/simulator"
+)
+
+// Simulate predicts a benchmark run's outcome entirely in-process, without
+// deploying anything to Kubernetes: it estimates feasibility and throughput
+// from the coarse roofline in the simulator package, then persists the
+// result through the same database.Repo path Execute uses, so a simulated
+// run shows up in ListCatalog/compare alongside real ones. Callers are
+// expected to have already created cfg.RunID's row with RunType="simulated",
+// the same contract Execute has with its caller.
+func (o *Orchestrator) Simulate(ctx context.Context, cfg RunConfig) (*metrics.LoadgenOutput, error) {
+	if cfg.Model.ParameterCount == nil {
+		return nil, fmt.Errorf("simulate run %s: model %s has no known parameter count", cfg.RunID, cfg.Model.HfID)
+	}
+
+	params := simulator.Params{
+		ParameterCount:       *cfg.Model.ParameterCount,
+		AcceleratorName:      cfg.InstanceType.AcceleratorName,
+		AcceleratorCount:     cfg.InstanceType.AcceleratorCount,
+		AcceleratorMemoryGiB: cfg.InstanceType.AcceleratorMemoryGiB,
+		TensorParallelDegree: cfg.Request.TensorParallelDegree,
+		Concurrency:          cfg.Request.Concurrency,
+		InputSequenceLength:  cfg.Request.InputSequenceLength,
+		OutputSequenceLength: cfg.Request.OutputSequenceLength,
+	}
+	if cfg.Request.Quantization != nil {
+		params.Quantization = *cfg.Request.Quantization
+	}
+
+	if err := o.repo.UpdateRunStatus(ctx, cfg.RunID, "running"); err != nil {
+		return nil, fmt.Errorf("update status to running: %w", err)
+	}
+
+	output, err := simulator.Run(params)
+	if err != nil {
+		o.markFailed(ctx, cfg.RunID, cfg.InstanceType.Name, cfg.Request.Framework, "simulate", "")
+		return nil, fmt.Errorf("simulate: %w", err)
+	}
+
+	computed := metrics.ComputeMetrics(output, o.resolvePricingContext(ctx, cfg.InstanceType.Name))
+	computed.RunID = cfg.RunID
+	if err := o.repo.PersistMetrics(ctx, cfg.RunID, computed, nil); err != nil {
+		o.markFailed(ctx, cfg.RunID, cfg.InstanceType.Name, cfg.Request.Framework, "persist", "")
+		return nil, fmt.Errorf("persist metrics: %w", err)
+	}
+
+	if err := o.repo.UpdateRunStatus(ctx, cfg.RunID, "completed"); err != nil {
+		return nil, fmt.Errorf("update status to completed: %w", err)
+	}
+
+	log.Printf("[%s] simulation completed", cfg.RunID[:8])
+	return output, nil
+}
@@ -0,0 +1,99 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/accelbench/accelbench/internal/database"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testSimulateRunConfig(t *testing.T, repo *database.MockRepo) RunConfig {
+	t.Helper()
+	params := int64(7_000_000_000)
+	run := &database.BenchmarkRun{
+		ModelID: "m1", InstanceTypeID: "i1",
+		Framework: "vllm", FrameworkVersion: "v0.6.0",
+		TensorParallelDegree: 1, Concurrency: 16,
+		InputSequenceLength: 512, OutputSequenceLength: 256,
+		DatasetName: "sharegpt", RunType: "simulated", Status: "pending",
+	}
+	runID, err := repo.CreateBenchmarkRun(context.Background(), run)
+	if err != nil {
+		t.Fatalf("CreateBenchmarkRun: %v", err)
+	}
+
+	return RunConfig{
+		RunID: runID,
+		Model: &database.Model{
+			ID:             "m1",
+			HfID:           "meta-llama/Llama-3.1-8B",
+			ParameterCount: &params,
+		},
+		InstanceType: &database.InstanceType{
+			ID:                   "i1",
+			Name:                 "g5.xlarge",
+			Family:               "g5",
+			AcceleratorType:      "gpu",
+			AcceleratorName:      "A10G",
+			AcceleratorCount:     1,
+			AcceleratorMemoryGiB: 24,
+			VCPUs:                4,
+			MemoryGiB:            16,
+		},
+		Request: &database.RunRequest{
+			ModelHfID:            "meta-llama/Llama-3.1-8B",
+			InstanceTypeName:     "g5.xlarge",
+			TensorParallelDegree: 1,
+			Concurrency:          16,
+			InputSequenceLength:  512,
+			OutputSequenceLength: 256,
+			RunType:              "simulated",
+		},
+	}
+}
+
+func TestSimulate_PersistsMetricsAndCompletes(t *testing.T) {
+	repo := database.NewMockRepo()
+	o := New(fake.NewSimpleClientset(), repo)
+	cfg := testSimulateRunConfig(t, repo)
+
+	out, err := o.Simulate(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if out == nil || len(out.Requests) == 0 {
+		t.Fatal("expected a non-empty synthetic LoadgenOutput")
+	}
+	if got := repo.GetRunStatus(cfg.RunID); got != "completed" {
+		t.Errorf("run status = %s, want completed", got)
+	}
+}
+
+func TestSimulate_MarksFailedWhenInfeasible(t *testing.T) {
+	repo := database.NewMockRepo()
+	o := New(fake.NewSimpleClientset(), repo)
+	cfg := testSimulateRunConfig(t, repo)
+
+	tooBig := int64(70_000_000_000)
+	cfg.Model.ParameterCount = &tooBig
+
+	if _, err := o.Simulate(context.Background(), cfg); err == nil {
+		t.Fatal("expected an error when the model doesn't fit")
+	}
+	if got := repo.GetRunStatus(cfg.RunID); got != "failed" {
+		t.Errorf("run status = %s, want failed", got)
+	}
+}
+
+func TestSimulate_ErrorsWhenParameterCountUnknown(t *testing.T) {
+	repo := database.NewMockRepo()
+	o := New(fake.NewSimpleClientset(), repo)
+	cfg := testSimulateRunConfig(t, repo)
+	cfg.Model.ParameterCount = nil
+
+	if _, err := o.Simulate(context.Background(), cfg); err == nil {
+		t.Fatal("expected an error when the model's parameter count is unknown")
+	}
+}
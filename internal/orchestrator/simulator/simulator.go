@@ -0,0 +1,187 @@
+// Package simulator analytically predicts a benchmark run's outcome without
+// touching Kubernetes. Given model size, instance accelerator specs, and
+// workload shape, Run produces the same metrics.LoadgenOutput shape a real
+// loadgen run against a live deployment would, so it can be parsed and
+// persisted through the normal metrics.ComputeMetrics path.
+//
+// Throughput is bounded by a coarse roofline, the same style of
+// approximation internal/recommend uses for KV-cache sizing: prefill and
+// decode are each modeled as the slower of compute-bound or
+// memory-bandwidth-bound, and the simulated run is capped by whichever
+// bound is tighter. This is deliberately analytic rather than stochastic —
+// every simulated request gets identical synthetic timings — since the
+// point is a fast, reproducible estimate across many (instance, TP,
+// concurrency) points, not a faithful trace of real-world jitter.
+package simulator
+
+import (
+	"fmt"
+
+	"github.com/accelbench/accelbench/internal/metrics"
+	"github.com/accelbench/accelbench/internal/recommend"
+)
+
+// Params holds the subset of a benchmark run's configuration the simulator
+// needs. It deliberately doesn't require the full HuggingFace architecture
+// metadata recommend.ModelConfig needs (hidden size, head counts, ...),
+// since that isn't persisted on a benchmark run — only parameter count is.
+type Params struct {
+	ParameterCount       int64
+	Quantization         string // "" means native precision (bf16/fp16)
+	AcceleratorName      string
+	AcceleratorCount     int
+	AcceleratorMemoryGiB int
+	TensorParallelDegree int
+	Concurrency          int
+	InputSequenceLength  int
+	OutputSequenceLength int
+	NumRequests          int // 0 picks a default the same way launchLoadgen does
+}
+
+// AcceleratorSpec is the coarse roofline inputs for one accelerator model:
+// its peak dense bf16/fp16 matmul throughput and HBM bandwidth.
+type AcceleratorSpec struct {
+	PeakTFLOPS      float64
+	MemBandwidthGBs float64
+}
+
+// acceleratorSpecs is a small lookup table covering the accelerators the
+// catalog's instance types expose. It's a convenience default in the same
+// spirit as internal/pricing's embedded rate table: coarse, publicly
+// published figures, not a source of truth for capacity planning.
+var acceleratorSpecs = map[string]AcceleratorSpec{
+	"A10G":        {PeakTFLOPS: 125, MemBandwidthGBs: 600},
+	"A100":        {PeakTFLOPS: 312, MemBandwidthGBs: 2039},
+	"H100":        {PeakTFLOPS: 989, MemBandwidthGBs: 3350},
+	"H200":        {PeakTFLOPS: 989, MemBandwidthGBs: 4800},
+	"L40S":        {PeakTFLOPS: 362, MemBandwidthGBs: 864},
+	"L4":          {PeakTFLOPS: 121, MemBandwidthGBs: 300},
+	"Trainium2":   {PeakTFLOPS: 667, MemBandwidthGBs: 2900},
+	"Inferentia2": {PeakTFLOPS: 190, MemBandwidthGBs: 820},
+}
+
+// defaultAcceleratorSpec is used for an accelerator name the table doesn't
+// recognize, so an unfamiliar instance type degrades to a conservative
+// estimate rather than failing the simulation outright.
+var defaultAcceleratorSpec = acceleratorSpecs["A10G"]
+
+func specFor(acceleratorName string) AcceleratorSpec {
+	if s, ok := acceleratorSpecs[acceleratorName]; ok {
+		return s
+	}
+	return defaultAcceleratorSpec
+}
+
+const (
+	// flopsPerParamPerToken is the standard 2×N forward-pass FLOPs-per-
+	// parameter approximation, ignoring the (usually small) quadratic
+	// attention term — the same order of coarseness recommend.go accepts
+	// elsewhere.
+	flopsPerParamPerToken = 2
+
+	// overheadFraction and gibBytes mirror internal/recommend's constants
+	// of the same name; duplicated rather than imported since recommend
+	// doesn't export them and the values are part of this package's own
+	// feasibility contract.
+	overheadFraction = 0.10
+	gibBytes         = 1024 * 1024 * 1024
+)
+
+// Run simulates a benchmark against Params and returns a synthetic
+// metrics.LoadgenOutput, or an error if the model's weights don't fit
+// across p.TensorParallelDegree accelerators.
+func Run(p Params) (*metrics.LoadgenOutput, error) {
+	if p.TensorParallelDegree < 1 {
+		return nil, fmt.Errorf("simulate: tensor parallel degree must be >= 1, got %d", p.TensorParallelDegree)
+	}
+	if p.Concurrency < 1 {
+		return nil, fmt.Errorf("simulate: concurrency must be >= 1, got %d", p.Concurrency)
+	}
+
+	weightBytes := recommend.ModelMemoryBytes(p.ParameterCount, p.Quantization)
+	perDeviceGiB := float64(p.AcceleratorMemoryGiB) / float64(p.AcceleratorCount)
+	usablePerDevice := perDeviceGiB * gibBytes * (1 - overheadFraction)
+	perDeviceWeightBytes := weightBytes / float64(p.TensorParallelDegree)
+	if perDeviceWeightBytes > usablePerDevice {
+		return nil, fmt.Errorf("simulate: model weights (%.1f GiB total, %.1f GiB per device at tp=%d) exceed %.1f GiB available per device",
+			weightBytes/gibBytes, perDeviceWeightBytes/gibBytes, p.TensorParallelDegree, usablePerDevice/gibBytes)
+	}
+
+	spec := specFor(p.AcceleratorName)
+	tp := float64(p.TensorParallelDegree)
+	peakFLOPS := spec.PeakTFLOPS * 1e12 * tp
+	memBW := spec.MemBandwidthGBs * 1e9 * tp
+	flopsPerToken := flopsPerParamPerToken * float64(p.ParameterCount)
+
+	// Prefill: time to process one request's input tokens, capped by
+	// whichever bound is tighter — compute for the matmuls over the whole
+	// prompt, or memory for the one-time weight read.
+	prefillComputeSeconds := float64(p.InputSequenceLength) * flopsPerToken / peakFLOPS
+	prefillMemSeconds := weightBytes / memBW
+	ttftSeconds := max64(prefillComputeSeconds, prefillMemSeconds)
+
+	// Decode: one step serves the whole concurrency-sized batch at once.
+	// Memory-bound throughput scales with concurrency (the weight read is
+	// shared across the batch); compute-bound throughput doesn't (it's
+	// proportional to the number of sequences being decoded). The tighter
+	// of the two caps the aggregate tokens/sec, per the roofline model.
+	computeBoundTPS := peakFLOPS / flopsPerToken
+	memBoundTPS := float64(p.Concurrency) * memBW / weightBytes
+	aggregateDecodeTPS := min64(computeBoundTPS, memBoundTPS)
+
+	stepSeconds := float64(p.Concurrency) / aggregateDecodeTPS
+	itlMs := stepSeconds * 1000
+	ttftMs := ttftSeconds * 1000
+	e2eMs := ttftMs + float64(p.OutputSequenceLength)*itlMs
+	e2eSeconds := e2eMs / 1000
+
+	numRequests := p.NumRequests
+	if numRequests == 0 {
+		numRequests = 200
+		if p.Concurrency > 32 {
+			numRequests = p.Concurrency * 10
+		}
+	}
+
+	requests := make([]metrics.RequestResult, numRequests)
+	for i := range requests {
+		requests[i] = metrics.RequestResult{
+			TTFTMs:          ttftMs,
+			E2ELatencyMs:    e2eMs,
+			ITLMs:           itlMs,
+			OutputTokens:    p.OutputSequenceLength,
+			InputTokens:     p.InputSequenceLength,
+			DurationSeconds: e2eSeconds,
+			Success:         true,
+		}
+	}
+
+	rps := aggregateDecodeTPS / float64(p.OutputSequenceLength)
+	totalDurationSeconds := float64(numRequests) / float64(p.Concurrency) * e2eSeconds
+
+	return &metrics.LoadgenOutput{
+		Requests: requests,
+		Summary: metrics.Summary{
+			TotalDurationSeconds:   totalDurationSeconds,
+			TotalRequests:          numRequests,
+			SuccessfulRequests:     numRequests,
+			FailedRequests:         0,
+			ThroughputAggregateTPS: aggregateDecodeTPS,
+			RequestsPerSecond:      rps,
+		},
+	}, nil
+}
+
+func max64(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min64(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,99 @@
+package simulator
+
+import "testing"
+
+func testParams() Params {
+	return Params{
+		ParameterCount:       7_000_000_000,
+		AcceleratorName:      "A10G",
+		AcceleratorCount:     1,
+		AcceleratorMemoryGiB: 24,
+		TensorParallelDegree: 1,
+		Concurrency:          16,
+		InputSequenceLength:  512,
+		OutputSequenceLength: 256,
+	}
+}
+
+func TestRun_ProducesPlausibleMetrics(t *testing.T) {
+	out, err := Run(testParams())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(out.Requests) == 0 {
+		t.Fatal("expected at least one synthetic request")
+	}
+	if out.Summary.ThroughputAggregateTPS <= 0 {
+		t.Errorf("ThroughputAggregateTPS = %v, want > 0", out.Summary.ThroughputAggregateTPS)
+	}
+	if out.Summary.RequestsPerSecond <= 0 {
+		t.Errorf("RequestsPerSecond = %v, want > 0", out.Summary.RequestsPerSecond)
+	}
+	for _, r := range out.Requests {
+		if !r.Success {
+			t.Fatal("expected all synthetic requests to succeed")
+		}
+		if r.TTFTMs <= 0 || r.ITLMs <= 0 || r.E2ELatencyMs <= 0 {
+			t.Fatalf("expected positive timings, got %+v", r)
+		}
+	}
+}
+
+func TestRun_InfeasibleWhenWeightsDontFit(t *testing.T) {
+	p := testParams()
+	p.ParameterCount = 70_000_000_000 // 70B doesn't fit on a single 24 GiB A10G
+	if _, err := Run(p); err == nil {
+		t.Fatal("expected an error when model weights exceed device memory")
+	}
+}
+
+func TestRun_HigherTPFitsLargerModel(t *testing.T) {
+	p := testParams()
+	p.ParameterCount = 70_000_000_000
+	p.AcceleratorCount = 8
+	p.AcceleratorMemoryGiB = 192
+	p.TensorParallelDegree = 8
+	if _, err := Run(p); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+}
+
+func TestRun_HigherConcurrencyIncreasesAggregateThroughputUpToComputeBound(t *testing.T) {
+	low := testParams()
+	low.Concurrency = 1
+	high := testParams()
+	high.Concurrency = 64
+
+	outLow, err := Run(low)
+	if err != nil {
+		t.Fatalf("Run(low): %v", err)
+	}
+	outHigh, err := Run(high)
+	if err != nil {
+		t.Fatalf("Run(high): %v", err)
+	}
+	if outHigh.Summary.ThroughputAggregateTPS < outLow.Summary.ThroughputAggregateTPS {
+		t.Errorf("expected higher concurrency to not decrease aggregate throughput: low=%v high=%v",
+			outLow.Summary.ThroughputAggregateTPS, outHigh.Summary.ThroughputAggregateTPS)
+	}
+}
+
+func TestRun_UnknownAcceleratorFallsBackRatherThanErroring(t *testing.T) {
+	p := testParams()
+	p.AcceleratorName = "SomeFutureGPU"
+	if _, err := Run(p); err != nil {
+		t.Fatalf("Run with unknown accelerator: %v", err)
+	}
+}
+
+func TestRun_RespectsExplicitNumRequests(t *testing.T) {
+	p := testParams()
+	p.NumRequests = 7
+	out, err := Run(p)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(out.Requests) != 7 {
+		t.Errorf("len(Requests) = %d, want 7", len(out.Requests))
+	}
+}
@@ -0,0 +1,156 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// defaultSLOSearchMaxConcurrency caps the doubling phase of ExecuteSLOSearch
+// when a request's MaxConcurrency is left zero.
+const defaultSLOSearchMaxConcurrency = 1024
+
+// ExecuteSLOSearch finds the highest concurrency at which model/instanceType
+// still meets req.SLOSpec: it doubles concurrency from 1 until a probe
+// violates the SLO (or req.MaxConcurrency is reached), then bisects between
+// the last passing and first failing concurrency until the gap is within
+// req.Tolerance. Every probe is persisted as a RunType="slo_search_probe"
+// child run of parentRunID via the same Execute path a plain run takes, so
+// it shows up in ListRunsByParent/ListCatalog like any other run. The final
+// result is stamped onto parentRunID's own BenchmarkMetrics.MaxConcurrencyAtSLO,
+// and parentRunID is marked completed. A probe's hard execution failure
+// (as opposed to an SLO violation, which is expected bisection behavior)
+// fails the parent run and returns the error.
+func (o *Orchestrator) ExecuteSLOSearch(ctx context.Context, parentRunID string, model *database.Model, instanceType *database.InstanceType, req database.SLOSearchRequest) error {
+	if err := o.repo.UpdateRunStatus(ctx, parentRunID, "running"); err != nil {
+		return fmt.Errorf("update status to running: %w", err)
+	}
+
+	maxConcurrency := req.MaxConcurrency
+	if maxConcurrency == 0 {
+		maxConcurrency = defaultSLOSearchMaxConcurrency
+	}
+	tolerance := req.Tolerance
+	if tolerance == 0 {
+		tolerance = 1
+	}
+
+	// Phase 1: double concurrency until a probe violates the SLO or the
+	// cap is reached.
+	low := 0
+	high := 0
+	concurrency := 1
+	for {
+		passed, err := o.probeSLOSearch(ctx, parentRunID, model, instanceType, req, concurrency)
+		if err != nil {
+			o.markFailed(ctx, parentRunID, instanceType.Name, req.Framework, "slo_search", "")
+			return err
+		}
+		if !passed {
+			high = concurrency
+			break
+		}
+		low = concurrency
+		if concurrency >= maxConcurrency {
+			break
+		}
+		concurrency *= 2
+		if concurrency > maxConcurrency {
+			concurrency = maxConcurrency
+		}
+	}
+
+	// Phase 2: bisect between low (last passing, 0 if none passed) and
+	// high (first failing, 0 if the cap was never violated) until the gap
+	// closes to within tolerance.
+	for high != 0 && high-low > tolerance {
+		mid := low + (high-low)/2
+		passed, err := o.probeSLOSearch(ctx, parentRunID, model, instanceType, req, mid)
+		if err != nil {
+			o.markFailed(ctx, parentRunID, instanceType.Name, req.Framework, "slo_search", "")
+			return err
+		}
+		if passed {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	result := low
+	if err := o.repo.PersistMetrics(ctx, parentRunID, &database.BenchmarkMetrics{MaxConcurrencyAtSLO: &result}, nil); err != nil {
+		o.markFailed(ctx, parentRunID, instanceType.Name, req.Framework, "slo_search", "")
+		return fmt.Errorf("persist slo search result: %w", err)
+	}
+
+	log.Printf("[%s] slo search converged at concurrency=%d", parentRunID[:8], result)
+	return nil
+}
+
+// probeSLOSearch creates and executes one concurrency probe as a child run
+// of parentRunID, then evaluates its persisted metrics against req.SLOSpec.
+// The returned bool is false whenever either latency bound in req.SLOSpec is
+// violated; an error is returned only for a probe that failed to execute or
+// produce metrics at all.
+func (o *Orchestrator) probeSLOSearch(ctx context.Context, parentRunID string, model *database.Model, instanceType *database.InstanceType, req database.SLOSearchRequest, concurrency int) (bool, error) {
+	run := &database.BenchmarkRun{
+		ModelID:              model.ID,
+		InstanceTypeID:       instanceType.ID,
+		Framework:            req.Framework,
+		FrameworkVersion:     req.FrameworkVersion,
+		TensorParallelDegree: req.TensorParallelDegree,
+		Quantization:         req.Quantization,
+		Concurrency:          concurrency,
+		InputSequenceLength:  req.InputSequenceLength,
+		OutputSequenceLength: req.OutputSequenceLength,
+		DatasetName:          req.DatasetName,
+		DatasetSpec:          req.DatasetSpec,
+		RunType:              "slo_search_probe",
+		Status:               "pending",
+		ParentRunID:          &parentRunID,
+	}
+	runID, err := o.repo.CreateBenchmarkRun(ctx, run)
+	if err != nil {
+		return false, fmt.Errorf("create slo search probe run: %w", err)
+	}
+
+	runReq := &database.RunRequest{
+		ModelHfID:            model.HfID,
+		ModelHfRevision:      model.HfRevision,
+		InstanceTypeName:     instanceType.Name,
+		Framework:            req.Framework,
+		FrameworkVersion:     req.FrameworkVersion,
+		TensorParallelDegree: req.TensorParallelDegree,
+		Quantization:         req.Quantization,
+		Concurrency:          concurrency,
+		InputSequenceLength:  req.InputSequenceLength,
+		OutputSequenceLength: req.OutputSequenceLength,
+		DatasetName:          req.DatasetName,
+		DatasetSpec:          req.DatasetSpec,
+		RunType:              "slo_search_probe",
+		MaxModelLen:          req.MaxModelLen,
+		HfToken:              req.HfToken,
+		Plugins:              req.Plugins,
+	}
+	if err := o.Execute(ctx, RunConfig{RunID: runID, Model: model, InstanceType: instanceType, Request: runReq}); err != nil {
+		return false, fmt.Errorf("execute slo search probe at concurrency=%d: %w", concurrency, err)
+	}
+
+	metrics, err := o.repo.GetMetricsByRunID(ctx, runID)
+	if err != nil {
+		return false, fmt.Errorf("get slo search probe metrics: %w", err)
+	}
+	if metrics == nil {
+		return false, fmt.Errorf("slo search probe at concurrency=%d produced no metrics", concurrency)
+	}
+
+	if req.SLOSpec.TTFTP95MsMax != nil && (metrics.TTFTP95Ms == nil || *metrics.TTFTP95Ms > *req.SLOSpec.TTFTP95MsMax) {
+		return false, nil
+	}
+	if req.SLOSpec.E2ELatencyP99MsMax != nil && (metrics.E2ELatencyP99Ms == nil || *metrics.E2ELatencyP99Ms > *req.SLOSpec.E2ELatencyP99MsMax) {
+		return false, nil
+	}
+	return true, nil
+}
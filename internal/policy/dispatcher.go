@@ -0,0 +1,179 @@
+// Package policy ticks database.BenchmarkPolicy rows and materializes a
+// BenchmarkRun from each one that comes due, the scheduled counterpart to
+// a human POST /runs. It is deliberately its own package rather than a
+// method on orchestrator.Scheduler, since that type already owns a
+// different concept — admitting and dispatching runs already created —
+// and conflating the two would make "Scheduler" ambiguous between "what
+// decides a run should exist" and "what runs it once it does".
+package policy
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/cronexpr"
+	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/orchestrator"
+)
+
+// tickInterval is how often the Dispatcher polls for due policies when its
+// repo doesn't implement database.PolicyClaimer. It's coarser than
+// orchestrator.Scheduler's dispatchPoll since a policy's own CronExpr
+// resolution is already in minutes, not seconds.
+const tickInterval = 30 * time.Second
+
+// Dispatcher ticks enabled, scheduled BenchmarkPolicy rows and submits one
+// BenchmarkRun per due policy through sched, the same admission path a
+// human POST /runs uses. If repo implements database.PolicyClaimer,
+// Dispatcher claims due policies through it so more than one Dispatcher
+// process can safely poll the same backend; otherwise it falls back to
+// plain ListPolicies plus an in-process NextRunAt check, which is safe
+// only when exactly one Dispatcher process is ticking against that
+// backend (mirroring orchestrator.Scheduler's own leased/non-leased
+// split).
+type Dispatcher struct {
+	repo  database.Repo
+	sched *orchestrator.Scheduler
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewDispatcher creates a Dispatcher. Call Start to begin ticking.
+func NewDispatcher(repo database.Repo, sched *orchestrator.Scheduler) *Dispatcher {
+	return &Dispatcher{repo: repo, sched: sched, done: make(chan struct{})}
+}
+
+// Start begins the background tick loop. Safe to call only once.
+func (d *Dispatcher) Start(ctx context.Context) {
+	ctx, d.cancel = context.WithCancel(ctx)
+	go d.loop(ctx)
+}
+
+// Stop cancels the tick loop and waits for it to exit. A run already
+// submitted to the scheduler keeps executing.
+func (d *Dispatcher) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	<-d.done
+}
+
+func (d *Dispatcher) loop(ctx context.Context) {
+	defer close(d.done)
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	d.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.tick(ctx)
+		}
+	}
+}
+
+// tick claims every due policy and submits one run for each.
+func (d *Dispatcher) tick(ctx context.Context) {
+	if claimer, ok := d.repo.(database.PolicyClaimer); ok {
+		due, err := claimer.ClaimDuePolicies(ctx, time.Now().UTC())
+		if err != nil {
+			log.Printf("policy: claim due policies: %v", err)
+			return
+		}
+		for _, p := range due {
+			d.submit(ctx, p)
+		}
+		return
+	}
+
+	policies, err := d.repo.ListPolicies(ctx)
+	if err != nil {
+		log.Printf("policy: list policies: %v", err)
+		return
+	}
+	now := time.Now().UTC()
+	for _, p := range policies {
+		if !p.Enabled || p.Trigger != "scheduled" || p.NextRunAt == nil || p.NextRunAt.After(now) {
+			continue
+		}
+		next, err := cronexpr.Next(p.CronExpr, now)
+		if err != nil {
+			log.Printf("policy: compute next run for policy %s: %v", p.ID, err)
+			continue
+		}
+		updated := p
+		updated.NextRunAt = &next
+		if err := d.repo.UpdatePolicy(ctx, p.ID, &updated); err != nil {
+			log.Printf("policy: advance next run for policy %s: %v", p.ID, err)
+			continue
+		}
+		d.submit(ctx, p)
+	}
+}
+
+// submit materializes and enqueues one BenchmarkRun from policy's
+// Template, tagging it with PolicyID and Trigger so it's attributable
+// back to the policy that produced it.
+func (d *Dispatcher) submit(ctx context.Context, p database.BenchmarkPolicy) {
+	model, err := d.repo.EnsureModel(ctx, p.Template.ModelHfID, p.Template.ModelHfRevision)
+	if err != nil {
+		log.Printf("policy: ensure model for policy %s: %v", p.ID, err)
+		return
+	}
+	instType, err := d.repo.GetInstanceTypeByName(ctx, p.Template.InstanceTypeName)
+	if err != nil {
+		log.Printf("policy: lookup instance type for policy %s: %v", p.ID, err)
+		return
+	}
+	if instType == nil {
+		log.Printf("policy: instance type %s not found for policy %s", p.Template.InstanceTypeName, p.ID)
+		return
+	}
+
+	req := database.RunRequest{
+		ModelHfID:            p.Template.ModelHfID,
+		ModelHfRevision:      p.Template.ModelHfRevision,
+		InstanceTypeName:     p.Template.InstanceTypeName,
+		Framework:            p.Template.Framework,
+		FrameworkVersion:     p.Template.FrameworkVersion,
+		TensorParallelDegree: p.Template.TensorParallelDegree,
+		Quantization:         p.Template.Quantization,
+		Concurrency:          p.Template.Concurrency,
+		InputSequenceLength:  p.Template.InputSequenceLength,
+		OutputSequenceLength: p.Template.OutputSequenceLength,
+		DatasetName:          p.Template.DatasetName,
+		RunType:              "catalog",
+	}
+	policyID := p.ID
+	run := &database.BenchmarkRun{
+		ModelID:              model.ID,
+		InstanceTypeID:       instType.ID,
+		Framework:            req.Framework,
+		FrameworkVersion:     req.FrameworkVersion,
+		TensorParallelDegree: req.TensorParallelDegree,
+		Quantization:         req.Quantization,
+		Concurrency:          req.Concurrency,
+		InputSequenceLength:  req.InputSequenceLength,
+		OutputSequenceLength: req.OutputSequenceLength,
+		DatasetName:          req.DatasetName,
+		RunType:              req.RunType,
+		Status:               "queued",
+		OriginalRequest:      &req,
+		PolicyID:             &policyID,
+		Trigger:              "scheduled",
+	}
+
+	runID, err := d.repo.CreateBenchmarkRun(ctx, run)
+	if err != nil {
+		log.Printf("policy: create run for policy %s: %v", p.ID, err)
+		return
+	}
+	if err := d.sched.Enqueue(ctx, runID, instType.Family, "", 0); err != nil {
+		log.Printf("policy: enqueue run %s for policy %s: %v", runID, p.ID, err)
+		_ = d.repo.UpdateRunStatus(ctx, runID, "failed")
+	}
+}
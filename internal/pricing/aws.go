@@ -0,0 +1,132 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	pricingtypes "github.com/aws/aws-sdk-go-v2/service/pricing/types"
+)
+
+// AWSProvider resolves rates by calling the AWS Pricing API on demand,
+// caching each instance type's rate for the life of the provider. Useful
+// for an up-to-the-minute number when the static table hasn't been
+// refreshed, at the cost of one Pricing API round trip per unseen
+// instance type.
+type AWSProvider struct {
+	client *pricing.Client
+	region string
+
+	mu    sync.Mutex
+	cache map[string]*Rate
+}
+
+// NewAWSProvider builds an AWSProvider using the default AWS credential
+// chain. region selects the pricing region filter (e.g. "us-east-2"); the
+// Pricing API itself is only available in us-east-1 and is queried there
+// regardless.
+func NewAWSProvider(ctx context.Context, region string) (*AWSProvider, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion("us-east-1"))
+	if err != nil {
+		return nil, fmt.Errorf("load AWS config: %w", err)
+	}
+	return &AWSProvider{
+		client: pricing.NewFromConfig(cfg),
+		region: region,
+		cache:  make(map[string]*Rate),
+	}, nil
+}
+
+// Rate implements Provider.
+func (p *AWSProvider) Rate(ctx context.Context, instanceType string) (*Rate, error) {
+	p.mu.Lock()
+	if r, ok := p.cache[instanceType]; ok {
+		p.mu.Unlock()
+		return r, nil
+	}
+	p.mu.Unlock()
+
+	usd, err := p.fetchOnDemand(ctx, instanceType)
+	if err != nil {
+		return nil, err
+	}
+
+	var r *Rate
+	if usd != nil {
+		r = &Rate{InstanceTypeName: instanceType, OnDemandHourlyUSD: *usd}
+	}
+	p.mu.Lock()
+	p.cache[instanceType] = r
+	p.mu.Unlock()
+	return r, nil
+}
+
+// fetchOnDemand returns the hourly on-demand USD rate for instanceType, or
+// nil if the Pricing API has no matching product.
+func (p *AWSProvider) fetchOnDemand(ctx context.Context, instanceType string) (*float64, error) {
+	resp, err := p.client.GetProducts(ctx, &pricing.GetProductsInput{
+		ServiceCode: strPtr("AmazonEC2"),
+		Filters: []pricingtypes.Filter{
+			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("instanceType"), Value: strPtr(instanceType)},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("operatingSystem"), Value: strPtr("Linux")},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("tenancy"), Value: strPtr("Shared")},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("preInstalledSw"), Value: strPtr("NA")},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("capacitystatus"), Value: strPtr("Used")},
+			{Type: pricingtypes.FilterTypeTermMatch, Field: strPtr("regionCode"), Value: strPtr(p.region)},
+		},
+		MaxResults: int32Ptr(10),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetProducts(%s): %w", instanceType, err)
+	}
+	if len(resp.PriceList) == 0 {
+		return nil, nil
+	}
+
+	var product priceDoc
+	if err := json.Unmarshal([]byte(resp.PriceList[0]), &product); err != nil {
+		return nil, fmt.Errorf("parse price list for %s: %w", instanceType, err)
+	}
+	for _, term := range product.Terms.OnDemand {
+		for _, pd := range term.PriceDimensions {
+			if pd.Unit != "Hrs" {
+				continue
+			}
+			usdStr, ok := pd.PricePerUnit["USD"]
+			if !ok {
+				continue
+			}
+			usd, err := strconv.ParseFloat(usdStr, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parse on-demand price for %s: %w", instanceType, err)
+			}
+			return &usd, nil
+		}
+	}
+	return nil, nil
+}
+
+// priceDoc is the subset of the AWS Pricing API's product document this
+// provider needs; see cmd/pricingrefresh for the full shape including
+// reserved-instance terms.
+type priceDoc struct {
+	Terms struct {
+		OnDemand map[string]termEntry `json:"OnDemand"`
+	} `json:"terms"`
+}
+
+type termEntry struct {
+	PriceDimensions map[string]priceDimension `json:"priceDimensions"`
+}
+
+type priceDimension struct {
+	Unit         string            `json:"unit"`
+	PricePerUnit map[string]string `json:"pricePerUnit"`
+}
+
+func strPtr(s string) *string { return &s }
+func int32Ptr(i int32) *int32 { return &i }
@@ -0,0 +1,57 @@
+package pricing
+
+import "strings"
+
+// usdConversionRates is a static USD conversion table for --currency.
+// AccelBench doesn't pull live FX rates — these are a back-of-envelope
+// snapshot, good enough for relative cost comparisons across instance
+// types. Update by hand if it drifts enough to matter.
+var usdConversionRates = map[string]float64{
+	"USD": 1.0,
+	"EUR": 0.92,
+	"GBP": 0.79,
+	"JPY": 157.0,
+}
+
+// ConvertUSD converts a USD amount into currency, returning the converted
+// amount and the normalized (uppercased) currency code actually used. An
+// unrecognized currency code falls back to USD rather than erroring, since
+// this only affects display.
+func ConvertUSD(usd float64, currency string) (float64, string) {
+	code, rate := normalizeCurrency(currency)
+	return usd * rate, code
+}
+
+// ConvertPerUSD converts a "per dollar" rate (e.g. tokens/$) into "per unit
+// of currency", returning the converted rate and the normalized currency
+// code. Converting a per-dollar rate divides by the USD rate rather than
+// multiplying, since a currency unit worth fewer dollars buys fewer tokens
+// per unit.
+func ConvertPerUSD(perUSD float64, currency string) (float64, string) {
+	code, rate := normalizeCurrency(currency)
+	return perUSD / rate, code
+}
+
+func normalizeCurrency(currency string) (code string, rate float64) {
+	code = strings.ToUpper(strings.TrimSpace(currency))
+	rate, ok := usdConversionRates[code]
+	if !ok {
+		return "USD", 1.0
+	}
+	return code, rate
+}
+
+// SortKeys are the --sort values this package adds on top of the catalog's
+// own server-side sort columns. Sorting on these is done client-side, since
+// the derived values don't exist in the database.
+const (
+	SortCostPerMToken  = "cost_per_mtoken"
+	SortTokensPerJoule = "tokens_per_joule"
+)
+
+// IsClientSortKey reports whether key names a derived column that must be
+// sorted client-side after fetching catalog entries, rather than passed
+// through to the server as CatalogFilter.SortBy.
+func IsClientSortKey(key string) bool {
+	return key == SortCostPerMToken || key == SortTokensPerJoule
+}
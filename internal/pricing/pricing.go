@@ -0,0 +1,75 @@
+// Package pricing supplies hourly on-demand instance rates and derives the
+// cost- and energy-aware columns ($/1M tokens, $/req, tokens/$, J/token,
+// tokens/kWh) that the `compare` and `query` CLI commands overlay onto
+// catalog entries.
+package pricing
+
+import (
+	"context"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// Rate is the hourly on-demand price for one instance type.
+type Rate struct {
+	InstanceTypeName  string  `yaml:"instance_type" json:"instance_type"`
+	OnDemandHourlyUSD float64 `yaml:"on_demand_hourly_usd" json:"on_demand_hourly_usd"`
+}
+
+// Provider resolves an on-demand Rate for an instance type. Implementations
+// must return a nil Rate and a nil error for an instance type they have no
+// rate for, rather than an error — the caller degrades gracefully (a dash in
+// the derived columns) instead of failing the whole command.
+type Provider interface {
+	Rate(ctx context.Context, instanceType string) (*Rate, error)
+}
+
+// Derived holds the cost- and energy-aware columns computed for one catalog
+// entry. Fields are nil wherever the inputs needed to compute them (a
+// pricing rate, a throughput/RPS sample, or a PowerAvgW reading) are
+// unavailable, so callers render a dash instead of a bogus zero.
+type Derived struct {
+	CostPerMToken   *float64 // $ per 1M output tokens, in the requested currency
+	CostPerRequest  *float64 // $ per request, in the requested currency
+	TokensPerDollar *float64 // output tokens per $, in the requested currency
+	JoulesPerToken  *float64 // joules per output token, PUE-adjusted
+	TokensPerJoule  *float64 // reciprocal of JoulesPerToken, for --sort tokens_per_joule
+	TokensPerKWh    *float64 // output tokens per kWh, PUE-adjusted
+}
+
+// secondsPerHour converts an hourly rate into the per-second throughput and
+// RPS figures already on CatalogEntry.
+const secondsPerHour = 3600
+
+// Derive computes the cost and energy overlay for a catalog entry given its
+// hourly rate (nil if the instance type has no known rate) and the
+// data-center PUE multiplier to apply to PowerAvgW (1.0 disables the
+// adjustment). Any input missing from entry or rate leaves the
+// corresponding Derived field nil.
+func Derive(e database.CatalogEntry, rate *Rate, pue float64) Derived {
+	var d Derived
+
+	if rate != nil && e.ThroughputAggregateTPS != nil && *e.ThroughputAggregateTPS > 0 {
+		tokensPerHour := *e.ThroughputAggregateTPS * secondsPerHour
+		costPerMToken := rate.OnDemandHourlyUSD / tokensPerHour * 1e6
+		tokensPerDollar := tokensPerHour / rate.OnDemandHourlyUSD
+		d.CostPerMToken = &costPerMToken
+		d.TokensPerDollar = &tokensPerDollar
+	}
+	if rate != nil && e.RequestsPerSecond != nil && *e.RequestsPerSecond > 0 {
+		costPerRequest := rate.OnDemandHourlyUSD / (*e.RequestsPerSecond * secondsPerHour)
+		d.CostPerRequest = &costPerRequest
+	}
+	if e.PowerAvgW != nil && *e.PowerAvgW > 0 && e.ThroughputAggregateTPS != nil && *e.ThroughputAggregateTPS > 0 {
+		facilityW := *e.PowerAvgW * pue
+		joulesPerToken := facilityW / *e.ThroughputAggregateTPS
+		tokensPerJoule := 1 / joulesPerToken
+		kWh := facilityW / 1000
+		tokensPerKWh := (*e.ThroughputAggregateTPS * secondsPerHour) / kWh
+		d.JoulesPerToken = &joulesPerToken
+		d.TokensPerJoule = &tokensPerJoule
+		d.TokensPerKWh = &tokensPerKWh
+	}
+
+	return d
+}
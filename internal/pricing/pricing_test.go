@@ -0,0 +1,92 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+func f64(v float64) *float64 { return &v }
+
+func TestDerive_ComputesCostAndEnergyColumns(t *testing.T) {
+	e := database.CatalogEntry{
+		ThroughputAggregateTPS: f64(1000), // tokens/sec
+		RequestsPerSecond:      f64(2),
+		PowerAvgW:              f64(700),
+	}
+	rate := &Rate{InstanceTypeName: "p5.48xlarge", OnDemandHourlyUSD: 36}
+
+	d := Derive(e, rate, 1.0)
+
+	wantCostPerMToken := 36.0 / (1000 * 3600) * 1e6
+	if d.CostPerMToken == nil || math.Abs(*d.CostPerMToken-wantCostPerMToken) > 1e-9 {
+		t.Errorf("CostPerMToken = %v, want %v", d.CostPerMToken, wantCostPerMToken)
+	}
+
+	wantCostPerRequest := 36.0 / (2 * 3600)
+	if d.CostPerRequest == nil || math.Abs(*d.CostPerRequest-wantCostPerRequest) > 1e-9 {
+		t.Errorf("CostPerRequest = %v, want %v", d.CostPerRequest, wantCostPerRequest)
+	}
+
+	wantJoulesPerToken := 700.0 / 1000
+	if d.JoulesPerToken == nil || math.Abs(*d.JoulesPerToken-wantJoulesPerToken) > 1e-9 {
+		t.Errorf("JoulesPerToken = %v, want %v", d.JoulesPerToken, wantJoulesPerToken)
+	}
+	if d.TokensPerJoule == nil || math.Abs(*d.TokensPerJoule-1/wantJoulesPerToken) > 1e-9 {
+		t.Errorf("TokensPerJoule = %v, want %v", d.TokensPerJoule, 1/wantJoulesPerToken)
+	}
+}
+
+func TestDerive_DegradesGracefullyWhenInputsMissing(t *testing.T) {
+	e := database.CatalogEntry{ThroughputAggregateTPS: f64(1000)}
+
+	// No rate known for this instance type.
+	d := Derive(e, nil, 1.0)
+	if d.CostPerMToken != nil || d.CostPerRequest != nil || d.TokensPerDollar != nil {
+		t.Errorf("expected nil cost columns with no rate, got %+v", d)
+	}
+
+	// Rate known, but no power sample, so energy columns stay nil.
+	d = Derive(e, &Rate{OnDemandHourlyUSD: 10}, 1.0)
+	if d.JoulesPerToken != nil || d.TokensPerJoule != nil || d.TokensPerKWh != nil {
+		t.Errorf("expected nil energy columns with no power sample, got %+v", d)
+	}
+	if d.CostPerMToken == nil {
+		t.Error("expected CostPerMToken to be populated when rate and throughput are known")
+	}
+}
+
+func TestConvertUSD_UnknownCurrencyFallsBackToUSD(t *testing.T) {
+	amount, code := ConvertUSD(10, "XYZ")
+	if code != "USD" || amount != 10 {
+		t.Errorf("ConvertUSD(10, %q) = (%v, %q), want (10, \"USD\")", "XYZ", amount, code)
+	}
+
+	amount, code = ConvertUSD(10, "eur")
+	if code != "EUR" || amount != 10*usdConversionRates["EUR"] {
+		t.Errorf("ConvertUSD(10, %q) = (%v, %q), want EUR conversion", "eur", amount, code)
+	}
+}
+
+func TestNewStaticProvider_LoadsEmbeddedDefaults(t *testing.T) {
+	p, err := NewStaticProvider("")
+	if err != nil {
+		t.Fatalf("NewStaticProvider: %v", err)
+	}
+	r, err := p.Rate(nil, "p5.48xlarge")
+	if err != nil {
+		t.Fatalf("Rate: %v", err)
+	}
+	if r == nil {
+		t.Fatal("expected a default rate for p5.48xlarge")
+	}
+
+	r, err = p.Rate(nil, "not-a-real-instance-type")
+	if err != nil {
+		t.Fatalf("Rate: %v", err)
+	}
+	if r != nil {
+		t.Errorf("expected nil rate for unknown instance type, got %+v", r)
+	}
+}
@@ -0,0 +1,60 @@
+package pricing
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed rates.yaml
+var defaultRatesYAML []byte
+
+// ratesDoc is the YAML shape for both the embedded default rates and any
+// file passed via --pricing-file.
+type ratesDoc struct {
+	Rates []Rate `yaml:"rates"`
+}
+
+// StaticProvider resolves rates from an in-memory table loaded once from
+// YAML: the module's embedded defaults, or a user-supplied --pricing-file
+// so private/negotiated rates can be plugged in without recompiling.
+type StaticProvider struct {
+	rates map[string]float64
+}
+
+// NewStaticProvider loads rates from path, or from the embedded defaults
+// when path is empty.
+func NewStaticProvider(path string) (*StaticProvider, error) {
+	data := defaultRatesYAML
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read pricing file %q: %w", path, err)
+		}
+	}
+
+	var doc ratesDoc
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse pricing file: %w", err)
+	}
+
+	rates := make(map[string]float64, len(doc.Rates))
+	for _, r := range doc.Rates {
+		rates[r.InstanceTypeName] = r.OnDemandHourlyUSD
+	}
+	return &StaticProvider{rates: rates}, nil
+}
+
+// Rate implements Provider. It returns a nil Rate, not an error, for an
+// instance type absent from the table.
+func (p *StaticProvider) Rate(ctx context.Context, instanceType string) (*Rate, error) {
+	usd, ok := p.rates[instanceType]
+	if !ok {
+		return nil, nil
+	}
+	return &Rate{InstanceTypeName: instanceType, OnDemandHourlyUSD: usd}, nil
+}
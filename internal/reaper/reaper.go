@@ -0,0 +1,121 @@
+// Package reaper fails out any BenchmarkRun whose DeadlineAt has passed —
+// a stuck vLLM process or a hung model download that would otherwise hold
+// an accelerator forever. It is deliberately its own package rather than a
+// method on orchestrator or api, mirroring internal/callbacks and
+// internal/policy: it ticks database.Repo on its own schedule and, for
+// each run it reaps, drives both the repo's CAS status transition and the
+// orchestrator's in-process teardown of whatever Kubernetes objects that
+// run's Execute goroutine created.
+package reaper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/orchestrator"
+)
+
+// tickInterval is how often the Reaper scans for expired runs.
+const tickInterval = 15 * time.Second
+
+// Reaper periodically fails every run whose DeadlineAt has passed. Unlike
+// callbacks.Worker and policy.Dispatcher it has no claim/lease-based
+// variant for multi-process safety: repo.CancelRun's CAS already makes
+// reaping a run idempotent and safe for more than one Reaper to race, the
+// same way it's already safe for a reaper tick to race a human's
+// POST /runs/{id}/cancel or the run completing normally.
+type Reaper struct {
+	repo database.Repo
+	orch *orchestrator.Orchestrator
+	now  func() time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Option configures optional Reaper behavior at construction time.
+type Option func(*Reaper)
+
+// WithClock overrides the default time.Now, so a test can drive tick with
+// a controlled, advancing clock instead of waiting out real deadlines.
+func WithClock(now func() time.Time) Option {
+	return func(r *Reaper) { r.now = now }
+}
+
+// New creates a Reaper. Call Start to begin ticking.
+func New(repo database.Repo, orch *orchestrator.Orchestrator, opts ...Option) *Reaper {
+	r := &Reaper{repo: repo, orch: orch, now: time.Now, done: make(chan struct{})}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Start begins the background tick loop. Safe to call only once.
+func (r *Reaper) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+	go r.loop(ctx)
+}
+
+// Stop cancels the tick loop and waits for it to exit. A reap already in
+// flight is allowed to finish.
+func (r *Reaper) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	<-r.done
+}
+
+func (r *Reaper) loop(ctx context.Context) {
+	defer close(r.done)
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	r.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.tick(ctx)
+		}
+	}
+}
+
+// tick fails every run whose DeadlineAt has passed.
+func (r *Reaper) tick(ctx context.Context) {
+	expired, err := r.repo.ListExpiredRuns(ctx, r.now())
+	if err != nil {
+		log.Printf("reaper: list expired runs: %v", err)
+		return
+	}
+	for _, run := range expired {
+		r.reap(ctx, run)
+	}
+}
+
+// reap moves run to "failed" via the same CAS CancelRun uses for a human-
+// initiated cancel (so a reap racing a completion notification can never
+// clobber a terminal status), stamps FailureReason, and asks the
+// orchestrator to tear down whatever Kubernetes objects its Execute
+// goroutine, if any, already created.
+func (r *Reaper) reap(ctx context.Context, run database.BenchmarkRun) {
+	if err := r.repo.CancelRun(ctx, run.ID); err != nil {
+		if errors.Is(err, database.ErrRunNotCancellable) {
+			// Lost the race to a completion or an explicit cancel; fine.
+			return
+		}
+		log.Printf("reaper: cancel expired run %s: %v", run.ID, err)
+		return
+	}
+	reason := fmt.Sprintf("deadline exceeded (%ds)", run.TimeoutSeconds)
+	if err := r.repo.UpdateRunFailureReason(ctx, run.ID, reason); err != nil {
+		log.Printf("reaper: stamp failure reason for run %s: %v", run.ID, err)
+	}
+	r.orch.CancelRun(run.ID)
+	log.Printf("reaper: run %s exceeded its deadline, marked failed", run.ID)
+}
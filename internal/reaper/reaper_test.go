@@ -0,0 +1,158 @@
+package reaper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/accelbench/accelbench/internal/database"
+	"github.com/accelbench/accelbench/internal/orchestrator"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newTestReaper mirrors orchestrator's newTestScheduler helper: a fake
+// Kubernetes clientset plus a MockRepo, wired into a Reaper whose clock is
+// caller-controlled so deadlines can be crossed without waiting on a real
+// tickInterval.
+func newTestReaper(now func() time.Time) (*Reaper, *database.MockRepo, *orchestrator.Orchestrator) {
+	client := fake.NewSimpleClientset()
+	repo := database.NewMockRepo()
+	orch := orchestrator.New(client, repo)
+	return New(repo, orch, WithClock(now)), repo, orch
+}
+
+func TestReaper_Tick_ReapsExactlyOncePerRun(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+	r, repo, _ := newTestReaper(clock)
+	ctx := context.Background()
+
+	runID, err := repo.CreateBenchmarkRun(ctx, &database.BenchmarkRun{
+		Status:         "running",
+		TimeoutSeconds: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateBenchmarkRun: %v", err)
+	}
+
+	// Advance the fake clock past the deadline and tick.
+	now = now.Add(2 * time.Minute)
+	r.tick(ctx)
+
+	run, err := repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		t.Fatalf("GetBenchmarkRun: %v", err)
+	}
+	if run.Status != "failed" {
+		t.Fatalf("expected status failed after first tick, got %q", run.Status)
+	}
+	if run.FailureReason == nil || *run.FailureReason == "" {
+		t.Fatal("expected FailureReason to be stamped")
+	}
+	firstReason := *run.FailureReason
+
+	// A second tick must be a no-op: the run is already terminal, so
+	// ListExpiredRuns no longer returns it and reap isn't called again.
+	r.tick(ctx)
+
+	run, err = repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		t.Fatalf("GetBenchmarkRun after second tick: %v", err)
+	}
+	if run.Status != "failed" || *run.FailureReason != firstReason {
+		t.Fatalf("second tick mutated an already-reaped run: status=%q reason=%v", run.Status, run.FailureReason)
+	}
+}
+
+func TestReaper_Tick_IgnoresRunsNotYetExpired(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+	r, repo, _ := newTestReaper(clock)
+	ctx := context.Background()
+
+	runID, err := repo.CreateBenchmarkRun(ctx, &database.BenchmarkRun{
+		Status:         "running",
+		TimeoutSeconds: 3600,
+	})
+	if err != nil {
+		t.Fatalf("CreateBenchmarkRun: %v", err)
+	}
+
+	r.tick(ctx)
+
+	run, err := repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		t.Fatalf("GetBenchmarkRun: %v", err)
+	}
+	if run.Status != "running" {
+		t.Fatalf("expected status unchanged, got %q", run.Status)
+	}
+}
+
+func TestExtendDeadline_RacesSafelyAgainstReaper(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+	r, repo, _ := newTestReaper(clock)
+	ctx := context.Background()
+
+	runID, err := repo.CreateBenchmarkRun(ctx, &database.BenchmarkRun{
+		Status:         "running",
+		TimeoutSeconds: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateBenchmarkRun: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	r.tick(ctx)
+
+	run, err := repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		t.Fatalf("GetBenchmarkRun: %v", err)
+	}
+	if run.Status != "failed" {
+		t.Fatalf("expected reaper to have failed the run first, got %q", run.Status)
+	}
+
+	// The reaper already won the race: a caller trying to renew the lease
+	// on an now-terminal run must get ErrRunNotExtendable, not a silent
+	// success that resurrects its deadline.
+	err = repo.ExtendDeadline(ctx, runID, now.Add(time.Hour))
+	if err != database.ErrRunNotExtendable {
+		t.Fatalf("ExtendDeadline after reap: got %v, want ErrRunNotExtendable", err)
+	}
+}
+
+func TestExtendDeadline_SucceedsBeforeDeadlinePasses(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+	r, repo, _ := newTestReaper(clock)
+	ctx := context.Background()
+
+	runID, err := repo.CreateBenchmarkRun(ctx, &database.BenchmarkRun{
+		Status:         "running",
+		TimeoutSeconds: 60,
+	})
+	if err != nil {
+		t.Fatalf("CreateBenchmarkRun: %v", err)
+	}
+
+	newDeadline := now.Add(10 * time.Minute)
+	if err := repo.ExtendDeadline(ctx, runID, newDeadline); err != nil {
+		t.Fatalf("ExtendDeadline: %v", err)
+	}
+
+	// Advance past the original deadline but not the extended one; the
+	// reaper must leave the run alone.
+	now = now.Add(2 * time.Minute)
+	r.tick(ctx)
+
+	run, err := repo.GetBenchmarkRun(ctx, runID)
+	if err != nil {
+		t.Fatalf("GetBenchmarkRun: %v", err)
+	}
+	if run.Status != "running" {
+		t.Fatalf("expected extended run to survive the tick, got %q", run.Status)
+	}
+}
@@ -0,0 +1,219 @@
+package recommend
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Cache is the pluggable persistence layer HFClient uses to avoid
+// re-fetching unchanged HuggingFace metadata. NewHFClient defaults
+// HFClient.Cache to a diskCache rooted under the OS cache directory
+// (os.UserCacheDir, which honors $XDG_CACHE_HOME on Linux); set it to a
+// different Cache, or nil, to change that — a nil Cache behaves like
+// noopCache.
+type Cache interface {
+	// Get returns the cached body and ETag for (modelID, revision, path),
+	// and whether a live entry exists. An entry past its TTL reports
+	// ok=false, so doGet treats it the same as no entry at all rather than
+	// conditionally GETting against an ETag it no longer trusts enough to
+	// keep around.
+	Get(modelID, revision, path string) (body []byte, etag string, ok bool)
+	// Put stores body and etag for (modelID, revision, path).
+	Put(modelID, revision, path string, body []byte, etag string) error
+
+	// GetModelConfig returns the previously resolved ModelConfig for
+	// (modelID, revision), so FetchModelConfigContext can skip doGet and
+	// the parameter-count estimate entirely, if one is cached and still
+	// within its TTL.
+	GetModelConfig(modelID, revision string) (cfg *ModelConfig, ok bool)
+	// PutModelConfig stores the resolved ModelConfig for (modelID, revision).
+	PutModelConfig(modelID, revision string, cfg *ModelConfig) error
+}
+
+const (
+	defaultCacheTTL      = 24 * time.Hour
+	defaultCacheMaxBytes = 512 << 20 // 512MiB, evicted LRU by file mtime
+)
+
+// newDiskCache builds the default on-disk Cache rooted under the OS cache
+// directory, or falls back to noopCache if one can't be determined (e.g.
+// $HOME unset) — caching is a latency optimization, never a requirement.
+func newDiskCache() Cache {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return noopCache{}
+	}
+	return &diskCache{
+		root:     filepath.Join(base, "accelbench", "hf"),
+		ttl:      defaultCacheTTL,
+		maxBytes: defaultCacheMaxBytes,
+	}
+}
+
+// diskCache persists entries as one JSON file per (modelID, revision, path)
+// under root/<modelID>/<revision>/<path>.json, plus one
+// root/<modelID>/<revision>/model-config.json holding the resolved
+// ModelConfig. Total size is kept under maxBytes by evicting the
+// least-recently-written files first.
+type diskCache struct {
+	root     string
+	ttl      time.Duration
+	maxBytes int64
+}
+
+type cacheEntry struct {
+	ETag     string          `json:"etag"`
+	Body     json.RawMessage `json:"body"`
+	StoredAt time.Time       `json:"stored_at"`
+}
+
+type modelConfigEntry struct {
+	Config   *ModelConfig `json:"config"`
+	StoredAt time.Time    `json:"stored_at"`
+}
+
+func (d *diskCache) entryPath(modelID, revision, path string) string {
+	return filepath.Join(d.root, sanitizePathSegment(modelID), sanitizePathSegment(revision), sanitizeCacheKey(path)+".json")
+}
+
+func (d *diskCache) modelConfigPath(modelID, revision string) string {
+	return filepath.Join(d.root, sanitizePathSegment(modelID), sanitizePathSegment(revision), "model-config.json")
+}
+
+func sanitizeCacheKey(path string) string {
+	r := strings.NewReplacer("/", "_", "?", "_", "&", "_", "=", "_")
+	return r.Replace(path)
+}
+
+// sanitizePathSegment neutralizes ".." path traversal in a caller-controlled
+// identifier before it's joined into a filesystem path. modelID legitimately
+// contains "/" (HuggingFace's org/model naming), so slashes are preserved as
+// directory separators, but no "/"-delimited segment may be ".", "..", or
+// empty — each such segment is replaced so the result can never escape root.
+func sanitizePathSegment(s string) string {
+	parts := strings.Split(s, "/")
+	for i, p := range parts {
+		if p == "" || p == "." || p == ".." {
+			parts[i] = "_"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func (d *diskCache) Get(modelID, revision, path string) ([]byte, string, bool) {
+	var entry cacheEntry
+	if !readJSONFile(d.entryPath(modelID, revision, path), &entry) {
+		return nil, "", false
+	}
+	if time.Since(entry.StoredAt) > d.ttl {
+		return nil, "", false
+	}
+	return entry.Body, entry.ETag, true
+}
+
+func (d *diskCache) Put(modelID, revision, path string, body []byte, etag string) error {
+	entry := cacheEntry{ETag: etag, Body: body, StoredAt: time.Now()}
+	if err := writeJSONFile(d.entryPath(modelID, revision, path), entry); err != nil {
+		return err
+	}
+	d.evictIfOverBudget()
+	return nil
+}
+
+func (d *diskCache) GetModelConfig(modelID, revision string) (*ModelConfig, bool) {
+	var entry modelConfigEntry
+	if !readJSONFile(d.modelConfigPath(modelID, revision), &entry) {
+		return nil, false
+	}
+	if time.Since(entry.StoredAt) > d.ttl {
+		return nil, false
+	}
+	return entry.Config, true
+}
+
+func (d *diskCache) PutModelConfig(modelID, revision string, cfg *ModelConfig) error {
+	entry := modelConfigEntry{Config: cfg, StoredAt: time.Now()}
+	if err := writeJSONFile(d.modelConfigPath(modelID, revision), entry); err != nil {
+		return err
+	}
+	d.evictIfOverBudget()
+	return nil
+}
+
+func readJSONFile(path string, out any) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, out) == nil
+}
+
+func writeJSONFile(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// evictIfOverBudget walks the whole cache tree and removes the
+// least-recently-written files first until the total size is back under
+// maxBytes. It runs after every write, so the tree never grows much past
+// the budget; eviction throughput doesn't matter at this scale.
+func (d *diskCache) evictIfOverBudget() {
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	var total int64
+
+	filepath.WalkDir(d.root, func(p string, de os.DirEntry, err error) error {
+		if err != nil || de.IsDir() {
+			return nil
+		}
+		info, err := de.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, file{path: p, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+
+	if total <= d.maxBytes {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total <= d.maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err == nil {
+			total -= f.size
+		}
+	}
+}
+
+// noopCache disables caching entirely: every Get/GetModelConfig reports a
+// miss and every Put/PutModelConfig is a no-op. HFClient falls back to it
+// when Cache is nil or --no-cache/ACCELBENCH_HF_NOCACHE disables caching.
+type noopCache struct{}
+
+func (noopCache) Get(modelID, revision, path string) ([]byte, string, bool) { return nil, "", false }
+func (noopCache) Put(modelID, revision, path string, body []byte, etag string) error {
+	return nil
+}
+func (noopCache) GetModelConfig(modelID, revision string) (*ModelConfig, bool) { return nil, false }
+func (noopCache) PutModelConfig(modelID, revision string, cfg *ModelConfig) error {
+	return nil
+}
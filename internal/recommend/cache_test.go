@@ -0,0 +1,138 @@
+package recommend
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestDiskCache(t *testing.T) *diskCache {
+	return &diskCache{root: t.TempDir(), ttl: time.Hour, maxBytes: defaultCacheMaxBytes}
+}
+
+func TestDiskCache_PutGetRoundTrip(t *testing.T) {
+	c := newTestDiskCache(t)
+
+	if err := c.Put("meta-llama/Llama-3.1-8B", "main", "config.json", []byte(`{"a":1}`), `"etag-1"`); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	body, etag, ok := c.Get("meta-llama/Llama-3.1-8B", "main", "config.json")
+	if !ok {
+		t.Fatal("expected cache hit")
+	}
+	if string(body) != `{"a":1}` {
+		t.Errorf("body = %s, want {\"a\":1}", body)
+	}
+	if etag != `"etag-1"` {
+		t.Errorf("etag = %s, want \"etag-1\"", etag)
+	}
+}
+
+func TestDiskCache_Get_MissForDifferentModel(t *testing.T) {
+	c := newTestDiskCache(t)
+	c.Put("model-a", "main", "config.json", []byte("{}"), "etag")
+
+	if _, _, ok := c.Get("model-b", "main", "config.json"); ok {
+		t.Error("expected miss for a different modelID")
+	}
+}
+
+func TestDiskCache_Get_ExpiredEntryIsMiss(t *testing.T) {
+	c := newTestDiskCache(t)
+	c.ttl = time.Millisecond
+	c.Put("model-a", "main", "config.json", []byte("{}"), "etag")
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, _, ok := c.Get("model-a", "main", "config.json"); ok {
+		t.Error("expected expired entry to report a miss")
+	}
+}
+
+func TestDiskCache_ModelConfigRoundTrip(t *testing.T) {
+	c := newTestDiskCache(t)
+	cfg := &ModelConfig{HiddenSize: 4096, NumHiddenLayers: 32, ParameterCount: 7_000_000_000}
+
+	if err := c.PutModelConfig("mistralai/Mistral-7B-v0.1", "main", cfg); err != nil {
+		t.Fatalf("PutModelConfig: %v", err)
+	}
+
+	got, ok := c.GetModelConfig("mistralai/Mistral-7B-v0.1", "main")
+	if !ok {
+		t.Fatal("expected cache hit")
+	}
+	if got.ParameterCount != cfg.ParameterCount {
+		t.Errorf("ParameterCount = %d, want %d", got.ParameterCount, cfg.ParameterCount)
+	}
+}
+
+func TestDiskCache_EvictsOldestFilesOverBudget(t *testing.T) {
+	c := newTestDiskCache(t)
+	c.maxBytes = 1 << 62 // no eviction yet
+
+	if err := c.Put("model-a", "main", "config.json", []byte(`"aaaaaaaaaa"`), "etag-a"); err != nil {
+		t.Fatalf("Put model-a: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond) // ensure distinct mtimes
+
+	info, err := os.Stat(c.entryPath("model-a", "main", "config.json"))
+	if err != nil {
+		t.Fatalf("stat model-a entry: %v", err)
+	}
+	oneEntry := info.Size()
+
+	// Budget room for one entry plus a sliver, so adding a second entry
+	// forces the oldest (model-a) out but leaves the newest (model-b) in.
+	c.maxBytes = oneEntry + oneEntry/2
+	if err := c.Put("model-b", "main", "config.json", []byte(`"bbbbbbbbbb"`), "etag-b"); err != nil {
+		t.Fatalf("Put model-b: %v", err)
+	}
+
+	if _, _, ok := c.Get("model-a", "main", "config.json"); ok {
+		t.Error("expected oldest entry to have been evicted")
+	}
+	if _, _, ok := c.Get("model-b", "main", "config.json"); !ok {
+		t.Error("expected newest entry to survive eviction")
+	}
+}
+
+func TestDiskCache_EntryPath_SanitizesPath(t *testing.T) {
+	c := newTestDiskCache(t)
+	got := c.entryPath("org/model", "main", "api/models?expand[]=safetensors")
+	want := filepath.Join(c.root, "org/model", "main", "api_models_expand[]_safetensors.json")
+	if got != want {
+		t.Errorf("entryPath = %s, want %s", got, want)
+	}
+}
+
+func TestDiskCache_EntryPath_RejectsPathTraversal(t *testing.T) {
+	c := newTestDiskCache(t)
+	got := c.entryPath("../../../../tmp/pwn", "main", "config.json")
+
+	rel, err := filepath.Rel(c.root, got)
+	if err != nil {
+		t.Fatalf("filepath.Rel: %v", err)
+	}
+	if strings.HasPrefix(rel, "..") {
+		t.Errorf("entryPath escaped root: %s", got)
+	}
+}
+
+func TestNoopCache_AlwaysMisses(t *testing.T) {
+	var c noopCache
+	if err := c.Put("m", "main", "p", []byte("x"), "etag"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, _, ok := c.Get("m", "main", "p"); ok {
+		t.Error("expected noopCache.Get to always miss")
+	}
+	if err := c.PutModelConfig("m", "main", &ModelConfig{}); err != nil {
+		t.Fatalf("PutModelConfig: %v", err)
+	}
+	if _, ok := c.GetModelConfig("m", "main"); ok {
+		t.Error("expected noopCache.GetModelConfig to always miss")
+	}
+}
@@ -0,0 +1,152 @@
+package recommend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Constraints holds SLO filters Compare applies before ranking candidates.
+// A zero value in either field means "no constraint".
+type Constraints struct {
+	MaxTTFTMs    float64
+	MinTPSPerReq float64 // tokens/sec a single request must sustain, i.e. 1000/ITLMs
+}
+
+// Pricing is the subset of a pricing row Compare needs per candidate
+// instance, keyed by instance type name in the map Compare takes.
+type Pricing struct {
+	OnDemandHourlyUSD float64
+}
+
+// ComparisonResult ranks one candidate instance type for a model under a
+// set of SLO Constraints. Candidates that don't fit the model at any
+// quantization, or that fit but violate a constraint, have Feasible false
+// and every projection field left zero — only Reason is populated.
+type ComparisonResult struct {
+	InstanceType string `json:"instance_type"`
+	Feasible     bool   `json:"feasible"`
+	Reason       string `json:"reason,omitempty"`
+
+	TensorParallelDegree int     `json:"tensor_parallel_degree,omitempty"`
+	Quantization         *string `json:"quantization,omitempty"`
+	MemoryHeadroomGiB    float64 `json:"memory_headroom_gib,omitempty"`
+
+	ProjectedTTFTMs                 float64 `json:"projected_ttft_ms,omitempty"`
+	ProjectedTPSPerRequest          float64 `json:"projected_tps_per_request,omitempty"`
+	ProjectedThroughputAggregateTPS float64 `json:"projected_throughput_aggregate_tps,omitempty"`
+
+	OnDemandHourlyUSD       *float64 `json:"on_demand_hourly_usd,omitempty"`
+	CostPerMillionTokensUSD *float64 `json:"cost_per_million_tokens_usd,omitempty"`
+}
+
+// Compare ranks candidates for cfg under constraints, fusing each
+// candidate's Recommend feasibility with a projected tokens/sec (via
+// projectThroughput) and — where pricing has an entry for its name — a
+// blended $/1M-output-token cost. allInstances is the full catalog Recommend
+// searches for a larger-instance suggestion when a candidate doesn't fit;
+// it's usually the same catalog candidates was filtered from, but callers
+// that restrict candidates to a short list should still pass the whole
+// catalog here so "try this bigger box instead" isn't limited to that
+// list. Infeasible candidates and those that violate a constraint sort
+// last, ordered among themselves by name for determinism; feasible
+// candidates sort by cost ascending when pricing is known for both,
+// falling back to aggregate throughput descending when it isn't.
+func Compare(cfg ModelConfig, candidates, allInstances []InstanceSpec, pricing map[string]Pricing, constraints Constraints) []ComparisonResult {
+	results := make([]ComparisonResult, 0, len(candidates))
+	for _, inst := range candidates {
+		results = append(results, compareOne(cfg, inst, allInstances, pricing, constraints))
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Feasible != b.Feasible {
+			return a.Feasible
+		}
+		if !a.Feasible {
+			return a.InstanceType < b.InstanceType
+		}
+		if a.CostPerMillionTokensUSD != nil && b.CostPerMillionTokensUSD != nil {
+			return *a.CostPerMillionTokensUSD < *b.CostPerMillionTokensUSD
+		}
+		if a.CostPerMillionTokensUSD != nil {
+			return true
+		}
+		if b.CostPerMillionTokensUSD != nil {
+			return false
+		}
+		return a.ProjectedThroughputAggregateTPS > b.ProjectedThroughputAggregateTPS
+	})
+	return results
+}
+
+func compareOne(cfg ModelConfig, inst InstanceSpec, allInstances []InstanceSpec, pricing map[string]Pricing, constraints Constraints) ComparisonResult {
+	result := ComparisonResult{InstanceType: inst.Name}
+
+	if !strings.EqualFold(inst.AcceleratorType, "gpu") {
+		result.Reason = "configuration projections are not yet available for non-GPU instances"
+		return result
+	}
+
+	rec := Recommend(cfg, inst, allInstances)
+	if !rec.Explanation.Feasible {
+		result.Reason = rec.Explanation.Reason
+		return result
+	}
+
+	quant := rec.ModelInfo.NativeDtype
+	if rec.Quantization != nil {
+		quant = *rec.Quantization
+	}
+
+	ttftMs, itlMs, aggregateTPS := projectThroughput(cfg, inst, quant, rec.TensorParallelDegree, rec.Concurrency, rec.InputSequenceLength)
+	tpsPerReq := 0.0
+	if itlMs > 0 {
+		tpsPerReq = 1000 / itlMs
+	}
+
+	if constraints.MaxTTFTMs > 0 && ttftMs > constraints.MaxTTFTMs {
+		result.Reason = fmt.Sprintf("projected TTFT %.0fms exceeds max_ttft_ms=%.0f", ttftMs, constraints.MaxTTFTMs)
+		return result
+	}
+	if constraints.MinTPSPerReq > 0 && tpsPerReq < constraints.MinTPSPerReq {
+		result.Reason = fmt.Sprintf("projected %.1f tokens/sec per request is below min_tps_per_req=%.1f", tpsPerReq, constraints.MinTPSPerReq)
+		return result
+	}
+
+	result.Feasible = true
+	result.TensorParallelDegree = rec.TensorParallelDegree
+	result.Quantization = rec.Quantization
+	result.MemoryHeadroomGiB = memoryHeadroomGiB(cfg, inst, quant)
+	result.ProjectedTTFTMs = ttftMs
+	result.ProjectedTPSPerRequest = tpsPerReq
+	result.ProjectedThroughputAggregateTPS = aggregateTPS
+
+	if p, ok := pricing[inst.Name]; ok {
+		hourly := p.OnDemandHourlyUSD
+		result.OnDemandHourlyUSD = &hourly
+		if aggregateTPS > 0 {
+			tokensPerHour := aggregateTPS * 3600
+			costPerMillion := hourly / tokensPerHour * 1e6
+			result.CostPerMillionTokensUSD = &costPerMillion
+		}
+	}
+
+	return result
+}
+
+// memoryHeadroomGiB returns how much accelerator memory is left across
+// inst's devices after cfg's weights at quant, using the same usable-
+// fraction and total-capacity accounting fit/Recommend size KV cache
+// with. Negative headroom (shouldn't happen for a feasible candidate,
+// since fit already checked this) is clamped to zero.
+func memoryHeadroomGiB(cfg ModelConfig, inst InstanceSpec, quant string) float64 {
+	perDeviceGiB := float64(inst.AcceleratorMemoryGiB) / float64(inst.AcceleratorCount)
+	usablePerDevice := perDeviceGiB * gibBytes * (1 - overheadFraction)
+	totalUsable := usablePerDevice * float64(inst.AcceleratorCount)
+	headroom := totalUsable - ModelMemoryBytes(cfg.ParameterCount, quant)
+	if headroom < 0 {
+		headroom = 0
+	}
+	return headroom / gibBytes
+}
@@ -0,0 +1,96 @@
+package recommend
+
+import "testing"
+
+func TestCompare_RanksFeasibleBeforeInfeasible(t *testing.T) {
+	candidates := []InstanceSpec{g5xlarge, p5_48xlarge}
+	results := Compare(llama70B, candidates, candidates, nil, Constraints{})
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if !results[0].Feasible {
+		t.Errorf("first result = %+v, want feasible", results[0])
+	}
+	if results[0].InstanceType != p5_48xlarge.Name {
+		t.Errorf("first result instance = %s, want %s", results[0].InstanceType, p5_48xlarge.Name)
+	}
+	if results[1].Feasible {
+		t.Errorf("second result = %+v, want infeasible (llama70B doesn't fit g5.xlarge)", results[1])
+	}
+	if results[1].Reason == "" {
+		t.Error("infeasible result should have a Reason")
+	}
+}
+
+func TestCompare_CostRankingPrefersCheaperPerMillionTokens(t *testing.T) {
+	pricing := map[string]Pricing{
+		g5_12xlarge.Name: {OnDemandHourlyUSD: 5.67},
+		g5_48xlarge.Name: {OnDemandHourlyUSD: 20.36},
+	}
+	candidates := []InstanceSpec{g5_12xlarge, g5_48xlarge}
+	results := Compare(mistral7B, candidates, candidates, pricing, Constraints{})
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	for _, r := range results {
+		if !r.Feasible {
+			t.Fatalf("expected %s feasible for a 7B model, got reason %q", r.InstanceType, r.Reason)
+		}
+		if r.CostPerMillionTokensUSD == nil {
+			t.Fatalf("expected cost computed for %s", r.InstanceType)
+		}
+	}
+	if *results[0].CostPerMillionTokensUSD > *results[1].CostPerMillionTokensUSD {
+		t.Errorf("results not sorted by cost ascending: %+v then %+v", results[0], results[1])
+	}
+}
+
+func TestCompare_MaxTTFTConstraintExcludesSlowCandidate(t *testing.T) {
+	unconstrained := Compare(mistral7B, []InstanceSpec{g5xlarge}, []InstanceSpec{g5xlarge}, nil, Constraints{})
+	if !unconstrained[0].Feasible {
+		t.Fatalf("expected mistral7B feasible on g5.xlarge, got reason %q", unconstrained[0].Reason)
+	}
+	tight := Compare(mistral7B, []InstanceSpec{g5xlarge}, []InstanceSpec{g5xlarge}, nil, Constraints{MaxTTFTMs: 0.001})
+	if tight[0].Feasible {
+		t.Errorf("expected an unrealistically tight max_ttft_ms to exclude the candidate, got %+v", tight[0])
+	}
+	if tight[0].Reason == "" {
+		t.Error("excluded-by-constraint result should have a Reason")
+	}
+}
+
+func TestCompare_NonGPUCandidateIsInfeasibleWithReason(t *testing.T) {
+	neuron := InstanceSpec{Name: "inf2.xlarge", AcceleratorType: "neuron", AcceleratorName: "Inferentia2", AcceleratorCount: 2, AcceleratorMemoryGiB: 32}
+	results := Compare(mistral7B, []InstanceSpec{neuron}, []InstanceSpec{neuron}, nil, Constraints{})
+	if results[0].Feasible {
+		t.Errorf("expected neuron candidate infeasible, got %+v", results[0])
+	}
+	if results[0].Reason == "" {
+		t.Error("expected a reason for the unsupported accelerator type")
+	}
+}
+
+func TestCompare_MemoryHeadroomPositiveForFeasibleCandidate(t *testing.T) {
+	results := Compare(mistral7B, []InstanceSpec{g5_12xlarge}, []InstanceSpec{g5_12xlarge}, nil, Constraints{})
+	if !results[0].Feasible {
+		t.Fatalf("expected feasible, got reason %q", results[0].Reason)
+	}
+	if results[0].MemoryHeadroomGiB <= 0 {
+		t.Errorf("got memory headroom %.1f GiB, want > 0", results[0].MemoryHeadroomGiB)
+	}
+}
+
+func TestCompare_LargerInstanceSuggestionSearchesFullCatalogNotJustCandidates(t *testing.T) {
+	// llama70B doesn't fit on g5.xlarge. If Recommend's alternative search is
+	// restricted to the (narrow) candidates slice instead of the full
+	// catalog, it can't find p5_48xlarge as a suggested larger instance even
+	// though it's available and would fit.
+	results := Compare(llama70B, []InstanceSpec{g5xlarge}, []InstanceSpec{g5xlarge, p5_48xlarge}, nil, Constraints{})
+	if results[0].Feasible {
+		t.Fatalf("expected g5.xlarge infeasible for llama70B, got %+v", results[0])
+	}
+	rec := Recommend(llama70B, g5xlarge, []InstanceSpec{g5xlarge, p5_48xlarge})
+	if rec.Alternatives == nil || rec.Alternatives.LargerInstance == "" {
+		t.Fatalf("expected Recommend to suggest a larger instance from the full catalog, got %+v", rec.Alternatives)
+	}
+}
@@ -0,0 +1,150 @@
+package recommend
+
+import (
+	"math"
+	"testing"
+)
+
+// TestEstimateParameterCount_MatchesSafetensorsTotals checks the estimate
+// against safetensors-reported totals for public models spanning the FFN
+// and embedding variations detectFFNFamily and TieWordEmbeddings handle:
+// gated (SwiGLU) vs. plain FFNs, tied vs. untied embeddings, and MHA vs.
+// GQA/MQA attention. tolerancePct is widened for Falcon, whose MQA (a
+// single shared KV head) this estimate doesn't special-case beyond the
+// general GQA reduction below — it still lands well short of the naive
+// 4×h² attention count, just not as precisely as true MQA sizing would.
+func TestEstimateParameterCount_MatchesSafetensorsTotals(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          *hfConfigJSON
+		actual       int64
+		tolerancePct float64
+	}{
+		{
+			name: "Llama-2-7b",
+			cfg: &hfConfigJSON{
+				ModelType: "llama", HiddenSize: 4096, NumHiddenLayers: 32,
+				IntermediateSize: 11008, VocabSize: 32000,
+				NumAttentionHeads: 32, NumKeyValueHeads: 32,
+			},
+			actual: 6_738_415_616, tolerancePct: 5,
+		},
+		{
+			name: "Llama-2-13b",
+			cfg: &hfConfigJSON{
+				ModelType: "llama", HiddenSize: 5120, NumHiddenLayers: 40,
+				IntermediateSize: 13824, VocabSize: 32000,
+				NumAttentionHeads: 40, NumKeyValueHeads: 40,
+			},
+			actual: 13_015_864_320, tolerancePct: 5,
+		},
+		{
+			name: "Mistral-7B-v0.1 (GQA)",
+			cfg: &hfConfigJSON{
+				ModelType: "mistral", HiddenSize: 4096, NumHiddenLayers: 32,
+				IntermediateSize: 14336, VocabSize: 32000,
+				NumAttentionHeads: 32, NumKeyValueHeads: 8,
+			},
+			actual: 7_241_732_096, tolerancePct: 5,
+		},
+		{
+			name: "Llama-3-8B (GQA, large vocab)",
+			cfg: &hfConfigJSON{
+				ModelType: "llama", HiddenSize: 4096, NumHiddenLayers: 32,
+				IntermediateSize: 14336, VocabSize: 128256,
+				NumAttentionHeads: 32, NumKeyValueHeads: 8,
+			},
+			actual: 8_030_261_248, tolerancePct: 5,
+		},
+		{
+			name: "GPT-NeoX-20B (plain FFN, untied)",
+			cfg: &hfConfigJSON{
+				ModelType: "gpt_neox", HiddenSize: 6144, NumHiddenLayers: 44,
+				IntermediateSize: 24576, VocabSize: 50432,
+				NumAttentionHeads: 64, NumKeyValueHeads: 64,
+				UseParallelResidual: true,
+			},
+			actual: 20_556_201_344, tolerancePct: 5,
+		},
+		{
+			name: "Falcon-7B (plain FFN, MQA)",
+			cfg: &hfConfigJSON{
+				Architectures: []string{"FalconForCausalLM"}, HiddenSize: 4544, NumHiddenLayers: 32,
+				IntermediateSize: 18176, VocabSize: 65024,
+				NumAttentionHeads: 71, NumKeyValueHeads: 1,
+			},
+			actual: 6_921_720_704, tolerancePct: 15,
+		},
+		{
+			name: "Gemma-2b (gated FFN, tied embeddings, huge vocab)",
+			cfg: &hfConfigJSON{
+				ModelType: "gemma", HiddenSize: 2048, NumHiddenLayers: 18,
+				IntermediateSize: 16384, VocabSize: 256000,
+				NumAttentionHeads: 8, NumKeyValueHeads: 1,
+				TieWordEmbeddings: true,
+			},
+			actual: 2_506_172_416, tolerancePct: 10,
+		},
+		{
+			name: "Mixtral-8x7B (MoE, gated, GQA)",
+			cfg: &hfConfigJSON{
+				ModelType: "mixtral", HiddenSize: 4096, NumHiddenLayers: 32,
+				IntermediateSize: 14336, VocabSize: 32000,
+				NumAttentionHeads: 32, NumKeyValueHeads: 8,
+				NumLocalExperts: 8, NumExpertsPerTok: 2,
+			},
+			actual: 46_702_792_704, tolerancePct: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := estimateParameterCount(tt.cfg)
+			diffPct := math.Abs(float64(got-tt.actual)) / float64(tt.actual) * 100
+			if diffPct > tt.tolerancePct {
+				t.Errorf("estimateParameterCount() = %d, actual %d (%.1f%% off, want <=%.1f%%)",
+					got, tt.actual, diffPct, tt.tolerancePct)
+			}
+		})
+	}
+}
+
+func TestDetectFFNFamily(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *hfConfigJSON
+		want ffnFamily
+	}{
+		{"llama by model_type", &hfConfigJSON{ModelType: "llama"}, gatedFFN},
+		{"gpt_neox by model_type", &hfConfigJSON{ModelType: "gpt_neox"}, plainFFN},
+		{"falcon by model_type", &hfConfigJSON{ModelType: "falcon"}, plainFFN},
+		{"bloom by model_type", &hfConfigJSON{ModelType: "bloom"}, plainFFN},
+		{"falcon by architectures fallback", &hfConfigJSON{Architectures: []string{"FalconForCausalLM"}}, plainFFN},
+		{"unknown defaults to gated", &hfConfigJSON{ModelType: "some-new-arch"}, gatedFFN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectFFNFamily(tt.cfg); got != tt.want {
+				t.Errorf("detectFFNFamily() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateParameterCount_TiedEmbeddingsHalvesEmbeddingContribution(t *testing.T) {
+	untied := &hfConfigJSON{
+		ModelType: "gemma", HiddenSize: 1024, NumHiddenLayers: 4,
+		IntermediateSize: 2048, VocabSize: 100000,
+		NumAttentionHeads: 8, NumKeyValueHeads: 8,
+	}
+	tied := *untied
+	tied.TieWordEmbeddings = true
+
+	gotUntied := estimateParameterCount(untied)
+	gotTied := estimateParameterCount(&tied)
+
+	wantDiff := int64(100000) * 1024 // one fewer vocab*h matrix when tied
+	if diff := gotUntied - gotTied; diff != wantDiff {
+		t.Errorf("tied/untied estimate diff = %d, want %d", diff, wantDiff)
+	}
+}
@@ -0,0 +1,370 @@
+package recommend
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GGUF value types, per the spec's metadata KV value_type enum
+// (https://github.com/ggml-org/ggml/blob/master/docs/gguf.md).
+const (
+	ggufTypeUint8 uint32 = iota
+	ggufTypeInt8
+	ggufTypeUint16
+	ggufTypeInt16
+	ggufTypeUint32
+	ggufTypeInt32
+	ggufTypeFloat32
+	ggufTypeBool
+	ggufTypeString
+	ggufTypeArray
+	ggufTypeUint64
+	ggufTypeInt64
+	ggufTypeFloat64
+)
+
+// ggufScalarSize gives the on-disk size in bytes of each fixed-size GGUF
+// value type; ggufTypeString and ggufTypeArray are variable-length and
+// handled separately by skipGGUFValue.
+var ggufScalarSize = map[uint32]int64{
+	ggufTypeUint8:   1,
+	ggufTypeInt8:    1,
+	ggufTypeUint16:  2,
+	ggufTypeInt16:   2,
+	ggufTypeUint32:  4,
+	ggufTypeInt32:   4,
+	ggufTypeFloat32: 4,
+	ggufTypeBool:    1,
+	ggufTypeUint64:  8,
+	ggufTypeInt64:   8,
+	ggufTypeFloat64: 8,
+}
+
+// ggufTensorTypeBitsPerElement approximates the on-disk size of each ggml
+// tensor dtype, in (numerator, denominator) bits-per-element so block-
+// quantized types (Q4_K, Q6_K, ...) don't need fractional bytes. Figures
+// are the superblock/block sizes ggml's quantization formats use; unknown
+// or future dtypes fall back to an 4.5-bit average rather than failing the
+// whole parse, since this only feeds a coarse VRAM estimate.
+var ggufTensorTypeBitsPerElement = map[uint32]float64{
+	0:  32,     // F32
+	1:  16,     // F16
+	2:  4.5,    // Q4_0
+	3:  5,      // Q4_1
+	6:  5.5,    // Q5_0
+	7:  6,      // Q5_1
+	8:  8.5,    // Q8_0
+	10: 2.625,  // Q2_K
+	11: 3.4375, // Q3_K
+	12: 4.5,    // Q4_K
+	13: 5.5,    // Q5_K
+	14: 6.5625, // Q6_K
+	15: 9.125,  // Q8_K
+	24: 8,      // I8
+	25: 16,     // I16
+	26: 32,     // I32
+	30: 16,     // BF16
+}
+
+const ggufDefaultBitsPerElement = 4.5
+
+// ggufMeta is the architecture and sizing metadata parsed from a GGUF
+// file's header — enough to populate ModelConfig without downloading the
+// (often many-GiB) tensor data that follows it.
+type ggufMeta struct {
+	Architecture      string
+	QuantizationLabel string
+	ParameterCount    int64
+	BytesOnDisk       int64
+
+	// HiddenSize, NumAttentionHeads, NumKeyValueHeads, NumHiddenLayers, and
+	// MaxPositionEmbeddings mirror hfConfigJSON's fields of the same
+	// meaning, read from GGUF's per-architecture "<arch>.*" keys (e.g.
+	// "llama.attention.head_count") rather than a known fixed name, since
+	// the prefix varies by model. They're zero if the file's metadata
+	// doesn't include them.
+	HiddenSize            int
+	NumAttentionHeads     int
+	NumKeyValueHeads      int
+	NumHiddenLayers       int
+	MaxPositionEmbeddings int
+}
+
+// parseGGUFHeader reads a GGUF file's magic, version, metadata KV section,
+// and tensor info section from r — the header llama.cpp writes before the
+// (aligned) tensor data — and derives total parameter count and on-disk
+// tensor bytes from the tensor list. r need not contain the tensor data
+// itself; only enough of the file to cover the header is required, and
+// parseGGUFHeader returns as soon as the tensor list has been read.
+func parseGGUFHeader(r io.Reader) (*ggufMeta, error) {
+	br := bufio.NewReader(r)
+
+	var magic [4]byte
+	if _, err := io.ReadFull(br, magic[:]); err != nil {
+		return nil, fmt.Errorf("read magic: %w", err)
+	}
+	if string(magic[:]) != "GGUF" {
+		return nil, fmt.Errorf("not a GGUF file: magic = %q", magic)
+	}
+
+	var version uint32
+	if err := binary.Read(br, binary.LittleEndian, &version); err != nil {
+		return nil, fmt.Errorf("read version: %w", err)
+	}
+
+	var tensorCount, kvCount uint64
+	if err := binary.Read(br, binary.LittleEndian, &tensorCount); err != nil {
+		return nil, fmt.Errorf("read tensor_count: %w", err)
+	}
+	if err := binary.Read(br, binary.LittleEndian, &kvCount); err != nil {
+		return nil, fmt.Errorf("read metadata_kv_count: %w", err)
+	}
+
+	meta := &ggufMeta{}
+	var fileType int64 = -1
+	for i := uint64(0); i < kvCount; i++ {
+		key, err := readGGUFString(br)
+		if err != nil {
+			return nil, fmt.Errorf("read kv[%d] key: %w", i, err)
+		}
+		var valueType uint32
+		if err := binary.Read(br, binary.LittleEndian, &valueType); err != nil {
+			return nil, fmt.Errorf("read kv[%d] value_type: %w", i, err)
+		}
+
+		switch key {
+		case "general.architecture":
+			s, err := readGGUFValueAsString(br, valueType)
+			if err != nil {
+				return nil, fmt.Errorf("read kv[%d] %s: %w", i, key, err)
+			}
+			meta.Architecture = s
+			continue
+		case "general.file_type":
+			if valueType == ggufTypeUint32 || valueType == ggufTypeInt32 {
+				var v int32
+				if err := binary.Read(br, binary.LittleEndian, &v); err != nil {
+					return nil, fmt.Errorf("read kv[%d] %s: %w", i, key, err)
+				}
+				fileType = int64(v)
+				continue
+			}
+		}
+
+		// Per-architecture dimension keys (e.g. "llama.attention.head_count")
+		// are prefixed with the architecture name, which varies, so match on
+		// suffix instead of the full key.
+		if dst := ggufDimensionField(meta, key); dst != nil {
+			v, err := readGGUFValueAsInt(br, valueType)
+			if err != nil {
+				return nil, fmt.Errorf("read kv[%d] %s: %w", i, key, err)
+			}
+			*dst = int(v)
+			continue
+		}
+
+		if err := skipGGUFValue(br, valueType); err != nil {
+			return nil, fmt.Errorf("skip kv[%d] %s: %w", i, key, err)
+		}
+	}
+
+	var totalElements int64
+	var totalBits float64
+	for i := uint64(0); i < tensorCount; i++ {
+		if _, err := readGGUFString(br); err != nil { // tensor name
+			return nil, fmt.Errorf("read tensor[%d] name: %w", i, err)
+		}
+		var nDims uint32
+		if err := binary.Read(br, binary.LittleEndian, &nDims); err != nil {
+			return nil, fmt.Errorf("read tensor[%d] n_dims: %w", i, err)
+		}
+		elements := int64(1)
+		for d := uint32(0); d < nDims; d++ {
+			var dim uint64
+			if err := binary.Read(br, binary.LittleEndian, &dim); err != nil {
+				return nil, fmt.Errorf("read tensor[%d] dim[%d]: %w", i, d, err)
+			}
+			elements *= int64(dim)
+		}
+		var dtype uint32
+		if err := binary.Read(br, binary.LittleEndian, &dtype); err != nil {
+			return nil, fmt.Errorf("read tensor[%d] dtype: %w", i, err)
+		}
+		var offset uint64
+		if err := binary.Read(br, binary.LittleEndian, &offset); err != nil {
+			return nil, fmt.Errorf("read tensor[%d] offset: %w", i, err)
+		}
+
+		bitsPerElement, ok := ggufTensorTypeBitsPerElement[dtype]
+		if !ok {
+			bitsPerElement = ggufDefaultBitsPerElement
+		}
+		totalElements += elements
+		totalBits += float64(elements) * bitsPerElement
+	}
+
+	meta.ParameterCount = totalElements
+	meta.BytesOnDisk = int64(totalBits / 8)
+	meta.QuantizationLabel = ggufFileTypeLabel(fileType)
+	return meta, nil
+}
+
+// ggufFileTypeLabel maps general.file_type's enum (llama.cpp's
+// llama_ftype) to the short quantization labels this package uses
+// elsewhere (e.g. "q4_k_m"). Unrecognized or absent file types return "",
+// leaving Quantization unset rather than guessing.
+func ggufFileTypeLabel(fileType int64) string {
+	switch fileType {
+	case 0:
+		return "f32"
+	case 1:
+		return "f16"
+	case 2:
+		return "q4_0"
+	case 3:
+		return "q4_1"
+	case 7:
+		return "q8_0"
+	case 8:
+		return "q5_0"
+	case 9:
+		return "q5_1"
+	case 10:
+		return "q2_k"
+	case 11:
+		return "q3_k_s"
+	case 12:
+		return "q3_k_m"
+	case 13:
+		return "q3_k_l"
+	case 14:
+		return "q4_k_s"
+	case 15:
+		return "q4_k_m"
+	case 16:
+		return "q5_k_s"
+	case 17:
+		return "q5_k_m"
+	case 18:
+		return "q6_k"
+	default:
+		return ""
+	}
+}
+
+// ggufDimensionField returns a pointer to the ggufMeta field key maps to,
+// matched by suffix since GGUF prefixes these keys with the (variable)
+// architecture name, or nil if key isn't one of the dimension fields
+// parseGGUFHeader tracks.
+func ggufDimensionField(meta *ggufMeta, key string) *int {
+	switch {
+	case strings.HasSuffix(key, ".attention.head_count"):
+		return &meta.NumAttentionHeads
+	case strings.HasSuffix(key, ".attention.head_count_kv"):
+		return &meta.NumKeyValueHeads
+	case strings.HasSuffix(key, ".embedding_length"):
+		return &meta.HiddenSize
+	case strings.HasSuffix(key, ".block_count"):
+		return &meta.NumHiddenLayers
+	case strings.HasSuffix(key, ".context_length"):
+		return &meta.MaxPositionEmbeddings
+	}
+	return nil
+}
+
+// readGGUFValueAsInt reads a scalar GGUF value of any integer value_type and
+// widens it to int64, for the fixed-size dimension fields GGUF stores as
+// uint32 (or occasionally a different integer width).
+func readGGUFValueAsInt(r io.Reader, valueType uint32) (int64, error) {
+	switch valueType {
+	case ggufTypeUint8:
+		var v uint8
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+	case ggufTypeInt8:
+		var v int8
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+	case ggufTypeUint16:
+		var v uint16
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+	case ggufTypeInt16:
+		var v int16
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+	case ggufTypeUint32:
+		var v uint32
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+	case ggufTypeInt32:
+		var v int32
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+	case ggufTypeUint64:
+		var v uint64
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return int64(v), err
+	case ggufTypeInt64:
+		var v int64
+		err := binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	default:
+		return 0, fmt.Errorf("expected integer value, got type %d", valueType)
+	}
+}
+
+func readGGUFString(r io.Reader) (string, error) {
+	var n uint64
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return "", err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// readGGUFValueAsString reads a scalar GGUF value expected to be a string,
+// returning an error for any other value_type.
+func readGGUFValueAsString(r io.Reader, valueType uint32) (string, error) {
+	if valueType != ggufTypeString {
+		return "", fmt.Errorf("expected string value, got type %d", valueType)
+	}
+	return readGGUFString(r)
+}
+
+// skipGGUFValue reads past a metadata value of valueType without
+// interpreting it, including nested array element values.
+func skipGGUFValue(r io.Reader, valueType uint32) error {
+	if valueType == ggufTypeString {
+		_, err := readGGUFString(r)
+		return err
+	}
+	if valueType == ggufTypeArray {
+		var elemType uint32
+		if err := binary.Read(r, binary.LittleEndian, &elemType); err != nil {
+			return err
+		}
+		var count uint64
+		if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+			return err
+		}
+		for i := uint64(0); i < count; i++ {
+			if err := skipGGUFValue(r, elemType); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	size, ok := ggufScalarSize[valueType]
+	if !ok {
+		return fmt.Errorf("unknown GGUF value_type %d", valueType)
+	}
+	_, err := io.CopyN(io.Discard, r, size)
+	return err
+}
@@ -0,0 +1,175 @@
+package recommend
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// ggufWriter builds an in-memory GGUF byte stream using the same encoding
+// parseGGUFHeader expects, so tests don't need a real .gguf file on disk.
+type ggufWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *ggufWriter) string(s string) {
+	binary.Write(&w.buf, binary.LittleEndian, uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+func (w *ggufWriter) u32(v uint32) { binary.Write(&w.buf, binary.LittleEndian, v) }
+func (w *ggufWriter) u64(v uint64) { binary.Write(&w.buf, binary.LittleEndian, v) }
+func (w *ggufWriter) i32(v int32)  { binary.Write(&w.buf, binary.LittleEndian, v) }
+
+func (w *ggufWriter) kvString(key, value string) {
+	w.string(key)
+	w.u32(ggufTypeString)
+	w.string(value)
+}
+
+func (w *ggufWriter) kvInt32(key string, value int32) {
+	w.string(key)
+	w.u32(ggufTypeInt32)
+	w.i32(value)
+}
+
+func (w *ggufWriter) kvArrayOfStrings(key string, values []string) {
+	w.string(key)
+	w.u32(ggufTypeArray)
+	w.u32(ggufTypeString)
+	w.u64(uint64(len(values)))
+	for _, v := range values {
+		w.string(v)
+	}
+}
+
+func (w *ggufWriter) tensor(name string, dims []uint64, dtype uint32) {
+	w.string(name)
+	w.u32(uint32(len(dims)))
+	for _, d := range dims {
+		w.u64(d)
+	}
+	w.u32(dtype)
+	w.u64(0) // offset, unused by parseGGUFHeader
+}
+
+func TestParseGGUFHeader_RoundTrip(t *testing.T) {
+	var w ggufWriter
+	w.buf.WriteString("GGUF")
+	w.u32(3) // version
+	w.u64(2) // tensor_count
+	w.u64(8) // metadata_kv_count
+
+	w.kvString("general.architecture", "llama")
+	w.kvInt32("general.file_type", 2) // q4_0
+	w.kvInt32("llama.attention.head_count", 32)
+	w.kvInt32("llama.attention.head_count_kv", 8)
+	w.kvInt32("llama.embedding_length", 4096)
+	w.kvInt32("llama.block_count", 32)
+	w.kvInt32("llama.context_length", 8192)
+	w.kvArrayOfStrings("tokenizer.ggml.tokens", []string{"a", "b", "c"}) // exercises skipGGUFValue's array path
+
+	w.tensor("token_embd.weight", []uint64{4096, 32000}, 1)  // F16
+	w.tensor("blk.0.attn_q.weight", []uint64{4096, 4096}, 2) // Q4_0
+
+	meta, err := parseGGUFHeader(bytes.NewReader(w.buf.Bytes()))
+	if err != nil {
+		t.Fatalf("parseGGUFHeader: %v", err)
+	}
+
+	if meta.Architecture != "llama" {
+		t.Errorf("Architecture = %q, want llama", meta.Architecture)
+	}
+	if meta.QuantizationLabel != "q4_0" {
+		t.Errorf("QuantizationLabel = %q, want q4_0", meta.QuantizationLabel)
+	}
+	if meta.NumAttentionHeads != 32 {
+		t.Errorf("NumAttentionHeads = %d, want 32", meta.NumAttentionHeads)
+	}
+	if meta.NumKeyValueHeads != 8 {
+		t.Errorf("NumKeyValueHeads = %d, want 8", meta.NumKeyValueHeads)
+	}
+	if meta.HiddenSize != 4096 {
+		t.Errorf("HiddenSize = %d, want 4096", meta.HiddenSize)
+	}
+	if meta.NumHiddenLayers != 32 {
+		t.Errorf("NumHiddenLayers = %d, want 32", meta.NumHiddenLayers)
+	}
+	if meta.MaxPositionEmbeddings != 8192 {
+		t.Errorf("MaxPositionEmbeddings = %d, want 8192", meta.MaxPositionEmbeddings)
+	}
+	wantElements := int64(4096*32000 + 4096*4096)
+	if meta.ParameterCount != wantElements {
+		t.Errorf("ParameterCount = %d, want %d", meta.ParameterCount, wantElements)
+	}
+	wantBits := float64(4096*32000)*16 + float64(4096*4096)*4.5
+	wantBytes := int64(wantBits / 8)
+	if meta.BytesOnDisk != wantBytes {
+		t.Errorf("BytesOnDisk = %d, want %d", meta.BytesOnDisk, wantBytes)
+	}
+}
+
+func TestParseGGUFHeader_RejectsBadMagic(t *testing.T) {
+	if _, err := parseGGUFHeader(bytes.NewReader([]byte("NOPE1234"))); err == nil {
+		t.Fatal("expected an error for a non-GGUF magic")
+	}
+}
+
+func TestParseGGUFHeader_UnknownDtypeFallsBackToDefault(t *testing.T) {
+	var w ggufWriter
+	w.buf.WriteString("GGUF")
+	w.u32(3)
+	w.u64(1)
+	w.u64(0)
+	w.tensor("weight", []uint64{1000}, 999) // unrecognized dtype
+
+	meta, err := parseGGUFHeader(bytes.NewReader(w.buf.Bytes()))
+	if err != nil {
+		t.Fatalf("parseGGUFHeader: %v", err)
+	}
+	elements := 1000
+	wantBytes := int64(float64(elements) * ggufDefaultBitsPerElement / 8)
+	if meta.BytesOnDisk != wantBytes {
+		t.Errorf("BytesOnDisk = %d, want %d", meta.BytesOnDisk, wantBytes)
+	}
+}
+
+func TestGGUFFileTypeLabel(t *testing.T) {
+	tests := []struct {
+		fileType int64
+		want     string
+	}{
+		{0, "f32"},
+		{1, "f16"},
+		{14, "q4_k_s"},
+		{18, "q6_k"},
+		{-1, ""},
+		{999, ""},
+	}
+	for _, tt := range tests {
+		if got := ggufFileTypeLabel(tt.fileType); got != tt.want {
+			t.Errorf("ggufFileTypeLabel(%d) = %q, want %q", tt.fileType, got, tt.want)
+		}
+	}
+}
+
+func TestQuantizationLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *hfQuantizationConfig
+		want string
+	}{
+		{"nil config", nil, ""},
+		{"empty quant_method", &hfQuantizationConfig{}, ""},
+		{"awq defaults bits to 4", &hfQuantizationConfig{QuantMethod: "awq"}, "awq-int4"},
+		{"gptq with group size", &hfQuantizationConfig{QuantMethod: "gptq", Bits: 4, GroupSize: 128}, "gptq-int4-g128"},
+		{"gptq without group size", &hfQuantizationConfig{QuantMethod: "gptq", Bits: 8}, "gptq-int8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quantizationLabel(tt.q); got != tt.want {
+				t.Errorf("quantizationLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,27 +1,177 @@
 package recommend
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
+// hfMainRevision is the only revision this client ever fetches — both
+// request URLs are hardcoded to HuggingFace's "main" branch. It's threaded
+// through as an explicit cache key component so a future revision-selecting
+// caller doesn't silently collide with "main"'s cache entries.
+const hfMainRevision = "main"
+
+// cacheModelKey scopes cache entries by hfToken as well as modelID: a gated
+// model's metadata fetched with a valid token must never be served back to
+// a later caller with no token, or a different one, so the token (hashed —
+// never written to disk in plaintext) is folded into the key used for both
+// the per-path cache and the resolved-ModelConfig cache.
+func cacheModelKey(modelID, hfToken string) string {
+	if hfToken == "" {
+		return modelID
+	}
+	sum := sha256.Sum256([]byte(hfToken))
+	return modelID + "#" + hex.EncodeToString(sum[:8])
+}
+
 // HFClient fetches model metadata from the HuggingFace API.
 type HFClient struct {
 	httpClient *http.Client
 	baseURL    string
+
+	// Cache persists fetched bodies (by ETag) and resolved ModelConfigs
+	// across calls. Defaults to a diskCache; set to a different Cache, or
+	// nil, to change that.
+	Cache Cache
+	// NoCache disables Cache entirely, as does setting
+	// ACCELBENCH_HF_NOCACHE=1 — the latter is checked on every call, so it
+	// can be toggled without restarting the process.
+	NoCache bool
+
+	deadline deadlineTimer
 }
 
 // NewHFClient creates a new HuggingFace API client.
 func NewHFClient() *HFClient {
-	return &HFClient{
+	c := &HFClient{
 		httpClient: &http.Client{Timeout: 15 * time.Second},
 		baseURL:    "https://huggingface.co",
+		Cache:      newDiskCache(),
+	}
+	c.deadline.init()
+	return c
+}
+
+// noCache reports whether caching is disabled for this call, either via
+// HFClient.NoCache or the ACCELBENCH_HF_NOCACHE escape hatch.
+func (c *HFClient) noCache() bool {
+	return c.NoCache || os.Getenv("ACCELBENCH_HF_NOCACHE") == "1"
+}
+
+// cache returns c.Cache, or noopCache if caching is disabled or c.Cache is nil.
+func (c *HFClient) cache() Cache {
+	if c.noCache() || c.Cache == nil {
+		return noopCache{}
+	}
+	return c.Cache
+}
+
+// SetFetchDeadline imposes a wall-clock deadline across both the safetensors
+// and config.json fetches FetchModelConfigContext makes: once t passes, any
+// in-flight fetches on this client are canceled and FetchModelConfigContext
+// returns errFetchDeadlineExceeded instead of whatever lower-level error the
+// canceled request produced. A zero Time clears the deadline. Safe to call
+// before or during a FetchModelConfigContext call.
+func (c *HFClient) SetFetchDeadline(t time.Time) {
+	c.deadline.set(t)
+}
+
+// deadlineTimer fires two independent cancel channels — one for the
+// safetensors fetch, one for config.json — from a single wall-clock
+// deadline, following the per-direction deadline pattern net.Conn
+// implementations use for SetReadDeadline/SetWriteDeadline (as in netstack's
+// gonet adapter): each SetFetchDeadline call replaces both channels and
+// re-arms a shared timer. changed is closed (and replaced) on every such
+// call so a watchDeadline goroutine blocked on a now-stale channel wakes up
+// and re-snapshots, letting a deadline set mid-fetch still take effect.
+type deadlineTimer struct {
+	mu           sync.Mutex
+	timer        *time.Timer
+	modelCancel  chan struct{}
+	configCancel chan struct{}
+	changed      chan struct{}
+}
+
+func (d *deadlineTimer) init() {
+	d.modelCancel = make(chan struct{})
+	d.configCancel = make(chan struct{})
+	d.changed = make(chan struct{})
+}
+
+func (d *deadlineTimer) set(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.modelCancel = make(chan struct{})
+	d.configCancel = make(chan struct{})
+	changed := d.changed
+	d.changed = make(chan struct{})
+	close(changed)
+
+	if t.IsZero() {
+		d.timer = nil
+		return
+	}
+
+	modelCh, configCh := d.modelCancel, d.configCancel
+	timeout := time.Until(t)
+	if timeout <= 0 {
+		close(modelCh)
+		close(configCh)
+		return
+	}
+	d.timer = time.AfterFunc(timeout, func() {
+		close(modelCh)
+		close(configCh)
+	})
+}
+
+// snapshot returns the cancel channels currently armed for the model and
+// config fetches, plus changed, which closes the next time set replaces
+// them — so a watcher can tell its snapshot is stale and re-snapshot
+// instead of waiting on channels no timer will ever close.
+func (d *deadlineTimer) snapshot() (model, config, changed chan struct{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.modelCancel, d.configCancel, d.changed
+}
+
+// exceeded reports whether direction's cancel channel is currently closed,
+// i.e. whether the most recently set deadline has fired for it.
+func (d *deadlineTimer) exceeded(direction fetchDirection) bool {
+	model, config, _ := d.snapshot()
+	ch := model
+	if direction == configDirection {
+		ch = config
 	}
+	return isClosed(ch)
 }
 
+type fetchDirection int
+
+const (
+	modelDirection fetchDirection = iota
+	configDirection
+)
+
+// errFetchDeadlineExceeded is returned by FetchModelConfigContext in place
+// of whatever lower-level error a canceled request produced, when
+// SetFetchDeadline's deadline is what caused the cancellation.
+var errFetchDeadlineExceeded = &HFError{StatusCode: http.StatusGatewayTimeout, Message: "huggingface fetch deadline exceeded"}
+
 // hfModelResponse is the subset of the HuggingFace /api/models response we need.
 type hfModelResponse struct {
 	Safetensors *struct {
@@ -52,20 +202,102 @@ type hfConfigJSON struct {
 	NSharedExperts      int `json:"n_shared_experts"`
 	MoeIntermediateSize int `json:"moe_intermediate_size"`
 	FirstKDenseReplace  int `json:"first_k_dense_replace"`
+	NumExpertsPerTok    int `json:"num_experts_per_tok"`
 	// Mixtral-style MoE
 	NumLocalExperts int `json:"num_local_experts"`
+
+	// TieWordEmbeddings, when true, means the input embedding and LM head
+	// share one matrix (Gemma, GPT-2, many small models) rather than two
+	// separate ones.
+	TieWordEmbeddings bool `json:"tie_word_embeddings"`
+	// UseParallelResidual selects GPT-NeoX's parallel attention+FFN residual
+	// stream. It doesn't change the parameter count (the same matrices
+	// exist either way, just combined differently), so estimateParameterCount
+	// doesn't read it; it's captured here for completeness alongside the
+	// other architecture fields.
+	UseParallelResidual bool `json:"use_parallel_residual"`
+	// Architectures is HuggingFace's class-name list (e.g.
+	// ["LlamaForCausalLM"]), used as a fallback architecture-family signal
+	// when ModelType alone is ambiguous or missing.
+	Architectures []string `json:"architectures"`
+
+	// QuantizationConfig is present on AWQ/GPTQ checkpoints (absent on
+	// full-precision ones).
+	QuantizationConfig *hfQuantizationConfig `json:"quantization_config"`
+}
+
+// hfQuantizationConfig is the subset of config.json's quantization_config
+// block produced by AWQ and GPTQ quantizers.
+type hfQuantizationConfig struct {
+	QuantMethod string `json:"quant_method"`
+	Bits        int    `json:"bits"`
+	GroupSize   int    `json:"group_size"`
+}
+
+// quantizationLabel builds a ModelConfig.Quantization label like "awq-int4"
+// or "gptq-int4-g128" from config.json's quantization_config block, or ""
+// if the checkpoint isn't pre-quantized.
+func quantizationLabel(q *hfQuantizationConfig) string {
+	if q == nil || q.QuantMethod == "" {
+		return ""
+	}
+	bits := q.Bits
+	if bits == 0 {
+		bits = 4 // AWQ/GPTQ configs often omit bits when it's the default 4
+	}
+	label := fmt.Sprintf("%s-int%d", strings.ToLower(q.QuantMethod), bits)
+	if q.GroupSize > 0 {
+		label += fmt.Sprintf("-g%d", q.GroupSize)
+	}
+	return label
 }
 
-// FetchModelConfig fetches model metadata from HuggingFace and returns a
-// ModelConfig. It makes two parallel requests: one for safetensors metadata
-// and one for config.json.
+// moeExpertCount returns the number of routed experts per layer, supporting
+// both DeepSeek's and Mixtral's field names for the same concept.
+func moeExpertCount(cfg *hfConfigJSON) int {
+	if cfg.NRoutedExperts > 0 {
+		return cfg.NRoutedExperts
+	}
+	return cfg.NumLocalExperts
+}
+
+// FetchModelConfig is FetchModelConfigContext with context.Background(), for
+// callers that don't need cancellation.
 func (c *HFClient) FetchModelConfig(modelID, hfToken string) (*ModelConfig, error) {
+	return c.FetchModelConfigContext(context.Background(), modelID, hfToken)
+}
+
+// FetchModelConfigContext fetches model metadata from HuggingFace and
+// returns a ModelConfig. If a ModelConfig for modelID was previously
+// resolved and cached (see HFClient.Cache) within its TTL, it's returned
+// directly, skipping both requests and the parameter-count estimate
+// entirely. Otherwise it makes two parallel requests — one for safetensors
+// metadata, one for config.json, each individually ETag-revalidated against
+// Cache — both derived from ctx, so canceling ctx (e.g. cobra's
+// cmd.Context() on Ctrl-C) aborts both. A deadline set with
+// SetFetchDeadline applies across both requests independently of ctx; if it
+// fires first, both are canceled and a single errFetchDeadlineExceeded is
+// returned regardless of which request noticed first.
+func (c *HFClient) FetchModelConfigContext(ctx context.Context, modelID, hfToken string) (*ModelConfig, error) {
+	cacheKey := cacheModelKey(modelID, hfToken)
+	if cfg, ok := c.cache().GetModelConfig(cacheKey, hfMainRevision); ok {
+		return cfg, nil
+	}
+
 	type result struct {
 		model  *hfModelResponse
 		config *hfConfigJSON
 		err    error
 	}
 
+	modelCtx, cancelModel := context.WithCancel(ctx)
+	configCtx, cancelConfig := context.WithCancel(ctx)
+	defer cancelModel()
+	defer cancelConfig()
+
+	go watchDeadline(&c.deadline, modelDirection, modelCtx, cancelModel)
+	go watchDeadline(&c.deadline, configDirection, configCtx, cancelConfig)
+
 	modelCh := make(chan result, 1)
 	configCh := make(chan result, 1)
 
@@ -73,7 +305,11 @@ func (c *HFClient) FetchModelConfig(modelID, hfToken string) (*ModelConfig, erro
 	go func() {
 		url := fmt.Sprintf("%s/api/models/%s?expand[]=safetensors", c.baseURL, modelID)
 		var resp hfModelResponse
-		if err := c.doGet(url, hfToken, &resp); err != nil {
+		if _, err := c.doGet(modelCtx, cacheKey, hfMainRevision, "model-info", url, hfToken, &resp); err != nil {
+			if c.deadline.exceeded(modelDirection) {
+				modelCh <- result{err: errFetchDeadlineExceeded}
+				return
+			}
 			modelCh <- result{err: fmt.Errorf("fetch model info: %w", err)}
 			return
 		}
@@ -84,7 +320,11 @@ func (c *HFClient) FetchModelConfig(modelID, hfToken string) (*ModelConfig, erro
 	go func() {
 		url := fmt.Sprintf("%s/%s/resolve/main/config.json", c.baseURL, modelID)
 		var cfg hfConfigJSON
-		if err := c.doGet(url, hfToken, &cfg); err != nil {
+		if _, err := c.doGet(configCtx, cacheKey, hfMainRevision, "config.json", url, hfToken, &cfg); err != nil {
+			if c.deadline.exceeded(configDirection) {
+				configCh <- result{err: errFetchDeadlineExceeded}
+				return
+			}
 			configCh <- result{err: fmt.Errorf("fetch config.json: %w", err)}
 			return
 		}
@@ -105,6 +345,12 @@ func (c *HFClient) FetchModelConfig(modelID, hfToken string) (*ModelConfig, erro
 				Message:    "This model is gated on HuggingFace. Provide an HF token with access above and try again.",
 			}
 		}
+		// No config.json usually means this isn't a standard transformers
+		// repo — check for a GGUF checkpoint before giving up.
+		if qcfg, qerr := c.resolveGGUFConfig(ctx, cacheKey, modelID, hfToken); qerr == nil {
+			_ = c.cache().PutModelConfig(cacheKey, hfMainRevision, qcfg)
+			return qcfg, nil
+		}
 		return nil, cr.err
 	}
 
@@ -116,6 +362,16 @@ func (c *HFClient) FetchModelConfig(modelID, hfToken string) (*ModelConfig, erro
 		MaxPositionEmbeddings: cr.config.MaxPositionEmbeddings,
 		TorchDtype:            cr.config.TorchDtype,
 		ModelType:             cr.config.ModelType,
+		Quantization:          quantizationLabel(cr.config.QuantizationConfig),
+	}
+
+	if numExperts := moeExpertCount(cr.config); numExperts > 0 {
+		cfg.NumLocalExperts = numExperts
+		cfg.NumExpertsPerToken = cr.config.NumExpertsPerTok
+		cfg.MoEIntermediateSize = cr.config.MoeIntermediateSize
+		if cfg.MoEIntermediateSize == 0 {
+			cfg.MoEIntermediateSize = cr.config.IntermediateSize // Mixtral uses intermediate_size for experts
+		}
 	}
 
 	if mr.model.Safetensors != nil && mr.model.Safetensors.Total > 0 {
@@ -135,40 +391,236 @@ func (c *HFClient) FetchModelConfig(modelID, hfToken string) (*ModelConfig, erro
 		cfg.NumKeyValueHeads = cfg.NumAttentionHeads
 	}
 
+	bytesOnDiskDtype := cfg.Quantization
+	if bytesOnDiskDtype == "" {
+		bytesOnDiskDtype = cfg.TorchDtype
+	}
+	cfg.BytesOnDisk = int64(ModelMemoryBytes(cfg.ParameterCount, bytesOnDiskDtype))
+
+	_ = c.cache().PutModelConfig(cacheKey, hfMainRevision, cfg)
 	return cfg, nil
 }
 
-func (c *HFClient) doGet(url, hfToken string, out any) error {
-	req, err := http.NewRequest("GET", url, nil)
+// hfTreeEntry is one entry of the /api/models/{id}/tree/{revision} response
+// — a flat listing of every file in the repo.
+type hfTreeEntry struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// ggufHeaderFetchBytes bounds how much of a .gguf file is downloaded to
+// read its header and tensor list — enough for models with many thousands
+// of tensors and large tokenizer vocabularies, without ever touching the
+// (often many-GiB) tensor data that follows it. If a file's metadata and
+// tensor list together exceed this, parseGGUFHeader hits EOF and
+// resolveGGUFConfig errors out rather than guessing from a truncated read.
+const ggufHeaderFetchBytes = 8 << 20 // 8MiB
+
+// resolveGGUFConfig is the fallback used when a model has no config.json —
+// true for llama.cpp-style GGUF repos, which ship only .gguf files. It
+// lists the repo's file tree, and if a .gguf file is present, parses its
+// header directly for architecture, parameter count, and on-disk size
+// instead of the config.json-based estimate the normal path uses. Only the
+// first .gguf file found is used — multi-part repos (model-00001-of-0000N)
+// aren't merged, so ParameterCount/BytesOnDisk will undercount for those.
+func (c *HFClient) resolveGGUFConfig(ctx context.Context, cacheKey, modelID, hfToken string) (*ModelConfig, error) {
+	var tree []hfTreeEntry
+	treeURL := fmt.Sprintf("%s/api/models/%s/tree/%s", c.baseURL, modelID, hfMainRevision)
+	if _, err := c.doGet(ctx, cacheKey, hfMainRevision, "tree", treeURL, hfToken, &tree); err != nil {
+		return nil, fmt.Errorf("list repo tree: %w", err)
+	}
+
+	var ggufPath string
+	for _, e := range tree {
+		if strings.HasSuffix(e.Path, ".gguf") {
+			ggufPath = e.Path
+			break
+		}
+	}
+	if ggufPath == "" {
+		return nil, fmt.Errorf("no .gguf file found in repo tree")
+	}
+
+	header, err := c.fetchGGUFHeader(ctx, modelID, ggufPath, hfToken)
+	if err != nil {
+		return nil, fmt.Errorf("fetch gguf header: %w", err)
+	}
+
+	meta, err := parseGGUFHeader(bytes.NewReader(header))
+	if err != nil {
+		return nil, fmt.Errorf("parse gguf header: %w", err)
+	}
+
+	cfg := &ModelConfig{
+		ParameterCount:        meta.ParameterCount,
+		BytesOnDisk:           meta.BytesOnDisk,
+		ModelType:             meta.Architecture,
+		Quantization:          meta.QuantizationLabel,
+		HiddenSize:            meta.HiddenSize,
+		NumAttentionHeads:     meta.NumAttentionHeads,
+		NumKeyValueHeads:      meta.NumKeyValueHeads,
+		NumHiddenLayers:       meta.NumHiddenLayers,
+		MaxPositionEmbeddings: meta.MaxPositionEmbeddings,
+	}
+	// Non-GQA GGUF files often omit attention.head_count_kv entirely,
+	// matching config.json's omission of num_key_value_heads for the same
+	// case (see the same defaulting below FetchModelConfigContext's normal
+	// path).
+	if cfg.NumKeyValueHeads == 0 {
+		cfg.NumKeyValueHeads = cfg.NumAttentionHeads
+	}
+	return cfg, nil
+}
+
+// fetchGGUFHeader downloads the first ggufHeaderFetchBytes of path from
+// modelID's repo via an HTTP Range request, since the GGUF header we need
+// sits at the front of the file and the tensor data after it can be
+// arbitrarily large.
+func (c *HFClient) fetchGGUFHeader(ctx context.Context, modelID, path, hfToken string) ([]byte, error) {
+	url := fmt.Sprintf("%s/%s/resolve/%s/%s", c.baseURL, modelID, hfMainRevision, path)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if hfToken != "" {
 		req.Header.Set("Authorization", "Bearer "+hfToken)
 	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", ggufHeaderFetchBytes-1))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, &HFError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, ggufHeaderFetchBytes))
+}
+
+// watchDeadline cancels cancel as soon as direction's cancel channel closes
+// (the wall-clock deadline fired) or ctx is done for any other reason. If
+// SetFetchDeadline replaces the channels while this is waiting, changed
+// wakes it up to re-snapshot and keep watching the new deadline, so a
+// deadline set after the fetch already started still takes effect.
+func watchDeadline(d *deadlineTimer, direction fetchDirection, ctx context.Context, cancel context.CancelFunc) {
+	for {
+		model, config, changed := d.snapshot()
+		cancelCh := model
+		if direction == configDirection {
+			cancelCh = config
+		}
+		select {
+		case <-cancelCh:
+			cancel()
+			return
+		case <-ctx.Done():
+			return
+		case <-changed:
+		}
+	}
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
 
+// doGet fetches url and decodes its JSON body into out, consulting
+// c.cache() for (modelID, revision, path) first: if an entry exists, its
+// ETag is sent as If-None-Match, and a 304 response is decoded from the
+// cached body instead of a fresh one (the entry's TTL still bounds how long
+// it's trusted enough to revalidate against — this never serves cached
+// data without checking the server first). hit reports whether the 304
+// path was taken.
+func (c *HFClient) doGet(ctx context.Context, modelID, revision, path, url, hfToken string, out any) (hit bool, err error) {
+	cache := c.cache()
+	cachedBody, cachedETag, cached := cache.Get(modelID, revision, path)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, err
+	}
+	if hfToken != "" {
+		req.Header.Set("Authorization", "Bearer "+hfToken)
+	}
+	if cached {
+		req.Header.Set("If-None-Match", cachedETag)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached {
+		return true, json.Unmarshal(cachedBody, out)
+	}
 	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
-		return &HFError{StatusCode: resp.StatusCode, Message: "model is gated — provide an HF token with access"}
+		return false, &HFError{StatusCode: resp.StatusCode, Message: "model is gated — provide an HF token with access"}
 	}
 	if resp.StatusCode == http.StatusNotFound {
 		msg := "Model not found on HuggingFace."
 		if hfToken == "" {
 			msg += " If this is a private or gated model, provide an HF token above and try again."
 		}
-		return &HFError{StatusCode: resp.StatusCode, Message: msg}
+		return false, &HFError{StatusCode: resp.StatusCode, Message: msg}
 	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
-		return &HFError{StatusCode: resp.StatusCode, Message: string(body)}
+		return false, &HFError{StatusCode: resp.StatusCode, Message: string(body)}
 	}
 
-	return json.NewDecoder(resp.Body).Decode(out)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("read response body: %w", err)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return false, fmt.Errorf("decode response body: %w", err)
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		_ = cache.Put(modelID, revision, path, body, etag)
+	}
+	return false, nil
+}
+
+// ffnFamily distinguishes the two FFN shapes estimateParameterCount knows
+// how to size. gatedFFN is SwiGLU-style (gate + up + down, 3 matrices):
+// Llama, Mistral, Qwen2, Gemma/Gemma2, Command-R/Cohere, Phi-3, Mixtral.
+// plainFFN has no gate projection, just up + down (2 matrices): GPT-NeoX,
+// Falcon, BLOOM.
+type ffnFamily int
+
+const (
+	gatedFFN ffnFamily = iota // 3 matrices: gate, up, down
+	plainFFN                  // 2 matrices: up, down
+)
+
+// detectFFNFamily inspects model_type and, failing that, architectures[]
+// (HuggingFace's class-name list) to decide which FFN shape a model uses.
+// Unrecognized architectures default to gatedFFN, matching the estimate's
+// pre-existing behavior before this distinction was added.
+func detectFFNFamily(cfg *hfConfigJSON) ffnFamily {
+	candidates := make([]string, 0, 1+len(cfg.Architectures))
+	if cfg.ModelType != "" {
+		candidates = append(candidates, cfg.ModelType)
+	}
+	candidates = append(candidates, cfg.Architectures...)
+
+	for _, c := range candidates {
+		c = strings.ToLower(c)
+		switch {
+		case strings.Contains(c, "falcon"), strings.Contains(c, "gpt_neox"), strings.Contains(c, "gptneox"), strings.Contains(c, "bloom"):
+			return plainFFN
+		}
+	}
+	return gatedFFN
 }
 
 // estimateParameterCount estimates total parameters from architecture fields
@@ -184,29 +636,50 @@ func estimateParameterCount(cfg *hfConfigJSON) int64 {
 	vocab := int64(cfg.VocabSize)
 	interSize := int64(cfg.IntermediateSize)
 
-	// Embeddings + LM head.
+	// Embeddings + LM head, or just embeddings if the two are tied
+	// (Gemma, GPT-2, Command-R, and many smaller models).
 	var total int64
 	if vocab > 0 {
-		total += 2 * vocab * h
+		if cfg.TieWordEmbeddings {
+			total += vocab * h
+		} else {
+			total += 2 * vocab * h
+		}
 	}
 
-	// Per-layer attention: Q, K, V, O projections ≈ 4 × hidden_size².
-	attnPerLayer := 4 * h * h
+	// Per-layer attention: Q and O project the full hidden_size, but K and V
+	// only project num_key_value_heads worth of it under GQA/MQA (Mistral,
+	// Qwen2, Gemma2, Llama 3, ...) — a plain 4×h² assumes K/V are full-size
+	// (num_key_value_heads == num_attention_heads), which overcounts
+	// GQA/MQA models substantially.
+	kvHeads, qHeads := int64(cfg.NumKeyValueHeads), int64(cfg.NumAttentionHeads)
+	if kvHeads == 0 {
+		kvHeads = qHeads
+	}
+	var attnPerLayer int64
+	if qHeads > 0 && kvHeads > 0 && kvHeads < qHeads {
+		kvDim := h * kvHeads / qHeads
+		attnPerLayer = 2*h*h + 2*h*kvDim // Q, O full-size; K, V reduced
+	} else {
+		attnPerLayer = 4 * h * h
+	}
 
 	// Per-layer layer norms (small).
 	normPerLayer := 2 * h
 
 	// Determine number of MoE experts (support both DeepSeek and Mixtral field names).
-	numExperts := cfg.NRoutedExperts
-	if numExperts == 0 {
-		numExperts = cfg.NumLocalExperts
-	}
+	numExperts := moeExpertCount(cfg)
 
 	moeInterSize := int64(cfg.MoeIntermediateSize)
 	if moeInterSize == 0 {
 		moeInterSize = interSize // Mixtral uses intermediate_size for experts
 	}
 
+	ffnMatrices := int64(3) // gated: gate + up + down
+	if detectFFNFamily(cfg) == plainFFN {
+		ffnMatrices = 2 // plain: up + down, no gate
+	}
+
 	if numExperts > 0 && moeInterSize > 0 {
 		// MoE model. Some layers may be dense (first_k_dense_replace).
 		denseLayers := int64(cfg.FirstKDenseReplace)
@@ -215,19 +688,19 @@ func estimateParameterCount(cfg *hfConfigJSON) int64 {
 			moeLayers = layers
 		}
 
-		// Dense FFN: gate + up + down = 3 × h × intermediate_size
-		denseFFN := int64(3) * h * interSize
+		// Dense FFN.
+		denseFFN := ffnMatrices * h * interSize
 
-		// MoE FFN: routed experts + shared experts
-		routedFFN := int64(numExperts) * 3 * h * moeInterSize
-		sharedFFN := int64(cfg.NSharedExperts) * 3 * h * interSize
+		// MoE FFN: routed experts + shared experts.
+		routedFFN := int64(numExperts) * ffnMatrices * h * moeInterSize
+		sharedFFN := int64(cfg.NSharedExperts) * ffnMatrices * h * interSize
 		moeFFN := routedFFN + sharedFFN
 
 		total += denseLayers * (attnPerLayer + denseFFN + normPerLayer)
 		total += moeLayers * (attnPerLayer + moeFFN + normPerLayer)
 	} else if interSize > 0 {
-		// Dense model: gate + up + down = 3 × h × intermediate_size
-		ffnPerLayer := int64(3) * h * interSize
+		// Dense model.
+		ffnPerLayer := ffnMatrices * h * interSize
 		total += layers * (attnPerLayer + ffnPerLayer + normPerLayer)
 	} else {
 		// No intermediate_size — rough estimate: ~12 × h² per layer.
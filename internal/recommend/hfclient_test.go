@@ -0,0 +1,300 @@
+package recommend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestHFClient(handler http.HandlerFunc) (*HFClient, *httptest.Server) {
+	srv := httptest.NewServer(handler)
+	c := NewHFClient()
+	c.baseURL = srv.URL
+	c.Cache = noopCache{} // tests that want caching behavior set up their own diskCache under t.TempDir()
+	return c, srv
+}
+
+func TestFetchModelConfigContext_CancelAbortsBothFetches(t *testing.T) {
+	started := make(chan struct{}, 2)
+	c, srv := newTestHFClient(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-r.Context().Done()
+	})
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.FetchModelConfigContext(ctx, "some/model", "")
+		done <- err
+	}()
+
+	<-started
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected an error after cancellation, got nil")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("FetchModelConfigContext did not return after ctx was canceled")
+	}
+}
+
+func TestSetFetchDeadline_CancelsInFlightFetches(t *testing.T) {
+	c, srv := newTestHFClient(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	defer srv.Close()
+
+	c.SetFetchDeadline(time.Now().Add(20 * time.Millisecond))
+
+	_, err := c.FetchModelConfigContext(context.Background(), "some/model", "")
+	if err == nil {
+		t.Fatal("expected errFetchDeadlineExceeded, got nil")
+	}
+	if !strings.Contains(err.Error(), "fetch deadline exceeded") {
+		t.Errorf("expected fetch deadline error, got: %v", err)
+	}
+}
+
+func TestSetFetchDeadline_SetMidFlightStillCancels(t *testing.T) {
+	started := make(chan struct{}, 2)
+	c, srv := newTestHFClient(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-r.Context().Done()
+	})
+	defer srv.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.FetchModelConfigContext(context.Background(), "some/model", "")
+		done <- err
+	}()
+
+	<-started
+	<-started
+	c.SetFetchDeadline(time.Now().Add(20 * time.Millisecond))
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "fetch deadline exceeded") {
+			t.Fatalf("expected fetch deadline error, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("a deadline set after the fetch started was never observed")
+	}
+}
+
+func TestDoGet_FreshRequestStoresBodyAndETag(t *testing.T) {
+	c, srv := newTestHFClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Error("expected no If-None-Match on a first request")
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"hidden_size":4096}`))
+	})
+	defer srv.Close()
+	c.Cache = newTestDiskCache(t)
+
+	var cfg hfConfigJSON
+	hit, err := c.doGet(context.Background(), "some/model", hfMainRevision, "config.json", srv.URL, "", &cfg)
+	if err != nil {
+		t.Fatalf("doGet: %v", err)
+	}
+	if hit {
+		t.Error("expected hit=false on a first request")
+	}
+	if cfg.HiddenSize != 4096 {
+		t.Errorf("HiddenSize = %d, want 4096", cfg.HiddenSize)
+	}
+
+	body, etag, ok := c.Cache.Get("some/model", hfMainRevision, "config.json")
+	if !ok {
+		t.Fatal("expected the response to be cached")
+	}
+	if etag != `"v1"` {
+		t.Errorf("cached etag = %s, want \"v1\"", etag)
+	}
+	if string(body) != `{"hidden_size":4096}` {
+		t.Errorf("cached body = %s", body)
+	}
+}
+
+func TestDoGet_RevalidatesWithIfNoneMatchAnd304(t *testing.T) {
+	var requests int32
+	c, srv := newTestHFClient(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("If-None-Match") != `"v1"` {
+			t.Errorf("If-None-Match = %q, want \"v1\"", r.Header.Get("If-None-Match"))
+		}
+		w.WriteHeader(http.StatusNotModified)
+	})
+	defer srv.Close()
+	c.Cache = newTestDiskCache(t)
+	c.Cache.Put("some/model", hfMainRevision, "config.json", []byte(`{"hidden_size":4096}`), `"v1"`)
+
+	var cfg hfConfigJSON
+	hit, err := c.doGet(context.Background(), "some/model", hfMainRevision, "config.json", srv.URL, "", &cfg)
+	if err != nil {
+		t.Fatalf("doGet: %v", err)
+	}
+	if !hit {
+		t.Error("expected hit=true on a 304 response")
+	}
+	if cfg.HiddenSize != 4096 {
+		t.Errorf("HiddenSize = %d, want 4096 (decoded from cached body)", cfg.HiddenSize)
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("expected exactly one request to the server, got %d", requests)
+	}
+}
+
+func TestDoGet_NoCache_SkipsIfNoneMatchAndCaching(t *testing.T) {
+	c, srv := newTestHFClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Error("expected NoCache to suppress If-None-Match")
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"hidden_size":4096}`))
+	})
+	defer srv.Close()
+	disk := newTestDiskCache(t)
+	c.Cache = disk
+	c.NoCache = true
+
+	var cfg hfConfigJSON
+	if _, err := c.doGet(context.Background(), "some/model", hfMainRevision, "config.json", srv.URL, "", &cfg); err != nil {
+		t.Fatalf("doGet: %v", err)
+	}
+
+	if _, _, ok := disk.Get("some/model", hfMainRevision, "config.json"); ok {
+		t.Error("expected NoCache to prevent the response from being cached")
+	}
+}
+
+func TestFetchModelConfigContext_CachedModelConfigSkipsNetwork(t *testing.T) {
+	c, srv := newTestHFClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected a cached ModelConfig to skip the network entirely")
+	})
+	defer srv.Close()
+	c.Cache = newTestDiskCache(t)
+
+	want := &ModelConfig{HiddenSize: 4096, NumHiddenLayers: 32, ParameterCount: 7_000_000_000}
+	if err := c.Cache.PutModelConfig("some/model", hfMainRevision, want); err != nil {
+		t.Fatalf("PutModelConfig: %v", err)
+	}
+
+	got, err := c.FetchModelConfigContext(context.Background(), "some/model", "")
+	if err != nil {
+		t.Fatalf("FetchModelConfigContext: %v", err)
+	}
+	if got.ParameterCount != want.ParameterCount {
+		t.Errorf("ParameterCount = %d, want %d", got.ParameterCount, want.ParameterCount)
+	}
+}
+
+func TestCacheModelKey_DiffersByToken(t *testing.T) {
+	noToken := cacheModelKey("meta-llama/Llama-2-7b", "")
+	tokenA := cacheModelKey("meta-llama/Llama-2-7b", "token-a")
+	tokenB := cacheModelKey("meta-llama/Llama-2-7b", "token-b")
+
+	if noToken == tokenA || noToken == tokenB || tokenA == tokenB {
+		t.Fatalf("expected distinct cache keys per token, got %q, %q, %q", noToken, tokenA, tokenB)
+	}
+	if strings.Contains(tokenA, "token-a") {
+		t.Error("cache key must not contain the raw token")
+	}
+}
+
+func TestFetchModelConfigContext_GatedModelNotServedToDifferentToken(t *testing.T) {
+	c, srv := newTestHFClient(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected a cache miss under a different token to still skip the network only via its own key, not hit another token's entry")
+	})
+	defer srv.Close()
+	c.Cache = newTestDiskCache(t)
+
+	gated := &ModelConfig{HiddenSize: 4096, ParameterCount: 7_000_000_000}
+	if err := c.Cache.PutModelConfig(cacheModelKey("meta-llama/Llama-2-7b", "authorized-token"), hfMainRevision, gated); err != nil {
+		t.Fatalf("PutModelConfig: %v", err)
+	}
+
+	if _, ok := c.cache().GetModelConfig(cacheModelKey("meta-llama/Llama-2-7b", ""), hfMainRevision); ok {
+		t.Fatal("expected a caller with no token to miss a ModelConfig cached under a different token")
+	}
+	if _, ok := c.cache().GetModelConfig(cacheModelKey("meta-llama/Llama-2-7b", "other-token"), hfMainRevision); ok {
+		t.Fatal("expected a caller with a different token to miss a ModelConfig cached under the authorized token")
+	}
+}
+
+func TestFetchModelConfigContext_FallsBackToGGUFWhenConfigJSONMissing(t *testing.T) {
+	var gw ggufWriter
+	gw.buf.WriteString("GGUF")
+	gw.u32(3)
+	gw.u64(1)
+	gw.u64(5)
+	gw.kvString("general.architecture", "llama")
+	gw.kvInt32("general.file_type", 2) // q4_0
+	gw.kvInt32("llama.attention.head_count", 32)
+	gw.kvInt32("llama.attention.head_count_kv", 8)
+	gw.kvInt32("llama.embedding_length", 4096)
+	gw.tensor("token_embd.weight", []uint64{4096, 32000}, 1)
+	ggufBody := gw.buf.Bytes()
+
+	c, srv := newTestHFClient(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/api/models/") && strings.Contains(r.URL.Path, "/tree/"):
+			w.Write([]byte(`[{"path":"model.q4_0.gguf","size":1234}]`))
+		case strings.Contains(r.URL.Path, "/resolve/") && strings.HasSuffix(r.URL.Path, ".gguf"):
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(ggufBody)
+		case strings.Contains(r.URL.Path, "config.json"):
+			http.NotFound(w, r)
+		case strings.Contains(r.URL.Path, "/api/models/"):
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer srv.Close()
+	c.Cache = noopCache{}
+
+	got, err := c.FetchModelConfigContext(context.Background(), "TheBloke/some-GGUF", "")
+	if err != nil {
+		t.Fatalf("FetchModelConfigContext: %v", err)
+	}
+	if got.ModelType != "llama" {
+		t.Errorf("ModelType = %q, want llama", got.ModelType)
+	}
+	if got.Quantization != "q4_0" {
+		t.Errorf("Quantization = %q, want q4_0", got.Quantization)
+	}
+	wantParams := int64(4096 * 32000)
+	if got.ParameterCount != wantParams {
+		t.Errorf("ParameterCount = %d, want %d", got.ParameterCount, wantParams)
+	}
+	if got.NumAttentionHeads != 32 || got.NumKeyValueHeads != 8 || got.HiddenSize != 4096 {
+		t.Errorf("attention dims = (heads=%d, kv_heads=%d, hidden=%d), want (32, 8, 4096)",
+			got.NumAttentionHeads, got.NumKeyValueHeads, got.HiddenSize)
+	}
+}
+
+func TestSetFetchDeadline_Zero_ClearsDeadline(t *testing.T) {
+	c, srv := newTestHFClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	defer srv.Close()
+
+	c.SetFetchDeadline(time.Now().Add(time.Hour))
+	c.SetFetchDeadline(time.Time{})
+
+	if _, err := c.FetchModelConfigContext(context.Background(), "some/model", ""); err != nil {
+		t.Fatalf("expected no error with cleared deadline, got: %v", err)
+	}
+}
@@ -9,16 +9,34 @@ import (
 )
 
 // ModelConfig holds architecture metadata fetched from HuggingFace.
+//
+// The MoE fields (NumLocalExperts, NumExpertsPerToken, MoEIntermediateSize)
+// are zero for dense transformers; Recommend treats NumLocalExperts == 0 as
+// the signal that a model is dense.
 type ModelConfig struct {
-	ParameterCount        int64   `json:"parameter_count"`
-	HiddenSize            int     `json:"hidden_size"`
-	NumAttentionHeads     int     `json:"num_attention_heads"`
-	NumKeyValueHeads      int     `json:"num_key_value_heads"`
-	NumHiddenLayers       int     `json:"num_hidden_layers"`
-	MaxPositionEmbeddings int     `json:"max_position_embeddings"`
-	TorchDtype            string  `json:"torch_dtype"`
-	ModelType             string  `json:"model_type"`
-	Architecture          string  `json:"architecture"`
+	ParameterCount        int64  `json:"parameter_count"`
+	HiddenSize            int    `json:"hidden_size"`
+	NumAttentionHeads     int    `json:"num_attention_heads"`
+	NumKeyValueHeads      int    `json:"num_key_value_heads"`
+	NumHiddenLayers       int    `json:"num_hidden_layers"`
+	MaxPositionEmbeddings int    `json:"max_position_embeddings"`
+	TorchDtype            string `json:"torch_dtype"`
+	ModelType             string `json:"model_type"`
+	Architecture          string `json:"architecture"`
+
+	NumLocalExperts     int `json:"num_local_experts,omitempty"`
+	NumExpertsPerToken  int `json:"num_experts_per_token,omitempty"`
+	MoEIntermediateSize int `json:"moe_intermediate_size,omitempty"`
+
+	// Quantization is set when the checkpoint itself is pre-quantized (a
+	// GGUF file, or a config.json with an AWQ/GPTQ quantization_config) —
+	// e.g. "q4_k_m", "awq-int4", "gptq-int4-g128". Empty means the
+	// checkpoint is full-precision, in whatever TorchDtype says.
+	Quantization string `json:"quantization,omitempty"`
+	// BytesOnDisk is the tensor data size reported by the checkpoint
+	// itself (GGUF's tensor list), when known, rather than derived from
+	// ParameterCount and an assumed dtype.
+	BytesOnDisk int64 `json:"bytes_on_disk,omitempty"`
 }
 
 // InstanceSpec holds GPU specs from the instance_types DB table.
@@ -39,8 +57,13 @@ type Recommendation struct {
 	InputSequenceLength  int     `json:"input_sequence_length"`
 	OutputSequenceLength int     `json:"output_sequence_length"`
 
-	Explanation Explanation `json:"explanation"`
-	ModelInfo   ModelInfo   `json:"model_info"`
+	// PipelineParallelDegree and ExpertParallelDegree are 1 for dense
+	// models; MoE models may get pp > 1 and/or ep > 1 alongside TP.
+	PipelineParallelDegree int `json:"pipeline_parallel_degree"`
+	ExpertParallelDegree   int `json:"expert_parallel_degree"`
+
+	Explanation  Explanation  `json:"explanation"`
+	ModelInfo    ModelInfo    `json:"model_info"`
 	InstanceInfo InstanceInfo `json:"instance_info"`
 
 	// Alternatives is non-nil when the model doesn't fit at native precision.
@@ -56,6 +79,11 @@ type Explanation struct {
 	Feasible             bool   `json:"feasible"`
 	Reason               string `json:"reason,omitempty"`
 	SuggestedInstance    string `json:"suggested_instance,omitempty"`
+
+	// PipelineParallelDegree and ExpertParallelDegree are only populated
+	// for MoE models (see Recommendation.ExpertParallelDegree).
+	PipelineParallelDegree string `json:"pipeline_parallel_degree,omitempty"`
+	ExpertParallelDegree   string `json:"expert_parallel_degree,omitempty"`
 }
 
 // ModelInfo summarizes the model metadata in the response.
@@ -92,22 +120,50 @@ const (
 
 // bytesPerParam returns the bytes per parameter for a given dtype/quantization.
 func bytesPerParam(quant string) float64 {
-	switch quant {
-	case "fp32":
+	switch {
+	case quant == "fp32":
 		return 4
-	case "", "fp16", "bfloat16":
+	case quant == "" || quant == "fp16" || quant == "bfloat16" || quant == "f16" || quant == "bf16":
 		return 2
-	case "fp8", "int8":
+	case quant == "fp8" || quant == "int8":
 		return 1
-	case "int4":
+	case quant == "int4":
+		return 0.5
+	case strings.Contains(quant, "int4"):
+		// AWQ/GPTQ quantization labels, e.g. "awq-int4", "gptq-int4-g128".
 		return 0.5
+	case strings.Contains(quant, "int8"):
+		return 1
+	case strings.HasPrefix(quant, "q"):
+		// GGUF quantization labels (q4_k_m, q5_k_s, q8_0, ...) — approximate
+		// with the same per-weight-bit-width family bytesPerParam already
+		// uses for int4/int8, since the k-quants cluster around those bit
+		// widths even though their exact block sizes vary.
+		switch {
+		case strings.HasPrefix(quant, "q2") || strings.HasPrefix(quant, "q3") || strings.HasPrefix(quant, "q4"):
+			return 0.5
+		case strings.HasPrefix(quant, "q5") || strings.HasPrefix(quant, "q6"):
+			return 0.75
+		case strings.HasPrefix(quant, "q8"):
+			return 1
+		default:
+			return 0.5
+		}
 	default:
 		return 2 // assume FP16
 	}
 }
 
-// supportsFP8 returns true if the GPU supports FP8 quantization.
-func supportsFP8(acceleratorName string) bool {
+// supportsFP8 returns true if the instance's accelerator supports FP8
+// quantization. Only specific NVIDIA GPUs have the FP8 tensor cores this
+// needs — AWS Neuron and Google TPU accelerators have no FP8 execution
+// path in their current compiler toolchains (neuronx-cc, the XLA/PJRT
+// stack), and AMD's MI-series FP8 support isn't yet wired into this
+// recommender's quantization options.
+func supportsFP8(acceleratorType, acceleratorName string) bool {
+	if !strings.EqualFold(acceleratorType, "gpu") {
+		return false
+	}
 	switch acceleratorName {
 	case "H100", "H200", "L40S":
 		return true
@@ -115,8 +171,25 @@ func supportsFP8(acceleratorName string) bool {
 	return false
 }
 
-// modelMemoryBytes returns the model weight memory in bytes for a given quantization.
-func modelMemoryBytes(params int64, quant string) float64 {
+// memoryOverheadFraction reserves headroom per accelerator for runtime
+// bookkeeping beyond the model weights themselves — CUDA context and
+// activations on GPU, SBUF/scratch buffers on Neuron and TPU. Neuron and
+// TPU runtimes don't carry a CUDA-sized context, so they get a smaller
+// reservation than GPU's overheadFraction.
+func memoryOverheadFraction(acceleratorType string) float64 {
+	switch strings.ToLower(acceleratorType) {
+	case "neuron", "tpu":
+		return 0.05
+	default:
+		return overheadFraction
+	}
+}
+
+// ModelMemoryBytes returns the model weight memory in bytes for a given
+// quantization. Exported so other packages (e.g. the orchestrator's
+// simulator) can reuse the same weight-memory formula this package sizes
+// recommendations with.
+func ModelMemoryBytes(params int64, quant string) float64 {
 	return float64(params) * bytesPerParam(quant)
 }
 
@@ -129,6 +202,12 @@ func kvCachePerTokenBytes(cfg ModelConfig) float64 {
 
 // nativeDtype returns the native dtype string, defaulting to "bfloat16".
 func nativeDtype(cfg ModelConfig) string {
+	// A pre-quantized checkpoint (GGUF, AWQ, GPTQ) has no full-precision
+	// form to fall back to — its Quantization label is what's actually on
+	// disk, so size against that instead of TorchDtype.
+	if cfg.Quantization != "" {
+		return cfg.Quantization
+	}
 	if cfg.TorchDtype != "" {
 		return cfg.TorchDtype
 	}
@@ -147,6 +226,111 @@ func validTPDegree(minTP, numHeads, numKVHeads, maxGPUs int) int {
 	return maxGPUs
 }
 
+// expertWeightBytes splits a MoE model's weight memory into its dense
+// (attention, embeddings, and anything not modeled as a routed expert) and
+// sparse (routed expert MLP) components, at a given quantization. Resident
+// memory must hold every expert (capacity planning has to assume any expert
+// may be routed to); activated memory reflects only the NumExpertsPerToken
+// of NumLocalExperts actually read on a given forward pass, which is what
+// matters for memory-bandwidth-bound throughput rather than capacity.
+// Dense models (NumLocalExperts == 0) return all weight memory as dense.
+func expertWeightBytes(cfg ModelConfig, quant string) (dense, sparseResident, sparseActivated float64) {
+	if cfg.NumLocalExperts == 0 {
+		return ModelMemoryBytes(cfg.ParameterCount, quant), 0, 0
+	}
+
+	bpp := bytesPerParam(quant)
+	// Routed expert FFN per layer: gate + up + down = 3 × hidden_size × moe_intermediate_size.
+	sparseParams := float64(cfg.NumHiddenLayers) * float64(cfg.NumLocalExperts) * 3 * float64(cfg.HiddenSize) * float64(cfg.MoEIntermediateSize)
+	denseParams := float64(cfg.ParameterCount) - sparseParams
+	if denseParams < 0 {
+		denseParams = 0
+	}
+
+	sparseResident = sparseParams * bpp
+	dense = denseParams * bpp
+	if cfg.NumLocalExperts > 0 {
+		sparseActivated = sparseResident * float64(cfg.NumExpertsPerToken) / float64(cfg.NumLocalExperts)
+	}
+	return dense, sparseResident, sparseActivated
+}
+
+// factorTriples returns every (tp, pp, ep) triple of positive integers whose
+// product is total, i.e. every way to factor total into three parallelism
+// degrees.
+func factorTriples(total int) [][3]int {
+	var triples [][3]int
+	for tp := 1; tp <= total; tp++ {
+		if total%tp != 0 {
+			continue
+		}
+		rem := total / tp
+		for pp := 1; pp <= rem; pp++ {
+			if rem%pp != 0 {
+				continue
+			}
+			triples = append(triples, [3]int{tp, pp, rem / pp})
+		}
+	}
+	return triples
+}
+
+// chooseMoEParallelism picks the (tp, pp, ep) factorization of
+// acceleratorCount that minimizes per-device weight bytes for cfg at quant,
+// subject to tp dividing NumAttentionHeads, pp dividing NumHiddenLayers, and
+// ep dividing NumLocalExperts. Per-device bytes are dense/(tp*pp) +
+// sparseResident/(tp*pp*ep); since tp*pp*ep == acceleratorCount is fixed,
+// the sparse term is constant across candidates and only ep (which shrinks
+// how finely the dense remainder gets sharded) varies the total — smaller ep
+// is generally better, but the search is exhaustive rather than relying on
+// that, since it's cheap and doesn't assume away edge cases.
+func chooseMoEParallelism(cfg ModelConfig, quant string, acceleratorCount int) (tp, pp, ep int, perDeviceBytes float64, ok bool) {
+	dense, sparseResident, _ := expertWeightBytes(cfg, quant)
+	best := math.MaxFloat64
+	for _, t := range factorTriples(acceleratorCount) {
+		ctp, cpp, cep := t[0], t[1], t[2]
+		if cfg.NumAttentionHeads%ctp != 0 || cfg.NumHiddenLayers%cpp != 0 || cfg.NumLocalExperts%cep != 0 {
+			continue
+		}
+		bytes := dense*float64(cep)/float64(acceleratorCount) + sparseResident/float64(acceleratorCount)
+		if bytes < best {
+			best = bytes
+			tp, pp, ep, ok = ctp, cpp, cep, true
+		}
+	}
+	if !ok {
+		return 0, 0, 0, 0, false
+	}
+	return tp, pp, ep, best, true
+}
+
+// fit reports whether cfg's weights fit in inst's total accelerator memory
+// at the given quantization, and the (tp, pp, ep) split to realize it. Dense
+// models keep the original TP-only search; MoE models search the joint
+// (tp, pp, ep) factorization, since expert weights shard differently than
+// the dense remainder (see chooseMoEParallelism).
+func fit(cfg ModelConfig, quant string, inst InstanceSpec, usablePerDevice float64) (fits bool, tp, pp, ep int) {
+	if cfg.NumLocalExperts > 0 {
+		tp, pp, ep, perDevice, ok := chooseMoEParallelism(cfg, quant, inst.AcceleratorCount)
+		if !ok {
+			return false, 0, 0, 0
+		}
+		return perDevice <= usablePerDevice, tp, pp, ep
+	}
+
+	mem := ModelMemoryBytes(cfg.ParameterCount, quant)
+	totalUsable := usablePerDevice * float64(inst.AcceleratorCount)
+	if mem > totalUsable {
+		return false, 0, 1, 1
+	}
+	minGPUs := int(math.Ceil(mem / usablePerDevice))
+	if minGPUs < 1 {
+		minGPUs = 1
+	}
+	tp = validTPDegree(minGPUs, cfg.NumAttentionHeads, cfg.NumKeyValueHeads, inst.AcceleratorCount)
+	return true, tp, 1, 1
+}
+
 // roundDownContext rounds a token count down to the nearest common context length.
 func roundDownContext(tokens int) int {
 	common := []int{131072, 65536, 32768, 16384, 8192, 4096, 2048, 1024, 512}
@@ -164,17 +348,22 @@ func Recommend(cfg ModelConfig, inst InstanceSpec, allInstances []InstanceSpec)
 	dtype := nativeDtype(cfg)
 	perDeviceGiB := float64(inst.AcceleratorMemoryGiB) / float64(inst.AcceleratorCount)
 	perDeviceBytes := perDeviceGiB * gibBytes
-	usablePerDevice := perDeviceBytes * (1 - overheadFraction)
-
-	modelMemNative := modelMemoryBytes(cfg.ParameterCount, dtype)
-	minGPUs := int(math.Ceil(modelMemNative / usablePerDevice))
-	if minGPUs < 1 {
-		minGPUs = 1
+	usablePerDevice := perDeviceBytes * (1 - memoryOverheadFraction(inst.AcceleratorType))
+
+	// BytesOnDisk, when known (GGUF's own tensor list), is the actual
+	// on-disk size of dtype — use it instead of bytesPerParam's bucketed
+	// approximation, which exists for checkpoints whose exact size isn't
+	// otherwise available.
+	modelMemNative := ModelMemoryBytes(cfg.ParameterCount, dtype)
+	if cfg.BytesOnDisk > 0 && dtype == cfg.Quantization {
+		modelMemNative = float64(cfg.BytesOnDisk)
 	}
 
 	rec := &Recommendation{
-		InputSequenceLength:  512,
-		OutputSequenceLength: 256,
+		InputSequenceLength:    512,
+		OutputSequenceLength:   256,
+		PipelineParallelDegree: 1,
+		ExpertParallelDegree:   1,
 		ModelInfo: ModelInfo{
 			ParameterCount:        cfg.ParameterCount,
 			NativeDtype:           dtype,
@@ -188,43 +377,65 @@ func Recommend(cfg ModelConfig, inst InstanceSpec, allInstances []InstanceSpec)
 		},
 	}
 
-	// Determine quantization and TP.
+	// Determine quantization, and the (tp, pp, ep) split that realizes it.
 	var chosenQuant string // "" means native precision
 	totalUsableBytes := usablePerDevice * float64(inst.AcceleratorCount)
+	isMoE := cfg.NumLocalExperts > 0
 
-	if modelMemNative <= totalUsableBytes {
+	fitsNative, nativeTP, nativePP, nativeEP := fit(cfg, dtype, inst, usablePerDevice)
+	if fitsNative {
 		// Fits at native precision.
-		tp := validTPDegree(minGPUs, cfg.NumAttentionHeads, cfg.NumKeyValueHeads, inst.AcceleratorCount)
-		rec.TensorParallelDegree = tp
+		rec.TensorParallelDegree = nativeTP
+		rec.PipelineParallelDegree = nativePP
+		rec.ExpertParallelDegree = nativeEP
 		rec.Quantization = nil
 		chosenQuant = dtype
 		rec.Explanation.Quantization = fmt.Sprintf("Model fits in native %s precision (%.1f GiB weights, %.0f GiB available).",
 			dtype, modelMemNative/gibBytes, totalUsableBytes/gibBytes)
 		rec.Explanation.TensorParallelDegree = fmt.Sprintf("TP=%d: model requires %.1f GiB, each %s has %.0f GiB.",
-			tp, modelMemNative/gibBytes, inst.AcceleratorName, perDeviceGiB)
+			nativeTP, modelMemNative/gibBytes, inst.AcceleratorName, perDeviceGiB)
+		if isMoE {
+			rec.Explanation.PipelineParallelDegree = fmt.Sprintf("PP=%d: %d hidden layers split into %d pipeline stage(s).",
+				nativePP, cfg.NumHiddenLayers, nativePP)
+			rec.Explanation.ExpertParallelDegree = fmt.Sprintf("EP=%d: %d experts (%d active per token) sharded across %d expert-parallel group(s).",
+				nativeEP, cfg.NumLocalExperts, cfg.NumExpertsPerToken, nativeEP)
+		}
 	} else {
 		// Doesn't fit at native precision — try quantization options.
 		rec.Alternatives = &Alternatives{}
 
-		// Try quantization levels in order of preference.
-		quantOptions := []struct {
+		// Try quantization levels in order of preference. A checkpoint
+		// that's already pre-quantized (GGUF, AWQ, GPTQ) has no other
+		// precision available without fetching a different file, so none
+		// of these are real alternatives for it — skip straight to
+		// suggesting a larger instance.
+		var quantOptions []struct {
 			name string
 			ok   bool
-		}{
-			{"fp8", supportsFP8(inst.AcceleratorName)},
-			{"int8", true},
-			{"int4", true},
+		}
+		if cfg.Quantization == "" {
+			quantOptions = []struct {
+				name string
+				ok   bool
+			}{
+				{"fp8", supportsFP8(inst.AcceleratorType, inst.AcceleratorName)},
+				{"int8", true},
+				{"int4", true},
+			}
 		}
 
 		var fitsWithQuant bool
+		var quantTP, quantPP, quantEP int
 		for _, qo := range quantOptions {
 			if !qo.ok {
 				continue
 			}
-			qMem := modelMemoryBytes(cfg.ParameterCount, qo.name)
-			if qMem <= totalUsableBytes {
+			fits, tp, pp, ep := fit(cfg, qo.name, inst, usablePerDevice)
+			if fits {
 				chosenQuant = qo.name
 				fitsWithQuant = true
+				quantTP, quantPP, quantEP = tp, pp, ep
+				qMem := ModelMemoryBytes(cfg.ParameterCount, qo.name)
 				rec.Alternatives.QuantizationOption = &QuantizationOption{
 					Quantization:    qo.name,
 					EstimatedMemGiB: qMem / gibBytes,
@@ -233,13 +444,15 @@ func Recommend(cfg ModelConfig, inst InstanceSpec, allInstances []InstanceSpec)
 			}
 		}
 
-		// Find a larger instance that fits at native precision.
+		// Find a larger instance of the same accelerator family that fits
+		// at native precision — a Neuron deployment doesn't get suggested a
+		// GPU instance, and vice versa.
 		if len(allInstances) > 0 {
 			for _, alt := range allInstances {
-				if !strings.EqualFold(alt.AcceleratorType, "gpu") {
+				if !strings.EqualFold(alt.AcceleratorType, inst.AcceleratorType) {
 					continue
 				}
-				altTotal := float64(alt.AcceleratorMemoryGiB) * gibBytes * (1 - overheadFraction)
+				altTotal := float64(alt.AcceleratorMemoryGiB) * gibBytes * (1 - memoryOverheadFraction(alt.AcceleratorType))
 				if modelMemNative <= altTotal && alt.AcceleratorMemoryGiB > inst.AcceleratorMemoryGiB {
 					rec.Alternatives.LargerInstance = alt.Name
 					break
@@ -250,23 +463,31 @@ func Recommend(cfg ModelConfig, inst InstanceSpec, allInstances []InstanceSpec)
 		if fitsWithQuant {
 			q := chosenQuant
 			rec.Quantization = &q
-			qMem := modelMemoryBytes(cfg.ParameterCount, chosenQuant)
-			minGPUsQ := int(math.Ceil(qMem / usablePerDevice))
-			if minGPUsQ < 1 {
-				minGPUsQ = 1
-			}
-			tp := validTPDegree(minGPUsQ, cfg.NumAttentionHeads, cfg.NumKeyValueHeads, inst.AcceleratorCount)
-			rec.TensorParallelDegree = tp
+			qMem := ModelMemoryBytes(cfg.ParameterCount, chosenQuant)
+			rec.TensorParallelDegree = quantTP
+			rec.PipelineParallelDegree = quantPP
+			rec.ExpertParallelDegree = quantEP
 			rec.Explanation.Quantization = fmt.Sprintf("Model requires %.1f GiB in %s but only %.0f GiB available. Using %s quantization (%.1f GiB).",
 				modelMemNative/gibBytes, dtype, totalUsableBytes/gibBytes, chosenQuant, qMem/gibBytes)
 			rec.Explanation.TensorParallelDegree = fmt.Sprintf("TP=%d with %s quantization: %.1f GiB model across %d × %s.",
-				tp, chosenQuant, qMem/gibBytes, inst.AcceleratorCount, inst.AcceleratorName)
+				quantTP, chosenQuant, qMem/gibBytes, inst.AcceleratorCount, inst.AcceleratorName)
+			if isMoE {
+				rec.Explanation.PipelineParallelDegree = fmt.Sprintf("PP=%d: %d hidden layers split into %d pipeline stage(s).",
+					quantPP, cfg.NumHiddenLayers, quantPP)
+				rec.Explanation.ExpertParallelDegree = fmt.Sprintf("EP=%d: %d experts (%d active per token) sharded across %d expert-parallel group(s).",
+					quantEP, cfg.NumLocalExperts, cfg.NumExpertsPerToken, quantEP)
+			}
 		} else {
 			// Nothing fits — infeasible on this instance.
 			rec.Explanation.Feasible = false
-			rec.Explanation.Reason = fmt.Sprintf("Model requires %.1f GiB in %s. Even INT4 (%.1f GiB) exceeds %.0f GiB available on %s.",
-				modelMemNative/gibBytes, dtype, modelMemoryBytes(cfg.ParameterCount, "int4")/gibBytes,
-				totalUsableBytes/gibBytes, inst.Name)
+			if cfg.Quantization != "" {
+				rec.Explanation.Reason = fmt.Sprintf("Model requires %.1f GiB in its %s checkpoint, which exceeds %.0f GiB available on %s. No smaller precision of this checkpoint is available.",
+					modelMemNative/gibBytes, dtype, totalUsableBytes/gibBytes, inst.Name)
+			} else {
+				rec.Explanation.Reason = fmt.Sprintf("Model requires %.1f GiB in %s. Even INT4 (%.1f GiB) exceeds %.0f GiB available on %s.",
+					modelMemNative/gibBytes, dtype, ModelMemoryBytes(cfg.ParameterCount, "int4")/gibBytes,
+					totalUsableBytes/gibBytes, inst.Name)
+			}
 			if rec.Alternatives.LargerInstance != "" {
 				rec.Explanation.SuggestedInstance = rec.Alternatives.LargerInstance
 			}
@@ -278,7 +499,7 @@ func Recommend(cfg ModelConfig, inst InstanceSpec, allInstances []InstanceSpec)
 
 	// Calculate max model length.
 	kvPerToken := kvCachePerTokenBytes(cfg)
-	effectiveModelMem := modelMemoryBytes(cfg.ParameterCount, chosenQuant)
+	effectiveModelMem := ModelMemoryBytes(cfg.ParameterCount, chosenQuant)
 	remainingBytes := totalUsableBytes - effectiveModelMem
 	if remainingBytes < 0 {
 		remainingBytes = 0
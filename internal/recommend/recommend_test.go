@@ -49,6 +49,26 @@ var (
 
 var allInstances = []InstanceSpec{g5xlarge, g5_12xlarge, g5_48xlarge, p5_48xlarge}
 
+// moeTiny is a small MoE model config chosen so that NumAttentionHeads (2)
+// only admits tp ∈ {1, 2} and NumHiddenLayers (27) shares no common factor
+// with an 8-accelerator instance, forcing pp=1 — so the (tp, pp, ep)
+// factorization search has a real choice to make between tp=1,ep=8 and
+// tp=2,ep=4, and picking the smaller per-device bytes should land on the
+// latter.
+var moeTiny = ModelConfig{
+	ParameterCount:        15_700_000_000,
+	HiddenSize:            2048,
+	NumAttentionHeads:     2,
+	NumKeyValueHeads:      2,
+	NumHiddenLayers:       27,
+	MaxPositionEmbeddings: 32768,
+	TorchDtype:            "bfloat16",
+	ModelType:             "deepseek",
+	NumLocalExperts:       64,
+	NumExpertsPerToken:    6,
+	MoEIntermediateSize:   1408,
+}
+
 func TestBytesPerParam(t *testing.T) {
 	tests := []struct {
 		quant string
@@ -71,15 +91,18 @@ func TestBytesPerParam(t *testing.T) {
 }
 
 func TestSupportsFP8(t *testing.T) {
-	if !supportsFP8("H100") {
+	if !supportsFP8("gpu", "H100") {
 		t.Error("expected H100 to support FP8")
 	}
-	if !supportsFP8("H200") {
+	if !supportsFP8("gpu", "H200") {
 		t.Error("expected H200 to support FP8")
 	}
-	if supportsFP8("A10G") {
+	if supportsFP8("gpu", "A10G") {
 		t.Error("expected A10G to not support FP8")
 	}
+	if supportsFP8("neuron", "Trainium2") {
+		t.Error("expected Neuron accelerators to never support FP8")
+	}
 }
 
 func TestKVCachePerTokenBytes(t *testing.T) {
@@ -93,12 +116,12 @@ func TestKVCachePerTokenBytes(t *testing.T) {
 
 func TestValidTPDegree(t *testing.T) {
 	tests := []struct {
-		name     string
-		minTP    int
-		heads    int
-		kvHeads  int
-		maxGPUs  int
-		wantTP   int
+		name    string
+		minTP   int
+		heads   int
+		kvHeads int
+		maxGPUs int
+		wantTP  int
 	}{
 		{"1 GPU sufficient", 1, 32, 8, 4, 1},
 		{"needs 2 GPUs", 2, 32, 8, 8, 2},
@@ -284,18 +307,91 @@ func TestRecommendAlternatives_ShowsBothOptions(t *testing.T) {
 	}
 }
 
+func TestRecommendMoE_P5_48xlarge_PicksJointParallelism(t *testing.T) {
+	// moeTiny fits at native BF16 on p5.48xlarge (8 H100, 640 GiB). Of the
+	// two (tp, pp, ep) combinations that satisfy the divisibility
+	// constraints (tp=1,pp=1,ep=8 and tp=2,pp=1,ep=4), tp=2,ep=4 has fewer
+	// per-device bytes, so that's what should be chosen.
+	rec := Recommend(moeTiny, p5_48xlarge, allInstances)
+
+	if !rec.Explanation.Feasible {
+		t.Fatalf("expected feasible: %s", rec.Explanation.Reason)
+	}
+	if rec.TensorParallelDegree != 2 {
+		t.Errorf("TP = %d, want 2", rec.TensorParallelDegree)
+	}
+	if rec.PipelineParallelDegree != 1 {
+		t.Errorf("PP = %d, want 1", rec.PipelineParallelDegree)
+	}
+	if rec.ExpertParallelDegree != 4 {
+		t.Errorf("EP = %d, want 4", rec.ExpertParallelDegree)
+	}
+	if got := rec.TensorParallelDegree * rec.PipelineParallelDegree * rec.ExpertParallelDegree; got != p5_48xlarge.AcceleratorCount {
+		t.Errorf("tp*pp*ep = %d, want %d", got, p5_48xlarge.AcceleratorCount)
+	}
+	if rec.Explanation.ExpertParallelDegree == "" {
+		t.Error("expected a non-empty expert parallelism explanation for an MoE model")
+	}
+	if rec.Explanation.PipelineParallelDegree == "" {
+		t.Error("expected a non-empty pipeline parallelism explanation for an MoE model")
+	}
+}
+
+func TestRecommendDense_PipelineAndExpertParallelismDefaultToOne(t *testing.T) {
+	// Dense models have no experts or pipeline stages to speak of; both
+	// degrees should default to 1 rather than 0.
+	rec := Recommend(mistral7B, g5xlarge, allInstances)
+
+	if rec.PipelineParallelDegree != 1 {
+		t.Errorf("PP = %d, want 1 for a dense model", rec.PipelineParallelDegree)
+	}
+	if rec.ExpertParallelDegree != 1 {
+		t.Errorf("EP = %d, want 1 for a dense model", rec.ExpertParallelDegree)
+	}
+	if rec.Explanation.PipelineParallelDegree != "" {
+		t.Error("expected no pipeline parallelism explanation for a dense model")
+	}
+	if rec.Explanation.ExpertParallelDegree != "" {
+		t.Error("expected no expert parallelism explanation for a dense model")
+	}
+}
+
+func TestExpertWeightBytes_DenseModelHasNoSparseComponent(t *testing.T) {
+	dense, sparseResident, sparseActivated := expertWeightBytes(mistral7B, "bfloat16")
+	if dense != ModelMemoryBytes(mistral7B.ParameterCount, "bfloat16") {
+		t.Errorf("dense bytes = %v, want the full model memory for a dense model", dense)
+	}
+	if sparseResident != 0 || sparseActivated != 0 {
+		t.Errorf("expected no sparse component for a dense model, got resident=%v activated=%v", sparseResident, sparseActivated)
+	}
+}
+
+func TestExpertWeightBytes_MoEActivatedIsFractionOfResident(t *testing.T) {
+	_, sparseResident, sparseActivated := expertWeightBytes(moeTiny, "bfloat16")
+	if sparseResident <= 0 {
+		t.Fatal("expected a positive sparse resident component for an MoE model")
+	}
+	wantActivated := sparseResident * float64(moeTiny.NumExpertsPerToken) / float64(moeTiny.NumLocalExperts)
+	if sparseActivated != wantActivated {
+		t.Errorf("sparseActivated = %v, want %v", sparseActivated, wantActivated)
+	}
+	if sparseActivated >= sparseResident {
+		t.Error("activated expert bytes should be less than resident expert bytes when NumExpertsPerToken < NumLocalExperts")
+	}
+}
+
 func TestModelMemoryBytes(t *testing.T) {
 	// 7B params in BF16 = 7e9 * 2 = 14e9 bytes ≈ 13 GiB
-	mem := modelMemoryBytes(7_000_000_000, "bfloat16")
+	mem := ModelMemoryBytes(7_000_000_000, "bfloat16")
 	wantBytes := float64(7_000_000_000) * 2
 	if mem != wantBytes {
-		t.Errorf("modelMemoryBytes(7B, bf16) = %v, want %v", mem, wantBytes)
+		t.Errorf("ModelMemoryBytes(7B, bf16) = %v, want %v", mem, wantBytes)
 	}
 
 	// Same model in INT4 = 7e9 * 0.5 = 3.5e9 bytes ≈ 3.3 GiB
-	mem4 := modelMemoryBytes(7_000_000_000, "int4")
+	mem4 := ModelMemoryBytes(7_000_000_000, "int4")
 	wantBytes4 := float64(7_000_000_000) * 0.5
 	if mem4 != wantBytes4 {
-		t.Errorf("modelMemoryBytes(7B, int4) = %v, want %v", mem4, wantBytes4)
+		t.Errorf("ModelMemoryBytes(7B, int4) = %v, want %v", mem4, wantBytes4)
 	}
 }
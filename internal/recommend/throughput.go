@@ -0,0 +1,76 @@
+package recommend
+
+// AcceleratorSpec is the coarse roofline inputs for one accelerator model:
+// its peak dense bf16/fp16 matmul throughput and HBM bandwidth.
+//
+// This mirrors internal/orchestrator/simulator's type and table of the
+// same shape. simulator already imports this package for ModelMemoryBytes,
+// so importing it back here to reuse its roofline would be a cycle;
+// duplicating the (small, rarely-changing) spec table is the lesser evil.
+type AcceleratorSpec struct {
+	PeakTFLOPS      float64
+	MemBandwidthGBs float64
+}
+
+var acceleratorSpecs = map[string]AcceleratorSpec{
+	"A10G":        {PeakTFLOPS: 125, MemBandwidthGBs: 600},
+	"A100":        {PeakTFLOPS: 312, MemBandwidthGBs: 2039},
+	"H100":        {PeakTFLOPS: 989, MemBandwidthGBs: 3350},
+	"H200":        {PeakTFLOPS: 989, MemBandwidthGBs: 4800},
+	"L40S":        {PeakTFLOPS: 362, MemBandwidthGBs: 864},
+	"L4":          {PeakTFLOPS: 121, MemBandwidthGBs: 300},
+	"Trainium2":   {PeakTFLOPS: 667, MemBandwidthGBs: 2900},
+	"Inferentia2": {PeakTFLOPS: 190, MemBandwidthGBs: 820},
+}
+
+// defaultAcceleratorSpec is used for an accelerator name the table doesn't
+// recognize, so an unfamiliar instance type degrades to a conservative
+// estimate rather than failing the comparison outright.
+var defaultAcceleratorSpec = acceleratorSpecs["A10G"]
+
+func specFor(acceleratorName string) AcceleratorSpec {
+	if s, ok := acceleratorSpecs[acceleratorName]; ok {
+		return s
+	}
+	return defaultAcceleratorSpec
+}
+
+// flopsPerParamPerToken is the standard 2×N forward-pass FLOPs-per-
+// parameter approximation, ignoring the (usually small) quadratic
+// attention term — the same order of coarseness the rest of this package
+// accepts elsewhere.
+const flopsPerParamPerToken = 2
+
+// projectThroughput estimates TTFT, inter-token latency, and aggregate
+// decode tokens/sec for cfg running at quant precision, sharded tpDegree
+// ways, serving concurrency concurrent requests with inputLen-token
+// prompts on inst — the same coarse roofline
+// internal/orchestrator/simulator uses to synthesize a run, so Compare can
+// rank candidates without deploying anything.
+func projectThroughput(cfg ModelConfig, inst InstanceSpec, quant string, tpDegree, concurrency, inputLen int) (ttftMs, itlMs, aggregateTPS float64) {
+	weightBytes := ModelMemoryBytes(cfg.ParameterCount, quant)
+	spec := specFor(inst.AcceleratorName)
+	tp := float64(tpDegree)
+	peakFLOPS := spec.PeakTFLOPS * 1e12 * tp
+	memBW := spec.MemBandwidthGBs * 1e9 * tp
+	flopsPerToken := flopsPerParamPerToken * float64(cfg.ParameterCount)
+
+	prefillComputeSeconds := float64(inputLen) * flopsPerToken / peakFLOPS
+	prefillMemSeconds := weightBytes / memBW
+	ttftSeconds := prefillComputeSeconds
+	if prefillMemSeconds > ttftSeconds {
+		ttftSeconds = prefillMemSeconds
+	}
+
+	computeBoundTPS := peakFLOPS / flopsPerToken
+	memBoundTPS := float64(concurrency) * memBW / weightBytes
+	aggregateTPS = computeBoundTPS
+	if memBoundTPS < aggregateTPS {
+		aggregateTPS = memBoundTPS
+	}
+
+	stepSeconds := float64(concurrency) / aggregateTPS
+	itlMs = stepSeconds * 1000
+	ttftMs = ttftSeconds * 1000
+	return ttftMs, itlMs, aggregateTPS
+}
@@ -0,0 +1,409 @@
+// Package remotewrite renders the benchmark catalog as OpenMetrics text and
+// pushes it to a Prometheus remote_write endpoint, so Grafana dashboards can
+// be built directly on top of AccelBench results.
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// metricPrefix namespaces every gauge emitted for the catalog.
+const metricPrefix = "accelbench_"
+
+// Sample is one labeled gauge observation derived from a CatalogEntry.
+type Sample struct {
+	Metric string
+	Value  float64
+	Labels map[string]string
+}
+
+// CatalogSamples flattens each CatalogEntry's numeric fields into one gauge
+// sample per field, labeled with the entry's run/model/instance dimensions.
+// hourlyRate, keyed by instance type name, is used to derive a
+// cost_per_million_tokens_usd sample alongside the raw metrics; pass a nil
+// map to skip it.
+func CatalogSamples(entries []database.CatalogEntry, hourlyRate map[string]float64) []Sample {
+	var samples []Sample
+	for _, e := range entries {
+		labels := map[string]string{
+			"model":             e.ModelHfID,
+			"model_family":      derefOr(e.ModelFamily, ""),
+			"instance_type":     e.InstanceTypeName,
+			"instance_family":   e.InstanceFamily,
+			"accelerator_type":  e.AcceleratorType,
+			"framework":         e.Framework,
+			"framework_version": e.FrameworkVersion,
+			"tp_degree":         fmt.Sprintf("%d", e.TensorParallelDegree),
+			"quantization":      derefOr(e.Quantization, ""),
+			"concurrency":       fmt.Sprintf("%d", e.Concurrency),
+			"input_seq_len":     fmt.Sprintf("%d", e.InputSequenceLength),
+			"output_seq_len":    fmt.Sprintf("%d", e.OutputSequenceLength),
+			"run_id":            e.RunID,
+		}
+
+		samples = append(samples, gaugeSamples(labels,
+			field{"ttft_p50_ms", e.TTFTP50Ms},
+			field{"ttft_p99_ms", e.TTFTP99Ms},
+			field{"e2e_latency_p50_ms", e.E2ELatencyP50Ms},
+			field{"e2e_latency_p99_ms", e.E2ELatencyP99Ms},
+			field{"itl_p50_ms", e.ITLP50Ms},
+			field{"itl_p99_ms", e.ITLP99Ms},
+			field{"throughput_per_request_tps", e.ThroughputPerRequestTPS},
+			field{"throughput_agg_tps", e.ThroughputAggregateTPS},
+			field{"requests_per_second", e.RequestsPerSecond},
+			field{"gpu_util_pct", e.AcceleratorUtilizationPct},
+			field{"mem_peak_gib", e.AcceleratorMemoryPeakGiB},
+			field{"server_ttft_p50_ms", e.ServerTTFTP50Ms},
+			field{"server_ttft_p99_ms", e.ServerTTFTP99Ms},
+			field{"server_e2e_latency_p50_ms", e.ServerE2ELatencyP50Ms},
+			field{"server_e2e_latency_p99_ms", e.ServerE2ELatencyP99Ms},
+			field{"sm_active_peak_pct", e.SMActivePeakPct},
+			field{"cost_per_million_tokens_usd", costPerMillionTokens(e, hourlyRate)},
+		)...)
+	}
+	return samples
+}
+
+// costPerMillionTokens derives $/1M output tokens from e's aggregate
+// throughput and rate's on-demand hourly price, the same formula the
+// recommend endpoint uses to rank instance types by cost. Returns nil if
+// either input is missing, so the sample is simply omitted rather than
+// emitted as a bogus zero.
+func costPerMillionTokens(e database.CatalogEntry, hourlyRate map[string]float64) *float64 {
+	if e.ThroughputAggregateTPS == nil || *e.ThroughputAggregateTPS <= 0 {
+		return nil
+	}
+	rate, ok := hourlyRate[e.InstanceTypeName]
+	if !ok {
+		return nil
+	}
+	tokensPerHour := *e.ThroughputAggregateTPS * 3600
+	cost := rate / tokensPerHour * 1e6
+	return &cost
+}
+
+// RunSamples flattens one run's BenchmarkMetrics into gauge samples labeled
+// with the run's model/instance/framework dimensions, for `accelbench
+// status -o prometheus`. Unlike CatalogSamples, a BenchmarkRun doesn't carry
+// model family, instance family, or accelerator type — those are only known
+// once the run lands in the catalog join — so those labels are emitted
+// empty to keep the label set consistent with CatalogSamples' output.
+// Returns nil if metrics is nil (e.g. the run hasn't completed yet).
+func RunSamples(run *database.BenchmarkRun, metrics *database.BenchmarkMetrics) []Sample {
+	if metrics == nil {
+		return nil
+	}
+
+	labels := map[string]string{
+		"model":             run.ModelID,
+		"model_family":      "",
+		"instance_type":     run.InstanceTypeID,
+		"instance_family":   "",
+		"accelerator_type":  "",
+		"framework":         run.Framework,
+		"framework_version": run.FrameworkVersion,
+		"tp_degree":         fmt.Sprintf("%d", run.TensorParallelDegree),
+		"quantization":      derefOr(run.Quantization, ""),
+		"concurrency":       fmt.Sprintf("%d", run.Concurrency),
+		"input_seq_len":     fmt.Sprintf("%d", run.InputSequenceLength),
+		"output_seq_len":    fmt.Sprintf("%d", run.OutputSequenceLength),
+		"run_id":            run.ID,
+	}
+
+	return gaugeSamples(labels,
+		field{"ttft_p50_ms", metrics.TTFTP50Ms},
+		field{"ttft_p99_ms", metrics.TTFTP99Ms},
+		field{"e2e_latency_p50_ms", metrics.E2ELatencyP50Ms},
+		field{"e2e_latency_p99_ms", metrics.E2ELatencyP99Ms},
+		field{"itl_p50_ms", metrics.ITLP50Ms},
+		field{"itl_p99_ms", metrics.ITLP99Ms},
+		field{"throughput_per_request_tps", metrics.ThroughputPerRequestTPS},
+		field{"throughput_agg_tps", metrics.ThroughputAggregateTPS},
+		field{"requests_per_second", metrics.RequestsPerSecond},
+		field{"gpu_util_pct", metrics.AcceleratorUtilizationPct},
+		field{"mem_peak_gib", metrics.AcceleratorMemoryPeakGiB},
+		field{"server_ttft_p50_ms", metrics.ServerTTFTP50Ms},
+		field{"server_ttft_p99_ms", metrics.ServerTTFTP99Ms},
+		field{"server_e2e_latency_p50_ms", metrics.ServerE2ELatencyP50Ms},
+		field{"server_e2e_latency_p99_ms", metrics.ServerE2ELatencyP99Ms},
+		field{"successful_requests", intPtr(metrics.SuccessfulRequests)},
+		field{"failed_requests", intPtr(metrics.FailedRequests)},
+	)
+}
+
+// field pairs a metric name suffix with its (possibly absent) value.
+type field struct {
+	suffix string
+	value  *float64
+}
+
+// metricHelp gives a one-line HELP description for each gauge suffix this
+// package emits, keyed without metricPrefix. RenderOpenMetrics uses it to
+// emit a "# HELP" line once per distinct metric name.
+var metricHelp = map[string]string{
+	"ttft_p50_ms":                 "Time to first token, 50th percentile, in milliseconds.",
+	"ttft_p99_ms":                 "Time to first token, 99th percentile, in milliseconds.",
+	"e2e_latency_p50_ms":          "End-to-end request latency, 50th percentile, in milliseconds.",
+	"e2e_latency_p99_ms":          "End-to-end request latency, 99th percentile, in milliseconds.",
+	"itl_p50_ms":                  "Inter-token latency, 50th percentile, in milliseconds.",
+	"itl_p99_ms":                  "Inter-token latency, 99th percentile, in milliseconds.",
+	"throughput_per_request_tps":  "Per-request output throughput, in tokens/sec.",
+	"throughput_agg_tps":          "Aggregate output throughput across all concurrent requests, in tokens/sec.",
+	"requests_per_second":         "Completed requests per second.",
+	"gpu_util_pct":                "Accelerator utilization, percent.",
+	"mem_peak_gib":                "Peak accelerator memory usage, in GiB.",
+	"server_ttft_p50_ms":          "Server-reported time to first token, 50th percentile, in milliseconds.",
+	"server_ttft_p99_ms":          "Server-reported time to first token, 99th percentile, in milliseconds.",
+	"server_e2e_latency_p50_ms":   "Server-reported end-to-end latency, 50th percentile, in milliseconds.",
+	"server_e2e_latency_p99_ms":   "Server-reported end-to-end latency, 99th percentile, in milliseconds.",
+	"sm_active_peak_pct":          "Peak streaming-multiprocessor active percent from the device exporter.",
+	"cost_per_million_tokens_usd": "Estimated cost per million output tokens, in USD.",
+	"successful_requests":         "Count of requests that completed successfully.",
+	"failed_requests":             "Count of requests that failed.",
+}
+
+func gaugeSamples(labels map[string]string, fields ...field) []Sample {
+	samples := make([]Sample, 0, len(fields))
+	for _, f := range fields {
+		if f.value == nil {
+			continue
+		}
+		samples = append(samples, Sample{
+			Metric: metricPrefix + f.suffix,
+			Value:  *f.value,
+			Labels: labels,
+		})
+	}
+	return samples
+}
+
+func derefOr(s *string, fallback string) string {
+	if s == nil {
+		return fallback
+	}
+	return *s
+}
+
+// intPtr converts an *int to an *float64, or returns nil unchanged, so int
+// fields like BenchmarkMetrics.SuccessfulRequests can feed the same
+// gaugeSamples nil-skipping logic as the package's *float64 fields.
+func intPtr(p *int) *float64 {
+	if p == nil {
+		return nil
+	}
+	v := float64(*p)
+	return &v
+}
+
+// RenderOpenMetrics writes samples as OpenMetrics/Prometheus exposition
+// text: one "# HELP"/"# TYPE" pair per distinct metric name, followed by
+// that metric's samples. samples is sorted by metric name first (stably,
+// so same-metric samples keep their relative order) since OpenMetrics
+// requires every sample for a metric to be contiguous, and callers build
+// samples one source entry at a time rather than one metric at a time.
+func RenderOpenMetrics(w *bytes.Buffer, samples []Sample) error {
+	sorted := make([]Sample, len(samples))
+	copy(sorted, samples)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Metric < sorted[j].Metric })
+
+	lastMetric := ""
+	for _, s := range sorted {
+		if s.Metric != lastMetric {
+			if help, ok := metricHelp[strings.TrimPrefix(s.Metric, metricPrefix)]; ok {
+				fmt.Fprintf(w, "# HELP %s %s\n", s.Metric, help)
+			}
+			fmt.Fprintf(w, "# TYPE %s gauge\n", s.Metric)
+			lastMetric = s.Metric
+		}
+		fmt.Fprintf(w, "%s{%s} %g\n", s.Metric, formatLabels(s.Labels), s.Value)
+	}
+	_, err := fmt.Fprint(w, "# EOF\n")
+	return err
+}
+
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%q", k, labels[k])
+	}
+	return joinComma(pairs)
+}
+
+func joinComma(parts []string) string {
+	out := ""
+	for i, p := range parts {
+		if i > 0 {
+			out += ","
+		}
+		out += p
+	}
+	return out
+}
+
+// Push sends samples to a Prometheus remote_write endpoint as a
+// snappy-compressed protobuf WriteRequest. authToken, if non-empty, is sent
+// as a bearer token; tenant, if non-empty, is sent as X-Scope-OrgID (the
+// convention used by Cortex/Mimir/Thanos multi-tenant receivers).
+func Push(ctx context.Context, url, authToken, tenant string, samples []Sample) error {
+	req := &prompb.WriteRequest{
+		Timeseries: make([]prompb.TimeSeries, 0, len(samples)),
+	}
+	now := time.Now().UnixMilli()
+	for _, s := range samples {
+		labelPairs := make([]prompb.Label, 0, len(s.Labels)+1)
+		labelPairs = append(labelPairs, prompb.Label{Name: "__name__", Value: s.Metric})
+		for k, v := range s.Labels {
+			labelPairs = append(labelPairs, prompb.Label{Name: k, Value: v})
+		}
+		req.Timeseries = append(req.Timeseries, prompb.TimeSeries{
+			Labels:  labelPairs,
+			Samples: []prompb.Sample{{Value: s.Value, Timestamp: now}},
+		})
+	}
+
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshal write request: %w", err)
+	}
+	compressed := snappy.Encode(nil, data)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(compressed))
+	if err != nil {
+		return fmt.Errorf("build remote_write request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	httpReq.Header.Set("Content-Encoding", "snappy")
+	httpReq.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if authToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+authToken)
+	}
+	if tenant != "" {
+		httpReq.Header.Set("X-Scope-OrgID", tenant)
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("remote_write request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("remote_write endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// pollInterval is how often the Pusher checks the catalog for newly
+// completed runs.
+const pollInterval = 30 * time.Second
+
+// defaultPricingRegion is the region priced for the cost_per_million_tokens_usd
+// sample, matching handleListPricing's default.
+const defaultPricingRegion = "us-east-2"
+
+// Pusher periodically streams newly completed benchmark runs to a
+// Prometheus remote_write endpoint, so dashboards stay current without
+// operators having to run `accelbench export` by hand.
+type Pusher struct {
+	repo      database.Repo
+	url       string
+	authToken string
+	tenant    string
+	pushed    map[string]bool
+}
+
+// NewPusher builds a Pusher targeting the given remote_write endpoint.
+// authToken and tenant may be empty.
+func NewPusher(repo database.Repo, url, authToken, tenant string) *Pusher {
+	return &Pusher{
+		repo:      repo,
+		url:       url,
+		authToken: authToken,
+		tenant:    tenant,
+		pushed:    make(map[string]bool),
+	}
+}
+
+// Run polls the catalog on a ticker until ctx is canceled, pushing samples
+// for any completed run it hasn't already pushed.
+func (p *Pusher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	p.pushNew(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.pushNew(ctx)
+		}
+	}
+}
+
+// hourlyRateByInstanceType returns the default region's on-demand hourly
+// rate for every instance type, keyed by name, for CatalogSamples' cost
+// derivation.
+func hourlyRateByInstanceType(ctx context.Context, repo database.Repo) (map[string]float64, error) {
+	pricing, err := repo.ListPricing(ctx, defaultPricingRegion)
+	if err != nil {
+		return nil, err
+	}
+	rate := make(map[string]float64, len(pricing))
+	for _, p := range pricing {
+		rate[p.InstanceTypeName] = p.OnDemandHourlyUSD
+	}
+	return rate, nil
+}
+
+func (p *Pusher) pushNew(ctx context.Context) {
+	entries, _, err := p.repo.ListCatalog(ctx, database.CatalogFilter{Limit: 500})
+	if err != nil {
+		log.Printf("remotewrite: list catalog: %v", err)
+		return
+	}
+
+	var fresh []database.CatalogEntry
+	for _, e := range entries {
+		if !p.pushed[e.RunID] {
+			fresh = append(fresh, e)
+		}
+	}
+	if len(fresh) == 0 {
+		return
+	}
+
+	hourlyRate, err := hourlyRateByInstanceType(ctx, p.repo)
+	if err != nil {
+		log.Printf("remotewrite: list pricing: %v", err)
+		hourlyRate = nil
+	}
+
+	if err := Push(ctx, p.url, p.authToken, p.tenant, CatalogSamples(fresh, hourlyRate)); err != nil {
+		log.Printf("remotewrite: push: %v", err)
+		return
+	}
+	for _, e := range fresh {
+		p.pushed[e.RunID] = true
+	}
+}
@@ -0,0 +1,106 @@
+package remotewrite
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/accelbench/accelbench/internal/database"
+)
+
+// ptr64 is a small test helper for building *float64 literals inline.
+func ptr64(v float64) *float64 { return &v }
+
+func sampleCatalogEntry() database.CatalogEntry {
+	family := "llama"
+	quant := "fp16"
+	return database.CatalogEntry{
+		RunID:                  "run-1",
+		ModelHfID:              "meta-llama/Llama-3.1-8B",
+		ModelFamily:            &family,
+		InstanceTypeName:       "g5.xlarge",
+		InstanceFamily:         "g5",
+		AcceleratorType:        "gpu",
+		AcceleratorName:        "A10G",
+		Framework:              "vllm",
+		FrameworkVersion:       "0.6.0",
+		TensorParallelDegree:   1,
+		Quantization:           &quant,
+		Concurrency:            8,
+		InputSequenceLength:    1024,
+		OutputSequenceLength:   512,
+		TTFTP50Ms:              ptr64(120.5),
+		ThroughputAggregateTPS: ptr64(450),
+	}
+}
+
+// TestRenderOpenMetrics_CatalogSamples compares rendered OpenMetrics text
+// against a checked-in golden file, so a label or HELP/TYPE regression shows
+// up as a diff instead of a hand-decoded assertion. There's no -update flag:
+// regenerate testdata/catalog.golden by hand when the format intentionally
+// changes.
+func TestRenderOpenMetrics_CatalogSamples(t *testing.T) {
+	entries := []database.CatalogEntry{sampleCatalogEntry()}
+
+	var buf bytes.Buffer
+	if err := RenderOpenMetrics(&buf, CatalogSamples(entries, nil)); err != nil {
+		t.Fatalf("RenderOpenMetrics: %v", err)
+	}
+
+	want, err := os.ReadFile("testdata/catalog.golden")
+	if err != nil {
+		t.Fatalf("read golden file: %v", err)
+	}
+	if buf.String() != string(want) {
+		t.Errorf("output mismatch:\ngot:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+// TestRenderOpenMetrics_RunSamples mirrors TestRenderOpenMetrics_CatalogSamples
+// for the single-run path used by `accelbench status -o prometheus`.
+func TestRenderOpenMetrics_RunSamples(t *testing.T) {
+	quant := "int8"
+	run := &database.BenchmarkRun{
+		ID:                   "run-2",
+		ModelID:              "m1",
+		InstanceTypeID:       "i1",
+		Framework:            "vllm",
+		FrameworkVersion:     "0.6.0",
+		TensorParallelDegree: 2,
+		Quantization:         &quant,
+		Concurrency:          16,
+		InputSequenceLength:  1024,
+		OutputSequenceLength: 512,
+	}
+	successful := 100
+	failed := 2
+	metrics := &database.BenchmarkMetrics{
+		RunID:              "run-2",
+		TTFTP50Ms:          ptr64(80),
+		SuccessfulRequests: &successful,
+		FailedRequests:     &failed,
+	}
+
+	var buf bytes.Buffer
+	if err := RenderOpenMetrics(&buf, RunSamples(run, metrics)); err != nil {
+		t.Fatalf("RenderOpenMetrics: %v", err)
+	}
+
+	want, err := os.ReadFile("testdata/run.golden")
+	if err != nil {
+		t.Fatalf("read golden file: %v", err)
+	}
+	if buf.String() != string(want) {
+		t.Errorf("output mismatch:\ngot:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+// TestRenderOpenMetrics_NilMetricsSkipped confirms RunSamples omits samples
+// entirely rather than emitting them with a zero value when metrics hasn't
+// landed yet (e.g. the run is still in progress).
+func TestRenderOpenMetrics_NilMetricsSkipped(t *testing.T) {
+	run := &database.BenchmarkRun{ID: "run-3"}
+	if samples := RunSamples(run, nil); samples != nil {
+		t.Errorf("RunSamples(run, nil) = %v, want nil", samples)
+	}
+}